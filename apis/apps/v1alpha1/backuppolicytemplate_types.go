@@ -43,6 +43,15 @@ type BackupPolicyTemplateSpec struct {
 	// +optional
 	// +kubebuilder:validation:MaxLength=20
 	Identifier string `json:"identifier,omitempty"`
+
+	// Specifies the priority of this BackupPolicyTemplate when multiple BackupPolicyTemplates match the same component.
+	// The template with the highest priority is chosen as the default, unless the ClusterDefinition or the
+	// ComponentDefinition names the default explicitly by the `apps.kubeblocks.io/default-backup-policy-template` annotation.
+	// Two templates of the same component cannot both claim to be the default with the same priority.
+	//
+	// +kubebuilder:default=0
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
 }
 
 type BackupPolicy struct {
@@ -240,6 +249,7 @@ type BackupPolicyTemplateStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories={kubeblocks},scope=Cluster,shortName=bpt
 // +kubebuilder:printcolumn:name="CLUSTER-DEFINITION",type="string",JSONPath=".spec.clusterDefinitionRef",description="ClusterDefinition referenced by cluster."
+// +kubebuilder:printcolumn:name="PRIORITY",type="integer",JSONPath=".spec.priority",description="priority of the template when multiple templates match a component."
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 
 // BackupPolicyTemplate is the Schema for the BackupPolicyTemplates API (defined by provider)
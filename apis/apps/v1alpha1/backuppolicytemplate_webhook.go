@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+// log is for logging in this package.
+var backuppolicytemplatelog = logf.Log.WithName("backuppolicytemplate-resource")
+
+func (r *BackupPolicyTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-apps-kubeblocks-io-v1alpha1-backuppolicytemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.kubeblocks.io,resources=backuppolicytemplates,verbs=create;update,versions=v1alpha1,name=vbackuppolicytemplate.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &BackupPolicyTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *BackupPolicyTemplate) ValidateCreate() (admission.Warnings, error) {
+	backuppolicytemplatelog.Info("validate create", "name", r.Name)
+	return nil, r.validateDefaultTemplate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *BackupPolicyTemplate) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	backuppolicytemplatelog.Info("validate update", "name", r.Name)
+	return nil, r.validateDefaultTemplate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *BackupPolicyTemplate) ValidateDelete() (admission.Warnings, error) {
+	backuppolicytemplatelog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
+
+// IsDefault returns whether the template claims to be the default of its components by the
+// dataprotection.kubeblocks.io/is-default-policy-template annotation.
+func (r *BackupPolicyTemplate) IsDefault() bool {
+	return r.Annotations[dptypes.DefaultBackupPolicyTemplateAnnotationKey] == "true"
+}
+
+// ComponentDefNames returns the componentDefRefs and the componentDefs the backup policies of the template apply to.
+func (r *BackupPolicyTemplate) ComponentDefNames() []string {
+	var names []string
+	for _, policy := range r.Spec.BackupPolicies {
+		if policy.ComponentDefRef != "" {
+			names = append(names, policy.ComponentDefRef)
+		}
+		names = append(names, policy.ComponentDefs...)
+	}
+	return names
+}
+
+// validateDefaultTemplate rejects the template if another template of the same component also claims
+// to be the default with the same priority, the default template of the component is ambiguous then.
+func (r *BackupPolicyTemplate) validateDefaultTemplate() error {
+	if !r.IsDefault() || webhookMgr == nil || webhookMgr.client == nil {
+		return nil
+	}
+	tplList := &BackupPolicyTemplateList{}
+	if err := webhookMgr.client.List(context.Background(), tplList); err != nil {
+		return err
+	}
+	compDefNames := map[string]struct{}{}
+	for _, name := range r.ComponentDefNames() {
+		compDefNames[name] = struct{}{}
+	}
+	var allErrs field.ErrorList
+	for i := range tplList.Items {
+		tpl := &tplList.Items[i]
+		if tpl.Name == r.Name || tpl.Spec.ClusterDefRef != r.Spec.ClusterDefRef ||
+			!tpl.IsDefault() || tpl.Spec.Priority != r.Spec.Priority {
+			continue
+		}
+		for _, name := range tpl.ComponentDefNames() {
+			if _, ok := compDefNames[name]; ok {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec.priority"), r.Spec.Priority,
+					fmt.Sprintf("BackupPolicyTemplate %s also claims to be the default of the component %s with the same priority", tpl.Name, name)))
+				break
+			}
+		}
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Group: APIVersion, Kind: BackupPolicyTemplateKind}, r.Name, allErrs)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func newWebhookTestBackupPolicyTemplate(name string, isDefault bool, priority int32, compDefRefs ...string) *BackupPolicyTemplate {
+	tpl := &BackupPolicyTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+		Spec: BackupPolicyTemplateSpec{
+			ClusterDefRef: "mysql",
+			Priority:      priority,
+		},
+	}
+	if isDefault {
+		tpl.Annotations[dptypes.DefaultBackupPolicyTemplateAnnotationKey] = "true"
+	}
+	for _, compDefRef := range compDefRefs {
+		tpl.Spec.BackupPolicies = append(tpl.Spec.BackupPolicies, BackupPolicy{ComponentDefRef: compDefRef})
+	}
+	return tpl
+}
+
+func TestValidateDefaultBackupPolicyTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	existing := newWebhookTestBackupPolicyTemplate("mysql-backup", true, 10, "mysql")
+	originalMgr := webhookMgr
+	webhookMgr = &webhookManager{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()}
+	defer func() { webhookMgr = originalMgr }()
+
+	testCases := []struct {
+		name     string
+		tpl      *BackupPolicyTemplate
+		expected string
+	}{
+		{
+			name:     "default with the same priority",
+			tpl:      newWebhookTestBackupPolicyTemplate("mysql-backup-2", true, 10, "proxy", "mysql"),
+			expected: "BackupPolicyTemplate mysql-backup also claims to be the default of the component mysql",
+		},
+		{
+			name: "default with a different priority",
+			tpl:  newWebhookTestBackupPolicyTemplate("mysql-backup-2", true, 20, "mysql"),
+		},
+		{
+			name: "not default with the same priority",
+			tpl:  newWebhookTestBackupPolicyTemplate("mysql-backup-2", false, 10, "mysql"),
+		},
+		{
+			name: "default of another component",
+			tpl:  newWebhookTestBackupPolicyTemplate("mysql-backup-2", true, 10, "proxy"),
+		},
+		{
+			name: "update the default template itself",
+			tpl:  newWebhookTestBackupPolicyTemplate("mysql-backup", true, 10, "mysql"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.tpl.ValidateCreate()
+			switch {
+			case tc.expected == "" && err != nil:
+				t.Errorf("expected no error, got: %v", err)
+			case tc.expected != "" && (err == nil || !strings.Contains(err.Error(), tc.expected)):
+				t.Errorf("expected error containing %q, got: %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -198,6 +199,23 @@ type ClusterBackup struct {
 	// +kubebuilder:default=false
 	// +optional
 	PITREnabled *bool `json:"pitrEnabled,omitempty"`
+
+	// Specifies whether to take a final backup with the backup method before the cluster is deleted with
+	// the `Delete` or `WipeOut` termination policy. The deletion is paused until the final backup is completed,
+	// it is resumed if the final backup is disabled.
+	//
+	// The final backup is labeled with `dataprotection.kubeblocks.io/final-backup=true`,
+	// which is retained after the cluster is deleted.
+	//
+	// +optional
+	FinalBackupOnDelete *bool `json:"finalBackupOnDelete,omitempty"`
+
+	// Specifies the timeout in minutes of the final backup, the deletion is paused if the final backup
+	// is not completed in time. Defaults to 60 minutes.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FinalBackupTimeoutMinutes *int64 `json:"finalBackupTimeoutMinutes,omitempty"`
 }
 
 type ClusterResources struct {
@@ -487,8 +505,13 @@ type ClusterComponentSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
-	// Defines the update strategy for the component.
-	// Not supported.
+	// Overrides the update strategy defined in the referenced ClusterComponentDefinition.
+	// It determines the pod management policy and the member update strategy of the underlying workload,
+	// the strategy of the definition is used if it is not specified.
+	//
+	// The Parallel strategy is rejected for Consensus components, since updating all members at the same time
+	// may leave the component without a quorum, unless the cluster is annotated with
+	// "apps.kubeblocks.io/allow-unsafe-update-strategy: true".
 	//
 	// +optional
 	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
@@ -518,6 +541,16 @@ type ClusterComponentSpec struct {
 	//
 	// +optional
 	Instances []string `json:"instances,omitempty"`
+
+	// Specifies a strategic merge patch applied to the podSpec of the component's workload.
+	// The patch is applied after the environment variables and volumes are injected by KubeBlocks,
+	// e.g. to tweak the args of a container or to add a sidecar for the specific cluster.
+	// The containers injected by KubeBlocks, e.g. lorry and config-manager, can't be removed or renamed.
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	PodSpecPatch *runtime.RawExtension `json:"podSpecPatch,omitempty"`
 }
 
 type ComponentMessageMap map[string]string
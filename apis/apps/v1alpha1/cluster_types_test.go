@@ -24,8 +24,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/apecloud/kubeblocks/pkg/constant"
@@ -334,3 +336,68 @@ func TestGetComponentOrName(t *testing.T) {
 		t.Error("function GetComponentByName should return nil")
 	}
 }
+
+func TestValidateComponentUpdateStrategy(t *testing.T) {
+	var (
+		cluster  = &Cluster{}
+		parallel = ParallelStrategy
+		serial   = SerialStrategy
+		compDef  = &ClusterComponentDefinition{WorkloadType: Consensus}
+	)
+	validate := func(compDef *ClusterComponentDefinition, strategy *UpdateStrategy) field.ErrorList {
+		var allErrs field.ErrorList
+		cluster.validateComponentUpdateStrategy(&allErrs, compDef, strategy, 0)
+		return allErrs
+	}
+	if errs := validate(compDef, &serial); len(errs) != 0 {
+		t.Errorf("expected the Serial strategy to be allowed, got: %v", errs)
+	}
+	if errs := validate(compDef, &parallel); len(errs) != 1 {
+		t.Errorf("expected the Parallel strategy to be rejected for Consensus components, got: %v", errs)
+	}
+	if errs := validate(&ClusterComponentDefinition{WorkloadType: Stateful}, &parallel); len(errs) != 0 {
+		t.Errorf("expected the Parallel strategy to be allowed for Stateful components, got: %v", errs)
+	}
+	cluster.Annotations = map[string]string{constant.AllowUnsafeUpdateStrategyAnnotationKey: "true"}
+	if errs := validate(compDef, &parallel); len(errs) != 0 {
+		t.Errorf("expected the Parallel strategy to be allowed by the annotation, got: %v", errs)
+	}
+}
+
+func TestValidateComponentPartition(t *testing.T) {
+	partition := int32(3)
+	llUpdateStrategy := &appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+	}
+	validate := func(compDef *ClusterComponentDefinition, replicas int32) field.ErrorList {
+		var allErrs field.ErrorList
+		(&Cluster{}).validateComponentPartition(&allErrs, compDef, &ClusterComponentSpec{Replicas: replicas}, field.NewPath("spec.components[0].replicas"))
+		return allErrs
+	}
+
+	stateful := &ClusterComponentDefinition{
+		WorkloadType: Stateful,
+		StatefulSpec: &StatefulSetSpec{LLUpdateStrategy: llUpdateStrategy},
+	}
+	if errs := validate(stateful, 3); len(errs) != 0 {
+		t.Errorf("expected the partition equal to the replicas to be allowed, got: %v", errs)
+	}
+	if errs := validate(stateful, 2); len(errs) != 1 {
+		t.Errorf("expected the partition greater than the replicas to be rejected, got: %v", errs)
+	}
+	consensus := &ClusterComponentDefinition{
+		WorkloadType:  Consensus,
+		ConsensusSpec: &ConsensusSetSpec{StatefulSetSpec: StatefulSetSpec{LLUpdateStrategy: llUpdateStrategy}},
+	}
+	if errs := validate(consensus, 1); len(errs) != 1 {
+		t.Errorf("expected the partition of Consensus components to be validated, got: %v", errs)
+	}
+	// the resolved strategies without LLUpdateStrategy are always valid
+	if errs := validate(&ClusterComponentDefinition{WorkloadType: Stateful}, 0); len(errs) != 0 {
+		t.Errorf("expected the default strategy to be allowed, got: %v", errs)
+	}
+	if errs := validate(&ClusterComponentDefinition{WorkloadType: Stateless}, 0); len(errs) != 0 {
+		t.Errorf("expected the Stateless components to be skipped, got: %v", errs)
+	}
+}
@@ -18,19 +18,37 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+)
+
+var (
+	// kbManagedInitContainerNames and kbManagedContainerNames are the names of the containers injected by KubeBlocks.
+	kbManagedInitContainerNames = []string{constant.LorryInitContainerName, constant.ProbeInitContainerName}
+	kbManagedContainerNames     = []string{
+		constant.LorryContainerName,
+		constant.ConfigSidecarName,
+		constant.RoleProbeContainerName,
+		constant.StatusProbeContainerName,
+		constant.RunningProbeContainerName,
+		constant.VolumeProtectionProbeContainerName,
+	}
 )
 
 // log is for logging in this package.
@@ -181,6 +199,18 @@ func (r *Cluster) validateComponents(allErrs *field.ErrorList, clusterDef *Clust
 
 		componentNameMap[v.Name] = struct{}{}
 		r.validateComponentResources(allErrs, v.Resources, i)
+		r.validateComponentPodSpecPatch(allErrs, v.PodSpecPatch, field.NewPath(fmt.Sprintf("spec.components[%d].podSpecPatch", i)))
+		if compDef, ok := componentMap[v.ComponentDefRef]; ok {
+			r.validateComponentUpdateStrategy(allErrs, &compDef, v.UpdateStrategy, i)
+			r.validateComponentPartition(allErrs, &compDef, &r.Spec.ComponentSpecs[i], field.NewPath(fmt.Sprintf("spec.components[%d].replicas", i)))
+		}
+	}
+
+	for i, v := range r.Spec.ShardingSpecs {
+		r.validateComponentPodSpecPatch(allErrs, v.Template.PodSpecPatch, field.NewPath(fmt.Sprintf("spec.shardingSpecs[%d].template.podSpecPatch", i)))
+		if compDef, ok := componentMap[v.Template.ComponentDefRef]; ok {
+			r.validateComponentPartition(allErrs, &compDef, &r.Spec.ShardingSpecs[i].Template, field.NewPath(fmt.Sprintf("spec.shardingSpecs[%d].template.replicas", i)))
+		}
 	}
 
 	r.validateComponentTLSSettings(allErrs)
@@ -191,6 +221,34 @@ func (r *Cluster) validateComponents(allErrs *field.ErrorList, clusterDef *Clust
 	}
 }
 
+// validateComponentUpdateStrategy validates the update strategy overridden by the component,
+// the Parallel strategy is not allowed for Consensus components unless it is declared unsafe explicitly.
+func (r *Cluster) validateComponentUpdateStrategy(allErrs *field.ErrorList, compDef *ClusterComponentDefinition, strategy *UpdateStrategy, index int) {
+	if strategy == nil || *strategy != ParallelStrategy || compDef.WorkloadType != Consensus {
+		return
+	}
+	if allowUnsafe, _ := strconv.ParseBool(r.Annotations[constant.AllowUnsafeUpdateStrategyAnnotationKey]); allowUnsafe {
+		return
+	}
+	*allErrs = append(*allErrs, field.Forbidden(field.NewPath(fmt.Sprintf("spec.components[%d].updateStrategy", index)),
+		fmt.Sprintf("the Parallel update strategy may break the quorum of the Consensus component, set the annotation %s=true to allow it",
+			constant.AllowUnsafeUpdateStrategyAnnotationKey)))
+}
+
+// validateComponentPartition validates the partition of the resolved update strategy of the component,
+// which is not allowed to be greater than the replicas of the component.
+func (r *Cluster) validateComponentPartition(allErrs *field.ErrorList, compDef *ClusterComponentDefinition,
+	compSpec *ClusterComponentSpec, path *field.Path) {
+	_, strategy, err := ResolveWorkloadUpdateStrategy(compDef, compSpec.UpdateStrategy)
+	if err != nil {
+		// the stateless workloads have no update strategy of StatefulSet
+		return
+	}
+	if err = ValidateUpdateStrategyPartition(&strategy, compSpec.Replicas); err != nil {
+		*allErrs = append(*allErrs, field.Invalid(path, compSpec.Replicas, err.Error()))
+	}
+}
+
 // validateComponentResources validate component resources
 func (r *Cluster) validateComponentResources(allErrs *field.ErrorList, resources corev1.ResourceRequirements, index int) {
 	if invalidValue, err := validateVerticalResourceList(resources.Requests); err != nil {
@@ -204,6 +262,54 @@ func (r *Cluster) validateComponentResources(allErrs *field.ErrorList, resources
 	}
 }
 
+// validateComponentPodSpecPatch validates the podSpecPatch of the component is a valid strategic merge patch,
+// which doesn't remove or rename the containers injected by KubeBlocks.
+func (r *Cluster) validateComponentPodSpecPatch(allErrs *field.ErrorList, patch *runtime.RawExtension, path *field.Path) {
+	if patch == nil || len(patch.Raw) == 0 {
+		return
+	}
+	podSpec := &corev1.PodSpec{}
+	for _, name := range kbManagedInitContainerNames {
+		podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{Name: name})
+	}
+	for _, name := range kbManagedContainerNames {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{Name: name})
+	}
+	original, err := json.Marshal(podSpec)
+	if err != nil {
+		*allErrs = append(*allErrs, field.InternalError(path, err))
+		return
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch.Raw, corev1.PodSpec{})
+	if err != nil {
+		*allErrs = append(*allErrs, field.Invalid(path, string(patch.Raw), err.Error()))
+		return
+	}
+	patchedPodSpec := &corev1.PodSpec{}
+	if err = json.Unmarshal(patched, patchedPodSpec); err != nil {
+		*allErrs = append(*allErrs, field.Invalid(path, string(patch.Raw), err.Error()))
+		return
+	}
+	hasContainer := func(containers []corev1.Container, name string) bool {
+		for _, c := range containers {
+			if c.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range kbManagedInitContainerNames {
+		if !hasContainer(patchedPodSpec.InitContainers, name) {
+			*allErrs = append(*allErrs, field.Forbidden(path, fmt.Sprintf("the init container %s managed by KubeBlocks can't be removed or renamed", name)))
+		}
+	}
+	for _, name := range kbManagedContainerNames {
+		if !hasContainer(patchedPodSpec.Containers, name) {
+			*allErrs = append(*allErrs, field.Forbidden(path, fmt.Sprintf("the container %s managed by KubeBlocks can't be removed or renamed", name)))
+		}
+	}
+}
+
 func (r *Cluster) validateComponentTLSSettings(allErrs *field.ErrorList) {
 	for index, component := range r.Spec.ComponentSpecs {
 		if !component.TLS {
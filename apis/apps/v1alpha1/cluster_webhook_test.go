@@ -19,6 +19,8 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"strings"
+	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -26,6 +28,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -233,3 +237,53 @@ spec:
 	cluster.Spec.TerminationPolicy = WipeOut
 	return cluster, err
 }
+
+func TestValidateComponentPodSpecPatch(t *testing.T) {
+	cluster := &Cluster{}
+	path := field.NewPath("spec.components[0].podSpecPatch")
+	testCases := []struct {
+		name  string
+		patch string
+		err   string
+	}{
+		{
+			name:  "patch the args and add a sidecar",
+			patch: `{"containers":[{"name":"mysql","args":["--max-connections=1000"]},{"name":"lorry","resources":{"limits":{"cpu":"1"}}},{"name":"sidecar"}]}`,
+		},
+		{
+			name:  "remove the lorry container",
+			patch: `{"containers":[{"name":"lorry","$patch":"delete"}]}`,
+			err:   "the container lorry managed by KubeBlocks can't be removed or renamed",
+		},
+		{
+			name:  "replace the containers",
+			patch: `{"containers":[{"name":"mysql"}, {"$patch":"replace"}]}`,
+			err:   "the container config-manager managed by KubeBlocks can't be removed or renamed",
+		},
+		{
+			name:  "replace the init containers",
+			patch: `{"initContainers":[{"name":"init"}, {"$patch":"replace"}]}`,
+			err:   "the init container init-lorry managed by KubeBlocks can't be removed or renamed",
+		},
+		{
+			name:  "invalid patch",
+			patch: `{"containers":{"name":"mysql"}}`,
+			err:   "Invalid value",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var allErrs field.ErrorList
+			cluster.validateComponentPodSpecPatch(&allErrs, &runtime.RawExtension{Raw: []byte(tc.patch)}, path)
+			if tc.err == "" {
+				if len(allErrs) != 0 {
+					t.Errorf("expected no error, got: %v", allErrs)
+				}
+				return
+			}
+			if !strings.Contains(allErrs.ToAggregate().Error(), tc.err) {
+				t.Errorf("expected error %q, got: %v", tc.err, allErrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// AllowBreakingChangeAnnotationKey is the annotation of ClusterDefinition to allow the breaking changes,
+// which remove the componentDefs, service ports or volumes referenced by the existing clusters.
+const AllowBreakingChangeAnnotationKey = "kubeblocks.io/allow-breaking-change"
+
+// clusterDefinitionChangeType describes the impact of a change of ClusterDefinition on the existing clusters.
+type clusterDefinitionChangeType string
+
+const (
+	// clusterDefinitionChangeSafe means the change has no impact on the existing clusters.
+	clusterDefinitionChangeSafe clusterDefinitionChangeType = "Safe"
+	// clusterDefinitionChangeRolling means the change will restart the pods of the existing clusters.
+	clusterDefinitionChangeRolling clusterDefinitionChangeType = "Rolling"
+	// clusterDefinitionChangeBreaking means the change removes the componentDefs, service ports or volumes
+	// referenced by the existing clusters.
+	clusterDefinitionChangeBreaking clusterDefinitionChangeType = "Breaking"
+)
+
+// clusterDefinitionChange describes a change of the spec of ClusterDefinition.
+//
+// +kubebuilder:object:generate=false
+type clusterDefinitionChange struct {
+	Type    clusterDefinitionChangeType
+	Path    *field.Path
+	Message string
+}
+
+func (c clusterDefinitionChange) String() string {
+	return fmt.Sprintf("%s: %s", c.Path, c.Message)
+}
+
+// classifyClusterDefinitionChanges diffs the spec of the old and new ClusterDefinition, and classifies the changes
+// of the componentDefs by their impact on the given clusters. The clusters which do not reference the ClusterDefinition
+// are ignored, and the removals are safe if no clusters are given, e.g. when checking the changes of an addon offline.
+// The changes which are not listed, e.g. to the probes or the system accounts, are not reported.
+func classifyClusterDefinitionChanges(oldClusterDef, newClusterDef *ClusterDefinition, clusters []Cluster) []clusterDefinitionChange {
+	var changes []clusterDefinitionChange
+	refs := getComponentDefReferences(oldClusterDef.Name, clusters)
+	removed := func(path *field.Path, referencedBy []string, format string, args ...any) {
+		change := clusterDefinitionChange{
+			Type:    clusterDefinitionChangeSafe,
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		}
+		if len(referencedBy) > 0 {
+			change.Type = clusterDefinitionChangeBreaking
+			change.Message += fmt.Sprintf(", which is referenced by clusters [%s]", strings.Join(referencedBy, ", "))
+		}
+		changes = append(changes, change)
+	}
+	rolling := func(path *field.Path, format string, args ...any) {
+		changes = append(changes, clusterDefinitionChange{
+			Type:    clusterDefinitionChangeRolling,
+			Path:    path,
+			Message: fmt.Sprintf(format, args...) + ", the pods will be restarted",
+		})
+	}
+
+	for _, oldCompDef := range oldClusterDef.Spec.ComponentDefs {
+		path := field.NewPath("spec", "componentDefs").Key(oldCompDef.Name)
+		compDefRefs := refs[oldCompDef.Name]
+		newCompDef := newClusterDef.GetComponentDefByName(oldCompDef.Name)
+		if newCompDef == nil {
+			removed(path, compDefRefs.clusters(), "componentDef %s is removed", oldCompDef.Name)
+			continue
+		}
+		if oldCompDef.Service != nil {
+			for _, port := range oldCompDef.Service.Ports {
+				if !newCompDef.Service.hasPort(port.Name) {
+					removed(path.Child("service", "ports").Key(port.Name), compDefRefs.clusters(), "service port %s is removed", port.Name)
+				}
+			}
+		}
+		// the pods are addressed by the headless service, renaming it breaks the network identity of the pods.
+		if oldSuffix, newSuffix := oldCompDef.Service.GetHeadlessNameSuffix(), newCompDef.Service.GetHeadlessNameSuffix(); oldSuffix != newSuffix {
+			removed(path.Child("service", "headless", "nameSuffix"), compDefRefs.clusters(),
+				"the name suffix of headless service is changed from %q to %q", oldSuffix, newSuffix)
+		}
+		for _, volumeType := range oldCompDef.VolumeTypes {
+			if !newCompDef.hasVolumeType(volumeType.Name) {
+				removed(path.Child("volumeTypes").Key(volumeType.Name), compDefRefs.clustersWithVolume(volumeType.Name),
+					"volume %s is removed", volumeType.Name)
+			}
+		}
+		if !reflect.DeepEqual(oldCompDef.PodSpec, newCompDef.PodSpec) {
+			rolling(path.Child("podSpec"), "podSpec is changed")
+		}
+		if !reflect.DeepEqual(oldCompDef.ConfigSpecs, newCompDef.ConfigSpecs) {
+			rolling(path.Child("configSpecs"), "configSpecs are changed")
+		}
+		if !reflect.DeepEqual(oldCompDef.ScriptSpecs, newCompDef.ScriptSpecs) {
+			rolling(path.Child("scriptSpecs"), "scriptSpecs are changed")
+		}
+	}
+	for _, newCompDef := range newClusterDef.Spec.ComponentDefs {
+		if oldClusterDef.GetComponentDefByName(newCompDef.Name) == nil {
+			changes = append(changes, clusterDefinitionChange{
+				Type:    clusterDefinitionChangeSafe,
+				Path:    field.NewPath("spec", "componentDefs").Key(newCompDef.Name),
+				Message: fmt.Sprintf("componentDef %s is added", newCompDef.Name),
+			})
+		}
+	}
+	return changes
+}
+
+// componentDefReferences maps the clusters referencing a componentDef to the names of their volume claim templates.
+type componentDefReferences map[string][]string
+
+// clusters returns the sorted names of the clusters referencing the componentDef.
+func (r componentDefReferences) clusters() []string {
+	var clusters []string
+	for cluster := range r {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// clustersWithVolume returns the sorted names of the clusters which claim the volume of the componentDef.
+func (r componentDefReferences) clustersWithVolume(volumeName string) []string {
+	var clusters []string
+	for cluster, volumes := range r {
+		for _, volume := range volumes {
+			if volume == volumeName {
+				clusters = append(clusters, cluster)
+				break
+			}
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// getComponentDefReferences returns the references of the componentDefs by the components and shardings
+// of the clusters which reference the ClusterDefinition.
+func getComponentDefReferences(clusterDefName string, clusters []Cluster) map[string]componentDefReferences {
+	refs := map[string]componentDefReferences{}
+	addReference := func(cluster *Cluster, compSpec *ClusterComponentSpec) {
+		if compSpec.ComponentDefRef == "" {
+			return
+		}
+		if refs[compSpec.ComponentDefRef] == nil {
+			refs[compSpec.ComponentDefRef] = componentDefReferences{}
+		}
+		key := cluster.Namespace + "/" + cluster.Name
+		volumes := refs[compSpec.ComponentDefRef][key]
+		for _, vct := range compSpec.VolumeClaimTemplates {
+			volumes = append(volumes, vct.Name)
+		}
+		refs[compSpec.ComponentDefRef][key] = volumes
+	}
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.Spec.ClusterDefRef != clusterDefName {
+			continue
+		}
+		for j := range cluster.Spec.ComponentSpecs {
+			addReference(cluster, &cluster.Spec.ComponentSpecs[j])
+		}
+		for j := range cluster.Spec.ShardingSpecs {
+			addReference(cluster, &cluster.Spec.ShardingSpecs[j].Template)
+		}
+	}
+	return refs
+}
+
+func (r *ServiceSpec) hasPort(name string) bool {
+	if r == nil {
+		return false
+	}
+	for _, port := range r.Ports {
+		if port.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ClusterComponentDefinition) hasVolumeType(name string) bool {
+	for _, volumeType := range r.VolumeTypes {
+		if volumeType.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newDiffTestClusterDef() *ClusterDefinition {
+	return &ClusterDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql"},
+		Spec: ClusterDefinitionSpec{
+			ComponentDefs: []ClusterComponentDefinition{
+				{
+					Name:          "mysql",
+					WorkloadType:  Stateful,
+					CharacterType: "mysql",
+					PodSpec: &corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "mysql", Image: "mysql:8.0.33"}},
+					},
+					Service: &ServiceSpec{
+						Ports: []ServicePort{{Name: "mysql", Port: 3306}, {Name: "paxos", Port: 13306}},
+					},
+					VolumeTypes: []VolumeTypeSpec{{Name: "data", Type: VolumeTypeData}, {Name: "log", Type: VolumeTypeLog}},
+				},
+				{
+					Name:         "proxy",
+					WorkloadType: Stateless,
+					PodSpec: &corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "proxy", Image: "proxy:1.0"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newDiffTestCluster(name, clusterDefRef string, compSpecs ...ClusterComponentSpec) Cluster {
+	return Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: ClusterSpec{
+			ClusterDefRef:  clusterDefRef,
+			ComponentSpecs: compSpecs,
+		},
+	}
+}
+
+func findClusterDefinitionChange(changes []clusterDefinitionChange, path string) *clusterDefinitionChange {
+	for i := range changes {
+		if changes[i].Path.String() == path {
+			return &changes[i]
+		}
+	}
+	return nil
+}
+
+func TestClassifyClusterDefinitionChanges(t *testing.T) {
+	oldClusterDef := newDiffTestClusterDef()
+	clusterDef := newDiffTestClusterDef()
+	// remove the proxy, rename the paxos port and remove the log volume of mysql
+	clusterDef.Spec.ComponentDefs = clusterDef.Spec.ComponentDefs[:1]
+	mysql := &clusterDef.Spec.ComponentDefs[0]
+	mysql.Service.Ports[1].Name = "consensus"
+	mysql.VolumeTypes = mysql.VolumeTypes[:1]
+	mysql.PodSpec.Containers[0].Image = "mysql:8.0.34"
+	clusterDef.Spec.ComponentDefs = append(clusterDef.Spec.ComponentDefs, ClusterComponentDefinition{Name: "exporter", WorkloadType: Stateless})
+
+	if changes := classifyClusterDefinitionChanges(oldClusterDef, newDiffTestClusterDef(), nil); len(changes) != 0 {
+		t.Errorf("expected no changes, got: %v", changes)
+	}
+
+	testCases := []struct {
+		name     string
+		clusters []Cluster
+		expected map[string]clusterDefinitionChangeType
+	}{
+		{
+			name: "no clusters",
+			expected: map[string]clusterDefinitionChangeType{
+				"spec.componentDefs[proxy]":                      clusterDefinitionChangeSafe,
+				"spec.componentDefs[mysql].service.ports[paxos]": clusterDefinitionChangeSafe,
+				"spec.componentDefs[mysql].volumeTypes[log]":     clusterDefinitionChangeSafe,
+				"spec.componentDefs[mysql].podSpec":              clusterDefinitionChangeRolling,
+				"spec.componentDefs[exporter]":                   clusterDefinitionChangeSafe,
+			},
+		},
+		{
+			name: "clusters of other cluster definitions are ignored",
+			clusters: []Cluster{
+				newDiffTestCluster("other", "postgresql", ClusterComponentSpec{Name: "proxy", ComponentDefRef: "proxy"}),
+			},
+			expected: map[string]clusterDefinitionChangeType{
+				"spec.componentDefs[proxy]": clusterDefinitionChangeSafe,
+			},
+		},
+		{
+			name: "the removed port is referenced and the removed volume is not claimed",
+			clusters: []Cluster{
+				newDiffTestCluster("mycluster", "mysql", ClusterComponentSpec{
+					Name:                 "mysql",
+					ComponentDefRef:      "mysql",
+					VolumeClaimTemplates: []ClusterComponentVolumeClaimTemplate{{Name: "data"}},
+				}),
+			},
+			expected: map[string]clusterDefinitionChangeType{
+				"spec.componentDefs[proxy]":                      clusterDefinitionChangeSafe,
+				"spec.componentDefs[mysql].service.ports[paxos]": clusterDefinitionChangeBreaking,
+				"spec.componentDefs[mysql].volumeTypes[log]":     clusterDefinitionChangeSafe,
+			},
+		},
+		{
+			name: "the removed componentDef and volume are referenced",
+			clusters: []Cluster{
+				newDiffTestCluster("mycluster", "mysql",
+					ClusterComponentSpec{
+						Name:                 "mysql",
+						ComponentDefRef:      "mysql",
+						VolumeClaimTemplates: []ClusterComponentVolumeClaimTemplate{{Name: "data"}, {Name: "log"}},
+					},
+					ClusterComponentSpec{Name: "proxy", ComponentDefRef: "proxy"},
+				),
+			},
+			expected: map[string]clusterDefinitionChangeType{
+				"spec.componentDefs[proxy]":                  clusterDefinitionChangeBreaking,
+				"spec.componentDefs[mysql].volumeTypes[log]": clusterDefinitionChangeBreaking,
+				"spec.componentDefs[mysql].podSpec":          clusterDefinitionChangeRolling,
+			},
+		},
+		{
+			name: "the removed componentDef is referenced by a sharding",
+			clusters: []Cluster{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sharding"},
+					Spec: ClusterSpec{
+						ClusterDefRef: "mysql",
+						ShardingSpecs: []ShardingSpec{{
+							Name:     "proxy",
+							Template: ClusterComponentSpec{Name: "proxy", ComponentDefRef: "proxy"},
+							Shards:   3,
+						}},
+					},
+				},
+			},
+			expected: map[string]clusterDefinitionChangeType{
+				"spec.componentDefs[proxy]": clusterDefinitionChangeBreaking,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := classifyClusterDefinitionChanges(oldClusterDef, clusterDef, tc.clusters)
+			if len(changes) != 5 {
+				t.Errorf("expected 5 changes, got: %v", changes)
+			}
+			for path, expected := range tc.expected {
+				change := findClusterDefinitionChange(changes, path)
+				if change == nil {
+					t.Errorf("expected change of %s, got: %v", path, changes)
+					continue
+				}
+				if change.Type != expected {
+					t.Errorf("expected change of %s to be %s, got: %s", path, expected, change)
+				}
+			}
+		})
+	}
+
+	change := findClusterDefinitionChange(classifyClusterDefinitionChanges(oldClusterDef, clusterDef, testCases[3].clusters),
+		"spec.componentDefs[proxy]")
+	if !strings.Contains(change.Message, "referenced by clusters [default/mycluster]") {
+		t.Errorf("expected the referencing clusters in the message, got: %s", change.Message)
+	}
+}
+
+func TestValidateClusterDefinitionChanges(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cluster := newDiffTestCluster("mycluster", "mysql",
+		ClusterComponentSpec{Name: "proxy", ComponentDefRef: "proxy"})
+	originalMgr := webhookMgr
+	webhookMgr = &webhookManager{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&cluster).Build()}
+	defer func() { webhookMgr = originalMgr }()
+
+	oldClusterDef := newDiffTestClusterDef()
+
+	// the rolling changes are returned as warnings
+	clusterDef := newDiffTestClusterDef()
+	clusterDef.Spec.ComponentDefs[1].PodSpec.Containers[0].Image = "proxy:1.1"
+	warnings, err := clusterDef.validateChanges(oldClusterDef)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spec.componentDefs[proxy].podSpec: podSpec is changed") {
+		t.Errorf("expected a warning of the podSpec change, got: %v", warnings)
+	}
+
+	// the breaking changes are rejected
+	clusterDef = newDiffTestClusterDef()
+	clusterDef.Spec.ComponentDefs = clusterDef.Spec.ComponentDefs[:1]
+	_, err = clusterDef.ValidateUpdate(oldClusterDef)
+	if err == nil || !strings.Contains(err.Error(), "componentDef proxy is removed, which is referenced by clusters [default/mycluster]") {
+		t.Fatalf("expected the breaking change to be rejected, got: %v", err)
+	}
+
+	// the breaking changes are allowed by the annotation
+	clusterDef.Annotations = map[string]string{AllowBreakingChangeAnnotationKey: "true"}
+	warnings, err = clusterDef.validateChanges(oldClusterDef)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "componentDef proxy is removed") {
+		t.Errorf("expected a warning of the breaking change, got: %v", warnings)
+	}
+}
+
+func TestClassifyHeadlessServiceNameSuffixChanges(t *testing.T) {
+	oldClusterDef := newDiffTestClusterDef()
+	clusterDef := newDiffTestClusterDef()
+	clusterDef.Spec.ComponentDefs[0].Service.Headless = &HeadlessServiceSpec{NameSuffix: "hs"}
+	const path = "spec.componentDefs[mysql].service.headless.nameSuffix"
+
+	change := findClusterDefinitionChange(classifyClusterDefinitionChanges(oldClusterDef, clusterDef, nil), path)
+	if change == nil || change.Type != clusterDefinitionChangeSafe {
+		t.Errorf("expected a safe change of the name suffix, got: %v", change)
+	}
+
+	clusters := []Cluster{newDiffTestCluster("mycluster", "mysql", ClusterComponentSpec{Name: "mysql", ComponentDefRef: "mysql"})}
+	change = findClusterDefinitionChange(classifyClusterDefinitionChanges(oldClusterDef, clusterDef, clusters), path)
+	if change == nil || change.Type != clusterDefinitionChangeBreaking {
+		t.Fatalf("expected a breaking change of the name suffix, got: %v", change)
+	}
+	if !strings.Contains(change.Message, `changed from "" to "hs"`) {
+		t.Errorf("expected the suffixes in the message, got: %s", change.Message)
+	}
+
+	// the other customizations of the headless service are not breaking
+	clusterDef = newDiffTestClusterDef()
+	clusterDef.Spec.ComponentDefs[0].Service.Headless = &HeadlessServiceSpec{SessionAffinity: corev1.ServiceAffinityClientIP}
+	if changes := classifyClusterDefinitionChanges(oldClusterDef, clusterDef, clusters); len(changes) != 0 {
+		t.Errorf("expected no changes, got: %v", changes)
+	}
+}
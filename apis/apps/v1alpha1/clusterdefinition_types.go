@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -30,6 +33,7 @@ import (
 // ClusterDefinitionSpec defines the desired state of ClusterDefinition
 type ClusterDefinitionSpec struct {
 	// Specifies the well-known application cluster type, such as mysql, redis, or mongodb.
+	// Cannot be updated once set.
 	//
 	// +kubebuilder:validation:MaxLength=24
 	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`
@@ -60,9 +64,22 @@ type ClusterDefinitionSpec struct {
 	//    where 1ST_COMP_NAME is the 1st component that provide `ClusterDefinition.spec.componentDefs[].service` attribute;
 	// - `$(SVC_FQDN)` service FQDN placeholder, value pattern is `$(CLUSTER_NAME)-$(1ST_COMP_NAME).$(NAMESPACE).svc`,
 	//    where 1ST_COMP_NAME is the 1st component that provide `ClusterDefinition.spec.componentDefs[].service` attribute;
+	// - `$(HEADLESS_SVC_FQDN_{COMPONENT-NAME})` and `$(SVC_FQDN_{COMPONENT-NAME})` are the same as `$(HEADLESS_SVC_FQDN)`
+	//    and `$(SVC_FQDN)`, but are resolved against the component referring to the componentDef named COMPONENT-NAME,
+	//    which must provide the `ClusterDefinition.spec.componentDefs[].service` attribute;
 	// - `$(SVC_PORT_{PORT-NAME})` is ServicePort's port value with specified port name, i.e, a servicePort JSON struct:
 	//    `{"name": "mysql", "targetPort": "mysqlContainerPort", "port": 3306}`, and `$(SVC_PORT_mysql)` in the
-	//    connection credential value is 3306.
+	//    connection credential value is 3306. The port name must be declared by at least one componentDef service,
+	//    and all componentDefs declaring it must use the same port value.
+	// - `$(KB_CLUSTER_COMP_NAME)` the name of the component prefixed with the cluster name.
+	// - `$(CONN_CREDENTIAL).{KEY}` the value of another key in the connection credential.
+	//
+	// Unknown `$(...)` placeholders are reported as warnings by the webhook, or rejected if it is configured so.
+	// Use `$$` for a literal `$`, e.g. `$$(VALUE)` is rendered as `$(VALUE)`.
+	//
+	// If the cluster is annotated with `apps.kubeblocks.io/connection-credential-seed`, the values of `$(RANDOM_PASSWD)`,
+	// `$(STRONG_RANDOM_PASSWD)` and the UUID placeholders are derived from the seed instead of being randomly generated,
+	// so the same connection credential is built for the clusters with the same seed.
 	//
 	// +optional
 	ConnectionCredential map[string]string `json:"connectionCredential,omitempty"`
@@ -134,11 +151,28 @@ type PasswordConfig struct {
 	//
 	// +optional
 	Seed string `json:"seed,omitempty"`
+
+	// Specifies the symbols which can be used in the password, it helps to avoid the symbols breaking
+	// the provision statements or the connection URLs. Only the symbols in `!#%&*+,-./:=?@^_~` are allowed.
+	// The default symbols `!@#&*` are used if not set.
+	// Cannot be updated.
+	//
+	// +kubebuilder:validation:Pattern:=`^[!#%&*+,\-./:=?@^_~]+$`
+	// +optional
+	SymbolCharacters string `json:"symbolCharacters,omitempty"`
+
+	// Specifies the generator of the password. The `Kms` generator requests the password from the KMS webhook
+	// configured for KubeBlocks, the seed is ignored by it. The `Default` generator is used if not set.
+	// The provisioning of the account fails if the password can not be generated.
+	//
+	// +optional
+	Generator PasswordGeneratorType `json:"generator,omitempty"`
 }
 
 // SystemAccountConfig specifies how to create and delete system accounts.
 type SystemAccountConfig struct {
 	// The unique identifier of a system account.
+	// Cannot be updated.
 	//
 	// +kubebuilder:validation:Required
 	Name AccountName `json:"name"`
@@ -147,6 +181,41 @@ type SystemAccountConfig struct {
 	//
 	// +kubebuilder:validation:Required
 	ProvisionPolicy ProvisionPolicy `json:"provisionPolicy"`
+
+	// Overrides the spec-level PasswordConfig for this account only.
+	// Fields that are not set fall back to the values defined in `systemAccounts.passwordConfig`.
+	//
+	// +optional
+	PasswordConfig *PasswordConfigOverride `json:"passwordConfig,omitempty"`
+}
+
+// PasswordConfigOverride overrides a subset of PasswordConfig for a specific account.
+type PasswordConfigOverride struct {
+	// The length of the password.
+	//
+	// +kubebuilder:validation:Maximum=32
+	// +kubebuilder:validation:Minimum=8
+	// +optional
+	Length *int32 `json:"length,omitempty"`
+
+	// The number of digits in the password.
+	//
+	// +kubebuilder:validation:Maximum=8
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NumDigits *int32 `json:"numDigits,omitempty"`
+
+	// The number of symbols in the password.
+	//
+	// +kubebuilder:validation:Maximum=8
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NumSymbols *int32 `json:"numSymbols,omitempty"`
+
+	// The case of the letters in the password.
+	//
+	// +optional
+	LetterCase *LetterCase `json:"letterCase,omitempty"`
 }
 
 // ProvisionPolicy defines the policy details for creating accounts.
@@ -221,12 +290,65 @@ type ClusterDefinitionStatus struct {
 	//
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Describes the current state of the ClusterDefinition, such as whether the data volumes
+	// required by backup are declared in the volumeTypes of componentDefs.
+	//
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Records the validation results of each componentDef. The ClusterDefinition is `Available`
+	// only if all the componentDefs are `Available`.
+	//
+	// +optional
+	Components []ComponentDefStatus `json:"components,omitempty"`
+}
+
+// ComponentDefStatus describes the validation result of a componentDef in the ClusterDefinition.
+type ComponentDefStatus struct {
+	// Specifies the name of the componentDef.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies the phase of the componentDef, `Available` if the componentDef passes the validation,
+	// otherwise `Unavailable`.
+	//
+	// +optional
+	Phase Phase `json:"phase,omitempty"`
+
+	// Provides the reason why the componentDef is unavailable.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Represents the generation of the ClusterDefinition observed when the componentDef is validated.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 func (r ClusterDefinitionStatus) GetTerminalPhases() []Phase {
 	return []Phase{AvailablePhase}
 }
 
+func (r ClusterDefinitionStatus) GetPhase() Phase {
+	return r.Phase
+}
+
+func (r ClusterDefinitionStatus) GetMessage() string {
+	return r.Message
+}
+
+func (r ClusterDefinitionStatus) GetObservedGeneration() int64 {
+	return r.ObservedGeneration
+}
+
+// GetTerminalPhaseStatus returns the status of the ClusterDefinition.
+func (r *ClusterDefinition) GetTerminalPhaseStatus() TerminalPhaseStatus {
+	return r.Status
+}
+
 type ExporterConfig struct {
 	// Defines the port that the exporter uses for the Time Series Database to scrape metrics.
 	//
@@ -240,6 +362,69 @@ type ExporterConfig struct {
 	// +kubebuilder:default="/metrics"
 	// +optional
 	ScrapePath string `json:"scrapePath,omitempty"`
+
+	// Specifies the scheme that the exporter uses for the Time Series Database to scrape metrics.
+	//
+	// +kubebuilder:default="http"
+	// +optional
+	ScrapeScheme ScrapeScheme `json:"scrapeScheme,omitempty"`
+
+	// Specifies the TLS configuration for scraping metrics, it is only valid when the scrapeScheme is https.
+	//
+	// +optional
+	TLSConfig *ExporterTLSConfig `json:"tlsConfig,omitempty"`
+
+	// Specifies whether to create a ServiceMonitor of prometheus-operator for the component, which scrapes the metrics
+	// from the exporter through the headless service of the component.
+	// If the ServiceMonitor CRD is not installed, the metrics are exposed by the annotations of the headless service instead.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	ServiceMonitor bool `json:"serviceMonitor,omitempty"`
+}
+
+// ExporterTLSConfig defines the TLS configuration used to scrape metrics from the exporter.
+type ExporterTLSConfig struct {
+	// Disables the verification of the exporter's certificate chain and host name.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// References the key of a Secret that contains the CA certificate to verify the exporter's certificate.
+	// The Secret must reside in the same namespace as the Cluster.
+	//
+	// +optional
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+}
+
+// GetScrapeScheme returns the scrape scheme of the exporter, the default is http.
+func (r *ExporterConfig) GetScrapeScheme() ScrapeScheme {
+	if r.ScrapeScheme == "" {
+		return HTTPScrapeScheme
+	}
+	return r.ScrapeScheme
+}
+
+// Validate checks that the TLS configuration is only set when the scrape scheme is https.
+func (r *ExporterConfig) Validate() error {
+	if r.TLSConfig != nil && r.GetScrapeScheme() != HTTPSScrapeScheme {
+		return fmt.Errorf("tlsConfig is only allowed when scrapeScheme is %s, but got %s", HTTPSScrapeScheme, r.GetScrapeScheme())
+	}
+	return nil
+}
+
+// Validate checks that the parsing hints of the log config are valid.
+func (r *LogConfig) Validate() error {
+	if r.MultilinePattern != "" {
+		if _, err := regexp.Compile(r.MultilinePattern); err != nil {
+			return fmt.Errorf("multilinePattern of log %s is not a valid regular expression: %v", r.Name, err)
+		}
+	}
+	if r.TimeFormat != "" && r.TimeKey == "" {
+		return fmt.Errorf("timeKey of log %s is required when timeFormat is set", r.Name)
+	}
+	return nil
 }
 
 type MonitorConfig struct {
@@ -270,6 +455,32 @@ type LogConfig struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MaxLength=4096
 	FilePathPattern string `json:"filePathPattern"`
+
+	// Specifies the format of the log, which tells the log agent how to parse the log entries.
+	// Supported values are `json` and `regex`, for `regex` the log agent parses the log with the
+	// parser named after the log type.
+	// If it is not set, the log is tailed as plain text line by line.
+	//
+	// +optional
+	Format LogFormat `json:"format,omitempty"`
+
+	// Specifies the key of the parsed log entry which holds the timestamp of the log.
+	//
+	// +optional
+	TimeKey string `json:"timeKey,omitempty"`
+
+	// Specifies the format of the timestamp held by the timeKey, e.g. `%Y-%m-%dT%H:%M:%S.%L%z`.
+	//
+	// +optional
+	TimeFormat string `json:"timeFormat,omitempty"`
+
+	// Specifies the regular expression matching the first line of a multiline log entry,
+	// e.g. `^# Time:` for a MySQL slow log. The following lines not matching it are appended
+	// to the entry.
+	//
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	MultilinePattern string `json:"multilinePattern,omitempty"`
 }
 
 type VolumeTypeSpec struct {
@@ -298,12 +509,60 @@ type VolumeProtectionSpec struct {
 	// +optional
 	HighWatermark int `json:"highWatermark,omitempty"`
 
+	// The low watermark threshold for volume space usage.
+	// Once the instance has been degraded by the "LOCK" action, the "UNLOCK" action will only be performed after
+	// all volumes' space usage drops under this threshold, to avoid flapping between lock and unlock around the
+	// high watermark. It should be no greater than the high watermark. If it is not specified, the high watermark
+	// will be used as the low watermark.
+	//
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LowWatermark int `json:"lowWatermark,omitempty"`
+
 	// The Volumes to be protected.
 	//
 	// +optional
 	Volumes []ProtectedVolume `json:"volumes,omitempty"`
 }
 
+// ScheduledRestartPolicy defines the policy to restart the component periodically.
+type ScheduledRestartPolicy struct {
+	// Specifies the schedule of the restart in the Cron format, e.g. "0 3 * * 0".
+	// The time zone can be specified by the prefix "CRON_TZ=", UTC is used by default.
+	//
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// Overrides the member update strategy of the component while the scheduled restart is in progress.
+	// It only takes effect when the component is updated by the member update strategy,
+	// if not specified, the update strategy of the component is used.
+	//
+	// +optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// Specifies the maintenance window in which the scheduled restart can be started.
+	// The restart is postponed to the next window if it is due outside the window.
+	// If not specified, the restart is started as soon as it is due.
+	//
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindow defines a daily time window for the maintenance operations.
+type MaintenanceWindow struct {
+	// Specifies the start time of the window every day, in the format of "HH:MM" in UTC.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+
+	// Specifies the duration of the window, no longer than 24 hours.
+	//
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+}
+
 type ProtectedVolume struct {
 	// The Name of the volume to protect.
 	//
@@ -317,6 +576,15 @@ type ProtectedVolume struct {
 	// +kubebuilder:validation:Minimum=0
 	// +optional
 	HighWatermark *int `json:"highWatermark,omitempty"`
+
+	// Defines the low watermark threshold for the volume, it will override the component level threshold.
+	// It should be no greater than the high watermark of the volume.
+	// If the value is invalid, it will be ignored and the component level threshold will be used.
+	//
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	LowWatermark *int `json:"lowWatermark,omitempty"`
 }
 
 type ServiceRefDeclaration struct {
@@ -340,6 +608,42 @@ type ServiceRefDeclaration struct {
 	//
 	// +kubebuilder:validation:Required
 	ServiceRefDeclarationSpecs []ServiceRefDeclarationSpec `json:"serviceRefDeclarationSpecs"`
+
+	// Specifies whether the service reference is optional.
+	// If set to false, the Cluster must bind the service reference in `cluster.spec.componentSpecs[*].serviceRefs`,
+	// otherwise the component can not be rendered.
+	// If set to true or not set, the unbound service reference is ignored unless the `defaultEndpoint` is specified.
+	//
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+
+	// Specifies the default endpoint of the service reference, which is used when the service reference
+	// is not bound by the Cluster. It can only be set if the service reference is optional.
+	//
+	// +optional
+	DefaultEndpoint *ServiceRefDefaultEndpoint `json:"defaultEndpoint,omitempty"`
+}
+
+// ServiceRefDefaultEndpoint defines the default endpoint of a service reference.
+type ServiceRefDefaultEndpoint struct {
+	// Specifies the host of the default endpoint.
+	//
+	// +kubebuilder:validation:Required
+	Host string `json:"host"`
+
+	// Specifies the port of the default endpoint.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// Refers to the secret which contains the credential to access the default endpoint.
+	// The secret must be in the namespace of the Cluster, and the `username` and `password` keys
+	// of the secret are used as the credential.
+	//
+	// +optional
+	CredentialSecretRef *corev1.LocalObjectReference `json:"credentialSecretRef,omitempty"`
 }
 
 type ServiceRefDeclarationSpec struct {
@@ -354,6 +658,7 @@ type ServiceRefDeclarationSpec struct {
 
 	// Defines the service version of the service reference. This is a regular expression that matches a version number pattern.
 	// For instance, `^8.0.8$`, `8.0.\d{1,2}$`, `^[v\-]*?(\d{1,2}\.){0,3}\d{1,2}$` are all valid patterns.
+	// The pattern is not anchored implicitly, and a malformed pattern is rejected on admission.
 	//
 	// +kubebuilder:validation:Required
 	ServiceVersion string `json:"serviceVersion"`
@@ -497,13 +802,24 @@ type ClusterComponentDefinition struct {
 	// +optional
 	VolumeTypes []VolumeTypeSpec `json:"volumeTypes,omitempty"`
 
-	// Used for custom label tags which you want to add to the component resources.
+	// Used for custom label tags which you want to add to all the component resources.
+	// The key and value support the built-in variables, such as $(KB_CLUSTER_NAME), $(KB_COMP_NAME)
+	// and $(KB_CLUSTER_COMP_NAME).
 	//
 	// +listType=map
 	// +listMapKey=key
 	// +optional
 	CustomLabelSpecs []CustomLabelSpec `json:"customLabelSpecs,omitempty"`
 
+	// Used for custom annotations which you want to add to all the component resources.
+	// The key and value support the built-in variables, such as $(KB_CLUSTER_NAME), $(KB_COMP_NAME)
+	// and $(KB_CLUSTER_COMP_NAME).
+	//
+	// +listType=map
+	// +listMapKey=key
+	// +optional
+	CustomAnnotationSpecs []CustomAnnotationSpec `json:"customAnnotationSpecs,omitempty"`
+
 	// Defines command to do switchover.
 	// In particular, when workloadType=Replication, the command defined in switchoverSpec will only be executed under
 	// the condition of cluster.componentSpecs[x].SwitchPolicy.type=Noop.
@@ -522,6 +838,12 @@ type ClusterComponentDefinition struct {
 	// +optional
 	VolumeProtectionSpec *VolumeProtectionSpec `json:"volumeProtectionSpec,omitempty"`
 
+	// Defines the policy to restart the component periodically, which is useful for the engines
+	// that need a periodic rolling restart, such as to release the fragmented memory.
+	//
+	// +optional
+	RestartPolicy *ScheduledRestartPolicy `json:"restartPolicy,omitempty"`
+
 	// Used to inject values from other components into the current component. Values will be saved and updated in a
 	// configmap and mounted to the current component.
 	//
@@ -578,6 +900,69 @@ func (r *ClusterComponentDefinition) GetCommonStatefulSpec() (*StatefulSetSpec,
 	return nil, nil
 }
 
+// ValidateVolumeTypes checks that each volume declared in VolumeTypes corresponds to a volumeMount
+// of the PodSpec containers, and returns an error listing the dangling volume names if not.
+func (r *ClusterComponentDefinition) ValidateVolumeTypes() error {
+	if len(r.VolumeTypes) == 0 {
+		return nil
+	}
+	containerNames := make([]string, 0)
+	volumeMounts := make(map[string]struct{})
+	if r.PodSpec != nil {
+		for _, container := range r.PodSpec.Containers {
+			containerNames = append(containerNames, container.Name)
+			for _, volumeMount := range container.VolumeMounts {
+				volumeMounts[volumeMount.Name] = struct{}{}
+			}
+		}
+	}
+	danglingNames := make([]string, 0)
+	for _, volumeType := range r.VolumeTypes {
+		if _, ok := volumeMounts[volumeType.Name]; !ok {
+			danglingNames = append(danglingNames, volumeType.Name)
+		}
+	}
+	if len(danglingNames) == 0 {
+		return nil
+	}
+	return fmt.Errorf("volumeTypes [%s] of componentDef %s are not mounted by any container, containers searched: [%s]",
+		strings.Join(danglingNames, ","), r.Name, strings.Join(containerNames, ","))
+}
+
+// Validate checks that the default endpoint is only declared for the optional service reference,
+// and the service versions of the declaration specs are valid regular expressions.
+func (r *ServiceRefDeclaration) Validate() error {
+	if r.DefaultEndpoint != nil && (r.Optional == nil || !*r.Optional) {
+		return fmt.Errorf("the defaultEndpoint of serviceRefDeclaration %s can only be set if optional is true", r.Name)
+	}
+	for _, spec := range r.ServiceRefDeclarationSpecs {
+		if _, err := MatchServiceVersion(spec.ServiceVersion, ""); err != nil {
+			return fmt.Errorf("the serviceVersion of serviceRefDeclaration %s is invalid: %s", r.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// MatchServiceVersion checks whether the version matches the pattern of ServiceRefDeclarationSpec.ServiceVersion.
+// The pattern is not anchored implicitly, so `8.0` matches `8.0.30` while `^8.0$` does not.
+func MatchServiceVersion(pattern, version string) (bool, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return regex.MatchString(version), nil
+}
+
+// HasDataVolumeType checks whether a volume of type data is declared in VolumeTypes.
+func (r *ClusterComponentDefinition) HasDataVolumeType() bool {
+	for _, volumeType := range r.VolumeTypes {
+		if volumeType.Type == VolumeTypeData {
+			return true
+		}
+	}
+	return false
+}
+
 type ServiceSpec struct {
 	// The list of ports that are exposed by this service.
 	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#virtual-ips-and-service-proxies
@@ -591,6 +976,36 @@ type ServiceSpec struct {
 	Ports []ServicePort `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"port" protobuf:"bytes,1,rep,name=ports"`
 
 	// NOTES: name also need to be key
+
+	// Customizes the headless service of the component, which governs the network identity of the pods.
+	//
+	// +optional
+	Headless *HeadlessServiceSpec `json:"headless,omitempty"`
+}
+
+// HeadlessServiceSpec customizes the headless service of the component.
+type HeadlessServiceSpec struct {
+	// Indicates whether the addresses of the pods are published by the headless service before they are ready,
+	// which is required by the consensus engines to discover the peers during bootstrap. Defaults to true.
+	//
+	// +kubebuilder:default=true
+	// +optional
+	PublishNotReadyAddresses *bool `json:"publishNotReadyAddresses,omitempty"`
+
+	// Specifies the session affinity of the headless service, supports "ClientIP" and "None".
+	//
+	// +kubebuilder:validation:Enum={ClientIP,None}
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+
+	// Replaces the default suffix `headless` of the headless service name, the pods are addressed as
+	// `$(POD_NAME).$(CLUSTER_NAME)-$(COMPONENT_NAME)-{nameSuffix}`.
+	// It can't be changed once the ClusterDefinition is referenced by clusters.
+	//
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`
+	// +optional
+	NameSuffix string `json:"nameSuffix,omitempty"`
 }
 
 func (r *ServiceSpec) ToSVCPorts() []corev1.ServicePort {
@@ -607,6 +1022,29 @@ func (r ServiceSpec) ToSVCSpec() corev1.ServiceSpec {
 	}
 }
 
+// ToHeadlessSVCSpec converts the service spec to the spec of the headless service.
+func (r ServiceSpec) ToHeadlessSVCSpec() corev1.ServiceSpec {
+	spec := r.ToSVCSpec()
+	spec.Type = corev1.ServiceTypeClusterIP
+	spec.ClusterIP = corev1.ClusterIPNone
+	spec.PublishNotReadyAddresses = true
+	if r.Headless != nil {
+		if r.Headless.PublishNotReadyAddresses != nil {
+			spec.PublishNotReadyAddresses = *r.Headless.PublishNotReadyAddresses
+		}
+		spec.SessionAffinity = r.Headless.SessionAffinity
+	}
+	return spec
+}
+
+// GetHeadlessNameSuffix returns the custom suffix of the headless service name, it's empty if not customized.
+func (r *ServiceSpec) GetHeadlessNameSuffix() string {
+	if r == nil || r.Headless == nil {
+		return ""
+	}
+	return r.Headless.NameSuffix
+}
+
 type ServicePort struct {
 	// The name of this port within the service. This must be a DNS_LABEL.
 	// All ports within a ServiceSpec must have unique names. When considering
@@ -675,15 +1113,34 @@ type HorizontalScalePolicy struct {
 	Type HScaleDataClonePolicyType `json:"type,omitempty"`
 
 	// Refers to the backup policy template.
+	// If it is not specified, the default backup policy template of the component is used,
+	// which is the same as the one that the default backup policy of the cluster is generated from.
 	//
 	// +optional
 	BackupPolicyTemplateName string `json:"backupPolicyTemplateName,omitempty"`
 
+	// Specifies the preferred method to clone data when the policy type is `CloneVolume`.
+	// The method can be one of the following: {Snapshot, Backup}. The default method is `Snapshot`.
+	//
+	// - `Snapshot`: Uses a volume snapshot first, and falls back to the backup tool if volume snapshot is not enabled.
+	// - `Backup`: Uses the backup tool first, and falls back to a volume snapshot if no backup tool method is configured
+	//   in the backup policy. It requires BackupPolicyTemplateName to be specified.
+	//
+	// +optional
+	PreferredCloneMethod HScaleDataCloneMethod `json:"preferredCloneMethod,omitempty"`
+
 	// Specifies the volumeMount of the container to backup.
 	// This only works if Type is not None. If not specified, the first volumeMount will be selected.
 	//
 	// +optional
 	VolumeMountsName string `json:"volumeMountsName,omitempty"`
+
+	// Specifies the name of the VolumeSnapshotClass used to clone the volumes by the volume snapshot.
+	// It overrides the VolumeSnapshotClass of the backup method. If not specified, the class of the
+	// backup method is used, or the first one whose driver matches the CSI driver of the volume.
+	//
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
 }
 
 type ClusterDefinitionProbeCMDs struct {
@@ -698,6 +1155,10 @@ type ClusterDefinitionProbeCMDs struct {
 	Queries []string `json:"queries,omitempty"`
 }
 
+// ClusterDefinitionProbe defines how to probe the component, the probe is performed by one of the commands,
+// the HTTP GET request or the gRPC health check.
+//
+// +kubebuilder:validation:XValidation:rule="[has(self.commands), has(self.httpGet), has(self.grpc)].filter(x, x).size() <= 1",message="only one of commands, httpGet and grpc can be specified"
 type ClusterDefinitionProbe struct {
 	// How often (in seconds) to perform the probe.
 	//
@@ -706,6 +1167,7 @@ type ClusterDefinitionProbe struct {
 	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
 
 	// Number of seconds after which the probe times out. Defaults to 1 second.
+	// It should not be greater than periodSeconds, otherwise the executions of the probe overlap.
 	//
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Minimum=1
@@ -721,6 +1183,56 @@ type ClusterDefinitionProbe struct {
 	//
 	// +optional
 	Commands *ClusterDefinitionProbeCMDs `json:"commands,omitempty"`
+
+	// Specifies the HTTP request to perform for probe, it follows the semantics of the HTTPGet of corev1.Probe.
+	// For the role probe, the response body is taken as the role of the replica.
+	//
+	// +optional
+	HTTPGet *corev1.HTTPGetAction `json:"httpGet,omitempty"`
+
+	// Specifies the gRPC health check to perform for probe, it follows the semantics of the GRPC of corev1.Probe.
+	// It is not supported by the role probe, as the gRPC health checking protocol does not report the role.
+	//
+	// +optional
+	GRPC *corev1.GRPCAction `json:"grpc,omitempty"`
+}
+
+// Validate checks that at most one mode of the probe is specified, the commands are not empty if
+// specified, and the probe times out no later than the next probe starts, otherwise the executions
+// of the probe overlap.
+func (r *ClusterDefinitionProbe) Validate() error {
+	modes := 0
+	if r.Commands != nil {
+		modes++
+		if len(r.Commands.Queries) == 0 && len(r.Commands.Writes) == 0 {
+			return fmt.Errorf("at least one of commands.queries and commands.writes should be specified")
+		}
+	}
+	if r.HTTPGet != nil {
+		modes++
+	}
+	if r.GRPC != nil {
+		modes++
+	}
+	if modes > 1 {
+		return fmt.Errorf("only one of commands, httpGet and grpc can be specified")
+	}
+	if r.TimeoutSeconds > r.PeriodSeconds && r.PeriodSeconds > 0 {
+		return fmt.Errorf("timeoutSeconds %d should not be greater than periodSeconds %d", r.TimeoutSeconds, r.PeriodSeconds)
+	}
+	return nil
+}
+
+// SetDefaults sets the default role probe timeout if the role probe is specified, and resets it
+// to zero otherwise, since it only takes effect for the components supporting the role probe.
+func (r *ClusterDefinitionProbes) SetDefaults() {
+	if r.RoleProbe == nil {
+		r.RoleProbeTimeoutAfterPodsReady = 0
+		return
+	}
+	if r.RoleProbeTimeoutAfterPodsReady == 0 {
+		r.RoleProbeTimeoutAfterPodsReady = DefaultRoleProbeTimeoutAfterPodsReady
+	}
 }
 
 type ClusterDefinitionProbes struct {
@@ -790,6 +1302,17 @@ type StatefulSetSpec struct {
 	//
 	// +optional
 	LLUpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"llUpdateStrategy,omitempty"`
+
+	// Specifies the minimum number of seconds for which a newly created pod should be ready
+	// without any of its container crashing for it to be considered available.
+	// For the `Serial` and `BestEffortParallel` update strategies, the next member is updated only after
+	// the updated member has been available, which leaves time for the replication to catch up.
+	// Defaults to 0 (pod will be considered available as soon as it is ready).
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
 }
 
 var _ StatefulSetWorkload = &StatefulSetSpec{}
@@ -801,13 +1324,84 @@ func (r *StatefulSetSpec) GetUpdateStrategy() UpdateStrategy {
 	return r.UpdateStrategy
 }
 
-func (r *StatefulSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
+func (r *StatefulSetSpec) GetMinReadySeconds() int32 {
 	if r == nil {
-		r = &StatefulSetSpec{
-			UpdateStrategy: SerialStrategy,
+		return 0
+	}
+	return r.MinReadySeconds
+}
+
+func (r *StatefulSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
+	policy, strategy, _ := ResolveWorkloadUpdateStrategy(&ClusterComponentDefinition{WorkloadType: Stateful, StatefulSpec: r}, nil)
+	return policy, strategy
+}
+
+// ResolveWorkloadUpdateStrategy resolves the pod management policy and the update strategy of the StatefulSet
+// of a component, given its component definition and the update strategy overridden in the cluster.
+// It allows previewing the final update strategy of a component before it is created:
+//
+//   - The LLPodManagementPolicy and LLUpdateStrategy are used as they are if the LLUpdateStrategy is provided,
+//     the overridden update strategy is ignored in this case.
+//   - `Serial` is mapped to the OrderedReady pod management and the rolling update with 1 max unavailable pod,
+//     `BestEffortParallel` to the Parallel pod management and the rolling update with 49% max unavailable pods,
+//     `Parallel` to the Parallel pod management and the default rolling update.
+//   - The Consensus and Replication workloads always use the Parallel pod management and the OnDelete update strategy,
+//     as their pods are updated by the controller in the order of the roles.
+//
+// An error is returned if the workload type of the component definition is stateless or unknown.
+func ResolveWorkloadUpdateStrategy(compDef *ClusterComponentDefinition,
+	override *UpdateStrategy) (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy, error) {
+	if compDef == nil {
+		return "", appsv1.StatefulSetUpdateStrategy{}, fmt.Errorf("the component definition is nil")
+	}
+	var spec StatefulSetSpec
+	switch compDef.WorkloadType {
+	case Stateless:
+		return "", appsv1.StatefulSetUpdateStrategy{}, ErrWorkloadTypeIsStateless
+	case Stateful:
+		spec = StatefulSetSpec{UpdateStrategy: SerialStrategy}
+		if compDef.StatefulSpec != nil {
+			spec = *compDef.StatefulSpec
+		}
+	case Consensus:
+		spec = NewConsensusSetSpec().StatefulSetSpec
+		if compDef.ConsensusSpec != nil {
+			spec = compDef.ConsensusSpec.StatefulSetSpec
+		}
+	case Replication:
+		if compDef.ReplicationSpec != nil {
+			spec = compDef.ReplicationSpec.StatefulSetSpec
 		}
+	default:
+		return "", appsv1.StatefulSetUpdateStrategy{}, ErrWorkloadTypeIsUnknown
+	}
+	if override != nil {
+		spec.UpdateStrategy = *override
+	}
+
+	if spec.LLUpdateStrategy != nil {
+		return spec.LLPodManagementPolicy, *spec.LLUpdateStrategy, nil
+	}
+	policy, strategy := spec.finalStsUpdateStrategy()
+	if compDef.WorkloadType == Consensus || compDef.WorkloadType == Replication {
+		policy = appsv1.ParallelPodManagement
+		strategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+		strategy.RollingUpdate = nil
 	}
-	return r.finalStsUpdateStrategy()
+	return policy, strategy, nil
+}
+
+// ValidateUpdateStrategyPartition validates the partition of the rolling update strategy against the replicas
+// of the component, the pods are never updated if the partition is greater than the replicas.
+func ValidateUpdateStrategyPartition(strategy *appsv1.StatefulSetUpdateStrategy, replicas int32) error {
+	if strategy == nil || strategy.RollingUpdate == nil || strategy.RollingUpdate.Partition == nil {
+		return nil
+	}
+	if partition := *strategy.RollingUpdate.Partition; partition > replicas {
+		return fmt.Errorf("the partition %d of the rolling update is greater than the replicas %d, the pods will never be updated",
+			partition, replicas)
+	}
+	return nil
 }
 
 func (r *StatefulSetSpec) finalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
@@ -867,6 +1461,16 @@ type ConsensusSetSpec struct {
 	//
 	// +optional
 	Learner *ConsensusMember `json:"learner,omitempty"`
+
+	// Specifies how the leader is evicted, e.g. when the node hosting it is drained.
+	//
+	// - `Direct`: Evicts the leader directly like the other members.
+	// - `SwitchoverFirst`: Switches the leadership over to another member before evicting the leader,
+	//   and falls back to `Direct` if the switchover is not done in time.
+	//
+	// +kubebuilder:validation:Enum={Direct,SwitchoverFirst}
+	// +optional
+	LeaderEvictionPolicy workloads.LeaderEvictionPolicy `json:"leaderEvictionPolicy,omitempty"`
 }
 
 var _ StatefulSetWorkload = &ConsensusSetSpec{}
@@ -878,20 +1482,16 @@ func (r *ConsensusSetSpec) GetUpdateStrategy() UpdateStrategy {
 	return r.UpdateStrategy
 }
 
-func (r *ConsensusSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
+func (r *ConsensusSetSpec) GetMinReadySeconds() int32 {
 	if r == nil {
-		r = NewConsensusSetSpec()
+		return 0
 	}
-	if r.LLUpdateStrategy != nil {
-		return r.LLPodManagementPolicy, *r.LLUpdateStrategy
-	}
-	_, s := r.StatefulSetSpec.finalStsUpdateStrategy()
-	// switch r.UpdateStrategy {
-	// case SerialStrategy, BestEffortParallelStrategy:
-	s.Type = appsv1.OnDeleteStatefulSetStrategyType
-	s.RollingUpdate = nil
-	// }
-	return appsv1.ParallelPodManagement, s
+	return r.MinReadySeconds
+}
+
+func (r *ConsensusSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
+	policy, strategy, _ := ResolveWorkloadUpdateStrategy(&ClusterComponentDefinition{WorkloadType: Consensus, ConsensusSpec: r}, nil)
+	return policy, strategy
 }
 
 func NewConsensusSetSpec() *ConsensusSetSpec {
@@ -950,10 +1550,46 @@ type RSMSpec struct {
 	// +kubebuilder:validation:Enum={Serial,BestEffortParallel,Parallel}
 	// +optional
 	MemberUpdateStrategy *workloads.MemberUpdateStrategy `json:"memberUpdateStrategy,omitempty"`
+
+	// Specifies the minimum number of seconds for which a newly created member should be ready
+	// without any of its container crashing for it to be considered available.
+	// For the `Serial` and `BestEffortParallel` member update strategies, the next member is updated only after
+	// the updated member has been available. It takes precedence over the minReadySeconds of the workload spec.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=3600
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// Specifies how the leader is evicted, e.g. when the node hosting it is drained.
+	// It takes precedence over the leaderEvictionPolicy of the consensus spec.
+	//
+	// - `Direct`: Evicts the leader directly like the other members.
+	// - `SwitchoverFirst`: Switches the leadership over to another member before evicting the leader,
+	//   and falls back to `Direct` if the switchover is not done in time.
+	//
+	// +kubebuilder:validation:Enum={Direct,SwitchoverFirst}
+	// +optional
+	LeaderEvictionPolicy workloads.LeaderEvictionPolicy `json:"leaderEvictionPolicy,omitempty"`
 }
 
+// replicationPrimaries is the number of the primaries of the Replication workload.
+const replicationPrimaries int32 = 1
+
 type ReplicationSetSpec struct {
 	StatefulSetSpec `json:",inline"`
+
+	// Specifies the maximum number of the secondaries which can be updated at the same time with the
+	// `BestEffortParallel` update strategy, the primary is always updated last.
+	// The value can be an absolute number (ex: 2) or a percentage of the replicas (ex: 50%),
+	// the absolute number is calculated from the percentage by rounding down.
+	//
+	// It defaults to `replicas - 1 - primaries` with a minimum of 1, which keeps one secondary available
+	// to take over the primary while the others are being updated.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 var _ StatefulSetWorkload = &ReplicationSetSpec{}
@@ -965,17 +1601,28 @@ func (r *ReplicationSetSpec) GetUpdateStrategy() UpdateStrategy {
 	return r.UpdateStrategy
 }
 
-func (r *ReplicationSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
+func (r *ReplicationSetSpec) GetMinReadySeconds() int32 {
 	if r == nil {
-		r = &ReplicationSetSpec{}
+		return 0
 	}
-	if r.LLUpdateStrategy != nil {
-		return r.LLPodManagementPolicy, *r.LLUpdateStrategy
+	return r.MinReadySeconds
+}
+
+func (r *ReplicationSetSpec) FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy) {
+	policy, strategy, _ := ResolveWorkloadUpdateStrategy(&ClusterComponentDefinition{WorkloadType: Replication, ReplicationSpec: r}, nil)
+	return policy, strategy
+}
+
+// FinalMaxUnavailable resolves the maximum number of the secondaries which can be updated at the same time
+// with the `BestEffortParallel` update strategy, given the replicas of the component.
+// As the pods of the Replication workload are updated by the controller in the order of the roles,
+// the value is used by the member update of the ReplicatedStateMachine rather than the StatefulSet.
+func (r *ReplicationSetSpec) FinalMaxUnavailable(replicas int32) (int32, error) {
+	var maxUnavailable *intstr.IntOrString
+	if r != nil {
+		maxUnavailable = r.MaxUnavailable
 	}
-	_, s := r.StatefulSetSpec.finalStsUpdateStrategy()
-	s.Type = appsv1.OnDeleteStatefulSetStrategyType
-	s.RollingUpdate = nil
-	return appsv1.ParallelPodManagement, s
+	return workloads.GetMaxUnavailableMembers(maxUnavailable, replicas, replicationPrimaries)
 }
 
 type PostStartAction struct {
@@ -989,6 +1636,17 @@ type PostStartAction struct {
 	//
 	// +optional
 	ScriptSpecSelectors []ScriptSpecSelector `json:"scriptSpecSelectors,omitempty"`
+
+	// Specifies when the post-start action is executed.
+	//
+	// - `Once`: the action is executed only once after the component first becomes ready.
+	// - `OnChange`: the action is executed again when its definition changes, including the image, command, args,
+	//   env and the contents of the referenced scripts. The action should be idempotent under this policy.
+	//   A failed re-execution is reported by the component condition and does not affect the component phase.
+	//
+	// +kubebuilder:default=Once
+	// +optional
+	PostStartPolicy PostStartPolicy `json:"postStartPolicy,omitempty"`
 }
 
 type SwitchoverSpec struct {
@@ -1001,6 +1659,26 @@ type SwitchoverSpec struct {
 	//
 	// +optional
 	WithoutCandidate *SwitchoverAction `json:"withoutCandidate,omitempty"`
+
+	// Specifies the command to be executed before the switchover, e.g. to check that the replication lag
+	// is below a threshold. If the command fails, the switchover will be aborted without being executed.
+	//
+	// +optional
+	PreCheck *CmdExecutorConfig `json:"preCheck,omitempty"`
+
+	// Specifies the command to be executed after the switchover, e.g. to check that the new primary
+	// accepts writes. If the command fails, the switchover operation will be marked as failed.
+	//
+	// +optional
+	PostCheck *CmdExecutorConfig `json:"postCheck,omitempty"`
+
+	// Defines the schema of the parameters which can be specified by the switchover OpsRequest, e.g. `force` or `timeout`.
+	// The parameters are validated against the schema, and injected into the switchover job as the env `KB_SWITCHOVER_PARAM_<NAME>`,
+	// the name is converted to uppercase and the characters '-' are replaced with '_'.
+	// The unknown parameters are rejected if the `additionalProperties` of the schema is false.
+	//
+	// +optional
+	ParametersSchema *ParametersSchema `json:"parametersSchema,omitempty"`
 }
 
 type SwitchoverAction struct {
@@ -1039,6 +1717,22 @@ type CommandExecutorEnvItem struct {
 	// +patchStrategy=merge,retainKeys
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// A list of sources to populate the environment variables of the command execution context,
+	// such as the ConfigMaps or Secrets holding the cluster-specific endpoints and credentials.
+	//
+	// The built-in variables `$(KB_CLUSTER_NAME)`, `$(KB_COMP_NAME)` and `$(KB_POD_LIST)` can be referred
+	// by the names and values of `env`, and by the prefixes and object names of `envFrom`, they are resolved
+	// when rendering the job that executes the command.
+	//
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Specifies the resource requirements of the container that executes the command.
+	// If not set, the default resources configured for the command executor will be used.
+	//
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 type CommandExecutorItem struct {
@@ -1061,28 +1755,22 @@ type CustomLabelSpec struct {
 	Key string `json:"key"`
 
 	// The value of the label.
+	// It must be a valid label value, no more than 63 characters, after the built-in variables are substituted.
 	//
 	// +kubebuilder:validation:Required
 	Value string `json:"value"`
-
-	// The resources that will be patched with the label.
-	//
-	// +kubebuilder:validation:Required
-	Resources []GVKResource `json:"resources,omitempty"`
 }
 
-type GVKResource struct {
-	// Represents the GVK of a resource, such as "v1/Pod", "apps/v1/StatefulSet", etc.
-	// When a resource matching this is found by the selector, a custom label will be added if it doesn't already exist,
-	// or updated if it does.
+type CustomAnnotationSpec struct {
+	// The key of the annotation.
 	//
 	// +kubebuilder:validation:Required
-	GVK string `json:"gvk"`
+	Key string `json:"key"`
 
-	// A label query used to filter a set of resources.
+	// The value of the annotation.
 	//
-	// +optional
-	Selector map[string]string `json:"selector,omitempty"`
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
 }
 
 // +genclient
@@ -1141,16 +1829,153 @@ func (r *ClusterDefinition) ValidateEnabledLogConfigs(compDefName string, enable
 	return invalidLogNames
 }
 
-// GetComponentDefByName gets component definition from ClusterDefinition with compDefName
+// connCredentialPlaceholders are the built-in placeholders supported in ClusterDefinition.spec.connectionCredential.
+var connCredentialPlaceholders = map[string]struct{}{
+	"RANDOM_PASSWD":        {},
+	"STRONG_RANDOM_PASSWD": {},
+	"UUID":                 {},
+	"UUID_B64":             {},
+	"UUID_STR_B64":         {},
+	"UUID_HEX":             {},
+	"SVC_FQDN":             {},
+	"HEADLESS_SVC_FQDN":    {},
+	"KB_CLUSTER_COMP_NAME": {},
+	"CONN_CREDENTIAL":      {},
+}
+
+// connCredentialPlaceholderPrefixes are the prefixes of the built-in placeholders which take a name suffix,
+// e.g. `$(SVC_PORT_{PORT-NAME})`.
+var connCredentialPlaceholderPrefixes = []string{"SVC_PORT_", "SVC_FQDN_", "HEADLESS_SVC_FQDN_"}
+
+// ValidateConnectionCredentialTemplate parses the values of the connection credential template, and returns
+// errors for the unknown, nested or unterminated `$(...)` placeholders. The `$$` is an escaped `$`, so
+// `$$(...)` is treated as a literal string.
+func ValidateConnectionCredentialTemplate(template map[string]string) []error {
+	keys := make([]string, 0, len(template))
+	for k := range template {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		for _, err := range validateConnCredentialValue(template[key]) {
+			errs = append(errs, fmt.Errorf("connectionCredential[%s]: %v", key, err))
+		}
+	}
+	return errs
+}
+
+func validateConnCredentialValue(value string) []error {
+	var errs []error
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			continue
+		}
+		if value[i+1] == '$' {
+			// skip the escaped `$`
+			i++
+			continue
+		}
+		if value[i+1] != '(' {
+			continue
+		}
+		end := strings.IndexByte(value[i+2:], ')')
+		if end < 0 {
+			errs = append(errs, fmt.Errorf("unterminated placeholder %s", value[i:]))
+			return errs
+		}
+		placeholder := value[i : i+2+end+1]
+		name := value[i+2 : i+2+end]
+		if strings.Contains(name, "$(") {
+			errs = append(errs, fmt.Errorf("nested placeholder %s is not supported", placeholder))
+		} else if !isKnownConnCredentialPlaceholder(name) {
+			errs = append(errs, fmt.Errorf("unknown placeholder %s", placeholder))
+		}
+		i += 2 + end
+	}
+	return errs
+}
+
+func isKnownConnCredentialPlaceholder(name string) bool {
+	if _, ok := connCredentialPlaceholders[name]; ok {
+		return true
+	}
+	for _, prefix := range connCredentialPlaceholderPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetComponentDefsWithoutDataVolume returns the names of stateful componentDefs which declare no
+// volume of type data, the backup of these components is not supported.
+func (r *ClusterDefinition) GetComponentDefsWithoutDataVolume() []string {
+	var compDefNames []string
+	for i := range r.Spec.ComponentDefs {
+		compDef := &r.Spec.ComponentDefs[i]
+		if compDef.IsStatelessWorkload() || compDef.HasDataVolumeType() {
+			continue
+		}
+		compDefNames = append(compDefNames, compDef.Name)
+	}
+	return compDefNames
+}
+
+// GetComponentDefByName gets component definition from ClusterDefinition with compDefName,
+// the returned pointer refers to the element of Spec.ComponentDefs, so mutations through it are kept.
 func (r *ClusterDefinition) GetComponentDefByName(compDefName string) *ClusterComponentDefinition {
-	for _, component := range r.Spec.ComponentDefs {
-		if component.Name == compDefName {
-			return &component
+	if idx := r.GetComponentDefIndexByName(compDefName); idx >= 0 {
+		return &r.Spec.ComponentDefs[idx]
+	}
+	return nil
+}
+
+// GetComponentDefIndexByName gets the index of component definition in Spec.ComponentDefs with compDefName,
+// it returns -1 if not found.
+func (r *ClusterDefinition) GetComponentDefIndexByName(compDefName string) int {
+	for i := range r.Spec.ComponentDefs {
+		if r.Spec.ComponentDefs[i].Name == compDefName {
+			return i
 		}
 	}
+	return -1
+}
+
+// SetComponentDefByName replaces the component definition in Spec.ComponentDefs which has the same name as def.
+func (r *ClusterDefinition) SetComponentDefByName(def ClusterComponentDefinition) error {
+	idx := r.GetComponentDefIndexByName(def.Name)
+	if idx < 0 {
+		return fmt.Errorf("component definition %s not found in cluster definition %s", def.Name, r.Name)
+	}
+	r.Spec.ComponentDefs[idx] = def
 	return nil
 }
 
+// GetPasswordConfig returns the password config used to generate the password of the account,
+// the account-level override is merged onto the spec-level PasswordConfig.
+func (r *SystemAccountSpec) GetPasswordConfig(account SystemAccountConfig) PasswordConfig {
+	passwdConfig := r.PasswordConfig
+	override := account.PasswordConfig
+	if override == nil {
+		return passwdConfig
+	}
+	if override.Length != nil {
+		passwdConfig.Length = *override.Length
+	}
+	if override.NumDigits != nil {
+		passwdConfig.NumDigits = *override.NumDigits
+	}
+	if override.NumSymbols != nil {
+		passwdConfig.NumSymbols = *override.NumSymbols
+	}
+	if override.LetterCase != nil {
+		passwdConfig.LetterCase = *override.LetterCase
+	}
+	return passwdConfig
+}
+
 // FailurePolicyType specifies the type of failure policy.
 //
 // +enum
@@ -1167,7 +1992,7 @@ const (
 // ComponentValueFromType specifies the type of component value from which the data is derived.
 //
 // +enum
-// +kubebuilder:validation:Enum={FieldRef,ServiceRef,HeadlessServiceRef}
+// +kubebuilder:validation:Enum={FieldRef,ServiceRef,HeadlessServiceRef,CredentialRef}
 type ComponentValueFromType string
 
 const (
@@ -1177,6 +2002,8 @@ const (
 	FromServiceRef ComponentValueFromType = "ServiceRef"
 	// FromHeadlessServiceRef refers to a headless service within the same namespace as the object.
 	FromHeadlessServiceRef ComponentValueFromType = "HeadlessServiceRef"
+	// FromCredentialRef refers to a key of the connection credential secret of the cluster.
+	FromCredentialRef ComponentValueFromType = "CredentialRef"
 )
 
 // ComponentDefRef is used to select the component and its fields to be referenced.
@@ -1186,7 +2013,10 @@ type ComponentDefRef struct {
 	// +kubebuilder:validation:Required
 	ComponentDefName string `json:"componentDefName"`
 
-	// Defines the policy to be followed in case of a failure in finding the component.
+	// Defines the policy to be followed in case of a failure in finding the component or resolving the value of an env.
+	// The policy is applied to each env individually: with `Fail`, the component is not rendered if any env fails to
+	// be resolved; with `Ignore`, the env which fails to be resolved takes its default value, and the failures are
+	// reported in the `ComponentRefEnvResolved` condition of the component.
 	//
 	// +kubebuilder:validation:Enum={Ignore,Fail}
 	// +default="Ignore"
@@ -1221,12 +2051,20 @@ type ComponentRefEnv struct {
 	//
 	// +optional
 	ValueFrom *ComponentValueFrom `json:"valueFrom,omitempty"`
+
+	// The default value of the env, it is used when the value fails to be resolved from the `valueFrom`
+	// and the failure policy of the componentDefRef is `Ignore`.
+	//
+	// +optional
+	Default string `json:"default,omitempty"`
 }
 
 type ComponentValueFrom struct {
-	// Specifies the source to select. It can be one of three types: `FieldRef`, `ServiceRef`, `HeadlessServiceRef`.
+	// Specifies the source to select. It can be one of four types: `FieldRef`, `ServiceRef`, `HeadlessServiceRef`
+	// and `CredentialRef`.
+	// The values selected by `CredentialRef` are kept in a secret of the component instead of the env configmap.
 	//
-	// +kubebuilder:validation:Enum={FieldRef,ServiceRef,HeadlessServiceRef}
+	// +kubebuilder:validation:Enum={FieldRef,ServiceRef,HeadlessServiceRef,CredentialRef}
 	// +kubebuilder:validation:Required
 	Type ComponentValueFromType `json:"type"`
 
@@ -1240,11 +2078,13 @@ type ComponentValueFrom struct {
 	FieldPath string `json:"fieldPath,omitempty"`
 
 	// Defines the format of each headless service address.
-	// Three builtin variables can be used as placeholders: `$POD_ORDINAL`, `$POD_FQDN`, `$POD_NAME`
+	// Four builtin variables can be used as placeholders: `$POD_ORDINAL`, `$POD_FQDN`, `$POD_NAME`, `$POD_IP`
 	//
 	// - `$POD_ORDINAL` represents the ordinal of the pod.
 	// - `$POD_FQDN` represents the fully qualified domain name of the pod.
 	// - `$POD_NAME` represents the name of the pod.
+	// - `$POD_IP` represents the IP of the pod, which is selected from the endpoints of the headless service
+	//   by the `preferredIPFamily`. The IPv6 address is enclosed in brackets if it is followed by a port, e.g. `$POD_IP:3306`.
 	//
 	// +kubebuilder:default=="$POD_FQDN"
 	// +optional
@@ -1255,4 +2095,18 @@ type ComponentValueFrom struct {
 	// +kubebuilder:default=","
 	// +optional
 	JoinWith string `json:"joinWith,omitempty"`
+
+	// The IP family preferred to select the pod IP of `$POD_IP` when the Type is `HeadlessServiceRef`.
+	// The IP of the other family is selected if the pod has no IP of the preferred family, e.g. on a single-stack cluster.
+	// If it is not specified, the IP family of the headless service is preferred.
+	//
+	// +kubebuilder:validation:Enum={IPv4,IPv6}
+	// +optional
+	PreferredIPFamily corev1.IPFamily `json:"preferredIPFamily,omitempty"`
+
+	// The key of the connection credential secret to select when the Type is `CredentialRef`, e.g. `password`.
+	// The connection credential secret is the one of the cluster which the referenced component belongs to.
+	//
+	// +optional
+	CredentialKey string `json:"credentialKey,omitempty"`
 }
@@ -17,12 +17,20 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
@@ -84,6 +92,691 @@ func TestGetComponentDefByName(t *testing.T) {
 	}
 }
 
+func TestMutateComponentDefByName(t *testing.T) {
+	clusterDef := &ClusterDefinition{
+		Spec: ClusterDefinitionSpec{
+			ComponentDefs: []ClusterComponentDefinition{
+				{
+					Name: "proxy",
+				},
+				{
+					Name: "mysql",
+				},
+			},
+		},
+	}
+	if idx := clusterDef.GetComponentDefIndexByName("mysql"); idx != 1 {
+		t.Errorf("expected index 1 of component def mysql, but got %d", idx)
+	}
+	if idx := clusterDef.GetComponentDefIndexByName("test"); idx != -1 {
+		t.Errorf("expected index -1 of non-existent component def, but got %d", idx)
+	}
+
+	// mutations through the returned pointer are persisted in the parent object
+	clusterDef.GetComponentDefByName("mysql").CharacterType = "mysql"
+	if clusterDef.Spec.ComponentDefs[1].CharacterType != "mysql" {
+		t.Error("mutation through the pointer returned by GetComponentDefByName should be persisted")
+	}
+
+	compDef := *clusterDef.GetComponentDefByName("proxy")
+	compDef.WorkloadType = Stateless
+	if err := clusterDef.SetComponentDefByName(compDef); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if clusterDef.Spec.ComponentDefs[0].WorkloadType != Stateless {
+		t.Error("component def proxy should be replaced by SetComponentDefByName")
+	}
+	if err := clusterDef.SetComponentDefByName(ClusterComponentDefinition{Name: "test"}); err == nil {
+		t.Error("SetComponentDefByName should fail for a non-existent component def")
+	}
+	if len(clusterDef.Spec.ComponentDefs) != 2 {
+		t.Error("SetComponentDefByName should not add component defs")
+	}
+}
+
+func TestGetPasswordConfig(t *testing.T) {
+	length, numSymbols := int32(20), int32(0)
+	lowerCases := LowerCases
+	sysAccountSpec := &SystemAccountSpec{
+		PasswordConfig: PasswordConfig{
+			Length:     32,
+			NumDigits:  4,
+			NumSymbols: 4,
+			LetterCase: MixedCases,
+		},
+		Accounts: []SystemAccountConfig{
+			{
+				Name: AdminAccount,
+			},
+			{
+				Name: MonitorAccount,
+				PasswordConfig: &PasswordConfigOverride{
+					Length:     &length,
+					NumSymbols: &numSymbols,
+					LetterCase: &lowerCases,
+				},
+			},
+		},
+	}
+	if passwdConfig := sysAccountSpec.GetPasswordConfig(sysAccountSpec.Accounts[0]); passwdConfig != sysAccountSpec.PasswordConfig {
+		t.Errorf("expected spec-level password config, got %v", passwdConfig)
+	}
+	expected := PasswordConfig{
+		Length:     20,
+		NumDigits:  4,
+		NumSymbols: 0,
+		LetterCase: LowerCases,
+	}
+	if passwdConfig := sysAccountSpec.GetPasswordConfig(sysAccountSpec.Accounts[1]); passwdConfig != expected {
+		t.Errorf("expected merged password config %v, got %v", expected, passwdConfig)
+	}
+}
+
+func TestValidateConnectionCredentialPorts(t *testing.T) {
+	newCompDef := func(name string, ports ...ServicePort) ClusterComponentDefinition {
+		return ClusterComponentDefinition{
+			Name:    name,
+			Service: &ServiceSpec{Ports: ports},
+		}
+	}
+	clusterDef := &ClusterDefinition{
+		Spec: ClusterDefinitionSpec{
+			ComponentDefs: []ClusterComponentDefinition{
+				newCompDef("mysql", ServicePort{Name: "mysql", Port: 3306}, ServicePort{Name: "paxos", Port: 13306}),
+				newCompDef("proxy", ServicePort{Name: "mysql", Port: 3306}, ServicePort{Name: "admin", Port: 8080}),
+				{Name: "sidecar"},
+			},
+			ConnectionCredential: map[string]string{
+				"username":      "root",
+				"tcpEndpoint":   "tcp:$(SVC_FQDN):$(SVC_PORT_mysql)",
+				"paxosEndpoint": "paxos:$(SVC_FQDN):$(SVC_PORT_paxos)",
+			},
+		},
+	}
+
+	var allErrs field.ErrorList
+	clusterDef.validateConnectionCredentialPorts(&allErrs)
+	if len(allErrs) != 0 {
+		t.Errorf("expected no errors, got: %v", allErrs)
+	}
+
+	// missing port
+	clusterDef.Spec.ConnectionCredential["metricsEndpoint"] = "$(SVC_FQDN):$(SVC_PORT_metrics)"
+	allErrs = nil
+	clusterDef.validateConnectionCredentialPorts(&allErrs)
+	if len(allErrs) != 1 || !strings.Contains(allErrs[0].Error(), "$(SVC_PORT_metrics)") ||
+		!strings.Contains(allErrs[0].Error(), "not declared") {
+		t.Errorf("expected missing port error, got: %v", allErrs)
+	}
+	delete(clusterDef.Spec.ConnectionCredential, "metricsEndpoint")
+
+	// duplicate port with different values
+	clusterDef.Spec.ComponentDefs[1].Service.Ports[1] = ServicePort{Name: "paxos", Port: 23306}
+	allErrs = nil
+	clusterDef.validateConnectionCredentialPorts(&allErrs)
+	if len(allErrs) != 1 {
+		t.Fatalf("expected ambiguous port error, got: %v", allErrs)
+	}
+	errMsg := allErrs[0].Error()
+	for _, s := range []string{"$(SVC_PORT_paxos)", "ambiguous", "13306(mysql)", "23306(proxy)"} {
+		if !strings.Contains(errMsg, s) {
+			t.Errorf("expected error message to contain %q, got: %s", s, errMsg)
+		}
+	}
+}
+
+func TestValidateConnectionCredentialTemplate(t *testing.T) {
+	template := map[string]string{
+		"username":      "root",
+		"password":      "$(RANDOM_PASSWD)",
+		"strongPasswd":  "$(STRONG_RANDOM_PASSWD)",
+		"uuid":          "$(UUID)-$(UUID_B64)-$(UUID_STR_B64)-$(UUID_HEX)",
+		"endpoint":      "$(SVC_FQDN):$(SVC_PORT_mysql)",
+		"proxyEndpoint": "$(HEADLESS_SVC_FQDN_proxy):$(SVC_PORT_proxy)",
+		"host":          "$(HEADLESS_SVC_FQDN)",
+		"compName":      "$(KB_CLUSTER_COMP_NAME)",
+		"url":           "mysql://$(CONN_CREDENTIAL).username@$(SVC_FQDN_mysql)",
+		"escaped":       "$$(NOT_A_PLACEHOLDER) costs $5",
+	}
+	if errs := ValidateConnectionCredentialTemplate(template); len(errs) != 0 {
+		t.Errorf("expected no errors, got: %v", errs)
+	}
+
+	tests := []struct {
+		value  string
+		expect string
+	}{
+		{value: "$(RANDOM_PASSWORD)", expect: "unknown placeholder $(RANDOM_PASSWORD)"},
+		{value: "$(SVC_PORT_)", expect: "unknown placeholder $(SVC_PORT_)"},
+		{value: "$(SVC_FQDN_$(COMP_NAME))", expect: "nested placeholder $(SVC_FQDN_$(COMP_NAME) is not supported"},
+		{value: "$$$(UUID_HEXX)", expect: "unknown placeholder $(UUID_HEXX)"},
+		{value: "$(SVC_FQDN):$(SVC_PORT_mysql", expect: "unterminated placeholder $(SVC_PORT_mysql"},
+	}
+	for _, tt := range tests {
+		errs := ValidateConnectionCredentialTemplate(map[string]string{"key": tt.value})
+		if len(errs) != 1 {
+			t.Errorf("expected one error for %q, got: %v", tt.value, errs)
+			continue
+		}
+		if errs[0].Error() != "connectionCredential[key]: "+tt.expect {
+			t.Errorf("expected error %q for %q, got: %s", tt.expect, tt.value, errs[0].Error())
+		}
+	}
+
+	// the unknown placeholders are reported as warnings by default
+	clusterDef := &ClusterDefinition{
+		Spec: ClusterDefinitionSpec{
+			ConnectionCredential: map[string]string{"password": "$(RANDOM_PASSWORD)"},
+		},
+	}
+	if warnings := clusterDef.warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "$(RANDOM_PASSWORD)") {
+		t.Errorf("expected one warning, got: %v", warnings)
+	}
+	var allErrs field.ErrorList
+	clusterDef.validateConnectionCredentialPlaceholders(&allErrs)
+	if len(allErrs) != 1 || !strings.Contains(allErrs[0].Error(), "unknown placeholder $(RANDOM_PASSWORD)") {
+		t.Errorf("expected unknown placeholder error, got: %v", allErrs)
+	}
+}
+
+func TestValidateVolumeTypes(t *testing.T) {
+	compDef := &ClusterComponentDefinition{
+		Name:         "mysql",
+		WorkloadType: Consensus,
+		PodSpec: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "mysql", VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}},
+				{Name: "metrics", VolumeMounts: []corev1.VolumeMount{{Name: "log", MountPath: "/log"}}},
+			},
+		},
+		VolumeTypes: []VolumeTypeSpec{
+			{Name: "data", Type: VolumeTypeData},
+			{Name: "log", Type: VolumeTypeLog},
+		},
+	}
+	if err := compDef.ValidateVolumeTypes(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if !compDef.HasDataVolumeType() {
+		t.Error("expected data volume type to be declared")
+	}
+
+	// dangling volume name
+	compDef.VolumeTypes = append(compDef.VolumeTypes, VolumeTypeSpec{Name: "binlog", Type: VolumeTypeLog})
+	err := compDef.ValidateVolumeTypes()
+	if err == nil {
+		t.Fatal("expected error for dangling volume name")
+	}
+	for _, s := range []string{"[binlog]", "containers searched: [mysql,metrics]"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error message to contain %q, got: %s", s, err.Error())
+		}
+	}
+
+	// data volume missing
+	clusterDef := &ClusterDefinition{
+		Spec: ClusterDefinitionSpec{
+			ComponentDefs: []ClusterComponentDefinition{
+				{Name: "mysql", WorkloadType: Consensus, VolumeTypes: []VolumeTypeSpec{{Name: "data", Type: VolumeTypeData}}},
+				{Name: "proxy", WorkloadType: Stateless},
+				{Name: "redis", WorkloadType: Replication, VolumeTypes: []VolumeTypeSpec{{Name: "log", Type: VolumeTypeLog}}},
+			},
+		},
+	}
+	if names := clusterDef.GetComponentDefsWithoutDataVolume(); len(names) != 1 || names[0] != "redis" {
+		t.Errorf("expected [redis] without data volume, got: %v", names)
+	}
+	if warnings := clusterDef.warnDataVolumeMissing(); len(warnings) != 1 {
+		t.Errorf("expected one warning, got: %v", warnings)
+	}
+}
+
+func TestValidateImmutableFields(t *testing.T) {
+	newClusterDef := func(clusterType, seed, symbols string, accounts ...AccountName) *ClusterDefinition {
+		clusterDef := &ClusterDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql"},
+			Spec: ClusterDefinitionSpec{
+				Type: clusterType,
+				ComponentDefs: []ClusterComponentDefinition{{
+					Name:          "mysql",
+					WorkloadType:  Stateful,
+					CharacterType: "mysql",
+					SystemAccounts: &SystemAccountSpec{
+						PasswordConfig: PasswordConfig{Length: 16, NumSymbols: 2, Seed: seed, SymbolCharacters: symbols},
+					},
+				}},
+			},
+		}
+		for _, name := range accounts {
+			clusterDef.Spec.ComponentDefs[0].SystemAccounts.Accounts = append(clusterDef.Spec.ComponentDefs[0].SystemAccounts.Accounts,
+				SystemAccountConfig{Name: name, ProvisionPolicy: ProvisionPolicy{
+					Type:       CreateByStmt,
+					Statements: &ProvisionStatements{CreationStatement: "CREATE USER $(USERNAME)", DeletionStatement: "DROP USER $(USERNAME)"},
+				}})
+		}
+		return clusterDef
+	}
+	oldClusterDef := newClusterDef("mysql", "seed", "_-", AdminAccount)
+	if _, err := oldClusterDef.ValidateCreate(); err != nil {
+		t.Fatalf("expected no error on create, got: %v", err)
+	}
+
+	clusterDef := newClusterDef("mysql", "seed", "_-", AdminAccount, DataprotectionAccount)
+	clusterDef.Spec.ComponentDefs[0].SystemAccounts.PasswordConfig.Length = 20
+	if _, err := clusterDef.ValidateUpdate(oldClusterDef); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	// the accounts are matched by name
+	clusterDef = newClusterDef("mysql", "seed", "_-", DataprotectionAccount, AdminAccount)
+	if _, err := clusterDef.ValidateUpdate(oldClusterDef); err != nil {
+		t.Errorf("expected no error when accounts are reordered, got: %v", err)
+	}
+
+	// the type can be set if it is empty
+	if err := newClusterDef("mysql", "seed", "_-").validateImmutableFields(newClusterDef("", "seed", "_-")); err != nil {
+		t.Errorf("expected no error when type is set, got: %v", err)
+	}
+
+	clusterDef = newClusterDef("postgresql", "new-seed", "_", ProbeAccount)
+	_, err := clusterDef.ValidateUpdate(oldClusterDef)
+	if err == nil {
+		t.Fatal("expected error when immutable fields are updated")
+	}
+	for _, s := range []string{
+		`spec.type: Forbidden: type is immutable once set, cannot be updated from "mysql" to "postgresql"`,
+		`spec.componentDefs[0].systemAccounts.passwordConfig.seed: Forbidden: seed is immutable, cannot be updated from "seed" to "new-seed"`,
+		`symbolCharacters is immutable, cannot be updated from "_-" to "_"`,
+		`spec.componentDefs[0].systemAccounts.accounts: Forbidden: account "kbadmin" has been provisioned, cannot be renamed or removed`,
+	} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error message to contain %q, got: %s", s, err.Error())
+		}
+	}
+}
+
+func TestLogConfigValidate(t *testing.T) {
+	logConfig := &LogConfig{
+		Name:             "slow",
+		FilePathPattern:  "/data/mysql/log/mysqld-slowquery.log",
+		Format:           RegexLogFormat,
+		TimeKey:          "time",
+		TimeFormat:       "%Y-%m-%dT%H:%M:%S.%L%z",
+		MultilinePattern: `^# Time:`,
+	}
+	if err := logConfig.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	// plain tail without parsing hints
+	if err := (&LogConfig{Name: "error", FilePathPattern: "/data/mysql/log/mysqld.err"}).Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	logConfig.MultilinePattern = `^# Time:(`
+	if err := logConfig.Validate(); err == nil || !strings.Contains(err.Error(), "multilinePattern") {
+		t.Errorf("expected invalid multilinePattern error, got: %v", err)
+	}
+
+	logConfig.MultilinePattern = ""
+	logConfig.TimeKey = ""
+	if err := logConfig.Validate(); err == nil || !strings.Contains(err.Error(), "timeKey") {
+		t.Errorf("expected timeKey required error, got: %v", err)
+	}
+}
+
+func TestExporterConfigValidate(t *testing.T) {
+	exporter := &ExporterConfig{}
+	if exporter.GetScrapeScheme() != HTTPScrapeScheme {
+		t.Errorf("expected default scrape scheme %s, got %s", HTTPScrapeScheme, exporter.GetScrapeScheme())
+	}
+	if err := exporter.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	exporter.TLSConfig = &ExporterTLSConfig{InsecureSkipVerify: true}
+	if err := exporter.Validate(); err == nil {
+		t.Error("expected error when tlsConfig is set with default http scheme")
+	}
+	exporter.ScrapeScheme = HTTPScrapeScheme
+	if err := exporter.Validate(); err == nil {
+		t.Error("expected error when tlsConfig is set with http scheme")
+	}
+	exporter.ScrapeScheme = HTTPSScrapeScheme
+	if err := exporter.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
+
+func TestClusterDefinitionProbeValidate(t *testing.T) {
+	probe := &ClusterDefinitionProbe{}
+	if err := probe.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	probe.Commands = &ClusterDefinitionProbeCMDs{Queries: []string{"select 1"}}
+	if err := probe.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	probe.HTTPGet = &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8080)}
+	if err := probe.Validate(); err == nil {
+		t.Error("expected error when both commands and httpGet are set")
+	}
+
+	probe.Commands = nil
+	if err := probe.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	probe.GRPC = &corev1.GRPCAction{Port: 9090}
+	if err := probe.Validate(); err == nil {
+		t.Error("expected error when both httpGet and grpc are set")
+	}
+
+	probe = &ClusterDefinitionProbe{Commands: &ClusterDefinitionProbeCMDs{}}
+	if err := probe.Validate(); err == nil {
+		t.Error("expected error when both commands.queries and commands.writes are empty")
+	}
+
+	probe = &ClusterDefinitionProbe{PeriodSeconds: 2, TimeoutSeconds: 2}
+	if err := probe.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	probe.TimeoutSeconds = 3
+	if err := probe.Validate(); err == nil {
+		t.Error("expected error when timeoutSeconds is greater than periodSeconds")
+	}
+}
+
+func TestClusterDefinitionProbesSetDefaults(t *testing.T) {
+	probes := &ClusterDefinitionProbes{RoleProbe: &ClusterDefinitionProbe{}}
+	probes.SetDefaults()
+	if probes.RoleProbeTimeoutAfterPodsReady != DefaultRoleProbeTimeoutAfterPodsReady {
+		t.Errorf("expected the default role probe timeout, got: %d", probes.RoleProbeTimeoutAfterPodsReady)
+	}
+
+	probes.RoleProbeTimeoutAfterPodsReady = 120
+	probes.SetDefaults()
+	if probes.RoleProbeTimeoutAfterPodsReady != 120 {
+		t.Errorf("expected the role probe timeout to be kept, got: %d", probes.RoleProbeTimeoutAfterPodsReady)
+	}
+
+	probes.RoleProbe = nil
+	probes.SetDefaults()
+	if probes.RoleProbeTimeoutAfterPodsReady != 0 {
+		t.Errorf("expected the role probe timeout to be reset, got: %d", probes.RoleProbeTimeoutAfterPodsReady)
+	}
+}
+
+func TestResolveWorkloadUpdateStrategy(t *testing.T) {
+	zeroPartition := int32(0)
+	serialMaxUnavailable := intstr.FromInt(1)
+	bestEffortParallelMaxUnavailable := intstr.FromString("49%")
+	rollingUpdate := func(maxUnavailable *intstr.IntOrString) appsv1.StatefulSetUpdateStrategy {
+		return appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition:      &zeroPartition,
+				MaxUnavailable: maxUnavailable,
+			},
+		}
+	}
+	llStrategy := appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	onDelete := appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+
+	type expectation struct {
+		policy   appsv1.PodManagementPolicyType
+		strategy appsv1.StatefulSetUpdateStrategy
+	}
+	// the expectations of the stateful workload, keyed by the update strategy.
+	statefulExpectations := map[UpdateStrategy]expectation{
+		"":                         {appsv1.OrderedReadyPodManagement, rollingUpdate(&serialMaxUnavailable)},
+		SerialStrategy:             {appsv1.OrderedReadyPodManagement, rollingUpdate(&serialMaxUnavailable)},
+		BestEffortParallelStrategy: {appsv1.ParallelPodManagement, rollingUpdate(&bestEffortParallelMaxUnavailable)},
+		ParallelStrategy:           {appsv1.ParallelPodManagement, appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}},
+	}
+
+	newCompDef := func(workloadType WorkloadType, spec *StatefulSetSpec) *ClusterComponentDefinition {
+		compDef := &ClusterComponentDefinition{WorkloadType: workloadType}
+		if spec == nil {
+			return compDef
+		}
+		switch workloadType {
+		case Stateful:
+			compDef.StatefulSpec = spec
+		case Consensus:
+			compDef.ConsensusSpec = &ConsensusSetSpec{StatefulSetSpec: *spec, Leader: DefaultLeader}
+		case Replication:
+			compDef.ReplicationSpec = &ReplicationSetSpec{StatefulSetSpec: *spec}
+		}
+		return compDef
+	}
+
+	type testCase struct {
+		name     string
+		compDef  *ClusterComponentDefinition
+		override *UpdateStrategy
+		expected expectation
+	}
+	var tests []testCase
+	for _, workloadType := range []WorkloadType{Stateful, Consensus, Replication} {
+		for _, updateStrategy := range []UpdateStrategy{"", SerialStrategy, BestEffortParallelStrategy, ParallelStrategy} {
+			for _, withLL := range []bool{false, true} {
+				spec := &StatefulSetSpec{UpdateStrategy: updateStrategy}
+				expected := statefulExpectations[updateStrategy]
+				if workloadType != Stateful {
+					expected = expectation{appsv1.ParallelPodManagement, onDelete}
+				}
+				if withLL {
+					spec.LLPodManagementPolicy = appsv1.OrderedReadyPodManagement
+					spec.LLUpdateStrategy = &llStrategy
+					expected = expectation{appsv1.OrderedReadyPodManagement, llStrategy}
+				}
+				tests = append(tests, testCase{
+					name:     fmt.Sprintf("%s/%s/ll=%v", workloadType, updateStrategy, withLL),
+					compDef:  newCompDef(workloadType, spec),
+					expected: expected,
+				})
+
+				// the update strategy overridden in the cluster takes precedence unless the ll strategy is provided
+				override := ParallelStrategy
+				expected = statefulExpectations[override]
+				if workloadType != Stateful {
+					expected = expectation{appsv1.ParallelPodManagement, onDelete}
+				}
+				if withLL {
+					expected = expectation{appsv1.OrderedReadyPodManagement, llStrategy}
+				}
+				tests = append(tests, testCase{
+					name:     fmt.Sprintf("%s/%s/ll=%v/override=%s", workloadType, updateStrategy, withLL, override),
+					compDef:  newCompDef(workloadType, spec),
+					override: &override,
+					expected: expected,
+				})
+			}
+		}
+		// the workload spec is not set
+		expected := statefulExpectations[SerialStrategy]
+		if workloadType != Stateful {
+			expected = expectation{appsv1.ParallelPodManagement, onDelete}
+		}
+		tests = append(tests, testCase{
+			name:     fmt.Sprintf("%s/nil spec", workloadType),
+			compDef:  newCompDef(workloadType, nil),
+			expected: expected,
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, strategy, err := ResolveWorkloadUpdateStrategy(tt.compDef, tt.override)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if policy != tt.expected.policy {
+				t.Errorf("expected pod management policy %s, got: %s", tt.expected.policy, policy)
+			}
+			if !reflect.DeepEqual(strategy, tt.expected.strategy) {
+				t.Errorf("expected update strategy %+v, got: %+v", tt.expected.strategy, strategy)
+			}
+			// the methods of the workload specs are consistent with it if not overridden
+			if tt.override == nil {
+				policy, strategy = tt.compDef.GetStatefulSetWorkload().FinalStsUpdateStrategy()
+				if policy != tt.expected.policy || !reflect.DeepEqual(strategy, tt.expected.strategy) {
+					t.Errorf("expected FinalStsUpdateStrategy to return %+v, got: %s, %+v", tt.expected, policy, strategy)
+				}
+			}
+		})
+	}
+
+	for _, compDef := range []*ClusterComponentDefinition{nil, {WorkloadType: Stateless}, {WorkloadType: "unknown"}} {
+		if _, _, err := ResolveWorkloadUpdateStrategy(compDef, nil); err == nil {
+			t.Errorf("expected error for the component definition: %+v", compDef)
+		}
+	}
+}
+
+func TestReplicationSetSpecFinalMaxUnavailable(t *testing.T) {
+	intValue := func(v int) *intstr.IntOrString {
+		value := intstr.FromInt(v)
+		return &value
+	}
+	percentValue := func(v string) *intstr.IntOrString {
+		value := intstr.FromString(v)
+		return &value
+	}
+	tests := []struct {
+		name           string
+		spec           *ReplicationSetSpec
+		replicas       int32
+		maxUnavailable int32
+	}{
+		{"nil spec with 2 replicas", nil, 2, 1},
+		{"default with 2 replicas", &ReplicationSetSpec{}, 2, 1},
+		{"default with 3 replicas", &ReplicationSetSpec{}, 3, 1},
+		{"default with 5 replicas", &ReplicationSetSpec{}, 5, 3},
+		{"override with 2 replicas", &ReplicationSetSpec{MaxUnavailable: intValue(2)}, 2, 1},
+		{"override with 3 replicas", &ReplicationSetSpec{MaxUnavailable: intValue(2)}, 3, 2},
+		{"override with 5 replicas", &ReplicationSetSpec{MaxUnavailable: intValue(4)}, 5, 4},
+		{"override exceeds the secondaries", &ReplicationSetSpec{MaxUnavailable: intValue(5)}, 5, 4},
+		{"percentage with 2 replicas", &ReplicationSetSpec{MaxUnavailable: percentValue("50%")}, 2, 1},
+		{"percentage with 3 replicas", &ReplicationSetSpec{MaxUnavailable: percentValue("50%")}, 3, 1},
+		{"percentage with 5 replicas", &ReplicationSetSpec{MaxUnavailable: percentValue("50%")}, 5, 2},
+		{"percentage rounds down to 0", &ReplicationSetSpec{MaxUnavailable: percentValue("10%")}, 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxUnavailable, err := tt.spec.FinalMaxUnavailable(tt.replicas)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if maxUnavailable != tt.maxUnavailable {
+				t.Errorf("expected maxUnavailable %d, got: %d", tt.maxUnavailable, maxUnavailable)
+			}
+		})
+	}
+
+	spec := &ReplicationSetSpec{MaxUnavailable: percentValue("invalid")}
+	if _, err := spec.FinalMaxUnavailable(3); err == nil {
+		t.Errorf("expected error for the invalid maxUnavailable")
+	}
+}
+
+func TestServiceRefDeclarationValidate(t *testing.T) {
+	decl := &ServiceRefDeclaration{Name: "metrics"}
+	if err := decl.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	optional := false
+	decl.DefaultEndpoint = &ServiceRefDefaultEndpoint{Host: "metrics.default.svc", Port: 9090}
+	if err := decl.Validate(); err == nil {
+		t.Error("expected error when defaultEndpoint is set without optional")
+	}
+	decl.Optional = &optional
+	if err := decl.Validate(); err == nil {
+		t.Error("expected error when defaultEndpoint is set for a required service reference")
+	}
+
+	optional = true
+	if err := decl.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	decl.ServiceRefDeclarationSpecs = []ServiceRefDeclarationSpec{{ServiceKind: "mysql", ServiceVersion: `^8.0.\d{1,2}$`}}
+	if err := decl.Validate(); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	decl.ServiceRefDeclarationSpecs = append(decl.ServiceRefDeclarationSpecs, ServiceRefDeclarationSpec{ServiceKind: "mysql", ServiceVersion: "[8.0"})
+	err := decl.Validate()
+	if err == nil {
+		t.Fatal("expected error for the malformed serviceVersion")
+	}
+	if !strings.Contains(err.Error(), "metrics") || !strings.Contains(err.Error(), "missing closing ]") {
+		t.Errorf("expected the declaration name and the compile error, got: %v", err)
+	}
+}
+
+func TestMatchServiceVersion(t *testing.T) {
+	cases := []struct {
+		pattern string
+		version string
+		match   bool
+		wantErr bool
+	}{
+		{pattern: `^8.0.8$`, version: "8.0.8", match: true},
+		{pattern: `^8.0.8$`, version: "8.0.80", match: false},
+		{pattern: `^8.0`, version: "v8.0.30", match: false},
+		{pattern: `8.0`, version: "8.0.30", match: true},
+		{pattern: `8.0`, version: "v8.0.30", match: true},
+		{pattern: `8.0.\d{1,2}$`, version: "8.0.30", match: true},
+		{pattern: `^[v\-]*?(\d{1,2}\.){0,3}\d{1,2}$`, version: "v8.0.30", match: true},
+		{pattern: `[8.0`, version: "8.0", wantErr: true},
+	}
+	for _, c := range cases {
+		match, err := MatchServiceVersion(c.pattern, c.version)
+		if (err != nil) != c.wantErr {
+			t.Errorf("pattern %q: unexpected error: %v", c.pattern, err)
+			continue
+		}
+		if match != c.match {
+			t.Errorf("pattern %q, version %q: expected match %v, got %v", c.pattern, c.version, c.match, match)
+		}
+	}
+}
+
+func TestScheduledRestartPolicyValidate(t *testing.T) {
+	policy := &ScheduledRestartPolicy{Schedule: "0 3 * * 0"}
+	var allErrs field.ErrorList
+	policy.validate(&allErrs)
+	if len(allErrs) != 0 {
+		t.Errorf("expected no error, got: %v", allErrs)
+	}
+
+	policy.Schedule = "0 3 * *"
+	policy.validate(&allErrs)
+	if len(allErrs) != 1 {
+		t.Errorf("expected error for the invalid schedule, got: %v", allErrs)
+	}
+
+	allErrs = nil
+	policy.Schedule = "CRON_TZ=Asia/Shanghai 0 3 * * 0"
+	policy.MaintenanceWindow = &MaintenanceWindow{StartTime: "02:00", Duration: metav1.Duration{Duration: 25 * time.Hour}}
+	policy.validate(&allErrs)
+	if len(allErrs) != 1 {
+		t.Errorf("expected error for the maintenance window longer than 24h, got: %v", allErrs)
+	}
+
+	allErrs = nil
+	policy.MaintenanceWindow.Duration.Duration = 2 * time.Hour
+	policy.validate(&allErrs)
+	if len(allErrs) != 0 {
+		t.Errorf("expected no error, got: %v", allErrs)
+	}
+}
+
 var _ = Describe("", func() {
 
 	It("test GetTerminalPhases", func() {
@@ -186,3 +879,31 @@ var _ = Describe("", func() {
 		Expect(strategy.Type).Should(BeEquivalentTo(appsv1.OnDeleteStatefulSetStrategyType))
 	})
 })
+
+func TestToHeadlessSVCSpec(t *testing.T) {
+	svc := ServiceSpec{Ports: []ServicePort{{Name: "mysql", Port: 3306}}}
+	spec := svc.ToHeadlessSVCSpec()
+	if spec.ClusterIP != corev1.ClusterIPNone || !spec.PublishNotReadyAddresses || len(spec.Ports) != 1 {
+		t.Errorf("unexpected default headless service spec: %v", spec)
+	}
+	if suffix := svc.GetHeadlessNameSuffix(); suffix != "" {
+		t.Errorf("expected no name suffix, got: %s", suffix)
+	}
+
+	publishNotReadyAddresses := false
+	svc.Headless = &HeadlessServiceSpec{
+		PublishNotReadyAddresses: &publishNotReadyAddresses,
+		SessionAffinity:          corev1.ServiceAffinityClientIP,
+		NameSuffix:               "hs",
+	}
+	spec = svc.ToHeadlessSVCSpec()
+	if spec.PublishNotReadyAddresses || spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		t.Errorf("expected the customized headless service spec, got: %v", spec)
+	}
+	if suffix := svc.GetHeadlessNameSuffix(); suffix != "hs" {
+		t.Errorf("expected the name suffix hs, got: %s", suffix)
+	}
+	if suffix := (*ServiceSpec)(nil).GetHeadlessNameSuffix(); suffix != "" {
+		t.Errorf("expected no name suffix of nil service, got: %s", suffix)
+	}
+}
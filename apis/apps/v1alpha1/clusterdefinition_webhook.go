@@ -17,18 +17,28 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
 // log is for logging in this package.
@@ -36,6 +46,9 @@ var (
 	clusterdefinitionlog = logf.Log.WithName("clusterdefinition-resource")
 )
 
+// svcPortPlaceholderRegex matches the `$(SVC_PORT_{PORT-NAME})` placeholders in connectionCredential.
+var svcPortPlaceholderRegex = regexp.MustCompile(`\$\(SVC_PORT_([^)]+)\)`)
+
 // DefaultRoleProbeTimeoutAfterPodsReady the default role probe timeout for application when all pods of component are ready.
 // default values are 60 seconds.
 const DefaultRoleProbeTimeoutAfterPodsReady int32 = 60
@@ -54,26 +67,20 @@ var _ webhook.Defaulter = &ClusterDefinition{}
 func (r *ClusterDefinition) Default() {
 	clusterdefinitionlog.Info("default", "name", r.Name)
 	for i := range r.Spec.ComponentDefs {
-		probes := r.Spec.ComponentDefs[i].Probes
-		if probes == nil {
-			continue
-		}
-		if probes.RoleProbe != nil {
-			// set default values
-			if probes.RoleProbeTimeoutAfterPodsReady == 0 {
-				probes.RoleProbeTimeoutAfterPodsReady = DefaultRoleProbeTimeoutAfterPodsReady
-			}
-		} else {
-			// if component does not support RoleProbe, reset RoleProbeTimeoutAtPodsReady to zero
-			if probes.RoleProbeTimeoutAfterPodsReady != 0 {
-				probes.RoleProbeTimeoutAfterPodsReady = 0
-			}
+		if probes := r.Spec.ComponentDefs[i].Probes; probes != nil {
+			probes.SetDefaults()
 		}
 		// set to CloneVolume if deprecated value used
 		if r.Spec.ComponentDefs[i].HorizontalScalePolicy != nil &&
 			r.Spec.ComponentDefs[i].HorizontalScalePolicy.Type == HScaleDataClonePolicyFromSnapshot {
 			r.Spec.ComponentDefs[i].HorizontalScalePolicy.Type = HScaleDataClonePolicyCloneVolume
 		}
+		// set to Serial if roles declared but member update strategy not specified
+		rsmSpec := r.Spec.ComponentDefs[i].RSMSpec
+		if rsmSpec != nil && len(rsmSpec.Roles) > 0 && rsmSpec.MemberUpdateStrategy == nil {
+			strategy := workloads.SerialUpdateStrategy
+			rsmSpec.MemberUpdateStrategy = &strategy
+		}
 	}
 }
 
@@ -85,13 +92,20 @@ var _ webhook.Validator = &ClusterDefinition{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *ClusterDefinition) ValidateCreate() (admission.Warnings, error) {
 	clusterdefinitionlog.Info("validate create", "name", r.Name)
-	return nil, r.validate()
+	return r.warnings(), r.validate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ClusterDefinition) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	clusterdefinitionlog.Info("validate update", "name", r.Name)
-	return nil, r.validate()
+	if err := r.validateImmutableFields(old.(*ClusterDefinition)); err != nil {
+		return nil, err
+	}
+	changeWarnings, err := r.validateChanges(old.(*ClusterDefinition))
+	if err != nil {
+		return nil, err
+	}
+	return append(changeWarnings, r.warnings()...), r.validate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -113,6 +127,12 @@ func (r *ClusterDefinition) validate() error {
 
 	r.validateComponents(&allErrs)
 	r.validateLogFilePatternPrefix(&allErrs)
+	r.validateConnectionCredentialPorts(&allErrs)
+	r.validateVolumeTypes(&allErrs)
+	r.validateServiceRefDeclarations(&allErrs)
+	if viper.GetBool(constant.CfgKeyRejectUnknownConnCredentialPlaceholders) {
+		r.validateConnectionCredentialPlaceholders(&allErrs)
+	}
 
 	if len(allErrs) > 0 {
 		return apierrors.NewInvalid(
@@ -122,6 +142,162 @@ func (r *ClusterDefinition) validate() error {
 	return nil
 }
 
+// validateVolumeTypes validates spec.componentDefs[*].volumeTypes, each volume name must match a volumeMount
+// of the podSpec containers.
+func (r *ClusterDefinition) validateVolumeTypes(allErrs *field.ErrorList) {
+	for i := range r.Spec.ComponentDefs {
+		compDef := &r.Spec.ComponentDefs[i]
+		if err := compDef.ValidateVolumeTypes(); err != nil {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath("spec.componentDefs").Index(i).Child("volumeTypes"),
+				compDef.VolumeTypes, err.Error()))
+		}
+	}
+}
+
+// validateServiceRefDeclarations validates spec.componentDefs[*].serviceRefDeclarations.
+func (r *ClusterDefinition) validateServiceRefDeclarations(allErrs *field.ErrorList) {
+	for i := range r.Spec.ComponentDefs {
+		compDef := &r.Spec.ComponentDefs[i]
+		for j := range compDef.ServiceRefDeclarations {
+			if err := compDef.ServiceRefDeclarations[j].Validate(); err != nil {
+				*allErrs = append(*allErrs, field.Invalid(field.NewPath("spec.componentDefs").Index(i).
+					Child("serviceRefDeclarations").Index(j), compDef.ServiceRefDeclarations[j].Name, err.Error()))
+			}
+		}
+	}
+}
+
+// warnings returns the warnings of the ClusterDefinition, which do not reject the request.
+func (r *ClusterDefinition) warnings() admission.Warnings {
+	warnings := r.warnDataVolumeMissing()
+	if !viper.GetBool(constant.CfgKeyRejectUnknownConnCredentialPlaceholders) {
+		for _, err := range ValidateConnectionCredentialTemplate(r.Spec.ConnectionCredential) {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	return warnings
+}
+
+// validateConnectionCredentialPlaceholders validates the `$(...)` placeholders in spec.connectionCredential,
+// it is only enforced if CfgKeyRejectUnknownConnCredentialPlaceholders is enabled, otherwise the invalid
+// placeholders are reported as warnings.
+func (r *ClusterDefinition) validateConnectionCredentialPlaceholders(allErrs *field.ErrorList) {
+	for _, err := range ValidateConnectionCredentialTemplate(r.Spec.ConnectionCredential) {
+		*allErrs = append(*allErrs, field.Invalid(field.NewPath("spec.connectionCredential"), field.OmitValueType{}, err.Error()))
+	}
+}
+
+// warnDataVolumeMissing returns warnings for the stateful componentDefs which declare no data volume,
+// it does not reject the request, since the backup is silently disabled for these components only.
+func (r *ClusterDefinition) warnDataVolumeMissing() admission.Warnings {
+	var warnings admission.Warnings
+	for _, compDefName := range r.GetComponentDefsWithoutDataVolume() {
+		warnings = append(warnings, fmt.Sprintf("componentDef %s declares no volume of type data in volumeTypes, the backup will not be supported", compDefName))
+	}
+	return warnings
+}
+
+// validateImmutableFields validates that spec.type and the fields determining the system accounts
+// and their passwords are not updated.
+func (r *ClusterDefinition) validateImmutableFields(old *ClusterDefinition) error {
+	var allErrs field.ErrorList
+	if old.Spec.Type != "" && r.Spec.Type != old.Spec.Type {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec.type"),
+			fmt.Sprintf("type is immutable once set, cannot be updated from %q to %q", old.Spec.Type, r.Spec.Type)))
+	}
+	r.validateImmutableSystemAccounts(&allErrs, old)
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Group: APIVersion, Kind: ClusterDefinitionKind}, r.Name, allErrs)
+	}
+	return nil
+}
+
+// validateChanges classifies the changes of the componentDefs against the existing clusters referencing the
+// ClusterDefinition. It returns warnings for the changes which will restart the pods, and rejects the breaking
+// changes unless the AllowBreakingChangeAnnotationKey annotation is present.
+func (r *ClusterDefinition) validateChanges(old *ClusterDefinition) (admission.Warnings, error) {
+	clusters, err := r.listClusters()
+	if err != nil {
+		return nil, err
+	}
+	_, allowBreakingChange := r.Annotations[AllowBreakingChangeAnnotationKey]
+	var (
+		warnings admission.Warnings
+		allErrs  field.ErrorList
+	)
+	for _, change := range classifyClusterDefinitionChanges(old, r, clusters) {
+		switch {
+		case change.Type == clusterDefinitionChangeRolling:
+			warnings = append(warnings, change.String())
+		case change.Type == clusterDefinitionChangeBreaking && allowBreakingChange:
+			warnings = append(warnings, change.String())
+		case change.Type == clusterDefinitionChangeBreaking:
+			allErrs = append(allErrs, field.Forbidden(change.Path,
+				fmt.Sprintf("%s, add the annotation %s to allow the breaking change", change.Message, AllowBreakingChangeAnnotationKey)))
+		}
+	}
+	if len(allErrs) > 0 {
+		return nil, apierrors.NewInvalid(schema.GroupKind{Group: APIVersion, Kind: ClusterDefinitionKind}, r.Name, allErrs)
+	}
+	return warnings, nil
+}
+
+// listClusters lists the clusters referencing the ClusterDefinition from the cache of the manager.
+func (r *ClusterDefinition) listClusters() ([]Cluster, error) {
+	if webhookMgr == nil || webhookMgr.client == nil {
+		return nil, nil
+	}
+	clusterList := &ClusterList{}
+	if err := webhookMgr.client.List(context.Background(), clusterList); err != nil {
+		return nil, err
+	}
+	var clusters []Cluster
+	for _, cluster := range clusterList.Items {
+		if cluster.Spec.ClusterDefRef == r.Name {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, nil
+}
+
+// validateImmutableSystemAccounts validates the immutable fields of spec.componentDefs[*].systemAccounts,
+// renaming or removing an account is not allowed since the account has been provisioned with the old name.
+func (r *ClusterDefinition) validateImmutableSystemAccounts(allErrs *field.ErrorList, old *ClusterDefinition) {
+	for i, compDef := range r.Spec.ComponentDefs {
+		oldCompDef := old.GetComponentDefByName(compDef.Name)
+		if compDef.SystemAccounts == nil || oldCompDef == nil || oldCompDef.SystemAccounts == nil {
+			continue
+		}
+		path := field.NewPath("spec.componentDefs").Index(i).Child("systemAccounts")
+		validateImmutablePasswordConfig(allErrs, path.Child("passwordConfig"),
+			compDef.SystemAccounts.PasswordConfig, oldCompDef.SystemAccounts.PasswordConfig)
+		// the accounts are matched by name, reordering and adding accounts are allowed.
+		accountNames := map[AccountName]bool{}
+		for _, account := range compDef.SystemAccounts.Accounts {
+			accountNames[account.Name] = true
+		}
+		for _, oldAccount := range oldCompDef.SystemAccounts.Accounts {
+			if !accountNames[oldAccount.Name] {
+				*allErrs = append(*allErrs, field.Forbidden(path.Child("accounts"),
+					fmt.Sprintf("account %q has been provisioned, cannot be renamed or removed", oldAccount.Name)))
+			}
+		}
+	}
+}
+
+// validateImmutablePasswordConfig validates that the seed and symbolCharacters of the password config are not updated,
+// they determine the passwords generated.
+func validateImmutablePasswordConfig(allErrs *field.ErrorList, path *field.Path, config, oldConfig PasswordConfig) {
+	if config.Seed != oldConfig.Seed {
+		*allErrs = append(*allErrs, field.Forbidden(path.Child("seed"),
+			fmt.Sprintf("seed is immutable, cannot be updated from %q to %q", oldConfig.Seed, config.Seed)))
+	}
+	if config.SymbolCharacters != oldConfig.SymbolCharacters {
+		*allErrs = append(*allErrs, field.Forbidden(path.Child("symbolCharacters"),
+			fmt.Sprintf("symbolCharacters is immutable, cannot be updated from %q to %q", oldConfig.SymbolCharacters, config.SymbolCharacters)))
+	}
+}
+
 // validateLogsPatternPrefix validate spec.components[*].logConfigs[*].filePathPattern
 func (r *ClusterDefinition) validateLogFilePatternPrefix(allErrs *field.ErrorList) {
 	for idx1, component := range r.Spec.ComponentDefs {
@@ -145,6 +321,61 @@ func (r *ClusterDefinition) validateLogFilePatternPrefix(allErrs *field.ErrorLis
 	}
 }
 
+// validateConnectionCredentialPorts validates the `$(SVC_PORT_{PORT-NAME})` placeholders in spec.connectionCredential,
+// the referenced port name must be declared by the service of at least one componentDef, and all componentDefs
+// declaring it must agree on the port value.
+func (r *ClusterDefinition) validateConnectionCredentialPorts(allErrs *field.ErrorList) {
+	if len(r.Spec.ConnectionCredential) == 0 {
+		return
+	}
+	// port name -> port value -> componentDef names
+	declaredPorts := map[string]map[int32][]string{}
+	for _, compDef := range r.Spec.ComponentDefs {
+		if compDef.Service == nil {
+			continue
+		}
+		for _, port := range compDef.Service.Ports {
+			if _, ok := declaredPorts[port.Name]; !ok {
+				declaredPorts[port.Name] = map[int32][]string{}
+			}
+			declaredPorts[port.Name][port.Port] = append(declaredPorts[port.Name][port.Port], compDef.Name)
+		}
+	}
+
+	keys := make([]string, 0, len(r.Spec.ConnectionCredential))
+	for k := range r.Spec.ConnectionCredential {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path := field.NewPath("spec.connectionCredential").Key(key)
+		for _, match := range svcPortPlaceholderRegex.FindAllStringSubmatch(r.Spec.ConnectionCredential[key], -1) {
+			placeholder, portName := match[0], match[1]
+			ports, ok := declaredPorts[portName]
+			if !ok {
+				*allErrs = append(*allErrs, field.Invalid(path, placeholder,
+					fmt.Sprintf("port %s referenced by placeholder %s is not declared by any componentDef service", portName, placeholder)))
+				continue
+			}
+			if len(ports) <= 1 {
+				continue
+			}
+			portValues := make([]int, 0, len(ports))
+			for port := range ports {
+				portValues = append(portValues, int(port))
+			}
+			sort.Ints(portValues)
+			var declarations []string
+			for _, port := range portValues {
+				declarations = append(declarations, fmt.Sprintf("%d(%s)", port, strings.Join(ports[int32(port)], ",")))
+			}
+			*allErrs = append(*allErrs, field.Invalid(path, placeholder,
+				fmt.Sprintf("port %s referenced by placeholder %s is ambiguous, it is declared with different values by componentDefs: %s",
+					portName, placeholder, strings.Join(declarations, "; "))))
+		}
+	}
+}
+
 // ValidateComponents validate spec.components is legal.
 func (r *ClusterDefinition) validateComponents(allErrs *field.ErrorList) {
 
@@ -203,6 +434,47 @@ func (r *ClusterDefinition) validateComponents(allErrs *field.ErrorList) {
 		// validate system account defined in spec.components[].systemAccounts
 		validateSystemAccount(&component)
 
+		// validate exporter config defined in spec.components[].monitor.exporterConfig
+		if component.Monitor != nil && component.Monitor.Exporter != nil {
+			if err := component.Monitor.Exporter.Validate(); err != nil {
+				*allErrs = append(*allErrs, field.Invalid(field.NewPath("spec.components[*].monitor.exporterConfig.tlsConfig"),
+					component.Monitor.Exporter.TLSConfig, err.Error()))
+			}
+		}
+
+		// validate parsing hints defined in spec.components[].logConfigs
+		for i := range component.LogConfigs {
+			if err := component.LogConfigs[i].Validate(); err != nil {
+				*allErrs = append(*allErrs, field.Invalid(field.NewPath("spec.components[*].logConfigs").Index(i),
+					component.LogConfigs[i].Name, err.Error()))
+			}
+		}
+
+		// validate probes defined in spec.components[].probes
+		if component.Probes != nil {
+			component.Probes.validate(allErrs)
+		}
+
+		// validate horizontal scale policy defined in spec.components[].horizontalScalePolicy
+		if component.HorizontalScalePolicy != nil {
+			component.HorizontalScalePolicy.validate(allErrs)
+		}
+
+		// validate roles defined in spec.components[].rsmSpec
+		if component.RSMSpec != nil {
+			component.RSMSpec.validate(allErrs, component.Name)
+		}
+
+		// validate watermarks defined in spec.components[].volumeProtectionSpec
+		if component.VolumeProtectionSpec != nil {
+			component.VolumeProtectionSpec.validate(allErrs, component.Name)
+		}
+
+		// validate schedule and maintenance window defined in spec.components[].restartPolicy
+		if component.RestartPolicy != nil {
+			component.RestartPolicy.validate(allErrs)
+		}
+
 		switch component.WorkloadType {
 		case Consensus:
 			// if consensus
@@ -215,12 +487,173 @@ func (r *ClusterDefinition) validateComponents(allErrs *field.ErrorList) {
 			}
 			validateConsensus(&component)
 		case Replication:
+			if component.ReplicationSpec != nil {
+				component.ReplicationSpec.validate(allErrs, component.Name)
+			}
 		default:
 			continue
 		}
 	}
 }
 
+// validate validates spec.components[].replicationSpec, the maxUnavailable should make the primary never
+// be updated together with the secondaries, a percentage of 100% of the replicas includes the primary.
+func (r *ReplicationSetSpec) validate(allErrs *field.ErrorList, compName string) {
+	if r.MaxUnavailable == nil {
+		return
+	}
+	path := field.NewPath("spec.components[*].replicationSpec.maxUnavailable")
+	value, err := intstr.GetScaledValueFromIntOrPercent(r.MaxUnavailable, 100, false)
+	switch {
+	case err != nil:
+		*allErrs = append(*allErrs, field.Invalid(path, r.MaxUnavailable.String(), err.Error()))
+	case value < 1:
+		*allErrs = append(*allErrs, field.Invalid(path, r.MaxUnavailable.String(),
+			fmt.Sprintf("maxUnavailable of component %s should be at least 1", compName)))
+	case r.MaxUnavailable.Type == intstr.String && value >= 100:
+		*allErrs = append(*allErrs, field.Invalid(path, r.MaxUnavailable.String(),
+			fmt.Sprintf("maxUnavailable of component %s should be less than 100%% to exclude the primary", compName)))
+	}
+}
+
+// validate validates spec.components[].horizontalScalePolicy
+func (r *HorizontalScalePolicy) validate(allErrs *field.ErrorList) {
+	if r.PreferredCloneMethod == HScaleDataCloneMethodBackup && len(r.BackupPolicyTemplateName) == 0 {
+		*allErrs = append(*allErrs,
+			field.Required(field.NewPath("spec.components[*].horizontalScalePolicy.backupPolicyTemplateName"),
+				"backupPolicyTemplateName is required when preferredCloneMethod=Backup"))
+	}
+}
+
+// validate validates spec.components[].probes, only one probe mode can be specified for each probe,
+// and the gRPC mode is not supported by the role probe.
+func (r *ClusterDefinitionProbes) validate(allErrs *field.ErrorList) {
+	names := []string{"runningProbe", "statusProbe", "roleProbe"}
+	for i, probe := range []*ClusterDefinitionProbe{r.RunningProbe, r.StatusProbe, r.RoleProbe} {
+		if probe == nil {
+			continue
+		}
+		if err := probe.Validate(); err != nil {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath("spec.components[*].probes").Child(names[i]),
+				field.OmitValueType{}, err.Error()))
+		}
+	}
+	if r.RoleProbe != nil && r.RoleProbe.GRPC != nil {
+		*allErrs = append(*allErrs, field.Forbidden(field.NewPath("spec.components[*].probes.roleProbe.grpc"),
+			"grpc is not supported by the role probe"))
+	}
+}
+
+// validate validates spec.components[].restartPolicy, the schedule should be a valid cron expression,
+// and the duration of the maintenance window should be greater than 0 and no longer than 24 hours.
+func (r *ScheduledRestartPolicy) validate(allErrs *field.ErrorList) {
+	path := field.NewPath("spec.components[*].restartPolicy")
+	if _, err := cron.ParseStandard(r.Schedule); err != nil {
+		*allErrs = append(*allErrs, field.Invalid(path.Child("schedule"), r.Schedule, err.Error()))
+	}
+	if r.MaintenanceWindow != nil {
+		duration := r.MaintenanceWindow.Duration.Duration
+		if duration <= 0 || duration > 24*time.Hour {
+			*allErrs = append(*allErrs, field.Invalid(path.Child("maintenanceWindow", "duration"), duration.String(),
+				"the duration of the maintenance window should be greater than 0 and no longer than 24h"))
+		}
+	}
+}
+
+// validate validates spec.components[].rsmSpec, exactly one leader role with voting rights is required,
+// role names should be unique, and roleProbe is required if more than one role is declared.
+func (r *RSMSpec) validate(allErrs *field.ErrorList, compName string) {
+	if len(r.Roles) == 0 {
+		return
+	}
+	rolesPath := field.NewPath("spec.components[*].rsmSpec.roles")
+	roleNames := make(map[string]bool)
+	var leaders []string
+	for _, role := range r.Roles {
+		// role names should be unique
+		if roleNames[role.Name] {
+			*allErrs = append(*allErrs,
+				field.Duplicate(rolesPath.Child("name"),
+					fmt.Sprintf("role %s of component %s", role.Name, compName)))
+			continue
+		}
+		roleNames[role.Name] = true
+		if !role.IsLeader {
+			continue
+		}
+		leaders = append(leaders, role.Name)
+		// leader should have voting rights
+		if !role.CanVote {
+			*allErrs = append(*allErrs,
+				field.Invalid(rolesPath.Child("canVote"), role.Name,
+					fmt.Sprintf("leader role %s of component %s must have voting rights", role.Name, compName)))
+		}
+	}
+	switch {
+	case len(leaders) == 0:
+		*allErrs = append(*allErrs,
+			field.Required(rolesPath.Child("isLeader"),
+				fmt.Sprintf("exactly one leader role is required for component %s", compName)))
+	case len(leaders) > 1:
+		*allErrs = append(*allErrs,
+			field.Invalid(rolesPath.Child("isLeader"), strings.Join(leaders, ","),
+				fmt.Sprintf("exactly one leader role is allowed for component %s, but roles %s are all marked as leader",
+					compName, strings.Join(leaders, ","))))
+	}
+
+	if len(r.Roles) > 1 && r.RoleProbe == nil {
+		*allErrs = append(*allErrs,
+			field.Required(field.NewPath("spec.components[*].rsmSpec.roleProbe"),
+				fmt.Sprintf("roleProbe is required for component %s when more than one role is declared", compName)))
+	}
+}
+
+// validate validates spec.components[].volumeProtectionSpec, all watermarks should be in the range of [0, 100],
+// and the low watermark should be no greater than the high watermark, both at component and volume level.
+func (r *VolumeProtectionSpec) validate(allErrs *field.ErrorList, compName string) {
+	specPath := field.NewPath("spec.components[*].volumeProtectionSpec")
+	validateRange := func(path *field.Path, watermark int) bool {
+		if watermark < 0 || watermark > 100 {
+			*allErrs = append(*allErrs,
+				field.Invalid(path, watermark,
+					fmt.Sprintf("watermark of component %s should be in the range of [0, 100]", compName)))
+			return false
+		}
+		return true
+	}
+	validateOrder := func(path *field.Path, highWatermark, lowWatermark int) {
+		if lowWatermark > highWatermark {
+			*allErrs = append(*allErrs,
+				field.Invalid(path, lowWatermark,
+					fmt.Sprintf("lowWatermark of component %s should be no greater than highWatermark %d", compName, highWatermark)))
+		}
+	}
+
+	highValid := validateRange(specPath.Child("highWatermark"), r.HighWatermark)
+	lowValid := validateRange(specPath.Child("lowWatermark"), r.LowWatermark)
+	if highValid && lowValid {
+		validateOrder(specPath.Child("lowWatermark"), r.HighWatermark, r.LowWatermark)
+	}
+
+	volumesPath := specPath.Child("volumes")
+	for _, vol := range r.Volumes {
+		highWatermark := r.HighWatermark
+		if vol.HighWatermark != nil {
+			if !validateRange(volumesPath.Child("highWatermark"), *vol.HighWatermark) {
+				continue
+			}
+			highWatermark = *vol.HighWatermark
+		}
+		// the inherited component level low watermark will be adjusted to the volume's high watermark if it is higher.
+		if vol.LowWatermark == nil {
+			continue
+		}
+		if validateRange(volumesPath.Child("lowWatermark"), *vol.LowWatermark) {
+			validateOrder(volumesPath.Child("lowWatermark"), highWatermark, *vol.LowWatermark)
+		}
+	}
+}
+
 // validate validates spec.components[].systemAccounts
 func (r *SystemAccountSpec) validate(allErrs *field.ErrorList) {
 	accountName := make(map[AccountName]bool)
@@ -268,6 +701,19 @@ func (r *SystemAccountSpec) validate(allErrs *field.ErrorList) {
 			field.Invalid(field.NewPath("spec.components[*].systemAccounts.passwordConfig"),
 				passwdConfig, "numDigits plus numSymbols exceeds password length. "))
 	}
+
+	// validate the password config of each account after merging the account-level override
+	for _, sysAccount := range r.Accounts {
+		if sysAccount.PasswordConfig == nil {
+			continue
+		}
+		passwdConfig = r.GetPasswordConfig(sysAccount)
+		if passwdConfig.Length < passwdConfig.NumDigits+passwdConfig.NumSymbols {
+			*allErrs = append(*allErrs,
+				field.Invalid(field.NewPath("spec.components[*].systemAccounts.accounts.passwordConfig"),
+					passwdConfig, fmt.Sprintf("numDigits plus numSymbols exceeds password length of account %s. ", sysAccount.Name)))
+		}
+	}
 }
 
 func (r *ClusterDefinition) validateConfigSpec(component ClusterComponentDefinition) error {
@@ -330,6 +776,9 @@ func (r ComponentDefRef) validate(allErrs *field.ErrorList, clusterDef *ClusterD
 				*allErrs = append(*allErrs, field.Invalid(field.NewPath("componentRefEnv[*].valueFrom"), valueFrom, "headlessServiceRef cannot set fieldPath"))
 			}
 		}
+		if len(valueFrom.PreferredIPFamily) > 0 && valueFrom.Type != FromHeadlessServiceRef {
+			*allErrs = append(*allErrs, field.Invalid(field.NewPath("componentRefEnv[*].valueFrom"), valueFrom, "preferredIPFamily is only valid for headlessServiceRef"))
+		}
 		// get the componentDef by name
 		compDefName := r.ComponentDefName
 		compDef := clusterDef.GetComponentDefByName(compDefName)
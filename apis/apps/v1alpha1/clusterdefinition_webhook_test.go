@@ -23,8 +23,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 )
 
 var _ = Describe("clusterDefinition webhook", func() {
@@ -84,6 +88,110 @@ var _ = Describe("clusterDefinition webhook", func() {
 			Expect(testCtx.CreateObj(ctx, clusterDef)).ShouldNot(Succeed())
 		})
 
+		It("Validate Cluster Definition HorizontalScalePolicy", func() {
+			By("By creating a new clusterDefinition preferring backup tool without backup policy template")
+			clusterDef, _ := createTestClusterDefinitionObj3(clusterDefinitionName3)
+			clusterDef.Spec.ComponentDefs[0].HorizontalScalePolicy = &HorizontalScalePolicy{
+				Type:                 HScaleDataClonePolicyCloneVolume,
+				PreferredCloneMethod: HScaleDataCloneMethodBackup,
+			}
+			err := testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("backupPolicyTemplateName is required"))
+
+			By("By creating a new clusterDefinition preferring backup tool with backup policy template")
+			clusterDef.Spec.ComponentDefs[0].HorizontalScalePolicy.BackupPolicyTemplateName = "test-backup-policy-template"
+			Expect(testCtx.CreateObj(ctx, clusterDef)).Should(Succeed())
+		})
+
+		It("Validate Cluster Definition RSMSpec roles", func() {
+			By("By creating a new clusterDefinition with two leader roles")
+			clusterDef, _ := createTestClusterDefinitionObj3(clusterDefinitionName3)
+			clusterDef.Spec.ComponentDefs[0].RSMSpec = &RSMSpec{
+				Roles: []workloads.ReplicaRole{
+					{Name: "leader", AccessMode: workloads.ReadWriteMode, CanVote: true, IsLeader: true},
+					{Name: "primary", AccessMode: workloads.ReadWriteMode, CanVote: true, IsLeader: true},
+				},
+				RoleProbe: &workloads.RoleProbe{},
+			}
+			err := testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("exactly one leader role is allowed for component replicasets"))
+
+			By("By creating a new clusterDefinition with duplicated role names")
+			clusterDef.Spec.ComponentDefs[0].RSMSpec.Roles[1] = workloads.ReplicaRole{Name: "leader", AccessMode: workloads.ReadonlyMode, CanVote: true}
+			err = testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("role leader of component replicasets"))
+
+			By("By creating a new clusterDefinition with a leader role without voting rights")
+			clusterDef.Spec.ComponentDefs[0].RSMSpec.Roles[0].CanVote = false
+			clusterDef.Spec.ComponentDefs[0].RSMSpec.Roles[1].Name = "follower"
+			err = testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("leader role leader of component replicasets must have voting rights"))
+
+			By("By creating a new clusterDefinition with multiple roles but without role probe")
+			clusterDef.Spec.ComponentDefs[0].RSMSpec.Roles[0].CanVote = true
+			clusterDef.Spec.ComponentDefs[0].RSMSpec.RoleProbe = nil
+			err = testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("roleProbe is required for component replicasets"))
+
+			By("By creating a new clusterDefinition with valid roles")
+			clusterDef.Spec.ComponentDefs[0].RSMSpec.RoleProbe = &workloads.RoleProbe{}
+			Expect(testCtx.CreateObj(ctx, clusterDef)).Should(Succeed())
+		})
+
+		It("Validate Cluster Definition VolumeProtectionSpec watermarks", func() {
+			By("By creating a new clusterDefinition with low watermark greater than high watermark")
+			clusterDef, _ := createTestClusterDefinitionObj3(clusterDefinitionName3)
+			clusterDef.Spec.ComponentDefs[0].VolumeProtectionSpec = &VolumeProtectionSpec{
+				HighWatermark: 80,
+				LowWatermark:  85,
+				Volumes: []ProtectedVolume{
+					{Name: "data"},
+				},
+			}
+			err := testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("lowWatermark of component replicasets should be no greater than highWatermark 80"))
+
+			By("By creating a new clusterDefinition with volume low watermark greater than volume high watermark")
+			volumeHighWatermark, volumeLowWatermark := 70, 75
+			clusterDef.Spec.ComponentDefs[0].VolumeProtectionSpec.LowWatermark = 75
+			clusterDef.Spec.ComponentDefs[0].VolumeProtectionSpec.Volumes[0].HighWatermark = &volumeHighWatermark
+			clusterDef.Spec.ComponentDefs[0].VolumeProtectionSpec.Volumes[0].LowWatermark = &volumeLowWatermark
+			err = testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("lowWatermark of component replicasets should be no greater than highWatermark 70"))
+
+			By("By creating a new clusterDefinition with valid watermarks")
+			volumeLowWatermark = 65
+			Expect(testCtx.CreateObj(ctx, clusterDef)).Should(Succeed())
+		})
+
+		It("Validate Cluster Definition ReplicationSpec maxUnavailable", func() {
+			By("By creating a new clusterDefinition with zero maxUnavailable")
+			clusterDef, _ := createTestClusterDefinitionObj(clusterDefinitionName)
+			maxUnavailable := intstr.FromInt(0)
+			clusterDef.Spec.ComponentDefs[0].WorkloadType = Replication
+			clusterDef.Spec.ComponentDefs[0].ReplicationSpec = &ReplicationSetSpec{MaxUnavailable: &maxUnavailable}
+			err := testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("maxUnavailable of component replicasets should be at least 1"))
+
+			By("By creating a new clusterDefinition with maxUnavailable including the primary")
+			maxUnavailable = intstr.FromString("100%")
+			err = testCtx.CreateObj(ctx, clusterDef)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("maxUnavailable of component replicasets should be less than 100%"))
+
+			By("By creating a new clusterDefinition with valid maxUnavailable")
+			maxUnavailable = intstr.FromString("50%")
+			Expect(testCtx.CreateObj(ctx, clusterDef)).Should(Succeed())
+		})
+
 		It("Validate Cluster Definition System Accounts", func() {
 			By("By creating a new clusterDefinition")
 			clusterDef, _ := createTestClusterDefinitionObj3(clusterDefinitionName3)
@@ -145,6 +253,23 @@ var _ = Describe("clusterDefinition webhook", func() {
 			}
 			Expect(testCtx.CreateObj(ctx, clusterDef)).ShouldNot(Succeed())
 
+			By("By creating a new clusterDefinition with invalid account-level password setting")
+			numDigits := int32(8)
+			mockAccounts[1].PasswordConfig = &PasswordConfigOverride{
+				NumDigits: &numDigits,
+			}
+			clusterDef.Spec.ComponentDefs[0].SystemAccounts = &SystemAccountSpec{
+				CmdExecutorConfig: cmdExecConfig,
+				PasswordConfig: PasswordConfig{
+					Length:     10,
+					NumSymbols: 4,
+				},
+				Accounts: mockAccounts,
+			}
+			Expect(testCtx.CreateObj(ctx, clusterDef)).ShouldNot(Succeed())
+			// reset account setting
+			mockAccounts[1].PasswordConfig = nil
+
 			By("By creating a new clusterDefinition with statements missing")
 			mockAccounts[0].ProvisionPolicy.Type = ReferToExisting
 			clusterDef.Spec.ComponentDefs[0].SystemAccounts = &SystemAccountSpec{
@@ -205,6 +330,14 @@ var _ = Describe("clusterDefinition webhook", func() {
 			clusterDef.Spec.ComponentDefs[0].ComponentDefRef = componentRefs
 			Expect(testCtx.CreateObj(ctx, clusterDef)).ShouldNot(Succeed())
 
+			By("By creating a new clusterDefinition with preferredIPFamily of serviceRef, should fail")
+			componentRefs[0].ComponentRefEnvs[0].ValueFrom = &ComponentValueFrom{
+				Type:              FromServiceRef,
+				PreferredIPFamily: corev1.IPv6Protocol,
+			}
+			clusterDef.Spec.ComponentDefs[0].ComponentDefRef = componentRefs
+			Expect(testCtx.CreateObj(ctx, clusterDef)).ShouldNot(Succeed())
+
 			By("By creating a new clusterDefinition with valid valueFrom type, should succeed")
 			componentRefs[0].ComponentRefEnvs[0].ValueFrom = &ComponentValueFrom{
 				Type: FromServiceRef,
@@ -347,6 +480,17 @@ var _ = Describe("clusterDefinition webhook", func() {
 		Expect(k8sClient.Update(ctx, clusterDef)).Should(Succeed())
 		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: clusterDef.Name}, clusterDef)).Should(Succeed())
 		Expect(clusterDef.Spec.ComponentDefs[0].HorizontalScalePolicy.Type).Should(Equal(HScaleDataClonePolicyCloneVolume))
+
+		By("set default member update strategy when roles declared")
+		clusterDef.Spec.ComponentDefs[0].RSMSpec = &RSMSpec{
+			Roles: []workloads.ReplicaRole{
+				{Name: "leader", AccessMode: workloads.ReadWriteMode, CanVote: true, IsLeader: true},
+			},
+		}
+		Expect(k8sClient.Update(ctx, clusterDef)).Should(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: clusterDef.Name}, clusterDef)).Should(Succeed())
+		Expect(clusterDef.Spec.ComponentDefs[0].RSMSpec.MemberUpdateStrategy).ShouldNot(BeNil())
+		Expect(*clusterDef.Spec.ComponentDefs[0].RSMSpec.MemberUpdateStrategy).Should(Equal(workloads.SerialUpdateStrategy))
 	})
 })
 
@@ -67,6 +67,23 @@ func (r ClusterVersionStatus) GetTerminalPhases() []Phase {
 	return []Phase{AvailablePhase}
 }
 
+func (r ClusterVersionStatus) GetPhase() Phase {
+	return r.Phase
+}
+
+func (r ClusterVersionStatus) GetMessage() string {
+	return r.Message
+}
+
+func (r ClusterVersionStatus) GetObservedGeneration() int64 {
+	return r.ObservedGeneration
+}
+
+// GetTerminalPhaseStatus returns the status of the ClusterVersion.
+func (r *ClusterVersion) GetTerminalPhaseStatus() TerminalPhaseStatus {
+	return r.Status
+}
+
 // ClusterComponentVersion is an application version component spec.
 type ClusterComponentVersion struct {
 	// Specifies a reference to one of the cluster component definition names in the ClusterDefinition API (spec.componentDefs.name).
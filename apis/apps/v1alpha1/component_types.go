@@ -20,8 +20,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
@@ -133,6 +135,14 @@ type ComponentSpec struct {
 	//
 	// +optional
 	Instances []string `json:"instances,omitempty"`
+
+	// Specifies a strategic merge patch applied to the podSpec of the component's workload,
+	// after the environment variables and volumes are injected.
+	//
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	PodSpecPatch *runtime.RawExtension `json:"podSpecPatch,omitempty"`
 }
 
 // ComponentStatus represents the observed state of a Component within the cluster.
@@ -165,6 +175,56 @@ type ComponentStatus struct {
 	//
 	// +optional
 	Message ComponentMessageMap `json:"message,omitempty"`
+
+	// Records the time when the last switchover of the component finished, either succeeded or failed.
+	// The result of the switchover is recorded in the `Switchover` condition.
+	//
+	// +optional
+	LastSwitchoverTime *metav1.Time `json:"lastSwitchoverTime,omitempty"`
+
+	// Records the time when the last scheduled restart of the component was triggered,
+	// according to the restart policy defined in the ClusterComponentDefinition.
+	//
+	// +optional
+	LastScheduledRestartTime *metav1.Time `json:"lastScheduledRestartTime,omitempty"`
+
+	// Records the volumes whose space usage has exceeded the high watermark and caused the instances
+	// to be locked to read-only by the volume protection. The volumes of an instance are removed from
+	// the list once the instance is unlocked.
+	//
+	// +optional
+	LockedVolumes []LockedVolume `json:"lockedVolumes,omitempty"`
+
+	// Records the pod management policy of the underlying StatefulSet resolved from the definition
+	// and the update strategy of the component.
+	//
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
+	// Records the update strategy of the underlying StatefulSet resolved from the definition and the update strategy
+	// of the component, e.g. the `llUpdateStrategy` of the ClusterDefinition. It is `OnDelete` if the pods are updated
+	// by the controller in the order of the roles, such as the Consensus and Replication components.
+	//
+	// +optional
+	UpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+// LockedVolume describes a volume that caused the instance to be locked by the volume protection.
+type LockedVolume struct {
+	// Specifies the name of the pod that is locked.
+	//
+	// +kubebuilder:validation:Required
+	PodName string `json:"podName"`
+
+	// Specifies the name of the volume whose space usage has exceeded the high watermark.
+	//
+	// +kubebuilder:validation:Required
+	VolumeName string `json:"volumeName"`
+
+	// Records the space usage of the volume in percentage when the instance is locked.
+	//
+	// +optional
+	UsagePercentage *int32 `json:"usagePercentage,omitempty"`
 }
 
 // +genclient
@@ -23,6 +23,8 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 )
 
 // +genclient
@@ -251,6 +253,16 @@ type ComponentDefinitionSpec struct {
 	// +kubebuilder:default=0
 	// +optional
 	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// Specifies how the leader is evicted, e.g. when the node hosting it is drained.
+	//
+	// - `Direct`: Evicts the leader directly like the other replicas.
+	// - `SwitchoverFirst`: Switches the leadership over to another replica by the switchover action before
+	//   evicting the leader, and falls back to `Direct` if the switchover is not done in time.
+	//
+	// +kubebuilder:validation:Enum={Direct,SwitchoverFirst}
+	// +optional
+	LeaderEvictionPolicy workloads.LeaderEvictionPolicy `json:"leaderEvictionPolicy,omitempty"`
 }
 
 // ComponentDefinitionStatus defines the observed state of ComponentDefinition.
@@ -272,6 +284,27 @@ type ComponentDefinitionStatus struct {
 	Message string `json:"message,omitempty"`
 }
 
+func (r ComponentDefinitionStatus) GetTerminalPhases() []Phase {
+	return []Phase{AvailablePhase}
+}
+
+func (r ComponentDefinitionStatus) GetPhase() Phase {
+	return r.Phase
+}
+
+func (r ComponentDefinitionStatus) GetMessage() string {
+	return r.Message
+}
+
+func (r ComponentDefinitionStatus) GetObservedGeneration() int64 {
+	return r.ObservedGeneration
+}
+
+// GetTerminalPhaseStatus returns the status of the ComponentDefinition.
+func (r *ComponentDefinition) GetTerminalPhaseStatus() TerminalPhaseStatus {
+	return r.Status
+}
+
 type ComponentVolume struct {
 	// Specifies the name of the volume.
 	// It must be a DNS_LABEL and unique within the pod.
@@ -523,6 +556,23 @@ type Action struct {
 	// +patchStrategy=merge
 	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
 
+	// Represents a list of sources to populate the environment variables in the container.
+	// It only takes effect when the action is executed in a dedicated job with the specified image,
+	// such as the switchover and postProvision actions.
+	// This field cannot be updated.
+	//
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Specifies the resource requirements of the container that runs the action.
+	// It only takes effect when the action is executed in a dedicated job with the specified image,
+	// such as the switchover and postProvision actions. If not set, the default resources configured
+	// for the command executor will be used.
+	// This field cannot be updated.
+	//
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
 	// Defines how to select the target Pod where the action will be performed,
 	// if there may not have a target replica by default.
 	// This field cannot be updated.
@@ -819,6 +869,24 @@ type ComponentSwitchover struct {
 	// +kubebuilder:deprecatedversion:warning="This field is deprecated from KB 0.9.0"
 	// +optional
 	ScriptSpecSelectors []ScriptSpecSelector `json:"scriptSpecSelectors,omitempty"`
+
+	// Represents the check to be performed before the switchover, the switchover will be aborted if it fails.
+	// Only Action.Exec is currently supported.
+	//
+	// +optional
+	PreCheck *Action `json:"preCheck,omitempty"`
+
+	// Represents the check to be performed after the switchover, the switchover will be considered failed if it fails.
+	// Only Action.Exec is currently supported.
+	//
+	// +optional
+	PostCheck *Action `json:"postCheck,omitempty"`
+
+	// Defines the schema of the parameters which can be specified by the switchover OpsRequest.
+	// The parameters are injected into the switchover job as the env `KB_SWITCHOVER_PARAM_<NAME>`.
+	//
+	// +optional
+	ParametersSchema *ParametersSchema `json:"parametersSchema,omitempty"`
 }
 
 type RoleProbe struct {
@@ -20,7 +20,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package v1alpha1
 
 import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -57,17 +60,72 @@ var _ webhook.Validator = &ComponentDefinition{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *ComponentDefinition) ValidateCreate() (admission.Warnings, error) {
 	componentdefinitionlog.Info("validate create", "name", r.Name)
-
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil, nil
+	if err := r.validateServiceRefDeclarations(); err != nil {
+		return nil, err
+	}
+	return nil, r.validateLogConfigs()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ComponentDefinition) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	componentdefinitionlog.Info("validate update", "name", r.Name)
+	if err := r.validateImmutablePasswordGenerationPolicies(old.(*ComponentDefinition)); err != nil {
+		return nil, err
+	}
+	if err := r.validateServiceRefDeclarations(); err != nil {
+		return nil, err
+	}
+	return nil, r.validateLogConfigs()
+}
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil, nil
+// validateLogConfigs validates the parsing hints of spec.logConfigs.
+func (r *ComponentDefinition) validateLogConfigs() error {
+	var allErrs field.ErrorList
+	for i := range r.Spec.LogConfigs {
+		if err := r.Spec.LogConfigs[i].Validate(); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.logConfigs").Index(i), r.Spec.LogConfigs[i].Name, err.Error()))
+		}
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Group: APIVersion, Kind: ComponentDefinitionKind}, r.Name, allErrs)
+	}
+	return nil
+}
+
+// validateServiceRefDeclarations validates spec.serviceRefDeclarations.
+func (r *ComponentDefinition) validateServiceRefDeclarations() error {
+	var allErrs field.ErrorList
+	for i := range r.Spec.ServiceRefDeclarations {
+		if err := r.Spec.ServiceRefDeclarations[i].Validate(); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec.serviceRefDeclarations").Index(i),
+				r.Spec.ServiceRefDeclarations[i].Name, err.Error()))
+		}
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Group: APIVersion, Kind: ComponentDefinitionKind}, r.Name, allErrs)
+	}
+	return nil
+}
+
+// validateImmutablePasswordGenerationPolicies validates the immutable fields of spec.systemAccounts[*].passwordGenerationPolicy.
+func (r *ComponentDefinition) validateImmutablePasswordGenerationPolicies(old *ComponentDefinition) error {
+	oldPolicies := make(map[string]PasswordConfig)
+	for _, account := range old.Spec.SystemAccounts {
+		oldPolicies[account.Name] = account.PasswordGenerationPolicy
+	}
+	var allErrs field.ErrorList
+	for i, account := range r.Spec.SystemAccounts {
+		oldPolicy, ok := oldPolicies[account.Name]
+		if !ok {
+			continue
+		}
+		path := field.NewPath("spec.systemAccounts").Index(i).Child("passwordGenerationPolicy")
+		validateImmutablePasswordConfig(&allErrs, path, account.PasswordGenerationPolicy, oldPolicy)
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(schema.GroupKind{Group: APIVersion, Kind: ComponentDefinitionKind}, r.Name, allErrs)
+	}
+	return nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -175,6 +175,12 @@ type Switchover struct {
 	//
 	// +kubebuilder:validation:Required
 	InstanceName string `json:"instanceName"`
+
+	// Specifies the parameters of the switchover, they are validated against the `parametersSchema` of the switchover spec.
+	// If the parameter type is an array, the format should be "v1,v2,v3".
+	//
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // Upgrade represents the parameters required for an upgrade operation.
@@ -782,6 +788,10 @@ type OpsRequestComponentStatus struct {
 	// +optional
 	ProgressDetails []ProgressStatusDetail `json:"progressDetails,omitempty"`
 
+	// Records the parameters used by the operation of the component, e.g. the parameters of the switchover.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
 	// References the workload type of component in ClusterDefinition.
 	// +optional
 	WorkloadType WorkloadType `json:"workloadType,omitempty"`
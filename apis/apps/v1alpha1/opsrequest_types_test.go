@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
 	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 var componentName = "mysql"
@@ -217,3 +220,74 @@ func TestSetStatusAndMessage(t *testing.T) {
 		t.Error("set progressDetail status and message failed")
 	}
 }
+
+func TestValidateSwitchoverParameters(t *testing.T) {
+	schema := &ParametersSchema{
+		OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"sync-wait": {Type: "integer"},
+				"force":     {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"true"`)}, {Raw: []byte(`"false"`)}}},
+			},
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{Allows: false},
+		},
+	}
+	newSwitchover := func(parameters map[string]string) Switchover {
+		return Switchover{
+			ComponentOps: ComponentOps{ComponentName: componentName},
+			InstanceName: "*",
+			Parameters:   parameters,
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		parameters map[string]string
+		schema     *ParametersSchema
+		errMsg     string
+	}{
+		{name: "no parameters without schema"},
+		{
+			name:       "parameters without schema",
+			parameters: map[string]string{"sync-wait": "10"},
+			errMsg:     "does not support parameters",
+		},
+		{
+			name:       "valid parameters",
+			parameters: map[string]string{"sync-wait": "10", "force": "true"},
+			schema:     schema,
+		},
+		{
+			name:       "invalid type",
+			parameters: map[string]string{"sync-wait": "ten"},
+			schema:     schema,
+			errMsg:     "sync-wait",
+		},
+		{
+			name:       "invalid enum",
+			parameters: map[string]string{"force": "yes"},
+			schema:     schema,
+			errMsg:     "force",
+		},
+		{
+			name:       "unknown parameter",
+			parameters: map[string]string{"timeout": "10"},
+			schema:     schema,
+			errMsg:     "timeout",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSwitchoverParameters(newSwitchover(tc.parameters), tc.schema)
+			if tc.errMsg == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("expected error containing %q, got: %v", tc.errMsg, err)
+			}
+		})
+	}
+}
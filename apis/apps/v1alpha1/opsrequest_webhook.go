@@ -37,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	"github.com/apecloud/kubeblocks/pkg/common"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 )
 
@@ -707,6 +708,9 @@ func validateSwitchoverResourceList(ctx context.Context, cli client.Client, clus
 			if clusterCompDefObj.SwitchoverSpec == nil {
 				return fmt.Errorf("this cluster component %s does not support switchover", switchover.ComponentName)
 			}
+			if err := validateSwitchoverParameters(switchover, clusterCompDefObj.SwitchoverSpec.ParametersSchema); err != nil {
+				return err
+			}
 			switch switchover.InstanceName {
 			case KBSwitchoverCandidateInstanceForAnyPod:
 				if clusterCompDefObj.SwitchoverSpec.WithoutCandidate == nil {
@@ -764,6 +768,9 @@ func validateSwitchoverResourceList(ctx context.Context, cli client.Client, clus
 			if compDefObj.Spec.LifecycleActions == nil || compDefObj.Spec.LifecycleActions.Switchover == nil {
 				return fmt.Errorf("this cluster component %s does not support switchover", switchover.ComponentName)
 			}
+			if err := validateSwitchoverParameters(switchover, compDefObj.Spec.LifecycleActions.Switchover.ParametersSchema); err != nil {
+				return err
+			}
 			switch switchover.InstanceName {
 			case KBSwitchoverCandidateInstanceForAnyPod:
 				if compDefObj.Spec.LifecycleActions.Switchover.WithoutCandidate == nil {
@@ -815,6 +822,31 @@ func validateSwitchoverResourceList(ctx context.Context, cli client.Client, clus
 	return nil
 }
 
+// validateSwitchoverParameters validates the parameters of the switchover against the parameters schema,
+// the parameters are not allowed if the schema is not defined.
+func validateSwitchoverParameters(switchover Switchover, schema *ParametersSchema) error {
+	if schema == nil || schema.OpenAPIV3Schema == nil {
+		if len(switchover.Parameters) > 0 {
+			return fmt.Errorf("the switchover of component %s does not support parameters", switchover.ComponentName)
+		}
+		return nil
+	}
+	params, err := common.CoverStringToInterfaceBySchemaType(schema.OpenAPIV3Schema, switchover.Parameters)
+	if err != nil {
+		return err
+	}
+	// keep the unknown parameters, they are rejected if the additional properties are not allowed.
+	for k, v := range switchover.Parameters {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	if err = common.ValidateDataWithSchema(schema.OpenAPIV3Schema, params); err != nil {
+		return fmt.Errorf("invalid parameters of the switchover of component %s: %s", switchover.ComponentName, err.Error())
+	}
+	return nil
+}
+
 // getComponentDefByName gets ComponentDefinition with compDefName
 func getComponentDefByName(ctx context.Context, cli client.Client, compDefName string) (*ComponentDefinition, error) {
 	compDef := &ComponentDefinition{}
@@ -831,10 +863,8 @@ func getClusterComponentDefByName(ctx context.Context, cli client.Client, cluste
 	if err := cli.Get(ctx, client.ObjectKey{Name: cluster.Spec.ClusterDefRef}, clusterDef); err != nil {
 		return nil, err
 	}
-	for _, component := range clusterDef.Spec.ComponentDefs {
-		if component.Name == compDefName {
-			return &component, nil
-		}
+	if compDef := clusterDef.GetComponentDefByName(compDefName); compDef != nil {
+		return compDef, nil
 	}
 	return nil, ErrNotMatchingCompDef
 }
@@ -27,11 +27,13 @@ import (
 )
 
 const (
-	APIVersion            = "apps.kubeblocks.io/v1alpha1"
-	ClusterVersionKind    = "ClusterVersion"
-	ClusterDefinitionKind = "ClusterDefinition"
-	ClusterKind           = "Cluster"
-	OpsRequestKind        = "OpsRequestKind"
+	APIVersion               = "apps.kubeblocks.io/v1alpha1"
+	ClusterVersionKind       = "ClusterVersion"
+	ClusterDefinitionKind    = "ClusterDefinition"
+	ClusterKind              = "Cluster"
+	ComponentDefinitionKind  = "ComponentDefinition"
+	OpsRequestKind           = "OpsRequestKind"
+	BackupPolicyTemplateKind = "BackupPolicyTemplate"
 )
 
 type ComponentTemplateSpec struct {
@@ -149,6 +151,26 @@ const (
 	NoneMergePolicy MergedPolicy = "none"
 )
 
+// ScrapeScheme defines the scheme used by the Time Series Database to scrape metrics.
+// +enum
+// +kubebuilder:validation:Enum={http,https}
+type ScrapeScheme string
+
+const (
+	HTTPScrapeScheme  ScrapeScheme = "http"
+	HTTPSScrapeScheme ScrapeScheme = "https"
+)
+
+// LogFormat defines the format of the log file, which is used by the log agent to parse the log.
+// +enum
+// +kubebuilder:validation:Enum={json,regex}
+type LogFormat string
+
+const (
+	JSONLogFormat  LogFormat = "json"
+	RegexLogFormat LogFormat = "regex"
+)
+
 // ClusterPhase defines the phase of the Cluster within the .status.phase field.
 //
 // +enum
@@ -229,6 +251,57 @@ const (
 	ConditionTypeReplicasReady       = "ReplicasReady"       // ConditionTypeReplicasReady all pods of components are ready
 	ConditionTypeReady               = "Ready"               // ConditionTypeReady all components are running
 	ConditionTypeSwitchoverPrefix    = "Switchover-"         // ConditionTypeSwitchoverPrefix component status condition of switchover
+
+	ConditionTypeBackupPolicyTemplateResolved = "BackupPolicyTemplateResolved" // ConditionTypeBackupPolicyTemplateResolved which BackupPolicyTemplates are chosen as the defaults of the components
+)
+
+const (
+	// define the reasons of the component switchover condition
+	ReasonSwitchoverProgressing = "SwitchoverProgressing" // ReasonSwitchoverProgressing the switchover job of the component is running
+	ReasonSwitchoverSucceed     = "SwitchoverSucceed"     // ReasonSwitchoverSucceed the switchover of the component succeeded
+	ReasonSwitchoverFailed      = "SwitchoverFailed"      // ReasonSwitchoverFailed the switchover job of the component failed
+)
+
+const (
+	// define the condition type and reasons of the component post-start action
+	ConditionTypePostStartSucceeded = "PostStartSucceeded" // ConditionTypePostStartSucceeded whether the last execution of the post-start action succeeded
+	ReasonPostStartSucceed          = "PostStartSucceed"   // ReasonPostStartSucceed the post-start job of the component succeeded
+	ReasonPostStartFailed           = "PostStartFailed"    // ReasonPostStartFailed the post-start job of the component failed
+)
+
+const (
+	// define the condition type and reasons of the component volume protection
+	ConditionTypeVolumeProtection = "VolumeProtection" // ConditionTypeVolumeProtection whether some instances of the component are locked to read-only by the volume protection
+	ReasonVolumesLocked           = "VolumesLocked"    // ReasonVolumesLocked some instances are locked as their volumes are running out of space
+	ReasonVolumesUnlocked         = "VolumesUnlocked"  // ReasonVolumesUnlocked no instance is locked by the volume protection
+)
+
+const (
+	// define the condition type and reasons of the component ref envs
+	ConditionTypeComponentRefEnvResolved = "ComponentRefEnvResolved" // ConditionTypeComponentRefEnvResolved whether all envs of the componentDefRefs are resolved
+	ReasonComponentRefEnvResolved        = "ComponentRefEnvResolved" // ReasonComponentRefEnvResolved all envs of the componentDefRefs are resolved
+	ReasonComponentRefEnvIgnored         = "ComponentRefEnvIgnored"  // ReasonComponentRefEnvIgnored some envs failed to be resolved and took their default values
+)
+
+const (
+	// define the condition type and reasons of the component ServiceMonitor
+	ConditionTypeServiceMonitorSupported = "ServiceMonitorSupported"   // ConditionTypeServiceMonitorSupported whether the ServiceMonitor required by the component is supported
+	ReasonServiceMonitorSupported        = "ServiceMonitorSupported"   // ReasonServiceMonitorSupported the ServiceMonitor CRD of prometheus-operator is installed
+	ReasonServiceMonitorUnsupported      = "ServiceMonitorUnsupported" // ReasonServiceMonitorUnsupported the ServiceMonitor CRD of prometheus-operator is not installed
+)
+
+const (
+	// define the condition type and reasons of the component scheduled restart
+	ConditionTypeScheduledRestart = "ScheduledRestart"        // ConditionTypeScheduledRestart whether the last scheduled restart of the component is started
+	ReasonScheduledRestart        = "ScheduledRestart"        // ReasonScheduledRestart the scheduled restart is started
+	ReasonScheduledRestartSkipped = "ScheduledRestartSkipped" // ReasonScheduledRestartSkipped the scheduled restart is skipped
+)
+
+const (
+	// define the cluster definition condition type and reasons
+	ConditionTypeDataVolumeDeclared = "DataVolumeDeclared" // ConditionTypeDataVolumeDeclared whether all stateful componentDefs declare a data volume in volumeTypes
+	ReasonDataVolumeDeclared        = "DataVolumeDeclared" // ReasonDataVolumeDeclared all stateful componentDefs declare a data volume
+	ReasonDataVolumeMissing         = "DataVolumeMissing"  // ReasonDataVolumeMissing some stateful componentDefs declare no data volume, their backups are disabled
 )
 
 // Phase represents the current status of the ClusterDefinition and ClusterVersion CR.
@@ -245,6 +318,17 @@ const (
 	UnavailablePhase Phase = "Unavailable"
 )
 
+// TerminalPhaseStatus is implemented by the status of the definition objects, e.g. ClusterDefinition,
+// which can be referenced by other objects once they reach one of the terminal phases.
+//
+// +kubebuilder:object:generate=false
+type TerminalPhaseStatus interface {
+	GetPhase() Phase
+	GetMessage() string
+	GetObservedGeneration() int64
+	GetTerminalPhases() []Phase
+}
+
 // ConfigConstraintPhase defines the ConfigConstraint  CR .status.phase
 // +enum
 // +kubebuilder:validation:Enum={Available,Unavailable, Deleting}
@@ -380,6 +464,21 @@ const (
 	BestEffortParallelStrategy UpdateStrategy = "BestEffortParallel"
 )
 
+// PostStartPolicy defines when the post-start action of the component is executed.
+// The available policies are `Once` and `OnChange`.
+//
+// +enum
+// +kubebuilder:validation:Enum={Once,OnChange}
+type PostStartPolicy string
+
+const (
+	// PostStartPolicyOnce indicates that the post-start action is executed only once after the component first becomes ready.
+	PostStartPolicyOnce PostStartPolicy = "Once"
+
+	// PostStartPolicyOnChange indicates that the post-start action is executed again whenever its definition changes.
+	PostStartPolicyOnChange PostStartPolicy = "OnChange"
+)
+
 var DefaultLeader = ConsensusMember{
 	Name:       "leader",
 	AccessMode: ReadWrite,
@@ -448,6 +547,22 @@ const (
 	HScaleDataClonePolicyFromSnapshot HScaleDataClonePolicyType = "Snapshot"
 )
 
+// HScaleDataCloneMethod defines the preferred method used to clone data when the data clone policy is `CloneVolume`.
+//
+// +enum
+// +kubebuilder:validation:Enum={Snapshot,Backup}
+type HScaleDataCloneMethod string
+
+const (
+	// HScaleDataCloneMethodSnapshot indicates that the volume snapshot is preferred, and the backup tool is used
+	// only when volume snapshot is not enabled.
+	HScaleDataCloneMethodSnapshot HScaleDataCloneMethod = "Snapshot"
+
+	// HScaleDataCloneMethodBackup indicates that the backup tool is preferred, and the volume snapshot is used
+	// only when no backup tool method is configured in the backup policy.
+	HScaleDataCloneMethodBackup HScaleDataCloneMethod = "Backup"
+)
+
 // PodAntiAffinity defines the pod anti-affinity strategy.
 //
 // This strategy determines how pods are scheduled in relation to other pods, with the aim of either spreading pods
@@ -628,6 +743,21 @@ const (
 	MixedCases LetterCase = "MixedCases"
 )
 
+// PasswordGeneratorType defines the implementation to generate the passwords of the system accounts.
+//
+// +enum
+// +kubebuilder:validation:Enum={Default,Kms}
+type PasswordGeneratorType string
+
+const (
+	// DefaultPasswordGenerator generates the passwords by KubeBlocks, the passwords are deterministic if the seed is set.
+	DefaultPasswordGenerator PasswordGeneratorType = "Default"
+
+	// KmsPasswordGenerator requests the passwords from the KMS webhook configured for KubeBlocks,
+	// e.g. to generate the passwords in a FIPS-compliant way.
+	KmsPasswordGenerator PasswordGeneratorType = "Kms"
+)
+
 var webhookMgr *webhookManager
 
 type webhookManager struct {
@@ -805,6 +935,7 @@ var (
 type StatefulSetWorkload interface {
 	FinalStsUpdateStrategy() (appsv1.PodManagementPolicyType, appsv1.StatefulSetUpdateStrategy)
 	GetUpdateStrategy() UpdateStrategy
+	GetMinReadySeconds() int32
 }
 
 type HostNetwork struct {
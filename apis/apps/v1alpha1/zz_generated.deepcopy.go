@@ -25,6 +25,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package v1alpha1
 
 import (
+	workloadsv1alpha1 "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -33,8 +34,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
-
-	workloadsv1alpha1 "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -57,6 +56,14 @@ func (in *Action) DeepCopyInto(out *Action) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
 	if in.RetryPolicy != nil {
 		in, out := &in.RetryPolicy, &out.RetryPolicy
 		*out = new(RetryPolicy)
@@ -428,6 +435,16 @@ func (in *ClusterBackup) DeepCopyInto(out *ClusterBackup) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.FinalBackupOnDelete != nil {
+		in, out := &in.FinalBackupOnDelete, &out.FinalBackupOnDelete
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FinalBackupTimeoutMinutes != nil {
+		in, out := &in.FinalBackupTimeoutMinutes, &out.FinalBackupTimeoutMinutes
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterBackup.
@@ -525,9 +542,12 @@ func (in *ClusterComponentDefinition) DeepCopyInto(out *ClusterComponentDefiniti
 	if in.CustomLabelSpecs != nil {
 		in, out := &in.CustomLabelSpecs, &out.CustomLabelSpecs
 		*out = make([]CustomLabelSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+		copy(*out, *in)
+	}
+	if in.CustomAnnotationSpecs != nil {
+		in, out := &in.CustomAnnotationSpecs, &out.CustomAnnotationSpecs
+		*out = make([]CustomAnnotationSpec, len(*in))
+		copy(*out, *in)
 	}
 	if in.SwitchoverSpec != nil {
 		in, out := &in.SwitchoverSpec, &out.SwitchoverSpec
@@ -544,6 +564,11 @@ func (in *ClusterComponentDefinition) DeepCopyInto(out *ClusterComponentDefiniti
 		*out = new(VolumeProtectionSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RestartPolicy != nil {
+		in, out := &in.RestartPolicy, &out.RestartPolicy
+		*out = new(ScheduledRestartPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ComponentDefRef != nil {
 		in, out := &in.ComponentDefRef, &out.ComponentDefRef
 		*out = make([]ComponentDefRef, len(*in))
@@ -667,6 +692,11 @@ func (in *ClusterComponentSpec) DeepCopyInto(out *ClusterComponentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PodSpecPatch != nil {
+		in, out := &in.PodSpecPatch, &out.PodSpecPatch
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentSpec.
@@ -770,7 +800,7 @@ func (in *ClusterDefinition) DeepCopyInto(out *ClusterDefinition) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefinition.
@@ -831,6 +861,16 @@ func (in *ClusterDefinitionProbe) DeepCopyInto(out *ClusterDefinitionProbe) {
 		*out = new(ClusterDefinitionProbeCMDs)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(v1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(v1.GRPCAction)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefinitionProbe.
@@ -930,6 +970,18 @@ func (in *ClusterDefinitionSpec) DeepCopy() *ClusterDefinitionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDefinitionStatus) DeepCopyInto(out *ClusterDefinitionStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentDefStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefinitionStatus.
@@ -1333,6 +1385,14 @@ func (in *CommandExecutorEnvItem) DeepCopyInto(out *CommandExecutorEnvItem) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandExecutorEnvItem.
@@ -1640,6 +1700,21 @@ func (in *ComponentDefRef) DeepCopy() *ComponentDefRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentDefStatus) DeepCopyInto(out *ComponentDefStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentDefStatus.
+func (in *ComponentDefStatus) DeepCopy() *ComponentDefStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentDefStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentDefinition) DeepCopyInto(out *ComponentDefinition) {
 	*out = *in
@@ -2227,6 +2302,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PodSpecPatch != nil {
+		in, out := &in.PodSpecPatch, &out.PodSpecPatch
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
@@ -2256,6 +2336,26 @@ func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.LastSwitchoverTime != nil {
+		in, out := &in.LastSwitchoverTime, &out.LastSwitchoverTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduledRestartTime != nil {
+		in, out := &in.LastScheduledRestartTime, &out.LastScheduledRestartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LockedVolumes != nil {
+		in, out := &in.LockedVolumes, &out.LockedVolumes
+		*out = make([]LockedVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.StatefulSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
@@ -2286,6 +2386,21 @@ func (in *ComponentSwitchover) DeepCopyInto(out *ComponentSwitchover) {
 		*out = make([]ScriptSpecSelector, len(*in))
 		copy(*out, *in)
 	}
+	if in.PreCheck != nil {
+		in, out := &in.PreCheck, &out.PreCheck
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(Action)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ParametersSchema != nil {
+		in, out := &in.ParametersSchema, &out.ParametersSchema
+		*out = new(ParametersSchema)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSwitchover.
@@ -2976,15 +3091,23 @@ func (in *CredentialVars) DeepCopy() *CredentialVars {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CustomLabelSpec) DeepCopyInto(out *CustomLabelSpec) {
+func (in *CustomAnnotationSpec) DeepCopyInto(out *CustomAnnotationSpec) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]GVKResource, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomAnnotationSpec.
+func (in *CustomAnnotationSpec) DeepCopy() *CustomAnnotationSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(CustomAnnotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomLabelSpec) DeepCopyInto(out *CustomLabelSpec) {
+	*out = *in
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomLabelSpec.
@@ -3171,6 +3294,11 @@ func (in *ExecAction) DeepCopy() *ExecAction {
 func (in *ExporterConfig) DeepCopyInto(out *ExporterConfig) {
 	*out = *in
 	out.ScrapePort = in.ScrapePort
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(ExporterTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterConfig.
@@ -3183,6 +3311,26 @@ func (in *ExporterConfig) DeepCopy() *ExporterConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExporterTLSConfig) DeepCopyInto(out *ExporterTLSConfig) {
+	*out = *in
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterTLSConfig.
+func (in *ExporterTLSConfig) DeepCopy() *ExporterTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExporterTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Expose) DeepCopyInto(out *Expose) {
 	*out = *in
@@ -3243,44 +3391,42 @@ func (in *FormatterOptions) DeepCopy() *FormatterOptions {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GVKResource) DeepCopyInto(out *GVKResource) {
+func (in *HTTPAction) DeepCopyInto(out *HTTPAction) {
 	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out.Port = in.Port
+	if in.HTTPHeaders != nil {
+		in, out := &in.HTTPHeaders, &out.HTTPHeaders
+		*out = make([]v1.HTTPHeader, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GVKResource.
-func (in *GVKResource) DeepCopy() *GVKResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAction.
+func (in *HTTPAction) DeepCopy() *HTTPAction {
 	if in == nil {
 		return nil
 	}
-	out := new(GVKResource)
+	out := new(HTTPAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPAction) DeepCopyInto(out *HTTPAction) {
+func (in *HeadlessServiceSpec) DeepCopyInto(out *HeadlessServiceSpec) {
 	*out = *in
-	out.Port = in.Port
-	if in.HTTPHeaders != nil {
-		in, out := &in.HTTPHeaders, &out.HTTPHeaders
-		*out = make([]v1.HTTPHeader, len(*in))
-		copy(*out, *in)
+	if in.PublishNotReadyAddresses != nil {
+		in, out := &in.PublishNotReadyAddresses, &out.PublishNotReadyAddresses
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAction.
-func (in *HTTPAction) DeepCopy() *HTTPAction {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadlessServiceSpec.
+func (in *HeadlessServiceSpec) DeepCopy() *HeadlessServiceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPAction)
+	out := new(HeadlessServiceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3541,6 +3687,26 @@ func (in *LifecycleActionHandler) DeepCopy() *LifecycleActionHandler {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockedVolume) DeepCopyInto(out *LockedVolume) {
+	*out = *in
+	if in.UsagePercentage != nil {
+		in, out := &in.UsagePercentage, &out.UsagePercentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockedVolume.
+func (in *LockedVolume) DeepCopy() *LockedVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(LockedVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogConfig) DeepCopyInto(out *LogConfig) {
 	*out = *in
@@ -3556,6 +3722,22 @@ func (in *LogConfig) DeepCopy() *LogConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MatchExpressions) DeepCopyInto(out *MatchExpressions) {
 	*out = *in
@@ -3607,7 +3789,7 @@ func (in *MonitorConfig) DeepCopyInto(out *MonitorConfig) {
 	if in.Exporter != nil {
 		in, out := &in.Exporter, &out.Exporter
 		*out = new(ExporterConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -3914,6 +4096,13 @@ func (in *OpsRequestComponentStatus) DeepCopyInto(out *OpsRequestComponentStatus
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsRequestComponentStatus.
@@ -3988,7 +4177,9 @@ func (in *OpsRequestSpec) DeepCopyInto(out *OpsRequestSpec) {
 	if in.SwitchoverList != nil {
 		in, out := &in.SwitchoverList, &out.SwitchoverList
 		*out = make([]Switchover, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.VerticalScalingList != nil {
 		in, out := &in.VerticalScalingList, &out.VerticalScalingList
@@ -4325,6 +4516,41 @@ func (in *PasswordConfig) DeepCopy() *PasswordConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordConfigOverride) DeepCopyInto(out *PasswordConfigOverride) {
+	*out = *in
+	if in.Length != nil {
+		in, out := &in.Length, &out.Length
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumDigits != nil {
+		in, out := &in.NumDigits, &out.NumDigits
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumSymbols != nil {
+		in, out := &in.NumSymbols, &out.NumSymbols
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LetterCase != nil {
+		in, out := &in.LetterCase, &out.LetterCase
+		*out = new(LetterCase)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordConfigOverride.
+func (in *PasswordConfigOverride) DeepCopy() *PasswordConfigOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordConfigOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Payload.
 func (in *Payload) DeepCopy() *Payload {
 	if in == nil {
@@ -4556,6 +4782,11 @@ func (in *ProtectedVolume) DeepCopyInto(out *ProtectedVolume) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.LowWatermark != nil {
+		in, out := &in.LowWatermark, &out.LowWatermark
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolume.
@@ -4809,6 +5040,11 @@ func (in *ReplicasLimit) DeepCopy() *ReplicasLimit {
 func (in *ReplicationSetSpec) DeepCopyInto(out *ReplicationSetSpec) {
 	*out = *in
 	in.StatefulSetSpec.DeepCopyInto(&out.StatefulSetSpec)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationSetSpec.
@@ -4965,6 +5201,31 @@ func (in *SchedulePolicy) DeepCopy() *SchedulePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledRestartPolicy) DeepCopyInto(out *ScheduledRestartPolicy) {
+	*out = *in
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(UpdateStrategy)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRestartPolicy.
+func (in *ScheduledRestartPolicy) DeepCopy() *ScheduledRestartPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledRestartPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScriptConfig) DeepCopyInto(out *ScriptConfig) {
 	*out = *in
@@ -5263,6 +5524,16 @@ func (in *ServiceRefDeclaration) DeepCopyInto(out *ServiceRefDeclaration) {
 		*out = make([]ServiceRefDeclarationSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.Optional != nil {
+		in, out := &in.Optional, &out.Optional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultEndpoint != nil {
+		in, out := &in.DefaultEndpoint, &out.DefaultEndpoint
+		*out = new(ServiceRefDefaultEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceRefDeclaration.
@@ -5290,6 +5561,26 @@ func (in *ServiceRefDeclarationSpec) DeepCopy() *ServiceRefDeclarationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceRefDefaultEndpoint) DeepCopyInto(out *ServiceRefDefaultEndpoint) {
+	*out = *in
+	if in.CredentialSecretRef != nil {
+		in, out := &in.CredentialSecretRef, &out.CredentialSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceRefDefaultEndpoint.
+func (in *ServiceRefDefaultEndpoint) DeepCopy() *ServiceRefDefaultEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceRefDefaultEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceRefVarSelector) DeepCopyInto(out *ServiceRefVarSelector) {
 	*out = *in
@@ -5343,6 +5634,11 @@ func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Headless != nil {
+		in, out := &in.Headless, &out.Headless
+		*out = new(HeadlessServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
@@ -5508,6 +5804,13 @@ func (in *StorageConstraint) DeepCopy() *StorageConstraint {
 func (in *Switchover) DeepCopyInto(out *Switchover) {
 	*out = *in
 	out.ComponentOps = in.ComponentOps
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Switchover.
@@ -5578,6 +5881,21 @@ func (in *SwitchoverSpec) DeepCopyInto(out *SwitchoverSpec) {
 		*out = new(SwitchoverAction)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PreCheck != nil {
+		in, out := &in.PreCheck, &out.PreCheck
+		*out = new(CmdExecutorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostCheck != nil {
+		in, out := &in.PostCheck, &out.PostCheck
+		*out = new(CmdExecutorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ParametersSchema != nil {
+		in, out := &in.ParametersSchema, &out.ParametersSchema
+		*out = new(ParametersSchema)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverSpec.
@@ -5615,6 +5933,11 @@ func (in *SystemAccount) DeepCopy() *SystemAccount {
 func (in *SystemAccountConfig) DeepCopyInto(out *SystemAccountConfig) {
 	*out = *in
 	in.ProvisionPolicy.DeepCopyInto(&out.ProvisionPolicy)
+	if in.PasswordConfig != nil {
+		in, out := &in.PasswordConfig, &out.PasswordConfig
+		*out = new(PasswordConfigOverride)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemAccountConfig.
@@ -117,6 +117,21 @@ type BackupActionSpec struct {
 	//
 	// +optional
 	PreDeleteBackup *BaseJobActionSpec `json:"preDelete,omitempty"`
+
+	// Represents a custom action to verify the backup data stored in the backup repository,
+	// it is executed periodically according to the verification policy of the backup policy.
+	// The backup is considered unrestorable if the action job fails.
+	//
+	// +optional
+	VerifyBackup *BaseJobActionSpec `json:"verify,omitempty"`
+
+	// Represents a custom action to trim the data of a continuous backup which is out of
+	// the retention period, it is executed periodically while the backup keeps running.
+	// The data before the time specified by the `DP_BACKUP_TRIM_BEFORE_TIME` env can be deleted.
+	// The backup workload is scaled down to zero while trimming.
+	//
+	// +optional
+	TrimBackup *BaseJobActionSpec `json:"trim,omitempty"`
 }
 
 // BackupDataActionSpec defines how to back up data.
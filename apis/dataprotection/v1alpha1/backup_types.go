@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // BackupSpec defines the desired state of Backup.
@@ -70,10 +71,67 @@ type BackupSpec struct {
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
 
 	// Determines the parent backup name for incremental or differential backup.
+	// If it is empty for an incremental backup, the latest completed full backup
+	// of the same backup policy is used as the parent backup.
 	//
 	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.parentBackupName"
 	ParentBackupName string `json:"parentBackupName,omitempty"`
+
+	// Specifies the maximum duration the backup is allowed to run, counted from
+	// status.startTimestamp. If the backup is not completed within the deadline,
+	// it will be marked as Failed and its workloads will be deleted.
+	// Continuous backups are not affected by this field.
+	// When converted to a string, the format is "1h2m0.5s".
+	//
+	// +optional
+	CompletionDeadline *metav1.Duration `json:"completionDeadline,omitempty"`
+
+	// Specifies how long the backup contents in the backup repository are kept after
+	// the backup custom resource(CR) is deleted, only takes effect when the deletionPolicy
+	// is `Delete`. It allows to undo the accidental deletion of the backup CR.
+	// The grace period can be skipped by setting the annotation
+	// `dataprotection.kubeblocks.io/skip-deletion-grace-period` to `true` on the backup.
+	// When converted to a string, the format is "1h2m0.5s".
+	//
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+
+	// Specifies whether to only validate the prerequisites of the backup without running it.
+	// If set to true, the backup policy, backup method, encryption config, target pods and
+	// backup repository will be validated, and the backup will be marked as Completed with
+	// a condition summarizing the actions that would be executed. No workloads or volume
+	// snapshots will be created and nothing will be written to the backup repository.
+	//
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.dryRun"
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Specifies whether to pause the running continuous backup, e.g. during a storage migration.
+	// When paused, the backup workload is scaled down to zero and the backup keeps in the Running
+	// phase with a Paused condition. The backup workload is scaled back up when resumed.
+	// Only takes effect for the continuous backup.
+	//
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Specifies whether to allow backing up from a target pod which is not ready, e.g. backing up
+	// a crashed replica whose volumes are still mounted for forensics. By default, the backup fails
+	// if none of the selected target pods is ready. A TargetReady condition is recorded if the backup
+	// is taken from a not ready pod. The readiness is not required for the backup methods which take
+	// volume snapshots, since they do not exec into the target pod.
+	//
+	// +optional
+	AllowNotReadyTarget bool `json:"allowNotReadyTarget,omitempty"`
+
+	// Overrides the encryption config of the backup policy for this backup, it is only allowed
+	// if `allowEncryptionConfigOverride` of the backup policy is true. The effective encryption
+	// config is recorded in `status.encryptionConfig`, which is used to decrypt the backup data
+	// when restoring, so the backups remain restorable after the key of the policy is rotated.
+	//
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.encryptionConfig"
+	EncryptionConfig *EncryptionConfig `json:"encryptionConfig,omitempty"`
 }
 
 // BackupStatus defines the observed state of Backup.
@@ -120,11 +178,25 @@ type BackupStatus struct {
 	// +optional
 	TotalSize string `json:"totalSize,omitempty"`
 
+	// The progress of the backup in percentage, it's the mean of the progress of the actions
+	// which back up the data, the backup hooks are not counted.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Progress int32 `json:"progress,omitempty"`
+
 	// Any error that caused the backup operation to fail.
 	//
 	// +optional
 	FailureReason string `json:"failureReason,omitempty"`
 
+	// The code of the failure reason, which is used to handle the failures by automation,
+	// such as retrying the backup on the transient failures.
+	//
+	// +optional
+	FailureCode BackupFailureCode `json:"failureCode,omitempty"`
+
 	// The name of the backup repository.
 	//
 	// +optional
@@ -141,6 +213,19 @@ type BackupStatus struct {
 	// +optional
 	KopiaRepoPath string `json:"kopiaRepoPath,omitempty"`
 
+	// Records the name of the parent backup for an incremental backup, which is either
+	// specified by spec.parentBackupName or resolved to the latest completed full backup
+	// of the same backup policy.
+	//
+	// +optional
+	ParentBackupName string `json:"parentBackupName,omitempty"`
+
+	// Records the UID of the full backup at the root of the backup chain for an incremental
+	// backup, the restore assembles the backup chain from it.
+	//
+	// +optional
+	BaseBackupUID types.UID `json:"baseBackupUID,omitempty"`
+
 	// Records the name of the persistent volume claim used to store the backup data.
 	//
 	// +optional
@@ -152,6 +237,40 @@ type BackupStatus struct {
 	// +optional
 	TimeRange *BackupTimeRange `json:"timeRange,omitempty"`
 
+	// Records the time of the latest data synced by the continuous backup, which is the end
+	// of the time range published by the backup workload. Only available for continuous backups.
+	//
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Records how far the continuous backup lags behind, which is the duration between the
+	// last reconciliation and the lastSyncTime. Only available for continuous backups.
+	//
+	// +optional
+	LatestReplicationLag *metav1.Duration `json:"latestReplicationLag,omitempty"`
+
+	// Records why the backup is completed. It is `ActionsCompleted` if all the actions of the backup are completed,
+	// otherwise it records why the continuous backup is closed by the controller.
+	//
+	// +optional
+	CompletionReason BackupCompletionReason `json:"completionReason,omitempty"`
+
+	// Records the number of restores which have restored data from this backup.
+	//
+	// +optional
+	UsedByRestores int32 `json:"usedByRestores,omitempty"`
+
+	// Records the time when the last restore from this backup was started.
+	//
+	// +optional
+	LastRestoreTime *metav1.Time `json:"lastRestoreTime,omitempty"`
+
+	// Records the UID of the last restore counted in `usedByRestores`,
+	// it avoids counting the same restore again when the restore is retried.
+	//
+	// +optional
+	LastRestoreUID types.UID `json:"lastRestoreUID,omitempty"`
+
 	// Records the target information for this backup.
 	//
 	// +optional
@@ -163,7 +282,30 @@ type BackupStatus struct {
 	// +optional
 	BackupMethod *BackupMethod `json:"backupMethod,omitempty"`
 
-	// Records the encryption config for this backup.
+	// Records the name of the ActionSet used by this backup.
+	//
+	// +optional
+	ActionSetName string `json:"actionSetName,omitempty"`
+
+	// Records the generation of the ActionSet when this backup was taken.
+	//
+	// +optional
+	ActionSetGeneration int64 `json:"actionSetGeneration,omitempty"`
+
+	// Records the hash of the ActionSet spec when this backup was taken.
+	// It is compared with the hash of the current ActionSet when restoring from this backup,
+	// as the backup may not be restorable after the ActionSet is changed.
+	//
+	// +optional
+	ActionSetHash string `json:"actionSetHash,omitempty"`
+
+	// Records the names of the target pods that contributed data to this backup.
+	//
+	// +optional
+	TargetPods []string `json:"targetPods,omitempty"`
+
+	// Records the encryption config for this backup, it is either overridden by `spec.encryptionConfig`
+	// or inherited from the backup policy when the backup is started.
 	//
 	// +optional
 	EncryptionConfig *EncryptionConfig `json:"encryptionConfig,omitempty"`
@@ -178,10 +320,94 @@ type BackupStatus struct {
 	// +optional
 	VolumeSnapshots []VolumeSnapshotStatus `json:"volumeSnapshots,omitempty"`
 
+	// Records the status of the copies of the backup in other backup repositories,
+	// which are specified by the `copyTo` of the backup policy.
+	//
+	// +optional
+	Copies []BackupCopyStatus `json:"copies,omitempty"`
+
 	// Records any additional information for the backup.
 	//
 	// +optional
 	Extras []map[string]string `json:"extras,omitempty"`
+
+	// Describes the current state of the backup API Resource, like the result of a dry-run.
+	//
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Records the result of the latest verification of the backup.
+	//
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+}
+
+// BackupVerificationResult defines the result of the backup verification.
+// +enum
+// +kubebuilder:validation:Enum={Passed,Failed}
+type BackupVerificationResult string
+
+const (
+	BackupVerificationResultPassed BackupVerificationResult = "Passed"
+	BackupVerificationResultFailed BackupVerificationResult = "Failed"
+)
+
+// BackupVerificationStatus records the result of the backup verification.
+type BackupVerificationStatus struct {
+	// Records the time when the backup was verified last time.
+	//
+	// +optional
+	LastVerifiedTime *metav1.Time `json:"lastVerifiedTime,omitempty"`
+
+	// Records the result of the latest verification.
+	//
+	// +optional
+	Result BackupVerificationResult `json:"result,omitempty"`
+
+	// Provides the details of the latest verification, such as the cause of the failure.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BackupCopyPhase describes the lifecycle phase of a copy of the backup.
+// +enum
+// +kubebuilder:validation:Enum={Pending,Running,Completed,Failed}
+type BackupCopyPhase string
+
+const (
+	BackupCopyPhasePending   BackupCopyPhase = "Pending"
+	BackupCopyPhaseRunning   BackupCopyPhase = "Running"
+	BackupCopyPhaseCompleted BackupCopyPhase = "Completed"
+	BackupCopyPhaseFailed    BackupCopyPhase = "Failed"
+)
+
+// BackupCopyStatus records the status of a copy of the backup in another backup repository.
+type BackupCopyStatus struct {
+	// The name of the backup repository the backup is copied to.
+	//
+	// +kubebuilder:validation:Required
+	RepoName string `json:"repoName"`
+
+	// Indicates the current state of the copy.
+	//
+	// +optional
+	Phase BackupCopyPhase `json:"phase,omitempty"`
+
+	// Records the total size of the data copied.
+	//
+	// +optional
+	TotalSize string `json:"totalSize,omitempty"`
+
+	// Records the time when the copy was completed.
+	//
+	// +optional
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+
+	// Any error that caused the copy to fail.
+	//
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
 }
 
 // BackupTimeRange records the time range of backed up data, for PITR, this is the
@@ -238,6 +464,75 @@ const (
 	BackupPhaseDeleting BackupPhase = "Deleting"
 )
 
+// BackupFailureCode describes the category of the failure of a Backup.
+// +enum
+// +kubebuilder:validation:Enum={RepoNotReady,TargetPodNotFound,TargetPodNotReady,TargetContainerNotFound,ActionFailed,DeadlineExceeded,QuotaExceeded,RepoOutOfSpace,EncryptionKeyMissing,PathCollision,SnapshotClassMismatch,Unknown}
+type BackupFailureCode string
+
+const (
+	// BackupFailureCodeRepoNotReady means the backup repository is not ready.
+	BackupFailureCodeRepoNotReady BackupFailureCode = "RepoNotReady"
+
+	// BackupFailureCodeTargetPodNotFound means no pods matched the target pod selector.
+	BackupFailureCodeTargetPodNotFound BackupFailureCode = "TargetPodNotFound"
+
+	// BackupFailureCodeTargetPodNotReady means the pods matched the target pod selector are not ready.
+	BackupFailureCodeTargetPodNotReady BackupFailureCode = "TargetPodNotReady"
+
+	// BackupFailureCodeTargetContainerNotFound means the target container of the backup method is not found in the target pod.
+	BackupFailureCodeTargetContainerNotFound BackupFailureCode = "TargetContainerNotFound"
+
+	// BackupFailureCodeActionFailed means an action of the backup failed.
+	BackupFailureCodeActionFailed BackupFailureCode = "ActionFailed"
+
+	// BackupFailureCodeDeadlineExceeded means the backup was not completed within the completion deadline.
+	BackupFailureCodeDeadlineExceeded BackupFailureCode = "DeadlineExceeded"
+
+	// BackupFailureCodeQuotaExceeded means the quota of the backup repository is exceeded.
+	BackupFailureCodeQuotaExceeded BackupFailureCode = "QuotaExceeded"
+
+	// BackupFailureCodeRepoOutOfSpace means the free space of the backup repository is not enough for the backup.
+	BackupFailureCodeRepoOutOfSpace BackupFailureCode = "RepoOutOfSpace"
+
+	// BackupFailureCodeEncryptionKeyMissing means the encryption key of the backup is not found.
+	BackupFailureCodeEncryptionKeyMissing BackupFailureCode = "EncryptionKeyMissing"
+
+	// BackupFailureCodePathCollision means the backup path is used by another backup.
+	BackupFailureCodePathCollision BackupFailureCode = "PathCollision"
+
+	// BackupFailureCodeSnapshotClassMismatch means the VolumeSnapshotClass of the backup method is not found,
+	// or its driver doesn't match the provisioner of the target volumes.
+	BackupFailureCodeSnapshotClassMismatch BackupFailureCode = "SnapshotClassMismatch"
+
+	// BackupFailureCodeUnknown means the failure is not categorized.
+	BackupFailureCodeUnknown BackupFailureCode = "Unknown"
+)
+
+// BackupCompletionReason describes why a backup is completed.
+// +enum
+// +kubebuilder:validation:Enum={ActionsCompleted,ScheduleDisabled,ClusterDeleted,Manual,DryRun}
+type BackupCompletionReason string
+
+const (
+	// BackupCompletionReasonActionsCompleted means all the actions of the backup are completed.
+	BackupCompletionReasonActionsCompleted BackupCompletionReason = "ActionsCompleted"
+
+	// BackupCompletionReasonScheduleDisabled means the continuous backup method is disabled
+	// in the backup schedule, the backup is resumed if the method is enabled again.
+	BackupCompletionReasonScheduleDisabled BackupCompletionReason = "ScheduleDisabled"
+
+	// BackupCompletionReasonClusterDeleted means the target cluster of the backup has been deleted.
+	BackupCompletionReasonClusterDeleted BackupCompletionReason = "ClusterDeleted"
+
+	// BackupCompletionReasonManual means the continuous backup is not managed by any backup
+	// schedule, such as the backup created manually.
+	BackupCompletionReasonManual BackupCompletionReason = "Manual"
+
+	// BackupCompletionReasonDryRun means the backup is a dry run, only the prerequisites are validated,
+	// it has no backup data and can't be restored.
+	BackupCompletionReasonDryRun BackupCompletionReason = "DryRun"
+)
+
 type ActionStatus struct {
 	// The name of the action.
 	//
@@ -286,6 +581,21 @@ type ActionStatus struct {
 	// +optional
 	TotalSize string `json:"totalSize,omitempty"`
 
+	// The progress of the action in percentage. For the job action, it's published by the backup job
+	// through the annotation `dataprotection.kubeblocks.io/progress` of the job.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Progress int32 `json:"progress,omitempty"`
+
+	// The size of the data processed by the action so far.
+	// A string with capacity units in the format of "1Gi", "1Mi", "1Ki".
+	// If no capacity unit is specified, it is assumed to be in bytes.
+	//
+	// +optional
+	ProcessedSize string `json:"processedSize,omitempty"`
+
 	// Records the time range of backed up data, for PITR, this is the time
 	// range of recoverable data.
 	//
@@ -296,6 +606,18 @@ type ActionStatus struct {
 	//
 	// +optional
 	VolumeSnapshots []VolumeSnapshotStatus `json:"volumeSnapshots,omitempty"`
+
+	// Records the number of times the failed action has been retried. For the
+	// statefulSet action, it is the restart count of the backup pod since it was
+	// last running stably for 10 minutes.
+	//
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty"`
+
+	// Records the time the failed action will be retried.
+	//
+	// +optional
+	NextRetryTimestamp *metav1.Time `json:"nextRetryTimestamp,omitempty"`
 }
 
 type VolumeSnapshotStatus struct {
@@ -357,8 +679,9 @@ const (
 // +kubebuilder:printcolumn:name="TOTAL-SIZE",type=string,JSONPath=`.status.totalSize`
 // +kubebuilder:printcolumn:name="DURATION",type=string,JSONPath=`.status.duration`
 // +kubebuilder:printcolumn:name="CREATION-TIME",type=string,JSONPath=".metadata.creationTimestamp"
-// +kubebuilder:printcolumn:name="COMPLETION-TIME",type=string,JSONPath=`.status.completionTimestamp`
+// +kubebuilder:printcolumn:name="COMPLETION-TIME",type=date,JSONPath=`.status.completionTimestamp`
 // +kubebuilder:printcolumn:name="EXPIRATION-TIME",type=string,JSONPath=`.status.expiration`
+// +kubebuilder:printcolumn:name="CREATED-BY",type=string,JSONPath=`.metadata.annotations.dataprotection\.kubeblocks\.io/created-by`
 
 // Backup is the Schema for the backups API.
 type Backup struct {
@@ -402,6 +725,12 @@ func (r *Backup) GetEndTime() *metav1.Time {
 	return s.CompletionTimestamp
 }
 
+// IsDryRun checks whether the backup is a dry run, which has no backup data and can't be restored,
+// or used as the parent backup.
+func (r *Backup) IsDryRun() bool {
+	return r.Spec.DryRun || r.Status.CompletionReason == BackupCompletionReasonDryRun
+}
+
 func (r *Backup) GetTimeZone() string {
 	s := r.Status
 	if s.TimeRange != nil {
@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// log is for logging in this package.
+var backuplog = logf.Log.WithName("backup-resource")
+
+// auditAnnotationKeys are the annotations recording the creator of the backup, they can't be changed once set.
+var auditAnnotationKeys = []string{dptypes.CreatedByAnnotationKey, dptypes.RequestedByAnnotationKey}
+
+func (r *Backup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&backupAuditor{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-dataprotection-kubeblocks-io-v1alpha1-backup,mutating=true,failurePolicy=fail,sideEffects=None,groups=dataprotection.kubeblocks.io,resources=backups,verbs=create;update,versions=v1alpha1,name=mbackup.kb.io,admissionReviewVersions=v1
+
+// backupAuditor records the creator of the backups with the user info of the admission requests.
+type backupAuditor struct{}
+
+var _ webhook.CustomDefaulter = &backupAuditor{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (a *backupAuditor) Default(ctx context.Context, obj runtime.Object) error {
+	backup := obj.(*Backup)
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	backuplog.Info("default", "name", backup.Name, "operation", req.Operation)
+	switch req.Operation {
+	case admissionv1.Create:
+		backup.setCreatedBy(req.UserInfo.Username)
+	case admissionv1.Update:
+		oldBackup := &Backup{}
+		if err = json.Unmarshal(req.OldObject.Raw, oldBackup); err != nil {
+			return err
+		}
+		backup.keepAuditAnnotations(oldBackup)
+	}
+	return nil
+}
+
+// setCreatedBy records the requesting user as the creator of the backup. The creator set by the
+// KubeBlocks service account, e.g. for the schedules and the OpsRequests, is kept, but the one set by
+// the other users and service accounts is overwritten, so they can't take backups on behalf of the others.
+func (r *Backup) setCreatedBy(username string) {
+	if r.Annotations == nil {
+		r.Annotations = map[string]string{}
+	}
+	if r.Annotations[dptypes.CreatedByAnnotationKey] != "" && isKubeBlocksServiceAccount(username) {
+		return
+	}
+	r.Annotations[dptypes.CreatedByAnnotationKey] = dptypes.CreatedByUserPrefix + username
+	delete(r.Annotations, dptypes.RequestedByAnnotationKey)
+}
+
+// keepAuditAnnotations restores the audit annotations of the old backup, which are added, removed or changed by the update.
+func (r *Backup) keepAuditAnnotations(oldBackup *Backup) {
+	for _, key := range auditAnnotationKeys {
+		oldValue, ok := oldBackup.Annotations[key]
+		if !ok {
+			delete(r.Annotations, key)
+			continue
+		}
+		if r.Annotations == nil {
+			r.Annotations = map[string]string{}
+		}
+		r.Annotations[key] = oldValue
+	}
+}
+
+// isKubeBlocksServiceAccount checks if the user is the service account of the KubeBlocks controllers.
+func isKubeBlocksServiceAccount(username string) bool {
+	saName := viper.GetString(constant.KBServiceAccountName)
+	if saName == "" {
+		return false
+	}
+	return username == fmt.Sprintf("system:serviceaccount:%s:%s", viper.GetString(constant.CfgKeyCtrlrMgrNS), saName)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+func TestBackupAuditorDefault(t *testing.T) {
+	newBackup := func(annotations map[string]string) *Backup {
+		return &Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: "default", Annotations: annotations},
+			Spec:       BackupSpec{BackupPolicyName: "test-policy", BackupMethod: "xtrabackup"},
+		}
+	}
+	newContext := func(operation admissionv1.Operation, username string, oldBackup *Backup) context.Context {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			UserInfo:  authenticationv1.UserInfo{Username: username},
+		}}
+		if oldBackup != nil {
+			raw, err := json.Marshal(oldBackup)
+			assert.NoError(t, err)
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return admission.NewContextWithRequest(context.Background(), req)
+	}
+	auditor := &backupAuditor{}
+	const serviceAccount = "system:serviceaccount:kb-system:kubeblocks"
+	viper.Set(constant.CfgKeyCtrlrMgrNS, "kb-system")
+	viper.Set(constant.KBServiceAccountName, "kubeblocks")
+	defer func() {
+		viper.Set(constant.CfgKeyCtrlrMgrNS, "")
+		viper.Set(constant.KBServiceAccountName, "")
+	}()
+
+	t.Run("created by user", func(t *testing.T) {
+		backup := newBackup(nil)
+		assert.NoError(t, auditor.Default(newContext(admissionv1.Create, "alice", nil), backup))
+		assert.Equal(t, "user/alice", backup.Annotations[dptypes.CreatedByAnnotationKey])
+	})
+
+	t.Run("created by schedule", func(t *testing.T) {
+		backup := newBackup(map[string]string{dptypes.CreatedByAnnotationKey: "schedule/test-schedule"})
+		assert.NoError(t, auditor.Default(newContext(admissionv1.Create, serviceAccount, nil), backup))
+		assert.Equal(t, "schedule/test-schedule", backup.Annotations[dptypes.CreatedByAnnotationKey])
+	})
+
+	t.Run("creator set by user is overwritten", func(t *testing.T) {
+		backup := newBackup(map[string]string{
+			dptypes.CreatedByAnnotationKey:   "opsrequest/test-ops",
+			dptypes.RequestedByAnnotationKey: "bob",
+		})
+		assert.NoError(t, auditor.Default(newContext(admissionv1.Create, "alice", nil), backup))
+		assert.Equal(t, "user/alice", backup.Annotations[dptypes.CreatedByAnnotationKey])
+		assert.NotContains(t, backup.Annotations, dptypes.RequestedByAnnotationKey)
+	})
+
+	t.Run("creator set by other service account is overwritten", func(t *testing.T) {
+		const otherServiceAccount = "system:serviceaccount:default:test-sa"
+		backup := newBackup(map[string]string{dptypes.CreatedByAnnotationKey: "schedule/test-schedule"})
+		assert.NoError(t, auditor.Default(newContext(admissionv1.Create, otherServiceAccount, nil), backup))
+		assert.Equal(t, "user/"+otherServiceAccount, backup.Annotations[dptypes.CreatedByAnnotationKey])
+	})
+
+	t.Run("audit annotations are kept by updates", func(t *testing.T) {
+		oldBackup := newBackup(map[string]string{
+			dptypes.CreatedByAnnotationKey:   "opsrequest/test-ops",
+			dptypes.RequestedByAnnotationKey: "bob",
+		})
+		backup := newBackup(map[string]string{dptypes.CreatedByAnnotationKey: "user/alice"})
+		assert.NoError(t, auditor.Default(newContext(admissionv1.Update, "alice", oldBackup), backup))
+		assert.Equal(t, oldBackup.Annotations, backup.Annotations)
+
+		// the audit annotations can't be added to the existing backups
+		backup = newBackup(map[string]string{dptypes.CreatedByAnnotationKey: "user/alice"})
+		assert.NoError(t, auditor.Default(newContext(admissionv1.Update, "alice", newBackup(nil)), backup))
+		assert.Empty(t, backup.Annotations)
+	})
+}
@@ -30,6 +30,29 @@ type BackupPolicySpec struct {
 	// +optional
 	BackupRepoName *string `json:"backupRepoName,omitempty"`
 
+	// Specifies an ordered list of BackupRepos to fall back to when the backup repository
+	// in use is not ready within `backupRepoFailoverTimeout`. The backup is retried against
+	// the next repository in the list, and the one finally used is recorded in the backup status.
+	//
+	// +listType=set
+	// +optional
+	FallbackBackupRepoNames []string `json:"fallbackBackupRepoNames,omitempty"`
+
+	// Specifies how long to wait for a backup repository to become ready before failing over
+	// to the next one in `fallbackBackupRepoNames`. Defaults to 5 minutes.
+	//
+	// +optional
+	BackupRepoFailoverTimeout *metav1.Duration `json:"backupRepoFailoverTimeout,omitempty"`
+
+	// Specifies the names of the BackupRepos to copy the backup data to after the backup is completed,
+	// e.g. to keep the backups in multiple geographic locations. The status of each copy is recorded
+	// in the `status.copies` of the backup, and a failed copy does not fail the backup itself.
+	// The copies are deleted with the backup unless the deletion policy of the backup is Retain.
+	//
+	// +listType=set
+	// +optional
+	CopyTo []string `json:"copyTo,omitempty"`
+
 	// Specifies the directory inside the backup repository to store the backup.
 	// This path is relative to the path of the backup repository.
 	//
@@ -43,6 +66,15 @@ type BackupPolicySpec struct {
 	// +kubebuilder:validation:Maximum=10
 	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
 
+	// Determines the duration for which the failed backups should be kept, counted from the
+	// time the backup failed. It allows the failed backups to be garbage-collected sooner than
+	// the successful ones, which are still kept for their retention period. If it is not set,
+	// the failed backups are kept for their retention period too.
+	// It does not take effect on the backups whose deletionPolicy is `Retain`.
+	//
+	// +optional
+	FailedRetentionPeriod RetentionPeriod `json:"failedRetentionPeriod,omitempty"`
+
 	// Specifies the target information to back up, such as the target pod, the
 	// cluster connection credential.
 	//
@@ -72,6 +104,97 @@ type BackupPolicySpec struct {
 	//
 	// +optional
 	EncryptionConfig *EncryptionConfig `json:"encryptionConfig,omitempty"`
+
+	// Specifies whether the backups of the policy are allowed to override the encryption config
+	// with their own `spec.encryptionConfig`, e.g. to encrypt the new backups with a rotated key
+	// while the existing backups remain decryptable with the key recorded in their status.
+	//
+	// +optional
+	AllowEncryptionConfigOverride bool `json:"allowEncryptionConfigOverride,omitempty"`
+
+	// Specifies the maximum acceptable replication lag of the continuous backups.
+	// A warning event will be emitted if the latestReplicationLag of a continuous
+	// backup exceeds this threshold. No check will be performed if it is not set.
+	//
+	// +optional
+	ReplicationLagThreshold *metav1.Duration `json:"replicationLagThreshold,omitempty"`
+
+	// Specifies the scheduling constraints of the worker pods, including the backup jobs
+	// and the continuous backup statefulset, to keep them away from the database pods.
+	// It does not affect the actions executed in the target pod.
+	//
+	// +optional
+	WorkerPodSpec *WorkerPodSpec `json:"workerPodSpec,omitempty"`
+
+	// Specifies the pre-created service account to run the worker pods, including the backup
+	// jobs, the continuous backup statefulset and the jobs deleting the backup files.
+	// It allows the worker pods to access the backup repo by the workload identity bound to
+	// the service account. The service account must exist in the namespace of the backup and
+	// be bound to the required roles.
+	// If it is not set, the worker service account managed by the controller is used.
+	//
+	// +optional
+	WorkerServiceAccountName string `json:"workerServiceAccountName,omitempty"`
+
+	// Specifies how to verify the completed backups periodically, to find out the backups
+	// which can not be restored, such as the backups with corrupted archives.
+	//
+	// +optional
+	VerificationPolicy *VerificationPolicy `json:"verificationPolicy,omitempty"`
+}
+
+// VerificationPolicy defines how the completed backups are verified periodically.
+type VerificationPolicy struct {
+	// Specifies whether the verification is enabled.
+	//
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Specifies the cron expression for the schedule to verify the completed backups. The timezone is in UTC.
+	// see https://en.wikipedia.org/wiki/Cron.
+	// A backup is verified once it is completed, and then verified again at the next scheduled time
+	// after its last verification.
+	//
+	// +kubebuilder:validation:Required
+	CronExpression string `json:"cronExpression"`
+
+	// Specifies the name of the ActionSet whose `backup.verify` action is used to verify the backups.
+	//
+	// +kubebuilder:validation:Required
+	ActionSetName string `json:"actionSetName"`
+
+	// Specifies whether to set the `Verified` condition of the backup according to the result
+	// of the verification, the condition can be consumed by alerting.
+	//
+	// +optional
+	UpdateCondition bool `json:"updateCondition,omitempty"`
+}
+
+// WorkerPodSpec defines the scheduling constraints of the backup worker pods.
+type WorkerPodSpec struct {
+	// Specifies the tolerations of the worker pods, they are appended to the tolerations
+	// configured for the controller manager.
+	//
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Specifies the node selector of the worker pods, it overrides the node selector
+	// configured for the controller manager.
+	//
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Specifies the affinity of the worker pods, it overrides the affinity configured
+	// for the controller manager, e.g. a pod anti-affinity to the primary pod.
+	//
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Specifies the priority class name of the worker pods.
+	//
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
 }
 
 type BackupTarget struct {
@@ -80,6 +203,13 @@ type BackupTarget struct {
 	// +kube:validation:Required
 	PodSelector *PodSelector `json:"podSelector,omitempty"`
 
+	// Specifies the namespace of the target pods, it defaults to the namespace of the BackupPolicy.
+	// The target pods in another namespace are only allowed if the cross-namespace backup is enabled
+	// in the dataprotection controller, and the volume snapshot is not supported for them.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
 	// Specifies the connection credential to connect to the target database cluster.
 	//
 	// +optional
@@ -108,6 +238,14 @@ type PodSelector struct {
 	//
 	// +kubebuilder:default=Any
 	Strategy PodSelectionStrategy `json:"strategy,omitempty"`
+
+	// Specifies the role to fall back to when none of the pods matched by the labelsSelector is ready.
+	// The fallback pods are selected by the labelsSelector with the role label replaced by this role,
+	// for example, back up a ready follower and fall back to the leader if all followers are down.
+	// It only takes effect when the strategy is `Any`.
+	//
+	// +optional
+	FallbackRole string `json:"fallbackRole,omitempty"`
 }
 
 // PodSelectionStrategy specifies the strategy to select when multiple pods are
@@ -201,11 +339,27 @@ type BackupMethod struct {
 	// +optional
 	ActionSetName string `json:"actionSetName,omitempty"`
 
+	// Specifies the name of the VolumeSnapshotClass used to take the snapshots of persistent volumes.
+	// If not specified, the first VolumeSnapshotClass whose driver matches the CSI driver of the volume
+	// is selected. The backup fails before taking the snapshots if the class is not found or its driver
+	// doesn't match the provisioner of the volume.
+	//
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+
 	// Specifies which volumes from the target should be mounted in the backup workload.
 	//
 	// +optional
 	TargetVolumes *TargetVolumeInfo `json:"targetVolumes,omitempty"`
 
+	// Specifies the name of the container in the target pod to back up. The exec actions
+	// run in this container, and the name is passed to the job actions in the environment
+	// variable `DP_TARGET_CONTAINER_NAME`. If not specified, the container specified by the
+	// exec action is used, or the first container of the target pod if neither is specified.
+	//
+	// +optional
+	TargetContainerName string `json:"targetContainerName,omitempty"`
+
 	// Specifies the environment variables for the backup workload.
 	//
 	// +optional
@@ -220,8 +374,96 @@ type BackupMethod struct {
 	//
 	// +optional
 	Target *BackupTarget `json:"target,omitempty"`
+
+	// Specifies the hook to execute before the backup data is copied, such as flushing
+	// buffers or setting a consistency barrier. If the hook fails, the backup fails
+	// before any data is copied.
+	//
+	// +optional
+	PreBackupHook *BackupHook `json:"preBackupHook,omitempty"`
+
+	// Specifies the hook to execute after the backup data is copied, such as releasing
+	// the consistency barrier set by the preBackupHook. If the hook fails, the backup is
+	// still completed, and the failure is recorded in the `PostBackupHookSucceeded` condition.
+	//
+	// +optional
+	PostBackupHook *BackupHook `json:"postBackupHook,omitempty"`
+
+	// Specifies how the failed actions of the backup are retried. If not specified,
+	// the backup fails as soon as any of its actions fails.
+	//
+	// +optional
+	RetryPolicy *BackupRetryPolicy `json:"retryPolicy,omitempty"`
 }
 
+// BackupRetryPolicy defines how the failed actions of a backup are retried.
+type BackupRetryPolicy struct {
+	// Specifies the maximum number of times a failed job action is recreated before
+	// the backup fails. For continuous backups, it is the maximum number of restarts
+	// of the backup pod before the backup fails, the restarts are counted since the
+	// pod was last running stably for 10 minutes.
+	//
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int32 `json:"maxRetries"`
+
+	// Specifies the upper limit of the backoff seconds between retries. The backoff
+	// starts from 10 seconds and doubles after each retry, a random jitter of up to
+	// 10% of the backoff is added to avoid retrying the failed backups at the same time.
+	//
+	// +kubebuilder:default=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BackoffLimitSeconds int32 `json:"backoffLimitSeconds,omitempty"`
+}
+
+// BackupHook defines a command to execute before or after the backup data is copied.
+type BackupHook struct {
+	// Specifies where the hook is executed.
+	//
+	// - `TargetPod`: executes the command in a container of the target pod via the pod exec API.
+	// - `WorkerJob`: executes the command in a Kubernetes Job with the specified image.
+	//
+	// +kubebuilder:default=TargetPod
+	// +optional
+	Target BackupHookTarget `json:"target,omitempty"`
+
+	// Specifies the image of the hook container, it is required if the target is `WorkerJob`.
+	//
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Specifies the container of the target pod to execute the command, it is only used if the
+	// target is `TargetPod`. If not specified, the first container in the pod is used by default.
+	//
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Defines the command and arguments to be executed.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Specifies the maximum duration to wait for the hook to complete before
+	// considering the execution a failure, it is only used if the target is `TargetPod`.
+	//
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// BackupHookTarget defines where the backup hook is executed.
+//
+// +enum
+// +kubebuilder:validation:Enum={TargetPod,WorkerJob}
+type BackupHookTarget string
+
+const (
+	// BackupHookTargetPod executes the hook in the target pod.
+	BackupHookTargetPod BackupHookTarget = "TargetPod"
+
+	// BackupHookTargetWorkerJob executes the hook in a worker job.
+	BackupHookTargetWorkerJob BackupHookTarget = "WorkerJob"
+)
+
 // TargetVolumeInfo specifies the volumes and their mounts of the targeted application
 // that should be mounted in backup workload.
 type TargetVolumeInfo struct {
@@ -235,6 +477,13 @@ type TargetVolumeInfo struct {
 	//
 	// +optional
 	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Specifies the sub paths of the volumes in `volumeMounts` to back up. If specified, only
+	// the sub paths are mounted on the backup workload, and they are passed to the backup and
+	// restore actions in the environment variable `DP_BACKUP_VOLUME_SUBPATHS`, separated by commas.
+	//
+	// +optional
+	VolumeSubPaths []string `json:"volumeSubPaths,omitempty"`
 }
 
 type RuntimeSettings struct {
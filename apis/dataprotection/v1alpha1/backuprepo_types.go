@@ -70,6 +70,52 @@ type BackupRepoSpec struct {
 	//
 	// +optional
 	Credential *corev1.SecretReference `json:"credential,omitempty"`
+
+	// Specifies the quota of the total size of backups stored in the backup repository.
+	// New backups are rejected if the quota is exceeded.
+	//
+	// +optional
+	Quota *BackupRepoQuota `json:"quota,omitempty"`
+
+	// Specifies whether the backup repository is read-only. New backups to a read-only
+	// backup repository are paused until it becomes writable again, while the restores
+	// from and the deletions of the existing backups are still allowed.
+	//
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Specifies the schedule in Cron format to run the maintenance of the Kopia repositories stored in
+	// the backup repository, which reclaims the storage occupied by the data no longer referenced by any backup.
+	// If it is empty, the default schedule of the dataprotection controller is used.
+	//
+	// +optional
+	MaintenanceSchedule string `json:"maintenanceSchedule,omitempty"`
+}
+
+// BackupRepoQuota defines the limits of the total size of backups stored in the backup repository.
+type BackupRepoQuota struct {
+	// Specifies the limit of the total size of all backups stored in the backup repository.
+	//
+	// +optional
+	Total *resource.Quantity `json:"total,omitempty"`
+
+	// Specifies the limits of the total size of backups per namespace, the key is the namespace name.
+	//
+	// +optional
+	PerNamespace map[string]resource.Quantity `json:"perNamespace,omitempty"`
+}
+
+// BackupRepoUsage represents the space consumed by the backups stored in the backup repository.
+type BackupRepoUsage struct {
+	// Represents the total size of all backups stored in the backup repository.
+	//
+	// +optional
+	Total resource.Quantity `json:"total,omitempty"`
+
+	// Represents the total size of backups per namespace, the key is the namespace name.
+	//
+	// +optional
+	PerNamespace map[string]resource.Quantity `json:"perNamespace,omitempty"`
 }
 
 // BackupRepoStatus defines the observed state of `BackupRepo`.
@@ -111,10 +157,33 @@ type BackupRepoStatus struct {
 	// +optional
 	ToolConfigSecretName string `json:"toolConfigSecretName,omitempty"`
 
+	// Represents the generation of the credential secret, it is increased each time the content of
+	// the credential secret is changed, e.g. when the credential is rotated.
+	// The generation used by a backup is recorded in its annotation `dataprotection.kubeblocks.io/credential-generation`.
+	//
+	// +optional
+	CredentialGeneration int64 `json:"credentialGeneration,omitempty"`
+
 	// Indicates if this backup repository is the default one.\
 	//
 	// +optional
 	IsDefault bool `json:"isDefault,omitempty"`
+
+	// Represents the space consumed by the backups stored in the backup repository,
+	// it is calculated by summing the total size of the backups which are not being deleted.
+	//
+	// +optional
+	Usage *BackupRepoUsage `json:"usage,omitempty"`
+
+	// Records the time when the last maintenance of the Kopia repositories was finished.
+	//
+	// +optional
+	LastMaintenanceTime *metav1.Time `json:"lastMaintenanceTime,omitempty"`
+
+	// Represents the number of bytes reclaimed from the storage by the last maintenance of the Kopia repositories.
+	//
+	// +optional
+	ReclaimedBytes int64 `json:"reclaimedBytes,omitempty"`
 }
 
 // +genclient
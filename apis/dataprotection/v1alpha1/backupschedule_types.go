@@ -17,7 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // BackupScheduleSpec defines the desired state of BackupSchedule.
@@ -76,6 +81,74 @@ type SchedulePolicy struct {
 	// +optional
 	// +kubebuilder:default="7d"
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
+
+	// Specifies the number of the latest completed backups of the backup method to keep regardless
+	// of the retentionPeriod. An expired completed backup is not deleted if doing so would leave fewer
+	// than keepLatest completed backups for the backup policy and the backup method, so the last good
+	// backups are kept even if the new backups have been failing for a long time.
+	// No backup is kept beyond its retention period if it is not set or set to 0.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepLatest *int32 `json:"keepLatest,omitempty"`
+
+	// Specifies the template of the names of the backups created by the schedule.
+	// The following variables in the template are substituted when the backup is created:
+	//
+	// - $(CLUSTER_NAME): the name of the cluster that the backup policy targets.
+	// - $(COMPONENT_NAME): the name of the component that the backup policy targets.
+	// - $(METHOD): the backup method of the schedule.
+	// - $(SCHEDULE_TIME): the UTC time when the backup is created, in the format of `20060102150405`.
+	//
+	// For example, `$(CLUSTER_NAME)-$(METHOD)-$(SCHEDULE_TIME)`. The rendered name must be
+	// a valid DNS-1123 label. If a backup with the rendered name already exists, a short random
+	// suffix is appended to the name.
+	// If it is not set, the backups are named after the cluster and the schedule time.
+	//
+	// +optional
+	NameTemplate string `json:"nameTemplate,omitempty"`
+}
+
+const (
+	// BackupNameVarClusterName is substituted with the name of the cluster in the backup name template.
+	BackupNameVarClusterName = "CLUSTER_NAME"
+	// BackupNameVarComponentName is substituted with the name of the component in the backup name template.
+	BackupNameVarComponentName = "COMPONENT_NAME"
+	// BackupNameVarMethod is substituted with the backup method in the backup name template.
+	BackupNameVarMethod = "METHOD"
+	// BackupNameVarScheduleTime is substituted with the schedule time in the backup name template.
+	BackupNameVarScheduleTime = "SCHEDULE_TIME"
+
+	// BackupNameScheduleTimeLayout is the layout of the schedule time in the backup name.
+	BackupNameScheduleTimeLayout = "20060102150405"
+)
+
+var backupNameTemplateVarRegex = regexp.MustCompile(`\$\(([^)]*)\)`)
+
+// RenderBackupNameTemplate substitutes the variables in the backup name template with
+// the values in vars. It returns an error if the template references an unknown variable.
+func RenderBackupNameTemplate(nameTemplate string, vars map[string]string) (string, error) {
+	var err error
+	name := backupNameTemplateVarRegex.ReplaceAllStringFunc(nameTemplate, func(s string) string {
+		key := backupNameTemplateVarRegex.FindStringSubmatch(s)[1]
+		value, ok := vars[key]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("unknown variable $(%s) in backup name template %q", key, nameTemplate)
+			}
+			return s
+		}
+		return value
+	})
+	return name, err
+}
+
+// ValidateBackupName checks whether the rendered backup name is a valid DNS-1123 label.
+func ValidateBackupName(name string) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return fmt.Errorf("invalid backup name %q: %s", name, strings.Join(errs, "; "))
+	}
+	return nil
 }
 
 // BackupScheduleStatus defines the observed state of BackupSchedule.
@@ -97,10 +170,16 @@ type BackupScheduleStatus struct {
 	// +optional
 	FailureReason string `json:"failureReason,omitempty"`
 
-	// Describes the status of each schedule.
+	// Describes the status of each schedule, keyed by the backup method of the schedule.
 	//
 	// +optional
 	Schedules map[string]ScheduleStatus `json:"schedules,omitempty"`
+
+	// Records the latest time range which the cluster can be restored to, it is
+	// computed from the completed full backups and the continuous backups covering them.
+	//
+	// +optional
+	RestorableTimeRange *BackupTimeRange `json:"restorableTimeRange,omitempty"`
 }
 
 // BackupSchedulePhase defines the phase of BackupSchedule
@@ -135,6 +214,22 @@ type ScheduleStatus struct {
 	//
 	// +optional
 	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// Records the name of the last successfully completed backup.
+	//
+	// +optional
+	LastSuccessfulBackupName string `json:"lastSuccessfulBackupName,omitempty"`
+
+	// Records the next time the backup will be scheduled, which is computed from the cron expression.
+	// It is not set if the schedule is disabled.
+	//
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// Records the number of consecutive failed backups since the last successfully completed backup.
+	//
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
 }
 
 // SchedulePhase represents the phase of a schedule.
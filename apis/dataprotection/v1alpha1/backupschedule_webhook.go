@@ -0,0 +1,100 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var backupschedulelog = logf.Log.WithName("backupschedule-resource")
+
+func (r *BackupSchedule) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-dataprotection-kubeblocks-io-v1alpha1-backupschedule,mutating=false,failurePolicy=fail,sideEffects=None,groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=create;update,versions=v1alpha1,name=vbackupschedule.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &BackupSchedule{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *BackupSchedule) ValidateCreate() (admission.Warnings, error) {
+	backupschedulelog.Info("validate create", "name", r.Name)
+	return nil, r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *BackupSchedule) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	backupschedulelog.Info("validate update", "name", r.Name)
+	return nil, r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *BackupSchedule) ValidateDelete() (admission.Warnings, error) {
+	backupschedulelog.Info("validate delete", "name", r.Name)
+	return nil, nil
+}
+
+func (r *BackupSchedule) validate() error {
+	var allErrs field.ErrorList
+	for i, sp := range r.Spec.Schedules {
+		if sp.NameTemplate == "" {
+			continue
+		}
+		path := field.NewPath("spec").Child("schedules").Index(i).Child("nameTemplate")
+		if err := validateBackupNameTemplate(sp.NameTemplate, sp.BackupMethod); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, sp.NameTemplate, err.Error()))
+		}
+	}
+	if len(allErrs) > 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{
+				Group: GroupVersion.Group,
+				Kind:  "BackupSchedule",
+			},
+			r.Name, allErrs)
+	}
+	return nil
+}
+
+// validateBackupNameTemplate renders the backup name template with the shortest possible
+// cluster and component names, the rendered name can be checked without knowing the backup
+// policy target, and the names rendered with the actual values are checked by the controller.
+func validateBackupNameTemplate(nameTemplate, backupMethod string) error {
+	name, err := RenderBackupNameTemplate(nameTemplate, map[string]string{
+		BackupNameVarClusterName:   "c",
+		BackupNameVarComponentName: "c",
+		BackupNameVarMethod:        backupMethod,
+		BackupNameVarScheduleTime:  BackupNameScheduleTimeLayout,
+	})
+	if err != nil {
+		return err
+	}
+	return ValidateBackupName(name)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderBackupNameTemplate(t *testing.T) {
+	vars := map[string]string{
+		BackupNameVarClusterName:   "mycluster",
+		BackupNameVarComponentName: "mysql",
+		BackupNameVarMethod:        "xtrabackup",
+		BackupNameVarScheduleTime:  "20240101000000",
+	}
+	name, err := RenderBackupNameTemplate("$(CLUSTER_NAME)-$(COMPONENT_NAME)-$(METHOD)-$(SCHEDULE_TIME)", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "mycluster-mysql-xtrabackup-20240101000000", name)
+
+	name, err = RenderBackupNameTemplate("daily-$(CLUSTER_NAME)", vars)
+	assert.NoError(t, err)
+	assert.Equal(t, "daily-mycluster", name)
+
+	_, err = RenderBackupNameTemplate("$(CLUSTER_NAME)-$(NAMESPACE)", vars)
+	assert.ErrorContains(t, err, "unknown variable $(NAMESPACE)")
+}
+
+func TestBackupScheduleValidate(t *testing.T) {
+	newBackupSchedule := func(nameTemplates ...string) *BackupSchedule {
+		schedule := &BackupSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-schedule"},
+			Spec:       BackupScheduleSpec{BackupPolicyName: "test-policy"},
+		}
+		for _, nameTemplate := range nameTemplates {
+			schedule.Spec.Schedules = append(schedule.Spec.Schedules, SchedulePolicy{
+				BackupMethod:   "xtrabackup",
+				CronExpression: "0 0 * * *",
+				NameTemplate:   nameTemplate,
+			})
+		}
+		return schedule
+	}
+
+	tests := []struct {
+		name         string
+		nameTemplate string
+		valid        bool
+	}{
+		{name: "not set", nameTemplate: "", valid: true},
+		{name: "all variables", nameTemplate: "$(CLUSTER_NAME)-$(COMPONENT_NAME)-$(METHOD)-$(SCHEDULE_TIME)", valid: true},
+		{name: "without variables", nameTemplate: "daily-backup", valid: true},
+		{name: "unknown variable", nameTemplate: "$(CLUSTER_NAME)-$(NAMESPACE)", valid: false},
+		{name: "unclosed variable", nameTemplate: "$(CLUSTER_NAME-backup", valid: false},
+		{name: "upper case letters", nameTemplate: "Daily-$(SCHEDULE_TIME)", valid: false},
+		{name: "invalid characters", nameTemplate: "$(CLUSTER_NAME)_$(SCHEDULE_TIME)", valid: false},
+		{name: "ends with dash", nameTemplate: "$(CLUSTER_NAME)-", valid: false},
+		{name: "too long", nameTemplate: strings.Repeat("a", 50) + "-$(SCHEDULE_TIME)", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newBackupSchedule("$(CLUSTER_NAME)-$(SCHEDULE_TIME)", tt.nameTemplate)
+			_, createErr := schedule.ValidateCreate()
+			_, updateErr := schedule.ValidateUpdate(newBackupSchedule())
+			if tt.valid {
+				assert.NoError(t, createErr)
+				assert.NoError(t, updateErr)
+				return
+			}
+			assert.True(t, apierrors.IsInvalid(createErr))
+			assert.ErrorContains(t, createErr, "spec.schedules[1].nameTemplate")
+			assert.True(t, apierrors.IsInvalid(updateErr))
+		})
+	}
+}
@@ -87,6 +87,31 @@ type RestoreSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=10
 	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// Specifies how to handle the restore if the ActionSet used by the backup has changed since
+	// the backup was taken, the backup may not be restorable with the new restore actions.
+	//
+	// - `Warn`: records the change in the `ActionSetChanged` condition and continues to restore.
+	// - `Fail`: fails the restore.
+	//
+	// +kubebuilder:default=Warn
+	// +optional
+	ActionSetChangedPolicy ActionSetChangedPolicy `json:"actionSetChangedPolicy,omitempty"`
+
+	// Specifies whether to only check if the backup can be restored into the target environment,
+	// no restore workloads will be created if it is true. The following checks are performed and
+	// the result of each check is recorded in a condition of the restore:
+	//
+	// - `PreflightBackupArtifacts`: the backup artifacts exist in the backup repository or volume snapshots,
+	//   the backup path is listed by a job with the backup repository mounted.
+	// - `PreflightEncryptionKey`: the secret of the encryption key exists in the namespace of the restore.
+	// - `PreflightConnectionPassword`: the connection password saved in the backup can be decrypted.
+	// - `PreflightClusterSnapshot`: the cluster snapshot saved in the backup can be parsed.
+	//
+	// The restore is `Completed` if all checks pass, otherwise it is `Failed`.
+	//
+	// +optional
+	Preflight bool `json:"preflight,omitempty"`
 }
 
 // BackupRef describes the backup name and namespace.
@@ -186,6 +186,18 @@ func (r RetentionPeriod) nextNumber(input string) (num int, rest string, err err
 	return num, rest, nil
 }
 
+// ActionSetChangedPolicy defines how to handle the restore when the ActionSet has changed since the backup was taken.
+// +enum
+// +kubebuilder:validation:Enum={Warn,Fail}
+type ActionSetChangedPolicy string
+
+const (
+	// ActionSetChangedPolicyWarn records the change in the conditions of the restore and continues to restore.
+	ActionSetChangedPolicyWarn ActionSetChangedPolicy = "Warn"
+	// ActionSetChangedPolicyFail fails the restore.
+	ActionSetChangedPolicyFail ActionSetChangedPolicy = "Fail"
+)
+
 // RestorePhase The current phase. Valid values are Running, Completed, Failed, AsDataSource.
 // +enum
 // +kubebuilder:validation:Enum={Running,Completed,Failed,AsDataSource}
@@ -233,12 +245,15 @@ const (
 
 // EncryptionConfig defines the parameters for encrypting backup data.
 type EncryptionConfig struct {
-	// Specifies the encryption algorithm. Currently supported algorithms are:
+	// Specifies the encryption algorithm of the backup data, the data is encrypted by datasafed.
+	// Currently supported algorithms are:
 	//
 	// - AES-128-CFB
 	// - AES-192-CFB
 	// - AES-256-CFB
 	//
+	// The connection password saved in the backup is encrypted by AES-256-GCM with a random nonce.
+	//
 	// +kubebuilder:validation:Required
 	// +kubebuilder:default=AES-256-CFB
 	// +kubebuilder:validation:Enum={AES-128-CFB,AES-192-CFB,AES-256-CFB}
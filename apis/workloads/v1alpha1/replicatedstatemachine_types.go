@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -72,6 +73,11 @@ type ReplicatedStateMachineSpec struct {
 	// the network identity of the set. Pods get DNS/hostnames that follow a specific pattern.
 	ServiceName string `json:"serviceName"`
 
+	// Customizes the headless service named by ServiceName, which is created by the ReplicatedStateMachine.
+	//
+	// +optional
+	HeadlessService *HeadlessService `json:"headlessService,omitempty"`
+
 	// Defines the behavior of a service spec.
 	// Provides read-write service.
 	// https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
@@ -135,6 +141,28 @@ type ReplicatedStateMachineSpec struct {
 	// +optional
 	MemberUpdateStrategy *MemberUpdateStrategy `json:"memberUpdateStrategy,omitempty"`
 
+	// Specifies the maximum number of the members except the leader which can be updated at the same time
+	// with the `BestEffortParallel` member update strategy, the leader is always updated last.
+	// The value can be an absolute number (ex: 2) or a percentage of the replicas (ex: 50%),
+	// the absolute number is calculated from the percentage by rounding down.
+	// It must not exceed the number of the members except the leader.
+	//
+	// The members except the leader are updated in two halves if it is not set.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Specifies how the leader is evicted, e.g. when the node hosting it is drained.
+	//
+	// - Direct: the leader is evicted directly like the other members.
+	// - SwitchoverFirst: the eviction of the leader is blocked until the leadership has been switched over
+	//   to another member by the switchover action. It falls back to Direct if the switchover is not done in time.
+	//
+	// +kubebuilder:validation:Enum={Direct,SwitchoverFirst}
+	// +optional
+	LeaderEvictionPolicy LeaderEvictionPolicy `json:"leaderEvictionPolicy,omitempty"`
+
 	// Indicates that the rsm is paused, meaning the reconciliation of this rsm object will be paused.
 	// +optional
 	Paused bool `json:"paused,omitempty"`
@@ -156,6 +184,19 @@ type ReplicatedStateMachineSpec struct {
 	NodeAssignment []NodeAssignment `json:"nodeAssignment,omitempty"`
 }
 
+// HeadlessService customizes the headless service which governs the pods.
+type HeadlessService struct {
+	// Indicates whether the addresses of the pods are published before they are ready. Defaults to true.
+	//
+	// +optional
+	PublishNotReadyAddresses *bool `json:"publishNotReadyAddresses,omitempty"`
+
+	// Specifies the session affinity of the headless service.
+	//
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+}
+
 type NodeAssignment struct {
 	// Specifies the identifier for the statefulSet requiring node allocation.
 	//
@@ -283,6 +324,15 @@ const (
 	ParallelUpdateStrategy           MemberUpdateStrategy = "Parallel"
 )
 
+// LeaderEvictionPolicy defines how the leader is evicted.
+// +enum
+type LeaderEvictionPolicy string
+
+const (
+	DirectLeaderEviction          LeaderEvictionPolicy = "Direct"
+	SwitchoverFirstLeaderEviction LeaderEvictionPolicy = "SwitchoverFirst"
+)
+
 // RoleUpdateMechanism defines the way how pod role label being updated.
 // +enum
 type RoleUpdateMechanism string
@@ -460,6 +510,29 @@ type MemberStatus struct {
 	ReadyWithoutPrimary bool `json:"readyWithoutPrimary"`
 }
 
+// GetMaxUnavailableMembers resolves the maximum number of the members except the leaders which can be updated
+// at the same time with the `BestEffortParallel` member update strategy.
+// It defaults to `replicas - 1 - leaders` which keeps one member available to take over the leadership,
+// and the result is limited to the range of [1, replicas - leaders].
+func GetMaxUnavailableMembers(maxUnavailable *intstr.IntOrString, replicas, leaders int32) (int32, error) {
+	upper := replicas - leaders
+	value := upper - 1
+	if maxUnavailable != nil {
+		scaled, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(replicas), false)
+		if err != nil {
+			return 0, err
+		}
+		value = int32(scaled)
+	}
+	if value > upper {
+		value = upper
+	}
+	if value < 1 {
+		value = 1
+	}
+	return value, nil
+}
+
 func init() {
 	SchemeBuilder.Register(&ReplicatedStateMachine{}, &ReplicatedStateMachineList{})
 }
@@ -20,9 +20,12 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package v1alpha1
 
 import (
+	"fmt"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -97,6 +100,8 @@ func (r *ReplicatedStateMachine) validate() error {
 		}
 	}
 
+	r.validateMaxUnavailable(&allErrs)
+
 	// servicePort must provide if spec.service is not nil
 	if r.Spec.Service != nil && len(r.Spec.Service.Spec.Ports) == 0 {
 		allErrs = append(allErrs,
@@ -115,3 +120,41 @@ func (r *ReplicatedStateMachine) validate() error {
 
 	return nil
 }
+
+// validateMaxUnavailable validates the maxUnavailable against the replicas and the number of leaders,
+// the leaders should never be updated together with the other members.
+func (r *ReplicatedStateMachine) validateMaxUnavailable(allErrs *field.ErrorList) {
+	if r.Spec.MaxUnavailable == nil {
+		return
+	}
+	path := field.NewPath("spec.maxUnavailable")
+	replicas := int32(1)
+	if r.Spec.Replicas != nil {
+		replicas = *r.Spec.Replicas
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(r.Spec.MaxUnavailable, int(replicas), false)
+	if err != nil {
+		*allErrs = append(*allErrs, field.Invalid(path, r.Spec.MaxUnavailable.String(), err.Error()))
+		return
+	}
+	if r.Spec.MaxUnavailable.Type == intstr.Int && value < 1 {
+		*allErrs = append(*allErrs, field.Invalid(path, value, "maxUnavailable should be at least 1"))
+		return
+	}
+	// a percentage rounding down to 0 blocks the update of the members.
+	if value < 1 && replicas > 0 {
+		*allErrs = append(*allErrs, field.Invalid(path, r.Spec.MaxUnavailable.String(),
+			fmt.Sprintf("maxUnavailable should be at least 1 for %d replicas", replicas)))
+		return
+	}
+	var leaders int32
+	for _, role := range r.Spec.Roles {
+		if role.IsLeader {
+			leaders++
+		}
+	}
+	if upper := replicas - leaders; upper > 0 && int32(value) > upper {
+		*allErrs = append(*allErrs, field.Invalid(path, r.Spec.MaxUnavailable.String(),
+			fmt.Sprintf("maxUnavailable should not exceed the number of the members except the leader: %d", upper)))
+	}
+}
@@ -25,6 +25,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/apecloud/kubeblocks/pkg/constant"
 )
@@ -106,6 +107,44 @@ var _ = Describe("ReplicatedStateMachine Webhook", func() {
 			Expect(err.Error()).Should(ContainSubstring("servicePort must provide"))
 		})
 
+		It("should return an error if maxUnavailable includes the leader", func() {
+			replicas := int32(3)
+			maxUnavailable := intstr.FromInt(3)
+			rsm.Spec.Replicas = &replicas
+			rsm.Spec.MaxUnavailable = &maxUnavailable
+			rsm.Spec.Roles = []ReplicaRole{
+				{
+					Name:       "leader",
+					IsLeader:   true,
+					AccessMode: ReadWriteMode,
+				},
+			}
+			rsm.Spec.Service.Spec.Ports = []corev1.ServicePort{
+				{
+					Name:     "foo",
+					Protocol: "tcp",
+					Port:     12345,
+				},
+			}
+			err := k8sClient.Create(ctx, rsm)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("maxUnavailable should not exceed the number of the members except the leader: 2"))
+
+			maxUnavailable = intstr.FromInt(0)
+			err = k8sClient.Create(ctx, rsm)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("maxUnavailable should be at least 1"))
+
+			maxUnavailable = intstr.FromString("10%")
+			err = k8sClient.Create(ctx, rsm)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(ContainSubstring("maxUnavailable should be at least 1 for 3 replicas"))
+
+			maxUnavailable = intstr.FromInt(2)
+			Expect(k8sClient.Create(ctx, rsm)).Should(Succeed())
+			Expect(k8sClient.Delete(ctx, rsm)).Should(Succeed())
+		})
+
 		It("should succeed if spec is well defined", func() {
 			rsm.Spec.Roles = []ReplicaRole{
 				{
@@ -34,6 +34,7 @@ import (
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	discoverycli "k8s.io/client-go/discovery"
@@ -42,6 +43,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	// +kubebuilder:scaffold:imports
 
@@ -126,6 +128,7 @@ func init() {
 	viper.SetDefault(constant.KubernetesClusterDomainEnv, constant.DefaultDNSDomain)
 	viper.SetDefault(rsm.FeatureGateRSMCompatibilityMode, true)
 	viper.SetDefault(rsm.FeatureGateRSMToPod, true)
+	viper.SetDefault(rsm.LeaderEvictionTimeout, 5*time.Minute)
 	viper.SetDefault(constant.FeatureGateEnableRuntimeMetrics, false)
 }
 
@@ -229,6 +232,12 @@ func validateRequiredToParseConfigs() error {
 	if err := validateAffinity(viper.GetString(constant.CfgKeyDataPlaneAffinity)); err != nil {
 		return err
 	}
+	if cmdExecutorResources := viper.GetString(constant.CfgKeyCmdExecutorDefaultResources); cmdExecutorResources != "" {
+		resources := corev1.ResourceRequirements{}
+		if err := json.Unmarshal([]byte(cmdExecutorResources), &resources); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -515,6 +524,11 @@ func main() {
 			os.Exit(1)
 		}
 
+		if err = (&appsv1alpha1.BackupPolicyTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BackupPolicyTemplate")
+			os.Exit(1)
+		}
+
 		if err = (&workloadsv1alpha1.ReplicatedStateMachine{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ReplicatedStateMachine")
 			os.Exit(1)
@@ -524,6 +538,19 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ServiceDescriptor")
 			os.Exit(1)
 		}
+
+		if err = (&dpv1alpha1.BackupSchedule{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BackupSchedule")
+			os.Exit(1)
+		}
+
+		if err = (&dpv1alpha1.Backup{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Backup")
+			os.Exit(1)
+		}
+
+		mgr.GetWebhookServer().Register(workloadscontrollers.PodEvictionWebhookPath,
+			&webhook.Admission{Handler: &workloadscontrollers.PodEvictionWebhook{Client: mgr.GetClient()}})
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -548,6 +575,10 @@ func main() {
 	}
 	viper.SetDefault(constant.CfgKeyServerInfo, *ver)
 
+	serviceMonitorAPIEnabled := isServiceMonitorAPIEnabled(discoveryClient)
+	setupLog.Info("prometheus-operator ServiceMonitor API.", "enabled", serviceMonitorAPIEnabled)
+	viper.SetDefault(constant.CfgKeyServiceMonitorAPIEnabled, serviceMonitorAPIEnabled)
+
 	setupLog.Info("golang runtime metrics.", "featureGate", constant.EnabledRuntimeMetrics())
 	metrics.RegisterRuntimeMetric(mgr)
 
@@ -563,3 +594,21 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// isServiceMonitorAPIEnabled checks whether the ServiceMonitor CRD of prometheus-operator is installed,
+// the monitor annotations are used to expose the metrics of components if not.
+func isServiceMonitorAPIEnabled(discoveryClient discoverycli.DiscoveryInterface) bool {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(constant.MonitoringAPIGroupVersion)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			setupLog.Error(err, "unable to discover the ServiceMonitor API")
+		}
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Kind == constant.ServiceMonitorKind {
+			return true
+		}
+	}
+	return false
+}
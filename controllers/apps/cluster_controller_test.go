@@ -38,6 +38,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
@@ -1024,12 +1025,13 @@ var _ = Describe("Cluster Controller", func() {
 			By("create a cluster with the unavailable cluster version")
 			createClusterObjNoWait(consensusCompName, consensusCompDefName, false, nil)
 
-			By("expect the cluster provisioning condition as pre-check failed")
+			By("expect the cluster provisioning condition as referenced definition not available")
 			Eventually(testapps.CheckObj(&testCtx, clusterKey, func(g Gomega, cluster *appsv1alpha1.Cluster) {
 				g.Expect(cluster.Status.ObservedGeneration).Should(BeZero())
 				condition := meta.FindStatusCondition(cluster.Status.Conditions, appsv1alpha1.ConditionTypeProvisioningStarted)
 				g.Expect(condition).ShouldNot(BeNil())
-				g.Expect(condition.Reason).Should(BeEquivalentTo(ReasonPreCheckFailed))
+				g.Expect(condition.Reason).Should(BeEquivalentTo(intctrlutil.ErrorTypeReferencedDefinitionNotAvailable))
+				g.Expect(condition.Message).Should(ContainSubstring(string(appsv1alpha1.UnavailablePhase)))
 			})).Should(Succeed())
 
 			By("reset cluster version to Available")
@@ -40,6 +40,9 @@ const (
 	ReasonAllReplicasReady      = "AllReplicasReady"      // ReasonAllReplicasReady the pods of components are ready
 	ReasonComponentsNotReady    = "ComponentsNotReady"    // ReasonComponentsNotReady the components of cluster are not ready
 	ReasonClusterReady          = "ClusterReady"          // ReasonClusterReady the components of cluster are ready, the component phase is running
+
+	ReasonBackupPolicyTemplateResolved = "BackupPolicyTemplateResolved" // ReasonBackupPolicyTemplateResolved the default BackupPolicyTemplates of the components are resolved
+	ReasonBackupPolicyTemplateNotFound = "BackupPolicyTemplateNotFound" // ReasonBackupPolicyTemplateNotFound no BackupPolicyTemplate matches the components of cluster
 )
 
 func setProvisioningStartedCondition(conditions *[]metav1.Condition, clusterName string, clusterGeneration int64, err error) {
@@ -157,3 +160,28 @@ func newComponentsNotReadyCondition(notReadyComponentNames map[string]struct{})
 		Reason:  ReasonComponentsNotReady,
 	}
 }
+
+// newBackupPolicyTemplateResolvedCondition creates a condition with the default BackupPolicyTemplates chosen for the components,
+// the keys of defaultTpls are the component names.
+func newBackupPolicyTemplateResolvedCondition(defaultTpls map[string]string) metav1.Condition {
+	if len(defaultTpls) == 0 {
+		return metav1.Condition{
+			Type:    appsv1alpha1.ConditionTypeBackupPolicyTemplateResolved,
+			Status:  metav1.ConditionFalse,
+			Message: "no BackupPolicyTemplate matches the components",
+			Reason:  ReasonBackupPolicyTemplateNotFound,
+		}
+	}
+	compNames := maps.Keys(defaultTpls)
+	slices.Sort(compNames)
+	tpls := make([]string, 0, len(compNames))
+	for _, compName := range compNames {
+		tpls = append(tpls, fmt.Sprintf("%s: %s", compName, defaultTpls[compName]))
+	}
+	return metav1.Condition{
+		Type:    appsv1alpha1.ConditionTypeBackupPolicyTemplateResolved,
+		Status:  metav1.ConditionTrue,
+		Message: fmt.Sprintf("the default BackupPolicyTemplates of Components: %v", tpls),
+		Reason:  ReasonBackupPolicyTemplateResolved,
+	}
+}
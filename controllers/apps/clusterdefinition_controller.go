@@ -21,15 +21,21 @@ package apps
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	appsconfig "github.com/apecloud/kubeblocks/controllers/apps/configuration"
@@ -89,6 +95,22 @@ func (r *ClusterDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return intctrlutil.Reconciled()
 	}
 
+	// report the validation results of each componentDef, the ClusterDefinition is unavailable
+	// if any of the componentDefs fails the validation.
+	if unavailable := r.validateComponentDefs(reqCtx, dbClusterDef); len(unavailable) > 0 {
+		// the observedGeneration is not updated until the ClusterDefinition is available.
+		statusPatch := client.MergeFrom(dbClusterDef.DeepCopy())
+		dbClusterDef.Status.Phase = appsv1alpha1.UnavailablePhase
+		dbClusterDef.Status.Message = fmt.Sprintf("componentDefs [%s] are unavailable, see status.components for details",
+			strings.Join(unavailable, ","))
+		if err := r.Client.Status().Patch(reqCtx.Ctx, dbClusterDef, statusPatch); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		// requeue with the rate-limited backoff, rather than a fixed interval, since the componentDefs failing
+		// the validation of the spec are not recovered until the ClusterDefinition is updated.
+		return intctrlutil.Requeue(reqCtx.Log, dbClusterDef.Status.Message)
+	}
+
 	if err := appsconfig.ReconcileConfigSpecsForReferencedCR(r.Client, reqCtx, dbClusterDef); err != nil {
 		return intctrlutil.RequeueAfter(time.Second, reqCtx.Log, err.Error())
 	}
@@ -102,6 +124,8 @@ func (r *ClusterDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	statusPatch := client.MergeFrom(dbClusterDef.DeepCopy())
 	dbClusterDef.Status.ObservedGeneration = dbClusterDef.Generation
 	dbClusterDef.Status.Phase = appsv1alpha1.AvailablePhase
+	dbClusterDef.Status.Message = ""
+	meta.SetStatusCondition(&dbClusterDef.Status.Conditions, buildDataVolumeCondition(dbClusterDef))
 	if err = r.Client.Status().Patch(reqCtx.Ctx, dbClusterDef, statusPatch); err != nil {
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 	}
@@ -113,9 +137,34 @@ func (r *ClusterDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Re
 func (r *ClusterDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&appsv1alpha1.ClusterDefinition{}).
+		Watches(&appsv1alpha1.ConfigConstraint{}, handler.EnqueueRequestsFromMapFunc(r.filterConfigConstraint)).
 		Complete(r)
 }
 
+// filterConfigConstraint enqueues the unavailable ClusterDefinitions referencing the ConfigConstraint,
+// so that they are validated again once the ConfigConstraint is changed.
+func (r *ClusterDefinitionReconciler) filterConfigConstraint(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterDefList := &appsv1alpha1.ClusterDefinitionList{}
+	if err := r.Client.List(ctx, clusterDefList); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, clusterDef := range clusterDefList.Items {
+		if clusterDef.Status.Phase != appsv1alpha1.UnavailablePhase {
+			continue
+		}
+		for _, compDef := range clusterDef.Spec.ComponentDefs {
+			if slices.ContainsFunc(compDef.ConfigSpecs, func(spec appsv1alpha1.ComponentConfigSpec) bool {
+				return spec.ConfigConstraintRef == obj.GetName()
+			}) {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&clusterDef)})
+				break
+			}
+		}
+	}
+	return requests
+}
+
 func (r *ClusterDefinitionReconciler) deleteExternalResources(reqCtx intctrlutil.RequestCtx, clusterDef *appsv1alpha1.ClusterDefinition) error {
 	//
 	// delete any external resources associated with the cronJob
@@ -124,3 +173,112 @@ func (r *ClusterDefinitionReconciler) deleteExternalResources(reqCtx intctrlutil
 	// multiple times for same object.
 	return appsconfig.DeleteConfigMapFinalizer(r.Client, reqCtx, clusterDef)
 }
+
+// validateComponentDefs validates each componentDef and records the results in status.components,
+// it returns the names of the componentDefs failing the validation.
+func (r *ClusterDefinitionReconciler) validateComponentDefs(reqCtx intctrlutil.RequestCtx,
+	clusterDef *appsv1alpha1.ClusterDefinition) []string {
+	var unavailable []string
+	statuses := make([]appsv1alpha1.ComponentDefStatus, 0, len(clusterDef.Spec.ComponentDefs))
+	for i := range clusterDef.Spec.ComponentDefs {
+		compDef := &clusterDef.Spec.ComponentDefs[i]
+		status := appsv1alpha1.ComponentDefStatus{
+			Name:               compDef.Name,
+			Phase:              appsv1alpha1.AvailablePhase,
+			ObservedGeneration: clusterDef.Generation,
+		}
+		if err := r.validateComponentDef(reqCtx, compDef); err != nil {
+			status.Phase = appsv1alpha1.UnavailablePhase
+			status.Message = err.Error()
+			unavailable = append(unavailable, compDef.Name)
+		}
+		statuses = append(statuses, status)
+	}
+	clusterDef.Status.Components = statuses
+	return unavailable
+}
+
+func (r *ClusterDefinitionReconciler) validateComponentDef(reqCtx intctrlutil.RequestCtx,
+	compDef *appsv1alpha1.ClusterComponentDefinition) error {
+	for _, validator := range []func(intctrlutil.RequestCtx, *appsv1alpha1.ClusterComponentDefinition) error{
+		r.validateProbes,
+		r.validateVolumeTypes,
+		r.validateConfigSpecs,
+		r.validateServiceRefDeclarations,
+	} {
+		if err := validator(reqCtx, compDef); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ClusterDefinitionReconciler) validateProbes(reqCtx intctrlutil.RequestCtx,
+	compDef *appsv1alpha1.ClusterComponentDefinition) error {
+	probes := compDef.Probes
+	if probes == nil {
+		return nil
+	}
+	names := []string{"runningProbe", "statusProbe", "roleProbe"}
+	for i, probe := range []*appsv1alpha1.ClusterDefinitionProbe{probes.RunningProbe, probes.StatusProbe, probes.RoleProbe} {
+		if probe == nil {
+			continue
+		}
+		if err := probe.Validate(); err != nil {
+			return fmt.Errorf("invalid %s: %s", names[i], err.Error())
+		}
+	}
+	if probes.RoleProbe != nil && probes.RoleProbe.GRPC != nil {
+		return fmt.Errorf("invalid roleProbe: grpc is not supported by the role probe")
+	}
+	return nil
+}
+
+func (r *ClusterDefinitionReconciler) validateVolumeTypes(reqCtx intctrlutil.RequestCtx,
+	compDef *appsv1alpha1.ClusterComponentDefinition) error {
+	return compDef.ValidateVolumeTypes()
+}
+
+func (r *ClusterDefinitionReconciler) validateConfigSpecs(reqCtx intctrlutil.RequestCtx,
+	compDef *appsv1alpha1.ClusterComponentDefinition) error {
+	if len(compDef.ConfigSpecs) == 0 {
+		return nil
+	}
+	if err := appsconfig.ValidateConfigSpecs(r.Client, reqCtx, compDef.ConfigSpecs); err != nil {
+		return fmt.Errorf("invalid configSpecs: %s", err.Error())
+	}
+	return nil
+}
+
+func (r *ClusterDefinitionReconciler) validateServiceRefDeclarations(reqCtx intctrlutil.RequestCtx,
+	compDef *appsv1alpha1.ClusterComponentDefinition) error {
+	for i := range compDef.ServiceRefDeclarations {
+		if err := compDef.ServiceRefDeclarations[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDataVolumeCondition builds the condition which reports the componentDefs declaring no data volume,
+// the backup of these components is silently disabled.
+func buildDataVolumeCondition(clusterDef *appsv1alpha1.ClusterDefinition) metav1.Condition {
+	compDefNames := clusterDef.GetComponentDefsWithoutDataVolume()
+	if len(compDefNames) == 0 {
+		return metav1.Condition{
+			Type:               appsv1alpha1.ConditionTypeDataVolumeDeclared,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: clusterDef.Generation,
+			Reason:             appsv1alpha1.ReasonDataVolumeDeclared,
+			Message:            "all stateful componentDefs declare a data volume",
+		}
+	}
+	return metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeDataVolumeDeclared,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clusterDef.Generation,
+		Reason:             appsv1alpha1.ReasonDataVolumeMissing,
+		Message: fmt.Sprintf("componentDefs [%s] declare no volume of type data in volumeTypes, the backup will not be supported",
+			strings.Join(compDefNames, ",")),
+	}
+}
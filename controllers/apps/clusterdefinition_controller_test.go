@@ -24,8 +24,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -164,12 +166,26 @@ var _ = Describe("ClusterDefinition Controller", func() {
 					g.Expect(cd.Status.ObservedGeneration).To(BeEquivalentTo(0))
 				})).Should(Succeed())
 
+			By("check the componentDef referencing the configmap is reported as unavailable.")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(clusterDefObj),
+				func(g Gomega, cd *appsv1alpha1.ClusterDefinition) {
+					g.Expect(cd.Status.Phase).To(Equal(appsv1alpha1.UnavailablePhase))
+					g.Expect(cd.Status.Message).To(ContainSubstring(statefulCompDefName))
+					g.Expect(cd.Status.Components).To(HaveLen(1))
+					g.Expect(cd.Status.Components[0].Name).To(Equal(statefulCompDefName))
+					g.Expect(cd.Status.Components[0].Phase).To(Equal(appsv1alpha1.UnavailablePhase))
+					g.Expect(cd.Status.Components[0].Message).To(ContainSubstring("configSpecs"))
+				})).Should(Succeed())
+
 			assureCfgTplConfigMapObj()
 
 			By("check the reconciler update Status.ObservedGeneration after configmap is created.")
 			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(clusterDefObj),
 				func(g Gomega, cd *appsv1alpha1.ClusterDefinition) {
 					g.Expect(cd.Status.ObservedGeneration).To(BeEquivalentTo(1))
+					g.Expect(cd.Status.Phase).To(Equal(appsv1alpha1.AvailablePhase))
+					g.Expect(cd.Status.Components).To(HaveLen(1))
+					g.Expect(cd.Status.Components[0].Phase).To(Equal(appsv1alpha1.AvailablePhase))
 
 					// check labels and finalizers
 					g.Expect(cd.Finalizers).ShouldNot(BeEmpty())
@@ -189,4 +205,36 @@ var _ = Describe("ClusterDefinition Controller", func() {
 			})).Should(Succeed())
 		})
 	})
+
+	Context("when the ConfigConstraint is changed", func() {
+		It("should enqueue the unavailable clusterDefinitions referencing it", func() {
+			newClusterDef := func(name, ccName string, phase appsv1alpha1.Phase) *appsv1alpha1.ClusterDefinition {
+				clusterDef := &appsv1alpha1.ClusterDefinition{}
+				clusterDef.Name = name
+				clusterDef.Spec.ComponentDefs = []appsv1alpha1.ClusterComponentDefinition{{
+					Name: statefulCompDefName,
+					ConfigSpecs: []appsv1alpha1.ComponentConfigSpec{{
+						ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{Name: "mysql-config"},
+						ConfigConstraintRef:   ccName,
+					}},
+				}}
+				clusterDef.Status.Phase = phase
+				return clusterDef
+			}
+			scheme := runtime.NewScheme()
+			Expect(appsv1alpha1.AddToScheme(scheme)).Should(Succeed())
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newClusterDef("cd-unavailable", cmName, appsv1alpha1.UnavailablePhase),
+				newClusterDef("cd-available", cmName, appsv1alpha1.AvailablePhase),
+				newClusterDef("cd-other", "other-cc", appsv1alpha1.UnavailablePhase),
+			).Build()
+			reconciler := &ClusterDefinitionReconciler{Client: cli}
+
+			cc := &appsv1alpha1.ConfigConstraint{}
+			cc.Name = cmName
+			requests := reconciler.filterConfigConstraint(testCtx.Ctx, cc)
+			Expect(requests).Should(HaveLen(1))
+			Expect(requests[0].Name).Should(Equal("cd-unavailable"))
+		})
+	})
 })
@@ -25,6 +25,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -65,6 +66,7 @@ type ComponentReconciler struct {
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=core,resources=services/status,verbs=get
 // +kubebuilder:rbac:groups=core,resources=services/finalizers,verbs=update
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims/status,verbs=get
@@ -75,6 +77,8 @@ type ComponentReconciler struct {
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+
 // read + update access
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=pods/finalizers,verbs=update
@@ -137,6 +141,8 @@ func (r *ComponentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			&componentHostNetworkTransformer{},
 			// handle component services
 			&componentServiceTransformer{},
+			// handle the ServiceMonitor of component
+			&componentMonitorTransformer{},
 			// handle component system accounts
 			&componentAccountTransformer{},
 			// provision component system accounts
@@ -192,7 +198,8 @@ func (r *ComponentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.filterComponentResources)).
 		Owns(&batchv1.Job{}).
 		Watches(&appsv1alpha1.Configuration{}, handler.EnqueueRequestsFromMapFunc(r.configurationEventHandler)).
-		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.filterComponentResources))
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.filterComponentResources)).
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.endpointSliceEventHandler))
 
 	if viper.GetBool(constant.EnableRBACManager) {
 		b.Owns(&rbacv1.ClusterRoleBinding{}).
@@ -229,6 +236,29 @@ func (r *ComponentReconciler) filterComponentResources(ctx context.Context, obj
 	}
 }
 
+// endpointSliceEventHandler enqueues all the components of the cluster when the endpoints of a service change,
+// the pod IPs referred by other components are rendered from the endpoint slices once the pods get their IPs.
+func (r *ComponentReconciler) endpointSliceEventHandler(ctx context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if v, ok := labels[constant.AppManagedByLabelKey]; !ok || v != constant.AppName {
+		return []reconcile.Request{}
+	}
+	clusterName, ok := labels[constant.AppInstanceLabelKey]
+	if !ok {
+		return []reconcile.Request{}
+	}
+	compList := &appsv1alpha1.ComponentList{}
+	if err := r.Client.List(ctx, compList, client.InNamespace(obj.GetNamespace()),
+		client.MatchingLabels{constant.AppInstanceLabelKey: clusterName}); err != nil {
+		return []reconcile.Request{}
+	}
+	requests := make([]reconcile.Request, 0, len(compList.Items))
+	for _, comp := range compList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&comp)})
+	}
+	return requests
+}
+
 func (r *ComponentReconciler) configurationEventHandler(_ context.Context, obj client.Object) []reconcile.Request {
 	cr, ok := obj.(*appsv1alpha1.Configuration)
 	if !ok {
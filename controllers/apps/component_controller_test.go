@@ -52,7 +52,9 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	"github.com/apecloud/kubeblocks/pkg/controller/plan"
 	"github.com/apecloud/kubeblocks/pkg/controller/rsm"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
@@ -541,6 +543,13 @@ var _ = Describe("Component Controller", func() {
 				backupKey := types.NamespacedName{Name: fmt.Sprintf("%s-%s-scaling",
 					clusterKey.Name, comp.Name),
 					Namespace: testCtx.DefaultNamespace}
+
+				if policy.PreferredCloneMethod == appsv1alpha1.HScaleDataCloneMethodBackup {
+					By("Checking backup uses the backup tool method as preferred")
+					Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, backup *dpv1alpha1.Backup) {
+						g.Expect(backup.Spec.BackupMethod).Should(Equal(backupMethodName))
+					})).Should(Succeed())
+				}
 				By("Mocking backup status to completed")
 				Expect(testapps.GetAndChangeObjStatus(&testCtx, backupKey, func(backup *dpv1alpha1.Backup) {
 					backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
@@ -693,9 +702,14 @@ var _ = Describe("Component Controller", func() {
 
 					By("Checking backup policy created from backup policy template")
 					policyName := generateBackupPolicyName(clusterKey.Name, compDef.Name, "")
+					var preferredCloneMethod appsv1alpha1.HScaleDataCloneMethod
+					if compDef.HorizontalScalePolicy != nil {
+						preferredCloneMethod = compDef.HorizontalScalePolicy.PreferredCloneMethod
+					}
 					clusterDef.Spec.ComponentDefs[i].HorizontalScalePolicy = &appsv1alpha1.HorizontalScalePolicy{
 						Type:                     policyType,
 						BackupPolicyTemplateName: backupPolicyTPLName,
+						PreferredCloneMethod:     preferredCloneMethod,
 					}
 
 					Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Name: policyName, Namespace: clusterKey.Namespace},
@@ -1376,6 +1390,60 @@ var _ = Describe("Component Controller", func() {
 		})).Should(Succeed())
 	}
 
+	testCompEnvAdoption := func(compName, compDefName string) {
+		compDefKey := client.ObjectKeyFromObject(compDefObj)
+		Eventually(testapps.GetAndChangeObj(&testCtx, compDefKey, func(compDef *appsv1alpha1.ComponentDefinition) {
+			compDef.Spec.Vars = []appsv1alpha1.EnvVar{
+				{
+					Name: "SERVICE_HOST",
+					ValueFrom: &appsv1alpha1.VarSource{
+						ServiceVarRef: &appsv1alpha1.ServiceVarSelector{
+							ClusterObjectReference: appsv1alpha1.ClusterObjectReference{
+								Name: compDefObj.Spec.Services[0].Name,
+							},
+							ServiceVars: appsv1alpha1.ServiceVars{
+								Host: &appsv1alpha1.VarRequired,
+							},
+						},
+					},
+				},
+			}
+		})).Should(Succeed())
+
+		By("create a cluster with the orphan env configmap left behind by a deleted cluster with the same name")
+		var envCMKey types.NamespacedName
+		createClusterObjV2(compName, compDefObj.Name, func(f *testapps.MockClusterFactory) {
+			clusterName := f.GetObject().Name
+			envCMKey = types.NamespacedName{
+				Namespace: testCtx.DefaultNamespace,
+				Name:      constant.GenerateClusterComponentEnvPattern(clusterName, compName),
+			}
+			staleCM := builder.NewConfigMapBuilder(envCMKey.Namespace, envCMKey.Name).
+				AddLabelsInMap(constant.GetComponentWellKnownLabels(clusterName, compName)).
+				AddLabelsInMap(map[string]string{testCtx.TestObjLabelKey: "true"}).
+				SetData(map[string]string{"SERVICE_HOST": "stale-service-host"}).
+				GetObject()
+			Expect(testCtx.CheckedCreateObj(testCtx.Ctx, staleCM)).Should(Succeed())
+		})
+
+		By("check the env configmap is adopted by the component and regenerated")
+		serviceHost := constant.GenerateComponentServiceName(clusterObj.Name, compName, compDefObj.Spec.Services[0].Name)
+		Eventually(testapps.CheckObj(&testCtx, envCMKey, func(g Gomega, cm *corev1.ConfigMap) {
+			g.Expect(cm.Data).Should(HaveKeyWithValue("SERVICE_HOST", serviceHost))
+			g.Expect(model.IsOwnerOf(compObj, cm)).Should(BeTrue())
+		})).Should(Succeed())
+
+		By("remove the owner reference of the env configmap")
+		Eventually(testapps.GetAndChangeObj(&testCtx, envCMKey, func(cm *corev1.ConfigMap) {
+			cm.OwnerReferences = nil
+		})).Should(Succeed())
+
+		By("check the env configmap is adopted again")
+		Eventually(testapps.CheckObj(&testCtx, envCMKey, func(g Gomega, cm *corev1.ConfigMap) {
+			g.Expect(model.IsOwnerOf(compObj, cm)).Should(BeTrue())
+		})).Should(Succeed())
+	}
+
 	testCompReplicasLimit := func(compName, compDefName string) {
 		replicasLimit := &appsv1alpha1.ReplicasLimit{
 			MinReplicas: 4,
@@ -1874,11 +1942,8 @@ var _ = Describe("Component Controller", func() {
 		By("Set HorizontalScalePolicy")
 		Expect(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(clusterDefObj),
 			func(clusterDef *appsv1alpha1.ClusterDefinition) {
-				for i, def := range clusterDef.Spec.ComponentDefs {
-					if def.Name != compDefName {
-						continue
-					}
-					clusterDef.Spec.ComponentDefs[i].HorizontalScalePolicy =
+				if compDef := clusterDef.GetComponentDefByName(compDefName); compDef != nil {
+					compDef.HorizontalScalePolicy =
 						&appsv1alpha1.HorizontalScalePolicy{Type: appsv1alpha1.HScaleDataClonePolicyCloneVolume,
 							BackupPolicyTemplateName: backupPolicyTPLName}
 				}
@@ -2079,6 +2144,10 @@ var _ = Describe("Component Controller", func() {
 			testCompVars(defaultCompName, compDefName)
 		})
 
+		It("with orphan component env configmap", func() {
+			testCompEnvAdoption(defaultCompName, compDefName)
+		})
+
 		It("with component replicas limit", func() {
 			testCompReplicasLimit(defaultCompName, compDefName)
 		})
@@ -2142,7 +2211,8 @@ var _ = Describe("Component Controller", func() {
 			waitForCreatingResourceCompletely(clusterKey, compNames...)
 		}
 
-		testMultiCompHScale := func(policyType appsv1alpha1.HScaleDataClonePolicyType) {
+		testMultiCompHScale := func(policyType appsv1alpha1.HScaleDataClonePolicyType,
+			preferredCloneMethod appsv1alpha1.HScaleDataCloneMethod) {
 			compNameNDef := map[string]string{
 				statefulCompName:    statefulCompDefName,
 				consensusCompName:   consensusCompDefName,
@@ -2151,6 +2221,20 @@ var _ = Describe("Component Controller", func() {
 			initialReplicas := int32(1)
 			updatedReplicas := int32(3)
 
+			if len(preferredCloneMethod) > 0 {
+				By(fmt.Sprintf("Set preferred clone method to %s", preferredCloneMethod))
+				Expect(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(clusterDefObj),
+					func(clusterDef *appsv1alpha1.ClusterDefinition) {
+						for i := range clusterDef.Spec.ComponentDefs {
+							clusterDef.Spec.ComponentDefs[i].HorizontalScalePolicy = &appsv1alpha1.HorizontalScalePolicy{
+								Type:                     policyType,
+								BackupPolicyTemplateName: backupPolicyTPLName,
+								PreferredCloneMethod:     preferredCloneMethod,
+							}
+						}
+					})()).ShouldNot(HaveOccurred())
+			}
+
 			By("Creating a multi components cluster with VolumeClaimTemplate")
 			pvcSpec := testapps.NewPVCSpec("1Gi")
 
@@ -2169,12 +2253,22 @@ var _ = Describe("Component Controller", func() {
 
 		It("h-scale with volume snapshot", func() {
 			testk8s.MockEnableVolumeSnapshot(&testCtx, testk8s.DefaultStorageClassName)
-			testMultiCompHScale(appsv1alpha1.HScaleDataClonePolicyCloneVolume)
+			testMultiCompHScale(appsv1alpha1.HScaleDataClonePolicyCloneVolume, "")
 		})
 
 		It("h-scale with backup tool", func() {
 			testk8s.MockDisableVolumeSnapshot(&testCtx, testk8s.DefaultStorageClassName)
-			testMultiCompHScale(appsv1alpha1.HScaleDataClonePolicyCloneVolume)
+			testMultiCompHScale(appsv1alpha1.HScaleDataClonePolicyCloneVolume, "")
+		})
+
+		It("h-scale with volume snapshot preferred", func() {
+			testk8s.MockEnableVolumeSnapshot(&testCtx, testk8s.DefaultStorageClassName)
+			testMultiCompHScale(appsv1alpha1.HScaleDataClonePolicyCloneVolume, appsv1alpha1.HScaleDataCloneMethodSnapshot)
+		})
+
+		It("h-scale with backup tool preferred even if volume snapshot is enabled", func() {
+			testk8s.MockEnableVolumeSnapshot(&testCtx, testk8s.DefaultStorageClassName)
+			testMultiCompHScale(appsv1alpha1.HScaleDataClonePolicyCloneVolume, appsv1alpha1.HScaleDataCloneMethodBackup)
 		})
 	})
 
@@ -335,6 +335,18 @@ func (d *backupDataClone) ClearTmpResources() ([]client.Object, error) {
 func (d *backupDataClone) backup() ([]client.Object, error) {
 	objs := make([]client.Object, 0)
 	backupPolicyTplName := d.component.HorizontalScalePolicy.BackupPolicyTemplateName
+	if backupPolicyTplName == "" {
+		// use the same default template as the backup policies of the cluster if no template is specified.
+		backupPolicyTpl, err := getDefaultBackupPolicyTemplate(d.reqCtx.Ctx, d.cli, d.cluster.Spec.ClusterDefRef,
+			d.component.CompDefName, d.component.ClusterCompDefName)
+		if err != nil {
+			return nil, err
+		}
+		if backupPolicyTpl == nil {
+			return nil, intctrlutil.NewNotFound("not found any backup policy template of the component %s", d.component.Name)
+		}
+		backupPolicyTplName = backupPolicyTpl.Name
+	}
 	backupPolicy, err := getBackupPolicyFromTemplate(d.reqCtx, d.cli, d.cluster, d.component.ClusterCompDefName, backupPolicyTplName)
 	if err != nil {
 		return nil, err
@@ -342,11 +354,12 @@ func (d *backupDataClone) backup() ([]client.Object, error) {
 	if backupPolicy == nil {
 		return nil, intctrlutil.NewNotFound("not found any backup policy created by %s", backupPolicyTplName)
 	}
-	volumeSnapshotEnabled, err := isVolumeSnapshotEnabled(d.reqCtx.Ctx, d.cli, d.stsObj, backupVCT(d.component))
+	volumeSnapshotEnabled, err := isVolumeSnapshotEnabled(d.reqCtx.Ctx, d.cli, d.stsObj, backupVCT(d.component),
+		d.component.HorizontalScalePolicy.VolumeSnapshotClassName)
 	if err != nil {
 		return nil, err
 	}
-	backupMethods := getBackupMethods(backupPolicy, volumeSnapshotEnabled)
+	backupMethods := getBackupMethods(backupPolicy, volumeSnapshotEnabled, d.component.HorizontalScalePolicy.PreferredCloneMethod)
 	if len(backupMethods) == 0 {
 		return nil, fmt.Errorf("no backup method found in backup policy %s", backupPolicy.Name)
 	} else if len(backupMethods) > 1 {
@@ -441,8 +454,10 @@ func backupVCT(component *component.SynthesizedComponent) *corev1.PersistentVolu
 	return &vct
 }
 
+// isVolumeSnapshotEnabled checks if the volume of the first pod can be snapshotted, by the pinned
+// VolumeSnapshotClass if specified, or any class whose driver matches the CSI driver of the volume.
 func isVolumeSnapshotEnabled(ctx context.Context, cli client.Client,
-	sts *appsv1.StatefulSet, vct *corev1.PersistentVolumeClaimTemplate) (bool, error) {
+	sts *appsv1.StatefulSet, vct *corev1.PersistentVolumeClaimTemplate, volumeSnapshotClassName string) (bool, error) {
 	if sts == nil || vct == nil {
 		return false, nil
 	}
@@ -455,10 +470,22 @@ func isVolumeSnapshotEnabled(ctx context.Context, cli client.Client,
 		return false, client.IgnoreNotFound(err)
 	}
 
+	if volumeSnapshotClassName != "" {
+		vsc, err := dputils.GetVolumeSnapshotClass(ctx, cli, volumeSnapshotClassName)
+		if err != nil || vsc == nil {
+			return false, err
+		}
+		provisioner, err := dputils.GetPVCProvisioner(ctx, cli, &pvc)
+		if err != nil {
+			return false, err
+		}
+		return provisioner == vsc.Driver, nil
+	}
 	return dputils.IsVolumeSnapshotEnabled(ctx, cli, pvc.Spec.VolumeName)
 }
 
-func getBackupMethods(backupPolicy *dpv1alpha1.BackupPolicy, useVolumeSnapshot bool) []string {
+func getBackupMethods(backupPolicy *dpv1alpha1.BackupPolicy, useVolumeSnapshot bool,
+	preferredCloneMethod appsv1alpha1.HScaleDataCloneMethod) []string {
 	var vsMethods []string
 	var otherMethods []string
 	for _, method := range backupPolicy.Spec.BackupMethods {
@@ -468,6 +495,12 @@ func getBackupMethods(backupPolicy *dpv1alpha1.BackupPolicy, useVolumeSnapshot b
 			otherMethods = append(otherMethods, method.Name)
 		}
 	}
+	if preferredCloneMethod == appsv1alpha1.HScaleDataCloneMethodBackup {
+		if len(otherMethods) == 0 && useVolumeSnapshot {
+			return vsMethods
+		}
+		return otherMethods
+	}
 	if useVolumeSnapshot && len(vsMethods) > 0 {
 		return vsMethods
 	}
@@ -0,0 +1,166 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/rsm"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// handleScheduledRestart triggers the rolling restart of the component according to the restart policy,
+// in the same way as the Restart OpsRequest, by updating the restart annotation of the pod template.
+// It returns a delayed requeue error to reconcile the component when the next restart is due.
+func handleScheduledRestart(transCtx *componentTransformContext, runningRSM, protoRSM *workloads.ReplicatedStateMachine) error {
+	policy := transCtx.SynthesizeComponent.RestartPolicy
+	if policy == nil || runningRSM == nil || protoRSM == nil {
+		return nil
+	}
+	comp := transCtx.Component
+	schedule, err := cron.ParseStandard(policy.Schedule)
+	if err != nil {
+		setScheduledRestartSkippedCondition(transCtx,
+			fmt.Sprintf("invalid schedule %q of the restart policy: %s", policy.Schedule, err.Error()))
+		return nil
+	}
+
+	lastRestartTime := comp.CreationTimestamp.Time
+	if comp.Status.LastScheduledRestartTime != nil {
+		lastRestartTime = comp.Status.LastScheduledRestartTime.Time
+	}
+	// keep overriding the update strategy until the last scheduled restart is done.
+	if isScheduledRestartInProgress(runningRSM, lastRestartTime) {
+		overrideMemberUpdateStrategy(policy, protoRSM)
+	}
+
+	now := time.Now()
+	requeueAfter, err := getScheduledRestartWaitTime(policy, schedule, lastRestartTime, now)
+	if err != nil {
+		return err
+	}
+	if requeueAfter > 0 {
+		return intctrlutil.NewDelayedRequeueError(requeueAfter, "wait for the next scheduled restart")
+	}
+
+	// the restart is due, skip it if the component is not running, the component will be
+	// reconciled again when its phase changes.
+	if comp.Status.Phase != appsv1alpha1.RunningClusterCompPhase {
+		setScheduledRestartSkippedCondition(transCtx,
+			fmt.Sprintf("skip the scheduled restart since the component is in %s phase", comp.Status.Phase))
+		return nil
+	}
+
+	restartTime := metav1.NewTime(now.Truncate(time.Second))
+	if protoRSM.Spec.Template.Annotations == nil {
+		protoRSM.Spec.Template.Annotations = map[string]string{}
+	}
+	protoRSM.Spec.Template.Annotations[constant.RestartAnnotationKey] = restartTime.UTC().Format(time.RFC3339)
+	overrideMemberUpdateStrategy(policy, protoRSM)
+	comp.Status.LastScheduledRestartTime = &restartTime
+	message := fmt.Sprintf("start the scheduled restart of the component with schedule %q", policy.Schedule)
+	meta.SetStatusCondition(&comp.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeScheduledRestart,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: comp.Generation,
+		Reason:             appsv1alpha1.ReasonScheduledRestart,
+		Message:            message,
+	})
+	transCtx.EventRecorder.Event(comp, corev1.EventTypeNormal, appsv1alpha1.ReasonScheduledRestart, message)
+
+	return intctrlutil.NewDelayedRequeueError(schedule.Next(now).Sub(now), "wait for the next scheduled restart")
+}
+
+// setScheduledRestartSkippedCondition sets the ScheduledRestart condition of the component to false,
+// and emits a warning event only if the reason of skipping changes, rather than on every reconciliation.
+func setScheduledRestartSkippedCondition(transCtx *componentTransformContext, message string) {
+	comp := transCtx.Component
+	oldCond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeScheduledRestart)
+	if oldCond == nil || oldCond.Status != metav1.ConditionFalse || oldCond.Message != message {
+		transCtx.EventRecorder.Event(comp, corev1.EventTypeWarning, appsv1alpha1.ReasonScheduledRestartSkipped, message)
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeScheduledRestart,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: comp.Generation,
+		Reason:             appsv1alpha1.ReasonScheduledRestartSkipped,
+		Message:            message,
+	})
+}
+
+// getScheduledRestartWaitTime returns how long to wait for the next scheduled restart after the last one,
+// zero means the restart is due and in the maintenance window.
+func getScheduledRestartWaitTime(policy *appsv1alpha1.ScheduledRestartPolicy,
+	schedule cron.Schedule, lastRestartTime, now time.Time) (time.Duration, error) {
+	if next := schedule.Next(lastRestartTime); next.After(now) {
+		return next.Sub(now), nil
+	}
+	if policy.MaintenanceWindow == nil {
+		return 0, nil
+	}
+	return getMaintenanceWindowWaitTime(policy.MaintenanceWindow, now)
+}
+
+// getMaintenanceWindowWaitTime returns how long to wait for the maintenance window to be open,
+// zero means now is in the window.
+func getMaintenanceWindowWaitTime(window *appsv1alpha1.MaintenanceWindow, now time.Time) (time.Duration, error) {
+	startTime, err := time.Parse("15:04", window.StartTime)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time %q of the maintenance window: %s", window.StartTime, err.Error())
+	}
+	now = now.UTC()
+	// the latest start of the window before now
+	start := time.Date(now.Year(), now.Month(), now.Day(), startTime.Hour(), startTime.Minute(), 0, 0, time.UTC)
+	if start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+	if now.Before(start.Add(window.Duration.Duration)) {
+		return 0, nil
+	}
+	return start.AddDate(0, 0, 1).Sub(now), nil
+}
+
+// isScheduledRestartInProgress checks whether the workload is still rolling for the last scheduled restart.
+func isScheduledRestartInProgress(runningRSM *workloads.ReplicatedStateMachine, lastRestartTime time.Time) bool {
+	restartTime, err := time.Parse(time.RFC3339, runningRSM.Spec.Template.Annotations[constant.RestartAnnotationKey])
+	if err != nil || !restartTime.Equal(lastRestartTime) {
+		return false
+	}
+	return !rsm.IsRSMReady(runningRSM)
+}
+
+// overrideMemberUpdateStrategy overrides the member update strategy of the workload by the restart policy,
+// it only takes effect if the workload is updated by the member update strategy.
+func overrideMemberUpdateStrategy(policy *appsv1alpha1.ScheduledRestartPolicy, protoRSM *workloads.ReplicatedStateMachine) {
+	if policy.UpdateStrategy == nil || protoRSM.Spec.MemberUpdateStrategy == nil {
+		return
+	}
+	strategy := workloads.MemberUpdateStrategy(*policy.UpdateStrategy)
+	protoRSM.Spec.MemberUpdateStrategy = &strategy
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestGetMaintenanceWindowWaitTime(t *testing.T) {
+	window := &appsv1alpha1.MaintenanceWindow{StartTime: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}}
+	cases := []struct {
+		now      time.Time
+		expected time.Duration
+	}{
+		{time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC), 0},
+		{time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC), 0},
+		{time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC), 20 * time.Hour},
+		{time.Date(2024, 1, 2, 21, 30, 0, 0, time.UTC), 30 * time.Minute},
+	}
+	for _, c := range cases {
+		waitTime, err := getMaintenanceWindowWaitTime(window, c.now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if waitTime != c.expected {
+			t.Errorf("expected wait time %s at %s, got %s", c.expected, c.now, waitTime)
+		}
+	}
+}
+
+func TestGetScheduledRestartWaitTime(t *testing.T) {
+	policy := &appsv1alpha1.ScheduledRestartPolicy{Schedule: "0 3 * * *"}
+	schedule, _ := cron.ParseStandard(policy.Schedule)
+	lastRestartTime := time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	waitTime, _ := getScheduledRestartWaitTime(policy, schedule, lastRestartTime, lastRestartTime.Add(time.Hour))
+	if waitTime != 23*time.Hour {
+		t.Errorf("expected to wait for the next schedule, got %s", waitTime)
+	}
+
+	now := lastRestartTime.Add(25 * time.Hour)
+	waitTime, _ = getScheduledRestartWaitTime(policy, schedule, lastRestartTime, now)
+	if waitTime != 0 {
+		t.Errorf("expected the restart is due, got %s", waitTime)
+	}
+
+	policy.MaintenanceWindow = &appsv1alpha1.MaintenanceWindow{StartTime: "05:00", Duration: metav1.Duration{Duration: time.Hour}}
+	waitTime, _ = getScheduledRestartWaitTime(policy, schedule, lastRestartTime, now)
+	if waitTime != time.Hour {
+		t.Errorf("expected to wait for the maintenance window, got %s", waitTime)
+	}
+}
+
+func TestHandleScheduledRestart(t *testing.T) {
+	strategy := workloads.SerialUpdateStrategy
+	newRSM := func() *workloads.ReplicatedStateMachine {
+		return &workloads.ReplicatedStateMachine{Spec: workloads.ReplicatedStateMachineSpec{MemberUpdateStrategy: &strategy}}
+	}
+	parallel := appsv1alpha1.ParallelStrategy
+	recorder := record.NewFakeRecorder(10)
+	transCtx := &componentTransformContext{
+		Context:       context.Background(),
+		EventRecorder: recorder,
+		Component: &appsv1alpha1.Component{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+		},
+		SynthesizeComponent: &component.SynthesizedComponent{
+			RestartPolicy: &appsv1alpha1.ScheduledRestartPolicy{Schedule: "@hourly", UpdateStrategy: &parallel},
+		},
+	}
+
+	// the restart is skipped if the component is not running
+	protoRSM := newRSM()
+	if err := handleScheduledRestart(transCtx, newRSM(), protoRSM); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transCtx.Component.Status.LastScheduledRestartTime != nil || protoRSM.Spec.Template.Annotations != nil {
+		t.Error("expected the restart to be skipped")
+	}
+	// the skip event is emitted only once for the repeated reconciliations
+	if err := handleScheduledRestart(transCtx, newRSM(), protoRSM); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cond := meta.FindStatusCondition(transCtx.Component.Status.Conditions, appsv1alpha1.ConditionTypeScheduledRestart)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != appsv1alpha1.ReasonScheduledRestartSkipped {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(recorder.Events))
+	}
+	<-recorder.Events
+
+	// the restart is triggered if the component is running
+	transCtx.Component.Status.Phase = appsv1alpha1.RunningClusterCompPhase
+	err := handleScheduledRestart(transCtx, newRSM(), protoRSM)
+	if !intctrlutil.IsDelayedRequeueError(err) {
+		t.Fatalf("expected delayed requeue error, got: %v", err)
+	}
+	lastRestartTime := transCtx.Component.Status.LastScheduledRestartTime
+	if lastRestartTime == nil {
+		t.Fatal("expected the last scheduled restart time to be recorded")
+	}
+	if protoRSM.Spec.Template.Annotations[constant.RestartAnnotationKey] != lastRestartTime.UTC().Format(time.RFC3339) {
+		t.Errorf("expected the restart annotation to be set, got: %v", protoRSM.Spec.Template.Annotations)
+	}
+	if *protoRSM.Spec.MemberUpdateStrategy != workloads.ParallelUpdateStrategy {
+		t.Errorf("expected the member update strategy to be overridden, got: %s", *protoRSM.Spec.MemberUpdateStrategy)
+	}
+	cond = meta.FindStatusCondition(transCtx.Component.Status.Conditions, appsv1alpha1.ConditionTypeScheduledRestart)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != appsv1alpha1.ReasonScheduledRestart {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+
+	// the update strategy is kept overridden while the restart is in progress
+	runningRSM := newRSM()
+	runningRSM.Spec.Template.Annotations = protoRSM.Spec.Template.Annotations
+	protoRSM = newRSM()
+	err = handleScheduledRestart(transCtx, runningRSM, protoRSM)
+	if !intctrlutil.IsDelayedRequeueError(err) {
+		t.Fatalf("expected delayed requeue error, got: %v", err)
+	}
+	if !transCtx.Component.Status.LastScheduledRestartTime.Equal(lastRestartTime) {
+		t.Error("expected no new restart before the next schedule")
+	}
+	if *protoRSM.Spec.MemberUpdateStrategy != workloads.ParallelUpdateStrategy {
+		t.Errorf("expected the member update strategy to be overridden, got: %s", *protoRSM.Spec.MemberUpdateStrategy)
+	}
+}
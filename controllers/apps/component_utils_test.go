@@ -20,7 +20,9 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"context"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -29,9 +31,12 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
@@ -56,6 +61,49 @@ func TestIsProbeTimeout(t *testing.T) {
 	}
 }
 
+func TestEndpointSliceEventHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	newComp := func(clusterName, compName string) *appsv1alpha1.Component {
+		return &appsv1alpha1.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      constant.GenerateClusterComponentName(clusterName, compName),
+				Labels:    constant.GetComponentWellKnownLabels(clusterName, compName),
+			},
+		}
+	}
+	r := &ComponentReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newComp("mycluster", "mysql"), newComp("mycluster", "proxy"), newComp("other", "mysql")).
+			Build(),
+	}
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "mycluster-mysql-headless-abcde",
+			Labels:    constant.GetComponentWellKnownLabels("mycluster", "mysql"),
+		},
+	}
+	requests := r.endpointSliceEventHandler(context.Background(), slice)
+	names := make([]string, 0)
+	for _, req := range requests {
+		names = append(names, req.Name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"mycluster-mysql", "mycluster-proxy"}) {
+		t.Errorf("expected all the components of the cluster to be enqueued, got: %v", names)
+	}
+
+	// the endpoint slices not managed by KubeBlocks are ignored
+	slice.Labels = map[string]string{discoveryv1.LabelServiceName: "mycluster-mysql-headless"}
+	if requests = r.endpointSliceEventHandler(context.Background(), slice); len(requests) != 0 {
+		t.Errorf("expected no request, got: %v", requests)
+	}
+}
+
 var _ = Describe("Component Utils", func() {
 	var (
 		randomStr          = testCtx.GetRandomStr()
@@ -0,0 +1,259 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/controllers/k8score"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+)
+
+// volumeProtectionHandledAnnotKey marks the volume protection events that have been handled.
+const volumeProtectionHandledAnnotKey = "apps.kubeblocks.io/volume-protection-handled"
+
+// VolumeProtectionEventHandler handles the events sent by lorry when an instance is locked or unlocked
+// by the volume protection, and reflects the lock state of the instances to the component status.
+type VolumeProtectionEventHandler struct{}
+
+var _ k8score.EventHandler = &VolumeProtectionEventHandler{}
+
+func init() {
+	k8score.EventHandlerMap["volume-protection-handler"] = &VolumeProtectionEventHandler{}
+}
+
+// Handle handles the volume protection events.
+func (h *VolumeProtectionEventHandler) Handle(cli client.Client, reqCtx intctrlutil.RequestCtx, recorder record.EventRecorder, event *corev1.Event) error {
+	if event.InvolvedObject.Kind != constant.PodKind ||
+		(event.Reason != lorryutil.VolumeProtectionLockEventReason && event.Reason != lorryutil.VolumeProtectionUnlockEventReason) {
+		return nil
+	}
+	// filter the events that have been handled
+	count := fmt.Sprintf("count-%d", event.Count)
+	if event.Annotations[volumeProtectionHandledAnnotKey] == count {
+		return nil
+	}
+
+	if err := handleVolumeProtectionEvent(cli, reqCtx, recorder, event); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(event.DeepCopy())
+	if event.Annotations == nil {
+		event.Annotations = map[string]string{}
+	}
+	event.Annotations[volumeProtectionHandledAnnotKey] = count
+	return cli.Patch(reqCtx.Ctx, event, patch)
+}
+
+// handleVolumeProtectionEvent updates the locked volumes of the pod in the component status, the component
+// events are recorded only when the pod is locked or unlocked, so the repeated events of the same state are ignored.
+func handleVolumeProtectionEvent(cli client.Client, reqCtx intctrlutil.RequestCtx, recorder record.EventRecorder, event *corev1.Event) error {
+	pod := &corev1.Pod{}
+	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Namespace: event.InvolvedObject.Namespace, Name: event.InvolvedObject.Name}, pod); err != nil {
+		return err
+	}
+	// event belongs to old pod with the same name, ignore it
+	if pod.UID != event.InvolvedObject.UID {
+		return nil
+	}
+	clusterName := pod.Labels[constant.AppInstanceLabelKey]
+	compName := pod.Labels[constant.KBAppComponentLabelKey]
+	if clusterName == "" || compName == "" {
+		return nil
+	}
+	comp := &appsv1alpha1.Component{}
+	compKey := client.ObjectKey{Namespace: pod.Namespace, Name: constant.GenerateClusterComponentName(clusterName, compName)}
+	if err := cli.Get(reqCtx.Ctx, compKey, comp); err != nil {
+		return err
+	}
+
+	locked := event.Reason == lorryutil.VolumeProtectionLockEventReason
+	var volumes []appsv1alpha1.LockedVolume
+	if locked {
+		var err error
+		if volumes, err = parseLockedVolumes(pod.Name, event.Message); err != nil {
+			reqCtx.Log.Info("parse volume protection event message failed", "message", event.Message, "error", err.Error())
+			return nil
+		}
+	}
+
+	patch := client.MergeFrom(comp.DeepCopy())
+	wasLocked, changed := setPodLockedVolumes(comp, pod.Name, volumes)
+	if !changed {
+		return nil
+	}
+	if err := cli.Status().Patch(reqCtx.Ctx, comp, patch); err != nil {
+		return err
+	}
+	if recorder == nil || wasLocked == locked {
+		return nil
+	}
+	if locked {
+		recorder.Eventf(comp, corev1.EventTypeWarning, appsv1alpha1.ReasonVolumesLocked,
+			"instance %s is locked to read-only as the volumes are running out of space: %s", pod.Name, formatLockedVolumes(volumes))
+	} else {
+		recorder.Eventf(comp, corev1.EventTypeNormal, appsv1alpha1.ReasonVolumesUnlocked,
+			"instance %s is unlocked as the space usage of the volumes is under the low watermark", pod.Name)
+	}
+	return nil
+}
+
+// parseLockedVolumes parses the volumes that exceed the high watermark from the message of the lock event,
+// the message is the volume usages reported by lorry, for example:
+//
+//	{"highWatermark":"90","volumes":[{"data":"95%"},{"log":"50%","highWatermark":"80"}]}
+func parseLockedVolumes(podName, message string) ([]appsv1alpha1.LockedVolume, error) {
+	usages := struct {
+		HighWatermark string              `json:"highWatermark"`
+		Volumes       []map[string]string `json:"volumes"`
+	}{}
+	if err := json.Unmarshal([]byte(message), &usages); err != nil {
+		return nil, err
+	}
+
+	var all, higher []appsv1alpha1.LockedVolume
+	for _, v := range usages.Volumes {
+		highWatermark := usages.HighWatermark
+		if w, ok := v["highWatermark"]; ok {
+			highWatermark = w
+		}
+		for name, usage := range v {
+			if name == "highWatermark" || name == "lowWatermark" {
+				continue
+			}
+			volume := appsv1alpha1.LockedVolume{PodName: podName, VolumeName: name}
+			if percentage, err := strconv.Atoi(strings.TrimSuffix(usage, "%")); err == nil {
+				volume.UsagePercentage = pointer.Int32(int32(percentage))
+			}
+			all = append(all, volume)
+			if watermark, err := strconv.Atoi(highWatermark); err == nil &&
+				volume.UsagePercentage != nil && int(*volume.UsagePercentage) >= watermark {
+				higher = append(higher, volume)
+			}
+		}
+	}
+	// the usages may be changed after the instance is locked, take all volumes as locked in that case.
+	if len(higher) == 0 {
+		higher = all
+	}
+	if len(higher) == 0 {
+		return nil, fmt.Errorf("no volume found in the message")
+	}
+	return higher, nil
+}
+
+// setPodLockedVolumes replaces the locked volumes of the pod in the component status and updates
+// the VolumeProtection condition, it returns whether the pod was locked and whether the status is changed.
+func setPodLockedVolumes(comp *appsv1alpha1.Component, podName string, volumes []appsv1alpha1.LockedVolume) (bool, bool) {
+	wasLocked := false
+	lockedVolumes := make([]appsv1alpha1.LockedVolume, 0)
+	for _, v := range comp.Status.LockedVolumes {
+		if v.PodName == podName {
+			wasLocked = true
+			continue
+		}
+		lockedVolumes = append(lockedVolumes, v)
+	}
+	lockedVolumes = append(lockedVolumes, volumes...)
+	return wasLocked, setLockedVolumes(comp, lockedVolumes)
+}
+
+// pruneLockedVolumes removes the locked volumes of the pods that no longer exist.
+func pruneLockedVolumes(comp *appsv1alpha1.Component, pods []*corev1.Pod) {
+	if len(comp.Status.LockedVolumes) == 0 {
+		return
+	}
+	podNames := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		podNames[pod.Name] = true
+	}
+	lockedVolumes := make([]appsv1alpha1.LockedVolume, 0)
+	for _, v := range comp.Status.LockedVolumes {
+		if podNames[v.PodName] {
+			lockedVolumes = append(lockedVolumes, v)
+		}
+	}
+	setLockedVolumes(comp, lockedVolumes)
+}
+
+// setLockedVolumes sets the locked volumes and the VolumeProtection condition of the component,
+// it returns whether the status is changed.
+func setLockedVolumes(comp *appsv1alpha1.Component, lockedVolumes []appsv1alpha1.LockedVolume) bool {
+	sort.SliceStable(lockedVolumes, func(i, j int) bool {
+		if lockedVolumes[i].PodName != lockedVolumes[j].PodName {
+			return lockedVolumes[i].PodName < lockedVolumes[j].PodName
+		}
+		return lockedVolumes[i].VolumeName < lockedVolumes[j].VolumeName
+	})
+	if len(lockedVolumes) == 0 {
+		lockedVolumes = nil
+	}
+	oldStatus := comp.Status.DeepCopy()
+	comp.Status.LockedVolumes = lockedVolumes
+
+	// the condition is not added until some instance is locked
+	if len(lockedVolumes) == 0 && meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeVolumeProtection) == nil {
+		return !reflect.DeepEqual(oldStatus.LockedVolumes, comp.Status.LockedVolumes)
+	}
+	cond := metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeVolumeProtection,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: comp.Generation,
+		Reason:             appsv1alpha1.ReasonVolumesUnlocked,
+		Message:            "no instance is locked by the volume protection",
+	}
+	if len(lockedVolumes) > 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = appsv1alpha1.ReasonVolumesLocked
+		cond.Message = fmt.Sprintf("instances are locked to read-only: %s", formatLockedVolumes(lockedVolumes))
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, cond)
+	return !reflect.DeepEqual(oldStatus.LockedVolumes, comp.Status.LockedVolumes) ||
+		!reflect.DeepEqual(oldStatus.Conditions, comp.Status.Conditions)
+}
+
+// formatLockedVolumes formats the locked volumes as "pod/volume(usage%)".
+func formatLockedVolumes(lockedVolumes []appsv1alpha1.LockedVolume) string {
+	items := make([]string, 0, len(lockedVolumes))
+	for _, v := range lockedVolumes {
+		usage := "unknown"
+		if v.UsagePercentage != nil {
+			usage = fmt.Sprintf("%d%%", *v.UsagePercentage)
+		}
+		items = append(items, fmt.Sprintf("%s/%s(%s)", v.PodName, v.VolumeName, usage))
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	lorryutil "github.com/apecloud/kubeblocks/pkg/lorry/util"
+)
+
+func TestParseLockedVolumes(t *testing.T) {
+	volumes, err := parseLockedVolumes("pod-0",
+		`{"highWatermark":"90","volumes":[{"data":"95%"},{"log":"85%","highWatermark":"80"},{"tmp":"50%"},{"bin":"<nil>"}]}`)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []appsv1alpha1.LockedVolume{
+		{PodName: "pod-0", VolumeName: "data", UsagePercentage: pointer.Int32(95)},
+		{PodName: "pod-0", VolumeName: "log", UsagePercentage: pointer.Int32(85)},
+	}, volumes)
+
+	// all volumes are taken as locked if no volume exceeds the high watermark
+	volumes, err = parseLockedVolumes("pod-0", `{"highWatermark":"90","volumes":[{"data":"85%"}]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []appsv1alpha1.LockedVolume{{PodName: "pod-0", VolumeName: "data", UsagePercentage: pointer.Int32(85)}}, volumes)
+
+	_, err = parseLockedVolumes("pod-0", `{"highWatermark":"90","volumes":[]}`)
+	assert.Error(t, err)
+	_, err = parseLockedVolumes("pod-0", `not a json`)
+	assert.Error(t, err)
+}
+
+func TestVolumeProtectionEventHandler(t *testing.T) {
+	const (
+		namespace   = "default"
+		clusterName = "mycluster"
+		compName    = "mysql"
+	)
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, appsv1alpha1.AddToScheme(scheme))
+
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				UID:       types.UID("uid-" + name),
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName,
+					constant.KBAppComponentLabelKey: compName,
+				},
+			},
+		}
+	}
+	comp := &appsv1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      constant.GenerateClusterComponentName(clusterName, compName),
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newPod("pod-0"), newPod("pod-1"), comp).
+		WithStatusSubresource(comp).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: logf.Log}
+	handler := &VolumeProtectionEventHandler{}
+
+	eventCount := 0
+	handle := func(podName, reason, message string) {
+		eventCount++
+		event := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: podName + ".event"},
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      constant.PodKind,
+				Namespace: namespace,
+				Name:      podName,
+				UID:       newPod(podName).UID,
+			},
+			Reason:  reason,
+			Message: message,
+			Count:   int32(eventCount),
+		}
+		_ = cli.Delete(reqCtx.Ctx, event)
+		assert.NoError(t, cli.Create(reqCtx.Ctx, event))
+		assert.NoError(t, handler.Handle(cli, reqCtx, recorder, event))
+		assert.Equal(t, fmt.Sprintf("count-%d", eventCount), event.Annotations[volumeProtectionHandledAnnotKey])
+	}
+	getComp := func() *appsv1alpha1.Component {
+		obj := &appsv1alpha1.Component{}
+		assert.NoError(t, cli.Get(reqCtx.Ctx, client.ObjectKeyFromObject(comp), obj))
+		return obj
+	}
+	expectEvents := func(events ...string) {
+		assert.Len(t, recorder.Events, len(events))
+		for _, e := range events {
+			assert.Contains(t, <-recorder.Events, e)
+		}
+	}
+	lockMessage := `{"highWatermark":"90","volumes":[{"data":"95%"},{"log":"50%"}]}`
+	unlockMessage := `{"highWatermark":"90","volumes":[{"data":"60%"},{"log":"50%"}]}`
+
+	// the condition is not added before any instance is locked
+	handle("pod-0", lorryutil.VolumeProtectionUnlockEventReason, unlockMessage)
+	assert.Nil(t, meta.FindStatusCondition(getComp().Status.Conditions, appsv1alpha1.ConditionTypeVolumeProtection))
+	expectEvents()
+
+	handle("pod-0", lorryutil.VolumeProtectionLockEventReason, lockMessage)
+	status := getComp().Status
+	assert.Equal(t, []appsv1alpha1.LockedVolume{{PodName: "pod-0", VolumeName: "data", UsagePercentage: pointer.Int32(95)}}, status.LockedVolumes)
+	cond := meta.FindStatusCondition(status.Conditions, appsv1alpha1.ConditionTypeVolumeProtection)
+	assert.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Equal(t, appsv1alpha1.ReasonVolumesLocked, cond.Reason)
+	assert.Contains(t, cond.Message, "pod-0/data(95%)")
+	expectEvents("Warning " + appsv1alpha1.ReasonVolumesLocked)
+
+	// repeated lock events of the same state are debounced
+	handle("pod-0", lorryutil.VolumeProtectionLockEventReason, lockMessage)
+	expectEvents()
+
+	handle("pod-1", lorryutil.VolumeProtectionLockEventReason, lockMessage)
+	assert.Len(t, getComp().Status.LockedVolumes, 2)
+	expectEvents("Warning " + appsv1alpha1.ReasonVolumesLocked)
+
+	handle("pod-0", lorryutil.VolumeProtectionUnlockEventReason, unlockMessage)
+	status = getComp().Status
+	assert.Equal(t, []appsv1alpha1.LockedVolume{{PodName: "pod-1", VolumeName: "data", UsagePercentage: pointer.Int32(95)}}, status.LockedVolumes)
+	assert.Equal(t, metav1.ConditionTrue, meta.FindStatusCondition(status.Conditions, appsv1alpha1.ConditionTypeVolumeProtection).Status)
+	expectEvents("Normal " + appsv1alpha1.ReasonVolumesUnlocked)
+
+	handle("pod-1", lorryutil.VolumeProtectionUnlockEventReason, unlockMessage)
+	handle("pod-1", lorryutil.VolumeProtectionUnlockEventReason, unlockMessage)
+	status = getComp().Status
+	assert.Empty(t, status.LockedVolumes)
+	cond = meta.FindStatusCondition(status.Conditions, appsv1alpha1.ConditionTypeVolumeProtection)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, appsv1alpha1.ReasonVolumesUnlocked, cond.Reason)
+	expectEvents("Normal " + appsv1alpha1.ReasonVolumesUnlocked)
+}
+
+func TestPruneLockedVolumes(t *testing.T) {
+	comp := &appsv1alpha1.Component{}
+	setPodLockedVolumes(comp, "pod-0", []appsv1alpha1.LockedVolume{{PodName: "pod-0", VolumeName: "data"}})
+	setPodLockedVolumes(comp, "pod-1", []appsv1alpha1.LockedVolume{{PodName: "pod-1", VolumeName: "data"}})
+
+	pruneLockedVolumes(comp, []*corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}}})
+	assert.Equal(t, []appsv1alpha1.LockedVolume{{PodName: "pod-1", VolumeName: "data"}}, comp.Status.LockedVolumes)
+
+	pruneLockedVolumes(comp, nil)
+	assert.Empty(t, comp.Status.LockedVolumes)
+	cond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeVolumeProtection)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+}
@@ -87,7 +87,7 @@ func (r *ComponentDefinitionReconciler) reconcile(rctx intctrlutil.RequestCtx,
 	}
 
 	if cmpd.Status.ObservedGeneration == cmpd.Generation &&
-		slices.Contains([]appsv1alpha1.Phase{appsv1alpha1.AvailablePhase}, cmpd.Status.Phase) {
+		slices.Contains(cmpd.Status.GetTerminalPhases(), cmpd.Status.Phase) {
 		return intctrlutil.Reconciled()
 	}
 
@@ -159,6 +159,7 @@ func (r *ComponentDefinitionReconciler) validate(cli client.Client, rctx intctrl
 		r.validateReplicaRoles,
 		r.validateLifecycleActions,
 		r.validateComponentDefRef,
+		r.validateMonitor,
 	} {
 		if err := validator(cli, rctx, cmpd); err != nil {
 			return err
@@ -289,6 +290,14 @@ func (r *ComponentDefinitionReconciler) validateReplicasLimit(cli client.Client,
 	return nil
 }
 
+func (r *ComponentDefinitionReconciler) validateMonitor(cli client.Client, rctx intctrlutil.RequestCtx,
+	cmpd *appsv1alpha1.ComponentDefinition) error {
+	if cmpd.Spec.Monitor == nil || cmpd.Spec.Monitor.Exporter == nil {
+		return nil
+	}
+	return cmpd.Spec.Monitor.Exporter.Validate()
+}
+
 func (r *ComponentDefinitionReconciler) validateSystemAccounts(cli client.Client, rctx intctrlutil.RequestCtx,
 	cmpd *appsv1alpha1.ComponentDefinition) error {
 	for _, v := range cmpd.Spec.SystemAccounts {
@@ -365,6 +365,12 @@ func validateConfigTemplate(cli client.Client, ctx intctrlutil.RequestCtx, confi
 	return true, nil
 }
 
+// ValidateConfigSpecs checks whether the config templates and config constraints referenced by the configSpecs are valid.
+func ValidateConfigSpecs(cli client.Client, ctx intctrlutil.RequestCtx, configSpecs []appsv1alpha1.ComponentConfigSpec) error {
+	_, err := validateConfigTemplate(cli, ctx, configSpecs)
+	return err
+}
+
 func validateConfigConstraintStatus(ccStatus appsv1alpha1.ConfigConstraintStatus) bool {
 	return ccStatus.Phase == appsv1alpha1.CCAvailablePhase
 }
@@ -140,9 +140,10 @@ func buildBackup(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRequest *a
 
 	backup := &dpv1alpha1.Backup{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      backupSpec.BackupName,
-			Namespace: cluster.Namespace,
-			Labels:    getBackupLabels(cluster.Name, opsRequest.Name),
+			Name:        backupSpec.BackupName,
+			Namespace:   cluster.Namespace,
+			Labels:      getBackupLabels(cluster.Name, opsRequest.Name),
+			Annotations: getBackupAnnotations(opsRequest),
 		},
 		Spec: dpv1alpha1.BackupSpec{
 			BackupPolicyName: backupSpec.BackupPolicyName,
@@ -169,6 +170,9 @@ func buildBackup(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRequest *a
 		if parentBackup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
 			return nil, fmt.Errorf("parent backup %s is not completed", backupSpec.ParentBackupName)
 		}
+		if parentBackup.IsDryRun() {
+			return nil, fmt.Errorf("parent backup %s is a dry run", backupSpec.ParentBackupName)
+		}
 		// check parent backup belongs to the cluster of the backup
 		if parentBackup.Labels[constant.AppInstanceLabelKey] != cluster.Name {
 			return nil, fmt.Errorf("parent backup %s is not belong to cluster %s", backupSpec.ParentBackupName, cluster.Name)
@@ -179,6 +183,17 @@ func buildBackup(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRequest *a
 	return backup, nil
 }
 
+// getBackupAnnotations records the OpsRequest and its requestor which created the backup for auditing.
+func getBackupAnnotations(opsRequest *appsv1alpha1.OpsRequest) map[string]string {
+	annotations := map[string]string{
+		dptypes.CreatedByAnnotationKey: dptypes.CreatedByOpsRequestPrefix + opsRequest.Name,
+	}
+	if requestor := opsRequest.Annotations[constant.OpsRequestRequestorAnnotationKey]; requestor != "" {
+		annotations[dptypes.RequestedByAnnotationKey] = requestor
+	}
+	return annotations
+}
+
 func getDefaultBackupPolicy(reqCtx intctrlutil.RequestCtx, cli client.Client, cluster *appsv1alpha1.Cluster, backupPolicy string) (string, error) {
 	// if backupPolicy is not empty, return it directly
 	if backupPolicy != "" {
@@ -57,13 +57,17 @@ func buildComponentEnvs(reqCtx intctrlutil.RequestCtx,
 	env *[]corev1.EnvVar,
 	comp *appsv1alpha1.ClusterComponentSpec) error {
 	// inject built-in component env
+	synthesizedComp, err := component.BuildSynthesizedComponentWrapper(reqCtx, cli, cluster, comp)
+	if err != nil {
+		return err
+	}
 	fullCompName := constant.GenerateClusterComponentName(cluster.Name, comp.Name)
 	*env = append(*env, []corev1.EnvVar{
 		{Name: constant.KBEnvClusterName, Value: cluster.Name},
 		{Name: constant.KBEnvCompName, Value: comp.Name},
 		{Name: constant.KBEnvClusterCompName, Value: fullCompName},
 		{Name: constant.KBEnvCompReplicas, Value: strconv.Itoa(int(comp.Replicas))},
-		{Name: kbEnvCompHeadlessSVCName, Value: constant.GenerateDefaultComponentHeadlessServiceName(cluster.Name, comp.Name)},
+		{Name: kbEnvCompHeadlessSVCName, Value: component.HeadlessServiceName(synthesizedComp)},
 	}...)
 	if len(opsDef.Spec.ComponentDefinitionRefs) == 0 {
 		return nil
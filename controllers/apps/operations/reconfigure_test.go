@@ -164,11 +164,7 @@ var _ = Describe("Reconfigure OpsRequest", func() {
 			cmObj, tplObj := assureCfgTplObj("mysql-tpl-test", "mysql-cm-test", testCtx.DefaultNamespace)
 			By("update clusterdefinition tpl")
 			patch := client.MergeFrom(clusterDef.DeepCopy())
-			for i := range clusterDef.Spec.ComponentDefs {
-				component := &clusterDef.Spec.ComponentDefs[i]
-				if component.Name != consensusComp {
-					continue
-				}
+			if component := clusterDef.GetComponentDefByName(consensusComp); component != nil {
 				stsComponent = component
 				component.ConfigSpecs = []appsv1alpha1.ComponentConfigSpec{{
 					ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
@@ -98,6 +99,9 @@ func (r switchoverOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli
 
 	expectCount, actualCount, err := handleSwitchoverProgress(reqCtx, cli, opsRes)
 	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return appsv1alpha1.OpsFailedPhase, 0, err
+		}
 		return "", 0, err
 	}
 
@@ -146,11 +150,16 @@ func doSwitchoverComponents(reqCtx intctrlutil.RequestCtx, cli client.Client, op
 			opsRequest.Status.Components[switchover.ComponentName] = appsv1alpha1.OpsRequestComponentStatus{
 				Phase:           appsv1alpha1.UpdatingClusterCompPhase,
 				ProgressDetails: []appsv1alpha1.ProgressStatusDetail{},
+				Parameters:      switchover.Parameters,
 			}
 		}
 		if err := createSwitchoverJob(reqCtx, cli, opsRes.Cluster, synthesizedComp, &switchover); err != nil {
 			return err
 		}
+		jobName := genSwitchoverJobName(opsRes.Cluster.Name, synthesizedComp.Name, opsRes.Cluster.Generation)
+		if err := setComponentSwitchoverProgressing(reqCtx.Ctx, cli, opsRes.Cluster, synthesizedComp, jobName); err != nil {
+			return err
+		}
 	}
 	if !reflect.DeepEqual(*oldOpsRequestStatus, opsRequest.Status) {
 		if err := cli.Status().Patch(reqCtx.Ctx, opsRequest, patch); err != nil {
@@ -173,6 +182,7 @@ func handleSwitchoverProgress(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		oldOpsRequestStatus = opsRequest.Status.DeepCopy()
 		consistency         bool
 		err                 error
+		checkErr            error
 	)
 	patch := client.MergeFrom(opsRequest.DeepCopy())
 	succeedJobs := make([]string, 0, len(opsRes.OpsRequest.Spec.SwitchoverList))
@@ -195,7 +205,31 @@ func handleSwitchoverProgress(reqCtx intctrlutil.RequestCtx, cli client.Client,
 			ObjectKey: getProgressObjectKey(KBSwitchoverCheckJobKey, jobName),
 			Status:    appsv1alpha1.ProcessingProgressStatus,
 		}
+		// record the results of the phases executed by the switchover job, and abort the switchover
+		// if the preCheck or postCheck fails.
+		failedPhase, errRecord := recordSwitchoverPhaseResults(reqCtx, cli, opsRes, switchover.ComponentName, jobName)
+		if errRecord != nil {
+			err = errRecord
+			break
+		}
+		if failedPhase != nil && failedPhase.Phase != KBSwitchoverPhaseSwitchover {
+			if errSet := setComponentSwitchoverFinished(reqCtx, cli, opsRes.Cluster, switchover.ComponentName, jobName, switchoverCondition, false); errSet != nil {
+				err = errSet
+				break
+			}
+			checkErr = handleSwitchoverCheckFailed(reqCtx.Recorder, opsRequest, switchover.ComponentName, jobName, failedPhase)
+			checkJobProcessDetail.Message = checkErr.Error()
+			checkJobProcessDetail.Status = appsv1alpha1.FailedProgressStatus
+			setComponentSwitchoverProgressDetails(reqCtx.Recorder, opsRequest, appsv1alpha1.FailedClusterCompPhase, checkJobProcessDetail, switchover.ComponentName)
+			continue
+		}
 		if err = component.CheckJobSucceed(reqCtx.Ctx, cli, opsRes.Cluster, jobName); err != nil {
+			if errors.Is(err, component.ErrJobFailed) {
+				if errSet := setComponentSwitchoverFinished(reqCtx, cli, opsRes.Cluster, switchover.ComponentName, jobName, switchoverCondition, false); errSet != nil {
+					err = errSet
+					break
+				}
+			}
 			checkJobProcessDetail.Message = fmt.Sprintf("switchover job %s is not succeed", jobName)
 			setComponentSwitchoverProgressDetails(reqCtx.Recorder, opsRequest, appsv1alpha1.UpdatingClusterCompPhase, checkJobProcessDetail, switchover.ComponentName)
 			continue
@@ -237,6 +271,9 @@ func handleSwitchoverProgress(reqCtx intctrlutil.RequestCtx, cli client.Client,
 		}
 
 		// component switchover is successful
+		if err = setComponentSwitchoverFinished(reqCtx, cli, opsRes.Cluster, switchover.ComponentName, jobName, switchoverCondition, true); err != nil {
+			break
+		}
 		completedCount += 1
 		succeedJobs = append(succeedJobs, jobName)
 		componentProcessDetail := appsv1alpha1.ProgressStatusDetail{
@@ -258,6 +295,9 @@ func handleSwitchoverProgress(reqCtx intctrlutil.RequestCtx, cli client.Client,
 	if err != nil {
 		return expectCount, completedCount, err
 	}
+	if checkErr != nil {
+		return expectCount, completedCount, checkErr
+	}
 
 	if completedCount == expectCount {
 		for _, jobName := range succeedJobs {
@@ -282,5 +322,63 @@ func setComponentSwitchoverProgressDetails(recorder record.EventRecorder,
 	opsRequest.Status.Components[componentName] = appsv1alpha1.OpsRequestComponentStatus{
 		Phase:           phase,
 		ProgressDetails: componentProcessDetails,
+		Parameters:      opsRequest.Status.Components[componentName].Parameters,
+	}
+}
+
+// recordSwitchoverPhaseResults records the exit code and output of each finished phase of the switchover job
+// in the component progress details, and returns the first failed phase if exists.
+func recordSwitchoverPhaseResults(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	componentName string,
+	jobName string) (*switchoverPhaseResult, error) {
+	phaseResults, err := getSwitchoverPhaseResults(reqCtx.Ctx, cli, opsRes.Cluster, jobName)
+	if err != nil {
+		return nil, err
+	}
+	var failedPhase *switchoverPhaseResult
+	for i, result := range phaseResults {
+		phaseProcessDetail := appsv1alpha1.ProgressStatusDetail{
+			ObjectKey: getProgressObjectKey(result.Phase, jobName),
+			Status:    appsv1alpha1.SucceedProgressStatus,
+			Message:   fmt.Sprintf("%s of switchover job %s exited with code %d", result.Phase, jobName, result.ExitCode),
+		}
+		if result.Output != "" {
+			phaseProcessDetail.Message = fmt.Sprintf("%s, output: %s", phaseProcessDetail.Message, result.Output)
+		}
+		if result.ExitCode != 0 {
+			phaseProcessDetail.Status = appsv1alpha1.FailedProgressStatus
+			if failedPhase == nil {
+				failedPhase = &phaseResults[i]
+			}
+		}
+		setComponentSwitchoverProgressDetails(reqCtx.Recorder, opsRes.OpsRequest, appsv1alpha1.UpdatingClusterCompPhase, phaseProcessDetail, componentName)
+	}
+	return failedPhase, nil
+}
+
+// handleSwitchoverCheckFailed sends a warning event for the failed preCheck or postCheck of the switchover job,
+// and returns a fatal error to fail the opsRequest.
+func handleSwitchoverCheckFailed(recorder record.EventRecorder,
+	opsRequest *appsv1alpha1.OpsRequest,
+	componentName string,
+	jobName string,
+	failedPhase *switchoverPhaseResult) error {
+	var reason, message string
+	switch failedPhase.Phase {
+	case KBSwitchoverPhasePreCheck:
+		reason = ReasonSwitchoverPreCheckFailed
+		message = fmt.Sprintf("the preCheck of switchover job %s failed with exit code %d, the switchover of component %s is aborted",
+			jobName, failedPhase.ExitCode, componentName)
+	default:
+		reason = ReasonSwitchoverRollback
+		message = fmt.Sprintf("the postCheck of switchover job %s failed with exit code %d, the switchover of component %s needs to be rolled back",
+			jobName, failedPhase.ExitCode, componentName)
+	}
+	if failedPhase.Output != "" {
+		message = fmt.Sprintf("%s, output: %s", message, failedPhase.Output)
 	}
+	recorder.Event(opsRequest, corev1.EventTypeWarning, reason, message)
+	return intctrlutil.NewFatalError(message)
 }
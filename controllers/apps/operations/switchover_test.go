@@ -27,6 +27,8 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -88,6 +90,7 @@ var _ = Describe("", func() {
 		ml := client.HasLabels{testCtx.TestObjLabelKey}
 		// namespaced
 		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.ComponentSignature, inNS, ml)
 	}
 
 	BeforeEach(cleanEnv)
@@ -175,6 +178,11 @@ var _ = Describe("", func() {
 				}
 			}
 
+			By("Creating the component object.")
+			compObj := testapps.NewComponentFactory(testCtx.DefaultNamespace,
+				constant.GenerateClusterComponentName(clusterObj.Name, consensusComp), "").
+				Create(&testCtx).GetObject()
+
 			opsRes := &OpsResource{
 				Cluster:  clusterObj,
 				Recorder: k8sManager.GetEventRecorderFor("opsrequest-controller"),
@@ -212,6 +220,14 @@ var _ = Describe("", func() {
 			By("do switchover action")
 			_, err = GetOpsManager().Do(reqCtx, k8sClient, opsRes)
 			Expect(err).ShouldNot(HaveOccurred())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(compObj), func(g Gomega, comp *appsv1alpha1.Component) {
+				cond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeSwitchover)
+				g.Expect(cond).ShouldNot(BeNil())
+				g.Expect(cond.Status).Should(Equal(metav1.ConditionUnknown))
+				g.Expect(cond.Reason).Should(Equal(appsv1alpha1.ReasonSwitchoverProgressing))
+				g.Expect(cond.Message).Should(ContainSubstring(leaderPod.Name))
+				g.Expect(comp.Status.LastSwitchoverTime).Should(BeNil())
+			})).Should(Succeed())
 
 			By("do reconcile switchoverAction failed because switchover job status failed")
 			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
@@ -240,6 +256,16 @@ var _ = Describe("", func() {
 			})).Should(Succeed())
 			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsRes)
 			Expect(err).ShouldNot(HaveOccurred())
+
+			By("check the switchover condition of the component is succeed")
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(compObj), func(g Gomega, comp *appsv1alpha1.Component) {
+				cond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeSwitchover)
+				g.Expect(cond).ShouldNot(BeNil())
+				g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+				g.Expect(cond.Reason).Should(Equal(appsv1alpha1.ReasonSwitchoverSucceed))
+				g.Expect(cond.Message).Should(ContainSubstring(fmt.Sprintf("old primary: %s, new primary: %s", leaderPod.Name, followerPod.Name)))
+				g.Expect(comp.Status.LastSwitchoverTime).ShouldNot(BeNil())
+			})).Should(Succeed())
 		})
 	})
 })
@@ -23,13 +23,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -52,9 +55,23 @@ const (
 	KBSwitchoverCheckJobKey       = "CheckJob"
 	KBSwitchoverCheckRoleLabelKey = "CheckRoleLabel"
 
+	KBSwitchoverPreCheckContainerName  = "kb-switchover-precheck-container"
+	KBSwitchoverPostCheckContainerName = "kb-switchover-postcheck-container"
+
+	// the phases executed by the switchover job in order.
+	KBSwitchoverPhasePreCheck   = "PreCheck"
+	KBSwitchoverPhaseSwitchover = "Switchover"
+	KBSwitchoverPhasePostCheck  = "PostCheck"
+
+	ReasonSwitchoverPreCheckFailed = "SwitchoverPreCheckFailed"
+	ReasonSwitchoverRollback       = "SwitchoverRollback"
+
 	KBSwitchoverCandidateName = "KB_SWITCHOVER_CANDIDATE_NAME"
 	KBSwitchoverCandidateFqdn = "KB_SWITCHOVER_CANDIDATE_FQDN"
 
+	// KBSwitchoverParamEnvPrefix is the prefix of the envs of the switchover parameters.
+	KBSwitchoverParamEnvPrefix = "KB_SWITCHOVER_PARAM_"
+
 	// KBSwitchoverReplicationPrimaryPodIP and the others Replication and Consensus switchover constants will be deprecated in the future, use KBSwitchoverLeaderPodIP instead.
 	KBSwitchoverReplicationPrimaryPodIP   = "KB_REPLICATION_PRIMARY_POD_IP"
 	KBSwitchoverReplicationPrimaryPodName = "KB_REPLICATION_PRIMARY_POD_NAME"
@@ -139,6 +156,107 @@ func createSwitchoverJob(reqCtx intctrlutil.RequestCtx,
 	return nil
 }
 
+// setComponentSwitchoverProgressing sets the switchover condition of the component to progressing with the switchover job.
+func setComponentSwitchoverProgressing(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	synthesizedComp *component.SynthesizedComponent,
+	jobName string) error {
+	var oldPrimary string
+	pod, err := getServiceableNWritablePod(ctx, cli, *cluster, *synthesizedComp)
+	if err != nil {
+		return err
+	}
+	if pod != nil {
+		oldPrimary = pod.Name
+	}
+	message := fmt.Sprintf("switchover job %s is running, old primary: %s", jobName, oldPrimary)
+	return setComponentSwitchoverCondition(ctx, cli, cluster, synthesizedComp.Name, metav1.ConditionUnknown,
+		appsv1alpha1.ReasonSwitchoverProgressing, message)
+}
+
+// setComponentSwitchoverFinished sets the switchover condition of the component to the result of the switchover job,
+// the message contains the old and new primary pod names and the termination message of the job.
+func setComponentSwitchoverFinished(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	compName string,
+	jobName string,
+	switchoverCondition *metav1.Condition,
+	succeed bool) error {
+	var newPrimary string
+	compSpec := cluster.Spec.GetComponentByName(compName)
+	if synthesizedComp, err := component.BuildSynthesizedComponentWrapper(reqCtx, cli, cluster, compSpec); err == nil {
+		if pod, _ := getServiceableNWritablePod(reqCtx.Ctx, cli, *cluster, *synthesizedComp); pod != nil {
+			newPrimary = pod.Name
+		}
+	}
+	terminationMessage, err := component.GetJobTerminationMessage(reqCtx.Ctx, cli, cluster, jobName)
+	if err != nil {
+		return err
+	}
+	var (
+		status = metav1.ConditionTrue
+		reason = appsv1alpha1.ReasonSwitchoverSucceed
+		result = "succeed"
+	)
+	if !succeed {
+		status = metav1.ConditionFalse
+		reason = appsv1alpha1.ReasonSwitchoverFailed
+		result = "failed"
+	}
+	message := fmt.Sprintf("switchover job %s %s, old primary: %s, new primary: %s",
+		jobName, result, getSwitchoverOldPrimary(switchoverCondition, compName), newPrimary)
+	if terminationMessage != "" {
+		message = fmt.Sprintf("%s, message: %s", message, terminationMessage)
+	}
+	return setComponentSwitchoverCondition(reqCtx.Ctx, cli, cluster, compName, status, reason, message)
+}
+
+// setComponentSwitchoverCondition sets the switchover condition of the component status,
+// and records the last switchover time if the switchover is finished.
+func setComponentSwitchoverCondition(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	compName string,
+	status metav1.ConditionStatus,
+	reason, message string) error {
+	comp := &appsv1alpha1.Component{}
+	compKey := types.NamespacedName{Namespace: cluster.Namespace, Name: constant.GenerateClusterComponentName(cluster.Name, compName)}
+	if err := cli.Get(ctx, compKey, comp); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	cond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeSwitchover)
+	if cond != nil && cond.Status == status && cond.Reason == reason && cond.Message == message {
+		return nil
+	}
+	patch := client.MergeFrom(comp.DeepCopy())
+	meta.SetStatusCondition(&comp.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeSwitchover,
+		Status:             status,
+		ObservedGeneration: comp.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	if status != metav1.ConditionUnknown {
+		now := metav1.Now()
+		comp.Status.LastSwitchoverTime = &now
+	}
+	return cli.Status().Patch(ctx, comp, patch)
+}
+
+// getSwitchoverOldPrimary gets the old primary pod name of the component from the switchover condition of the OpsRequest.
+func getSwitchoverOldPrimary(switchoverCondition *metav1.Condition, compName string) string {
+	if switchoverCondition == nil {
+		return ""
+	}
+	var switchoverMessageMap map[string]SwitchoverMessage
+	if err := json.Unmarshal([]byte(switchoverCondition.Message), &switchoverMessageMap); err != nil {
+		return ""
+	}
+	return switchoverMessageMap[compName].OldPrimary
+}
+
 // checkPodRoleLabelConsistency checks whether the pod role label is consistent with the specified role label after switchover.
 func checkPodRoleLabelConsistency(ctx context.Context,
 	cli client.Client,
@@ -253,6 +371,70 @@ func renderSwitchoverCmdJob(ctx context.Context,
 			return nil, errors.New("switchover exec action not found")
 		}
 		volumes, volumeMounts := renderJobPodVolumes(scriptSpecSelectors)
+		renderContainer := func(name string, action *appsv1alpha1.Action, envs []corev1.EnvVar, envFroms []corev1.EnvFromSource) (corev1.Container, error) {
+			resources, err := intctrlutil.BuildCmdExecutorResources(action.Resources)
+			if err != nil {
+				return corev1.Container{}, err
+			}
+			container := corev1.Container{
+				Name:            name,
+				Image:           action.Image,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				Command:         action.Exec.Command,
+				Args:            action.Exec.Args,
+				Env:             envs,
+				EnvFrom:         envFroms,
+				VolumeMounts:    volumeMounts,
+				Resources:       resources,
+				// record the output of the failed phase for troubleshooting.
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			}
+			intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+			return container, nil
+		}
+		renderCheckContainer := func(name string, check *appsv1alpha1.Action) (*corev1.Container, error) {
+			if check == nil || check.Exec == nil {
+				return nil, nil
+			}
+			// fall back to the image of the switchover action if the check does not specify one.
+			if check.Image == "" {
+				check = check.DeepCopy()
+				check.Image = cmdExecutorConfig.Image
+			}
+			// the envs of the check take precedence over the switchover envs.
+			envs := append(append([]corev1.EnvVar{}, switchoverEnvs...), check.Env...)
+			envFroms := append(append([]corev1.EnvFromSource{}, cmdExecutorConfig.EnvFrom...), check.EnvFrom...)
+			container, err := renderContainer(name, check, envs, envFroms)
+			if err != nil {
+				return nil, err
+			}
+			return &container, nil
+		}
+
+		// the phases are executed in order: preCheck -> switchover -> postCheck,
+		// all but the last phase are rendered as init containers so that the job
+		// stops at the first failed phase.
+		switchoverContainer, err := renderContainer(KBSwitchoverJobContainerName, cmdExecutorConfig, switchoverEnvs, cmdExecutorConfig.EnvFrom)
+		if err != nil {
+			return nil, err
+		}
+		preCheckContainer, err := renderCheckContainer(KBSwitchoverPreCheckContainerName, switchoverSpec.PreCheck)
+		if err != nil {
+			return nil, err
+		}
+		postCheckContainer, err := renderCheckContainer(KBSwitchoverPostCheckContainerName, switchoverSpec.PostCheck)
+		if err != nil {
+			return nil, err
+		}
+		var initContainers []corev1.Container
+		if preCheckContainer != nil {
+			initContainers = append(initContainers, *preCheckContainer)
+		}
+		containers := []corev1.Container{switchoverContainer}
+		if postCheckContainer != nil {
+			initContainers = append(initContainers, switchoverContainer)
+			containers = []corev1.Container{*postCheckContainer}
+		}
 
 		// jobName named with generation to distinguish different switchover jobs.
 		jobName := genSwitchoverJobName(cluster.Name, synthesizedComp.Name, cluster.Generation)
@@ -269,25 +451,18 @@ func renderSwitchoverCmdJob(ctx context.Context,
 						Name:      jobName,
 					},
 					Spec: corev1.PodSpec{
-						Volumes:       volumes,
-						RestartPolicy: corev1.RestartPolicyNever,
-						Containers: []corev1.Container{
-							{
-								Name:            KBSwitchoverJobContainerName,
-								Image:           cmdExecutorConfig.Image,
-								ImagePullPolicy: corev1.PullIfNotPresent,
-								Command:         cmdExecutorConfig.Exec.Command,
-								Args:            cmdExecutorConfig.Exec.Args,
-								Env:             switchoverEnvs,
-								VolumeMounts:    volumeMounts,
-							},
-						},
+						Volumes:        volumes,
+						RestartPolicy:  corev1.RestartPolicyNever,
+						InitContainers: initContainers,
+						Containers:     containers,
 					},
 				},
 			},
 		}
-		for i := range job.Spec.Template.Spec.Containers {
-			intctrlutil.InjectZeroResourcesLimitsIfEmpty(&job.Spec.Template.Spec.Containers[i])
+		if len(initContainers) > 0 {
+			// do not retry the job if any check fails, the switchover must not be executed
+			// again after the preCheck or postCheck failed.
+			job.Spec.BackoffLimit = pointer.Int32(0)
 		}
 		if len(cluster.Spec.Tolerations) > 0 {
 			job.Spec.Template.Spec.Tolerations = cluster.Spec.Tolerations
@@ -323,10 +498,9 @@ func getSwitchoverCmdJobLabel(clusterName, componentName string) map[string]stri
 
 // buildSwitchoverCandidateEnv builds the candidate instance name environment variable for the switchover job.
 func buildSwitchoverCandidateEnv(
-	cluster *appsv1alpha1.Cluster,
-	componentName string,
+	synthesizeComp *component.SynthesizedComponent,
 	switchover *appsv1alpha1.Switchover) []corev1.EnvVar {
-	svcName := strings.Join([]string{cluster.Name, componentName, "headless"}, "-")
+	svcName := component.HeadlessServiceName(synthesizeComp)
 	if switchover == nil {
 		return nil
 	}
@@ -362,6 +536,9 @@ func buildSwitchoverEnvs(ctx context.Context,
 
 	// replace secret env and merge envs defined in SwitchoverSpec
 	replaceSwitchoverConnCredentialEnv(synthesizeComp.LifecycleActions.Switchover, cluster.Name, synthesizeComp.Name)
+	if err := replaceSwitchoverBuiltinEnvVars(ctx, cli, cluster, synthesizeComp); err != nil {
+		return nil, err
+	}
 	var switchoverEnvs []corev1.EnvVar
 	switch switchover.InstanceName {
 	case KBSwitchoverCandidateInstanceForAnyPod:
@@ -382,11 +559,32 @@ func buildSwitchoverEnvs(ctx context.Context,
 	switchoverEnvs = append(switchoverEnvs, workloadEnvs...)
 
 	// inject the candidate instance name into the environment variable if specify the candidate instance
-	switchoverCandidateEnvs := buildSwitchoverCandidateEnv(cluster, synthesizeComp.Name, switchover)
+	switchoverCandidateEnvs := buildSwitchoverCandidateEnv(synthesizeComp, switchover)
 	switchoverEnvs = append(switchoverEnvs, switchoverCandidateEnvs...)
+
+	// inject the parameters of the switchover into the environment variable
+	switchoverEnvs = append(switchoverEnvs, buildSwitchoverParameterEnvs(switchover.Parameters)...)
 	return switchoverEnvs, nil
 }
 
+// buildSwitchoverParameterEnvs builds the envs of the switchover parameters sorted by the names,
+// e.g. the parameter "sync-wait" is injected as the env KB_SWITCHOVER_PARAM_SYNC_WAIT.
+func buildSwitchoverParameterEnvs(parameters map[string]string) []corev1.EnvVar {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envs := make([]corev1.EnvVar, 0, len(names))
+	for _, name := range names {
+		envs = append(envs, corev1.EnvVar{
+			Name:  KBSwitchoverParamEnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_")),
+			Value: parameters[name],
+		})
+	}
+	return envs
+}
+
 // replaceSwitchoverConnCredentialEnv replaces the connection credential environment variables for the switchover job.
 func replaceSwitchoverConnCredentialEnv(switchoverSpec *appsv1alpha1.ComponentSwitchover, clusterName, componentName string) {
 	if switchoverSpec == nil {
@@ -400,6 +598,35 @@ func replaceSwitchoverConnCredentialEnv(switchoverSpec *appsv1alpha1.ComponentSw
 	}
 	replaceEnvVars(switchoverSpec.WithCandidate)
 	replaceEnvVars(switchoverSpec.WithoutCandidate)
+	replaceEnvVars(switchoverSpec.PreCheck)
+	replaceEnvVars(switchoverSpec.PostCheck)
+}
+
+// replaceSwitchoverBuiltinEnvVars resolves the built-in variables referred by the envs and envFroms of the switchover actions.
+func replaceSwitchoverBuiltinEnvVars(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	synthesizeComp *component.SynthesizedComponent) error {
+	podList, err := component.GetComponentPodList(ctx, cli, *cluster, synthesizeComp.Name)
+	if err != nil {
+		return err
+	}
+	podNames := make([]string, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		podNames = append(podNames, pod.Name)
+	}
+	namedValuesMap := component.GetReplacementMapForCmdExecutor(cluster.Name, synthesizeComp.Name, podNames)
+	replaceEnvVars := func(action *appsv1alpha1.Action) {
+		if action != nil {
+			action.Env, action.EnvFrom = component.ReplaceCmdExecutorEnvVars(namedValuesMap, action.Env, action.EnvFrom)
+		}
+	}
+	switchoverSpec := synthesizeComp.LifecycleActions.Switchover
+	replaceEnvVars(switchoverSpec.WithCandidate)
+	replaceEnvVars(switchoverSpec.WithoutCandidate)
+	replaceEnvVars(switchoverSpec.PreCheck)
+	replaceEnvVars(switchoverSpec.PostCheck)
+	return nil
 }
 
 // buildSwitchoverWorkloadEnvs builds the replication or consensus workload environment variables for the switchover job.
@@ -415,7 +642,7 @@ func buildSwitchoverWorkloadEnvs(ctx context.Context,
 	if pod == nil {
 		return nil, errors.New("serviceable and writable pod not found")
 	}
-	svcName := strings.Join([]string{cluster.Name, synthesizeComp.Name, "headless"}, "-")
+	svcName := component.HeadlessServiceName(synthesizeComp)
 
 	workloadEnvs = append(workloadEnvs, []corev1.EnvVar{
 		{
@@ -493,3 +720,50 @@ func getServiceableNWritablePod(ctx context.Context, cli client.Client, cluster
 	}
 	return &podList.Items[0], nil
 }
+
+// switchoverPhaseResult is the result of a phase executed by the switchover job.
+type switchoverPhaseResult struct {
+	Phase    string
+	ExitCode int32
+	Output   string
+}
+
+// getSwitchoverPhaseResults gets the results of the finished phases of the switchover job
+// from the container statuses of the latest pod of the job.
+func getSwitchoverPhaseResults(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	jobName string) ([]switchoverPhaseResult, error) {
+	podList := &corev1.PodList{}
+	if err := cli.List(ctx, podList, client.InNamespace(cluster.Namespace), client.MatchingLabels{"job-name": jobName}); err != nil {
+		return nil, err
+	}
+	var latestPod *corev1.Pod
+	for i := range podList.Items {
+		if latestPod == nil || latestPod.CreationTimestamp.Before(&podList.Items[i].CreationTimestamp) {
+			latestPod = &podList.Items[i]
+		}
+	}
+	if latestPod == nil {
+		return nil, nil
+	}
+	phases := map[string]string{
+		KBSwitchoverPreCheckContainerName:  KBSwitchoverPhasePreCheck,
+		KBSwitchoverJobContainerName:       KBSwitchoverPhaseSwitchover,
+		KBSwitchoverPostCheckContainerName: KBSwitchoverPhasePostCheck,
+	}
+	var results []switchoverPhaseResult
+	statuses := append(append([]corev1.ContainerStatus{}, latestPod.Status.InitContainerStatuses...), latestPod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		phase, ok := phases[status.Name]
+		if !ok || status.State.Terminated == nil {
+			continue
+		}
+		results = append(results, switchoverPhaseResult{
+			Phase:    phase,
+			ExitCode: status.State.Terminated.ExitCode,
+			Output:   strings.TrimSpace(status.State.Terminated.Message),
+		})
+	}
+	return results, nil
+}
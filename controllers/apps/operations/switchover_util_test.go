@@ -21,11 +21,16 @@ package operations
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -44,6 +49,19 @@ var _ = Describe("Switchover Util", func() {
 		clusterVersionName = "test-cluster-version-repl"
 	)
 
+	var (
+		switchoverJobResources = corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+		}
+	)
+
 	var (
 		clusterDefObj     *appsv1alpha1.ClusterDefinition
 		clusterVersionObj *appsv1alpha1.ClusterVersion
@@ -190,6 +208,47 @@ var _ = Describe("Switchover Util", func() {
 		Expect(synthesizedComp).ShouldNot(BeNil())
 		err = createSwitchoverJob(reqCtx, k8sClient, clusterObj, synthesizedComp, opsSwitchover)
 		Expect(err).Should(Succeed())
+
+		By("Test the switchover job carries the resources of cmdExecutorConfig")
+		job, err := renderSwitchoverCmdJob(testCtx.Ctx, k8sClient, clusterObj, synthesizedComp, opsSwitchover)
+		Expect(err).Should(Succeed())
+		Expect(job.Spec.Template.Spec.Containers[0].Resources).Should(Equal(switchoverJobResources))
+		Expect(job.Spec.Template.Spec.InitContainers).Should(BeEmpty())
+
+		By("Test the built-in variables in the envs of cmdExecutorConfig are resolved")
+		podList, err := component.GetComponentPodList(testCtx.Ctx, k8sClient, *clusterObj, synthesizedComp.Name)
+		Expect(err).Should(Succeed())
+		podNames := make([]string, 0, len(podList.Items))
+		for _, pod := range podList.Items {
+			podNames = append(podNames, pod.Name)
+		}
+		slices.Sort(podNames)
+		Expect(job.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{
+			Name:  "ENDPOINT_" + synthesizedComp.Name,
+			Value: clusterObj.Name + ":" + strings.Join(podNames, ","),
+		}))
+		Expect(job.Spec.Template.Spec.Containers[0].EnvFrom).Should(Equal([]corev1.EnvFromSource{{
+			Prefix:    "EXT_",
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: clusterObj.Name + "-credential"}},
+		}}))
+
+		By("Test the switchover job executes the preCheck and postCheck around the switchover")
+		synthesizedComp.LifecycleActions.Switchover.PreCheck = &appsv1alpha1.Action{
+			Exec: &appsv1alpha1.ExecAction{Command: []string{"echo", "precheck"}},
+		}
+		synthesizedComp.LifecycleActions.Switchover.PostCheck = &appsv1alpha1.Action{
+			Exec: &appsv1alpha1.ExecAction{Command: []string{"echo", "postcheck"}},
+		}
+		job, err = renderSwitchoverCmdJob(testCtx.Ctx, k8sClient, clusterObj, synthesizedComp, opsSwitchover)
+		Expect(err).Should(Succeed())
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.InitContainers).Should(HaveLen(2))
+		Expect(podSpec.InitContainers[0].Name).Should(Equal(KBSwitchoverPreCheckContainerName))
+		Expect(podSpec.InitContainers[0].Image).Should(Equal(testapps.DefaultRedisImageName))
+		Expect(podSpec.InitContainers[1].Name).Should(Equal(KBSwitchoverJobContainerName))
+		Expect(podSpec.Containers).Should(HaveLen(1))
+		Expect(podSpec.Containers[0].Name).Should(Equal(KBSwitchoverPostCheckContainerName))
+		Expect(*job.Spec.BackoffLimit).Should(BeEquivalentTo(0))
 	}
 
 	// Scenarios
@@ -197,7 +256,16 @@ var _ = Describe("Switchover Util", func() {
 		BeforeEach(func() {
 			By("Create a clusterDefinition obj with replication workloadType.")
 			commandExecutorEnvItem := &appsv1alpha1.CommandExecutorEnvItem{
-				Image: testapps.DefaultRedisImageName,
+				Image:     testapps.DefaultRedisImageName,
+				Resources: switchoverJobResources,
+				Env: []corev1.EnvVar{{
+					Name:  "ENDPOINT_$(KB_COMP_NAME)",
+					Value: "$(KB_CLUSTER_NAME):$(KB_POD_LIST)",
+				}},
+				EnvFrom: []corev1.EnvFromSource{{
+					Prefix:    "EXT_",
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "$(KB_CLUSTER_NAME)-credential"}},
+				}},
 			}
 			commandExecutorItem := &appsv1alpha1.CommandExecutorItem{
 				Command: []string{"echo", "hello"},
@@ -246,5 +314,97 @@ var _ = Describe("Switchover Util", func() {
 		It("Test doSwitchover when opsRequest triggers", func() {
 			testDoSwitchover()
 		})
+
+		It("Test the switchover job fails on the preCheck and postCheck", func() {
+			clusterObj = testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName,
+				clusterDefObj.Name, clusterVersionObj.Name).WithRandomName().
+				AddComponent(testapps.DefaultRedisCompSpecName, testapps.DefaultRedisCompDefName).
+				SetReplicas(2).
+				Create(&testCtx).GetObject()
+			recorder := record.NewFakeRecorder(10)
+			reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx, Recorder: recorder}
+			// the progress details emit events too, so the check failures are recorded separately.
+			checkRecorder := record.NewFakeRecorder(1)
+
+			createJobPod := func(jobName string, initStatuses, statuses []corev1.ContainerStatus) {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      jobName + "-pod",
+						Namespace: testCtx.DefaultNamespace,
+						Labels:    map[string]string{"job-name": jobName},
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: KBSwitchoverPostCheckContainerName, Image: testapps.DefaultRedisImageName}},
+					},
+				}
+				Expect(testCtx.CreateObj(testCtx.Ctx, pod)).Should(Succeed())
+				Expect(testapps.ChangeObjStatus(&testCtx, pod, func() {
+					pod.Status.InitContainerStatuses = initStatuses
+					pod.Status.ContainerStatuses = statuses
+				})).Should(Succeed())
+			}
+			terminated := func(name string, exitCode int32, message string) corev1.ContainerStatus {
+				return corev1.ContainerStatus{
+					Name: name,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message},
+					},
+				}
+			}
+			newOpsRes := func() *OpsResource {
+				opsRequest := &appsv1alpha1.OpsRequest{
+					ObjectMeta: metav1.ObjectMeta{Name: "switchover-ops", Namespace: testCtx.DefaultNamespace},
+				}
+				opsRequest.Status.Components = map[string]appsv1alpha1.OpsRequestComponentStatus{
+					testapps.DefaultRedisCompSpecName: {},
+				}
+				return &OpsResource{Cluster: clusterObj, OpsRequest: opsRequest, Recorder: recorder}
+			}
+
+			By("Test a failed preCheck aborts the switchover")
+			preCheckJob := genSwitchoverJobName(clusterObj.Name, testapps.DefaultRedisCompSpecName, 1)
+			createJobPod(preCheckJob, []corev1.ContainerStatus{
+				terminated(KBSwitchoverPreCheckContainerName, 1, "replication lag too large"),
+			}, nil)
+			opsRes := newOpsRes()
+			failedPhase, err := recordSwitchoverPhaseResults(reqCtx, k8sClient, opsRes, testapps.DefaultRedisCompSpecName, preCheckJob)
+			Expect(err).Should(Succeed())
+			Expect(failedPhase).ShouldNot(BeNil())
+			Expect(failedPhase.Phase).Should(Equal(KBSwitchoverPhasePreCheck))
+			progressDetails := opsRes.OpsRequest.Status.Components[testapps.DefaultRedisCompSpecName].ProgressDetails
+			Expect(progressDetails).Should(HaveLen(1))
+			Expect(progressDetails[0].Status).Should(Equal(appsv1alpha1.FailedProgressStatus))
+			Expect(progressDetails[0].Message).Should(ContainSubstring("replication lag too large"))
+			err = handleSwitchoverCheckFailed(checkRecorder, opsRes.OpsRequest, testapps.DefaultRedisCompSpecName, preCheckJob, failedPhase)
+			Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+			Expect(checkRecorder.Events).Should(Receive(ContainSubstring(ReasonSwitchoverPreCheckFailed)))
+
+			By("Test a failed postCheck requires a rollback of the switchover")
+			postCheckJob := genSwitchoverJobName(clusterObj.Name, testapps.DefaultRedisCompSpecName, 2)
+			createJobPod(postCheckJob, []corev1.ContainerStatus{
+				terminated(KBSwitchoverPreCheckContainerName, 0, ""),
+				terminated(KBSwitchoverJobContainerName, 0, ""),
+			}, []corev1.ContainerStatus{
+				terminated(KBSwitchoverPostCheckContainerName, 2, "new primary is not writable"),
+			})
+			opsRes = newOpsRes()
+			failedPhase, err = recordSwitchoverPhaseResults(reqCtx, k8sClient, opsRes, testapps.DefaultRedisCompSpecName, postCheckJob)
+			Expect(err).Should(Succeed())
+			Expect(failedPhase).ShouldNot(BeNil())
+			Expect(failedPhase.Phase).Should(Equal(KBSwitchoverPhasePostCheck))
+			Expect(failedPhase.ExitCode).Should(BeEquivalentTo(2))
+			Expect(opsRes.OpsRequest.Status.Components[testapps.DefaultRedisCompSpecName].ProgressDetails).Should(HaveLen(3))
+			err = handleSwitchoverCheckFailed(checkRecorder, opsRes.OpsRequest, testapps.DefaultRedisCompSpecName, postCheckJob, failedPhase)
+			Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue())
+			Expect(checkRecorder.Events).Should(Receive(And(ContainSubstring(ReasonSwitchoverRollback), ContainSubstring("new primary is not writable"))))
+		})
+
+		It("Test building the envs of the switchover parameters", func() {
+			envs := buildSwitchoverParameterEnvs(map[string]string{"sync-wait": "10", "force": "true"})
+			Expect(envs).Should(Equal([]corev1.EnvVar{
+				{Name: KBSwitchoverParamEnvPrefix + "FORCE", Value: "true"},
+				{Name: KBSwitchoverParamEnvPrefix + "SYNC_WAIT", Value: "10"},
+			}))
+		})
 	})
 })
@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	opsutil "github.com/apecloud/kubeblocks/controllers/apps/operations/util"
@@ -77,6 +78,10 @@ const (
 	SysAcctDelete      = "SysAcctDelete"
 	SysAcctCreate      = "SysAcctCreate"
 	SysAcctUnsupported = "SysAcctUnsupported"
+	SysAcctRotate      = "SysAcctRotate"
+
+	// SysAcctPasswordGenerationFailed is the event reason if the password of an account can not be generated.
+	SysAcctPasswordGenerationFailed = "PasswordGenerationFailed"
 )
 
 // Environment names for cmd config connections
@@ -181,6 +186,13 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	componentVersions := clusterVersion.Spec.GetDefNameMappingComponents()
 
+	// rotate the connection credential on demand
+	if isConnCredentialRotationRequested(cluster) && !existsOperations(cluster) {
+		if err := r.rotateConnCredential(reqCtx, cluster, clusterdefinition, componentVersions); err != nil {
+			return intctrlutil.RequeueWithErrorAndRecordEvent(cluster, r.Recorder, err, reqCtx.Log)
+		}
+	}
+
 	// process accounts for each component
 	processAccountsForComponent := func(compDef *appsv1alpha1.ClusterComponentDefinition, compDecl *appsv1alpha1.ClusterComponentSpec,
 		svcEP *corev1.Endpoints, headlessEP *corev1.Endpoints) error {
@@ -243,7 +255,11 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 					execConfig := compDef.SystemAccounts.CmdExecutorConfig
 					// complete execConfig with settings from component version
 					completeExecConfig(execConfig, componentVersions[compDef.Name])
-					engine = newCustomizedEngine(execConfig, cluster, compDecl.Name)
+					podNames, err := getComponentPodNames(reqCtx.Ctx, r.Client, cluster, compDecl.Name)
+					if err != nil {
+						return err
+					}
+					engine = newCustomizedEngine(execConfig, cluster, compDecl.Name, podNames)
 				}
 				reqCtx.Log.V(1).Info("create account by stmt", "cluster", req.NamespacedName, "account", account.Name, "strategy", strategy)
 				if err := r.createByStmt(reqCtx, cluster, compDef, compKey, engine, account, svcEP, headlessEP, strategy); err != nil {
@@ -261,29 +277,27 @@ func (r *SystemAccountReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	reconcileCounter := 0
 	existsOps := existsOperations(cluster)
 	// for each component in the cluster
-	for _, compDecl := range cluster.Spec.ComponentSpecs {
-		compName := compDecl.Name
-		compType := compDecl.ComponentDefRef
-		for _, compDef := range clusterdefinition.Spec.ComponentDefs {
-			if compType != compDef.Name || compDef.SystemAccounts == nil {
-				continue
-			}
+	for i := range cluster.Spec.ComponentSpecs {
+		compDecl := &cluster.Spec.ComponentSpecs[i]
+		compDef := clusterdefinition.GetComponentDefByName(compDecl.ComponentDefRef)
+		if compDef == nil || compDef.SystemAccounts == nil {
+			continue
+		}
 
-			isReady, svcEP, headlessEP, err := r.isComponentReady(reqCtx, cluster.Name, compName)
-			if err != nil {
-				return intctrlutil.RequeueAfter(requeueDuration, reqCtx.Log, "failed to get service")
-			}
+		isReady, svcEP, headlessEP, err := r.isComponentReady(reqCtx, cluster.Name, compDecl.Name, compDef)
+		if err != nil {
+			return intctrlutil.RequeueAfter(requeueDuration, reqCtx.Log, "failed to get service")
+		}
 
-			// either service or endpoint is not ready, increase counter and continue to process next component
-			if !isReady || existsOps {
-				reconcileCounter++
-				continue
-			}
+		// either service or endpoint is not ready, increase counter and continue to process next component
+		if !isReady || existsOps {
+			reconcileCounter++
+			continue
+		}
 
-			if err := processAccountsForComponent(&compDef, &compDecl, svcEP, headlessEP); err != nil {
-				reconcileCounter++
-				continue
-			}
+		if err := processAccountsForComponent(compDef, compDecl, svcEP, headlessEP); err != nil {
+			reconcileCounter++
+			continue
 		}
 	}
 
@@ -322,10 +336,18 @@ func (r *SystemAccountReconciler) createByStmt(reqCtx intctrlutil.RequestCtx,
 		}
 	}
 
-	stmts, passwd := getCreationStmtForAccount(compKey, compDef.SystemAccounts.PasswordConfig, account, strategy)
+	stmts, passwd, err := getCreationStmtForAccount(compKey, compDef.SystemAccounts.GetPasswordConfig(account), account, strategy)
+	if err != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, SysAcctPasswordGenerationFailed,
+			"failed to generate the password of account %s for component %s: %s", account.Name, compKey.componentName, err.Error())
+		return err
+	}
 
 	for _, ep := range retrieveEndpoints(policy.Scope, svcEP, headlessEP) {
-		job := renderJob(generateJobName(), engine, compKey, stmts, ep)
+		job, err := renderJob(generateJobName(), engine, compKey, stmts, ep)
+		if err != nil {
+			return err
+		}
 		controllerutil.AddFinalizer(job, constant.DBClusterFinalizerName)
 		if job.Annotations == nil {
 			job.Annotations = map[string]string{}
@@ -370,12 +392,14 @@ func (r *SystemAccountReconciler) createByReferringToExisting(reqCtx intctrlutil
 	return nil
 }
 
-func (r *SystemAccountReconciler) isComponentReady(reqCtx intctrlutil.RequestCtx, clusterName string, compName string) (bool, *corev1.Endpoints, *corev1.Endpoints, error) {
+func (r *SystemAccountReconciler) isComponentReady(reqCtx intctrlutil.RequestCtx, clusterName string, compName string,
+	compDef *appsv1alpha1.ClusterComponentDefinition) (bool, *corev1.Endpoints, *corev1.Endpoints, error) {
 	svcEP := &corev1.Endpoints{}
 	serviceName := clusterName + "-" + compName
 
 	headlessEP := &corev1.Endpoints{}
-	headlessSvcName := serviceName + "-headless"
+	headlessSvcName := constant.GenerateComponentHeadlessServiceNameWithSuffix(clusterName, compName,
+		compDef.Service.GetHeadlessNameSuffix())
 
 	svcErr := r.Client.Get(reqCtx.Ctx, types.NamespacedName{Namespace: reqCtx.Req.Namespace, Name: serviceName}, svcEP)
 	if svcErr != nil {
@@ -535,6 +559,14 @@ func (r *SystemAccountReconciler) jobCompletionHandler() *handler.Funcs {
 				return
 			}
 
+			if _, ok := job.Annotations[connCredentialRotatedAtAnnotation]; ok {
+				r.handleConnCredentialRotationJob(ctx, logger, cluster, job,
+					containsJobCondition(*job, job.Status.Conditions, batchv1.JobFailed, corev1.ConditionTrue))
+				// reconcile the cluster to complete the rotation once all the jobs are finished.
+				q.Add(reconcile.Request{NamespacedName: clusterKey})
+				return
+			}
+
 			if containsJobCondition(*job, job.Status.Conditions, batchv1.JobFailed, corev1.ConditionTrue) {
 				logger.V(1).Info("job failed", "job", job.Name)
 				r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctCreate,
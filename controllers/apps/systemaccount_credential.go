@@ -0,0 +1,399 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	componetutil "github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/factory"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// connCredentialRotatedAtAnnotation and connCredentialRotatedDataAnnotation are annotated on the jobs updating the
+// passwords of accounts referring to connection credential, to keep the rotation time and the rotated entries.
+const (
+	connCredentialRotatedAtAnnotation   = "conn-credential-rotated-at"
+	connCredentialRotatedDataAnnotation = "conn-credential-rotated-data"
+)
+
+// isConnCredentialRotationRequested checks whether the rotation of connection credential is requested by annotation.
+func isConnCredentialRotationRequested(cluster *appsv1alpha1.Cluster) bool {
+	rotate, _ := strconv.ParseBool(cluster.Annotations[constant.RotateConnCredentialAnnotationKey])
+	return rotate
+}
+
+// rotateConnCredential regenerates the random entries of connection credential.
+// If there are accounts referring to the connection credential, the new passwords will be applied to the database
+// by their update statements first, the secrets will be updated once the jobs succeed, and the rotation is completed
+// once all the jobs are finished. Otherwise, the connection credential will be updated directly.
+func (r *SystemAccountReconciler) rotateConnCredential(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
+	clusterDef *appsv1alpha1.ClusterDefinition, componentVersions map[string]*appsv1alpha1.ClusterComponentVersion) error {
+	// wait for the in-flight rotation, to keep the rotation idempotent.
+	rotationJobs, err := r.listConnCredentialRotationJobs(reqCtx.Ctx, cluster)
+	if err != nil {
+		return err
+	}
+	if len(rotationJobs) > 0 {
+		if !isConnCredentialRotationFinished(rotationJobs) {
+			return nil
+		}
+		return r.completeConnCredentialRotation(reqCtx, cluster, rotationJobs)
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: cluster.Namespace, Name: constant.GenerateDefaultConnCredential(cluster.Name)}
+	if err := r.Client.Get(reqCtx.Ctx, secretKey, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		reqCtx.Log.Info("connection credential not found, nothing to rotate", "secret", secretKey)
+		return r.clearConnCredentialRotation(reqCtx.Ctx, cluster)
+	}
+
+	var rotated map[string]string
+	compSpecs := make([]*appsv1alpha1.ClusterComponentSpec, 0)
+	for i := range cluster.Spec.ComponentSpecs {
+		compSpecs = append(compSpecs, &cluster.Spec.ComponentSpecs[i])
+	}
+	synthesizedComp := buildConnCredentialSynthesizedComponent(clusterDef, compSpecs)
+	if synthesizedComp != nil {
+		if rotated, err = factory.RotateConnCredential(clusterDef, cluster, synthesizedComp, secret); err != nil {
+			return err
+		}
+	}
+	if rotated == nil {
+		reqCtx.Log.Info("no random entries in connection credential, nothing to rotate", "secret", secretKey)
+		return r.clearConnCredentialRotation(reqCtx.Ctx, cluster)
+	}
+	rotatedAt := time.Now()
+
+	jobs, err := r.buildConnCredentialRotationJobs(reqCtx, cluster, clusterDef, componentVersions, secret, rotatedAt, rotated)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		if err = r.applyRotatedConnCredential(reqCtx.Ctx, secret, rotated); err != nil {
+			return err
+		}
+		if err = r.clearConnCredentialRotation(reqCtx.Ctx, cluster); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctRotate,
+			"Rotated connection credential for cluster: %s at %s", cluster.Name, rotatedAt.UTC().Format(time.RFC3339))
+		return nil
+	}
+	for _, job := range jobs {
+		if err = r.Client.Create(reqCtx.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		reqCtx.Log.V(1).Info("created job to rotate connection credential", "job", job.Name)
+	}
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctRotate,
+		"Rotating connection credential for cluster: %s at %s, the secret will be updated once accounts are updated",
+		cluster.Name, rotatedAt.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// listConnCredentialRotationJobs lists the jobs of the latest rotation of connection credential. The jobs are kept
+// until the rotation is completed, see calibrateConnCredentialRotationJob.
+func (r *SystemAccountReconciler) listConnCredentialRotationJobs(ctx context.Context,
+	cluster *appsv1alpha1.Cluster) ([]batchv1.Job, error) {
+	jobs := &batchv1.JobList{}
+	if err := r.Client.List(ctx, jobs, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: cluster.Name},
+		client.HasLabels{constant.ClusterAccountLabelKey}); err != nil {
+		return nil, err
+	}
+	var (
+		latest       string
+		rotationJobs []batchv1.Job
+	)
+	for _, job := range jobs.Items {
+		// the rotation time is formatted in RFC3339 and UTC, which can be compared as strings.
+		rotatedAt, ok := job.Annotations[connCredentialRotatedAtAnnotation]
+		switch {
+		case !ok || rotatedAt < latest || job.DeletionTimestamp != nil:
+		case rotatedAt > latest:
+			latest = rotatedAt
+			rotationJobs = []batchv1.Job{job}
+		default:
+			rotationJobs = append(rotationJobs, job)
+		}
+	}
+	return rotationJobs, nil
+}
+
+// isConnCredentialRotationFinished checks whether all the jobs of the rotation are finished and handled by the
+// job completion handler, which removes the finalizer of the jobs after updating the secrets.
+func isConnCredentialRotationFinished(jobs []batchv1.Job) bool {
+	for i := range jobs {
+		if finished, _ := isSystemAccountJobFinished(&jobs[i]); !finished {
+			return false
+		}
+		if controllerutil.ContainsFinalizer(&jobs[i], constant.DBClusterFinalizerName) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSystemAccountJobFinished checks whether the job is finished, and whether it is failed.
+func isSystemAccountJobFinished(job *batchv1.Job) (bool, bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, false
+		case batchv1.JobFailed:
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// getConnCredentialAccounts returns the accounts which refer to the connection credential and whose passwords
+// are rotated, they should be updated by the update statement.
+func getConnCredentialAccounts(sysAccounts *appsv1alpha1.SystemAccountSpec, secret *corev1.Secret,
+	rotated map[string]string) []appsv1alpha1.SystemAccountConfig {
+	if _, ok := rotated[constant.AccountPasswdForSecret]; !ok {
+		return nil
+	}
+	accounts := make([]appsv1alpha1.SystemAccountConfig, 0)
+	for _, account := range sysAccounts.Accounts {
+		policy := account.ProvisionPolicy
+		if policy.Type != appsv1alpha1.ReferToExisting || policy.SecretRef == nil {
+			continue
+		}
+		if policy.SecretRef.Name != secret.Name || policy.SecretRef.Namespace != secret.Namespace {
+			continue
+		}
+		if policy.Statements == nil || len(policy.Statements.UpdateStatement) == 0 {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+// buildConnCredentialRotationJobs builds the jobs to update the passwords of accounts referring to connection credential.
+func (r *SystemAccountReconciler) buildConnCredentialRotationJobs(reqCtx intctrlutil.RequestCtx,
+	cluster *appsv1alpha1.Cluster, clusterDef *appsv1alpha1.ClusterDefinition,
+	componentVersions map[string]*appsv1alpha1.ClusterComponentVersion,
+	secret *corev1.Secret, rotatedAt time.Time, rotated map[string]string) ([]*batchv1.Job, error) {
+	rotatedData, err := json.Marshal(rotated)
+	if err != nil {
+		return nil, err
+	}
+	userName := string(secret.Data[constant.AccountNameForSecret])
+	if name, ok := rotated[constant.AccountNameForSecret]; ok {
+		userName = name
+	}
+	passwd := rotated[constant.AccountPasswdForSecret]
+
+	generateJobName := func(compName string, account appsv1alpha1.AccountName, idx int) string {
+		// the job name is determined by the rotation, to avoid updating the account repeatedly.
+		suffix := fmt.Sprintf("rotate-%d-%d", rotatedAt.Unix(), idx)
+		fullJobName := strings.Join([]string{systemAccountjobPrefix, cluster.Name, compName, string(account), suffix}, "-")
+		if len(fullJobName) > 63 {
+			return systemAccountjobPrefix + "-" + string(account) + "-" + suffix
+		}
+		return fullJobName
+	}
+
+	jobs := make([]*batchv1.Job, 0)
+	for _, compDecl := range cluster.Spec.ComponentSpecs {
+		compDef := clusterDef.GetComponentDefByName(compDecl.ComponentDefRef)
+		if compDef == nil || compDef.SystemAccounts == nil || compDef.SystemAccounts.CmdExecutorConfig == nil {
+			continue
+		}
+		replaceEnvsValues(cluster.Name, compDef.SystemAccounts, nil)
+		accounts := getConnCredentialAccounts(compDef.SystemAccounts, secret, rotated)
+		if len(accounts) == 0 {
+			continue
+		}
+
+		isReady, svcEP, headlessEP, err := r.isComponentReady(reqCtx, cluster.Name, compDecl.Name, compDef)
+		if err != nil {
+			return nil, err
+		}
+		if !isReady {
+			return nil, fmt.Errorf("component %s is not ready to rotate connection credential", compDecl.Name)
+		}
+
+		execConfig := compDef.SystemAccounts.CmdExecutorConfig
+		completeExecConfig(execConfig, componentVersions[compDef.Name])
+		podNames, err := getComponentPodNames(reqCtx.Ctx, r.Client, cluster, compDecl.Name)
+		if err != nil {
+			return nil, err
+		}
+		engine := newCustomizedEngine(execConfig, cluster, compDecl.Name, podNames)
+		compKey := componentUniqueKey{
+			namespace:     cluster.Namespace,
+			clusterName:   cluster.Name,
+			componentName: compDecl.Name,
+			characterType: compDef.CharacterType,
+		}
+		for _, account := range accounts {
+			accountUserName := userName
+			if len(accountUserName) == 0 {
+				accountUserName = string(account.Name)
+			}
+			namedVars := getEnvReplacementMapForAccount(accountUserName, passwd)
+			stmt := componetutil.ReplaceNamedVars(namedVars, account.ProvisionPolicy.Statements.UpdateStatement, -1, true)
+			for idx, ep := range retrieveEndpoints(account.ProvisionPolicy.Scope, svcEP, headlessEP) {
+				job, err := renderJob(generateJobName(compDecl.Name, account.Name, idx), engine, compKey, []string{stmt}, ep)
+				if err != nil {
+					return nil, err
+				}
+				controllerutil.AddFinalizer(job, constant.DBClusterFinalizerName)
+				job.Annotations = map[string]string{
+					connCredentialRotatedAtAnnotation:   rotatedAt.UTC().Format(time.RFC3339),
+					connCredentialRotatedDataAnnotation: string(rotatedData),
+				}
+				if err := calibrateJobMetaAndSpec(job, cluster, compKey, account.Name); err != nil {
+					return nil, err
+				}
+				// the finished jobs are deleted once the rotation is completed.
+				job.Spec.TTLSecondsAfterFinished = nil
+				if err := controllerutil.SetControllerReference(cluster, job, r.Scheme); err != nil {
+					return nil, err
+				}
+				jobs = append(jobs, job)
+			}
+		}
+	}
+	return jobs, nil
+}
+
+// applyRotatedConnCredential updates the secret with the rotated entries.
+func (r *SystemAccountReconciler) applyRotatedConnCredential(ctx context.Context, secret *corev1.Secret, rotated map[string]string) error {
+	patch := client.MergeFrom(secret.DeepCopy())
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for k, v := range rotated {
+		secret.Data[k] = []byte(v)
+	}
+	return r.Client.Patch(ctx, secret, patch)
+}
+
+// completeConnCredentialRotation completes the rotation whose jobs are all finished, it deletes the jobs, clears
+// the rotation annotation of the cluster and records the result of the rotation.
+func (r *SystemAccountReconciler) completeConnCredentialRotation(reqCtx intctrlutil.RequestCtx, cluster *appsv1alpha1.Cluster,
+	jobs []batchv1.Job) error {
+	failed := false
+	for i := range jobs {
+		if _, jobFailed := isSystemAccountJobFinished(&jobs[i]); jobFailed {
+			failed = true
+		}
+		if err := intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, &jobs[i]); err != nil {
+			return err
+		}
+	}
+	if err := r.clearConnCredentialRotation(reqCtx.Ctx, cluster); err != nil {
+		return err
+	}
+	rotatedAt := jobs[0].Annotations[connCredentialRotatedAtAnnotation]
+	if failed {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, SysAcctRotate,
+			"Failed to rotate connection credential for cluster: %s at %s, some accounts are not updated",
+			cluster.Name, rotatedAt)
+		return nil
+	}
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctRotate,
+		"Rotated connection credential for cluster: %s at %s", cluster.Name, rotatedAt)
+	return nil
+}
+
+// clearConnCredentialRotation clears the rotation annotation of the cluster.
+func (r *SystemAccountReconciler) clearConnCredentialRotation(ctx context.Context, cluster *appsv1alpha1.Cluster) error {
+	patch := client.MergeFrom(cluster.DeepCopy())
+	delete(cluster.Annotations, constant.RotateConnCredentialAnnotationKey)
+	return r.Client.Patch(ctx, cluster, patch)
+}
+
+// handleConnCredentialRotationJob updates the connection credential with the rotated entries and the account secret
+// with the rotated password once the job to update the account succeeds.
+func (r *SystemAccountReconciler) handleConnCredentialRotationJob(ctx context.Context, logger logr.Logger,
+	cluster *appsv1alpha1.Cluster, job *batchv1.Job, failed bool) {
+	accountName := job.Labels[constant.ClusterAccountLabelKey]
+	componentName := job.Labels[constant.KBAppComponentLabelKey]
+	rotatedAt := job.Annotations[connCredentialRotatedAtAnnotation]
+	if failed {
+		logger.V(1).Info("job to rotate connection credential failed", "job", job.Name)
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, SysAcctRotate,
+			"Failed to rotate connection credential for cluster: %s, component: %s, accounts: %s, rotated at: %s",
+			cluster.Name, componentName, accountName, rotatedAt)
+		return
+	}
+
+	rotated := map[string]string{}
+	if err := json.Unmarshal([]byte(job.Annotations[connCredentialRotatedDataAnnotation]), &rotated); err != nil {
+		logger.Error(err, "failed to parse rotated connection credential", "job", job.Name)
+		return
+	}
+	// the account secret only keeps the name and the password of the account.
+	accountRotated := map[string]string{}
+	if passwd, ok := rotated[constant.AccountPasswdForSecret]; ok {
+		accountRotated[constant.AccountPasswdForSecret] = passwd
+	}
+	secrets := []struct {
+		name    string
+		rotated map[string]string
+	}{
+		{constant.GenerateDefaultConnCredential(cluster.Name), rotated},
+		{constant.GenerateAccountSecretName(cluster.Name, componentName, accountName), accountRotated},
+	}
+	for _, s := range secrets {
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: s.name}, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "failed to get secret", "secret", s.name)
+			}
+			continue
+		}
+		if err := r.applyRotatedConnCredential(ctx, secret, s.rotated); err != nil {
+			logger.Error(err, "failed to update secret", "secret", s.name)
+			return
+		}
+	}
+
+	r.Recorder.Eventf(cluster, corev1.EventTypeNormal, SysAcctRotate,
+		"Rotated connection credential for cluster: %s, component: %s, accounts: %s, rotated at: %s",
+		cluster.Name, componentName, accountName, rotatedAt)
+}
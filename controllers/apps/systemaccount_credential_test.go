@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestConnCredentialRotation(t *testing.T) {
+	const (
+		namespace   = "default"
+		clusterName = "mycluster"
+		compName    = "mysql"
+		accountName = "kbadmin"
+		rotatedAt   = "2024-01-01T00:00:00Z"
+	)
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, batchv1.AddToScheme(scheme))
+	assert.NoError(t, appsv1alpha1.AddToScheme(scheme))
+
+	cluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        clusterName,
+			Annotations: map[string]string{constant.RotateConnCredentialAnnotationKey: "true"},
+		},
+	}
+	connCredential := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: constant.GenerateDefaultConnCredential(clusterName)},
+		Data: map[string][]byte{
+			constant.AccountNameForSecret:   []byte("root"),
+			constant.AccountPasswdForSecret: []byte("old"),
+			"endpoint":                      []byte("old-endpoint"),
+		},
+	}
+	accountSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: constant.GenerateAccountSecretName(clusterName, compName, accountName)},
+		Data: map[string][]byte{
+			constant.AccountNameForSecret:   []byte(accountName),
+			constant.AccountPasswdForSecret: []byte("old"),
+		},
+	}
+	newJob := func(name string) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName,
+					constant.KBAppComponentLabelKey: compName,
+					constant.ClusterAccountLabelKey: accountName,
+				},
+				Annotations: map[string]string{
+					connCredentialRotatedAtAnnotation:   rotatedAt,
+					connCredentialRotatedDataAnnotation: `{"password":"new","endpoint":"new-endpoint"}`,
+				},
+				Finalizers: []string{constant.DBClusterFinalizerName},
+			},
+		}
+	}
+	job0, job1 := newJob("job-0"), newJob("job-1")
+
+	ctx := context.Background()
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, connCredential, accountSecret, job0, job1).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &SystemAccountReconciler{Client: cli, Scheme: scheme, Recorder: recorder}
+	reqCtx := intctrlutil.RequestCtx{Ctx: ctx, Log: logf.Log, Recorder: recorder}
+
+	finishJob := func(job *batchv1.Job, condType batchv1.JobConditionType) {
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(job), job))
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condType, Status: corev1.ConditionTrue}}
+		job.Finalizers = nil
+		assert.NoError(t, cli.Update(ctx, job))
+	}
+	rotate := func() {
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(cluster), cluster))
+		assert.NoError(t, r.rotateConnCredential(reqCtx, cluster, nil, nil))
+		assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(cluster), cluster))
+	}
+
+	// the rotation is kept in progress while the jobs are running
+	rotate()
+	assert.True(t, isConnCredentialRotationRequested(cluster))
+
+	// the account secret only takes the rotated password once the job succeeds
+	r.handleConnCredentialRotationJob(ctx, logf.Log, cluster, job0, false)
+	finishJob(job0, batchv1.JobComplete)
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(connCredential), connCredential))
+	assert.Equal(t, "new", string(connCredential.Data[constant.AccountPasswdForSecret]))
+	assert.Equal(t, "new-endpoint", string(connCredential.Data["endpoint"]))
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(accountSecret), accountSecret))
+	assert.Equal(t, map[string][]byte{
+		constant.AccountNameForSecret:   []byte(accountName),
+		constant.AccountPasswdForSecret: []byte("new"),
+	}, accountSecret.Data)
+
+	// the rotation is not completed until all the jobs are finished
+	rotate()
+	assert.True(t, isConnCredentialRotationRequested(cluster))
+
+	// the rotation is completed once all the jobs are finished, and the jobs are cleaned up
+	r.handleConnCredentialRotationJob(ctx, logf.Log, cluster, job1, true)
+	finishJob(job1, batchv1.JobFailed)
+	rotate()
+	assert.False(t, isConnCredentialRotationRequested(cluster))
+	for _, job := range []*batchv1.Job{job0, job1} {
+		assert.True(t, apierrors.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(job), &batchv1.Job{})))
+	}
+	assert.Len(t, recorder.Events, 3)
+}
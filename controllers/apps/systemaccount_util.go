@@ -20,10 +20,10 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
-	"github.com/sethvargo/go-password/password"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,6 +45,9 @@ type customizedEngine struct {
 	command       []string
 	args          []string
 	envVarList    []corev1.EnvVar
+	envFromList   []corev1.EnvFromSource
+	resources     corev1.ResourceRequirements
+	podNames      []string
 }
 
 func (e *customizedEngine) getImage() string {
@@ -55,6 +58,14 @@ func (e *customizedEngine) getEnvs() []corev1.EnvVar {
 	return e.envVarList
 }
 
+func (e *customizedEngine) getEnvFroms() []corev1.EnvFromSource {
+	return e.envFromList
+}
+
+func (e *customizedEngine) getResources() corev1.ResourceRequirements {
+	return e.resources
+}
+
 // getPodCommand shows how to execute the sql statement.
 // for instance, mysql -h - demo-cluster-replicasets-1 -e  "create user username IDENTIFIED by 'passwd';"
 func (e customizedEngine) getCommand() []string {
@@ -67,7 +78,9 @@ func (e *customizedEngine) getArgs() []string {
 	return e.args
 }
 
-func newCustomizedEngine(execConfig *appsv1alpha1.CmdExecutorConfig, dbcluster *appsv1alpha1.Cluster, compName string) *customizedEngine {
+// newCustomizedEngine creates an engine to render the jobs of system accounts, the podNames are the pods
+// of the component, which are referred by the built-in variable $(KB_POD_LIST) in the envs.
+func newCustomizedEngine(execConfig *appsv1alpha1.CmdExecutorConfig, dbcluster *appsv1alpha1.Cluster, compName string, podNames []string) *customizedEngine {
 	return &customizedEngine{
 		cluster:       dbcluster,
 		componentName: compName,
@@ -75,7 +88,23 @@ func newCustomizedEngine(execConfig *appsv1alpha1.CmdExecutorConfig, dbcluster *
 		command:       execConfig.Command,
 		args:          execConfig.Args,
 		envVarList:    execConfig.Env,
+		envFromList:   execConfig.EnvFrom,
+		resources:     execConfig.Resources,
+		podNames:      podNames,
+	}
+}
+
+// getComponentPodNames gets the names of the pods of the component.
+func getComponentPodNames(ctx context.Context, cli client.Reader, cluster *appsv1alpha1.Cluster, compName string) ([]string, error) {
+	podList, err := componetutil.GetComponentPodList(ctx, cli, *cluster, compName)
+	if err != nil {
+		return nil, err
+	}
+	podNames := make([]string, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		podNames = append(podNames, pod.Name)
 	}
+	return podNames, nil
 }
 
 func replaceEnvsValues(clusterName string, sysAccounts *appsv1alpha1.SystemAccountSpec, placeholders map[string]string) {
@@ -113,7 +142,7 @@ func getLabelsForSecretsAndJobs(key componentUniqueKey) client.MatchingLabels {
 	}
 }
 
-func renderJob(jobName string, engine *customizedEngine, key componentUniqueKey, statement []string, endpoint string) *batchv1.Job {
+func renderJob(jobName string, engine *customizedEngine, key componentUniqueKey, statement []string, endpoint string) (*batchv1.Job, error) {
 	// inject one more system env variables
 	statementEnv := corev1.EnvVar{
 		Name:  kbAccountStmtEnvName,
@@ -123,11 +152,15 @@ func renderJob(jobName string, engine *customizedEngine, key componentUniqueKey,
 		Name:  kbAccountEndPointEnvName,
 		Value: endpoint,
 	}
+	// resolve the built-in variables referred by the user defined envs.
+	namedValuesMap := componetutil.GetReplacementMapForCmdExecutor(key.clusterName, key.componentName, engine.podNames)
+	userEnvs, envFroms := componetutil.ReplaceCmdExecutorEnvVars(namedValuesMap, engine.getEnvs(), engine.getEnvFroms())
+
 	// place statements and endpoints before user defined envs.
-	envs := make([]corev1.EnvVar, 0, 2+len(engine.getEnvs()))
+	envs := make([]corev1.EnvVar, 0, 2+len(userEnvs))
 	envs = append(envs, statementEnv, endpointEnv)
-	if len(engine.getEnvs()) > 0 {
-		envs = append(envs, engine.getEnvs()...)
+	if len(userEnvs) > 0 {
+		envs = append(envs, userEnvs...)
 	}
 
 	jobContainer := corev1.Container{
@@ -137,8 +170,14 @@ func renderJob(jobName string, engine *customizedEngine, key componentUniqueKey,
 		Command:         engine.getCommand(),
 		Args:            engine.getArgs(),
 		Env:             envs,
+		EnvFrom:         envFroms,
 	}
 
+	resources, err := intctrlutil.BuildCmdExecutorResources(engine.getResources())
+	if err != nil {
+		return nil, err
+	}
+	jobContainer.Resources = resources
 	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&jobContainer)
 
 	job := &batchv1.Job{
@@ -159,7 +198,7 @@ func renderJob(jobName string, engine *customizedEngine, key componentUniqueKey,
 		},
 	}
 
-	return job
+	return job, nil
 }
 
 func renderSecretWithPwd(key componentUniqueKey, username, passwd string) *corev1.Secret {
@@ -246,15 +285,12 @@ func updateFacts(accountName appsv1alpha1.AccountName, detectedFacts *appsv1alph
 }
 
 func getCreationStmtForAccount(key componentUniqueKey, passConfig appsv1alpha1.PasswordConfig,
-	accountConfig appsv1alpha1.SystemAccountConfig, strategy updateStrategy) ([]string, string) {
-	// generated password with mixedcases = true
-	passwd, _ := password.Generate((int)(passConfig.Length), (int)(passConfig.NumDigits), (int)(passConfig.NumSymbols), false, false)
-	// refine password to upper or lower cases w.r.t configuration
-	switch passConfig.LetterCase {
-	case appsv1alpha1.UpperCases:
-		passwd = strings.ToUpper(passwd)
-	case appsv1alpha1.LowerCases:
-		passwd = strings.ToLower(passwd)
+	accountConfig appsv1alpha1.SystemAccountConfig, strategy updateStrategy) ([]string, string, error) {
+	// the password is generated by the generator registered with the name in the config, the default
+	// generator is used if it's not specified.
+	passwd, err := intctrlutil.GeneratePassword(passConfig)
+	if err != nil {
+		return nil, "", err
 	}
 
 	userName := (string)(accountConfig.Name)
@@ -285,7 +321,7 @@ func getCreationStmtForAccount(key componentUniqueKey, passConfig appsv1alpha1.P
 		execStmts = append(execStmts, stmt)
 	}
 	// secret := renderSecretWithPwd(key, userName, passwd)
-	return execStmts, passwd
+	return execStmts, passwd, nil
 }
 
 func getAllSysAccounts() []appsv1alpha1.AccountName {
@@ -337,7 +373,7 @@ func calibrateJobMetaAndSpec(job *batchv1.Job, cluster *appsv1alpha1.Cluster, co
 	return nil
 }
 
-// completeExecConfig overrides the image of execConfig if version is not nil.
+// completeExecConfig overrides the image, resources and envs of execConfig if version is not nil.
 func completeExecConfig(execConfig *appsv1alpha1.CmdExecutorConfig, version *appsv1alpha1.ClusterComponentVersion) {
 	if version == nil || version.SystemAccountSpec == nil || version.SystemAccountSpec.CmdExecutorConfig == nil {
 		return
@@ -346,6 +382,18 @@ func completeExecConfig(execConfig *appsv1alpha1.CmdExecutorConfig, version *app
 	if len(sysAccountSpec.CmdExecutorConfig.Image) > 0 {
 		execConfig.Image = sysAccountSpec.CmdExecutorConfig.Image
 	}
+	if len(sysAccountSpec.CmdExecutorConfig.Resources.Limits) > 0 || len(sysAccountSpec.CmdExecutorConfig.Resources.Requests) > 0 {
+		execConfig.Resources = sysAccountSpec.CmdExecutorConfig.Resources
+	}
+
+	// envFroms from sysAccountSpec will override the envFroms from execConfig
+	if sysAccountSpec.CmdExecutorConfig.EnvFrom != nil {
+		if len(sysAccountSpec.CmdExecutorConfig.EnvFrom) == 0 {
+			execConfig.EnvFrom = nil
+		} else {
+			execConfig.EnvFrom = sysAccountSpec.CmdExecutorConfig.EnvFrom
+		}
+	}
 
 	// envs from sysAccountSpec will override the envs from execConfig
 	if sysAccountSpec.CmdExecutorConfig.Env == nil {
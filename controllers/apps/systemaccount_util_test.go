@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"reflect"
@@ -28,8 +29,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
@@ -178,7 +183,7 @@ func TestRenderJob(t *testing.T) {
 	replaceEnvsValues(cluster.Name, accountsSetting, map[string]string{secret4ReferentPlaceholder: secret4Referent})
 	cmdExecutorConfig := accountsSetting.CmdExecutorConfig
 
-	engine := newCustomizedEngine(cmdExecutorConfig, cluster, mysqlCompName)
+	engine := newCustomizedEngine(cmdExecutorConfig, cluster, mysqlCompName, nil)
 	assert.NotNil(t, engine)
 
 	compKey := componentUniqueKey{
@@ -204,7 +209,8 @@ func TestRenderJob(t *testing.T) {
 	for _, acc := range accountsSetting.Accounts {
 		switch acc.ProvisionPolicy.Type {
 		case appsv1alpha1.CreateByStmt:
-			creationStmt, secrets := getCreationStmtForAccount(compKey, accountsSetting.PasswordConfig, acc, reCreate)
+			creationStmt, secrets, err := getCreationStmtForAccount(compKey, accountsSetting.PasswordConfig, acc, reCreate)
+			assert.Nil(t, err)
 			// make sure all variables have been replaced
 			for _, stmt := range creationStmt {
 				assert.False(t, strings.Contains(stmt, "$(USERNAME)"))
@@ -213,7 +219,8 @@ func TestRenderJob(t *testing.T) {
 			// render job with debug mode off
 			endpoint := "10.0.0.1"
 			mockJobName := "mock-job" + testCtx.GetRandomStr()
-			job := renderJob(mockJobName, engine, compKey, creationStmt, endpoint)
+			job, err := renderJob(mockJobName, engine, compKey, creationStmt, endpoint)
+			assert.Nil(t, err)
 			assert.NotNil(t, job)
 			_ = calibrateJobMetaAndSpec(job, cluster, compKey, acc.Name)
 			assert.NotNil(t, job.Spec.TTLSecondsAfterFinished)
@@ -222,7 +229,8 @@ func TestRenderJob(t *testing.T) {
 			assert.GreaterOrEqual(t, len(envList), 1)
 			assert.Equal(t, job.Spec.Template.Spec.Containers[0].Image, cmdExecutorConfig.Image)
 			// render job with debug mode on
-			job = renderJob(mockJobName, engine, compKey, creationStmt, endpoint)
+			job, err = renderJob(mockJobName, engine, compKey, creationStmt, endpoint)
+			assert.Nil(t, err)
 			assert.NotNil(t, job)
 			// set debug mode on
 			cluster.Annotations[debugClusterAnnotationKey] = "True"
@@ -235,7 +243,8 @@ func TestRenderJob(t *testing.T) {
 			toleration := make([]corev1.Toleration, 0)
 			toleration = append(toleration, generateToleration())
 			cluster.Spec.Tolerations = toleration
-			job = renderJob(mockJobName, engine, compKey, creationStmt, endpoint)
+			job, err = renderJob(mockJobName, engine, compKey, creationStmt, endpoint)
+			assert.Nil(t, err)
 			assert.NotNil(t, job)
 			_ = calibrateJobMetaAndSpec(job, cluster, compKey, acc.Name)
 			jobToleration := job.Spec.Template.Spec.Tolerations
@@ -253,6 +262,137 @@ func TestRenderJob(t *testing.T) {
 	}
 }
 
+func TestRenderJobResources(t *testing.T) {
+	compKey := componentUniqueKey{
+		namespace:     testCtx.DefaultNamespace,
+		clusterName:   "test-cluster",
+		componentName: "mysql",
+	}
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+	}
+	defaultResources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	}
+	defaultResourcesJSON, _ := json.Marshal(defaultResources)
+	defer viper.Set(constant.CfgKeyCmdExecutorDefaultResources, "")
+
+	testCases := []struct {
+		name             string
+		resources        corev1.ResourceRequirements
+		defaultResources string
+		expected         corev1.ResourceRequirements
+		expectedErr      bool
+	}{
+		{
+			name:      "resources specified in cmdExecutorConfig",
+			resources: resources,
+			expected:  resources,
+		},
+		{
+			name:             "resources specified in cmdExecutorConfig take precedence over the default",
+			resources:        resources,
+			defaultResources: string(defaultResourcesJSON),
+			expected:         resources,
+		},
+		{
+			name:             "fall back to the default resources",
+			defaultResources: string(defaultResourcesJSON),
+			expected:         defaultResources,
+		},
+		{
+			name:             "invalid default resources",
+			defaultResources: "invalid",
+			expectedErr:      true,
+		},
+	}
+	for _, tc := range testCases {
+		viper.Set(constant.CfgKeyCmdExecutorDefaultResources, tc.defaultResources)
+		engine := newCustomizedEngine(&appsv1alpha1.CmdExecutorConfig{
+			CommandExecutorEnvItem: appsv1alpha1.CommandExecutorEnvItem{
+				Image:     "mysql-8.0.30",
+				Resources: tc.resources,
+			},
+			CommandExecutorItem: appsv1alpha1.CommandExecutorItem{
+				Command: []string{"mysql", "-e", "$(KB_ACCOUNT_STATEMENT)"},
+			},
+		}, nil, compKey.componentName, nil)
+		job, err := renderJob("mock-job", engine, compKey, []string{"select 1"}, "10.0.0.1")
+		if tc.expectedErr {
+			assert.NotNil(t, err, tc.name)
+			continue
+		}
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expected, job.Spec.Template.Spec.Containers[0].Resources, tc.name)
+	}
+}
+
+func TestRenderJobEnvs(t *testing.T) {
+	compKey := componentUniqueKey{
+		namespace:     "default",
+		clusterName:   "mycluster",
+		componentName: "mysql",
+	}
+	engine := newCustomizedEngine(&appsv1alpha1.CmdExecutorConfig{
+		CommandExecutorEnvItem: appsv1alpha1.CommandExecutorEnvItem{
+			Image: "mysql-8.0.30",
+			Env: []corev1.EnvVar{
+				{Name: "ENDPOINT_$(KB_COMP_NAME)", Value: "$(KB_CLUSTER_NAME)-$(KB_COMP_NAME):3306"},
+				{Name: "PEERS", Value: "$(KB_POD_LIST)"},
+			},
+			EnvFrom: []corev1.EnvFromSource{
+				{
+					Prefix:       "$(KB_COMP_NAME)_",
+					ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "$(KB_CLUSTER_NAME)-endpoints"}},
+				},
+				{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "external-credential"}},
+				},
+			},
+		},
+		CommandExecutorItem: appsv1alpha1.CommandExecutorItem{
+			Command: []string{"mysql", "-e", "$(KB_ACCOUNT_STATEMENT)"},
+		},
+	}, nil, compKey.componentName, []string{"mycluster-mysql-1", "mycluster-mysql-0"})
+
+	job, err := renderJob("mock-job", engine, compKey, []string{"select 1"}, "10.0.0.1")
+	assert.Nil(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: kbAccountStmtEnvName, Value: "select 1"},
+		{Name: kbAccountEndPointEnvName, Value: "10.0.0.1"},
+		{Name: "ENDPOINT_mysql", Value: "mycluster-mysql:3306"},
+		{Name: "PEERS", Value: "mycluster-mysql-0,mycluster-mysql-1"},
+	}, container.Env)
+	assert.Equal(t, []corev1.EnvFromSource{
+		{
+			Prefix:       "mysql_",
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "mycluster-endpoints"}},
+		},
+		{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "external-credential"}},
+		},
+	}, container.EnvFrom)
+
+	// the envs of the engine are not modified.
+	assert.Equal(t, "$(KB_POD_LIST)", engine.getEnvs()[1].Value)
+	assert.Equal(t, "$(KB_CLUSTER_NAME)-endpoints", engine.getEnvFroms()[0].ConfigMapRef.Name)
+}
+
 func TestAccountNum(t *testing.T) {
 	totalAccounts := getAllSysAccounts()
 	accountNum := len(totalAccounts)
@@ -350,7 +490,8 @@ func TestRenderCreationStmt(t *testing.T) {
 				account.ProvisionPolicy.Statements.DeletionStatement = ""
 			}
 
-			stmts, secret := getCreationStmtForAccount(compKey, compDef.SystemAccounts.PasswordConfig, account, reCreate)
+			stmts, secret, err := getCreationStmtForAccount(compKey, compDef.SystemAccounts.PasswordConfig, account, reCreate)
+			assert.Nil(t, err)
 			if toss == 1 {
 				assert.Equal(t, 1, len(stmts))
 			} else {
@@ -358,13 +499,76 @@ func TestRenderCreationStmt(t *testing.T) {
 			}
 			assert.NotNil(t, secret)
 
-			stmts, secret = getCreationStmtForAccount(compKey, compDef.SystemAccounts.PasswordConfig, account, inPlaceUpdate)
+			stmts, secret, err = getCreationStmtForAccount(compKey, compDef.SystemAccounts.PasswordConfig, account, inPlaceUpdate)
+			assert.Nil(t, err)
 			assert.Equal(t, 1, len(stmts))
 			assert.NotNil(t, secret)
 		}
 	}
 }
 
+func TestGetCreationStmtWithAccountPasswordConfig(t *testing.T) {
+	compKey := componentUniqueKey{
+		namespace:     "default",
+		clusterName:   "cluster",
+		componentName: "comp",
+	}
+	length, numSymbols := int32(20), int32(0)
+	adminAccount := mockCreateByStmtSystemAccount(appsv1alpha1.AdminAccount)
+	monitorAccount := mockCreateByStmtSystemAccount(appsv1alpha1.MonitorAccount)
+	monitorAccount.PasswordConfig = &appsv1alpha1.PasswordConfigOverride{
+		Length:     &length,
+		NumSymbols: &numSymbols,
+	}
+	accountsSetting := &appsv1alpha1.SystemAccountSpec{
+		PasswordConfig: appsv1alpha1.PasswordConfig{
+			Length:     32,
+			NumDigits:  8,
+			NumSymbols: 8,
+			LetterCase: appsv1alpha1.MixedCases,
+		},
+		Accounts: []appsv1alpha1.SystemAccountConfig{adminAccount, monitorAccount},
+	}
+
+	_, passwd, err := getCreationStmtForAccount(compKey, accountsSetting.GetPasswordConfig(adminAccount), adminAccount, reCreate)
+	assert.Nil(t, err)
+	assert.Len(t, passwd, 32)
+
+	isAlphanumeric := func(r rune) bool {
+		return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+	}
+	for i := 0; i < 10; i++ {
+		_, passwd, err = getCreationStmtForAccount(compKey, accountsSetting.GetPasswordConfig(monitorAccount), monitorAccount, reCreate)
+		assert.Nil(t, err)
+		assert.Len(t, passwd, 20)
+		for _, r := range passwd {
+			assert.True(t, isAlphanumeric(r), "unexpected character %q in password %s", r, passwd)
+		}
+	}
+
+	// the default password is generated by the generator registered as the default one
+	defaultGenerator, err := intctrlutil.GetPasswordGenerator(appsv1alpha1.DefaultPasswordGenerator)
+	assert.Nil(t, err)
+	intctrlutil.RegisterPasswordGenerator(appsv1alpha1.DefaultPasswordGenerator, fixedPasswordGenerator("fixed-password"))
+	_, passwd, err = getCreationStmtForAccount(compKey, accountsSetting.GetPasswordConfig(adminAccount), adminAccount, reCreate)
+	intctrlutil.RegisterPasswordGenerator(appsv1alpha1.DefaultPasswordGenerator, defaultGenerator)
+	assert.Nil(t, err)
+	assert.Equal(t, "fixed-password", passwd)
+
+	// the account fails to be created if its password can not be generated by the generator
+	accountsSetting.PasswordConfig.Generator = appsv1alpha1.KmsPasswordGenerator
+	viper.Set(constant.CfgKeyKMSPasswordGeneratorURL, "")
+	stmts, _, err := getCreationStmtForAccount(compKey, accountsSetting.GetPasswordConfig(adminAccount), adminAccount, reCreate)
+	assert.NotNil(t, err)
+	assert.Empty(t, stmts)
+}
+
+type fixedPasswordGenerator string
+
+func (g fixedPasswordGenerator) Generate(appsv1alpha1.PasswordConfig) (string, error) {
+	return string(g), nil
+}
+
 func TestMergeSystemAccountConfig(t *testing.T) {
 	systemAccount := mockSystemAccountsSpec()
 	// Make sure env is not empty
@@ -450,3 +654,37 @@ func TestMergeSystemAccountConfig(t *testing.T) {
 	assert.Len(t, accountConfig.Env, 1)
 	assert.Contains(t, accountConfig.Env, testEnv)
 }
+
+func TestGetConnCredentialAccounts(t *testing.T) {
+	clusterName := "test-cluster"
+	connCredential := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testCtx.DefaultNamespace,
+			Name:      constant.GenerateDefaultConnCredential(clusterName),
+		},
+	}
+	spec := &appsv1alpha1.SystemAccountSpec{
+		Accounts: []appsv1alpha1.SystemAccountConfig{
+			mockCreateByStmtSystemAccount(appsv1alpha1.MonitorAccount),
+			mockCreateByRefSystemAccount(appsv1alpha1.ProbeAccount, appsv1alpha1.AnyPods),
+			mockCreateByRefSystemAccount(appsv1alpha1.AdminAccount, appsv1alpha1.AnyPods),
+			mockCreateByRefSystemAccount(appsv1alpha1.ReplicatorAccount, appsv1alpha1.AllPods),
+		},
+	}
+	// the admin account refers to the connection credential without update statement
+	spec.Accounts[2].ProvisionPolicy.SecretRef.Name = constant.KBConnCredentialPlaceHolder
+	// the replicator account refers to the connection credential with update statement
+	spec.Accounts[3].ProvisionPolicy.SecretRef.Name = constant.KBConnCredentialPlaceHolder
+	spec.Accounts[3].ProvisionPolicy.Statements = &appsv1alpha1.ProvisionStatements{
+		UpdateStatement: "ALTER USER $(USERNAME) IDENTIFIED BY \"$(PASSWD)\";",
+	}
+	replaceEnvsValues(clusterName, spec, nil)
+
+	// the password is not rotated
+	accounts := getConnCredentialAccounts(spec, connCredential, map[string]string{"RANDOM_PASSWD": "passwd"})
+	assert.Empty(t, accounts)
+
+	accounts = getConnCredentialAccounts(spec, connCredential, map[string]string{constant.AccountPasswdForSecret: "passwd"})
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, appsv1alpha1.ReplicatorAccount, accounts[0].Name)
+}
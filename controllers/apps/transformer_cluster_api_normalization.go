@@ -104,6 +104,21 @@ func (t *ClusterAPINormalizationTransformer) Transform(ctx graph.TransformContex
 			}
 		}
 	}
+
+	// validate the podSpecPatch of components, the cluster fails to apply resources if the patch can't be applied
+	return validateComponentPodSpecPatches(transCtx)
+}
+
+func validateComponentPodSpecPatches(transCtx *clusterTransformContext) error {
+	for _, compSpec := range transCtx.ComponentSpecs {
+		if compSpec.PodSpecPatch == nil {
+			continue
+		}
+		compDef := transCtx.ComponentDefs[compSpec.ComponentDef]
+		if _, err := component.ApplyPodSpecPatch(compDef.Spec.Runtime.DeepCopy(), compSpec.PodSpecPatch); err != nil {
+			return fmt.Errorf("invalid podSpecPatch of component %s: %s", compSpec.Name, err.Error())
+		}
+	}
 	return nil
 }
 
@@ -20,11 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -49,7 +54,9 @@ type clusterBackupPolicyTransformer struct {
 
 	tplCount          int
 	tplIdentifier     string
-	isDefaultTemplate string
+	isDefaultTemplate bool
+	// defaultTpls maps the component names to their default backup policy templates.
+	defaultTpls map[string]string
 
 	backupPolicyTpl *appsv1alpha1.BackupPolicyTemplate
 	backupPolicy    *appsv1alpha1.BackupPolicy
@@ -82,10 +89,23 @@ func (r *clusterBackupPolicyTransformer) Transform(ctx graph.TransformContext, d
 		return nil
 	}
 
+	// resolve the default backup policy template of each component, only the
+	// backup policies generated from the default templates are the defaults.
+	r.defaultTpls = map[string]string{}
+	for _, comp := range r.Cluster.Spec.ComponentSpecs {
+		var compDef *appsv1alpha1.ComponentDefinition
+		if comp.ComponentDef != "" {
+			compDef = r.ComponentDefs[comp.ComponentDef]
+		}
+		if tpl := resolveDefaultBackupPolicyTemplate(backupPolicyTpls.Items, r.ClusterDef, compDef, comp.ComponentDefRef); tpl != nil {
+			r.defaultTpls[comp.Name] = tpl.Name
+		}
+	}
+	meta.SetStatusCondition(&r.Cluster.Status.Conditions, newBackupPolicyTemplateResolvedCondition(r.defaultTpls))
+
 	backupPolicyNames := map[string]struct{}{}
 	backupScheduleNames := map[string]struct{}{}
 	for _, tpl := range backupPolicyTpls.Items {
-		r.isDefaultTemplate = tpl.Annotations[dptypes.DefaultBackupPolicyTemplateAnnotationKey]
 		r.tplIdentifier = tpl.Spec.Identifier
 		r.backupPolicyTpl = &tpl
 
@@ -97,6 +117,7 @@ func (r *clusterBackupPolicyTransformer) Transform(ctx graph.TransformContext, d
 				if comp == nil {
 					return nil
 				}
+				r.isDefaultTemplate = r.defaultTpls[comp.Name] == tpl.Name
 				// build the data protection backup policy from the template.
 				oldBackupPolicy, newBackupPolicy := r.transformBackupPolicy(comp)
 				if newBackupPolicy == nil {
@@ -129,7 +150,7 @@ func (r *clusterBackupPolicyTransformer) Transform(ctx graph.TransformContext, d
 
 				// only create backup schedule for the default backup policy template
 				// if there are more than one backup policy templates.
-				if r.isDefaultTemplate != trueVal && r.tplCount > 1 {
+				if !r.isDefaultTemplate && r.tplCount > 1 {
 					r.V(1).Info("Skip creating backup schedule for non-default backup policy template %s", tpl.Name)
 					return
 				}
@@ -569,7 +590,7 @@ func (r *clusterBackupPolicyTransformer) getClusterComponentSpec() *appsv1alpha1
 }
 
 func (r *clusterBackupPolicyTransformer) defaultPolicyAnnotationValue() string {
-	if r.tplCount > 1 && r.isDefaultTemplate != trueVal {
+	if r.tplCount > 1 && !r.isDefaultTemplate {
 		return "false"
 	}
 	return trueVal
@@ -587,11 +608,16 @@ func (r *clusterBackupPolicyTransformer) buildAnnotations() map[string]string {
 }
 
 func (r *clusterBackupPolicyTransformer) buildLabels() map[string]string {
-	return map[string]string{
+	labels := map[string]string{
 		constant.AppInstanceLabelKey:          r.OrigCluster.Name,
 		constant.KBAppComponentDefRefLabelKey: r.backupPolicy.ComponentDefRef,
 		constant.AppManagedByLabelKey:         constant.AppName,
 	}
+	// the template name is also kept in the annotation, as it may exceed the length limit of a label value.
+	if len(validation.IsValidLabelValue(r.backupPolicyTpl.Name)) == 0 {
+		labels[constant.BackupPolicyTemplateLabelKey] = r.backupPolicyTpl.Name
+	}
+	return labels
 }
 
 // buildTargetPodLabels builds the target labels for the backup policy that will be
@@ -610,6 +636,102 @@ func (r *clusterBackupPolicyTransformer) buildTargetPodLabels(targetTpl appsv1al
 	return labels
 }
 
+// resolveDefaultBackupPolicyTemplate resolves the default backup policy template of the component from the templates
+// referring to the ClusterDefinition, the component is specified by the ComponentDefinition or the componentDefRef.
+// The templates are chosen in the following precedence:
+//  1. the template named by the default-backup-policy-template annotation of the ComponentDefinition;
+//  2. the template named by the default-backup-policy-template annotation of the ClusterDefinition;
+//  3. the template claiming to be the default by the is-default-policy-template annotation, with the highest priority;
+//  4. the template with the highest priority, and the smallest name if the priorities are equal.
+//
+// The backup policies and the data clone of the horizontal scaling both resolve the default template by it,
+// so they always agree on the default template.
+func resolveDefaultBackupPolicyTemplate(tpls []appsv1alpha1.BackupPolicyTemplate,
+	clusterDef *appsv1alpha1.ClusterDefinition,
+	compDef *appsv1alpha1.ComponentDefinition,
+	compDefRef string) *appsv1alpha1.BackupPolicyTemplate {
+	compDefName := ""
+	if compDef != nil {
+		compDefName = compDef.Name
+	}
+	var candidates []*appsv1alpha1.BackupPolicyTemplate
+	for i := range tpls {
+		for _, policy := range tpls[i].Spec.BackupPolicies {
+			if (compDefRef != "" && policy.ComponentDefRef == compDefRef) ||
+				(compDefName != "" && slices.Contains(policy.ComponentDefs, compDefName)) {
+				candidates = append(candidates, &tpls[i])
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// the annotation of the ComponentDefinition overrides the one of the ClusterDefinition.
+	var annotations []map[string]string
+	if compDef != nil {
+		annotations = append(annotations, compDef.Annotations)
+	}
+	if clusterDef != nil {
+		annotations = append(annotations, clusterDef.Annotations)
+	}
+	for _, annotation := range annotations {
+		tplName := annotation[constant.DefaultBackupPolicyTemplateNameAnnotKey]
+		if tplName == "" {
+			continue
+		}
+		for _, tpl := range candidates {
+			if tpl.Name == tplName {
+				return tpl
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].IsDefault() != candidates[j].IsDefault() {
+			return candidates[i].IsDefault()
+		}
+		if candidates[i].Spec.Priority != candidates[j].Spec.Priority {
+			return candidates[i].Spec.Priority > candidates[j].Spec.Priority
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates[0]
+}
+
+// getDefaultBackupPolicyTemplate lists the backup policy templates of the ClusterDefinition and resolves
+// the default one of the component by resolveDefaultBackupPolicyTemplate.
+func getDefaultBackupPolicyTemplate(ctx context.Context, cli client.Reader,
+	clusterDefName, compDefName, compDefRef string) (*appsv1alpha1.BackupPolicyTemplate, error) {
+	tplList := &appsv1alpha1.BackupPolicyTemplateList{}
+	if err := cli.List(ctx, tplList, client.MatchingLabels{constant.ClusterDefLabelKey: clusterDefName}); err != nil {
+		return nil, err
+	}
+	if len(tplList.Items) == 0 {
+		return nil, nil
+	}
+	var clusterDef *appsv1alpha1.ClusterDefinition
+	if clusterDefName != "" {
+		clusterDef = &appsv1alpha1.ClusterDefinition{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: clusterDefName}, clusterDef); err != nil {
+			return nil, err
+		}
+	}
+	var compDef *appsv1alpha1.ComponentDefinition
+	if compDefName != "" {
+		compDef = &appsv1alpha1.ComponentDefinition{}
+		// the ComponentDefinitions of the legacy components are built from the ClusterDefinition, they are not found.
+		if err := cli.Get(ctx, client.ObjectKey{Name: compDefName}, compDef); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			compDef = nil
+		}
+	}
+	return resolveDefaultBackupPolicyTemplate(tplList.Items, clusterDef, compDef, compDefRef), nil
+}
+
 // generateBackupPolicyName generates the backup policy name which is created from backup policy template.
 func generateBackupPolicyName(clusterName, componentDef, identifier string) string {
 	if len(identifier) == 0 {
@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestResolveDefaultBackupPolicyTemplate(t *testing.T) {
+	newTpl := func(name string, isDefault bool, priority int32, policies ...appsv1alpha1.BackupPolicy) appsv1alpha1.BackupPolicyTemplate {
+		tpl := appsv1alpha1.BackupPolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{}},
+			Spec: appsv1alpha1.BackupPolicyTemplateSpec{
+				ClusterDefRef:  "mysql",
+				BackupPolicies: policies,
+				Priority:       priority,
+			},
+		}
+		if isDefault {
+			tpl.Annotations[dptypes.DefaultBackupPolicyTemplateAnnotationKey] = trueVal
+		}
+		return tpl
+	}
+	withDefaultAnnotation := func(tplName string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:        "mysql",
+			Annotations: map[string]string{constant.DefaultBackupPolicyTemplateNameAnnotKey: tplName},
+		}
+	}
+	byCompDefRef := appsv1alpha1.BackupPolicy{ComponentDefRef: "mysql"}
+	byCompDef := appsv1alpha1.BackupPolicy{ComponentDefs: []string{"mysql"}}
+
+	testCases := []struct {
+		name       string
+		tpls       []appsv1alpha1.BackupPolicyTemplate
+		clusterDef *appsv1alpha1.ClusterDefinition
+		compDef    *appsv1alpha1.ComponentDefinition
+		compDefRef string
+		expected   string
+	}{
+		{
+			name:       "no template matches the component",
+			tpls:       []appsv1alpha1.BackupPolicyTemplate{newTpl("a", true, 0, appsv1alpha1.BackupPolicy{ComponentDefRef: "proxy"})},
+			compDefRef: "mysql",
+		},
+		{
+			name:       "the highest priority",
+			tpls:       []appsv1alpha1.BackupPolicyTemplate{newTpl("a", false, 1, byCompDefRef), newTpl("b", false, 2, byCompDefRef)},
+			compDefRef: "mysql",
+			expected:   "b",
+		},
+		{
+			name:       "the smallest name with the same priority",
+			tpls:       []appsv1alpha1.BackupPolicyTemplate{newTpl("b", false, 0, byCompDefRef), newTpl("a", false, 0, byCompDefRef)},
+			compDefRef: "mysql",
+			expected:   "a",
+		},
+		{
+			name:       "the template claiming default",
+			tpls:       []appsv1alpha1.BackupPolicyTemplate{newTpl("a", false, 2, byCompDefRef), newTpl("b", true, 1, byCompDefRef)},
+			compDefRef: "mysql",
+			expected:   "b",
+		},
+		{
+			name: "the annotation of the ClusterDefinition",
+			tpls: []appsv1alpha1.BackupPolicyTemplate{newTpl("a", true, 2, byCompDefRef), newTpl("b", false, 1, byCompDefRef)},
+			clusterDef: &appsv1alpha1.ClusterDefinition{
+				ObjectMeta: withDefaultAnnotation("b"),
+			},
+			compDefRef: "mysql",
+			expected:   "b",
+		},
+		{
+			name: "the annotation naming a template of other components",
+			tpls: []appsv1alpha1.BackupPolicyTemplate{newTpl("a", true, 2, byCompDefRef), newTpl("b", false, 1, appsv1alpha1.BackupPolicy{ComponentDefRef: "proxy"})},
+			clusterDef: &appsv1alpha1.ClusterDefinition{
+				ObjectMeta: withDefaultAnnotation("b"),
+			},
+			compDefRef: "mysql",
+			expected:   "a",
+		},
+		{
+			name: "the annotation of the ComponentDefinition overrides the ClusterDefinition",
+			tpls: []appsv1alpha1.BackupPolicyTemplate{newTpl("a", false, 0, byCompDef), newTpl("b", false, 0, byCompDef), newTpl("c", true, 0, byCompDef)},
+			clusterDef: &appsv1alpha1.ClusterDefinition{
+				ObjectMeta: withDefaultAnnotation("a"),
+			},
+			compDef: &appsv1alpha1.ComponentDefinition{
+				ObjectMeta: withDefaultAnnotation("b"),
+			},
+			expected: "b",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tpl := resolveDefaultBackupPolicyTemplate(tc.tpls, tc.clusterDef, tc.compDef, tc.compDefRef)
+			switch {
+			case tc.expected == "" && tpl != nil:
+				t.Errorf("expected no template, got: %s", tpl.Name)
+			case tc.expected != "" && (tpl == nil || tpl.Name != tc.expected):
+				t.Errorf("expected template %s, got: %v", tc.expected, tpl)
+			}
+		})
+	}
+}
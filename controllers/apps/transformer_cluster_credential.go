@@ -24,6 +24,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/common"
 
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
@@ -69,11 +70,14 @@ func (t *clusterConnCredentialTransformer) buildClusterConnCredential(transCtx *
 	if synthesizedComponent == nil {
 		return nil
 	}
-	secret := factory.BuildConnCredential(transCtx.ClusterDef, transCtx.Cluster, synthesizedComponent)
+	secret, err := factory.BuildConnCredential(transCtx.ClusterDef, transCtx.Cluster, synthesizedComponent)
+	if err != nil {
+		return err
+	}
 	if secret == nil {
 		return nil
 	}
-	err := transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(secret), &corev1.Secret{})
+	err = transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(secret), &corev1.Secret{})
 	if err != nil && !apierrors.IsNotFound(err) {
 		return err
 	}
@@ -84,11 +88,18 @@ func (t *clusterConnCredentialTransformer) buildClusterConnCredential(transCtx *
 }
 
 func (t *clusterConnCredentialTransformer) buildSynthesizedComponent(transCtx *clusterTransformContext) *component.SynthesizedComponent {
-	for _, compDef := range transCtx.ClusterDef.Spec.ComponentDefs {
+	return buildConnCredentialSynthesizedComponent(transCtx.ClusterDef, transCtx.ComponentSpecs)
+}
+
+// buildConnCredentialSynthesizedComponent builds the component which the connection credential is rendered against,
+// it's the first component whose componentDef defines a service.
+func buildConnCredentialSynthesizedComponent(clusterDef *appsv1alpha1.ClusterDefinition,
+	compSpecs []*appsv1alpha1.ClusterComponentSpec) *component.SynthesizedComponent {
+	for _, compDef := range clusterDef.Spec.ComponentDefs {
 		if compDef.Service == nil {
 			continue
 		}
-		for _, compSpec := range transCtx.ComponentSpecs {
+		for _, compSpec := range compSpecs {
 			if compDef.Name != compSpec.ComponentDefRef {
 				continue
 			}
@@ -30,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -42,6 +43,16 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	"github.com/apecloud/kubeblocks/pkg/controller/rsm"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+)
+
+const (
+	// defaultFinalBackupTimeoutMinutes is the default timeout of the final backup.
+	defaultFinalBackupTimeoutMinutes = 60
+	finalBackupRequeueDuration       = 5 * time.Second
+	finalBackupFailedRequeueDuration = 30 * time.Second
 )
 
 // clusterDeletionTransformer handles cluster deletion
@@ -77,6 +88,13 @@ func (t *clusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 		toDeleteNamespacedKinds, toDeleteNonNamespacedKinds = kindsForWipeOut()
 	}
 
+	// take the final backup before the data is deleted
+	if cluster.Spec.TerminationPolicy == appsv1alpha1.Delete || cluster.Spec.TerminationPolicy == appsv1alpha1.WipeOut {
+		if err := reconcileFinalBackup(transCtx, dag); err != nil {
+			return err
+		}
+	}
+
 	transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeNormal, constant.ReasonDeletingCR, "Deleting %s: %s",
 		strings.ToLower(cluster.GetObjectKind().GroupVersionKind().Kind), cluster.GetName())
 
@@ -136,18 +154,6 @@ func (t *clusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 		return err
 	}
 
-	toDeleteObjs := func(objs clusterOwningObjects) []client.Object {
-		var delObjs []client.Object
-		for _, obj := range objs {
-			// retain backup for data protection even if the cluster is wiped out.
-			if strings.EqualFold(obj.GetLabels()[constant.BackupProtectionLabelKey], constant.BackupRetain) {
-				continue
-			}
-			delObjs = append(delObjs, obj)
-		}
-		return delObjs
-	}
-
 	// add namespaced objects deletion vertex
 	namespacedObjs, err := getClusterOwningNamespacedObjects(transCtx, *cluster, ml, toDeleteNamespacedKinds)
 	if err != nil {
@@ -190,6 +196,131 @@ func (t *clusterDeletionTransformer) Transform(ctx graph.TransformContext, dag *
 	return graph.ErrPrematureStop
 }
 
+// toDeleteObjs filters the objects to delete, the retained backups and the final backup are kept.
+func toDeleteObjs(objs clusterOwningObjects) []client.Object {
+	var delObjs []client.Object
+	for _, obj := range objs {
+		// retain backup for data protection even if the cluster is wiped out.
+		if strings.EqualFold(obj.GetLabels()[constant.BackupProtectionLabelKey], constant.BackupRetain) {
+			continue
+		}
+		// retain the final backup, which is taken for restoring the deleted cluster.
+		if obj.GetLabels()[dptypes.FinalBackupLabelKey] == "true" {
+			continue
+		}
+		delObjs = append(delObjs, obj)
+	}
+	return delObjs
+}
+
+// reconcileFinalBackup takes the final backup of the cluster if it is enabled, and pauses the deletion until
+// the final backup is completed. The deletion is paused with a warning if the final backup is failed or timed out.
+func reconcileFinalBackup(transCtx *clusterTransformContext, dag *graph.DAG) error {
+	cluster := transCtx.OrigCluster
+	if cluster.Spec.Backup == nil || !boolptr.IsSetToTrue(cluster.Spec.Backup.FinalBackupOnDelete) {
+		return nil
+	}
+	graphCli, ok := transCtx.Client.(model.GraphClient)
+	if !ok {
+		return fmt.Errorf("the client of cluster %s is not a graph client", cluster.Name)
+	}
+
+	backup := &dpv1alpha1.Backup{}
+	err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Namespace: cluster.Namespace, Name: generateFinalBackupName(cluster)}, backup)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		if backup, err = buildFinalBackup(transCtx, cluster); err != nil {
+			transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeWarning, "FinalBackupFailed",
+				"failed to create the final backup, the deletion is paused: %s", err.Error())
+			graphCli.Status(dag, cluster, transCtx.Cluster)
+			return newRequeueError(finalBackupFailedRequeueDuration, err.Error())
+		}
+		transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeNormal, "CreatingFinalBackup",
+			"creating the final backup %s before the cluster is deleted", backup.Name)
+		graphCli.Create(dag, backup)
+		graphCli.Status(dag, cluster, transCtx.Cluster)
+		return newRequeueError(finalBackupRequeueDuration, "waiting for the final backup")
+	}
+
+	var message string
+	switch backup.Status.Phase {
+	case dpv1alpha1.BackupPhaseCompleted:
+		return nil
+	case dpv1alpha1.BackupPhaseFailed:
+		message = fmt.Sprintf("the final backup %s is failed: %s", backup.Name, backup.Status.FailureReason)
+	default:
+		timeout := time.Duration(defaultFinalBackupTimeoutMinutes) * time.Minute
+		if cluster.Spec.Backup.FinalBackupTimeoutMinutes != nil {
+			timeout = time.Duration(*cluster.Spec.Backup.FinalBackupTimeoutMinutes) * time.Minute
+		}
+		if time.Since(backup.CreationTimestamp.Time) <= timeout {
+			graphCli.Status(dag, cluster, transCtx.Cluster)
+			return newRequeueError(finalBackupRequeueDuration, "waiting for the final backup")
+		}
+		message = fmt.Sprintf("the final backup %s is not completed in %s", backup.Name, timeout)
+	}
+	transCtx.EventRecorder.Eventf(cluster, corev1.EventTypeWarning, "FinalBackupFailed",
+		"%s, the deletion is paused until the final backup is disabled by spec.backup.finalBackupOnDelete", message)
+	graphCli.Status(dag, cluster, transCtx.Cluster)
+	return newRequeueError(finalBackupFailedRequeueDuration, message)
+}
+
+// buildFinalBackup builds the final backup of the cluster with the backup method of the cluster
+// and its default backup policy.
+func buildFinalBackup(transCtx *clusterTransformContext, cluster *appsv1alpha1.Cluster) (*dpv1alpha1.Backup, error) {
+	backupPolicies := &dpv1alpha1.BackupPolicyList{}
+	if err := transCtx.Client.List(transCtx.Context, backupPolicies, client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: cluster.Name}); err != nil {
+		return nil, err
+	}
+	var backupPolicy *dpv1alpha1.BackupPolicy
+	for i, policy := range backupPolicies.Items {
+		if policy.Annotations[dptypes.DefaultBackupPolicyAnnotationKey] == "true" {
+			backupPolicy = &backupPolicies.Items[i]
+			break
+		}
+	}
+	if backupPolicy == nil {
+		return nil, fmt.Errorf(`not found any default backup policy for cluster "%s"`, cluster.Name)
+	}
+	backupMethod := cluster.Spec.Backup.Method
+	if backupMethod == "" {
+		backupMethod, _ = dputils.GetBackupMethodsFromBackupPolicy(backupPolicies, backupPolicy.Name)
+	}
+	if backupMethod == "" {
+		return nil, fmt.Errorf(`not found the backup method of the final backup in the backup policy "%s"`, backupPolicy.Name)
+	}
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      generateFinalBackupName(cluster),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey: cluster.Name,
+				dptypes.FinalBackupLabelKey:  "true",
+			},
+			Annotations: map[string]string{
+				dptypes.CreatedByAnnotationKey: dptypes.CreatedByClusterPrefix + cluster.Name,
+			},
+		},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: backupPolicy.Name,
+			BackupMethod:     backupMethod,
+		},
+	}, nil
+}
+
+// generateFinalBackupName generates the name of the final backup, the UID of the cluster is suffixed to
+// avoid conflicting with the final backup of a deleted cluster with the same name.
+func generateFinalBackupName(cluster *appsv1alpha1.Cluster) string {
+	uid := string(cluster.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-final-backup-%s", cluster.Name, uid)
+}
+
 func haltPreserveKinds() []client.ObjectList {
 	return []client.ObjectList{
 		&corev1.PersistentVolumeClaimList{},
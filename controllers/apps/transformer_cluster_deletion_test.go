@@ -0,0 +1,234 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestGenerateFinalBackupName(t *testing.T) {
+	cluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "mysql",
+			UID:  "5d3a1e7c-0b4f-4c55-9d6e-2f8a7b1c9e01",
+		},
+	}
+	if name := generateFinalBackupName(cluster); name != "mysql-final-backup-5d3a1e7c" {
+		t.Errorf("unexpected final backup name: %s", name)
+	}
+	// the final backup of a re-created cluster with the same name should not conflict with the previous one.
+	cluster.UID = "8c2f4b6a-1d3e-4f5a-9b7c-0e1d2c3b4a59"
+	if name := generateFinalBackupName(cluster); name != "mysql-final-backup-8c2f4b6a" {
+		t.Errorf("unexpected final backup name: %s", name)
+	}
+}
+
+func newFinalBackupTestContext(t *testing.T, cluster *appsv1alpha1.Cluster, objs ...client.Object) (*clusterTransformContext, *graph.DAG, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := dpv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	recorder := record.NewFakeRecorder(10)
+	transCtx := &clusterTransformContext{
+		Context:       context.Background(),
+		Client:        model.NewGraphClient(cli),
+		EventRecorder: recorder,
+		Cluster:       cluster.DeepCopy(),
+		OrigCluster:   cluster,
+	}
+	dag := graph.NewDAG()
+	model.NewGraphClient(nil).Root(dag, cluster, transCtx.Cluster, model.ActionStatusPtr())
+	return transCtx, dag, recorder
+}
+
+func newFinalBackupTestCluster(enabled bool) *appsv1alpha1.Cluster {
+	return &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "mysql",
+			UID:       "5d3a1e7c-0b4f-4c55-9d6e-2f8a7b1c9e01",
+		},
+		Spec: appsv1alpha1.ClusterSpec{
+			TerminationPolicy: appsv1alpha1.Delete,
+			Backup: &appsv1alpha1.ClusterBackup{
+				FinalBackupOnDelete: &enabled,
+				Method:              "xtrabackup",
+			},
+		},
+	}
+}
+
+func newFinalBackup(cluster *appsv1alpha1.Cluster, phase dpv1alpha1.BackupPhase, age time.Duration) *dpv1alpha1.Backup {
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         cluster.Namespace,
+			Name:              generateFinalBackupName(cluster),
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Labels:            map[string]string{dptypes.FinalBackupLabelKey: "true"},
+		},
+		Status: dpv1alpha1.BackupStatus{Phase: phase, FailureReason: "job failed"},
+	}
+}
+
+func expectFinalBackupEvent(t *testing.T, recorder *record.FakeRecorder, reason string) {
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, reason) {
+			t.Errorf("expected the event %s, got: %s", reason, event)
+		}
+	default:
+		t.Errorf("expected the event %s, got none", reason)
+	}
+}
+
+func TestReconcileFinalBackupDisabled(t *testing.T) {
+	cluster := newFinalBackupTestCluster(false)
+	transCtx, dag, recorder := newFinalBackupTestContext(t, cluster)
+	if err := reconcileFinalBackup(transCtx, dag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dag.Vertices()) != 1 || len(recorder.Events) != 0 {
+		t.Error("expected the final backup to be skipped")
+	}
+}
+
+func TestReconcileFinalBackupCreated(t *testing.T) {
+	cluster := newFinalBackupTestCluster(true)
+	backupPolicy := &dpv1alpha1.BackupPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   cluster.Namespace,
+			Name:        "mysql-backup-policy",
+			Labels:      map[string]string{constant.AppInstanceLabelKey: cluster.Name},
+			Annotations: map[string]string{dptypes.DefaultBackupPolicyAnnotationKey: "true"},
+		},
+	}
+	transCtx, dag, recorder := newFinalBackupTestContext(t, cluster, backupPolicy)
+	err := reconcileFinalBackup(transCtx, dag)
+	if !intctrlutil.IsRequeueError(err) {
+		t.Fatalf("expected the deletion to be paused, got: %v", err)
+	}
+	backups := model.NewGraphClient(nil).FindAll(dag, &dpv1alpha1.Backup{})
+	if len(backups) != 1 {
+		t.Fatalf("expected the final backup to be created, got: %d", len(backups))
+	}
+	backup := backups[0].(*dpv1alpha1.Backup)
+	if backup.Spec.BackupPolicyName != backupPolicy.Name || backup.Spec.BackupMethod != "xtrabackup" ||
+		backup.Labels[dptypes.FinalBackupLabelKey] != "true" {
+		t.Errorf("unexpected final backup: %v", backup)
+	}
+	expectFinalBackupEvent(t, recorder, "CreatingFinalBackup")
+}
+
+func TestReconcileFinalBackupWithoutBackupPolicy(t *testing.T) {
+	cluster := newFinalBackupTestCluster(true)
+	transCtx, dag, recorder := newFinalBackupTestContext(t, cluster)
+	err := reconcileFinalBackup(transCtx, dag)
+	if !intctrlutil.IsRequeueError(err) {
+		t.Fatalf("expected the deletion to be paused, got: %v", err)
+	}
+	expectFinalBackupEvent(t, recorder, "FinalBackupFailed")
+}
+
+func TestReconcileFinalBackupPhases(t *testing.T) {
+	cluster := newFinalBackupTestCluster(true)
+	tests := []struct {
+		name        string
+		backup      *dpv1alpha1.Backup
+		expectPause bool
+		expectEvent string
+	}{
+		{
+			name:        "the deletion is paused while the final backup is running",
+			backup:      newFinalBackup(cluster, dpv1alpha1.BackupPhaseRunning, time.Minute),
+			expectPause: true,
+		},
+		{
+			name:        "the deletion is paused with a warning if the final backup is failed",
+			backup:      newFinalBackup(cluster, dpv1alpha1.BackupPhaseFailed, time.Minute),
+			expectPause: true,
+			expectEvent: "is failed: job failed",
+		},
+		{
+			name:        "the deletion is paused with a warning if the final backup is timed out",
+			backup:      newFinalBackup(cluster, dpv1alpha1.BackupPhaseRunning, (defaultFinalBackupTimeoutMinutes+1)*time.Minute),
+			expectPause: true,
+			expectEvent: "is not completed in",
+		},
+		{
+			name:   "the deletion continues once the final backup is completed",
+			backup: newFinalBackup(cluster, dpv1alpha1.BackupPhaseCompleted, time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transCtx, dag, recorder := newFinalBackupTestContext(t, cluster, tt.backup)
+			err := reconcileFinalBackup(transCtx, dag)
+			if paused := intctrlutil.IsRequeueError(err); paused != tt.expectPause {
+				t.Fatalf("expected the deletion paused: %v, got: %v", tt.expectPause, err)
+			}
+			if tt.expectEvent == "" {
+				if len(recorder.Events) != 0 {
+					t.Errorf("unexpected event: %s", <-recorder.Events)
+				}
+				return
+			}
+			expectFinalBackupEvent(t, recorder, tt.expectEvent)
+		})
+	}
+}
+
+func TestToDeleteObjsRetainsFinalBackup(t *testing.T) {
+	newObj := func(name string, labels map[string]string) client.Object {
+		return &dpv1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: labels}}
+	}
+	objs := clusterOwningObjects{}
+	for _, obj := range []client.Object{
+		newObj("backup", nil),
+		newObj("final-backup", map[string]string{dptypes.FinalBackupLabelKey: "true"}),
+		newObj("retained-backup", map[string]string{constant.BackupProtectionLabelKey: constant.BackupRetain}),
+	} {
+		objs[gvkNObjKey{ObjectKey: client.ObjectKeyFromObject(obj)}] = obj
+	}
+	delObjs := toDeleteObjs(objs)
+	if len(delObjs) != 1 || delObjs[0].GetName() != "backup" {
+		t.Errorf("expected only the backup to be deleted, got: %v", delObjs)
+	}
+}
@@ -20,14 +20,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
-	"errors"
-	"fmt"
-
-	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
 // clusterLoadRefResourcesTransformer loads and validates referenced resources (cd & cv).
@@ -53,42 +50,23 @@ func (t *clusterLoadRefResourcesTransformer) Transform(ctx graph.TransformContex
 		return newRequeueError(requeueDuration, "two kinds of definitions cannot be used together")
 	}
 
-	validateExistence := func(key client.ObjectKey, object client.Object) error {
-		err = transCtx.Client.Get(transCtx.Context, key, object)
-		if err != nil {
-			return newRequeueError(requeueDuration, err.Error())
-		}
-		return nil
-	}
-
-	// validate cd & cv's existence
-	// if we can't get the referenced cd & cv, set provisioning condition failed, and jump to plan.Execute()
+	// validate cd & cv's existence and availability for their latest generations
+	// if we can't get the referenced cd & cv, or they are unavailable, set provisioning condition failed, and jump to plan.Execute()
 	var (
 		cd *appsv1alpha1.ClusterDefinition
 		cv *appsv1alpha1.ClusterVersion
 	)
 	if len(cluster.Spec.ClusterDefRef) > 0 {
-		cd = &appsv1alpha1.ClusterDefinition{}
-		if err = validateExistence(types.NamespacedName{Name: cluster.Spec.ClusterDefRef}, cd); err != nil {
-			return err
+		cd = &appsv1alpha1.ClusterDefinition{ObjectMeta: metav1.ObjectMeta{Name: cluster.Spec.ClusterDefRef}}
+		if err = intctrlutil.WaitForReferencedObjectAvailable(transCtx.Context, transCtx.Client, cd, 0); err != nil {
+			return newRequeueError(requeueDuration, err.Error())
 		}
 	}
 	if len(cluster.Spec.ClusterVersionRef) > 0 {
-		cv = &appsv1alpha1.ClusterVersion{}
-		if err = validateExistence(types.NamespacedName{Name: cluster.Spec.ClusterVersionRef}, cv); err != nil {
-			return err
-		}
-	}
-
-	// validate cd & cv's availability
-	// if wrong phase, set provisioning condition failed, and jump to plan.Execute()
-	if (cd != nil && cd.Status.Phase != appsv1alpha1.AvailablePhase) || (cv != nil && cv.Status.Phase != appsv1alpha1.AvailablePhase) {
-		message := fmt.Sprintf("ref resource is unavailable, this problem needs to be solved first. cd: %s", cd.Name)
-		if cv != nil {
-			message = fmt.Sprintf("%s, cv: %s", message, cv.Name)
+		cv = &appsv1alpha1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: cluster.Spec.ClusterVersionRef}}
+		if err = intctrlutil.WaitForReferencedObjectAvailable(transCtx.Context, transCtx.Client, cv, 0); err != nil {
+			return newRequeueError(requeueDuration, err.Error())
 		}
-		err = errors.New(message)
-		return newRequeueError(requeueDuration, message)
 	}
 
 	// inject cd & cv into the shared ctx
@@ -149,13 +127,10 @@ func (t *clusterLoadRefResourcesTransformer) loadAndCheckComponentDefinitions(
 		if _, ok := ctx.ComponentDefs[compDefName]; ok {
 			return nil
 		}
-		compDef := &appsv1alpha1.ComponentDefinition{}
-		if err := ctx.Client.Get(ctx.Context, types.NamespacedName{Name: compDefName}, compDef); err != nil {
+		compDef := &appsv1alpha1.ComponentDefinition{ObjectMeta: metav1.ObjectMeta{Name: compDefName}}
+		if err := intctrlutil.WaitForReferencedObjectAvailable(ctx.Context, ctx.Client, compDef, 0); err != nil {
 			return err
 		}
-		if compDef.Status.Phase != appsv1alpha1.AvailablePhase {
-			return fmt.Errorf("the component definition referenced is unavailable: %s", compDefName)
-		}
 		ctx.ComponentDefs[compDefName] = compDef
 		return nil
 	}
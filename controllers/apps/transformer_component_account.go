@@ -21,7 +21,6 @@ package apps
 
 import (
 	"fmt"
-	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -101,7 +100,12 @@ func (t *componentAccountTransformer) buildAccountSecret(ctx *componentTransform
 			return nil, err
 		}
 	default:
-		password = t.buildPassword(ctx, account)
+		var err error
+		if password, err = t.buildPassword(ctx, account); err != nil {
+			ctx.EventRecorder.Eventf(ctx.Component, corev1.EventTypeWarning, SysAcctPasswordGenerationFailed,
+				"failed to generate the password of account %s: %s", account.Name, err.Error())
+			return nil, err
+		}
 	}
 	return t.buildAccountSecretWithPassword(synthesizeComp, account, password), nil
 }
@@ -121,7 +125,7 @@ func (t *componentAccountTransformer) getPasswordFromSecret(ctx graph.TransformC
 	return secret.Data[constant.AccountPasswdForSecret], nil
 }
 
-func (t *componentAccountTransformer) buildPassword(ctx *componentTransformContext, account appsv1alpha1.SystemAccount) []byte {
+func (t *componentAccountTransformer) buildPassword(ctx *componentTransformContext, account appsv1alpha1.SystemAccount) ([]byte, error) {
 	if !account.InitAccount {
 		return t.generatePassword(account)
 	}
@@ -132,19 +136,15 @@ func (t *componentAccountTransformer) buildPassword(ctx *componentTransformConte
 	}
 	e := intctrlutil.NewEncryptor(viper.GetString(constant.CfgKeyDPEncryptionKey))
 	password, _ = e.Decrypt([]byte(password))
-	return []byte(password)
+	return []byte(password), nil
 }
 
-func (t *componentAccountTransformer) generatePassword(account appsv1alpha1.SystemAccount) []byte {
-	config := account.PasswordGenerationPolicy
-	passwd, _ := common.GeneratePassword((int)(config.Length), (int)(config.NumDigits), (int)(config.NumSymbols), false, config.Seed)
-	switch config.LetterCase {
-	case appsv1alpha1.UpperCases:
-		passwd = strings.ToUpper(passwd)
-	case appsv1alpha1.LowerCases:
-		passwd = strings.ToLower(passwd)
+func (t *componentAccountTransformer) generatePassword(account appsv1alpha1.SystemAccount) ([]byte, error) {
+	passwd, err := intctrlutil.GeneratePassword(account.PasswordGenerationPolicy)
+	if err != nil {
+		return nil, err
 	}
-	return []byte(passwd)
+	return []byte(passwd), nil
 }
 
 func (t *componentAccountTransformer) buildAccountSecretWithPassword(synthesizeComp *component.SynthesizedComponent,
@@ -24,6 +24,7 @@ import (
 	"fmt"
 
 	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -70,7 +71,12 @@ func (t *componentLoadResourcesTransformer) Transform(ctx graph.TransformContext
 	if generated {
 		return t.transformForGeneratedComponent(transCtx)
 	}
-	return t.transformForNativeComponent(transCtx)
+
+	compDef := &appsv1alpha1.ComponentDefinition{ObjectMeta: metav1.ObjectMeta{Name: comp.Spec.CompDef}}
+	if err = ictrlutil.WaitForReferencedObjectAvailable(transCtx.Context, transCtx.Client, compDef, 0); err != nil {
+		return newRequeueError(requeueDuration, err.Error())
+	}
+	return t.transformForNativeComponent(transCtx, compDef)
 }
 
 func (t *componentLoadResourcesTransformer) transformForGeneratedComponent(transCtx *componentTransformContext) error {
@@ -92,11 +98,8 @@ func (t *componentLoadResourcesTransformer) transformForGeneratedComponent(trans
 	return nil
 }
 
-func (t *componentLoadResourcesTransformer) transformForNativeComponent(transCtx *componentTransformContext) error {
-	compDef, err := t.getNCheckCompDef(transCtx)
-	if err != nil {
-		return newRequeueError(requeueDuration, err.Error())
-	}
+func (t *componentLoadResourcesTransformer) transformForNativeComponent(transCtx *componentTransformContext,
+	compDef *appsv1alpha1.ComponentDefinition) error {
 	transCtx.CompDef = compDef
 
 	reqCtx := ictrlutil.RequestCtx{
@@ -115,21 +118,6 @@ func (t *componentLoadResourcesTransformer) transformForNativeComponent(transCtx
 	return nil
 }
 
-func (t *componentLoadResourcesTransformer) getNCheckCompDef(transCtx *componentTransformContext) (*appsv1alpha1.ComponentDefinition, error) {
-	compKey := types.NamespacedName{
-		Namespace: transCtx.Component.Namespace,
-		Name:      transCtx.Component.Spec.CompDef,
-	}
-	compDef := &appsv1alpha1.ComponentDefinition{}
-	if err := transCtx.Client.Get(transCtx.Context, compKey, compDef); err != nil {
-		return nil, err
-	}
-	if compDef.Status.Phase != appsv1alpha1.AvailablePhase {
-		return nil, fmt.Errorf("ComponentDefinition referenced is unavailable: %s", compDef.Name)
-	}
-	return compDef, nil
-}
-
 // isGeneratedComponent checks if the component is generated from componentDefRef.
 // TODO: remove the dependency on cluster.Spec
 func isGeneratedComponent(ctx context.Context, cli client.Reader, cluster *appsv1alpha1.Cluster, comp *appsv1alpha1.Component) (bool, error) {
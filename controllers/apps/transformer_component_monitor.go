@@ -0,0 +1,141 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/factory"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+// componentMonitorTransformer handles the ServiceMonitor of component.
+type componentMonitorTransformer struct{}
+
+var _ graph.Transformer = &componentMonitorTransformer{}
+
+func (t *componentMonitorTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+	if common.IsCompactMode(transCtx.ComponentOrig.Annotations) {
+		transCtx.V(1).Info("Component is in compact mode, no need to create monitor related objects", "component", client.ObjectKeyFromObject(transCtx.ComponentOrig))
+		return nil
+	}
+
+	synthesizeComp := transCtx.SynthesizeComponent
+	monitor := synthesizeComp.Monitor
+	apiEnabled := component.IsServiceMonitorAPIEnabled()
+	if monitor != nil && monitor.Enable && !monitor.BuiltIn && monitor.ServiceMonitor {
+		setServiceMonitorCondition(transCtx, apiEnabled)
+	}
+	if !apiEnabled {
+		return nil
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	key := types.NamespacedName{
+		Namespace: synthesizeComp.Namespace,
+		Name:      constant.GenerateClusterComponentName(synthesizeComp.ClusterName, synthesizeComp.Name),
+	}
+	obj, err := t.getServiceMonitor(ctx, key)
+	if err != nil {
+		return err
+	}
+	// don't touch the ServiceMonitor not owned by the component
+	if obj != nil && !model.IsOwnerOf(transCtx.ComponentOrig, obj) {
+		return nil
+	}
+
+	switch {
+	case !monitor.UseServiceMonitor():
+		if obj != nil {
+			graphCli.Delete(dag, obj)
+		}
+	case obj == nil:
+		graphCli.Create(dag, factory.BuildServiceMonitor(synthesizeComp))
+	default:
+		serviceMonitor := factory.BuildServiceMonitor(synthesizeComp)
+		objCopy := obj.DeepCopy()
+		objCopy.Object["spec"] = serviceMonitor.Object["spec"]
+		labels := objCopy.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range serviceMonitor.GetLabels() {
+			labels[k] = v
+		}
+		objCopy.SetLabels(labels)
+		if !reflect.DeepEqual(obj, objCopy) {
+			graphCli.Update(dag, obj, objCopy)
+		}
+	}
+	return nil
+}
+
+func (t *componentMonitorTransformer) getServiceMonitor(ctx graph.TransformContext, key types.NamespacedName) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(constant.MonitoringAPIGroupVersion)
+	obj.SetKind(constant.ServiceMonitorKind)
+	if err := ctx.GetClient().Get(ctx.GetContext(), key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+// setServiceMonitorCondition sets the ServiceMonitorSupported condition of the component requiring the ServiceMonitor,
+// and emits a warning event only if the ServiceMonitor turns to be unsupported, rather than on every reconciliation.
+func setServiceMonitorCondition(transCtx *componentTransformContext, supported bool) {
+	comp := transCtx.Component
+	oldCond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeServiceMonitorSupported)
+	cond := metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeServiceMonitorSupported,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: comp.Generation,
+		Reason:             appsv1alpha1.ReasonServiceMonitorSupported,
+		Message:            "the metrics are exposed by the ServiceMonitor",
+	}
+	if !supported {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = appsv1alpha1.ReasonServiceMonitorUnsupported
+		cond.Message = "the ServiceMonitor CRD of prometheus-operator is not installed, the metrics are exposed by the monitor annotations of the headless service instead"
+		if oldCond == nil || oldCond.Status != metav1.ConditionFalse {
+			transCtx.EventRecorder.Event(comp, corev1.EventTypeWarning, appsv1alpha1.ReasonServiceMonitorUnsupported, cond.Message)
+		}
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, cond)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+func TestComponentMonitorTransformerServiceMonitorUnsupported(t *testing.T) {
+	viper.Set(constant.CfgKeyServiceMonitorAPIEnabled, false)
+
+	recorder := record.NewFakeRecorder(10)
+	comp := &appsv1alpha1.Component{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-mysql", Namespace: "default", Generation: 1}}
+	transCtx := &componentTransformContext{
+		Context:       context.Background(),
+		EventRecorder: recorder,
+		Component:     comp,
+		ComponentOrig: comp.DeepCopy(),
+		SynthesizeComponent: &component.SynthesizedComponent{
+			Namespace:   "default",
+			ClusterName: "test-cluster",
+			Name:        "mysql",
+			Monitor:     &component.MonitorConfig{Enable: true, ServiceMonitor: true},
+		},
+	}
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeServiceMonitorSupported)
+	}
+
+	transformer := &componentMonitorTransformer{}
+	for i := 0; i < 3; i++ {
+		if err := transformer.Transform(transCtx, graph.NewDAG()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	cond := getCondition()
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != appsv1alpha1.ReasonServiceMonitorUnsupported {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+	// the warning event is emitted only once for the repeated reconciliations
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(recorder.Events))
+	}
+	<-recorder.Events
+
+	// the condition turns to be true once the CRD is installed
+	setServiceMonitorCondition(transCtx, true)
+	cond = getCondition()
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != appsv1alpha1.ReasonServiceMonitorSupported {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("unexpected event for the supported ServiceMonitor")
+	}
+
+	// and the event is emitted again if the CRD is uninstalled later
+	setServiceMonitorCondition(transCtx, false)
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(recorder.Events))
+	}
+}
+
+func TestComponentMonitorTransformerServiceMonitorNotRequired(t *testing.T) {
+	viper.Set(constant.CfgKeyServiceMonitorAPIEnabled, false)
+
+	recorder := record.NewFakeRecorder(10)
+	comp := &appsv1alpha1.Component{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-mysql", Namespace: "default"}}
+	transCtx := &componentTransformContext{
+		Context:       context.Background(),
+		EventRecorder: recorder,
+		Component:     comp,
+		ComponentOrig: comp.DeepCopy(),
+		SynthesizeComponent: &component.SynthesizedComponent{
+			Namespace:   "default",
+			ClusterName: "test-cluster",
+			Name:        "mysql",
+			Monitor:     &component.MonitorConfig{Enable: true},
+		},
+	}
+	if err := (&componentMonitorTransformer{}).Transform(transCtx, graph.NewDAG()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeServiceMonitorSupported) != nil {
+		t.Errorf("unexpected condition for the component not requiring the ServiceMonitor")
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("unexpected event for the component not requiring the ServiceMonitor")
+	}
+}
@@ -53,6 +53,17 @@ func (f *componentOwnershipTransformer) Transform(ctx graph.TransformContext, da
 		if _, ok := object.(*corev1.PersistentVolume); ok {
 			continue
 		}
+		// the ServiceMonitor is not cleaned up by the cluster deletion, it's garbage-collected with the component
+		// by the owner reference, without the finalizer.
+		if object.GetObjectKind().GroupVersionKind().Kind == constant.ServiceMonitorKind {
+			if err := controllerutil.SetControllerReference(comp, object, rscheme); err != nil {
+				if _, ok := err.(*controllerutil.AlreadyOwnedError); ok {
+					continue
+				}
+				return err
+			}
+			continue
+		}
 		// if err := intctrlutil.SetOwnership(comp, object, rscheme, constant.DBComponentFinalizerName); err != nil {
 		if err := intctrlutil.SetOwnership(comp, object, rscheme, constant.DBClusterFinalizerName); err != nil {
 			if _, ok := err.(*controllerutil.AlreadyOwnedError); ok {
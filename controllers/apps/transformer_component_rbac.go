@@ -37,7 +37,6 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	ictrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
-	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
@@ -228,22 +227,6 @@ func isRoleBindingExist(transCtx *componentTransformContext, serviceAccountName
 	return true
 }
 
-func getDefaultBackupPolicyTemplate(transCtx *componentTransformContext, clusterDefName string) (*appsv1alpha1.BackupPolicyTemplate, error) {
-	backupPolicyTPLs := &appsv1alpha1.BackupPolicyTemplateList{}
-	if err := transCtx.Client.List(transCtx.Context, backupPolicyTPLs, client.MatchingLabels{constant.ClusterDefLabelKey: clusterDefName}); err != nil {
-		return nil, err
-	}
-	if len(backupPolicyTPLs.Items) == 0 {
-		return nil, nil
-	}
-	for _, item := range backupPolicyTPLs.Items {
-		if item.Annotations[dptypes.DefaultBackupPolicyTemplateAnnotationKey] == trueVal {
-			return &item, nil
-		}
-	}
-	return &backupPolicyTPLs.Items[0], nil
-}
-
 func buildServiceAccount(transCtx *componentTransformContext) (*corev1.ServiceAccount, bool, error) {
 	var (
 		cluster = transCtx.Cluster
@@ -252,7 +235,8 @@ func buildServiceAccount(transCtx *componentTransformContext) (*corev1.ServiceAc
 	)
 
 	// TODO(component): dependency on cluster definition
-	backupPolicyTPL, err := getDefaultBackupPolicyTemplate(transCtx, cluster.Spec.ClusterDefRef)
+	backupPolicyTPL, err := getDefaultBackupPolicyTemplate(transCtx.Context, transCtx.Client, cluster.Spec.ClusterDefRef,
+		compDef.Name, transCtx.SynthesizeComponent.ClusterCompDefName)
 	if err != nil {
 		return nil, false, err
 	}
@@ -249,6 +249,6 @@ func (t *componentServiceTransformer) checkRoleSelector(synthesizeComp *componen
 
 func (t *componentServiceTransformer) skipDefaultHeadlessSvc(synthesizeComp *component.SynthesizedComponent, service *appsv1alpha1.ComponentService) bool {
 	svcName := constant.GenerateComponentServiceName(synthesizeComp.ClusterName, synthesizeComp.Name, service.ServiceName)
-	defaultHeadlessSvcName := constant.GenerateDefaultComponentHeadlessServiceName(synthesizeComp.ClusterName, synthesizeComp.Name)
-	return svcName == defaultHeadlessSvcName
+	// the headless service which governs the pods is created by the workload.
+	return svcName == component.HeadlessServiceName(synthesizeComp)
 }
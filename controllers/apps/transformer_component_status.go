@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"golang.org/x/exp/slices"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -139,6 +140,12 @@ func (r *componentStatusHandler) reconcileComponentStatus() error {
 		return len(pods) > 0
 	}()
 
+	// remove the locked volumes of the pods that have been deleted
+	pruneLockedVolumes(r.comp, pods)
+
+	// record the update strategy applied to the underlying workload
+	r.setWorkloadUpdateStrategy()
+
 	// check if the rsm is running
 	isRSMRunning, err := r.isRSMRunning()
 	if err != nil {
@@ -235,6 +242,34 @@ func (r *componentStatusHandler) reconcileComponentStatus() error {
 	return nil
 }
 
+// setWorkloadUpdateStrategy records the pod management policy and the update strategy applied to the underlying
+// StatefulSet of the component, which are the ones set to the rsm. The StatefulSet is updated with the OnDelete
+// strategy if the pods are updated by the rsm in the order of the roles, e.g. the Consensus components, regardless
+// of the resolved update strategy.
+func (r *componentStatusHandler) setWorkloadUpdateStrategy() {
+	var (
+		policy   appsv1.PodManagementPolicyType
+		strategy *appsv1.StatefulSetUpdateStrategy
+	)
+	if rsm := r.protoRSM; rsm != nil {
+		policy = rsm.Spec.PodManagementPolicy
+		strategy = rsm.Spec.UpdateStrategy.DeepCopy()
+		// the StatefulSet defaults to the rolling update
+		if strategy.Type == "" {
+			strategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+		}
+	} else {
+		if r.synthesizeComp.PodManagementPolicy != nil {
+			policy = *r.synthesizeComp.PodManagementPolicy
+		}
+		if r.synthesizeComp.StsUpdateStrategy != nil {
+			strategy = r.synthesizeComp.StsUpdateStrategy.DeepCopy()
+		}
+	}
+	r.comp.Status.PodManagementPolicy = policy
+	r.comp.Status.UpdateStrategy = strategy
+}
+
 // isComponentAvailable tells whether the component is basically available, ether working well or in a fragile state:
 // 1. at least one pod is available
 // 2. with latest revision
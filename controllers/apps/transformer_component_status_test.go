@@ -0,0 +1,88 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+)
+
+func TestSetWorkloadUpdateStrategy(t *testing.T) {
+	synthesize := func(compDef *appsv1alpha1.ClusterComponentDefinition) *component.SynthesizedComponent {
+		policy, strategy, err := appsv1alpha1.ResolveWorkloadUpdateStrategy(compDef, nil)
+		assert.NoError(t, err)
+		return &component.SynthesizedComponent{PodManagementPolicy: &policy, StsUpdateStrategy: &strategy}
+	}
+	resolve := func(synthesizeComp *component.SynthesizedComponent, rsm *workloads.ReplicatedStateMachine) *appsv1alpha1.ComponentStatus {
+		r := &componentStatusHandler{comp: &appsv1alpha1.Component{}, synthesizeComp: synthesizeComp, protoRSM: rsm}
+		r.setWorkloadUpdateStrategy()
+		return &r.comp.Status
+	}
+
+	// the resolved strategy of the Stateful components is applied to the rsm as it is
+	synthesizeComp := synthesize(&appsv1alpha1.ClusterComponentDefinition{WorkloadType: appsv1alpha1.Stateful})
+	status := resolve(synthesizeComp, &workloads.ReplicatedStateMachine{Spec: workloads.ReplicatedStateMachineSpec{
+		PodManagementPolicy: *synthesizeComp.PodManagementPolicy,
+		UpdateStrategy:      *synthesizeComp.StsUpdateStrategy,
+	}})
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, status.PodManagementPolicy)
+	assert.Equal(t, appsv1.RollingUpdateStatefulSetStrategyType, status.UpdateStrategy.Type)
+	assert.Equal(t, int32(0), *status.UpdateStrategy.RollingUpdate.Partition)
+
+	// the resolved strategy is recorded before the rsm is built
+	status = resolve(synthesizeComp, nil)
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, status.PodManagementPolicy)
+	assert.Equal(t, synthesizeComp.StsUpdateStrategy, status.UpdateStrategy)
+
+	// the llUpdateStrategy of the Consensus components is overridden by OnDelete if the pods are updated by roles,
+	// the strategy actually set to the rsm is recorded.
+	partition := int32(1)
+	consensus := &appsv1alpha1.ClusterComponentDefinition{
+		WorkloadType: appsv1alpha1.Consensus,
+		ConsensusSpec: &appsv1alpha1.ConsensusSetSpec{
+			StatefulSetSpec: appsv1alpha1.StatefulSetSpec{
+				LLPodManagementPolicy: appsv1.ParallelPodManagement,
+				LLUpdateStrategy: &appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+				},
+			},
+		},
+	}
+	serial := workloads.SerialUpdateStrategy
+	status = resolve(synthesize(consensus), &workloads.ReplicatedStateMachine{Spec: workloads.ReplicatedStateMachineSpec{
+		PodManagementPolicy:  appsv1.ParallelPodManagement,
+		MemberUpdateStrategy: &serial,
+		UpdateStrategy:       appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+	}})
+	assert.Equal(t, appsv1.ParallelPodManagement, status.PodManagementPolicy)
+	assert.Equal(t, &appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}, status.UpdateStrategy)
+
+	// the StatefulSet defaults to the rolling update if the rsm sets no update strategy
+	status = resolve(&component.SynthesizedComponent{}, &workloads.ReplicatedStateMachine{Spec: workloads.ReplicatedStateMachineSpec{PodManagementPolicy: appsv1.ParallelPodManagement}})
+	assert.Equal(t, appsv1.ParallelPodManagement, status.PodManagementPolicy)
+	assert.Equal(t, &appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType}, status.UpdateStrategy)
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
 )
 
@@ -46,6 +47,9 @@ func (t *componentValidationTransformer) Transform(ctx graph.TransformContext, d
 	if err = validateCompReplicas(comp, transCtx.CompDef); err != nil {
 		return newRequeueError(requeueDuration, err.Error())
 	}
+	if err = validateCompUpdateStrategyPartition(transCtx.SynthesizeComponent); err != nil {
+		return newRequeueError(requeueDuration, err.Error())
+	}
 	return nil
 }
 
@@ -89,6 +93,15 @@ func validateCompReplicas(comp *appsv1alpha1.Component, compDef *appsv1alpha1.Co
 	return replicasOutOfLimitError(replicas, *replicasLimit)
 }
 
+// validateCompUpdateStrategyPartition validates the partition of the resolved update strategy against the replicas,
+// e.g. the replicas are scaled in below the partition of the `llUpdateStrategy` after the cluster is created.
+func validateCompUpdateStrategyPartition(synthesizeComp *component.SynthesizedComponent) error {
+	if synthesizeComp == nil {
+		return nil
+	}
+	return appsv1alpha1.ValidateUpdateStrategyPartition(synthesizeComp.StsUpdateStrategy, synthesizeComp.Replicas)
+}
+
 func replicasOutOfLimitError(replicas int32, replicasLimit appsv1alpha1.ReplicasLimit) error {
 	return fmt.Errorf("replicas %d out-of-limit [%d, %d]", replicas, replicasLimit.MinReplicas, replicasLimit.MaxReplicas)
 }
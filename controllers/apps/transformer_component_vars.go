@@ -21,13 +21,18 @@ package apps
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/common"
 	"github.com/apecloud/kubeblocks/pkg/constant"
@@ -77,9 +82,18 @@ func (t *componentVarsTransformer) Transform(ctx graph.TransformContext, dag *gr
 
 	// pass all direct value env vars through CM
 	envVars2, envData := buildEnvVarsNData(synthesizedComp, envVars, legacy)
-	setTemplateNEnvVars(synthesizedComp, templateVars, envVars2, legacy)
+	// pass the env vars referring to the connection credential through Secret
+	secretData, err := buildEnvSecretData(transCtx, synthesizedComp)
+	if err != nil {
+		return err
+	}
+	setComponentRefEnvCondition(transCtx, synthesizedComp.IgnoredComponentRefEnvs)
+	setTemplateNEnvVars(synthesizedComp, templateVars, envVars2, legacy, len(secretData) > 0)
 
-	return createOrUpdateEnvConfigMap(ctx, dag, envData)
+	if err = createOrUpdateEnvConfigMap(ctx, dag, envData); err != nil {
+		return err
+	}
+	return createOrUpdateEnvSecret(ctx, dag, secretData)
 }
 
 // generatedComponent4LegacyCluster checks whether the cluster to which this component belongs was created before 0.8.
@@ -133,17 +147,102 @@ func buildEnvVarsNData(synthesizedComp *component.SynthesizedComponent, vars []c
 	return envVars, envData
 }
 
-func setTemplateNEnvVars(synthesizedComp *component.SynthesizedComponent, templateVars map[string]any, envVars []corev1.EnvVar, legacy bool) {
+// buildEnvSecretData resolves the values of the env vars referring to the connection credential, the
+// failure policy of the component ref is applied if the secret or the key is not found.
+func buildEnvSecretData(transCtx *componentTransformContext, synthesizedComp *component.SynthesizedComponent) (map[string]string, error) {
+	envData := make(map[string]string)
+	secrets := make(map[string]*corev1.Secret)
+	for _, env := range synthesizedComp.ComponentRefCredentialEnvs {
+		handleNotFound := func(err error) error {
+			if env.FailurePolicy == appsv1alpha1.FailurePolicyFail {
+				return err
+			}
+			transCtx.Logger.V(1).Info(err.Error())
+			if len(env.Default) > 0 {
+				envData[env.Name] = env.Default
+			}
+			synthesizedComp.IgnoredComponentRefEnvs = append(synthesizedComp.IgnoredComponentRefEnvs,
+				component.ComponentRefEnvResult{Name: env.Name, Value: env.Default, Err: err})
+			return nil
+		}
+		secret, ok := secrets[env.SecretName]
+		if !ok {
+			secret = &corev1.Secret{}
+			secretKey := types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: env.SecretName}
+			if err := transCtx.Client.Get(transCtx.Context, secretKey, secret); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+				secret = nil
+			}
+			secrets[env.SecretName] = secret
+		}
+		if secret == nil {
+			if err := handleNotFound(fmt.Errorf("connection credential secret %s referred by env %s is not found",
+				env.SecretName, env.Name)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, ok := secret.Data[env.Key]
+		if !ok {
+			if err := handleNotFound(fmt.Errorf("key %s referred by env %s is not found in connection credential secret %s",
+				env.Key, env.Name, env.SecretName)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		envData[env.Name] = string(value)
+	}
+	return envData, nil
+}
+
+// setComponentRefEnvCondition sets the ComponentRefEnvResolved condition of the component by the envs of the
+// componentDefRefs ignored by the failure policy, and emits a warning event if the ignored envs are changed.
+// The condition is not added until some env is ignored.
+func setComponentRefEnvCondition(transCtx *componentTransformContext, ignored []component.ComponentRefEnvResult) {
+	comp := transCtx.Component
+	oldCond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypeComponentRefEnvResolved)
+	if len(ignored) == 0 && oldCond == nil {
+		return
+	}
+	cond := metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypeComponentRefEnvResolved,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: comp.Generation,
+		Reason:             appsv1alpha1.ReasonComponentRefEnvResolved,
+		Message:            "all envs of the componentDefRefs are resolved",
+	}
+	if len(ignored) > 0 {
+		failures := make([]string, 0, len(ignored))
+		for _, result := range ignored {
+			failures = append(failures, result.Err.Error())
+		}
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = appsv1alpha1.ReasonComponentRefEnvIgnored
+		cond.Message = fmt.Sprintf("envs are ignored and take their default values: %s", strings.Join(failures, "; "))
+		if oldCond == nil || oldCond.Message != cond.Message {
+			transCtx.EventRecorder.Event(comp, corev1.EventTypeWarning, appsv1alpha1.ReasonComponentRefEnvIgnored, cond.Message)
+		}
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, cond)
+}
+
+func setTemplateNEnvVars(synthesizedComp *component.SynthesizedComponent, templateVars map[string]any, envVars []corev1.EnvVar, legacy, envSecret bool) {
 	envSource := envConfigMapSource(synthesizedComp.ClusterName, synthesizedComp.Name)
 	if legacy {
 		envSource.ConfigMapRef.Optional = nil
 	}
+	envSources := []corev1.EnvFromSource{envSource}
+	if envSecret {
+		envSources = append(envSources, envSecretSource(synthesizedComp.ClusterName, synthesizedComp.Name))
+	}
 
 	synthesizedComp.TemplateVars = templateVars
 	synthesizedComp.EnvVars = envVars
-	synthesizedComp.EnvFromSources = []corev1.EnvFromSource{envSource}
+	synthesizedComp.EnvFromSources = envSources
 
-	component.InjectEnvVars(synthesizedComp, envVars, []corev1.EnvFromSource{envSource})
+	component.InjectEnvVars(synthesizedComp, envVars, envSources)
 }
 
 func envConfigMapSource(clusterName, compName string) corev1.EnvFromSource {
@@ -157,6 +256,17 @@ func envConfigMapSource(clusterName, compName string) corev1.EnvFromSource {
 	}
 }
 
+func envSecretSource(clusterName, compName string) corev1.EnvFromSource {
+	return corev1.EnvFromSource{
+		SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: constant.GenerateClusterComponentEnvSecretPattern(clusterName, compName),
+			},
+			Optional: func() *bool { optional := false; return &optional }(),
+		},
+	}
+}
+
 func createOrUpdateEnvConfigMap(ctx graph.TransformContext, dag *graph.DAG, data map[string]string) error {
 	var (
 		transCtx, _     = ctx.(*componentTransformContext)
@@ -179,7 +289,8 @@ func createOrUpdateEnvConfigMap(ctx graph.TransformContext, dag *graph.DAG, data
 			SetData(data).
 			GetObject()
 		graphCli.Create(dag, obj)
-	} else if !reflect.DeepEqual(envObj.Data, data) {
+	} else if !reflect.DeepEqual(envObj.Data, data) || isOrphanEnvObject(transCtx, envObj) {
+		// the orphan env object is updated to be adopted by the component, its ownership is set by the ownership transformer.
 		envObjCopy := envObj.DeepCopy()
 		envObjCopy.Data = data
 		graphCli.Update(dag, envObj, envObjCopy)
@@ -187,6 +298,65 @@ func createOrUpdateEnvConfigMap(ctx graph.TransformContext, dag *graph.DAG, data
 	return nil
 }
 
+// isOrphanEnvObject checks whether the env object of the component is not controlled by any object, such as the one
+// created without the owner reference or left behind by a deleted cluster with the same name.
+// The env object is identified by the well-known labels of the component, other objects are never adopted.
+func isOrphanEnvObject(transCtx *componentTransformContext, obj client.Object) bool {
+	synthesizedComp := transCtx.SynthesizeComponent
+	labels := obj.GetLabels()
+	for k, v := range constant.GetComponentWellKnownLabels(synthesizedComp.ClusterName, synthesizedComp.Name) {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return metav1.GetControllerOf(obj) == nil
+}
+
+// createOrUpdateEnvSecret creates or updates the secret holding the sensitive env vars, the secret
+// is deleted if there are no such env vars any more.
+func createOrUpdateEnvSecret(ctx graph.TransformContext, dag *graph.DAG, data map[string]string) error {
+	var (
+		transCtx, _     = ctx.(*componentTransformContext)
+		synthesizedComp = transCtx.SynthesizeComponent
+		envKey          = types.NamespacedName{
+			Namespace: synthesizedComp.Namespace,
+			Name:      constant.GenerateClusterComponentEnvSecretPattern(synthesizedComp.ClusterName, synthesizedComp.Name),
+		}
+	)
+	envObj := &corev1.Secret{}
+	err := transCtx.Client.Get(transCtx.Context, envKey, envObj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	switch {
+	case err != nil: // not-found
+		if len(data) == 0 {
+			return nil
+		}
+		obj := builder.NewSecretBuilder(envKey.Namespace, envKey.Name).
+			AddLabelsInMap(constant.GetComponentWellKnownLabels(synthesizedComp.ClusterName, synthesizedComp.Name)).
+			SetStringData(data).
+			GetObject()
+		graphCli.Create(dag, obj)
+	case len(data) == 0:
+		graphCli.Delete(dag, envObj)
+	default:
+		envData := make(map[string][]byte, len(data))
+		for k, v := range data {
+			envData[k] = []byte(v)
+		}
+		if !reflect.DeepEqual(envObj.Data, envData) || isOrphanEnvObject(transCtx, envObj) {
+			envObjCopy := envObj.DeepCopy()
+			envObjCopy.Data = envData
+			envObjCopy.StringData = nil
+			graphCli.Update(dag, envObj, envObjCopy)
+		}
+	}
+	return nil
+}
+
 type varsReader struct {
 	cli      client.Reader
 	graphCli model.GraphClient
@@ -0,0 +1,274 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+func TestSetComponentRefEnvCondition(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	transCtx := &componentTransformContext{
+		EventRecorder: recorder,
+		Component:     &appsv1alpha1.Component{ObjectMeta: metav1.ObjectMeta{Name: "mysql", Generation: 1}},
+	}
+	getCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(transCtx.Component.Status.Conditions, appsv1alpha1.ConditionTypeComponentRefEnvResolved)
+	}
+
+	// the condition is not added until some env is ignored
+	setComponentRefEnvCondition(transCtx, nil)
+	if getCondition() != nil {
+		t.Fatal("unexpected condition without ignored envs")
+	}
+
+	ignored := []component.ComponentRefEnvResult{
+		{Name: "HOST", Err: errors.New("failed to resolve env HOST: not found")},
+		{Name: "PORT", Value: "3306", Err: errors.New("failed to resolve env PORT: not found")},
+	}
+	setComponentRefEnvCondition(transCtx, ignored)
+	cond := getCondition()
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != appsv1alpha1.ReasonComponentRefEnvIgnored {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+	for _, name := range []string{"HOST", "PORT"} {
+		if !strings.Contains(cond.Message, "env "+name) {
+			t.Errorf("expected env %s in the condition message: %s", name, cond.Message)
+		}
+	}
+	// one aggregated warning event is emitted for all ignored envs
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(recorder.Events))
+	}
+	<-recorder.Events
+
+	// the event is not emitted again if the ignored envs are not changed
+	setComponentRefEnvCondition(transCtx, ignored)
+	if len(recorder.Events) != 0 {
+		t.Errorf("unexpected event for the unchanged ignored envs")
+	}
+
+	setComponentRefEnvCondition(transCtx, nil)
+	cond = getCondition()
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != appsv1alpha1.ReasonComponentRefEnvResolved {
+		t.Fatalf("unexpected condition: %v", cond)
+	}
+}
+
+func TestBuildEnvSecretData(t *testing.T) {
+	const (
+		namespace  = "default"
+		secretName = "mycluster-conn-credential"
+	)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secretName},
+		Data:       map[string][]byte{"password": []byte("passw0rd")},
+	}
+	newTransCtx := func(getErr error, getCount *int) *componentTransformContext {
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, cli client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					*getCount++
+					if getErr != nil {
+						return getErr
+					}
+					return cli.Get(ctx, key, obj, opts...)
+				},
+			}).Build()
+		return &componentTransformContext{Context: context.Background(), Client: cli, Logger: logr.Discard()}
+	}
+	newEnv := func(name, secretName, key string, policy appsv1alpha1.FailurePolicyType) component.ComponentRefCredentialEnv {
+		return component.ComponentRefCredentialEnv{Name: name, SecretName: secretName, Key: key, FailurePolicy: policy}
+	}
+
+	t.Run("ignore the missing secret and key", func(t *testing.T) {
+		getCount := 0
+		transCtx := newTransCtx(nil, &getCount)
+		userEnv := newEnv("USER", secretName, "username", appsv1alpha1.FailurePolicyIgnore)
+		userEnv.Default = "root"
+		synthesizedComp := &component.SynthesizedComponent{
+			Namespace: namespace,
+			ComponentRefCredentialEnvs: []component.ComponentRefCredentialEnv{
+				newEnv("PASSWORD", secretName, "password", appsv1alpha1.FailurePolicyIgnore),
+				userEnv,
+				newEnv("HOST", secretName, "host", appsv1alpha1.FailurePolicyIgnore),
+				newEnv("OTHER_PASSWORD", "non-existent", "password", appsv1alpha1.FailurePolicyIgnore),
+				newEnv("OTHER_USER", "non-existent", "username", appsv1alpha1.FailurePolicyIgnore),
+			},
+		}
+		data, err := buildEnvSecretData(transCtx, synthesizedComp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := map[string]string{"PASSWORD": "passw0rd", "USER": "root"}
+		if !reflect.DeepEqual(data, expected) {
+			t.Errorf("expected %v, got %v", expected, data)
+		}
+		if len(synthesizedComp.IgnoredComponentRefEnvs) != 4 {
+			t.Errorf("expected 4 ignored envs, got %v", synthesizedComp.IgnoredComponentRefEnvs)
+		}
+		// each secret is fetched only once, even if it is not found
+		if getCount != 2 {
+			t.Errorf("expected 2 gets of the secrets, got %d", getCount)
+		}
+	})
+
+	t.Run("fail on the missing secret and key", func(t *testing.T) {
+		for _, env := range []component.ComponentRefCredentialEnv{
+			newEnv("HOST", secretName, "host", appsv1alpha1.FailurePolicyFail),
+			newEnv("OTHER_PASSWORD", "non-existent", "password", appsv1alpha1.FailurePolicyFail),
+		} {
+			getCount := 0
+			synthesizedComp := &component.SynthesizedComponent{
+				Namespace:                  namespace,
+				ComponentRefCredentialEnvs: []component.ComponentRefCredentialEnv{env},
+			}
+			_, err := buildEnvSecretData(newTransCtx(nil, &getCount), synthesizedComp)
+			if err == nil || !strings.Contains(err.Error(), env.Name) {
+				t.Errorf("expected an error about env %s, got %v", env.Name, err)
+			}
+			if len(synthesizedComp.IgnoredComponentRefEnvs) != 0 {
+				t.Errorf("unexpected ignored envs: %v", synthesizedComp.IgnoredComponentRefEnvs)
+			}
+		}
+	})
+
+	t.Run("return the error other than not-found regardless of the failure policy", func(t *testing.T) {
+		getCount := 0
+		synthesizedComp := &component.SynthesizedComponent{
+			Namespace: namespace,
+			ComponentRefCredentialEnvs: []component.ComponentRefCredentialEnv{
+				newEnv("PASSWORD", secretName, "password", appsv1alpha1.FailurePolicyIgnore),
+			},
+		}
+		getErr := errors.New("connection refused")
+		if _, err := buildEnvSecretData(newTransCtx(getErr, &getCount), synthesizedComp); !errors.Is(err, getErr) {
+			t.Errorf("expected error %v, got %v", getErr, err)
+		}
+	})
+
+	t.Run("no credential envs", func(t *testing.T) {
+		getCount := 0
+		data, err := buildEnvSecretData(newTransCtx(nil, &getCount), &component.SynthesizedComponent{Namespace: namespace})
+		if err != nil || len(data) != 0 || getCount != 0 {
+			t.Errorf("unexpected result: %v, %v, %d gets", data, err, getCount)
+		}
+	})
+}
+
+func TestCreateOrUpdateEnvSecret(t *testing.T) {
+	const (
+		namespace   = "default"
+		clusterName = "mycluster"
+		compName    = "mysql"
+	)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	_ = workloads.AddToScheme(scheme)
+	cluster := &appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: clusterName}}
+	secretName := constant.GenerateClusterComponentEnvSecretPattern(clusterName, compName)
+	newSecret := func(data map[string]string) *corev1.Secret {
+		secret := builder.NewSecretBuilder(namespace, secretName).
+			AddLabelsInMap(constant.GetComponentWellKnownLabels(clusterName, compName)).
+			GetObject()
+		secret.Data = map[string][]byte{}
+		for k, v := range data {
+			secret.Data[k] = []byte(v)
+		}
+		isController := true
+		secret.OwnerReferences = []metav1.OwnerReference{{Kind: "Component", Name: compName, Controller: &isController}}
+		return secret
+	}
+	run := func(existing *corev1.Secret, data map[string]string) (model.GraphClient, *graph.DAG) {
+		cliBuilder := fake.NewClientBuilder().WithScheme(scheme)
+		if existing != nil {
+			cliBuilder.WithObjects(existing)
+		}
+		graphCli := model.NewGraphClient(cliBuilder.Build())
+		transCtx := &componentTransformContext{
+			Context: context.Background(),
+			Client:  graphCli,
+			Logger:  logr.Discard(),
+			SynthesizeComponent: &component.SynthesizedComponent{
+				Namespace:   namespace,
+				ClusterName: clusterName,
+				Name:        compName,
+			},
+		}
+		dag := mockDAG(graphCli, cluster)
+		if err := createOrUpdateEnvSecret(transCtx, dag, data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return graphCli, dag
+	}
+	secretKey := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secretName}}
+
+	// no secret is created without credential envs
+	graphCli, dag := run(nil, nil)
+	if graphCli.IsAction(dag, secretKey, model.ActionCreatePtr()) {
+		t.Errorf("unexpected creation of the env secret without data")
+	}
+
+	graphCli, dag = run(nil, map[string]string{"PASSWORD": "passw0rd"})
+	if !graphCli.IsAction(dag, secretKey, model.ActionCreatePtr()) {
+		t.Errorf("expected the env secret to be created")
+	}
+
+	graphCli, dag = run(newSecret(map[string]string{"PASSWORD": "passw0rd"}), map[string]string{"PASSWORD": "passw0rd"})
+	if graphCli.IsAction(dag, secretKey, model.ActionUpdatePtr()) {
+		t.Errorf("unexpected update of the unchanged env secret")
+	}
+
+	graphCli, dag = run(newSecret(map[string]string{"PASSWORD": "passw0rd"}), map[string]string{"PASSWORD": "new-passw0rd"})
+	if !graphCli.IsAction(dag, secretKey, model.ActionUpdatePtr()) {
+		t.Errorf("expected the env secret to be updated")
+	}
+
+	// the secret is deleted once there are no credential envs any more
+	graphCli, dag = run(newSecret(map[string]string{"PASSWORD": "passw0rd"}), nil)
+	if !graphCli.IsAction(dag, secretKey, model.ActionDeletePtr()) {
+		t.Errorf("expected the env secret to be deleted")
+	}
+}
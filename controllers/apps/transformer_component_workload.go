@@ -111,6 +111,14 @@ func (t *componentWorkloadTransformer) Transform(ctx graph.TransformContext, dag
 	if err != nil {
 		return err
 	}
+	// apply the podSpecPatch of the component after the envs and volumes are injected
+	if protoRSM != nil && synthesizeComp.PodSpecPatch != nil {
+		podSpec, err := component.ApplyPodSpecPatch(&protoRSM.Spec.Template.Spec, synthesizeComp.PodSpecPatch)
+		if err != nil {
+			return err
+		}
+		protoRSM.Spec.Template.Spec = *podSpec
+	}
 	if runningRSM != nil {
 		*protoRSM.Spec.Selector = *runningRSM.Spec.Selector
 		protoRSM.Spec.Template.Labels = runningRSM.Spec.Template.Labels
@@ -120,6 +128,9 @@ func (t *componentWorkloadTransformer) Transform(ctx graph.TransformContext, dag
 	// build configuration template annotations to rsm workload
 	buildRSMConfigTplAnnotations(protoRSM, synthesizeComp)
 
+	// handle the scheduled restart, the delayed requeue error is returned after the workload is handled
+	errRestart := handleScheduledRestart(transCtx, runningRSM, protoRSM)
+
 	graphCli, _ := transCtx.Client.(model.GraphClient)
 	if runningRSM == nil {
 		if protoRSM != nil {
@@ -133,7 +144,10 @@ func (t *componentWorkloadTransformer) Transform(ctx graph.TransformContext, dag
 			err = t.handleUpdate(reqCtx, graphCli, dag, cluster, synthesizeComp, runningRSM, protoRSM)
 		}
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return errRestart
 }
 
 func (t *componentWorkloadTransformer) runningRSMObject(ctx graph.TransformContext,
@@ -312,12 +326,14 @@ func copyAndMergeRSM(oldRsm, newRsm *workloads.ReplicatedStateMachine, synthesiz
 	mergeMetadataMap(rsmObjCopy.Spec.Template.Annotations, &rsmProto.Spec.Template.Annotations)
 	rsmObjCopy.Spec.Template = *rsmProto.Spec.Template.DeepCopy()
 	rsmObjCopy.Spec.Replicas = rsmProto.Spec.Replicas
+	rsmObjCopy.Spec.MaxUnavailable = rsmProto.Spec.MaxUnavailable
 	rsmObjCopy.Spec.Service = updateService(rsmObjCopy, rsmProto)
 	rsmObjCopy.Spec.AlternativeServices = rsmProto.Spec.AlternativeServices
 	rsmObjCopy.Spec.Roles = rsmProto.Spec.Roles
 	rsmObjCopy.Spec.RoleProbe = rsmProto.Spec.RoleProbe
 	rsmObjCopy.Spec.MembershipReconfiguration = rsmProto.Spec.MembershipReconfiguration
 	rsmObjCopy.Spec.MemberUpdateStrategy = rsmProto.Spec.MemberUpdateStrategy
+	rsmObjCopy.Spec.LeaderEvictionPolicy = rsmProto.Spec.LeaderEvictionPolicy
 	rsmObjCopy.Spec.Credential = rsmProto.Spec.Credential
 	rsmObjCopy.Spec.NodeAssignment = rsmProto.Spec.NodeAssignment
 
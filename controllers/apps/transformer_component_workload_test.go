@@ -23,11 +23,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apiserver/pkg/storage/names"
 
 	"github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
 )
 
 const (
@@ -170,4 +173,22 @@ var _ = Describe("transformer component workload", func() {
 			Expect(len(nodeAssignment)).Should(Equal(5))
 		})
 	})
+
+	Context("Test copyAndMergeRSM", func() {
+		It("should update the maxUnavailable of the existing RSM", func() {
+			oldMaxUnavailable := intstr.FromInt(1)
+			newMaxUnavailable := intstr.FromString("50%")
+			oldRsm := &v1alpha1.ReplicatedStateMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Spec:       v1alpha1.ReplicatedStateMachineSpec{MaxUnavailable: &oldMaxUnavailable},
+			}
+			newRsm := &v1alpha1.ReplicatedStateMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Spec:       v1alpha1.ReplicatedStateMachineSpec{MaxUnavailable: &newMaxUnavailable},
+			}
+			rsm := copyAndMergeRSM(oldRsm, newRsm, &component.SynthesizedComponent{})
+			Expect(rsm).ShouldNot(BeNil())
+			Expect(rsm.Spec.MaxUnavailable).Should(Equal(&newMaxUnavailable))
+		})
+	})
 })
@@ -22,26 +22,41 @@ package dataprotection
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	vsv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v3/apis/volumesnapshot/v1beta1"
 	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -52,6 +67,9 @@ import (
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
 	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
+	dperrors "github.com/apecloud/kubeblocks/pkg/dataprotection/errors"
+	dpmetrics "github.com/apecloud/kubeblocks/pkg/dataprotection/metrics"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/tracing"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
@@ -65,6 +83,9 @@ type BackupReconciler struct {
 	Recorder   record.EventRecorder
 	RestConfig *rest.Config
 	clock      clock.RealClock
+
+	// verificationThrottle records the backups whose verification waits for the in-progress backups.
+	verificationThrottle verificationThrottle
 }
 
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;create;update;patch;delete
@@ -78,7 +99,9 @@ type BackupReconciler struct {
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotclasses/finalizers,verbs=update;patch
 
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the backup closer to the desired state.
@@ -99,17 +122,36 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	reqCtx.Log.V(1).Info("reconcile", "backup", req.NamespacedName, "phase", backup.Status.Phase)
 
+	// the terminal phase is observed from the cache, the metrics of it won't be recorded again.
+	switch backup.Status.Phase {
+	case dpv1alpha1.BackupPhaseCompleted, dpv1alpha1.BackupPhaseFailed, dpv1alpha1.BackupPhaseDeleting:
+		dpmetrics.ForgetBackup(backup)
+	}
+
+	var span trace.Span
+	reqCtx.Ctx, span = tracing.StartBackupSpan(reqCtx.Ctx, "Backup.Reconcile", backup)
+	defer span.End()
+
 	// if backup is being deleted, set backup phase to Deleting. The backup
 	// reference workloads, data and volume snapshots will be deleted by controller
 	// later when the backup status.phase is deleting.
 	if !backup.GetDeletionTimestamp().IsZero() && backup.Status.Phase != dpv1alpha1.BackupPhaseDeleting {
 		patch := client.MergeFrom(backup.DeepCopy())
 		backup.Status.Phase = dpv1alpha1.BackupPhaseDeleting
-		if err := r.Client.Status().Patch(reqCtx.Ctx, backup, patch); err != nil {
+		if err := r.patchStatus(reqCtx.Ctx, backup, patch); err != nil {
 			return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 		}
 	}
 
+	// adopt the volume snapshots created by older versions in all phases, they may lack the labels
+	// and owner references, and would be leaked after the backup is deleted. The volume snapshots
+	// of the deleting backup are looked up by their names instead.
+	if backup.Status.Phase != dpv1alpha1.BackupPhaseDeleting {
+		if err := r.adoptVolumeSnapshots(reqCtx, backup); err != nil {
+			return RecorderEventAndRequeue(reqCtx, r.Recorder, backup, err)
+		}
+	}
+
 	switch backup.Status.Phase {
 	case "", dpv1alpha1.BackupPhaseNew:
 		return r.handleNewPhase(reqCtx, backup)
@@ -131,6 +173,12 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *BackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	for _, obj := range []client.Object{&rbacv1.RoleBinding{}, &rbacv1.ClusterRoleBinding{}} {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), obj,
+			serviceAccountSubjectIndexKey, indexServiceAccountSubjects); err != nil {
+			return err
+		}
+	}
 	b := intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&dpv1alpha1.Backup{}).
 		WithOptions(controller.Options{
@@ -139,7 +187,10 @@ func (r *BackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{}).
 		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.filterBackupPods)).
-		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.parseBackupJob))
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.parseBackupJob)).
+		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.parseParentBackup)).
+		Watches(&dpv1alpha1.Backup{}, r.verificationThrottleHandler()).
+		Watches(&dpv1alpha1.Restore{}, handler.EnqueueRequestsFromMapFunc(r.parseRestore))
 
 	if dputils.SupportsVolumeSnapshotV1() {
 		b.Owns(&vsv1.VolumeSnapshot{}, builder.Predicates{})
@@ -189,13 +240,50 @@ func (r *BackupReconciler) parseBackupJob(_ context.Context, object client.Objec
 	return requests
 }
 
+// parseRestore enqueues the source backup of the restore, so that the deletion of the backup can
+// proceed once the restores from it are finished.
+func (r *BackupReconciler) parseRestore(_ context.Context, object client.Object) []reconcile.Request {
+	labels := object.GetLabels()
+	backupName := labels[dptypes.BackupNameLabelKey]
+	backupNamespace := labels[dptypes.BackupNamespaceLabelKey]
+	if backupName == "" || backupNamespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: backupNamespace,
+			Name:      backupName,
+		},
+	}}
+}
+
+// parseParentBackup enqueues the parent backup of the incremental backup, so that the deletion of
+// the parent backup can proceed once its dependent backups are deleted.
+func (r *BackupReconciler) parseParentBackup(_ context.Context, object client.Object) []reconcile.Request {
+	backup := object.(*dpv1alpha1.Backup)
+	parentBackupName := dputils.GetParentBackupName(backup)
+	if parentBackupName == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: backup.Namespace,
+			Name:      parentBackupName,
+		},
+	}}
+}
+
 // deleteBackupFiles deletes the backup files stored in backup repository.
 func (r *BackupReconciler) deleteBackupFiles(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) error {
 	deleteBackup := func() error {
 		// remove backup finalizers to delete it
 		patch := client.MergeFrom(backup.DeepCopy())
 		controllerutil.RemoveFinalizer(backup, dptypes.DataProtectionFinalizerName)
-		return r.Patch(reqCtx.Ctx, backup, patch)
+		if err := r.Patch(reqCtx.Ctx, backup, patch); err != nil {
+			return err
+		}
+		dpmetrics.RecordBackupDeleted(backup)
+		return nil
 	}
 
 	deleter := &dpbackup.Deleter{
@@ -204,13 +292,23 @@ func (r *BackupReconciler) deleteBackupFiles(reqCtx intctrlutil.RequestCtx, back
 		Scheme:     r.Scheme,
 	}
 
-	saName, err := EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace)
+	// the backup policy may have been deleted, the worker service account managed
+	// by the controller is used in this case.
+	backupPolicy, err := dputils.GetBackupPolicyByName(reqCtx, r.Client, backup.Spec.BackupPolicyName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	saName, err := getWorkerServiceAccount(reqCtx, r.Client, backupPolicy, backup.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get worker service account: %w", err)
 	}
 	deleter.WorkerServiceAccount = saName
 
-	status, err := deleter.DeleteBackupFiles(backup)
+	// delete the copies of the backup first, the backup is removed once its files are deleted.
+	status, err := deleter.DeleteBackupCopies(backup)
+	if status == dpbackup.DeletionStatusSucceeded {
+		status, err = deleter.DeleteBackupFiles(backup)
+	}
 	switch status {
 	case dpbackup.DeletionStatusSucceeded:
 		return deleteBackup()
@@ -222,7 +320,7 @@ func (r *BackupReconciler) deleteBackupFiles(reqCtx intctrlutil.RequestCtx, back
 		backupPatch := client.MergeFrom(backup.DeepCopy())
 		backup.Status.FailureReason = failureReason
 		r.Recorder.Event(backup, corev1.EventTypeWarning, "DeleteBackupFilesFailed", failureReason)
-		return r.Status().Patch(reqCtx.Ctx, backup, backupPatch)
+		return r.patchStatus(reqCtx.Ctx, backup, backupPatch)
 	case dpbackup.DeletionStatusDeleting,
 		dpbackup.DeletionStatusUnknown:
 		// wait for the deletion job completed
@@ -236,32 +334,100 @@ func (r *BackupReconciler) deleteBackupFiles(reqCtx intctrlutil.RequestCtx, back
 func (r *BackupReconciler) handleDeletingPhase(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (ctrl.Result, error) {
 	// if backup phase is Deleting, delete the backup reference workloads,
 	// backup data stored in backup repository and volume snapshots.
-	// TODO(ldm): if backup is being used by restore, do not delete it.
 	if err := r.deleteExternalResources(reqCtx, backup); err != nil {
 		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 	}
 
+	// the dry-run backup does not write anything to the backup repository.
+	if backup.Spec.DryRun {
+		return intctrlutil.Reconciled()
+	}
+
 	if backup.Spec.DeletionPolicy == dpv1alpha1.BackupDeletionPolicyRetain {
 		r.Recorder.Event(backup, corev1.EventTypeWarning, "Retain", "can not delete the backup if deletionPolicy is Retain")
 		return intctrlutil.Reconciled()
 	}
 
+	// keep the backup files until the incremental backups depending on it are deleted, the backup
+	// will be reconciled again when they are deleted.
+	if backup.Annotations[dptypes.ForceDeleteAnnotationKey] != trueVal {
+		dependentBackupNames, err := dputils.GetDependentBackupNames(reqCtx.Ctx, r.Client, backup)
+		if err != nil {
+			return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+		}
+		if len(dependentBackupNames) > 0 {
+			r.Recorder.Eventf(backup, corev1.EventTypeWarning, "DependentBackupsExist",
+				"can not delete the backup which has dependent incremental backups: %s, set the annotation %s to true to force the deletion",
+				strings.Join(dependentBackupNames, ","), dptypes.ForceDeleteAnnotationKey)
+			return intctrlutil.Reconciled()
+		}
+		// keep the backup files until the restores from it are finished, the backup will be
+		// reconciled again when they are updated.
+		restoreNames, err := dputils.GetInProgressRestoreNames(reqCtx.Ctx, r.Client, backup)
+		if err != nil {
+			return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+		}
+		if len(restoreNames) > 0 {
+			r.Recorder.Eventf(backup, corev1.EventTypeWarning, "RestoresInProgress",
+				"can not delete the backup which is being restored by: %s, set the annotation %s to true to force the deletion",
+				strings.Join(restoreNames, ","), dptypes.ForceDeleteAnnotationKey)
+			return intctrlutil.Reconciled()
+		}
+	}
+
+	// keep the backup files until the deletion grace period has passed.
+	if remaining := getDeletionGracePeriodRemaining(backup, r.clock.Now()); remaining > 0 {
+		r.Recorder.Eventf(backup, corev1.EventTypeNormal, "WaitForDeletionGracePeriod",
+			"the backup files will be deleted after %s", remaining.Round(time.Second))
+		return intctrlutil.RequeueAfter(remaining, reqCtx.Log, "wait for the deletion grace period")
+	}
+
 	if err := r.deleteVolumeSnapshots(reqCtx, backup); err != nil {
 		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 	}
 
 	if err := r.deleteBackupFiles(reqCtx, backup); err != nil {
+		// the deletion job concurrency limit is reached, wait for a free slot.
+		if re, ok := err.(intctrlutil.RequeueError); ok {
+			return intctrlutil.RequeueAfter(re.RequeueAfter(), reqCtx.Log, re.Reason())
+		}
 		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 	}
 	return intctrlutil.Reconciled()
 }
 
+// getDeletionGracePeriodRemaining returns the remaining duration of the deletion grace period,
+// a non-positive value means the backup files can be deleted immediately.
+func getDeletionGracePeriodRemaining(backup *dpv1alpha1.Backup, now time.Time) time.Duration {
+	if backup.Spec.DeletionGracePeriod == nil || backup.GetDeletionTimestamp().IsZero() {
+		return 0
+	}
+	if backup.Annotations[dptypes.SkipDeletionGracePeriodAnnotationKey] == trueVal {
+		return 0
+	}
+	return backup.GetDeletionTimestamp().Add(backup.Spec.DeletionGracePeriod.Duration).Sub(now)
+}
+
 func (r *BackupReconciler) handleNewPhase(
 	reqCtx intctrlutil.RequestCtx,
 	backup *dpv1alpha1.Backup) (ctrl.Result, error) {
 	request, err := r.prepareBackupRequest(reqCtx, backup)
 	if err != nil {
-		return r.updateStatusIfFailed(reqCtx, backup.DeepCopy(), backup, err)
+		// wait for the backup repo to be ready, or it has failed over to the fallback backup repo.
+		if re, ok := err.(intctrlutil.RequeueError); ok {
+			return intctrlutil.RequeueAfter(re.RequeueAfter(), reqCtx.Log, re.Reason())
+		}
+		original := backup.DeepCopy()
+		if backup.Spec.DryRun {
+			setDryRunCondition(backup, err, "")
+		}
+		return r.updateStatusIfFailed(reqCtx, original, backup, err)
+	}
+
+	// the dry-run backup only validates the prerequisites, it does not patch
+	// the backup object meta and does not run any backup actions.
+	if backup.Spec.DryRun {
+		return r.handleDryRun(reqCtx, backup, request)
 	}
 
 	// set and patch backup object meta, including labels, annotations and finalizers
@@ -269,6 +435,12 @@ func (r *BackupReconciler) handleNewPhase(
 	if wait, err := PatchBackupObjectMeta(backup, request); err != nil {
 		return r.updateStatusIfFailed(reqCtx, backup, request.Backup, err)
 	} else if wait {
+		if err = r.checkBackupRepoFailover(request, nil); err != nil {
+			if re, ok := err.(intctrlutil.RequeueError); ok {
+				return intctrlutil.RequeueAfter(re.RequeueAfter(), reqCtx.Log, re.Reason())
+			}
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
 		return intctrlutil.Reconciled()
 	}
 
@@ -279,9 +451,84 @@ func (r *BackupReconciler) handleNewPhase(
 	return intctrlutil.Reconciled()
 }
 
+// checkBackupRepoFailover checks whether the backup should fail over to the next fallback
+// backup repo because the current one is not ready. It returns a RequeueError if the backup
+// should wait for the current backup repo or has failed over, otherwise it returns the cause.
+func (r *BackupReconciler) checkBackupRepoFailover(request *dpbackup.Request, cause error) error {
+	nextRepoName := getNextBackupRepoName(request)
+	if nextRepoName == "" {
+		return cause
+	}
+	currRepoName := request.BackupRepo.Name
+	if remaining := getBackupRepoFailoverRemaining(request.Backup, request.BackupPolicy, r.clock.Now()); remaining > 0 {
+		return intctrlutil.NewRequeueError(remaining, fmt.Sprintf("wait for backup repo %s to be ready", currRepoName))
+	}
+
+	original := request.Backup.DeepCopy()
+	request.Labels[dataProtectionBackupRepoKey] = nextRepoName
+	delete(request.Labels, dataProtectionWaitRepoPreparationKey)
+	request.Annotations[dataProtectionBackupRepoSelectedAtAnnotationKey] = r.clock.Now().UTC().Format(time.RFC3339)
+	if err := r.Client.Patch(request.Ctx, request.Backup, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(request.Backup, corev1.EventTypeWarning, "BackupRepoFailover",
+		"backup repo %s is not ready within %s, fail over to backup repo %s",
+		currRepoName, getBackupRepoFailoverTimeout(request.BackupPolicy), nextRepoName)
+	return intctrlutil.NewRequeueError(reconcileInterval, fmt.Sprintf("fail over to backup repo %s", nextRepoName))
+}
+
+// getNextBackupRepoName returns the name of the fallback backup repo following the current
+// one, an empty string means there is no backup repo to fail over to.
+func getNextBackupRepoName(request *dpbackup.Request) string {
+	if request.Spec.DryRun || request.BackupRepo == nil || request.BackupPolicy == nil {
+		return ""
+	}
+	fallbackRepoNames := request.BackupPolicy.Spec.FallbackBackupRepoNames
+	next := 0
+	for i, name := range fallbackRepoNames {
+		if name == request.BackupRepo.Name {
+			next = i + 1
+			break
+		}
+	}
+	if next >= len(fallbackRepoNames) {
+		return ""
+	}
+	return fallbackRepoNames[next]
+}
+
+func getBackupRepoFailoverTimeout(backupPolicy *dpv1alpha1.BackupPolicy) time.Duration {
+	if backupPolicy.Spec.BackupRepoFailoverTimeout != nil {
+		return backupPolicy.Spec.BackupRepoFailoverTimeout.Duration
+	}
+	return defaultBackupRepoFailoverTimeout
+}
+
+// getBackupRepoFailoverRemaining returns the remaining duration to wait for the current backup
+// repo before failing over, it is counted from the time the backup repo was selected.
+func getBackupRepoFailoverRemaining(backup *dpv1alpha1.Backup,
+	backupPolicy *dpv1alpha1.BackupPolicy, now time.Time) time.Duration {
+	selectedAt := backup.CreationTimestamp.Time
+	if val := backup.Annotations[dataProtectionBackupRepoSelectedAtAnnotationKey]; val != "" {
+		if t, err := time.Parse(time.RFC3339, val); err == nil {
+			selectedAt = t
+		}
+	}
+	return selectedAt.Add(getBackupRepoFailoverTimeout(backupPolicy)).Sub(now)
+}
+
 // prepareBackupRequest prepares a request for a backup, with all references to
 // other kubernetes objects, and validate them.
 func (r *BackupReconciler) prepareBackupRequest(
+	reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (*dpbackup.Request, error) {
+	_, span := tracing.StartBackupSpan(reqCtx.Ctx, "Backup.PrepareRequest", backup)
+	request, err := r.buildBackupRequest(reqCtx, backup)
+	tracing.EndSpan(span, err)
+	return request, err
+}
+
+func (r *BackupReconciler) buildBackupRequest(
 	reqCtx intctrlutil.RequestCtx,
 	backup *dpv1alpha1.Backup) (*dpbackup.Request, error) {
 	request := &dpbackup.Request{
@@ -327,53 +574,445 @@ func (r *BackupReconciler) prepareBackupRequest(
 	}
 
 	// check encryption config
-	if backupPolicy.Spec.EncryptionConfig != nil {
-		secretKeyRef := backupPolicy.Spec.EncryptionConfig.PassPhraseSecretKeyRef
+	encryptionConfig, err := getBackupEncryptionConfig(backup, backupPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if encryptionConfig != nil {
+		secretKeyRef := encryptionConfig.PassPhraseSecretKeyRef
 		if secretKeyRef == nil {
-			return nil, fmt.Errorf("encryptionConfig.passPhraseSecretKeyRef if empty")
+			return nil, dperrors.NewEncryptionKeyMissing("encryptionConfig.passPhraseSecretKeyRef is empty")
 		}
 		err := checkSecretKeyRef(reqCtx, r.Client, request.Namespace, secretKeyRef)
 		if err != nil {
-			return nil, fmt.Errorf("failed to check encryption key reference: %w", err)
+			return nil, dperrors.NewEncryptionKeyMissing(err.Error())
 		}
 	}
+	request.EncryptionConfig = encryptionConfig
 
 	request.BackupPolicy = backupPolicy
+	started := backup.Status.Phase == dpv1alpha1.BackupPhaseRunning
 	if !snapshotVolumes {
 		// if use volume snapshot, ignore backup repo
 		if err = HandleBackupRepo(request); err != nil {
+			if intctrlutil.IsTargetError(err, dperrors.ErrorTypeBackupRepoIsNotReady) {
+				return nil, r.checkBackupRepoFailover(request, err)
+			}
+			return nil, err
+		}
+		// pause the new backup until the backup repo becomes writable.
+		if !started {
+			if request.BackupRepo.Spec.ReadOnly {
+				return nil, r.pauseForReadOnlyBackupRepo(reqCtx, backup, request.BackupRepo.Name)
+			}
+			if err = r.resumeFromReadOnlyBackupRepo(reqCtx, backup, request); err != nil {
+				return nil, err
+			}
+		}
+		// reject the new backup if the quota of the backup repo is exceeded.
+		if err = checkBackupRepoQuota(reqCtx.Ctx, r.Client, request.BackupRepo, backup.Namespace); err != nil {
 			return nil, err
 		}
+		// fail fast if the backup repo has no room for the new backup, rather than
+		// failing after the backup workloads have run out of space.
+		if !started {
+			if err = checkBackupRepoFreeSpace(reqCtx.Ctx, r.Client, request); err != nil {
+				return nil, err
+			}
+		}
 	}
 	request.BackupMethod = backupMethod
 
+	if request.GetBackupType() == string(dpv1alpha1.BackupTypeIncremental) {
+		if request.ParentBackup, err = r.getParentBackup(reqCtx, backup); err != nil {
+			return nil, err
+		}
+		// the incremental backup should be stored in the same backup repo as its parent backup.
+		if request.BackupRepo != nil && request.ParentBackup.Status.BackupRepoName != request.BackupRepo.Name {
+			return nil, fmt.Errorf("parent backup %s is stored in backup repo %s, but the backup repo is %s",
+				request.ParentBackup.Name, request.ParentBackup.Status.BackupRepoName, request.BackupRepo.Name)
+		}
+	}
+
+	// the readiness of the target pods is only validated before the backup starts, and it is not
+	// required by the volume snapshot which does not exec into the target pod.
+	allowNotReady := backup.Spec.AllowNotReadyTarget || snapshotVolumes || started
 	targetPods, err := GetTargetPods(reqCtx, r.Client,
-		backup.Annotations[dptypes.BackupTargetPodLabelKey], backupMethod, backupPolicy)
-	if err != nil || len(targetPods) == 0 {
-		return nil, fmt.Errorf("failed to get target pods by backup policy %s/%s",
-			backupPolicy.Namespace, backupPolicy.Name)
+		backup.Annotations[dptypes.BackupTargetPodLabelKey], backupMethod, backupPolicy, allowNotReady)
+	if err != nil {
+		return nil, err
+	}
+	if len(targetPods) == 0 {
+		return nil, dperrors.NewNoTargetPods(backupPolicy.Namespace, backupPolicy.Name)
 	}
 	request.TargetPods = targetPods
+	if !started {
+		setTargetReadyCondition(request.Backup, targetPods)
+		if err = checkTargetContainer(backupMethod, targetPods); err != nil {
+			return nil, err
+		}
+		if backupMethod.TargetVolumes != nil {
+			if err = dputils.ValidateVolumeSubPaths(backupMethod.TargetVolumes.VolumeSubPaths); err != nil {
+				return nil, intctrlutil.NewFatalError(err.Error())
+			}
+		}
+		// fail fast if the pinned volume snapshot class can't take the snapshots, the snapshots hang otherwise.
+		if snapshotVolumes {
+			if err = checkVolumeSnapshotClass(reqCtx.Ctx, r.Client, request); err != nil {
+				return nil, err
+			}
+		}
+		// the backup data must not overwrite the data of other backups in the same backup repo.
+		if request.BackupRepo != nil {
+			if err = r.checkBackupPathCollision(reqCtx, request); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	saName := backupPolicy.Spec.Target.ServiceAccountName
-	if saName == "" {
-		saName, err = EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace)
+	if backupPolicy.Spec.WorkerServiceAccountName != "" {
+		if saName, err = getWorkerServiceAccount(reqCtx, r.Client, backupPolicy, backup.Namespace); err != nil {
+			return nil, err
+		}
+	} else if saName == "" && !backup.Spec.DryRun {
+		// do not create the worker service account for the dry-run backup. The role of the backup policy
+		// is scoped to its namespace, the workers of the cross-namespace backup use the shared worker
+		// service account, which is bound to the worker cluster role in the target namespace.
+		if targetPods[0].Namespace != backup.Namespace {
+			saName, err = EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace)
+		} else {
+			saName, err = ensureBackupWorkerServiceAccount(reqCtx, r.Client, request)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to get worker service account: %w", err)
 		}
 	}
 	request.WorkerServiceAccount = saName
+	if request.HasExecActions() && !backup.Spec.DryRun {
+		if err = ensureExecWorkerRoleBinding(reqCtx, r.Client, targetPods[0].Namespace); err != nil {
+			return nil, fmt.Errorf("failed to ensure exec worker role binding: %w", err)
+		}
+	}
+
+	// the backup workloads run in the namespace of the backup, which is different
+	// from the namespace of the target pods for the cross-namespace backup.
+	if targetNamespace := targetPods[0].Namespace; targetNamespace != backup.Namespace {
+		if snapshotVolumes {
+			return nil, fmt.Errorf("backup method %s takes volume snapshots which is not supported for the target namespace %s",
+				backupMethod.Name, targetNamespace)
+		}
+		if !backup.Spec.DryRun {
+			if err = r.prepareCrossNamespaceTarget(reqCtx, request, targetNamespace,
+				backupPolicy.Spec.WorkerServiceAccountName == "" && backupPolicy.Spec.Target.ServiceAccountName == ""); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	return request, nil
 }
 
+// prepareCrossNamespaceTarget prepares the resources in the namespace of the backup to back up the target pods
+// in another namespace. The connection credential secret is copied into the namespace of the backup and owned
+// by the backup, and the worker service account managed by the controller is bound to the worker cluster role
+// in the namespace of the target pods.
+func (r *BackupReconciler) prepareCrossNamespaceTarget(reqCtx intctrlutil.RequestCtx,
+	request *dpbackup.Request, targetNamespace string, managedWorkerServiceAccount bool) error {
+	if managedWorkerServiceAccount {
+		if err := ensureWorkerRoleBinding(reqCtx, r.Client, targetNamespace,
+			request.Namespace, request.WorkerServiceAccount); err != nil {
+			return fmt.Errorf("failed to create rolebinding in namespace %s: %w", targetNamespace, err)
+		}
+	}
+
+	credential := request.BackupPolicy.Spec.Target.ConnectionCredential
+	if credential == nil {
+		return nil
+	}
+	source := &corev1.Secret{}
+	if err := r.Client.Get(reqCtx.Ctx, client.ObjectKey{Namespace: targetNamespace, Name: credential.SecretName}, source); err != nil {
+		return fmt.Errorf("failed to get connection credential secret %s/%s: %w", targetNamespace, credential.SecretName, err)
+	}
+	secret := &corev1.Secret{}
+	secret.Namespace = request.Namespace
+	secret.Name = dpbackup.GenerateCrossNamespaceCredentialSecretName(request.Backup)
+	shouldUpdate := func() bool {
+		return !reflect.DeepEqual(secret.Data, source.Data)
+	}
+	if _, err := createOrUpdateObject(reqCtx.Ctx, r.Client, secret, func() error {
+		if !secret.CreationTimestamp.IsZero() && !metav1.IsControlledBy(secret, request.Backup) {
+			return fmt.Errorf("secret %s/%s already exists and is not owned by the backup", secret.Namespace, secret.Name)
+		}
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[dptypes.BackupNameLabelKey] = request.Name
+		secret.Type = source.Type
+		secret.Data = source.Data
+		return controllerutil.SetControllerReference(request.Backup, secret, r.Scheme)
+	}, shouldUpdate); err != nil {
+		return err
+	}
+	request.ConnectionCredential = credential.DeepCopy()
+	request.ConnectionCredential.SecretName = secret.Name
+	return nil
+}
+
+// checkTargetContainer checks whether the target container of the backup method exists in the target pods.
+func checkTargetContainer(backupMethod *dpv1alpha1.BackupMethod, targetPods []*corev1.Pod) error {
+	if backupMethod.TargetContainerName == "" {
+		return nil
+	}
+	for _, pod := range targetPods {
+		if _, c := intctrlutil.GetContainerByName(pod.Spec.Containers, backupMethod.TargetContainerName); c == nil {
+			return dperrors.NewTargetContainerNotFound(backupMethod.TargetContainerName, pod.Namespace, pod.Name)
+		}
+	}
+	return nil
+}
+
+// checkVolumeSnapshotClass checks whether the VolumeSnapshotClass pinned by the backup exists, and
+// its driver matches the provisioner of the target volumes.
+func checkVolumeSnapshotClass(ctx context.Context, cli client.Client, request *dpbackup.Request) error {
+	className := request.GetVolumeSnapshotClassName()
+	if className == "" || request.BackupMethod.TargetVolumes == nil {
+		return nil
+	}
+	vsc, err := dputils.GetVolumeSnapshotClass(ctx, cli, className)
+	if err != nil {
+		return err
+	}
+	if vsc == nil {
+		return dperrors.NewSnapshotClassMismatch(`the VolumeSnapshotClass "%s" is not found`, className)
+	}
+	for _, pod := range request.TargetPods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil || !slices.Contains(request.BackupMethod.TargetVolumes.Volumes, volume.Name) {
+				continue
+			}
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err = cli.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}, pvc); err != nil {
+				return err
+			}
+			provisioner, err := dputils.GetPVCProvisioner(ctx, cli, pvc)
+			if err != nil {
+				return err
+			}
+			if provisioner != vsc.Driver {
+				return dperrors.NewSnapshotClassMismatch(`the driver "%s" of the VolumeSnapshotClass "%s" doesn't match the provisioner "%s" of the persistentVolumeClaim "%s/%s"`,
+					vsc.Driver, className, provisioner, pvc.Namespace, pvc.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// setTargetReadyCondition records a TargetReady condition if the backup is taken from the
+// target pods which are not ready.
+func setTargetReadyCondition(backup *dpv1alpha1.Backup, targetPods []*corev1.Pod) {
+	notReadyPods := getNotReadyPods(targetPods)
+	if len(notReadyPods) == 0 {
+		return
+	}
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeTargetReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonTargetPodNotReady,
+		Message:            fmt.Sprintf("the backup is taken from the target pods %v which are not ready", notReadyPods),
+		ObservedGeneration: backup.Generation,
+	})
+}
+
+// getParentBackup gets the parent backup of the incremental backup. If the parent backup is not
+// specified, the latest completed full backup of the same backup policy is used.
+func (r *BackupReconciler) getParentBackup(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (*dpv1alpha1.Backup, error) {
+	parentBackupName := dputils.GetParentBackupName(backup)
+	if parentBackupName == "" {
+		parentBackup, err := dputils.GetLatestFullBackup(reqCtx.Ctx, r.Client, backup.Spec.BackupPolicyName, backup.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if parentBackup == nil {
+			return nil, fmt.Errorf("no completed full backup of backup policy %s is found as the parent backup",
+				backup.Spec.BackupPolicyName)
+		}
+		return parentBackup, nil
+	}
+
+	parentBackup := &dpv1alpha1.Backup{}
+	if err := r.Client.Get(reqCtx.Ctx, client.ObjectKey{Name: parentBackupName, Namespace: backup.Namespace}, parentBackup); err != nil {
+		return nil, fmt.Errorf("failed to get parent backup %s: %w", parentBackupName, err)
+	}
+	if parentBackup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return nil, fmt.Errorf("parent backup %s is not completed", parentBackupName)
+	}
+	if parentBackup.IsDryRun() {
+		return nil, fmt.Errorf("parent backup %s is a dry run", parentBackupName)
+	}
+	if parentBackup.Spec.BackupPolicyName != backup.Spec.BackupPolicyName {
+		return nil, fmt.Errorf("parent backup %s does not belong to backup policy %s", parentBackupName, backup.Spec.BackupPolicyName)
+	}
+	return parentBackup, nil
+}
+
+// handleDryRun validates the backup actions that would be executed, and marks the
+// dry-run backup as completed with a condition summarizing these actions.
+func (r *BackupReconciler) handleDryRun(
+	reqCtx intctrlutil.RequestCtx,
+	original *dpv1alpha1.Backup,
+	request *dpbackup.Request) (ctrl.Result, error) {
+	dryRunFailed := func(err error) (ctrl.Result, error) {
+		setDryRunCondition(request.Backup, err, "")
+		return r.updateStatusIfFailed(reqCtx, original, request.Backup, err)
+	}
+
+	if pvc := request.BackupRepoPVC; pvc != nil && pvc.Status.Phase != corev1.ClaimBound {
+		return dryRunFailed(fmt.Errorf("the PVC %s of backup repo %s is not bound", pvc.Name, request.BackupRepo.Name))
+	}
+
+	actions, err := request.BuildActions()
+	if err != nil {
+		return dryRunFailed(err)
+	}
+	actionNames := make([]string, len(actions))
+	for i, act := range actions {
+		actionNames[i] = act.GetName()
+	}
+
+	request.Status.Target = request.BackupPolicy.Spec.Target
+	// record the namespace of the target pods for the cross-namespace backup.
+	if targetNamespace := request.TargetPods[0].Namespace; targetNamespace != request.Namespace && request.Status.Target != nil {
+		request.Status.Target = request.Status.Target.DeepCopy()
+		request.Status.Target.Namespace = targetNamespace
+	}
+	request.Status.BackupMethod = request.BackupMethod
+	request.Status.TargetPods = make([]string, len(request.TargetPods))
+	for i, pod := range request.TargetPods {
+		request.Status.TargetPods[i] = pod.Name
+	}
+	if request.BackupRepo != nil {
+		request.Status.BackupRepoName = request.BackupRepo.Name
+	}
+	now := &metav1.Time{Time: r.clock.Now().UTC()}
+	request.Status.StartTimestamp = now
+	request.Status.CompletionTimestamp = now
+	request.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	request.Status.CompletionReason = dpv1alpha1.BackupCompletionReasonDryRun
+
+	msg := fmt.Sprintf("the backup would execute actions [%s] on target pods [%s] with backup method %s",
+		strings.Join(actionNames, ","), strings.Join(request.Status.TargetPods, ","), request.BackupMethod.Name)
+	setDryRunCondition(request.Backup, nil, msg)
+	r.Recorder.Event(original, corev1.EventTypeNormal, ReasonDryRunPassed, msg)
+	if err = r.patchStatus(reqCtx.Ctx, request.Backup, client.MergeFrom(original)); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
+
+// setDryRunCondition sets the dry-run condition of the backup, the condition status
+// is False and the message is the error if err is not nil.
+func setDryRunCondition(backup *dpv1alpha1.Backup, err error, msg string) {
+	cond := metav1.Condition{
+		Type:               ConditionTypeDryRunPassed,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonDryRunPassed,
+		Message:            msg,
+		ObservedGeneration: backup.Generation,
+	}
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = ReasonDryRunFailed
+		cond.Message = err.Error()
+	}
+	meta.SetStatusCondition(&backup.Status.Conditions, cond)
+}
+
+// runPostBackupHooksOnFailure runs the post-backup hook actions after the action at failedIndex failed,
+// so the barrier set by the pre-backup hook is released even if the backup fails. The hooks are not run if
+// the failed action is a backup hook itself, since the barrier is not set or is being released.
+// It returns true if some post-backup hook action is still running.
+func (r *BackupReconciler) runPostBackupHooksOnFailure(
+	actionCtx action.ActionContext,
+	request *dpbackup.Request,
+	actions []action.Action,
+	failedIndex int) (bool, error) {
+	failedName := actions[failedIndex].GetName()
+	if dpbackup.IsPreBackupHookAction(failedName) || dpbackup.IsPostBackupHookAction(failedName) {
+		return false, nil
+	}
+	running := false
+	for i := failedIndex + 1; i < len(actions); i++ {
+		act := actions[i]
+		if !dpbackup.IsPostBackupHookAction(act.GetName()) {
+			continue
+		}
+		status, err := act.Execute(actionCtx)
+		if err != nil {
+			return false, err
+		}
+		request.Status.Actions[i] = mergeActionStatus(&request.Status.Actions[i], status)
+		switch status.Phase {
+		case dpv1alpha1.ActionPhaseFailed:
+			if setPostBackupHookFailedCondition(request.Backup, act.GetName(), status.FailureReason) {
+				r.Recorder.Eventf(request.Backup, corev1.EventTypeWarning, ReasonPostBackupHookFailed,
+					"post-backup hook %s failed, %s", act.GetName(), status.FailureReason)
+			}
+		case dpv1alpha1.ActionPhaseNew, dpv1alpha1.ActionPhaseRunning:
+			running = true
+		}
+	}
+	return running, nil
+}
+
+// setPostBackupHookFailedCondition sets the condition of the backup to indicate the post-backup hook failed,
+// it returns false if the condition of the hook has been set.
+func setPostBackupHookFailedCondition(backup *dpv1alpha1.Backup, actionName, failureReason string) bool {
+	msg := fmt.Sprintf("post-backup hook %s failed, %s", actionName, failureReason)
+	cond := meta.FindStatusCondition(backup.Status.Conditions, ConditionTypePostBackupHookSucceeded)
+	if cond != nil && strings.Contains(cond.Message, msg) {
+		return false
+	}
+	if cond != nil && cond.Status == metav1.ConditionFalse {
+		// multiple hooks may fail if there are multiple target pods.
+		msg = cond.Message + "; " + msg
+	}
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePostBackupHookSucceeded,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonPostBackupHookFailed,
+		Message:            msg,
+		ObservedGeneration: backup.Generation,
+	})
+	return true
+}
+
 func (r *BackupReconciler) patchBackupStatus(
 	original *dpv1alpha1.Backup,
 	request *dpbackup.Request) error {
 	request.Status.FormatVersion = dpbackup.FormatVersion
 	request.Status.Path = dpbackup.BuildBackupPath(request.Backup, request.BackupPolicy.Spec.PathPrefix)
 	request.Status.Target = request.BackupPolicy.Spec.Target
+	// record the namespace of the target pods for the cross-namespace backup.
+	if targetNamespace := request.TargetPods[0].Namespace; targetNamespace != request.Namespace && request.Status.Target != nil {
+		request.Status.Target = request.Status.Target.DeepCopy()
+		request.Status.Target.Namespace = targetNamespace
+	}
 	request.Status.BackupMethod = request.BackupMethod
+	// record the ActionSet to check whether it has changed when restoring from the backup.
+	if request.ActionSet != nil {
+		actionSetHash, err := dputils.ComputeActionSetHash(request.ActionSet)
+		if err != nil {
+			return err
+		}
+		request.Status.ActionSetName = request.ActionSet.Name
+		request.Status.ActionSetGeneration = request.ActionSet.Generation
+		request.Status.ActionSetHash = actionSetHash
+	}
+	request.Status.TargetPods = make([]string, len(request.TargetPods))
+	for i, pod := range request.TargetPods {
+		request.Status.TargetPods[i] = pod.Name
+	}
 	if request.BackupRepo != nil {
 		request.Status.BackupRepoName = request.BackupRepo.Name
 	}
@@ -383,21 +1022,25 @@ func (r *BackupReconciler) patchBackupStatus(
 	if request.BackupPolicy.Spec.UseKopia {
 		request.Status.KopiaRepoPath = dpbackup.BuildKopiaRepoPath(request.Backup, request.BackupPolicy.Spec.PathPrefix)
 	}
-	if request.BackupPolicy.Spec.EncryptionConfig != nil {
-		request.Status.EncryptionConfig = request.BackupPolicy.Spec.EncryptionConfig
+	if request.EncryptionConfig != nil {
+		// record the exact key reference, the backup must be decrypted with it even if the key is rotated.
+		request.Status.EncryptionConfig = request.EncryptionConfig.DeepCopy()
+	}
+	if request.ParentBackup != nil {
+		request.Status.ParentBackupName = request.ParentBackup.Name
+		// the base backup is the full backup at the root of the backup chain.
+		request.Status.BaseBackupUID = request.ParentBackup.Status.BaseBackupUID
+		if request.Status.BaseBackupUID == "" {
+			request.Status.BaseBackupUID = request.ParentBackup.UID
+		}
 	}
 	// init action status
 	actions, err := request.BuildActions()
 	if err != nil {
 		return err
 	}
-	request.Status.Actions = make([]dpv1alpha1.ActionStatus, len(actions))
-	for i, act := range actions {
-		request.Status.Actions[i] = dpv1alpha1.ActionStatus{
-			Name:       act.GetName(),
-			Phase:      dpv1alpha1.ActionPhaseNew,
-			ActionType: act.Type(),
-		}
+	if request.Status.Actions, err = request.BuildActionStatuses(actions, r.Scheme); err != nil {
+		return err
 	}
 
 	// update phase to running
@@ -407,7 +1050,7 @@ func (r *BackupReconciler) patchBackupStatus(
 	if err = dpbackup.SetExpirationByCreationTime(request.Backup); err != nil {
 		return err
 	}
-	return r.Client.Status().Patch(request.Ctx, request.Backup, client.MergeFrom(original))
+	return r.patchStatus(request.Ctx, request.Backup, client.MergeFrom(original))
 }
 
 func (r *BackupReconciler) handleRunningPhase(
@@ -415,9 +1058,25 @@ func (r *BackupReconciler) handleRunningPhase(
 	backup *dpv1alpha1.Backup) (ctrl.Result, error) {
 	request, err := r.prepareBackupRequest(reqCtx, backup)
 	if err != nil {
-		return r.updateStatusIfFailed(reqCtx, backup.DeepCopy(), backup, err)
+		original := backup.DeepCopy()
+		if backup.Spec.DryRun {
+			setDryRunCondition(backup, err, "")
+		}
+		return r.updateStatusIfFailed(reqCtx, original, backup, err)
+	}
+
+	// the dry-run backup only validates the prerequisites, it does not patch
+	// the backup object meta and does not run any backup actions.
+	if backup.Spec.DryRun {
+		return r.handleDryRun(reqCtx, backup, request)
 	}
 
+	var (
+		deadlineRemaining time.Duration
+		hasDeadline       bool
+		lagCheckAfter     time.Duration
+		trimCheckAfter    time.Duration
+	)
 	if request.ActionSet != nil && request.ActionSet.Spec.BackupType == dpv1alpha1.BackupTypeContinuous {
 		// check if the continuous backup is completed.
 		if completed, err := r.checkIsCompletedDuringRunning(reqCtx, request); err != nil {
@@ -425,6 +1084,23 @@ func (r *BackupReconciler) handleRunningPhase(
 		} else if completed {
 			return intctrlutil.Reconciled()
 		}
+		r.setPausedCondition(request.Backup)
+		// trim the data out of the retention period, the backup workload is scaled
+		// down while trimming.
+		if trimCheckAfter, err = r.trimContinuousBackup(reqCtx, request); err != nil {
+			return RecorderEventAndRequeue(reqCtx, r.Recorder, backup, err)
+		}
+		// update the replication lag of the continuous backup, the lag is not
+		// checked while the backup is paused or trimmed.
+		if !request.Spec.Paused && request.Annotations[dptypes.TrimBeforeAnnotationKey] == "" {
+			lagCheckAfter = r.updateContinuousSyncStatus(request)
+		}
+	} else {
+		// check if the backup has exceeded its completion deadline, continuous backup is exempt.
+		deadlineRemaining, hasDeadline = getCompletionDeadlineRemaining(request.Backup, r.clock.Now())
+		if hasDeadline && deadlineRemaining <= 0 {
+			return r.handleCompletionDeadlineExceeded(reqCtx, backup, request)
+		}
 	}
 
 	// there are actions not completed, continue to handle following actions
@@ -433,6 +1109,15 @@ func (r *BackupReconciler) handleRunningPhase(
 		return r.updateStatusIfFailed(reqCtx, backup, request.Backup, err)
 	}
 
+	// the action statuses may be lost, e.g. the status patch failed before the controller
+	// restarted, reconstruct them from the existing jobs to avoid creating duplicate jobs.
+	if len(request.Status.Actions) != len(actions) {
+		reqCtx.Log.Info("reconstruct the action statuses", "expected", len(actions), "actual", len(request.Status.Actions))
+		if request.Status.Actions, err = request.BuildActionStatuses(actions, r.Scheme); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+
 	actionCtx := action.ActionContext{
 		Ctx:              reqCtx.Ctx,
 		Client:           r.Client,
@@ -445,7 +1130,19 @@ func (r *BackupReconciler) handleRunningPhase(
 	// if all actions completed, update backup status to completed, otherwise,
 	// continue to handle following actions.
 	for i, act := range actions {
+		// the failed action is waiting for the backoff before it is retried.
+		if nextRetry := request.Status.Actions[i].NextRetryTimestamp; nextRetry != nil {
+			if retryAfter := nextRetry.Sub(r.clock.Now()); retryAfter > 0 {
+				if hasDeadline && deadlineRemaining < retryAfter {
+					retryAfter = deadlineRemaining
+				}
+				return intctrlutil.RequeueAfter(retryAfter, reqCtx.Log, "wait for retrying the failed action", "action", act.GetName())
+			}
+		}
+		var span trace.Span
+		actionCtx.Ctx, span = tracing.StartActionSpan(reqCtx.Ctx, request.Backup, act.GetName())
 		status, err := act.Execute(actionCtx)
+		tracing.EndSpan(span, err)
 		if err != nil {
 			return r.updateStatusIfFailed(reqCtx, backup, request.Backup, err)
 		}
@@ -456,19 +1153,64 @@ func (r *BackupReconciler) handleRunningPhase(
 			updateBackupStatusByActionStatus(&request.Status)
 			continue
 		case dpv1alpha1.ActionPhaseFailed:
+			if dpbackup.IsPostBackupHookAction(act.GetName()) {
+				// the backup data has been copied, do not fail the backup if the post-backup hook failed.
+				if setPostBackupHookFailedCondition(request.Backup, act.GetName(), status.FailureReason) {
+					r.Recorder.Eventf(request.Backup, corev1.EventTypeWarning, ReasonPostBackupHookFailed,
+						"post-backup hook %s failed, %s", act.GetName(), status.FailureReason)
+				}
+				continue
+			}
+			retryAfter, retrying, err := r.retryFailedAction(actionCtx, request, act, &request.Status.Actions[i])
+			if err != nil {
+				return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+			}
+			if retrying {
+				if err = r.patchStatus(reqCtx.Ctx, request.Backup, client.MergeFrom(backup)); err != nil {
+					return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+				}
+				return intctrlutil.RequeueAfter(retryAfter, reqCtx.Log, "wait for retrying the failed action", "action", act.GetName())
+			}
+			// release the barrier set by the pre-backup hook before failing the backup.
+			releasing, err := r.runPostBackupHooksOnFailure(actionCtx, request, actions, i)
+			if err != nil {
+				return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+			}
+			if releasing {
+				if err = r.patchStatus(reqCtx.Ctx, request.Backup, client.MergeFrom(backup)); err != nil {
+					return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+				}
+				return intctrlutil.Reconciled()
+			}
 			return r.updateStatusIfFailed(reqCtx, backup, request.Backup,
-				fmt.Errorf("action %s failed, %s", act.GetName(), status.FailureReason))
+				dperrors.NewBackupActionFailed(act.GetName(), request.Status.Actions[i].FailureReason))
 		case dpv1alpha1.ActionPhaseRunning:
 			// update status
-			if err = r.Client.Status().Patch(reqCtx.Ctx, request.Backup, client.MergeFrom(backup)); err != nil {
+			updateBackupProgress(&request.Status)
+			if err = r.patchStatus(reqCtx.Ctx, request.Backup, client.MergeFrom(backup)); err != nil {
 				return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 			}
+			if hasDeadline {
+				// requeue to make sure the deadline can be checked even if no other event triggers reconciliation.
+				return intctrlutil.RequeueAfter(deadlineRemaining, reqCtx.Log, "wait for the completion deadline")
+			}
+			// requeue to make sure the replication lag can be checked even if the continuous backup stops syncing,
+			// and the data out of the retention period can be trimmed in time.
+			checkAfter := lagCheckAfter
+			if trimCheckAfter > 0 && (checkAfter == 0 || trimCheckAfter < checkAfter) {
+				checkAfter = trimCheckAfter
+			}
+			if checkAfter > 0 {
+				return intctrlutil.RequeueAfter(checkAfter, reqCtx.Log, "wait for the next check of the continuous backup")
+			}
 			return intctrlutil.Reconciled()
 		}
 	}
 
 	// all actions completed, update backup status to completed
 	request.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	request.Status.CompletionReason = dpv1alpha1.BackupCompletionReasonActionsCompleted
+	request.Status.Progress = 100
 	request.Status.CompletionTimestamp = &metav1.Time{Time: r.clock.Now().UTC()}
 	if !request.Status.StartTimestamp.IsZero() {
 		// round the duration to a multiple of seconds.
@@ -487,19 +1229,284 @@ func (r *BackupReconciler) handleRunningPhase(
 			}
 		}
 	}
-	r.Recorder.Event(backup, corev1.EventTypeNormal, "CreatedBackup", "Completed backup")
-	if err = r.Client.Status().Patch(reqCtx.Ctx, request.Backup, client.MergeFrom(backup)); err != nil {
+	request.Status.Copies = buildBackupCopies(request)
+	r.Recorder.Event(backup, corev1.EventTypeNormal, "CreatedBackup",
+		fmt.Sprintf("Completed backup, target pods: %s", strings.Join(request.Status.TargetPods, ",")))
+	if err = r.patchStatus(reqCtx.Ctx, request.Backup, client.MergeFrom(backup)); err != nil {
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 	}
+	dpmetrics.RecordBackupCompleted(request.Backup)
 	return intctrlutil.Reconciled()
 }
 
-// checkIsCompletedDuringRunning when continuous schedule is disabled or cluster has been deleted,
-// backup phase should be Completed.
-func (r *BackupReconciler) checkIsCompletedDuringRunning(reqCtx intctrlutil.RequestCtx,
-	request *dpbackup.Request) (bool, error) {
-	backupScheduleList := &dpv1alpha1.BackupScheduleList{}
-	if err := r.Client.List(reqCtx.Ctx, backupScheduleList, client.MatchingLabels{
+// getCompletionDeadlineRemaining returns the remaining duration before the backup exceeds
+// its completion deadline, the second return value is false if the deadline is not set.
+func getCompletionDeadlineRemaining(backup *dpv1alpha1.Backup, now time.Time) (time.Duration, bool) {
+	if backup.Spec.CompletionDeadline == nil || backup.Status.StartTimestamp.IsZero() {
+		return 0, false
+	}
+	deadline := backup.Status.StartTimestamp.Add(backup.Spec.CompletionDeadline.Duration)
+	return deadline.Sub(now), true
+}
+
+// handleCompletionDeadlineExceeded deletes the backup jobs and marks the backup as failed
+// when the backup is not completed within the completion deadline.
+func (r *BackupReconciler) handleCompletionDeadlineExceeded(reqCtx intctrlutil.RequestCtx,
+	original *dpv1alpha1.Backup,
+	request *dpbackup.Request) (ctrl.Result, error) {
+	if err := r.deleteExternalJobs(reqCtx, request.Backup); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	err := intctrlutil.NewErrorf(intctrlutil.ErrorTypeDeadlineExceeded,
+		"backup was not completed within the completion deadline %s", request.Spec.CompletionDeadline.Duration)
+	return r.updateStatusIfFailed(reqCtx, original, request.Backup, err)
+}
+
+// updateContinuousSyncStatus updates the lastSyncTime and latestReplicationLag of the continuous backup
+// according to the time range published by the backup workload, and emits a warning event if the lag
+// exceeds the threshold of the backup policy. It returns the duration after which the lag should be
+// checked again, zero means no further check is required.
+func (r *BackupReconciler) updateContinuousSyncStatus(request *dpbackup.Request) time.Duration {
+	timeRange := request.Status.TimeRange
+	if timeRange == nil || timeRange.End.IsZero() {
+		return 0
+	}
+	lag := r.clock.Now().Sub(timeRange.End.Time).Round(time.Second)
+	if lag < 0 {
+		lag = 0
+	}
+	request.Status.LastSyncTime = timeRange.End.DeepCopy()
+	request.Status.LatestReplicationLag = &metav1.Duration{Duration: lag}
+
+	threshold := request.BackupPolicy.Spec.ReplicationLagThreshold
+	if threshold == nil || threshold.Duration <= 0 {
+		return 0
+	}
+	if lag <= threshold.Duration {
+		return threshold.Duration - lag
+	}
+	r.Recorder.Eventf(request.Backup, corev1.EventTypeWarning, "ReplicationLagExceeded",
+		"the replication lag %s of continuous backup exceeds the threshold %s, last sync time: %s",
+		lag, threshold.Duration, timeRange.End.UTC().Format(time.RFC3339))
+	return threshold.Duration
+}
+
+// trimContinuousBackup trims the data of the continuous backup which is out of the retention period
+// by the trim action of the ActionSet. The trim job never runs concurrently with the backup workload:
+// the TrimBeforeAnnotationKey annotation is set first to scale the backup workload down to zero, and
+// the trim job is created after all the backup workload pods are terminated. It returns the duration
+// after which the backup should be trimmed again, zero means no further trim is scheduled.
+func (r *BackupReconciler) trimContinuousBackup(reqCtx intctrlutil.RequestCtx, request *dpbackup.Request) (time.Duration, error) {
+	backup := request.Backup
+	trimBefore, trimming := backup.Annotations[dptypes.TrimBeforeAnnotationKey]
+	if !trimming {
+		return r.scheduleContinuousBackupTrim(reqCtx, request)
+	}
+
+	before, err := time.Parse(time.RFC3339, trimBefore)
+	if err != nil {
+		return 0, r.finishContinuousBackupTrim(reqCtx, backup, fmt.Errorf("invalid trim time %s, %v", trimBefore, err))
+	}
+	// wait for the backup workload pods to be terminated, the pod events will trigger the reconciliation.
+	podList := &corev1.PodList{}
+	if err = r.Client.List(reqCtx.Ctx, podList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{dptypes.BackupNameLabelKey: backup.Name}); err != nil {
+		return 0, err
+	}
+	for i := range podList.Items {
+		if len(r.filterBackupPods(reqCtx.Ctx, &podList.Items[i])) > 0 {
+			reqCtx.Log.V(1).Info("wait for the backup workload to be scaled down before trimming", "pod", podList.Items[i].Name)
+			return 0, nil
+		}
+	}
+
+	deleter := &dpbackup.Deleter{
+		RequestCtx: reqCtx,
+		Client:     r.Client,
+		Scheme:     r.Scheme,
+	}
+	if deleter.WorkerServiceAccount, err = getWorkerServiceAccount(reqCtx, r.Client, request.BackupPolicy, backup.Namespace); err != nil {
+		return 0, err
+	}
+	status, err := deleter.TrimBackupFiles(backup, before)
+	switch status {
+	case dpbackup.DeletionStatusDeleting:
+		if requeueErr, ok := err.(intctrlutil.RequeueError); ok {
+			// wait for a free slot of the deletion jobs
+			return requeueErr.RequeueAfter(), nil
+		}
+		// wait for the trim job to finish
+		return 0, err
+	case dpbackup.DeletionStatusSucceeded:
+		if timeRange := backup.Status.TimeRange; timeRange != nil && (timeRange.Start == nil || timeRange.Start.Before(&metav1.Time{Time: before})) {
+			timeRange.Start = &metav1.Time{Time: before.UTC()}
+		}
+		if err = r.finishContinuousBackupTrim(reqCtx, backup, nil); err != nil {
+			return 0, err
+		}
+	case dpbackup.DeletionStatusFailed:
+		if err = r.finishContinuousBackupTrim(reqCtx, backup, err); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, err
+	}
+	if err = deleter.DeleteTrimJob(backup); err != nil {
+		return 0, err
+	}
+	return continuousBackupTrimInterval, nil
+}
+
+// scheduleContinuousBackupTrim sets the TrimBeforeAnnotationKey annotation to the continuous backup if
+// its data is out of the retention period for more than continuousBackupTrimInterval, and returns the
+// duration after which the backup should be checked again.
+func (r *BackupReconciler) scheduleContinuousBackupTrim(reqCtx intctrlutil.RequestCtx, request *dpbackup.Request) (time.Duration, error) {
+	backup := request.Backup
+	timeRange := backup.Status.TimeRange
+	if request.Spec.RetentionPeriod == "" || timeRange == nil || timeRange.Start.IsZero() ||
+		request.ActionSet.Spec.Backup == nil || request.ActionSet.Spec.Backup.TrimBackup == nil {
+		return 0, nil
+	}
+	retention, err := request.Spec.RetentionPeriod.ToDuration()
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse retention period %s, %v", request.Spec.RetentionPeriod, err)
+	}
+	if retention <= 0 {
+		return 0, nil
+	}
+	now := r.clock.Now().UTC()
+	next := timeRange.Start.Add(retention + continuousBackupTrimInterval)
+	// back off after the last trim failed.
+	if cond := meta.FindStatusCondition(backup.Status.Conditions, ConditionTypeTrimmed); cond != nil &&
+		cond.Status == metav1.ConditionFalse {
+		if retryAt := cond.LastTransitionTime.Add(continuousBackupTrimInterval); retryAt.After(next) {
+			next = retryAt
+		}
+	}
+	if remaining := next.Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+	patch := client.MergeFrom(backup.DeepCopy())
+	if backup.Annotations == nil {
+		backup.Annotations = map[string]string{}
+	}
+	backup.Annotations[dptypes.TrimBeforeAnnotationKey] = now.Add(-retention).Format(time.RFC3339)
+	reqCtx.Log.Info("start to trim the continuous backup", "before", backup.Annotations[dptypes.TrimBeforeAnnotationKey])
+	return 0, r.patchBackupMeta(reqCtx, backup, patch)
+}
+
+// finishContinuousBackupTrim removes the TrimBeforeAnnotationKey annotation to scale the backup workload
+// up again, and records the result of the trim in the Trimmed condition.
+func (r *BackupReconciler) finishContinuousBackupTrim(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup, trimErr error) error {
+	cond := metav1.Condition{
+		Type:               ConditionTypeTrimmed,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonTrimSucceeded,
+		Message:            fmt.Sprintf("the data before %s is trimmed", backup.Annotations[dptypes.TrimBeforeAnnotationKey]),
+		LastTransitionTime: metav1.Time{Time: r.clock.Now().UTC()},
+		ObservedGeneration: backup.Generation,
+	}
+	if trimErr != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = ReasonTrimFailed
+		cond.Message = trimErr.Error()
+		r.Recorder.Event(backup, corev1.EventTypeWarning, ReasonTrimFailed, trimErr.Error())
+	} else {
+		r.Recorder.Event(backup, corev1.EventTypeNormal, ReasonTrimSucceeded, cond.Message)
+	}
+	// the condition is patched along with the backup status, the last transition time
+	// is always refreshed to back off the next trim.
+	meta.RemoveStatusCondition(&backup.Status.Conditions, ConditionTypeTrimmed)
+	meta.SetStatusCondition(&backup.Status.Conditions, cond)
+
+	patch := client.MergeFrom(backup.DeepCopy())
+	delete(backup.Annotations, dptypes.TrimBeforeAnnotationKey)
+	return r.patchBackupMeta(reqCtx, backup, patch)
+}
+
+// patchBackupMeta patches the object meta of the backup and keeps the in-memory status,
+// which will be patched later.
+func (r *BackupReconciler) patchBackupMeta(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup, patch client.Patch) error {
+	status := backup.Status.DeepCopy()
+	if err := r.Client.Patch(reqCtx.Ctx, backup, patch); err != nil {
+		return err
+	}
+	backup.Status = *status
+	return nil
+}
+
+// setPausedCondition sets the Paused condition of the continuous backup if it is paused,
+// and removes the condition if it is resumed.
+func (r *BackupReconciler) setPausedCondition(backup *dpv1alpha1.Backup) {
+	paused := meta.IsStatusConditionTrue(backup.Status.Conditions, ConditionTypePaused)
+	switch {
+	case backup.Spec.Paused && !paused:
+		meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypePaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonBackupPaused,
+			Message:            "the continuous backup is paused, the backup workload is scaled down to zero",
+			ObservedGeneration: backup.Generation,
+		})
+		r.Recorder.Event(backup, corev1.EventTypeNormal, ReasonBackupPaused, "the continuous backup is paused")
+	case !backup.Spec.Paused && paused:
+		meta.RemoveStatusCondition(&backup.Status.Conditions, ConditionTypePaused)
+		r.Recorder.Event(backup, corev1.EventTypeNormal, ReasonBackupResumed, "the continuous backup is resumed")
+	}
+}
+
+// pauseForReadOnlyBackupRepo pauses the new backup until the backup repo becomes writable. The Paused
+// condition is patched and the warning event is emitted only when the backup gets paused, rather than
+// on every reconciliation.
+func (r *BackupReconciler) pauseForReadOnlyBackupRepo(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup, repoName string) error {
+	err := dperrors.NewBackupRepoReadOnly(repoName)
+	if !meta.IsStatusConditionTrue(backup.Status.Conditions, ConditionTypePaused) {
+		original := backup.DeepCopy()
+		msg := err.Error() + ", the backup is paused until it becomes writable"
+		meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypePaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonBackupRepoReadOnly,
+			Message:            msg,
+			ObservedGeneration: backup.Generation,
+		})
+		if patchErr := r.Client.Status().Patch(reqCtx.Ctx, backup, client.MergeFrom(original)); patchErr != nil {
+			return patchErr
+		}
+		r.Recorder.Event(backup, corev1.EventTypeWarning, string(dperrors.ErrorTypeBackupRepoReadOnly), msg)
+	}
+	return intctrlutil.NewRequeueError(reconcileInterval, err.Error())
+}
+
+// resumeFromReadOnlyBackupRepo removes the Paused condition of the new backup once the backup repo
+// becomes writable.
+func (r *BackupReconciler) resumeFromReadOnlyBackupRepo(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup, request *dpbackup.Request) error {
+	cond := meta.FindStatusCondition(backup.Status.Conditions, ConditionTypePaused)
+	if cond == nil || cond.Reason != ReasonBackupRepoReadOnly {
+		return nil
+	}
+	original := backup.DeepCopy()
+	meta.RemoveStatusCondition(&backup.Status.Conditions, ConditionTypePaused)
+	if err := r.Client.Status().Patch(reqCtx.Ctx, backup, client.MergeFrom(original)); err != nil {
+		return err
+	}
+	meta.RemoveStatusCondition(&request.Status.Conditions, ConditionTypePaused)
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, ReasonBackupResumed,
+		"backup repo %s becomes writable, the backup is resumed", request.BackupRepo.Name)
+	return nil
+}
+
+// checkIsCompletedDuringRunning when continuous schedule is disabled or cluster has been deleted,
+// backup phase should be Completed. The paused backup is never completed.
+func (r *BackupReconciler) checkIsCompletedDuringRunning(reqCtx intctrlutil.RequestCtx,
+	request *dpbackup.Request) (bool, error) {
+	if request.Spec.Paused {
+		return false, nil
+	}
+	backupScheduleList := &dpv1alpha1.BackupScheduleList{}
+	if err := r.Client.List(reqCtx.Ctx, backupScheduleList, client.MatchingLabels{
 		dptypes.BackupPolicyLabelKey: request.Backup.Spec.BackupPolicyName,
 	}); err != nil {
 		return false, err
@@ -531,16 +1538,85 @@ func (r *BackupReconciler) checkIsCompletedDuringRunning(reqCtx intctrlutil.Requ
 	if boolptr.IsSetToTrue(enabled) && targetClusterExists {
 		return false, nil
 	}
+
+	var completionReason dpv1alpha1.BackupCompletionReason
+	switch {
+	case !targetClusterExists:
+		completionReason = dpv1alpha1.BackupCompletionReasonClusterDeleted
+	case enabled == nil && request.Labels[dptypes.BackupScheduleLabelKey] == "":
+		completionReason = dpv1alpha1.BackupCompletionReasonManual
+	default:
+		completionReason = dpv1alpha1.BackupCompletionReasonScheduleDisabled
+	}
+
+	// the schedule may be enabled again, annotate the backup with its archive path to resume
+	// into the same path, and keep the archived data until the backup is resumed.
+	resumable := completionReason == dpv1alpha1.BackupCompletionReasonScheduleDisabled && request.Status.Path != ""
+	if resumable && request.Annotations[dptypes.ResumeArchivePathAnnotationKey] != request.Status.Path {
+		patch := client.MergeFrom(request.Backup.DeepCopy())
+		if request.Annotations == nil {
+			request.Annotations = map[string]string{}
+		}
+		request.Annotations[dptypes.ResumeArchivePathAnnotationKey] = request.Status.Path
+		if err := r.Client.Patch(reqCtx.Ctx, request.Backup, patch); err != nil {
+			return false, err
+		}
+	}
+
 	patch := client.MergeFrom(request.Backup.DeepCopy())
 	request.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	request.Status.CompletionReason = completionReason
+	updateBackupStatusByActionStatus(&request.Status)
 	request.Status.CompletionTimestamp = &metav1.Time{Time: r.clock.Now().UTC()}
-	_ = dpbackup.SetExpirationByCreationTime(request.Backup)
+	if resumable {
+		// keep the archived data for a retention period since the completion, so the backup can be resumed
+		// within the period, rather than keeping it forever if the schedule is never enabled again.
+		request.Status.Expiration = nil
+		if duration, err := request.Spec.RetentionPeriod.ToDuration(); err == nil && duration > 0 {
+			request.Status.Expiration = &metav1.Time{Time: request.Status.CompletionTimestamp.Add(duration)}
+		}
+	} else {
+		_ = dpbackup.SetExpirationByCreationTime(request.Backup)
+	}
 	if !request.Status.StartTimestamp.IsZero() {
 		// round the duration to a multiple of seconds.
 		duration := request.Status.CompletionTimestamp.Sub(request.Status.StartTimestamp.Time).Round(time.Second)
 		request.Status.Duration = &metav1.Duration{Duration: duration}
 	}
-	return true, r.Client.Status().Patch(reqCtx.Ctx, request.Backup, patch)
+	msg := fmt.Sprintf("the continuous backup is completed since %s, %s", getCompletionReasonMessage(completionReason),
+		getTimeRangeMessage(request.Status.TimeRange))
+	meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeCompleted,
+		Status:             metav1.ConditionTrue,
+		Reason:             string(completionReason),
+		Message:            msg,
+		ObservedGeneration: request.Generation,
+	})
+	if err := r.patchStatus(reqCtx.Ctx, request.Backup, patch); err != nil {
+		return true, err
+	}
+	r.Recorder.Event(request.Backup, corev1.EventTypeNormal, string(completionReason), msg)
+	dpmetrics.RecordBackupCompleted(request.Backup)
+	return true, nil
+}
+
+func getCompletionReasonMessage(reason dpv1alpha1.BackupCompletionReason) string {
+	switch reason {
+	case dpv1alpha1.BackupCompletionReasonClusterDeleted:
+		return "the target cluster has been deleted"
+	case dpv1alpha1.BackupCompletionReasonManual:
+		return "it is not enabled by any backup schedule"
+	default:
+		return "the backup method is disabled in the backup schedule, it will be resumed into the same archive path once enabled"
+	}
+}
+
+func getTimeRangeMessage(timeRange *dpv1alpha1.BackupTimeRange) string {
+	if timeRange == nil || timeRange.Start == nil || timeRange.End == nil {
+		return "no time range is covered"
+	}
+	return fmt.Sprintf("the covered time range is [%s, %s]",
+		timeRange.Start.UTC().Format(time.RFC3339), timeRange.End.UTC().Format(time.RFC3339))
 }
 
 // handleCompletedPhase handles the backup object in completed phase.
@@ -552,9 +1628,443 @@ func (r *BackupReconciler) handleCompletedPhase(
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 	}
 
+	copyCheckAfter, err := r.copyBackup(reqCtx, backup)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	result, err := r.verifyBackup(reqCtx, backup)
+	if err == nil && copyCheckAfter > 0 && !result.Requeue &&
+		(result.RequeueAfter == 0 || copyCheckAfter < result.RequeueAfter) {
+		return intctrlutil.RequeueAfter(copyCheckAfter, reqCtx.Log, "wait for the backup repo to copy the backup to")
+	}
+	return result, err
+}
+
+// buildBackupCopies builds the pending copies of the backup in the backup repos specified by
+// the copyTo of the backup policy, the backup repo storing the backup is skipped. The copies
+// are built only once when the backup is completed.
+func buildBackupCopies(request *dpbackup.Request) []dpv1alpha1.BackupCopyStatus {
+	// the volume snapshot backup has no data in the backup repo.
+	if request.Status.BackupRepoName == "" {
+		return nil
+	}
+	var copies []dpv1alpha1.BackupCopyStatus
+	for _, repoName := range request.BackupPolicy.Spec.CopyTo {
+		if repoName == request.Status.BackupRepoName {
+			continue
+		}
+		copies = append(copies, dpv1alpha1.BackupCopyStatus{
+			RepoName: repoName,
+			Phase:    dpv1alpha1.BackupCopyPhasePending,
+		})
+	}
+	return copies
+}
+
+// copyBackup copies the completed backup to the backup repos recorded in the status copies and
+// updates the status of the copies. A failed copy does not fail the backup, it is only recorded
+// in the copy status and reported by an event. It returns the duration to check the copies again
+// if some of them are waiting for the backup repos.
+func (r *BackupReconciler) copyBackup(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (time.Duration, error) {
+	if len(backup.Status.Copies) == 0 {
+		return 0, nil
+	}
+	original := backup.DeepCopy()
+	var checkAfter time.Duration
+	for i := range backup.Status.Copies {
+		backupCopy := &backup.Status.Copies[i]
+		if backupCopy.Phase == dpv1alpha1.BackupCopyPhaseCompleted ||
+			backupCopy.Phase == dpv1alpha1.BackupCopyPhaseFailed {
+			continue
+		}
+		wait, err := r.copyBackupToRepo(reqCtx, backup, backupCopy)
+		if err != nil {
+			return 0, err
+		}
+		if wait {
+			checkAfter = copyBackupCheckInterval
+		}
+	}
+	if reflect.DeepEqual(original.Status, backup.Status) {
+		return checkAfter, nil
+	}
+	return checkAfter, r.patchStatus(reqCtx.Ctx, backup, client.MergeFrom(original))
+}
+
+// copyBackupToRepo creates the job to copy the backup to the backup repo of the copy, and updates
+// the copy status according to the job. It returns true if the backup repo is not ready to copy to.
+func (r *BackupReconciler) copyBackupToRepo(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup, backupCopy *dpv1alpha1.BackupCopyStatus) (bool, error) {
+	failCopy := func(reason string) {
+		backupCopy.Phase = dpv1alpha1.BackupCopyPhaseFailed
+		backupCopy.FailureReason = reason
+		r.Recorder.Eventf(backup, corev1.EventTypeWarning, "CopyBackupFailed",
+			"failed to copy the backup to backup repo %s: %s", backupCopy.RepoName, reason)
+	}
+	getRepo := func(name string) (*dpv1alpha1.BackupRepo, error) {
+		repo := &dpv1alpha1.BackupRepo{}
+		if err := r.Client.Get(reqCtx.Ctx, client.ObjectKey{Name: name}, repo); err != nil {
+			if apierrors.IsNotFound(err) {
+				failCopy(fmt.Sprintf("backup repo %s not found", name))
+				return nil, nil
+			}
+			return nil, err
+		}
+		return repo, nil
+	}
+	sourceRepo, err := getRepo(backup.Status.BackupRepoName)
+	if err != nil || sourceRepo == nil {
+		return false, err
+	}
+	targetRepo, err := getRepo(backupCopy.RepoName)
+	if err != nil || targetRepo == nil {
+		return false, err
+	}
+	if targetRepo.Status.Phase != dpv1alpha1.BackupRepoReady {
+		return true, nil
+	}
+	// wait for the backup repo controller to prepare the resources to access the backup repo.
+	prepared, err := isBackupRepoPrepared(reqCtx.Ctx, r.Client, targetRepo, backup.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if !prepared {
+		if backup.Labels[dataProtectionWaitCopyRepoPreparationKey] != targetRepo.Name {
+			// patch a copy of the backup to keep the status of the copies being updated.
+			labeledBackup := backup.DeepCopy()
+			if labeledBackup.Labels == nil {
+				labeledBackup.Labels = map[string]string{}
+			}
+			labeledBackup.Labels[dataProtectionWaitCopyRepoPreparationKey] = targetRepo.Name
+			if err = r.Client.Patch(reqCtx.Ctx, labeledBackup, client.MergeFrom(backup)); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	backupPolicy, err := dputils.GetBackupPolicyByName(reqCtx, r.Client, backup.Spec.BackupPolicyName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	saName, err := getWorkerServiceAccount(reqCtx, r.Client, backupPolicy, backup.Namespace)
+	if err != nil {
+		return false, fmt.Errorf("failed to get worker service account: %w", err)
+	}
+	jobKey := dpbackup.BuildCopyBackupJobKey(backup, targetRepo.Name)
+	copyAction := &action.CopyBackupAction{
+		Name:  jobKey.Name,
+		Owner: backup,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: jobKey.Namespace,
+			Name:      jobKey.Name,
+			Labels: map[string]string{
+				constant.AppManagedByLabelKey: dptypes.AppName,
+				dptypes.BackupCopyJobLabelKey: trueVal,
+			},
+		},
+		Backup:              backup,
+		SourceRepo:          sourceRepo,
+		TargetRepo:          targetRepo,
+		RepoVolumeMountPath: dpbackup.RepoVolumeMountPath,
+		ServiceAccountName:  saName,
+	}
+	actionCtx := action.ActionContext{
+		Ctx:      reqCtx.Ctx,
+		Client:   r.Client,
+		Recorder: r.Recorder,
+		Scheme:   r.Scheme,
+	}
+	status, err := copyAction.Execute(actionCtx)
+	if err != nil {
+		return false, err
+	}
+	switch status.Phase {
+	case dpv1alpha1.ActionPhaseCompleted:
+		backupCopy.Phase = dpv1alpha1.BackupCopyPhaseCompleted
+		backupCopy.TotalSize = backup.Status.TotalSize
+		backupCopy.CompletionTimestamp = &metav1.Time{Time: r.clock.Now().UTC()}
+		r.Recorder.Eventf(backup, corev1.EventTypeNormal, "CopiedBackup",
+			"copied the backup to backup repo %s", backupCopy.RepoName)
+	case dpv1alpha1.ActionPhaseFailed:
+		failCopy(status.FailureReason)
+	default:
+		backupCopy.Phase = dpv1alpha1.BackupCopyPhaseRunning
+	}
+	return false, nil
+}
+
+// verifyBackup verifies the completed backup periodically according to the verification policy
+// of the backup policy, and records the result of the verification in the backup status.
+func (r *BackupReconciler) verifyBackup(
+	reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (ctrl.Result, error) {
+	// the dry-run backup and the volume snapshot backup have no data in the backup repo.
+	if backup.Spec.DryRun || backup.Status.BackupRepoName == "" {
+		return intctrlutil.Reconciled()
+	}
+	backupPolicy, err := dputils.GetBackupPolicyByName(reqCtx, r.Client, backup.Spec.BackupPolicyName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return intctrlutil.Reconciled()
+		}
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	policy := backupPolicy.Spec.VerificationPolicy
+	if policy == nil || boolptr.IsSetToFalse(policy.Enabled) {
+		return intctrlutil.Reconciled()
+	}
+	// the cron expression is in UTC unless the time zone is specified.
+	cronExpression := policy.CronExpression
+	if !strings.HasPrefix(cronExpression, "CRON_TZ=") && !strings.HasPrefix(cronExpression, "TZ=") {
+		cronExpression = "CRON_TZ=UTC " + cronExpression
+	}
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		r.Recorder.Eventf(backup, corev1.EventTypeWarning, "InvalidVerificationSchedule",
+			"invalid cron expression %q of the verification policy: %s", policy.CronExpression, err.Error())
+		return intctrlutil.Reconciled()
+	}
+	waitForNextVerification := func() (ctrl.Result, error) {
+		now := r.clock.Now()
+		return intctrlutil.RequeueAfter(schedule.Next(now).Sub(now), reqCtx.Log, "wait for the next verification")
+	}
+
+	verifier := &dpbackup.Verifier{
+		RequestCtx: reqCtx,
+		Client:     r.Client,
+		Scheme:     r.Scheme,
+	}
+	status, msg, err := verifier.GetVerificationStatus(backup)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	switch status {
+	case dpbackup.VerificationStatusVerifying:
+		// wait for the verification job to finish
+		return intctrlutil.Reconciled()
+	case dpbackup.VerificationStatusPassed:
+		if err = r.patchVerificationResult(reqCtx, backup, policy, nil); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if err = verifier.DeleteVerificationJob(backup); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		return waitForNextVerification()
+	case dpbackup.VerificationStatusFailed:
+		if err = r.patchVerificationResult(reqCtx, backup, policy, errors.New(msg)); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if err = verifier.DeleteVerificationJob(backup); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		return waitForNextVerification()
+	}
+
+	// wait until the next scheduled time after the last verification.
+	if verification := backup.Status.Verification; verification != nil && verification.LastVerifiedTime != nil {
+		remaining := schedule.Next(verification.LastVerifiedTime.Time).Sub(r.clock.Now())
+		if remaining > 0 {
+			return intctrlutil.RequeueAfter(remaining, reqCtx.Log, "wait for the next verification")
+		}
+	}
+
+	// do not verify the backup while another backup is writing to the same backup repo,
+	// the backup is enqueued again once the in-progress backups are finished.
+	inProgress, err := hasInProgressBackups(reqCtx.Ctx, r.Client, backup.Namespace, backup.Status.BackupRepoName)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if inProgress {
+		r.verificationThrottle.add(backup)
+		return intctrlutil.RequeueAfter(verificationThrottleInterval, reqCtx.Log,
+			"wait for the in-progress backups in the same backup repo")
+	}
+	r.verificationThrottle.remove(backup)
+
+	actionSet, err := dputils.GetActionSetByName(reqCtx, r.Client, policy.ActionSetName)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if actionSet.Spec.Backup == nil || actionSet.Spec.Backup.VerifyBackup == nil {
+		err = fmt.Errorf("the verify action is not defined in actionSet %s", actionSet.Name)
+		if err = r.patchVerificationResult(reqCtx, backup, policy, err); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		return waitForNextVerification()
+	}
+	backupRepo := &dpv1alpha1.BackupRepo{}
+	if err = r.Client.Get(reqCtx.Ctx, client.ObjectKey{Name: backup.Status.BackupRepoName}, backupRepo); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if verifier.WorkerServiceAccount, err = getWorkerServiceAccount(reqCtx, r.Client, backupPolicy, backup.Namespace); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if err = verifier.VerifyBackup(backup, backupRepo, actionSet); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
 	return intctrlutil.Reconciled()
 }
 
+// verificationThrottle records the backups whose verification is throttled by the in-progress backups,
+// grouped by the namespace and the backup repo.
+type verificationThrottle struct {
+	sync.Mutex
+	throttled map[types.NamespacedName]map[types.NamespacedName]struct{}
+}
+
+func verificationThrottleKey(backup *dpv1alpha1.Backup) types.NamespacedName {
+	return types.NamespacedName{Namespace: backup.Namespace, Name: backup.Status.BackupRepoName}
+}
+
+func (t *verificationThrottle) add(backup *dpv1alpha1.Backup) {
+	t.Lock()
+	defer t.Unlock()
+	if t.throttled == nil {
+		t.throttled = map[types.NamespacedName]map[types.NamespacedName]struct{}{}
+	}
+	key := verificationThrottleKey(backup)
+	if t.throttled[key] == nil {
+		t.throttled[key] = map[types.NamespacedName]struct{}{}
+	}
+	t.throttled[key][client.ObjectKeyFromObject(backup)] = struct{}{}
+}
+
+func (t *verificationThrottle) remove(backup *dpv1alpha1.Backup) {
+	t.Lock()
+	defer t.Unlock()
+	key := verificationThrottleKey(backup)
+	delete(t.throttled[key], client.ObjectKeyFromObject(backup))
+	if len(t.throttled[key]) == 0 {
+		delete(t.throttled, key)
+	}
+}
+
+// release removes and returns the throttled backups in the same namespace and backup repo as the backup.
+func (t *verificationThrottle) release(backup *dpv1alpha1.Backup) []types.NamespacedName {
+	t.Lock()
+	defer t.Unlock()
+	key := verificationThrottleKey(backup)
+	var backups []types.NamespacedName
+	for backupKey := range t.throttled[key] {
+		backups = append(backups, backupKey)
+	}
+	delete(t.throttled, key)
+	return backups
+}
+
+// verificationThrottleHandler enqueues the backups whose verification is throttled once a backup
+// in the same backup repo is no longer running.
+func (r *BackupReconciler) verificationThrottleHandler() handler.EventHandler {
+	releaseThrottled := func(backup *dpv1alpha1.Backup, q workqueue.RateLimitingInterface) {
+		for _, backupKey := range r.verificationThrottle.release(backup) {
+			q.Add(reconcile.Request{NamespacedName: backupKey})
+		}
+	}
+	return &handler.Funcs{
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			oldBackup, ok := e.ObjectOld.(*dpv1alpha1.Backup)
+			if !ok || oldBackup.Status.Phase != dpv1alpha1.BackupPhaseRunning {
+				return
+			}
+			if newBackup, ok := e.ObjectNew.(*dpv1alpha1.Backup); ok && newBackup.Status.Phase != dpv1alpha1.BackupPhaseRunning {
+				releaseThrottled(newBackup, q)
+			}
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			if backup, ok := e.Object.(*dpv1alpha1.Backup); ok && backup.Status.Phase == dpv1alpha1.BackupPhaseRunning {
+				releaseThrottled(backup, q)
+			}
+		},
+	}
+}
+
+// checkBackupPathCollision checks whether the path of the backup is used by another backup
+// which is not deleted in the same backup repo.
+func (r *BackupReconciler) checkBackupPathCollision(reqCtx intctrlutil.RequestCtx, request *dpbackup.Request) error {
+	// build the path in the same way as it will be recorded in the backup status.
+	backup := request.Backup.DeepCopy()
+	backup.Status.FormatVersion = dpbackup.FormatVersion
+	if cluster := getCluster(reqCtx.Ctx, r.Client, request.TargetPods[0]); cluster != nil {
+		backup.Labels[dptypes.ClusterUIDLabelKey] = string(cluster.UID)
+	}
+	backupPath := dpbackup.BuildBackupPath(backup, request.BackupPolicy.Spec.PathPrefix)
+
+	repoName := request.BackupRepo.Name
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(reqCtx.Ctx, backupList,
+		client.MatchingLabels{dataProtectionBackupRepoKey: repoName}); err != nil {
+		return err
+	}
+	for _, item := range backupList.Items {
+		if item.UID == backup.UID || item.Status.BackupRepoName != repoName ||
+			!item.DeletionTimestamp.IsZero() || item.Status.Phase == dpv1alpha1.BackupPhaseDeleting {
+			continue
+		}
+		if item.Status.Path == backupPath {
+			return dperrors.NewBackupPathCollision(backupPath, repoName, item.Namespace, item.Name)
+		}
+	}
+	return nil
+}
+
+// hasInProgressBackups checks whether there are backups writing to the backup repo in the namespace,
+// the continuous backups are ignored since they keep running.
+func hasInProgressBackups(ctx context.Context, cli client.Reader, namespace, repoName string) (bool, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(namespace),
+		client.MatchingLabels{dataProtectionBackupRepoKey: repoName}); err != nil {
+		return false, err
+	}
+	for _, item := range backupList.Items {
+		if item.Status.Phase != dpv1alpha1.BackupPhaseRunning ||
+			item.Labels[dptypes.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeContinuous) {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// patchVerificationResult records the result of the verification in the backup status, a warning
+// event is sent if the verification failed.
+func (r *BackupReconciler) patchVerificationResult(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup, policy *dpv1alpha1.VerificationPolicy, verifyErr error) error {
+	patch := client.MergeFrom(backup.DeepCopy())
+	backup.Status.Verification = &dpv1alpha1.BackupVerificationStatus{
+		LastVerifiedTime: &metav1.Time{Time: r.clock.Now().UTC()},
+		Result:           dpv1alpha1.BackupVerificationResultPassed,
+	}
+	cond := metav1.Condition{
+		Type:               ConditionTypeVerified,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonVerificationPassed,
+		ObservedGeneration: backup.Generation,
+	}
+	if verifyErr != nil {
+		backup.Status.Verification.Result = dpv1alpha1.BackupVerificationResultFailed
+		backup.Status.Verification.Message = verifyErr.Error()
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = ReasonVerificationFailed
+		cond.Message = verifyErr.Error()
+		r.Recorder.Event(backup, corev1.EventTypeWarning, ReasonVerificationFailed, verifyErr.Error())
+	} else {
+		r.Recorder.Event(backup, corev1.EventTypeNormal, ReasonVerificationPassed, "the backup is verified")
+	}
+	if policy.UpdateCondition {
+		meta.SetStatusCondition(&backup.Status.Conditions, cond)
+	}
+	return r.patchStatus(reqCtx.Ctx, backup, patch)
+}
+
+// patchStatus patches the status of the backup in a span of the trace.
+func (r *BackupReconciler) patchStatus(ctx context.Context, backup *dpv1alpha1.Backup, patch client.Patch) error {
+	ctx, span := tracing.StartBackupSpan(ctx, "Backup.PatchStatus", backup)
+	err := r.Client.Status().Patch(ctx, backup, patch)
+	tracing.EndSpan(span, err)
+	return err
+}
+
 func (r *BackupReconciler) updateStatusIfFailed(
 	reqCtx intctrlutil.RequestCtx,
 	original *dpv1alpha1.Backup,
@@ -566,17 +2076,36 @@ func (r *BackupReconciler) updateStatusIfFailed(
 	sendWarningEventForError(r.Recorder, backup, err)
 	backup.Status.Phase = dpv1alpha1.BackupPhaseFailed
 	backup.Status.FailureReason = err.Error()
+	backup.Status.FailureCode = getBackupFailureCode(err)
 
 	// set expiration time for failed backup, make sure the failed backup will be
-	// deleted after the expiration time.
-	_ = dpbackup.SetExpirationByCreationTime(backup)
+	// deleted after the expiration time. The expiration set before is kept if the
+	// failed retention period is invalid.
+	if errExpiration := dpbackup.SetExpirationForFailedBackup(backup,
+		r.getFailedRetentionPeriod(reqCtx, backup), r.clock.Now().UTC()); errExpiration != nil {
+		reqCtx.Log.Error(errExpiration, "failed to set expiration for the failed backup")
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "InvalidFailedRetentionPeriod", errExpiration.Error())
+	}
 
-	if errUpdate := r.Client.Status().Patch(reqCtx.Ctx, backup, client.MergeFrom(original)); errUpdate != nil {
+	if errUpdate := r.patchStatus(reqCtx.Ctx, backup, client.MergeFrom(original)); errUpdate != nil {
 		return intctrlutil.CheckedRequeueWithError(errUpdate, reqCtx.Log, "")
 	}
+	if original.Status.Phase != dpv1alpha1.BackupPhaseFailed {
+		dpmetrics.RecordBackupFailed(backup)
+	}
 	return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 }
 
+// getFailedRetentionPeriod gets the failed retention period from the backup policy of the backup,
+// it returns an empty value if the backup policy is not found.
+func (r *BackupReconciler) getFailedRetentionPeriod(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) dpv1alpha1.RetentionPeriod {
+	backupPolicy, err := dputils.GetBackupPolicyByName(reqCtx, r.Client, backup.Spec.BackupPolicyName)
+	if err != nil {
+		return ""
+	}
+	return backupPolicy.Spec.FailedRetentionPeriod
+}
+
 // deleteExternalJobs deletes the external jobs.
 func (r *BackupReconciler) deleteExternalJobs(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) error {
 	labels := dpbackup.BuildBackupWorkloadLabels(backup)
@@ -595,6 +2124,45 @@ func (r *BackupReconciler) deleteVolumeSnapshots(reqCtx intctrlutil.RequestCtx,
 	return deleter.DeleteVolumeSnapshots(backup)
 }
 
+// adoptVolumeSnapshots labels the volume snapshots whose names match the backup and sets the
+// backup as their controller owner, the volume snapshots owned by other objects are skipped.
+func (r *BackupReconciler) adoptVolumeSnapshots(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) error {
+	vsCli := dputils.NewCompatClient(r.Client)
+	for _, key := range dpbackup.GetVolumeSnapshotKeys(backup) {
+		vs := &vsv1.VolumeSnapshot{}
+		if err := vsCli.Get(reqCtx.Ctx, key, vs); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !vs.DeletionTimestamp.IsZero() || dpbackup.IsVolumeSnapshotOwnedByOthers(backup, vs) {
+			continue
+		}
+		original := vs.DeepCopy()
+		if vs.Labels == nil {
+			vs.Labels = map[string]string{}
+		}
+		for k, v := range dpbackup.BuildBackupWorkloadLabels(backup) {
+			vs.Labels[k] = v
+		}
+		if metav1.GetControllerOf(vs) == nil && vs.Namespace == backup.Namespace {
+			if err := controllerutil.SetControllerReference(backup, vs, r.Scheme); err != nil {
+				return err
+			}
+		}
+		controllerutil.AddFinalizer(vs, dptypes.DataProtectionFinalizerName)
+		if reflect.DeepEqual(vs.ObjectMeta, original.ObjectMeta) {
+			continue
+		}
+		reqCtx.Log.Info("adopt volume snapshot", "volume snapshot", key.Name)
+		if err := vsCli.Patch(reqCtx.Ctx, vs, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // deleteExternalStatefulSet deletes the external statefulSet.
 func (r *BackupReconciler) deleteExternalStatefulSet(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) error {
 	key := client.ObjectKey{
@@ -615,64 +2183,230 @@ func (r *BackupReconciler) deleteExternalStatefulSet(reqCtx intctrlutil.RequestC
 	return intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, sts)
 }
 
+// deleteExternalPVCs deletes the PVCs created by the volumeClaimTemplates of the external statefulSet
+// after the statefulSet is gone. The PVCs are kept if the deletionPolicy of the backup is Retain,
+// and the PVCs still mounted by the pods are deleted after the pods are gone. It returns a requeue
+// error until all the PVCs are deleted.
+func (r *BackupReconciler) deleteExternalPVCs(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) error {
+	if backup.Spec.DeletionPolicy == dpv1alpha1.BackupDeletionPolicyRetain {
+		return nil
+	}
+	// wait for the statefulSet to be deleted before deleting the PVCs created by it.
+	exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      backup.Name,
+	}, &appsv1.StatefulSet{})
+	if err != nil {
+		return err
+	}
+	if exists {
+		return intctrlutil.NewErrorf(intctrlutil.ErrorTypeRequeue,
+			"wait for the backup workload %s to be deleted before deleting its PVCs", backup.Name)
+	}
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err = r.Client.List(reqCtx.Ctx, pvcList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels(dpbackup.BuildBackupWorkloadLabels(backup))); err != nil {
+		return err
+	}
+	if len(pvcList.Items) == 0 {
+		return nil
+	}
+	mountedPVCs, err := r.getMountedPVCNames(reqCtx, backup.Namespace)
+	if err != nil {
+		return err
+	}
+	pendingPVCs := make([]string, 0, len(pvcList.Items))
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		pendingPVCs = append(pendingPVCs, pvc.Name)
+		if !pvc.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if mountedPVCs.Has(pvc.Name) {
+			reqCtx.Log.V(1).Info("skip deleting the pvc mounted by pods", "pvc", pvc.Name)
+			continue
+		}
+		reqCtx.Log.V(1).Info("delete pvc", "pvc", pvc.Name)
+		if err = intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, pvc); err != nil {
+			return err
+		}
+		r.Recorder.Eventf(backup, corev1.EventTypeNormal, "DeletedBackupWorkloadPVC",
+			"deleted the pvc %s created by the backup workload", pvc.Name)
+	}
+	// requeue until the PVCs are gone.
+	return intctrlutil.NewErrorf(intctrlutil.ErrorTypeRequeue,
+		"wait for the PVCs created by the backup workload to be deleted: %s", strings.Join(pendingPVCs, ","))
+}
+
+// getMountedPVCNames gets the names of the PVCs mounted by the pods which are not terminated.
+func (r *BackupReconciler) getMountedPVCNames(reqCtx intctrlutil.RequestCtx, namespace string) (sets.Set[string], error) {
+	podList := &corev1.PodList{}
+	if err := r.Client.List(reqCtx.Ctx, podList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	names := sets.New[string]()
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				names.Insert(volume.PersistentVolumeClaim.ClaimName)
+			}
+		}
+	}
+	return names, nil
+}
+
 // deleteExternalResources deletes the external workloads that execute backup.
-// Currently, it only supports two types of workloads: job.
+// Currently, it supports two types of workloads: job and statefulSet, the PVCs
+// created by the statefulSet are deleted too.
 func (r *BackupReconciler) deleteExternalResources(
 	reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) error {
 	if err := r.deleteExternalJobs(reqCtx, backup); err != nil {
 		return err
 	}
-	return r.deleteExternalStatefulSet(reqCtx, backup)
+	if err := r.deleteExternalStatefulSet(reqCtx, backup); err != nil {
+		return err
+	}
+	return r.deleteExternalPVCs(reqCtx, backup)
 }
 
 // PatchBackupObjectMeta patches backup object metaObject include cluster snapshot.
+// The backup is patched only if its labels, annotations or finalizers are changed semantically.
 func PatchBackupObjectMeta(
 	original *dpv1alpha1.Backup,
 	request *dpbackup.Request) (bool, error) {
+	labels, annotations, err := BuildBackupObjectMeta(original, request)
+	if err != nil {
+		return false, err
+	}
+	request.Labels = labels
+	request.Annotations = annotations
+
+	// set finalizer
+	controllerutil.AddFinalizer(request.Backup, dptypes.DataProtectionFinalizerName)
+
+	// wait for the backup repo controller to prepare the essential resource.
+	wait := isWaitingForBackupRepoPreparation(request)
+	if backupObjectMetaEqual(&original.ObjectMeta, &request.ObjectMeta) {
+		return wait, nil
+	}
+	return wait, request.Client.Patch(request.Ctx, request.Backup, client.MergeFrom(original))
+}
+
+// BuildBackupObjectMeta builds the labels and annotations of the backup, the existing ones are kept.
+// It doesn't modify the backup and the request, and the output is deterministic for the same inputs,
+// e.g. the encrypted connection password is not regenerated if it already exists.
+func BuildBackupObjectMeta(backup *dpv1alpha1.Backup, request *dpbackup.Request) (map[string]string, map[string]string, error) {
+	labels := make(map[string]string, len(backup.Labels))
+	for k, v := range backup.Labels {
+		labels[k] = v
+	}
+	annotations := make(map[string]string, len(backup.Annotations))
+	for k, v := range backup.Annotations {
+		annotations[k] = v
+	}
 	targetPod := request.TargetPods[0]
 
 	// get KubeBlocks cluster and set labels and annotations for backup
 	// TODO(ldm): we should remove this dependency of cluster in the future
 	cluster := getCluster(request.Ctx, request.Client, targetPod)
 	if cluster != nil {
-		if err := setClusterSnapshotAnnotation(request.Backup, cluster); err != nil {
-			return false, err
+		clusterString, err := getClusterObjectString(cluster)
+		if err != nil {
+			return nil, nil, err
 		}
-		if err := setConnectionPasswordAnnotation(request); err != nil {
-			return false, err
+		if clusterString != nil {
+			annotations[constant.ClusterSnapshotAnnotationKey] = *clusterString
+		}
+		if _, ok := annotations[dptypes.ConnectionPasswordAnnotationKey]; !ok {
+			ciphertext, err := encryptConnectionPassword(request)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ciphertext != "" {
+				annotations[dptypes.ConnectionPasswordAnnotationKey] = ciphertext
+			}
+		}
+		labels[dptypes.ClusterUIDLabelKey] = string(cluster.UID)
+		// propagate the configured cluster labels, they are only set when the backup is
+		// created, so the existing backups are not affected by the configuration changes.
+		for _, key := range getPropagatedClusterLabelKeys() {
+			if v, ok := cluster.Labels[key]; ok {
+				labels[key] = v
+			}
 		}
-		request.Labels[dptypes.ClusterUIDLabelKey] = string(cluster.UID)
 	}
 
 	for _, v := range getClusterLabelKeys() {
-		request.Labels[v] = targetPod.Labels[v]
+		labels[v] = targetPod.Labels[v]
 	}
 
-	request.Labels[constant.AppManagedByLabelKey] = dptypes.AppName
-	request.Labels[dptypes.BackupTypeLabelKey] = request.GetBackupType()
-	request.Labels[dptypes.BackupPolicyLabelKey] = request.Spec.BackupPolicyName
-	// wait for the backup repo controller to prepare the essential resource.
-	wait := false
+	labels[constant.AppManagedByLabelKey] = dptypes.AppName
+	labels[dptypes.BackupTypeLabelKey] = request.GetBackupType()
+	labels[dptypes.BackupPolicyLabelKey] = backup.Spec.BackupPolicyName
 	if request.BackupRepo != nil {
-		request.Labels[dataProtectionBackupRepoKey] = request.BackupRepo.Name
-		if (request.BackupRepo.AccessByMount() && request.BackupRepoPVC == nil) ||
-			(request.BackupRepo.AccessByTool() && request.ToolConfigSecret == nil) {
-			request.Labels[dataProtectionWaitRepoPreparationKey] = trueVal
-			wait = true
+		labels[dataProtectionBackupRepoKey] = request.BackupRepo.Name
+		if isWaitingForBackupRepoPreparation(request) {
+			labels[dataProtectionWaitRepoPreparationKey] = trueVal
+		}
+		// record the generation of the credential used by the backup, the tool config secret may still
+		// keep the old credential for the in-progress backups when the credential is rotated.
+		if _, ok := annotations[dataProtectionCredentialGenerationAnnotationKey]; !ok {
+			if generation := getCredentialGeneration(request); generation != "" {
+				annotations[dataProtectionCredentialGenerationAnnotationKey] = generation
+			}
 		}
 	}
 
 	// set annotations
-	request.Annotations[dptypes.BackupTargetPodLabelKey] = targetPod.Name
+	annotations[dptypes.BackupTargetPodLabelKey] = targetPod.Name
+	if request.ActionSet != nil {
+		actionSetHash, err := dputils.ComputeActionSetHash(request.ActionSet)
+		if err != nil {
+			return nil, nil, err
+		}
+		annotations[dptypes.ActionSetHashAnnotationKey] = actionSetHash
+	}
+	// record the role actually backed up, which may be the fallback role of the target.
+	if role := targetPod.Labels[constant.RoleLabelKey]; role != "" {
+		annotations[dptypes.BackupTargetPodRoleAnnotationKey] = role
+	}
+	return labels, annotations, nil
+}
 
-	// set finalizer
-	controllerutil.AddFinalizer(request.Backup, dptypes.DataProtectionFinalizerName)
+// getCredentialGeneration returns the generation of the backup repo credential used by the backup,
+// an empty string is returned if the backup repo has no credential.
+func getCredentialGeneration(request *dpbackup.Request) string {
+	if request.ToolConfigSecret != nil {
+		return request.ToolConfigSecret.Annotations[dataProtectionCredentialGenerationAnnotationKey]
+	}
+	if request.BackupRepo.Status.CredentialGeneration == 0 {
+		return ""
+	}
+	return strconv.FormatInt(request.BackupRepo.Status.CredentialGeneration, 10)
+}
 
-	if reflect.DeepEqual(original.ObjectMeta, request.ObjectMeta) {
-		return wait, nil
+// isWaitingForBackupRepoPreparation checks if the essential resources of the backup repo are not prepared yet.
+func isWaitingForBackupRepoPreparation(request *dpbackup.Request) bool {
+	if request.BackupRepo == nil {
+		return false
 	}
+	return (request.BackupRepo.AccessByMount() && request.BackupRepoPVC == nil) ||
+		(request.BackupRepo.AccessByTool() && request.ToolConfigSecret == nil)
+}
 
-	return wait, request.Client.Patch(request.Ctx, request.Backup, client.MergeFrom(original))
+// backupObjectMetaEqual checks if the labels, annotations and finalizers of the backup are semantically
+// equal, the nil and empty ones are treated as equal, and the order of the finalizers is ignored.
+func backupObjectMetaEqual(a, b *metav1.ObjectMeta) bool {
+	if !maps.Equal(a.Labels, b.Labels) || !maps.Equal(a.Annotations, b.Annotations) {
+		return false
+	}
+	if len(a.Finalizers) != len(b.Finalizers) {
+		return false
+	}
+	return sets.New(a.Finalizers...).Equal(sets.New(b.Finalizers...))
 }
 
 func mergeActionStatus(original, new *dpv1alpha1.ActionStatus) dpv1alpha1.ActionStatus {
@@ -680,11 +2414,79 @@ func mergeActionStatus(original, new *dpv1alpha1.ActionStatus) dpv1alpha1.Action
 	if original.StartTimestamp != nil {
 		as.StartTimestamp = original.StartTimestamp
 	}
+	// the retries of the job actions are recorded by the controller, keep them and
+	// accumulate the failure reasons of all attempts. the retry count of the statefulSet
+	// action is the restart count of the backup pod reported by the action itself.
+	if as.ActionType != dpv1alpha1.ActionTypeStatefulSet && original.RetryCount > 0 {
+		as.RetryCount = original.RetryCount
+		switch {
+		case as.FailureReason == "":
+			as.FailureReason = original.FailureReason
+		case original.FailureReason != "":
+			as.FailureReason = original.FailureReason + "; " + as.FailureReason
+		}
+	}
 	return *as
 }
 
+// retryFailedAction retries the failed action if the retry policy of the backup method allows, the
+// failed execution is cleaned up, and the action is executed again after an exponential backoff.
+// It returns false if the action can not be retried, and the backup should be failed.
+func (r *BackupReconciler) retryFailedAction(actCtx action.ActionContext, request *dpbackup.Request,
+	act action.Action, status *dpv1alpha1.ActionStatus) (time.Duration, bool, error) {
+	retryableAct, ok := act.(action.RetryableAction)
+	if !ok || request.BackupMethod == nil || request.BackupMethod.RetryPolicy == nil {
+		return 0, false, nil
+	}
+	policy := request.BackupMethod.RetryPolicy
+	if status.RetryCount >= policy.MaxRetries {
+		if policy.MaxRetries > 0 {
+			status.FailureReason = fmt.Sprintf("retries exhausted after %d attempts: %s", status.RetryCount+1, status.FailureReason)
+		}
+		return 0, false, nil
+	}
+	if err := retryableAct.Retry(actCtx); err != nil {
+		return 0, false, err
+	}
+	status.RetryCount++
+	retryAfter := getRetryBackoff(status.RetryCount, policy.BackoffLimitSeconds)
+	r.Recorder.Eventf(request.Backup, corev1.EventTypeWarning, ReasonRetryingAction,
+		"action %s failed, retry %d/%d after %s: %s", act.GetName(), status.RetryCount,
+		policy.MaxRetries, retryAfter.Round(time.Second), status.FailureReason)
+	status.Phase = dpv1alpha1.ActionPhaseRunning
+	status.CompletionTimestamp = nil
+	status.NextRetryTimestamp = &metav1.Time{Time: r.clock.Now().Add(retryAfter).UTC()}
+	return retryAfter, true, nil
+}
+
+// updateBackupStatusByActionStatus updates the backup status by the status of the actions. The total size
+// of the backup is the sum of the sizes reported by the actions, the sizes of the volume snapshots are
+// counted for the actions which do not report it.
 func updateBackupStatusByActionStatus(backupStatus *dpv1alpha1.BackupStatus) {
+	var totalSize *resource.Quantity
+	addSize := func(size string) {
+		if size == "" {
+			return
+		}
+		quantity, err := resource.ParseQuantity(size)
+		if err != nil {
+			return
+		}
+		if totalSize == nil {
+			totalSize = &quantity
+		} else {
+			totalSize.Add(quantity)
+		}
+	}
 	for _, act := range backupStatus.Actions {
+		if act.TotalSize != "" {
+			addSize(act.TotalSize)
+		} else {
+			for _, vs := range act.VolumeSnapshots {
+				addSize(vs.Size)
+			}
+		}
+		// keep the size reported by the action as it is if none of the sizes can be parsed.
 		if act.TotalSize != "" && backupStatus.TotalSize == "" {
 			backupStatus.TotalSize = act.TotalSize
 		}
@@ -692,35 +2494,48 @@ func updateBackupStatusByActionStatus(backupStatus *dpv1alpha1.BackupStatus) {
 			backupStatus.TimeRange = act.TimeRange
 		}
 	}
+	if totalSize != nil {
+		backupStatus.TotalSize = totalSize.String()
+	}
 }
 
-// setConnectionPasswordAnnotation sets the encrypted password of the connection credential to the backup's annotations
-func setConnectionPasswordAnnotation(request *dpbackup.Request) error {
-	encryptPassword := func() (string, error) {
-		target := request.BackupPolicy.Spec.Target
-		if target == nil || target.ConnectionCredential == nil {
-			return "", nil
-		}
-		secret := &corev1.Secret{}
-		if err := request.Client.Get(request.Ctx, client.ObjectKey{Name: target.ConnectionCredential.SecretName, Namespace: request.Namespace}, secret); err != nil {
-			return "", err
+// updateBackupProgress updates the progress of the backup to the mean of the progress of the
+// actions which back up the data, the completed actions are counted as 100.
+func updateBackupProgress(backupStatus *dpv1alpha1.BackupStatus) {
+	var total, count int32
+	for _, act := range backupStatus.Actions {
+		if !dpbackup.IsBackupDataAction(act.Name) {
+			continue
 		}
-		e := intctrlutil.NewEncryptor(viper.GetString(constant.CfgKeyDPEncryptionKey))
-		ciphertext, err := e.Encrypt(secret.Data[target.ConnectionCredential.PasswordKey])
-		if err != nil {
-			return "", err
+		count++
+		if act.Phase == dpv1alpha1.ActionPhaseCompleted {
+			total += 100
+		} else {
+			total += act.Progress
 		}
-		return ciphertext, nil
 	}
-	// save the connection credential password for cluster.
-	ciphertext, err := encryptPassword()
-	if err != nil {
-		return err
+	if count > 0 {
+		backupStatus.Progress = total / count
+	}
+}
+
+// encryptConnectionPassword encrypts the password of the connection credential of the backup target,
+// it returns an empty string if the target has no connection credential.
+func encryptConnectionPassword(request *dpbackup.Request) (string, error) {
+	target := request.BackupPolicy.Spec.Target
+	if target == nil || target.ConnectionCredential == nil {
+		return "", nil
 	}
-	if ciphertext != "" {
-		request.Backup.Annotations[dptypes.ConnectionPasswordAnnotationKey] = ciphertext
+	secret := &corev1.Secret{}
+	if err := request.Client.Get(request.Ctx, client.ObjectKey{Name: target.ConnectionCredential.SecretName, Namespace: request.Namespace}, secret); err != nil {
+		return "", err
 	}
-	return nil
+	algorithm := dputils.GetConnectionPasswordEncryptionAlgorithm(request.EncryptionConfig)
+	e, err := intctrlutil.NewEncryptorWithAlgorithm(viper.GetString(constant.CfgKeyDPEncryptionKey), algorithm)
+	if err != nil {
+		return "", err
+	}
+	return e.Encrypt(secret.Data[target.ConnectionCredential.PasswordKey])
 }
 
 // getClusterObjectString gets the cluster object and convert it to string.
@@ -746,19 +2561,3 @@ func getClusterObjectString(cluster *appsv1alpha1.Cluster) (*string, error) {
 	clusterString := string(clusterBytes)
 	return &clusterString, nil
 }
-
-// setClusterSnapshotAnnotation sets the snapshot of cluster to the backup's annotations.
-func setClusterSnapshotAnnotation(backup *dpv1alpha1.Backup, cluster *appsv1alpha1.Cluster) error {
-	clusterString, err := getClusterObjectString(cluster)
-	if err != nil {
-		return err
-	}
-	if clusterString == nil {
-		return nil
-	}
-	if backup.Annotations == nil {
-		backup.Annotations = map[string]string{}
-	}
-	backup.Annotations[constant.ClusterSnapshotAnnotationKey] = *clusterString
-	return nil
-}
@@ -28,11 +28,18 @@ import (
 	. "github.com/onsi/gomega"
 
 	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
@@ -40,8 +47,10 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
+	dperrors "github.com/apecloud/kubeblocks/pkg/dataprotection/errors"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	"github.com/apecloud/kubeblocks/pkg/testutil"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
@@ -72,6 +81,7 @@ var _ = Describe("Backup Controller test", func() {
 		// the job and get the job list, resulting the ClearResources panic.
 		Eventually(testapps.List(&testCtx, generics.BackupSignature, inNS)).Should(HaveLen(0))
 		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupScheduleSignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupPolicySignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.JobSignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeClaimSignature, true, inNS)
@@ -147,8 +157,13 @@ var _ = Describe("Backup Controller test", func() {
 				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
 					g.Expect(fetched.Status.PersistentVolumeClaimName).Should(Equal(repoPVCName))
 					g.Expect(fetched.Status.Path).Should(Equal(dpbackup.BuildBackupPath(fetched, backupPolicy.Spec.PathPrefix)))
+					g.Expect(fetched.Status.Path).Should(ContainSubstring(string(cluster.UID)))
 					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.TargetPods).Should(Equal([]string{targetPod.Name}))
 					g.Expect(fetched.Annotations[dptypes.ConnectionPasswordAnnotationKey]).ShouldNot(BeEmpty())
+					g.Expect(fetched.Status.ActionSetName).Should(Equal(testdp.ActionSetName))
+					g.Expect(fetched.Status.ActionSetHash).ShouldNot(BeEmpty())
+					g.Expect(fetched.Annotations[dptypes.ActionSetHashAnnotationKey]).Should(Equal(fetched.Status.ActionSetHash))
 				})).Should(Succeed())
 
 				By("check backup job's nodeName equals pod's nodeName")
@@ -156,7 +171,7 @@ var _ = Describe("Backup Controller test", func() {
 					g.Expect(fetched.Spec.Template.Spec.NodeSelector[corev1.LabelHostname]).To(Equal(targetPod.Spec.NodeName))
 					// image should be expanded by env
 					g.Expect(fetched.Spec.Template.Spec.Containers[0].Image).Should(ContainSubstring(testdp.ImageTag))
-					g.Expect(fetched.Spec.Template.Spec.ServiceAccountName).Should(Equal(viper.GetString(dptypes.CfgKeyWorkerServiceAccountName)))
+					g.Expect(fetched.Spec.Template.Spec.ServiceAccountName).Should(Equal(dpbackup.GenerateWorkerRBACName(testdp.BackupPolicyName)))
 				})).Should(Succeed())
 
 				testdp.PatchK8sJobStatus(&testCtx, getJobKey(), batchv1.JobComplete)
@@ -171,6 +186,7 @@ var _ = Describe("Backup Controller test", func() {
 				By("backup should have completed")
 				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
 					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.CompletionReason).Should(Equal(dpv1alpha1.BackupCompletionReasonActionsCompleted))
 					g.Expect(fetched.Labels[dptypes.ClusterUIDLabelKey]).Should(Equal(string(cluster.UID)))
 					g.Expect(fetched.Labels[constant.AppInstanceLabelKey]).Should(Equal(testdp.ClusterName))
 					g.Expect(fetched.Labels[constant.KBAppComponentLabelKey]).Should(Equal(testdp.ComponentName))
@@ -182,6 +198,36 @@ var _ = Describe("Backup Controller test", func() {
 				Eventually(testapps.CheckObjExists(&testCtx, getJobKey(), &batchv1.Job{}, false)).Should(Succeed())
 			})
 
+			It("should adopt the existing job if the action statuses are lost", func() {
+				By("wait for the backup job to be created")
+				Eventually(testapps.CheckObjExists(&testCtx, getJobKey(), &batchv1.Job{}, true)).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Actions).ShouldNot(BeEmpty())
+					g.Expect(fetched.Status.Actions[0].ObjectRef).ShouldNot(BeNil())
+				})).Should(Succeed())
+
+				By("wipe the action statuses of the backup")
+				Eventually(testapps.GetAndChangeObjStatus(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Status.Actions = nil
+				})).Should(Succeed())
+
+				By("the action statuses should be reconstructed from the existing job")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Actions).Should(HaveLen(1))
+					g.Expect(fetched.Status.Actions[0].Phase).Should(Equal(dpv1alpha1.ActionPhaseRunning))
+					g.Expect(fetched.Status.Actions[0].ObjectRef).ShouldNot(BeNil())
+					g.Expect(fetched.Status.Actions[0].ObjectRef.Name).Should(Equal(getJobKey().Name))
+				})).Should(Succeed())
+
+				By("no duplicate backup job should be created")
+				Consistently(func(g Gomega) {
+					jobs := &batchv1.JobList{}
+					g.Expect(k8sClient.List(ctx, jobs, client.InNamespace(backup.Namespace),
+						client.MatchingLabels{dptypes.BackupNameLabelKey: backup.Name})).Should(Succeed())
+					g.Expect(jobs.Items).Should(HaveLen(1))
+				}).Should(Succeed())
+			})
+
 			It("should fail after job fails", func() {
 				testdp.PatchK8sJobStatus(&testCtx, getJobKey(), batchv1.JobFailed)
 
@@ -194,10 +240,757 @@ var _ = Describe("Backup Controller test", func() {
 				By("check backup failed")
 				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
 					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureCode).To(Equal(dpv1alpha1.BackupFailureCodeActionFailed))
+				})).Should(Succeed())
+			})
+		})
+
+		Context("creates an incremental backup", func() {
+			const (
+				incActionSetName    = "incremental-backup"
+				incBackupMethodName = "incremental"
+				fullBackupName      = "full-backup"
+				incBackupName       = "incremental-backup"
+			)
+			var (
+				fullBackup    *dpv1alpha1.Backup
+				fullBackupKey client.ObjectKey
+				incBackupKey  client.ObjectKey
+			)
+
+			getJobKey := func(backup *dpv1alpha1.Backup) client.ObjectKey {
+				return client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
+				}
+			}
+
+			newIncrementalBackup := func(parentBackupName string) *dpv1alpha1.Backup {
+				return testdp.NewBackupFactory(testCtx.DefaultNamespace, incBackupName).
+					SetBackupPolicyName(testdp.BackupPolicyName).
+					SetBackupMethod(incBackupMethodName).
+					Apply(func(backup *dpv1alpha1.Backup) {
+						backup.Spec.ParentBackupName = parentBackupName
+					}).
+					Create(&testCtx).GetObject()
+			}
+
+			BeforeEach(func() {
+				By("creating an incremental actionSet and the backup method using it")
+				incActionSet := testdp.NewActionSetFactory(incActionSetName).
+					SetBackupType(dpv1alpha1.BackupTypeIncremental).
+					SetBackupData(testdp.ImageTag, true, "sh", "-c", "exit 0").
+					Create(&testCtx).GetObject()
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(incActionSet),
+					func(g Gomega, fetched *dpv1alpha1.ActionSet) {
+						g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.AvailablePhase))
+					})).Should(Succeed())
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(fetched *dpv1alpha1.BackupPolicy) {
+					method := *fetched.Spec.BackupMethods[0].DeepCopy()
+					method.Name = incBackupMethodName
+					method.ActionSetName = incActionSetName
+					fetched.Spec.BackupMethods = append(fetched.Spec.BackupMethods, method)
+				})).Should(Succeed())
+
+				By("creating a completed full backup")
+				fullBackup = testdp.NewBackupFactory(testCtx.DefaultNamespace, fullBackupName).
+					SetBackupPolicyName(testdp.BackupPolicyName).
+					SetBackupMethod(testdp.BackupMethodName).
+					Create(&testCtx).GetObject()
+				fullBackupKey = client.ObjectKeyFromObject(fullBackup)
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(fullBackup), batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, fullBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Labels[dptypes.BackupTypeLabelKey]).Should(Equal(string(dpv1alpha1.BackupTypeFull)))
+					fullBackup = fetched
+				})).Should(Succeed())
+				incBackupKey = client.ObjectKey{Name: incBackupName, Namespace: testCtx.DefaultNamespace}
+			})
+
+			It("should build on the latest completed full backup", func() {
+				By("creating an incremental backup without the parent backup")
+				newIncrementalBackup("")
+
+				By("the parent backup should be resolved to the full backup")
+				Eventually(testapps.CheckObj(&testCtx, incBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.ParentBackupName).Should(Equal(fullBackupName))
+					g.Expect(fetched.Status.BaseBackupUID).Should(Equal(fullBackup.UID))
+					g.Expect(fetched.Labels[dptypes.BackupTypeLabelKey]).Should(Equal(string(dpv1alpha1.BackupTypeIncremental)))
+				})).Should(Succeed())
+
+				By("the backup job should build on the data of the parent backup")
+				incBackup := &dpv1alpha1.Backup{}
+				Expect(k8sClient.Get(ctx, incBackupKey, incBackup)).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, getJobKey(incBackup), func(g Gomega, fetched *batchv1.Job) {
+					env := fetched.Spec.Template.Spec.Containers[0].Env
+					g.Expect(env).Should(ContainElement(corev1.EnvVar{Name: dptypes.DPParentBackupName, Value: fullBackupName}))
+					g.Expect(env).Should(ContainElement(corev1.EnvVar{Name: dptypes.DPParentBackupBasePath, Value: fullBackup.Status.Path}))
+					g.Expect(env).Should(ContainElement(corev1.EnvVar{Name: dptypes.DPParentKopiaRepoPath, Value: fullBackup.Status.KopiaRepoPath}))
+				})).Should(Succeed())
+
+				By("the incremental backup should complete after the job completes")
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(incBackup), batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, incBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.ParentBackupName).Should(Equal(fullBackupName))
+				})).Should(Succeed())
+			})
+
+			It("should fail if the specified parent backup does not exist", func() {
+				newIncrementalBackup("non-existent")
+				Eventually(testapps.CheckObj(&testCtx, incBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureReason).Should(ContainSubstring("non-existent"))
+				})).Should(Succeed())
+			})
+
+			It("should keep the full backup until the dependent incremental backups are deleted", func() {
+				By("creating an incremental backup building on the full backup")
+				incBackup := newIncrementalBackup(fullBackupName)
+				Eventually(testapps.CheckObj(&testCtx, incBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.ParentBackupName).Should(Equal(fullBackupName))
+					g.Expect(fetched.Labels[dptypes.BackupTypeLabelKey]).Should(Equal(string(dpv1alpha1.BackupTypeIncremental)))
+				})).Should(Succeed())
+
+				By("deleting the full backup, its files should be kept")
+				testapps.DeleteObject(&testCtx, fullBackupKey, &dpv1alpha1.Backup{})
+				Eventually(testapps.CheckObj(&testCtx, fullBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseDeleting))
+				})).Should(Succeed())
+				fullDeleteJobKey := dpbackup.BuildDeleteBackupFilesJobKey(fullBackup, false)
+				Consistently(testapps.CheckObjExists(&testCtx, fullDeleteJobKey, &batchv1.Job{}, false)).Should(Succeed())
+
+				By("deleting the incremental backup, the files of the full backup should be deleted then")
+				testapps.DeleteObject(&testCtx, incBackupKey, &dpv1alpha1.Backup{})
+				incDeleteJobKey := dpbackup.BuildDeleteBackupFilesJobKey(incBackup, false)
+				Eventually(testapps.CheckObjExists(&testCtx, incDeleteJobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.ReplaceK8sJobStatus(&testCtx, incDeleteJobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObjExists(&testCtx, incBackupKey, &dpv1alpha1.Backup{}, false)).Should(Succeed())
+				Eventually(testapps.CheckObjExists(&testCtx, fullDeleteJobKey, &batchv1.Job{}, true)).Should(Succeed())
+			})
+
+			It("should delete the full backup with dependent incremental backups if it is forced", func() {
+				By("creating an incremental backup building on the full backup")
+				newIncrementalBackup(fullBackupName)
+				Eventually(testapps.CheckObj(&testCtx, incBackupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Labels[dptypes.BackupTypeLabelKey]).Should(Equal(string(dpv1alpha1.BackupTypeIncremental)))
+				})).Should(Succeed())
+
+				By("force deleting the full backup")
+				Expect(testapps.ChangeObj(&testCtx, fullBackup, func(fetched *dpv1alpha1.Backup) {
+					if fetched.Annotations == nil {
+						fetched.Annotations = map[string]string{}
+					}
+					fetched.Annotations[dptypes.ForceDeleteAnnotationKey] = "true"
+				})).Should(Succeed())
+				testapps.DeleteObject(&testCtx, fullBackupKey, &dpv1alpha1.Backup{})
+				fullDeleteJobKey := dpbackup.BuildDeleteBackupFilesJobKey(fullBackup, false)
+				Eventually(testapps.CheckObjExists(&testCtx, fullDeleteJobKey, &batchv1.Job{}, true)).Should(Succeed())
+			})
+		})
+
+		Context("creates a backup with verification policy", func() {
+			It("should verify the completed backup", func() {
+				By("add verify action to the actionSet")
+				Eventually(testapps.GetAndChangeObj(&testCtx, client.ObjectKey{Name: testdp.ActionSetName},
+					func(fetched *dpv1alpha1.ActionSet) {
+						fetched.Spec.Backup.VerifyBackup = &dpv1alpha1.BaseJobActionSpec{
+							Image:   testdp.ImageTag,
+							Command: []string{"sh", "-c", "exit 0"},
+						}
+					})).Should(Succeed())
+
+				By("enable verification for the backupPolicy")
+				Eventually(testapps.GetAndChangeObj(&testCtx, client.ObjectKeyFromObject(backupPolicy),
+					func(fetched *dpv1alpha1.BackupPolicy) {
+						fetched.Spec.VerificationPolicy = &dpv1alpha1.VerificationPolicy{
+							CronExpression:  "0 * * * *",
+							ActionSetName:   testdp.ActionSetName,
+							UpdateCondition: true,
+						}
+					})).Should(Succeed())
+
+				By("create a backup and complete the backup job")
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				backupKey := client.ObjectKeyFromObject(backup)
+				jobKey := client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
+				}
+				testdp.PatchK8sJobStatus(&testCtx, jobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+				})).Should(Succeed())
+
+				By("the verification job should be created and completed")
+				verifyJobKey := dpbackup.BuildVerifyBackupJobKey(backup)
+				Eventually(testapps.CheckObjExists(&testCtx, verifyJobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.PatchK8sJobStatus(&testCtx, verifyJobKey, batchv1.JobComplete)
+
+				By("the backup verification status should be passed")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Verification).ShouldNot(BeNil())
+					g.Expect(fetched.Status.Verification.Result).To(Equal(dpv1alpha1.BackupVerificationResultPassed))
+					g.Expect(fetched.Status.Verification.LastVerifiedTime).ShouldNot(BeNil())
+					cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypeVerified)
+					g.Expect(cond).ShouldNot(BeNil())
+					g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				})).Should(Succeed())
+			})
+
+			It("should enqueue the throttled verifications once the running backup is finished", func() {
+				newBackup := func(name, repoName string, phase dpv1alpha1.BackupPhase) *dpv1alpha1.Backup {
+					backup := testdp.NewBackupFactory(testCtx.DefaultNamespace, name).
+						SetPhase(phase).
+						GetObject()
+					backup.Status.BackupRepoName = repoName
+					return backup
+				}
+				reconciler := &BackupReconciler{}
+				reconciler.verificationThrottle.add(newBackup("throttled-0", "repo", dpv1alpha1.BackupPhaseCompleted))
+				reconciler.verificationThrottle.add(newBackup("throttled-1", "repo", dpv1alpha1.BackupPhaseCompleted))
+				reconciler.verificationThrottle.add(newBackup("throttled-2", "other-repo", dpv1alpha1.BackupPhaseCompleted))
+				reconciler.verificationThrottle.add(newBackup("verifying", "repo", dpv1alpha1.BackupPhaseCompleted))
+				reconciler.verificationThrottle.remove(newBackup("verifying", "repo", dpv1alpha1.BackupPhaseCompleted))
+
+				h := reconciler.verificationThrottleHandler()
+				q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+				defer q.ShutDown()
+				running := newBackup("running", "repo", dpv1alpha1.BackupPhaseRunning)
+
+				By("the backup is still running")
+				h.Update(ctx, event.UpdateEvent{ObjectOld: running, ObjectNew: running.DeepCopy()}, q)
+				Expect(q.Len()).Should(BeZero())
+
+				By("the backup is completed")
+				completed := running.DeepCopy()
+				completed.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+				h.Update(ctx, event.UpdateEvent{ObjectOld: running, ObjectNew: completed}, q)
+				Expect(q.Len()).Should(Equal(2))
+				var names []string
+				for q.Len() > 0 {
+					item, _ := q.Get()
+					names = append(names, item.(reconcile.Request).Name)
+					q.Done(item)
+				}
+				Expect(names).Should(ConsistOf("throttled-0", "throttled-1"))
+
+				By("the backups are released only once")
+				h.Update(ctx, event.UpdateEvent{ObjectOld: running, ObjectNew: completed}, q)
+				Expect(q.Len()).Should(BeZero())
+
+				By("the running backup in another repo is deleted")
+				h.Delete(ctx, event.DeleteEvent{Object: newBackup("running", "other-repo", dpv1alpha1.BackupPhaseRunning)}, q)
+				Expect(q.Len()).Should(Equal(1))
+			})
+		})
+
+		Context("creates a backup with propagated cluster labels", func() {
+			It("should propagate the configured cluster labels to the backup", func() {
+				viper.Set(dptypes.CfgKeyPropagateClusterLabels, "cost-center, environment")
+				defer viper.Set(dptypes.CfgKeyPropagateClusterLabels, "")
+
+				By("add labels to the cluster")
+				Expect(testapps.ChangeObj(&testCtx, cluster, func(obj *appsv1alpha1.Cluster) {
+					if obj.Labels == nil {
+						obj.Labels = map[string]string{}
+					}
+					obj.Labels["cost-center"] = "cc-1"
+					obj.Labels["environment"] = "prod"
+					obj.Labels["team"] = "dba"
+				})).Should(Succeed())
+
+				By("the configured cluster labels should be set to the backup")
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Labels).Should(HaveKeyWithValue("cost-center", "cc-1"))
+					g.Expect(fetched.Labels).Should(HaveKeyWithValue("environment", "prod"))
+					g.Expect(fetched.Labels).ShouldNot(HaveKey("team"))
 				})).Should(Succeed())
 			})
 		})
 
+		Context("creates a continuous backup", func() {
+			var backupKey client.ObjectKey
+
+			BeforeEach(func() {
+				By("change the backup type of the actionSet to Continuous")
+				Eventually(testapps.GetAndChangeObj(&testCtx, client.ObjectKey{Name: testdp.ActionSetName},
+					func(fetched *dpv1alpha1.ActionSet) {
+						fetched.Spec.BackupType = dpv1alpha1.BackupTypeContinuous
+					})).Should(Succeed())
+
+				By("enable the continuous backup schedule")
+				schedule := testdp.NewFakeBackupSchedule(&testCtx, func(schedule *dpv1alpha1.BackupSchedule) {
+					schedule.Spec.Schedules[0].Enabled = boolptr.True()
+				})
+				backupKey = client.ObjectKey{
+					Name:      dpbackup.GenerateCRNameByBackupSchedule(schedule, testdp.BackupMethodName),
+					Namespace: schedule.Namespace,
+				}
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+				})).Should(Succeed())
+			})
+
+			It("should pause and resume the continuous backup", func() {
+				checkReplicas := func(replicas int32) {
+					Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *appsv1.StatefulSet) {
+						g.Expect(fetched.Spec.Replicas).ShouldNot(BeNil())
+						g.Expect(*fetched.Spec.Replicas).Should(Equal(replicas))
+					})).Should(Succeed())
+				}
+				checkReplicas(1)
+
+				By("mock the time range of the continuous backup")
+				start := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+				end := metav1.NewTime(time.Now().Truncate(time.Second))
+				Eventually(testapps.GetAndChangeObjStatus(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Status.TimeRange = &dpv1alpha1.BackupTimeRange{Start: &start, End: &end}
+				})).Should(Succeed())
+				checkTimeRange := func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.TimeRange).ShouldNot(BeNil())
+					g.Expect(fetched.Status.TimeRange.Start.Equal(&start)).Should(BeTrue())
+					g.Expect(fetched.Status.TimeRange.End.Equal(&end)).Should(BeTrue())
+				}
+
+				By("pause the continuous backup")
+				Eventually(testapps.GetAndChangeObj(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Spec.Paused = true
+				})).Should(Succeed())
+				checkReplicas(0)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(meta.IsStatusConditionTrue(fetched.Status.Conditions, ConditionTypePaused)).Should(BeTrue())
+					checkTimeRange(g, fetched)
+				})).Should(Succeed())
+
+				By("resume the continuous backup")
+				Eventually(testapps.GetAndChangeObj(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Spec.Paused = false
+				})).Should(Succeed())
+				checkReplicas(1)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypePaused)).Should(BeNil())
+					checkTimeRange(g, fetched)
+				})).Should(Succeed())
+			})
+
+			It("should record the completion reason and resume into the same path", func() {
+				backup := &dpv1alpha1.Backup{}
+				Expect(k8sClient.Get(ctx, backupKey, backup)).Should(Succeed())
+				archivePath := backup.Status.Path
+				Expect(archivePath).ShouldNot(BeEmpty())
+
+				By("disable the continuous backup schedule")
+				scheduleKey := client.ObjectKey{Name: testdp.BackupScheduleName, Namespace: testCtx.DefaultNamespace}
+				Eventually(testapps.GetAndChangeObj(&testCtx, scheduleKey, func(fetched *dpv1alpha1.BackupSchedule) {
+					fetched.Spec.Schedules[0].Enabled = boolptr.False()
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.CompletionReason).Should(Equal(dpv1alpha1.BackupCompletionReasonScheduleDisabled))
+					// the resumable backup is kept for a retention period since the completion
+					retention, err := fetched.Spec.RetentionPeriod.ToDuration()
+					g.Expect(err).ShouldNot(HaveOccurred())
+					g.Expect(retention).Should(BeNumerically(">", 0))
+					g.Expect(fetched.Status.Expiration).ShouldNot(BeNil())
+					g.Expect(fetched.Status.Expiration.Time).Should(Equal(fetched.Status.CompletionTimestamp.Add(retention)))
+					g.Expect(fetched.Annotations).Should(HaveKeyWithValue(dptypes.ResumeArchivePathAnnotationKey, archivePath))
+					cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypeCompleted)
+					g.Expect(cond).ShouldNot(BeNil())
+					g.Expect(cond.Reason).Should(Equal(string(dpv1alpha1.BackupCompletionReasonScheduleDisabled)))
+				})).Should(Succeed())
+
+				By("enable the continuous backup schedule again")
+				Eventually(testapps.GetAndChangeObj(&testCtx, scheduleKey, func(fetched *dpv1alpha1.BackupSchedule) {
+					fetched.Spec.Schedules[0].Enabled = boolptr.True()
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.CompletionReason).Should(BeEmpty())
+					g.Expect(fetched.Status.Path).Should(Equal(archivePath))
+					g.Expect(fetched.Annotations).ShouldNot(HaveKey(dptypes.ResumeArchivePathAnnotationKey))
+				})).Should(Succeed())
+			})
+
+			It("should trim the data out of the retention period", func() {
+				By("add trim action to the actionSet")
+				Eventually(testapps.GetAndChangeObj(&testCtx, client.ObjectKey{Name: testdp.ActionSetName},
+					func(fetched *dpv1alpha1.ActionSet) {
+						fetched.Spec.Backup.TrimBackup = &dpv1alpha1.BaseJobActionSpec{
+							Image:   testdp.ImageTag,
+							Command: []string{"sh", "-c", "exit 0"},
+						}
+					})).Should(Succeed())
+
+				By("mock the time range of the continuous backup out of the retention period")
+				start := metav1.NewTime(time.Now().Add(-3 * time.Hour).Truncate(time.Second))
+				end := metav1.NewTime(time.Now().Truncate(time.Second))
+				Eventually(testapps.GetAndChangeObjStatus(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Status.TimeRange = &dpv1alpha1.BackupTimeRange{Start: &start, End: &end}
+				})).Should(Succeed())
+				Eventually(testapps.GetAndChangeObj(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Spec.RetentionPeriod = "1h"
+				})).Should(Succeed())
+
+				By("check the backup workload is scaled down and the trim job is created")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *appsv1.StatefulSet) {
+					g.Expect(*fetched.Spec.Replicas).Should(BeEquivalentTo(0))
+				})).Should(Succeed())
+				backup := &dpv1alpha1.Backup{}
+				Expect(k8sClient.Get(ctx, backupKey, backup)).Should(Succeed())
+				trimBefore := backup.Annotations[dptypes.TrimBeforeAnnotationKey]
+				Expect(trimBefore).ShouldNot(BeEmpty())
+				trimJobKey := dpbackup.BuildTrimBackupFilesJobKey(backup)
+				Eventually(testapps.CheckObj(&testCtx, trimJobKey, func(g Gomega, fetched *batchv1.Job) {
+					g.Expect(fetched.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(
+						corev1.EnvVar{Name: dptypes.DPBackupTrimBeforeTime, Value: trimBefore}))
+				})).Should(Succeed())
+
+				By("mock the trim job completed")
+				testdp.PatchK8sJobStatus(&testCtx, trimJobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Annotations).ShouldNot(HaveKey(dptypes.TrimBeforeAnnotationKey))
+					g.Expect(meta.IsStatusConditionTrue(fetched.Status.Conditions, ConditionTypeTrimmed)).Should(BeTrue())
+					g.Expect(fetched.Status.TimeRange.Start.UTC().Format(time.RFC3339)).Should(Equal(trimBefore))
+					g.Expect(fetched.Status.TimeRange.End.Equal(&end)).Should(BeTrue())
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *appsv1.StatefulSet) {
+					g.Expect(*fetched.Spec.Replicas).Should(BeEquivalentTo(1))
+				})).Should(Succeed())
+			})
+
+			Context("deletes the continuous backup", func() {
+				var (
+					pvcKey        client.ObjectKey
+					mountedPVCKey client.ObjectKey
+				)
+
+				BeforeEach(func() {
+					By("mock the PVCs created by the backup workload")
+					backup := &dpv1alpha1.Backup{}
+					Expect(k8sClient.Get(ctx, backupKey, backup)).Should(Succeed())
+					createPVC := func(name string) client.ObjectKey {
+						pvc := testapps.NewPersistentVolumeClaimFactory(backupKey.Namespace, name, "", "", "data").
+							AddLabelsInMap(dpbackup.BuildBackupWorkloadLabels(backup)).
+							SetStorage("1Gi").
+							Create(&testCtx).GetObject()
+						return client.ObjectKeyFromObject(pvc)
+					}
+					pvcKey = createPVC(backupKey.Name + "-data-0")
+					mountedPVCKey = createPVC(backupKey.Name + "-data-1")
+
+					By("mock a pod mounting the PVC")
+					testapps.NewPodFactory(backupKey.Namespace, backupKey.Name+"-mount").
+						AddContainer(corev1.Container{Name: "mount", Image: testdp.ImageTag}).
+						AddVolume(corev1.Volume{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: mountedPVCKey.Name,
+								},
+							},
+						}).
+						Create(&testCtx)
+				})
+
+				checkPVCDeleted := func(key client.ObjectKey, deleted bool) func(g Gomega) {
+					return func(g Gomega) {
+						pvc := &corev1.PersistentVolumeClaim{}
+						err := k8sClient.Get(ctx, key, pvc)
+						if deleted && apierrors.IsNotFound(err) {
+							return
+						}
+						g.Expect(err).Should(Succeed())
+						g.Expect(pvc.DeletionTimestamp.IsZero()).Should(Equal(!deleted))
+					}
+				}
+
+				It("should delete the PVCs not mounted by pods after the backup workload is deleted", func() {
+					By("delete the backup")
+					testapps.DeleteObject(&testCtx, backupKey, &dpv1alpha1.Backup{})
+					Eventually(testapps.CheckObjExists(&testCtx, backupKey, &appsv1.StatefulSet{}, false)).Should(Succeed())
+
+					By("check the PVCs")
+					Eventually(checkPVCDeleted(pvcKey, true)).Should(Succeed())
+					Consistently(checkPVCDeleted(mountedPVCKey, false)).Should(Succeed())
+
+					By("check the backup is kept until the mounted PVC is deleted")
+					Consistently(testapps.CheckObjExists(&testCtx, backupKey, &dpv1alpha1.Backup{}, true)).Should(Succeed())
+					testapps.DeleteObject(&testCtx, client.ObjectKey{Namespace: backupKey.Namespace, Name: backupKey.Name + "-mount"}, &corev1.Pod{})
+					Eventually(checkPVCDeleted(mountedPVCKey, true)).Should(Succeed())
+				})
+
+				It("should retain the PVCs if the deletionPolicy is Retain", func() {
+					By("set the deletionPolicy to Retain and delete the backup")
+					Eventually(testapps.GetAndChangeObj(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+						fetched.Spec.DeletionPolicy = dpv1alpha1.BackupDeletionPolicyRetain
+					})).Should(Succeed())
+					testapps.DeleteObject(&testCtx, backupKey, &dpv1alpha1.Backup{})
+					Eventually(testapps.CheckObjExists(&testCtx, backupKey, &appsv1.StatefulSet{}, false)).Should(Succeed())
+
+					By("check the PVCs are retained")
+					Consistently(checkPVCDeleted(pvcKey, false)).Should(Succeed())
+					Consistently(checkPVCDeleted(mountedPVCKey, false)).Should(Succeed())
+				})
+			})
+		})
+
+		Context("creates a backup with backup hooks", func() {
+			getJobKey := func(backup *dpv1alpha1.Backup, name string) client.ObjectKey {
+				return client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, name),
+					Namespace: backup.Namespace,
+				}
+			}
+
+			setBackupHooks := func() {
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					hook := &dpv1alpha1.BackupHook{
+						Target:  dpv1alpha1.BackupHookTargetWorkerJob,
+						Image:   testdp.KBToolImage,
+						Command: []string{"sh", "-c", "echo hook"},
+					}
+					bp.Spec.BackupMethods[0].PreBackupHook = hook
+					bp.Spec.BackupMethods[0].PostBackupHook = hook.DeepCopy()
+				})).Should(Succeed())
+			}
+
+			It("should fail before backing up data if the pre-backup hook fails", func() {
+				setBackupHooks()
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				preHookJobKey := getJobKey(backup, "dp-prebackuphook-0")
+
+				By("the pre-backup hook action should be the first action")
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Actions).Should(HaveLen(3))
+					g.Expect(fetched.Status.Actions[0].Name).Should(Equal("dp-prebackuphook-0"))
+					g.Expect(fetched.Status.Actions[2].Name).Should(Equal("dp-postbackuphook-0"))
+				})).Should(Succeed())
+				testdp.PatchK8sJobStatus(&testCtx, preHookJobKey, batchv1.JobFailed)
+
+				By("check backup failed and no backup data job is created")
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+				})).Should(Succeed())
+				Eventually(testapps.CheckObjExists(&testCtx, getJobKey(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					&batchv1.Job{}, false)).Should(Succeed())
+			})
+
+			It("should run the post-backup hook before failing the backup if backing up data fails", func() {
+				setBackupHooks()
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				postHookJobKey := getJobKey(backup, "dp-postbackuphook-0")
+
+				By("mock the pre-backup hook job is completed and the backup data job is failed")
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(backup, "dp-prebackuphook-0"), batchv1.JobComplete)
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(backup, dpbackup.BackupDataJobNamePrefix+"-0"), batchv1.JobFailed)
+
+				By("check the post-backup hook job is created to release the barrier")
+				Eventually(testapps.CheckObjExists(&testCtx, postHookJobKey, &batchv1.Job{}, true)).Should(Succeed())
+				Consistently(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseRunning))
+				})).Should(Succeed())
+
+				By("check backup failed after the post-backup hook is completed")
+				testdp.PatchK8sJobStatus(&testCtx, postHookJobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.Actions[2].Phase).Should(Equal(dpv1alpha1.ActionPhaseCompleted))
+				})).Should(Succeed())
+			})
+
+			It("should complete with a warning condition if the post-backup hook fails", func() {
+				setBackupHooks()
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+
+				By("mock the pre-backup hook and backup data jobs are completed")
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(backup, "dp-prebackuphook-0"), batchv1.JobComplete)
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(backup, dpbackup.BackupDataJobNamePrefix+"-0"), batchv1.JobComplete)
+
+				By("mock the post-backup hook job is failed")
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(backup, "dp-postbackuphook-0"), batchv1.JobFailed)
+
+				By("check backup completed with the post-backup hook condition")
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.Actions[2].Phase).Should(Equal(dpv1alpha1.ActionPhaseFailed))
+					cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypePostBackupHookSucceeded)
+					g.Expect(cond).ShouldNot(BeNil())
+					g.Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+					g.Expect(cond.Reason).Should(Equal(ReasonPostBackupHookFailed))
+				})).Should(Succeed())
+			})
+		})
+
+		Context("creates a backup with retry policy", func() {
+			It("should retry the failed job until the retries are exhausted", func() {
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					bp.Spec.BackupMethods[0].RetryPolicy = &dpv1alpha1.BackupRetryPolicy{MaxRetries: 1}
+				})).Should(Succeed())
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				backupKey := client.ObjectKeyFromObject(backup)
+				jobKey := client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
+				}
+
+				By("mock the backup job is failed")
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.PatchK8sJobStatus(&testCtx, jobKey, batchv1.JobFailed)
+
+				By("the failed job should be deleted and the retry should be scheduled")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.Actions).Should(HaveLen(1))
+					g.Expect(fetched.Status.Actions[0].RetryCount).Should(BeEquivalentTo(1))
+					g.Expect(fetched.Status.Actions[0].NextRetryTimestamp).ShouldNot(BeNil())
+				})).Should(Succeed())
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+
+				By("skip the backoff, and the job should be recreated")
+				Expect(testapps.GetAndChangeObjStatus(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Status.Actions[0].NextRetryTimestamp = &metav1.Time{Time: time.Now().Add(-time.Second)}
+				})()).Should(Succeed())
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+
+				By("the backup should fail with the accumulated reasons after the retries are exhausted")
+				testdp.PatchK8sJobStatus(&testCtx, jobKey, batchv1.JobFailed)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureCode).To(Equal(dpv1alpha1.BackupFailureCodeActionFailed))
+					g.Expect(fetched.Status.FailureReason).Should(ContainSubstring("retries exhausted after 2 attempts"))
+				})).Should(Succeed())
+			})
+		})
+
+		Context("creates a backup with completion deadline", func() {
+			It("should fail and delete the backup job after the deadline is exceeded", func() {
+				By("creating a backup with a short completion deadline")
+				backup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Spec.CompletionDeadline = &metav1.Duration{Duration: 2 * time.Second}
+				})
+				backupKey := client.ObjectKeyFromObject(backup)
+				jobKey := client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
+				}
+
+				By("check backup job is created")
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+
+				By("check backup failed after the deadline is exceeded without any job event")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureReason).Should(ContainSubstring("completion deadline"))
+					g.Expect(fetched.Status.FailureCode).Should(Equal(dpv1alpha1.BackupFailureCodeDeadlineExceeded))
+				})).Should(Succeed())
+
+				By("check backup job is deleted")
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+			})
+		})
+
+		Context("creates a dry-run backup", func() {
+			It("should complete without creating backup workloads", func() {
+				By("creating a dry-run backup")
+				backup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Spec.DryRun = true
+				})
+				backupKey := client.ObjectKeyFromObject(backup)
+
+				By("check backup completed with the dry-run condition")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.CompletionReason).To(Equal(dpv1alpha1.BackupCompletionReasonDryRun))
+					g.Expect(fetched.Status.TargetPods).Should(Equal([]string{targetPod.Name}))
+					g.Expect(fetched.Finalizers).Should(BeEmpty())
+					cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypeDryRunPassed)
+					g.Expect(cond).ShouldNot(BeNil())
+					g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+					g.Expect(cond.Message).Should(ContainSubstring(dpbackup.BackupDataJobNamePrefix))
+				})).Should(Succeed())
+
+				By("check backup job is not created")
+				jobKey := client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
+				}
+				Consistently(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+			})
+
+			It("should fail if the backup method is not found", func() {
+				By("creating a dry-run backup using a not found backup method")
+				backup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Spec.DryRun = true
+					backup.Spec.BackupMethod = "non-existent"
+				})
+				backupKey := client.ObjectKeyFromObject(backup)
+
+				By("check backup failed with the dry-run condition")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypeDryRunPassed)
+					g.Expect(cond).ShouldNot(BeNil())
+					g.Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+				})).Should(Succeed())
+			})
+		})
+
+		Context("updates the sync status of a continuous backup", func() {
+			newRequest := func(end time.Time, threshold time.Duration) *dpbackup.Request {
+				backup := testdp.NewBackupFactory(testCtx.DefaultNamespace, testdp.BackupName).
+					SetPhase(dpv1alpha1.BackupPhaseRunning).
+					SetBackupTimeRange(end, end).
+					GetObject()
+				backupPolicyFactory := testdp.NewBackupPolicyFactory(testCtx.DefaultNamespace, testdp.BackupPolicyName)
+				if threshold > 0 {
+					backupPolicyFactory.SetReplicationLagThreshold(threshold)
+				}
+				return &dpbackup.Request{Backup: backup, BackupPolicy: backupPolicyFactory.GetObject()}
+			}
+
+			It("should record the replication lag without threshold", func() {
+				recorder := record.NewFakeRecorder(1)
+				reconciler := &BackupReconciler{Recorder: recorder}
+				end := time.Now().Add(-time.Hour)
+				request := newRequest(end, 0)
+				Expect(reconciler.updateContinuousSyncStatus(request)).Should(BeZero())
+				Expect(request.Status.LastSyncTime.Time).Should(Equal(end))
+				Expect(request.Status.LatestReplicationLag.Duration).Should(BeNumerically("~", time.Hour, time.Second))
+				Expect(recorder.Events).Should(BeEmpty())
+			})
+
+			It("should emit a warning event if the replication lag exceeds the threshold", func() {
+				recorder := record.NewFakeRecorder(1)
+				reconciler := &BackupReconciler{Recorder: recorder}
+				threshold := 10 * time.Minute
+
+				By("the replication lag is within the threshold")
+				request := newRequest(time.Now().Add(-time.Minute), threshold)
+				Expect(reconciler.updateContinuousSyncStatus(request)).Should(BeNumerically("~", 9*time.Minute, time.Second))
+				Expect(recorder.Events).Should(BeEmpty())
+
+				By("the replication lag exceeds the threshold")
+				request = newRequest(time.Now().Add(-time.Hour), threshold)
+				Expect(reconciler.updateContinuousSyncStatus(request)).Should(Equal(threshold))
+				Expect(recorder.Events).Should(HaveLen(1))
+				Expect(<-recorder.Events).Should(ContainSubstring("ReplicationLagExceeded"))
+			})
+
+			It("should skip if no time range is published", func() {
+				reconciler := &BackupReconciler{Recorder: record.NewFakeRecorder(1)}
+				request := newRequest(time.Now(), 0)
+				request.Status.TimeRange = nil
+				Expect(reconciler.updateContinuousSyncStatus(request)).Should(BeZero())
+				Expect(request.Status.LastSyncTime).Should(BeNil())
+				Expect(request.Status.LatestReplicationLag).Should(BeNil())
+			})
+		})
+
 		Context("create an invalid backup", func() {
 			It("should fail if backupPolicy is not found", func() {
 				By("creating a backup using a not found backupPolicy")
@@ -221,12 +1014,44 @@ var _ = Describe("Backup Controller test", func() {
 					backup.Spec.RetentionPeriod = "1h"
 				})
 				backupKey := client.ObjectKeyFromObject(backup)
-
-				getJobKey := func() client.ObjectKey {
-					return client.ObjectKey{
-						Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
-						Namespace: backup.Namespace,
-					}
+
+				getJobKey := func() client.ObjectKey {
+					return client.ObjectKey{
+						Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+						Namespace: backup.Namespace,
+					}
+				}
+
+				By("check backup expiration is set by start time when backup is running")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.Expiration.Second()).Should(Equal(fetched.Status.StartTimestamp.Add(time.Hour).Second()))
+				})).Should(Succeed())
+
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(), batchv1.JobComplete)
+
+				By("check backup expiration is updated by completion time when backup is completed")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.CompletionTimestamp).ShouldNot(BeNil())
+					g.Expect(fetched.Status.Expiration.Second()).Should(Equal(fetched.Status.CompletionTimestamp.Add(time.Hour).Second()))
+				})).Should(Succeed())
+			})
+
+			It("updates the expiration by the failedRetentionPeriod when the running backup fails", func() {
+				By("set the failedRetentionPeriod of the backupPolicy")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					bp.Spec.FailedRetentionPeriod = "30m"
+				})).Should(Succeed())
+
+				By("creating a backup from backupPolicy " + testdp.BackupPolicyName)
+				backup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Spec.RetentionPeriod = "1h"
+				})
+				backupKey := client.ObjectKeyFromObject(backup)
+				jobKey := client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
 				}
 
 				By("check backup expiration is set by start time when backup is running")
@@ -235,13 +1060,13 @@ var _ = Describe("Backup Controller test", func() {
 					g.Expect(fetched.Status.Expiration.Second()).Should(Equal(fetched.Status.StartTimestamp.Add(time.Hour).Second()))
 				})).Should(Succeed())
 
-				testdp.PatchK8sJobStatus(&testCtx, getJobKey(), batchv1.JobComplete)
+				testdp.PatchK8sJobStatus(&testCtx, jobKey, batchv1.JobFailed)
 
-				By("check backup expiration is updated by completion time when backup is completed")
+				By("check backup expiration is updated by the failedRetentionPeriod when backup fails")
 				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
-					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
-					g.Expect(fetched.Status.CompletionTimestamp).ShouldNot(BeNil())
-					g.Expect(fetched.Status.Expiration.Second()).Should(Equal(fetched.Status.CompletionTimestamp.Add(time.Hour).Second()))
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.Expiration).ShouldNot(BeNil())
+					g.Expect(fetched.Status.Expiration.Time).Should(BeTemporally("~", time.Now().Add(30*time.Minute), time.Minute))
 				})).Should(Succeed())
 			})
 
@@ -279,12 +1104,158 @@ var _ = Describe("Backup Controller test", func() {
 				reqCtx := intctrlutil.RequestCtx{
 					Ctx: ctx,
 				}
-				targets, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy)
+				targets, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
 				Expect(err).ShouldNot(HaveOccurred())
 				Expect(targets).Should(HaveLen(1))
 				Expect(targets[0].Name).Should(Equal(testdp.ClusterName + "-" + testdp.ComponentName + "-1"))
 			})
 
+			It("should fall back to the fallbackRole if all followers are down", func() {
+				By("Set backupMethod's target with fallbackRole")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					backupPolicy.Spec.BackupMethods[0].Target = &dpv1alpha1.BackupTarget{
+						PodSelector: &dpv1alpha1.PodSelector{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									constant.AppInstanceLabelKey:    testdp.ClusterName,
+									constant.KBAppComponentLabelKey: testdp.ComponentName,
+									constant.RoleLabelKey:           constant.Follower,
+								},
+							},
+							FallbackRole: constant.Leader,
+						},
+					}
+				})).Should(Succeed())
+
+				By("mock the follower pod is not ready")
+				follower := &corev1.Pod{}
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: testdp.ClusterName + "-" + testdp.ComponentName + "-1",
+					Namespace: testCtx.DefaultNamespace}, follower)).Should(Succeed())
+				Expect(testapps.ChangeObjStatus(&testCtx, follower, func() {
+					follower.Status.Conditions = nil
+				})).Should(Succeed())
+
+				By("check targets pod is the leader")
+				reqCtx := intctrlutil.RequestCtx{
+					Ctx: ctx,
+				}
+				targets, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(targets).Should(HaveLen(1))
+				Expect(targets[0].Name).Should(Equal(targetPod.Name))
+
+				By("create a backup and check the target pod role is recorded")
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Annotations[dptypes.BackupTargetPodLabelKey]).Should(Equal(targetPod.Name))
+					g.Expect(fetched.Annotations[dptypes.BackupTargetPodRoleAnnotationKey]).Should(Equal(constant.Leader))
+				})).Should(Succeed())
+			})
+
+			It("should fail if no pod has the role label or the fallbackRole label", func() {
+				By("Set backupMethod's target with a missing role and fallbackRole")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					backupPolicy.Spec.BackupMethods[0].Target = &dpv1alpha1.BackupTarget{
+						PodSelector: &dpv1alpha1.PodSelector{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									constant.AppInstanceLabelKey:    testdp.ClusterName,
+									constant.KBAppComponentLabelKey: testdp.ComponentName,
+									constant.RoleLabelKey:           constant.Secondary,
+								},
+							},
+							FallbackRole: constant.Primary,
+						},
+					}
+				})).Should(Succeed())
+				By("check targets pod")
+				reqCtx := intctrlutil.RequestCtx{
+					Ctx: ctx,
+				}
+				_, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
+				Expect(err).Should(HaveOccurred())
+				Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeNoTargetPods)).Should(BeTrue())
+			})
+
+			It("should reject the target pods in another namespace if the cross-namespace backup is disabled", func() {
+				By("set the target namespace of the backup policy")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					bp.Spec.Target.Namespace = "tenant"
+				})).Should(Succeed())
+				reqCtx := intctrlutil.RequestCtx{
+					Ctx: ctx,
+				}
+				_, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).Should(ContainSubstring("cross-namespace backup is not enabled"))
+
+				By("enable the cross-namespace backup, the pods are looked up in the target namespace")
+				viper.Set(dptypes.CfgKeyEnableCrossNamespaceBackup, true)
+				defer viper.Set(dptypes.CfgKeyEnableCrossNamespaceBackup, false)
+				_, err = GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
+				Expect(err).Should(HaveOccurred())
+				Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeNoTargetPods)).Should(BeTrue())
+			})
+
+			It("should back up from a not ready target pod only if it is allowed", func() {
+				By("Set backupMethod's target to the follower")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					backupPolicy.Spec.BackupMethods[0].Target = &dpv1alpha1.BackupTarget{
+						PodSelector: &dpv1alpha1.PodSelector{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									constant.AppInstanceLabelKey:    testdp.ClusterName,
+									constant.KBAppComponentLabelKey: testdp.ComponentName,
+									constant.RoleLabelKey:           constant.Follower,
+								},
+							},
+						},
+					}
+				})).Should(Succeed())
+
+				By("mock the follower pod is not ready")
+				follower := &corev1.Pod{}
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Name: testdp.ClusterName + "-" + testdp.ComponentName + "-1",
+					Namespace: testCtx.DefaultNamespace}, follower)).Should(Succeed())
+				Expect(testapps.ChangeObjStatus(&testCtx, follower, func() {
+					follower.Status.Conditions = nil
+				})).Should(Succeed())
+
+				By("check the target pods are not ready")
+				reqCtx := intctrlutil.RequestCtx{
+					Ctx: ctx,
+				}
+				_, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
+				Expect(err).Should(HaveOccurred())
+				Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeTargetPodsNotReady)).Should(BeTrue())
+				targets, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, true)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(targets).Should(HaveLen(1))
+				Expect(targets[0].Name).Should(Equal(follower.Name))
+
+				By("the backup should fail if not ready target is not allowed")
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureReason).Should(ContainSubstring("are not ready"))
+					g.Expect(fetched.Status.FailureCode).Should(Equal(dpv1alpha1.BackupFailureCodeTargetPodNotReady))
+				})).Should(Succeed())
+
+				By("the backup should record the TargetReady condition if not ready target is allowed")
+				backup = testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Name += "-allow-not-ready"
+					backup.Spec.AllowNotReadyTarget = true
+				})
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.TargetPods).Should(ConsistOf(follower.Name))
+					cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypeTargetReady)
+					g.Expect(cond).ShouldNot(BeNil())
+					g.Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+					g.Expect(cond.Reason).Should(Equal(ReasonTargetPodNotReady))
+				})).Should(Succeed())
+			})
+
 			It("create an backup with backupMethod's and podSelection strategy is All", func() {
 				By("Set backupMethod's target and podSelection strategy to All")
 				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
@@ -304,7 +1275,7 @@ var _ = Describe("Backup Controller test", func() {
 				reqCtx := intctrlutil.RequestCtx{
 					Ctx: ctx,
 				}
-				targets, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy)
+				targets, err := GetTargetPods(reqCtx, k8sClient, "", &backupPolicy.Spec.BackupMethods[0], backupPolicy, false)
 				Expect(err).ShouldNot(HaveOccurred())
 				Expect(targets).Should(HaveLen(2))
 
@@ -326,27 +1297,18 @@ var _ = Describe("Backup Controller test", func() {
 					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
 					g.Expect(fetched.Status.CompletionTimestamp).ShouldNot(BeNil())
 					g.Expect(fetched.Status.Expiration.Second()).Should(Equal(fetched.Status.CompletionTimestamp.Add(time.Hour).Second()))
+					g.Expect(fetched.Status.TargetPods).Should(ConsistOf(targets[0].Name, targets[1].Name))
+					g.Expect(fetched.Annotations[dptypes.BackupTargetPodLabelKey]).Should(Equal(targets[0].Name))
 				})).Should(Succeed())
 			})
 		})
 
 		Context("creates a backup with encryption", func() {
-			const (
-				encryptionKeySecretName = "backup-encryption"
-				keyName                 = "password"
-			)
+			const encryptionKeySecretName = "backup-encryption"
 			It("should fail if encryption key secret is not present", func() {
 				By("set encryptionConfig")
 				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
-					backupPolicy.Spec.EncryptionConfig = &dpv1alpha1.EncryptionConfig{
-						Algorithm: "AES-256-CFB",
-						PassPhraseSecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: encryptionKeySecretName,
-							},
-							Key: keyName,
-						},
-					}
+					backupPolicy.Spec.EncryptionConfig = testdp.NewFakeEncryptionConfig(encryptionKeySecretName, "AES-256-CFB")
 				})).Should(Succeed())
 
 				By("create a backup")
@@ -355,21 +1317,14 @@ var _ = Describe("Backup Controller test", func() {
 				By("check the backup, and it should be failed")
 				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
 					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureCode).To(Equal(dpv1alpha1.BackupFailureCodeEncryptionKeyMissing))
 				})).Should(Succeed())
 			})
 
 			It("should run the backup with encryption envs", func() {
 				By("set encryptionConfig")
 				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
-					backupPolicy.Spec.EncryptionConfig = &dpv1alpha1.EncryptionConfig{
-						Algorithm: "AES-256-CFB",
-						PassPhraseSecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: encryptionKeySecretName,
-							},
-							Key: keyName,
-						},
-					}
+					backupPolicy.Spec.EncryptionConfig = testdp.NewFakeEncryptionConfig(encryptionKeySecretName, "AES-256-CFB")
 				})).Should(Succeed())
 
 				By("create the encryption key secret")
@@ -379,7 +1334,7 @@ var _ = Describe("Backup Controller test", func() {
 						Namespace: testCtx.DefaultNamespace,
 					},
 					StringData: map[string]string{
-						keyName: "whatever",
+						testdp.EncryptionKeyName: "whatever",
 					},
 				}
 				testapps.CreateK8sResource(&testCtx, secret)
@@ -417,6 +1372,89 @@ var _ = Describe("Backup Controller test", func() {
 					}
 				})).Should(Succeed())
 			})
+
+			createEncryptionKeySecret := func(name string) {
+				testapps.CreateK8sResource(&testCtx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: testCtx.DefaultNamespace,
+					},
+					StringData: map[string]string{
+						testdp.EncryptionKeyName: name,
+					},
+				})
+			}
+
+			It("should fail if the backup policy does not allow to override the encryption config", func() {
+				By("create the encryption key secret")
+				createEncryptionKeySecret(encryptionKeySecretName)
+
+				By("create a backup which overrides the encryption config")
+				backup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Spec.EncryptionConfig = testdp.NewFakeEncryptionConfig(encryptionKeySecretName, "AES-256-CFB")
+				})
+
+				By("check the backup, and it should be failed")
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureReason).To(ContainSubstring("does not allow to override the encryption config"))
+					g.Expect(fetched.Status.EncryptionConfig).Should(BeNil())
+				})).Should(Succeed())
+			})
+
+			It("should keep the recorded encryption key of the backups after the key is rotated", func() {
+				const (
+					oldKeySecretName      = encryptionKeySecretName + "-old"
+					newKeySecretName      = encryptionKeySecretName + "-new"
+					overrideKeySecretName = encryptionKeySecretName + "-override"
+				)
+				By("create the encryption key secrets")
+				createEncryptionKeySecret(oldKeySecretName)
+				createEncryptionKeySecret(newKeySecretName)
+				createEncryptionKeySecret(overrideKeySecretName)
+
+				By("set encryptionConfig with the old key")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					bp.Spec.EncryptionConfig = testdp.NewFakeEncryptionConfig(oldKeySecretName, "AES-256-CFB")
+					bp.Spec.AllowEncryptionConfigOverride = true
+				})).Should(Succeed())
+
+				By("create a backup with the old key")
+				oldBackup := testdp.NewFakeBackup(&testCtx, nil)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(oldBackup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.EncryptionConfig).Should(Equal(testdp.NewFakeEncryptionConfig(oldKeySecretName, "AES-256-CFB")))
+				})).Should(Succeed())
+
+				By("rotate the key of the backup policy")
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(bp *dpv1alpha1.BackupPolicy) {
+					bp.Spec.EncryptionConfig = testdp.NewFakeEncryptionConfig(newKeySecretName, "AES-128-CFB")
+				})).Should(Succeed())
+
+				By("create a backup with the rotated key")
+				newBackup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Name = testdp.BackupName + "-new"
+				})
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(newBackup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.EncryptionConfig).Should(Equal(testdp.NewFakeEncryptionConfig(newKeySecretName, "AES-128-CFB")))
+				})).Should(Succeed())
+
+				By("create a backup which overrides the encryption config")
+				overrideBackup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Name = testdp.BackupName + "-override"
+					backup.Spec.EncryptionConfig = testdp.NewFakeEncryptionConfig(overrideKeySecretName, "AES-192-CFB")
+				})
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(overrideBackup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseRunning))
+					g.Expect(fetched.Status.EncryptionConfig).Should(Equal(testdp.NewFakeEncryptionConfig(overrideKeySecretName, "AES-192-CFB")))
+				})).Should(Succeed())
+
+				By("check the old backup still records the old key")
+				Consistently(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(oldBackup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.EncryptionConfig).Should(Equal(testdp.NewFakeEncryptionConfig(oldKeySecretName, "AES-256-CFB")))
+				})).Should(Succeed())
+			})
 		})
 
 		Context("deletes a backup", func() {
@@ -487,6 +1525,184 @@ var _ = Describe("Backup Controller test", func() {
 
 				// TODO: add delete backup test case with the pvc not exists
 			})
+
+			It("should keep the backup files until the deletion grace period has passed", func() {
+				By("setting the deletion grace period")
+				Expect(testapps.ChangeObj(&testCtx, backup, func(fetched *dpv1alpha1.Backup) {
+					fetched.Spec.DeletionGracePeriod = &metav1.Duration{Duration: time.Hour}
+				})).Should(Succeed())
+
+				By("deleting a backup object")
+				testapps.DeleteObject(&testCtx, backupKey, &dpv1alpha1.Backup{})
+
+				By("checking the backup is deleting and no deletion job is created")
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseDeleting))
+				})).Should(Succeed())
+				jobKey := dpbackup.BuildDeleteBackupFilesJobKey(backup, false)
+				Consistently(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+
+				By("skipping the deletion grace period by annotation")
+				Expect(testapps.ChangeObj(&testCtx, backup, func(fetched *dpv1alpha1.Backup) {
+					if fetched.Annotations == nil {
+						fetched.Annotations = map[string]string{}
+					}
+					fetched.Annotations[dptypes.SkipDeletionGracePeriodAnnotationKey] = "true"
+				})).Should(Succeed())
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+			})
+		})
+
+		Context("copies a backup to other backup repos", func() {
+			const copyRepoName = "copy-repo"
+			var (
+				backupKey types.NamespacedName
+				backup    *dpv1alpha1.Backup
+			)
+
+			completeBackup := func() {
+				By("waiting for the backup to be completed")
+				jobKey := client.ObjectKey{
+					Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+					Namespace: backup.Namespace,
+				}
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.PatchK8sJobStatus(&testCtx, jobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.Copies).Should(HaveLen(1))
+					g.Expect(fetched.Status.Copies[0].RepoName).Should(Equal(copyRepoName))
+				})).Should(Succeed())
+			}
+
+			BeforeEach(func() {
+				By("creating the backup repo to copy the backups to")
+				_, _ = testdp.NewFakeBackupRepo(&testCtx, func(repo *dpv1alpha1.BackupRepo) {
+					repo.Name = copyRepoName
+				})
+				Expect(testapps.ChangeObj(&testCtx, backupPolicy, func(fetched *dpv1alpha1.BackupPolicy) {
+					fetched.Spec.CopyTo = []string{copyRepoName}
+				})).Should(Succeed())
+
+				By("creating a backup from backupPolicy " + testdp.BackupPolicyName)
+				backup = testdp.NewFakeBackup(&testCtx, nil)
+				backupKey = client.ObjectKeyFromObject(backup)
+				completeBackup()
+			})
+
+			It("should stream the backup to the backup repo and record the copy", func() {
+				By("checking the copy job reads the backup from the backup repo of the backup")
+				copyJobKey := dpbackup.BuildCopyBackupJobKey(backup, copyRepoName)
+				Eventually(testapps.CheckObj(&testCtx, copyJobKey, func(g Gomega, job *batchv1.Job) {
+					g.Expect(job.Labels[dptypes.BackupCopyJobLabelKey]).Should(Equal(trueVal))
+					podSpec := job.Spec.Template.Spec
+					g.Expect(podSpec.Containers).Should(HaveLen(1))
+					g.Expect(podSpec.Volumes).Should(ContainElement(corev1.Volume{
+						Name: "dp-copy-source",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: repoPVCName},
+						},
+					}))
+					g.Expect(podSpec.Containers[0].Args[0]).Should(ContainSubstring(backup.Namespace))
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Copies[0].Phase).Should(Equal(dpv1alpha1.BackupCopyPhaseRunning))
+				})).Should(Succeed())
+
+				By("completing the copy job")
+				testdp.PatchK8sJobStatus(&testCtx, copyJobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Copies[0].Phase).Should(Equal(dpv1alpha1.BackupCopyPhaseCompleted))
+					g.Expect(fetched.Status.Copies[0].TotalSize).Should(Equal(fetched.Status.TotalSize))
+					g.Expect(fetched.Status.Copies[0].CompletionTimestamp).ShouldNot(BeNil())
+				})).Should(Succeed())
+
+				By("deleting the backup, the files of the copy should be deleted as well")
+				testapps.DeleteObject(&testCtx, backupKey, &dpv1alpha1.Backup{})
+				deleteCopyJobKey := dpbackup.BuildDeleteBackupCopyFilesJobKey(backup, copyRepoName)
+				Eventually(testapps.CheckObjExists(&testCtx, deleteCopyJobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.ReplaceK8sJobStatus(&testCtx, deleteCopyJobKey, batchv1.JobComplete)
+				deleteJobKey := dpbackup.BuildDeleteBackupFilesJobKey(backup, false)
+				Eventually(testapps.CheckObjExists(&testCtx, deleteJobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.ReplaceK8sJobStatus(&testCtx, deleteJobKey, batchv1.JobComplete)
+				Eventually(testapps.CheckObjExists(&testCtx, backupKey, &dpv1alpha1.Backup{}, false)).Should(Succeed())
+			})
+
+			It("should not fail the backup if the copy fails", func() {
+				copyJobKey := dpbackup.BuildCopyBackupJobKey(backup, copyRepoName)
+				Eventually(testapps.CheckObjExists(&testCtx, copyJobKey, &batchv1.Job{}, true)).Should(Succeed())
+				testdp.PatchK8sJobStatus(&testCtx, copyJobKey, batchv1.JobFailed)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseCompleted))
+					g.Expect(fetched.Status.Copies[0].Phase).Should(Equal(dpv1alpha1.BackupCopyPhaseFailed))
+					g.Expect(fetched.Status.Copies[0].FailureReason).ShouldNot(BeEmpty())
+				})).Should(Succeed())
+			})
+		})
+
+		Context("deletes backups with deletion job concurrency limit", func() {
+			const (
+				backupCount = 4
+				concurrency = 2
+			)
+
+			BeforeEach(func() {
+				viper.Set(dptypes.CfgKeyDeletionJobConcurrency, concurrency)
+				DeferCleanup(func() {
+					viper.Set(dptypes.CfgKeyDeletionJobConcurrency, 0)
+				})
+			})
+
+			It("should not run more deletion jobs than the limit simultaneously", func() {
+				By("creating backups")
+				var backups []*dpv1alpha1.Backup
+				for i := 0; i < backupCount; i++ {
+					backup := testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+						backup.Name = fmt.Sprintf("%s-%d", testdp.BackupName, i)
+					})
+					Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, fetched *dpv1alpha1.Backup) {
+						g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseRunning))
+					})).Should(Succeed())
+					backups = append(backups, backup)
+				}
+
+				By("deleting all backups, like they are expired at the same time")
+				for _, backup := range backups {
+					testapps.DeleteObject(&testCtx, client.ObjectKeyFromObject(backup), &dpv1alpha1.Backup{})
+				}
+
+				listInFlightDeletionJobs := func(g Gomega) []batchv1.Job {
+					jobList := &batchv1.JobList{}
+					g.Expect(testCtx.Cli.List(testCtx.Ctx, jobList, client.InNamespace(testCtx.DefaultNamespace),
+						client.MatchingLabels{dptypes.BackupDeletionJobLabelKey: "true"})).Should(Succeed())
+					var jobs []batchv1.Job
+					for _, job := range jobList.Items {
+						if finished, _, _ := dputils.IsJobFinished(&job); !finished {
+							jobs = append(jobs, job)
+						}
+					}
+					return jobs
+				}
+
+				By("checking the number of in-flight deletion jobs never exceeds the limit")
+				Eventually(func(g Gomega) {
+					g.Expect(listInFlightDeletionJobs(g)).Should(HaveLen(concurrency))
+				}).Should(Succeed())
+				Consistently(func(g Gomega) {
+					g.Expect(len(listInFlightDeletionJobs(g))).Should(BeNumerically("<=", concurrency))
+				}).Should(Succeed())
+
+				By("completing the in-flight deletion jobs, the rest backups should be deleted in turn")
+				Eventually(func(g Gomega) {
+					jobs := listInFlightDeletionJobs(g)
+					g.Expect(len(jobs)).Should(BeNumerically("<=", concurrency))
+					for i := range jobs {
+						testdp.ReplaceK8sJobStatus(&testCtx, client.ObjectKeyFromObject(&jobs[i]), batchv1.JobComplete)
+					}
+					g.Expect(testapps.List(&testCtx, generics.BackupSignature,
+						client.InNamespace(testCtx.DefaultNamespace))(g)).Should(HaveLen(0))
+				}).Should(Succeed())
+			})
 		})
 
 		Context("creates a snapshot backup", func() {
@@ -521,6 +1737,34 @@ var _ = Describe("Backup Controller test", func() {
 				})).Should(Succeed())
 			})
 
+			It("should adopt the legacy volume snapshot after the backup is completed", func() {
+				By("patching volumesnapshot status to ready")
+				testdp.PatchVolumeSnapshotStatus(&testCtx, vsKey, true)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+				})).Should(Succeed())
+
+				By("removing the labels and owner references of the volume snapshot as the older versions")
+				Eventually(testapps.GetAndChangeObj(&testCtx, vsKey, func(vs *vsv1.VolumeSnapshot) {
+					delete(vs.Labels, dptypes.BackupNameLabelKey)
+					vs.OwnerReferences = nil
+				})).Should(Succeed())
+
+				By("reconciling the completed backup, the volume snapshot should be adopted")
+				Eventually(testapps.GetAndChangeObj(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					if fetched.Annotations == nil {
+						fetched.Annotations = map[string]string{}
+					}
+					fetched.Annotations["test-trigger-reconcile"] = "true"
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, vsKey, func(g Gomega, fetched *vsv1.VolumeSnapshot) {
+					g.Expect(fetched.Labels[dptypes.BackupNameLabelKey]).To(Equal(backup.Name))
+					owner := metav1.GetControllerOf(fetched)
+					g.Expect(owner).ShouldNot(BeNil())
+					g.Expect(owner.Name).To(Equal(backup.Name))
+				})).Should(Succeed())
+			})
+
 			It("should fail if volumesnapshot reports error", func() {
 				By("patching volumesnapshot status with error")
 				Eventually(testapps.GetAndChangeObjStatus(&testCtx, vsKey, func(tmpVS *vsv1.VolumeSnapshot) {
@@ -652,12 +1896,12 @@ var _ = Describe("Backup Controller test", func() {
 				})).Should(Succeed())
 			})
 
-			It("should fail because actionSet's backup type isn't Full", func() {
-				actionSet := testdp.NewFakeActionSet(&testCtx)
-				actionSetKey := client.ObjectKeyFromObject(actionSet)
-				Eventually(testapps.GetAndChangeObj(&testCtx, actionSetKey, func(fetched *dpv1alpha1.ActionSet) {
-					fetched.Spec.BackupType = dpv1alpha1.BackupTypeIncremental
-				}))
+			It("should fail because actionSet's backup type is unsupported", func() {
+				By("create an actionSet with the unsupported backup type")
+				testdp.NewActionSetFactory(testdp.ActionSetName).
+					SetBackupType(dpv1alpha1.BackupTypeDifferential).
+					SetBackupData(testdp.ImageTag, true, "sh", "-c", "exit 0").
+					Create(&testCtx)
 
 				backup := testdp.NewFakeBackup(&testCtx, nil)
 				backupKey := client.ObjectKeyFromObject(backup)
@@ -719,6 +1963,59 @@ var _ = Describe("Backup Controller test", func() {
 			})
 		})
 
+		Context("backup path collision", func() {
+			It("should fail if the backup path is used by another backup in the backup repo", func() {
+				By("creating backup policy and backup")
+				backupPolicy := testdp.NewFakeBackupPolicy(&testCtx, nil)
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				backupKey := client.ObjectKeyFromObject(backup)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Path).ShouldNot(BeEmpty())
+				})).Should(Succeed())
+
+				By("occupying the path of the second backup")
+				backup2 := &dpv1alpha1.Backup{}
+				backup2.Namespace = testCtx.DefaultNamespace
+				backup2.Name = testdp.BackupName + "-2"
+				backup2.Labels = map[string]string{dptypes.ClusterUIDLabelKey: string(clusterInfo.Cluster.UID)}
+				Eventually(testapps.GetAndChangeObjStatus(&testCtx, backupKey, func(fetched *dpv1alpha1.Backup) {
+					fetched.Status.Path = dpbackup.BuildBackupPath(backup2, backupPolicy.Spec.PathPrefix)
+				})).Should(Succeed())
+
+				By("creating the second backup, it should fail")
+				backup2 = testdp.NewFakeBackup(&testCtx, func(backup *dpv1alpha1.Backup) {
+					backup.Name = testdp.BackupName + "-2"
+				})
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup2), func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseFailed))
+					g.Expect(fetched.Status.FailureReason).Should(ContainSubstring("is used by the backup"))
+					g.Expect(fetched.Status.FailureCode).Should(Equal(dpv1alpha1.BackupFailureCodePathCollision))
+				})).Should(Succeed())
+			})
+		})
+
+		Context("fail over to fallback backup repo", func() {
+			It("should fail over to the fallback backup repo if the backup repo is not ready", func() {
+				By("creating a backup repo which is not ready")
+				brokenRepo := testdp.NewBackupRepoFactory("", "broken-repo").
+					SetStorageProviderRef("non-existent-provider").
+					Create(&testCtx).GetObject()
+				By("creating backup policy with a fallback backup repo")
+				_ = testdp.NewFakeBackupPolicy(&testCtx, func(backupPolicy *dpv1alpha1.BackupPolicy) {
+					backupPolicy.Spec.BackupRepoName = &brokenRepo.Name
+					backupPolicy.Spec.FallbackBackupRepoNames = []string{repo.Name}
+					backupPolicy.Spec.BackupRepoFailoverTimeout = &metav1.Duration{Duration: time.Second}
+				})
+				backup := testdp.NewFakeBackup(&testCtx, nil)
+				By("checking backup, it should use the PVC from the fallback backup repo")
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup), func(g Gomega, backup *dpv1alpha1.Backup) {
+					g.Expect(backup.Labels[dataProtectionBackupRepoKey]).Should(BeEquivalentTo(repo.Name))
+					g.Expect(backup.Status.BackupRepoName).Should(BeEquivalentTo(repo.Name))
+					g.Expect(backup.Status.PersistentVolumeClaimName).Should(BeEquivalentTo(repoPVCName))
+				})).Should(Succeed())
+			})
+		})
+
 		Context("default backup repo", func() {
 			It("should use the default backup repo if it's not specified", func() {
 				By("creating backup policy and backup")
@@ -82,6 +82,12 @@ func (r *BackupPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// TODO(ldm): validate backup policy
+	if err = validateBackupPolicyTargets(backupPolicy); err != nil {
+		if patchErr := patchStatus(dpv1alpha1.UnavailablePhase, err.Error()); patchErr != nil {
+			return intctrlutil.CheckedRequeueWithError(patchErr, reqCtx.Log, "")
+		}
+		return ctrl.Result{}, nil
+	}
 
 	if err = patchStatus(dpv1alpha1.AvailablePhase, ""); err != nil {
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
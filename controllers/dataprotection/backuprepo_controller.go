@@ -27,15 +27,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"reflect"
+	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -52,16 +56,20 @@ import (
 	"k8s.io/utils/clock"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	storagev1alpha1 "github.com/apecloud/kubeblocks/apis/storage/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/pkg/generics"
@@ -73,9 +81,44 @@ const (
 	defaultPreCheckTimeout = 15 * time.Minute
 	defaultCheckInterval   = 1 * time.Minute
 
-	preCheckContainerName = "pre-check"
+	preCheckContainerName         = "pre-check"
+	kopiaMaintenanceContainerName = "kopia-maintenance"
 )
 
+// kopiaMaintenanceScript runs the maintenance of the Kopia repository specified by DATASAFED_KOPIA_REPO_ROOT.
+// datasafed runs the maintenance when closing the repository after a write operation if DATASAFED_KOPIA_MAINTENANCE
+// is set, so a marker file is pushed and removed to trigger it. The outputs of `datasafed stat` on the Kopia
+// repository before and after the maintenance are written to the termination message of the container, which
+// are parsed by parseKopiaMaintenanceReclaimedBytes.
+var kopiaMaintenanceScript = fmt.Sprintf(`
+set -e
+export PATH="$PATH:$DP_DATASAFED_BIN_PATH"
+kopiaRepoPath="${DATASAFED_KOPIA_REPO_ROOT}"
+
+# stat the files of the kopia repository in the storage
+function statRepo() {
+	(unset DATASAFED_KOPIA_REPO_ROOT; datasafed stat "${kopiaRepoPath}")
+}
+
+before=$(statRepo)
+marker="/.kopia-maintenance"
+echo "kopia-maintenance" | datasafed push - "${marker}"
+DATASAFED_KOPIA_MAINTENANCE=true datasafed rm "${marker}"
+after=$(statRepo)
+
+echo "the maintenance of the kopia repository at '${kopiaRepoPath}' is finished"
+printf '%%s\n%%s\n%%s\n%%s\n' "%s" "${before}" "%s" "${after}" | tee /dev/termination-log
+`, kopiaMaintenanceStatBefore, kopiaMaintenanceStatAfter)
+
+const (
+	kopiaMaintenanceStatBefore = "--- before maintenance"
+	kopiaMaintenanceStatAfter  = "--- after maintenance"
+)
+
+// datasafedStatTotalSizeRegex matches the total size of the files in the output of `datasafed stat`,
+// e.g. `TotalSize: 1024`.
+var datasafedStatTotalSizeRegex = regexp.MustCompile(`(?m)^\s*"?TotalSize"?\s*[:=]\s*(\d+)`)
+
 var (
 	// for testing
 	wallClock clock.Clock = &clock.RealClock{}
@@ -222,6 +265,11 @@ func (r *BackupRepoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		},
 	}
 
+	// increase the credential generation if the credential is rotated
+	if err = r.updateCredentialGeneration(reconCtx); err != nil {
+		return checkedRequeueWithError(err, reqCtx.Log, "failed to update credential generation")
+	}
+
 	// create StorageClass and Secret for the CSI driver
 	err = r.createStorageClassAndSecret(reconCtx)
 	if err != nil {
@@ -263,7 +311,7 @@ func (r *BackupRepoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	if repo.Status.Phase == dpv1alpha1.BackupRepoReady {
 		// update tool config if needed
-		err = r.updateToolConfigSecrets(reconCtx)
+		deferred, err := r.updateToolConfigSecrets(reconCtx)
 		if err != nil {
 			return checkedRequeueWithError(err, reqCtx.Log,
 				"failed to update tool config secrets")
@@ -274,6 +322,22 @@ func (r *BackupRepoReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			return checkedRequeueWithError(err, reqCtx.Log,
 				"check associated backups failed")
 		}
+
+		// run the maintenance of the kopia repositories if it is due
+		maintenanceWait, err := r.reconcileKopiaMaintenance(reconCtx)
+		if err != nil {
+			return checkedRequeueWithError(err, reqCtx.Log,
+				"failed to run the kopia maintenance")
+		}
+
+		if deferred {
+			return intctrlutil.RequeueAfter(credentialRotationCheckInterval, reqCtx.Log,
+				"wait for the in-progress backups to update the tool config secrets")
+		}
+		if maintenanceWait > 0 {
+			return intctrlutil.RequeueAfter(maintenanceWait, reqCtx.Log,
+				"wait for the next check of the kopia maintenance")
+		}
 	}
 
 	return ctrl.Result{}, nil
@@ -300,6 +364,15 @@ func (r *BackupRepoReconciler) updateStatus(reqCtx intctrlutil.RequestCtx, repo
 	}
 	repo.Status.IsDefault = repo.Annotations[dptypes.DefaultBackupRepoAnnotationKey] == trueVal
 
+	// update the usage of the repo, the deleted backups are excluded
+	usage, err := calculateBackupRepoUsage(reqCtx.Ctx, r.Client, repo.Name)
+	if err != nil {
+		return fmt.Errorf("failed to calculate usage: %w", err)
+	}
+	if old.Status.Usage == nil || !isSameBackupRepoUsage(old.Status.Usage, usage) {
+		repo.Status.Usage = usage
+	}
+
 	// update other fields
 	if repo.Status.BackupPVCName == "" {
 		repo.Status.BackupPVCName = randomNameForDerivedObject(repo, "pvc")
@@ -556,17 +629,72 @@ func (r *BackupRepoReconciler) checkPVCTemplate(reconCtx *reconcileContext) (err
 	return nil
 }
 
-func (r *BackupRepoReconciler) updateToolConfigSecrets(reconCtx *reconcileContext) (err error) {
-	if !reconCtx.repo.AccessByTool() {
+// isToolConfigUpdateDeferralExpired checks whether the tool config secrets have been deferred to update
+// for longer than toolConfigUpdateDeferralTimeout.
+func isToolConfigUpdateDeferralExpired(repo *dpv1alpha1.BackupRepo) bool {
+	cond := meta.FindStatusCondition(repo.Status.Conditions, ConditionTypeToolConfigUpdated)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != ReasonWaitingForBackups {
+		return false
+	}
+	return time.Since(cond.LastTransitionTime.Time) >= toolConfigUpdateDeferralTimeout
+}
+
+// updateCredentialGeneration increases the credential generation of the repo when the content
+// of the credential secret is changed.
+func (r *BackupRepoReconciler) updateCredentialGeneration(reconCtx *reconcileContext) error {
+	repo := reconCtx.repo
+	if repo.Spec.Credential == nil {
 		return nil
 	}
-	if reconCtx.repo.Annotations[dataProtectionNeedUpdateToolConfigAnnotationKey] != trueVal {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(reconCtx.Ctx, client.ObjectKey{
+		Namespace: repo.Spec.Credential.Namespace,
+		Name:      repo.Spec.Credential.Name,
+	}, secret); err != nil {
+		return err
+	}
+	data := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	digest := md5Digest(stableSerializeMap(data))
+	oldDigest := repo.Annotations[dataProtectionCredentialDigestAnnotationKey]
+	if oldDigest == digest {
 		return nil
 	}
+	// the generation is increased before recording the digest, it may be increased twice
+	// if failed to record the digest, which is harmless.
+	patch := client.MergeFrom(repo.DeepCopy())
+	repo.Status.CredentialGeneration++
+	if err := r.Client.Status().Patch(reconCtx.Ctx, repo, patch); err != nil {
+		return err
+	}
+	if oldDigest != "" {
+		r.Recorder.Eventf(repo, corev1.EventTypeNormal, "CredentialRotated",
+			"the credential secret %s/%s is changed, the credential generation is %d",
+			secret.Namespace, secret.Name, repo.Status.CredentialGeneration)
+	}
+	return updateAnnotations(reconCtx.Ctx, r.Client, repo, map[string]string{
+		dataProtectionCredentialDigestAnnotationKey: digest,
+	})
+}
+
+// updateToolConfigSecrets updates the tool config secrets if the digest of the repo is changed.
+// The secrets in the namespaces with in-progress backups are not updated until the backups are finished,
+// otherwise the backups may fail midway since the mounted secrets are refreshed in the running pods.
+// The deferral is bounded by toolConfigUpdateDeferralTimeout, since the new backups keep starting
+// with the stale credential in the meantime. It returns true if any secret is deferred to update.
+func (r *BackupRepoReconciler) updateToolConfigSecrets(reconCtx *reconcileContext) (deferred bool, err error) {
+	if !reconCtx.repo.AccessByTool() {
+		return false, nil
+	}
+	if reconCtx.repo.Annotations[dataProtectionNeedUpdateToolConfigAnnotationKey] != trueVal {
+		return false, nil
+	}
 	// render tool config template
 	content, err := renderTemplate("tool-config", reconCtx.provider.Spec.DatasafedConfigTemplate, reconCtx.renderCtx)
 	if err != nil {
-		return err
+		return false, err
 	}
 	// update existing tool config secrets
 	secretList := &corev1.SecretList{}
@@ -575,30 +703,344 @@ func (r *BackupRepoReconciler) updateToolConfigSecrets(reconCtx *reconcileContex
 		dataProtectionIsToolConfigKey: trueVal,
 	})
 	if err != nil {
-		return err
+		return false, err
 	}
+	deferralExpired := isToolConfigUpdateDeferralExpired(reconCtx.repo)
+	var deferredNamespaces, forcedNamespaces []string
 	for idx := range secretList.Items {
 		secret := &secretList.Items[idx]
 		oldDigest := secret.Annotations[dataProtectionBackupRepoDigestAnnotationKey]
 		if oldDigest == reconCtx.getDigest() {
 			continue
 		}
+		inProgress, err := hasInProgressBackups(reconCtx.Ctx, r.Client, secret.Namespace, reconCtx.repo.Name)
+		if err != nil {
+			return false, err
+		}
+		if inProgress && !deferralExpired {
+			deferredNamespaces = append(deferredNamespaces, secret.Namespace)
+			continue
+		}
+		if inProgress {
+			forcedNamespaces = append(forcedNamespaces, secret.Namespace)
+		}
 		patch := client.MergeFrom(secret.DeepCopy())
 		constructToolConfigSecret(secret, content)
 		if secret.Annotations == nil {
 			secret.Annotations = make(map[string]string)
 		}
 		secret.Annotations[dataProtectionBackupRepoDigestAnnotationKey] = reconCtx.getDigest()
+		secret.Annotations[dataProtectionCredentialGenerationAnnotationKey] = strconv.FormatInt(reconCtx.repo.Status.CredentialGeneration, 10)
 		if err = r.Client.Patch(reconCtx.Ctx, secret, patch); err != nil {
-			return err
+			return false, err
 		}
 	}
 
-	return updateAnnotations(reconCtx.Ctx, r.Client, reconCtx.repo, map[string]string{
+	if len(deferredNamespaces) > 0 {
+		sort.Strings(deferredNamespaces)
+		return true, updateCondition(reconCtx.Ctx, r.Client, reconCtx.repo, ConditionTypeToolConfigUpdated,
+			metav1.ConditionFalse, ReasonWaitingForBackups,
+			fmt.Sprintf("waiting for the in-progress backups in namespaces [%s]", strings.Join(deferredNamespaces, ", ")))
+	}
+	if len(forcedNamespaces) > 0 {
+		sort.Strings(forcedNamespaces)
+		r.Recorder.Eventf(reconCtx.repo, corev1.EventTypeWarning, ReasonToolConfigUpdateTimeout,
+			"the tool config secrets are updated after waiting for %s, the in-progress backups in namespaces [%s] may fail",
+			toolConfigUpdateDeferralTimeout, strings.Join(forcedNamespaces, ", "))
+	}
+	if err = updateCondition(reconCtx.Ctx, r.Client, reconCtx.repo, ConditionTypeToolConfigUpdated,
+		metav1.ConditionTrue, ReasonToolConfigUpdated, ""); err != nil {
+		return false, err
+	}
+	return false, updateAnnotations(reconCtx.Ctx, r.Client, reconCtx.repo, map[string]string{
 		dataProtectionNeedUpdateToolConfigAnnotationKey: "false",
 	})
 }
 
+// kopiaRepoRef refers to a Kopia repository stored in the backup repo, which is used by the backups
+// in the namespace.
+type kopiaRepoRef struct {
+	namespace        string
+	path             string
+	encryptionConfig *dpv1alpha1.EncryptionConfig
+	hasRunningBackup bool
+}
+
+// collectKopiaRepos collects the Kopia repositories used by the backups, sorted by the namespace and the path.
+func collectKopiaRepos(backups []*dpv1alpha1.Backup) []*kopiaRepoRef {
+	var kopiaRepos []*kopiaRepoRef
+	indexes := make(map[types.NamespacedName]int)
+	for _, backup := range backups {
+		if backup.Status.KopiaRepoPath == "" {
+			continue
+		}
+		key := types.NamespacedName{Namespace: backup.Namespace, Name: backup.Status.KopiaRepoPath}
+		idx, ok := indexes[key]
+		if !ok {
+			idx = len(kopiaRepos)
+			indexes[key] = idx
+			kopiaRepos = append(kopiaRepos, &kopiaRepoRef{
+				namespace: backup.Namespace,
+				path:      backup.Status.KopiaRepoPath,
+			})
+		}
+		kopiaRepo := kopiaRepos[idx]
+		if kopiaRepo.encryptionConfig == nil {
+			kopiaRepo.encryptionConfig = backup.Status.EncryptionConfig
+		}
+		if backup.Status.Phase == dpv1alpha1.BackupPhaseRunning {
+			kopiaRepo.hasRunningBackup = true
+		}
+	}
+	sort.Slice(kopiaRepos, func(i, j int) bool {
+		if kopiaRepos[i].namespace != kopiaRepos[j].namespace {
+			return kopiaRepos[i].namespace < kopiaRepos[j].namespace
+		}
+		return kopiaRepos[i].path < kopiaRepos[j].path
+	})
+	return kopiaRepos
+}
+
+func kopiaMaintenanceJobName(repo *dpv1alpha1.BackupRepo, kopiaRepoPath string) string {
+	return cutName(fmt.Sprintf("kopia-maintenance-%s-%s", repo.UID[:8], md5Digest(kopiaRepoPath)[:8]))
+}
+
+// reconcileKopiaMaintenance runs the maintenance of the Kopia repositories stored in the repo according to
+// the maintenance schedule, a job is created for each Kopia repository in the namespace of its backups.
+// The Kopia repositories with running backups are skipped until the backups are finished, and the in-progress
+// job is aborted if a backup starts to run. The maintenance is disabled for a read-only repo as it writes to
+// the storage. The maintenance failures are reported by warning events and never affect the phase of the repo
+// or the backups. It returns the duration to wait for the next check, zero means the maintenance is disabled.
+func (r *BackupRepoReconciler) reconcileKopiaMaintenance(reconCtx *reconcileContext) (time.Duration, error) {
+	repo := reconCtx.repo
+	// the jobs of the in-progress maintenance
+	jobList := &batchv1.JobList{}
+	if err := r.Client.List(reconCtx.Ctx, jobList, client.MatchingLabels{
+		dataProtectionBackupRepoKey:       repo.Name,
+		dataProtectionKopiaMaintenanceKey: trueVal,
+	}); err != nil {
+		return 0, err
+	}
+	jobs := make(map[types.NamespacedName]*batchv1.Job)
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if job.DeletionTimestamp.IsZero() {
+			jobs[client.ObjectKeyFromObject(job)] = job
+		}
+	}
+
+	if repo.Spec.ReadOnly {
+		for _, job := range jobs {
+			if err := intctrlutil.BackgroundDeleteObject(r.Client, reconCtx.Ctx, job); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+	scheduleExpr := repo.Spec.MaintenanceSchedule
+	if scheduleExpr == "" {
+		scheduleExpr = viper.GetString(dptypes.CfgKeyKopiaMaintenanceSchedule)
+	}
+	if scheduleExpr == "" {
+		return 0, nil
+	}
+	schedule, err := cron.ParseStandard(scheduleExpr)
+	if err != nil {
+		r.Recorder.Eventf(repo, corev1.EventTypeWarning, "InvalidMaintenanceSchedule",
+			"invalid kopia maintenance schedule %q: %s", scheduleExpr, err.Error())
+		return 0, nil
+	}
+
+	now := wallClock.Now()
+	if len(jobs) == 0 {
+		lastMaintenanceTime := repo.CreationTimestamp.Time
+		if repo.Status.LastMaintenanceTime != nil {
+			lastMaintenanceTime = repo.Status.LastMaintenanceTime.Time
+		}
+		if next := schedule.Next(lastMaintenanceTime); next.After(now) {
+			return next.Sub(now), nil
+		}
+	}
+
+	backups, err := r.listAssociatedBackups(reconCtx.Ctx, repo, nil)
+	if err != nil {
+		return 0, err
+	}
+	finished := true
+	var reclaimedBytes int64
+	var failedRepos, unknownRepos []string
+	for _, kopiaRepo := range collectKopiaRepos(backups) {
+		jobKey := types.NamespacedName{Namespace: kopiaRepo.namespace, Name: kopiaMaintenanceJobName(repo, kopiaRepo.path)}
+		job, ok := jobs[jobKey]
+		done, jobStatus, failureReason := utils.IsJobFinished(job)
+		if ok && !done && kopiaRepo.hasRunningBackup {
+			// abort the maintenance, it will be rerun after the backups are finished.
+			reconCtx.Log.Info("abort the kopia maintenance since there are running backups",
+				"job", jobKey, "path", kopiaRepo.path)
+			if err = intctrlutil.BackgroundDeleteObject(r.Client, reconCtx.Ctx, job); err != nil {
+				return 0, err
+			}
+			delete(jobs, jobKey)
+			ok = false
+		}
+		if !ok {
+			finished = false
+			if kopiaRepo.hasRunningBackup {
+				reconCtx.Log.V(1).Info("skip the kopia maintenance since there are running backups",
+					"namespace", kopiaRepo.namespace, "path", kopiaRepo.path)
+				continue
+			}
+			if err = r.createKopiaMaintenanceJob(reconCtx, jobKey, kopiaRepo); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		switch {
+		case !done:
+			finished = false
+		case jobStatus == batchv1.JobFailed:
+			failedRepos = append(failedRepos, fmt.Sprintf("%s/%s: %s", kopiaRepo.namespace, kopiaRepo.path, failureReason))
+		default:
+			bytes, err := r.getKopiaMaintenanceReclaimedBytes(reconCtx, job)
+			if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+				unknownRepos = append(unknownRepos, fmt.Sprintf("%s/%s: %s", kopiaRepo.namespace, kopiaRepo.path, err.Error()))
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			reclaimedBytes += bytes
+		}
+	}
+	if !finished {
+		return kopiaMaintenanceCheckInterval, nil
+	}
+
+	if len(failedRepos) > 0 {
+		r.Recorder.Eventf(repo, corev1.EventTypeWarning, "KopiaMaintenanceFailed",
+			"the kopia maintenance failed for the repositories [%s]", strings.Join(failedRepos, ", "))
+	}
+	if len(unknownRepos) > 0 {
+		r.Recorder.Eventf(repo, corev1.EventTypeWarning, "KopiaMaintenanceReclaimedBytesUnknown",
+			"the reclaimed bytes are not counted for the repositories [%s]", strings.Join(unknownRepos, ", "))
+	}
+	patch := client.MergeFrom(repo.DeepCopy())
+	repo.Status.LastMaintenanceTime = &metav1.Time{Time: now}
+	repo.Status.ReclaimedBytes = reclaimedBytes
+	if err = r.Client.Status().Patch(reconCtx.Ctx, repo, patch); err != nil {
+		return 0, err
+	}
+	for _, job := range jobs {
+		if err = intctrlutil.BackgroundDeleteObject(r.Client, reconCtx.Ctx, job); err != nil {
+			return 0, err
+		}
+	}
+	return schedule.Next(now).Sub(now), nil
+}
+
+func (r *BackupRepoReconciler) createKopiaMaintenanceJob(reconCtx *reconcileContext,
+	jobKey types.NamespacedName, kopiaRepo *kopiaRepoRef) error {
+	saName, err := EnsureWorkerServiceAccount(reconCtx.RequestCtx, r.Client, jobKey.Namespace)
+	if err != nil {
+		return err
+	}
+	runAsUser := int64(0)
+	container := corev1.Container{
+		Name:            kopiaMaintenanceContainerName,
+		Image:           viper.GetString(constant.KBToolsImage),
+		ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+		Command:         []string{"sh", "-c", kopiaMaintenanceScript},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			RunAsUser:                &runAsUser,
+		},
+	}
+	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: jobKey.Namespace,
+			Name:      jobKey.Name,
+			Labels: map[string]string{
+				constant.AppManagedByLabelKey:     dptypes.AppName,
+				dataProtectionBackupRepoKey:       reconCtx.repo.Name,
+				dataProtectionKopiaMaintenanceKey: trueVal,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers:         []corev1.Container{container},
+					ServiceAccountName: saName,
+				},
+			},
+			BackoffLimit: pointer.Int32(2),
+		},
+	}
+	if err = utils.AddTolerations(&job.Spec.Template.Spec); err != nil {
+		return err
+	}
+	utils.InjectDatasafed(&job.Spec.Template.Spec, reconCtx.repo, dpbackup.RepoVolumeMountPath,
+		kopiaRepo.encryptionConfig, kopiaRepo.path)
+	if err = controllerutil.SetControllerReference(reconCtx.repo, job, r.Scheme); err != nil {
+		return err
+	}
+	reconCtx.Log.Info("create a job to run the kopia maintenance", "job", jobKey, "path", kopiaRepo.path)
+	return client.IgnoreAlreadyExists(r.Client.Create(reconCtx.Ctx, job))
+}
+
+// getKopiaMaintenanceReclaimedBytes gets the number of bytes reclaimed by the maintenance job from the
+// termination message of the succeeded pod, it returns a fatal error if the message is missing or invalid.
+func (r *BackupRepoReconciler) getKopiaMaintenanceReclaimedBytes(reconCtx *reconcileContext, job *batchv1.Job) (int64, error) {
+	podList, err := utils.GetAssociatedPodsOfJob(reconCtx.Ctx, r.Client, job.Namespace, job.Name)
+	if err != nil {
+		return 0, err
+	}
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if status.Name != kopiaMaintenanceContainerName || terminated == nil || terminated.ExitCode != 0 {
+				continue
+			}
+			return parseKopiaMaintenanceReclaimedBytes(terminated.Message)
+		}
+	}
+	return 0, intctrlutil.NewFatalError(fmt.Sprintf(`no termination message is found for job "%s"`, job.Name))
+}
+
+// parseKopiaMaintenanceReclaimedBytes parses the termination message written by kopiaMaintenanceScript,
+// the reclaimed bytes are the decrease of the total size of the Kopia repository in the storage.
+func parseKopiaMaintenanceReclaimedBytes(message string) (int64, error) {
+	beforeIdx := strings.Index(message, kopiaMaintenanceStatBefore)
+	afterIdx := strings.Index(message, kopiaMaintenanceStatAfter)
+	if beforeIdx < 0 || afterIdx < beforeIdx {
+		return 0, intctrlutil.NewFatalError(fmt.Sprintf("invalid termination message: %q", message))
+	}
+	parseTotalSize := func(stat string) (int64, error) {
+		match := datasafedStatTotalSizeRegex.FindStringSubmatch(stat)
+		if match == nil {
+			return 0, intctrlutil.NewFatalError(fmt.Sprintf("the total size is not found in the output of datasafed stat: %q", stat))
+		}
+		size, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0, intctrlutil.NewFatalError(err.Error())
+		}
+		return size, nil
+	}
+	before, err := parseTotalSize(message[beforeIdx+len(kopiaMaintenanceStatBefore) : afterIdx])
+	if err != nil {
+		return 0, err
+	}
+	after, err := parseTotalSize(message[afterIdx+len(kopiaMaintenanceStatAfter):])
+	if err != nil {
+		return 0, err
+	}
+	if before < after {
+		return 0, nil
+	}
+	return before - after, nil
+}
+
 func (r *BackupRepoReconciler) preCheckRepo(reconCtx *reconcileContext) (err error) {
 	if reconCtx.digestChanged() {
 		// invalidate the old status. reconCtx.preCheckFinished() depends on this value
@@ -995,6 +1437,16 @@ func (r *BackupRepoReconciler) prepareForAssociatedBackups(reconCtx *reconcileCo
 	if err != nil {
 		return err
 	}
+	// the backups waiting for the repo to copy the backup data to.
+	copyBackupList := &dpv1alpha1.BackupList{}
+	if err = r.Client.List(reconCtx.Ctx, copyBackupList, client.MatchingLabels{
+		dataProtectionWaitCopyRepoPreparationKey: reconCtx.repo.Name,
+	}); err != nil {
+		return err
+	}
+	for i := range copyBackupList.Items {
+		backups = append(backups, &copyBackupList.Items[i])
+	}
 	// return any error to reconcile the repo
 	var retErr error
 	for _, backup := range backups {
@@ -1015,9 +1467,17 @@ func (r *BackupRepoReconciler) prepareForAssociatedBackups(reconCtx *reconcileCo
 			retErr = fmt.Errorf("unknown access method: %s", reconCtx.repo.Spec.AccessMethod)
 		}
 
-		if backup.Labels[dataProtectionWaitRepoPreparationKey] != "" {
+		waitRepo := backup.Labels[dataProtectionWaitRepoPreparationKey] != "" &&
+			backup.Labels[dataProtectionBackupRepoKey] == reconCtx.repo.Name
+		waitCopyRepo := backup.Labels[dataProtectionWaitCopyRepoPreparationKey] == reconCtx.repo.Name
+		if waitRepo || waitCopyRepo {
 			patch := client.MergeFrom(backup.DeepCopy())
-			delete(backup.Labels, dataProtectionWaitRepoPreparationKey)
+			if waitRepo {
+				delete(backup.Labels, dataProtectionWaitRepoPreparationKey)
+			}
+			if waitCopyRepo {
+				delete(backup.Labels, dataProtectionWaitCopyRepoPreparationKey)
+			}
 			if err = r.Client.Patch(reconCtx.Ctx, backup, patch); err != nil {
 				reconCtx.Log.Error(err, "failed to patch backup",
 					"backup", client.ObjectKeyFromObject(backup))
@@ -1115,7 +1575,8 @@ func (r *BackupRepoReconciler) createToolConfigSecret(reconCtx *reconcileContext
 				dataProtectionIsToolConfigKey: trueVal,
 			}
 			secret.Annotations = map[string]string{
-				dataProtectionBackupRepoDigestAnnotationKey: reconCtx.getDigest(),
+				dataProtectionBackupRepoDigestAnnotationKey:     reconCtx.getDigest(),
+				dataProtectionCredentialGenerationAnnotationKey: strconv.FormatInt(reconCtx.repo.Status.CredentialGeneration, 10),
 			}
 			for k, v := range extraAnnos {
 				secret.Annotations[k] = v
@@ -1312,25 +1773,56 @@ func (r *BackupRepoReconciler) deleteSecrets(reqCtx intctrlutil.RequestCtx, repo
 
 func (r *BackupRepoReconciler) mapBackupToRepo(ctx context.Context, obj client.Object) []ctrl.Request {
 	backup := obj.(*dpv1alpha1.Backup)
+	var requests []ctrl.Request
+	// the Backup waits for the BackupRepo to copy the backup data to.
+	if copyRepoName := backup.Labels[dataProtectionWaitCopyRepoPreparationKey]; copyRepoName != "" {
+		requests = append(requests, ctrl.Request{
+			NamespacedName: client.ObjectKey{Name: copyRepoName},
+		})
+	}
 	repoName, ok := backup.Labels[dataProtectionBackupRepoKey]
 	if !ok {
-		return nil
+		return requests
 	}
 	// ignore failed backups
 	if backup.Status.Phase == dpv1alpha1.BackupPhaseFailed {
-		return nil
+		return requests
 	}
 	// we should reconcile the BackupRepo when:
 	//   1. the Backup needs to use the BackupRepo, but it's not ready for the namespace.
 	//   2. the Backup is being deleted, because it may block the deletion of the BackupRepo.
+	//   3. the total size of the Backup is recorded, because it changes the usage of the BackupRepo.
 	shouldReconcileRepo := backup.Labels[dataProtectionWaitRepoPreparationKey] == trueVal ||
-		!backup.DeletionTimestamp.IsZero()
+		!backup.DeletionTimestamp.IsZero() || backup.Status.TotalSize != ""
 	if shouldReconcileRepo {
-		return []ctrl.Request{{
+		requests = append(requests, ctrl.Request{
 			NamespacedName: client.ObjectKey{Name: repoName},
-		}}
+		})
 	}
-	return nil
+	return requests
+}
+
+// backupUpdatePredicate filters out the updates of the Backup which don't affect the BackupRepo,
+// such as the progress of a running backup.
+type backupUpdatePredicate struct {
+	predicate.Funcs
+}
+
+var _ predicate.Predicate = backupUpdatePredicate{}
+
+func (p backupUpdatePredicate) Update(e event.UpdateEvent) bool {
+	oldBackup, ok := e.ObjectOld.(*dpv1alpha1.Backup)
+	if !ok {
+		return false
+	}
+	newBackup, ok := e.ObjectNew.(*dpv1alpha1.Backup)
+	if !ok {
+		return false
+	}
+	return !maps.Equal(oldBackup.Labels, newBackup.Labels) ||
+		!oldBackup.DeletionTimestamp.Equal(newBackup.DeletionTimestamp) ||
+		oldBackup.Status.Phase != newBackup.Status.Phase ||
+		oldBackup.Status.TotalSize != newBackup.Status.TotalSize
 }
 
 func (r *BackupRepoReconciler) mapProviderToRepos(ctx context.Context, obj client.Object) []ctrl.Request {
@@ -1367,7 +1859,8 @@ func (r *BackupRepoReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&dpv1alpha1.BackupRepo{}).
 		Watches(&storagev1alpha1.StorageProvider{}, handler.EnqueueRequestsFromMapFunc(r.mapProviderToRepos)).
-		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.mapBackupToRepo)).
+		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.mapBackupToRepo),
+			builder.WithPredicates(backupUpdatePredicate{})).
 		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapSecretToRepos)).
 		Owns(&storagev1.StorageClass{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
@@ -1408,6 +1901,19 @@ func checkedRequeueWithError(err error, logger logr.Logger, msg string, keysAndV
 	return intctrlutil.RequeueWithError(err, logger, msg, keysAndValues...)
 }
 
+func isSameBackupRepoUsage(a, b *dpv1alpha1.BackupRepoUsage) bool {
+	if a.Total.Cmp(b.Total) != 0 || len(a.PerNamespace) != len(b.PerNamespace) {
+		return false
+	}
+	for ns, used := range a.PerNamespace {
+		other, ok := b.PerNamespace[ns]
+		if !ok || used.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 type renderContext struct {
 	Parameters                map[string]string
 	CSIDriverSecretRef        corev1.SecretReference
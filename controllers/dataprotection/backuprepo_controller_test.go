@@ -40,6 +40,7 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	storagev1alpha1 "github.com/apecloud/kubeblocks/apis/storage/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
@@ -69,6 +70,7 @@ var _ = Describe("BackupRepo controller", func() {
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS, ml)
 		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
 		testapps.ClearResources(&testCtx, generics.JobSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
 
 		// namespace2
 		inNS2 := client.InNamespace(namespace2)
@@ -702,6 +704,194 @@ parameters:
 			})).Should(Succeed())
 		})
 
+		It("should run the kopia maintenance according to the schedule", func() {
+			fakeClock := testing.NewFakeClock(time.Now())
+			original := wallClock
+			wallClock = fakeClock
+			defer func() {
+				wallClock = original
+			}()
+			By("creating a repo with the maintenance schedule")
+			createBackupRepoSpec(func(repo *dpv1alpha1.BackupRepo) {
+				repo.Spec.MaintenanceSchedule = "0 * * * *"
+			})
+			completePreCheckJob(repo)
+			Eventually(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+				g.Expect(repo.Status.Phase).Should(Equal(dpv1alpha1.BackupRepoReady))
+			})).Should(Succeed())
+
+			By("creating a backup stored in the kopia repository")
+			const kopiaRepoPath = "/default/kopia"
+			backup := createBackupSpec(nil)
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(backup), func(backup *dpv1alpha1.Backup) {
+				backup.Status.KopiaRepoPath = kopiaRepoPath
+			})).Should(Succeed())
+
+			By("checking the maintenance job is created when the maintenance is due")
+			fakeClock.Step(2 * time.Hour)
+			Eventually(testapps.GetAndChangeObj(&testCtx, repoKey, func(repo *dpv1alpha1.BackupRepo) {
+				if repo.Annotations == nil {
+					repo.Annotations = make(map[string]string)
+				}
+				repo.Annotations["touch"] = "whatever"
+			})).Should(Succeed())
+			jobKey := types.NamespacedName{
+				Name:      kopiaMaintenanceJobName(repo, kopiaRepoPath),
+				Namespace: testCtx.DefaultNamespace,
+			}
+			Eventually(testapps.CheckObj(&testCtx, jobKey, func(g Gomega, job *batchv1.Job) {
+				g.Expect(job.Labels).Should(HaveKeyWithValue(dataProtectionKopiaMaintenanceKey, trueVal))
+				container := job.Spec.Template.Spec.Containers[0]
+				g.Expect(container.Env).Should(ContainElement(corev1.EnvVar{
+					Name:  dptypes.DPDatasafedKopiaRepoRoot,
+					Value: kopiaRepoPath,
+				}))
+			})).Should(Succeed())
+
+			By("completing the maintenance job, the maintenance time and the reclaimed bytes should be recorded")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: jobKey.Namespace,
+					Name:      jobKey.Name + "-abcde",
+					Labels:    map[string]string{"job-name": jobKey.Name},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: kopiaMaintenanceContainerName, Image: "test-image"}},
+				},
+			}
+			Expect(testCtx.Create(ctx, pod)).Should(Succeed())
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(pod), func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name: kopiaMaintenanceContainerName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 0,
+						Message: "--- before maintenance\nEntries: 12\nDirs: 3\nFiles: 9\nTotalSize: 3145728\n" +
+							"--- after maintenance\nEntries: 8\nDirs: 3\nFiles: 5\nTotalSize: 1048576\n",
+					}},
+				}}
+			})).Should(Succeed())
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, jobKey, func(job *batchv1.Job) {
+				job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+					Type:   batchv1.JobComplete,
+					Status: corev1.ConditionTrue,
+				})
+			})).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+				g.Expect(repo.Status.LastMaintenanceTime).ShouldNot(BeNil())
+				g.Expect(repo.Status.ReclaimedBytes).Should(BeEquivalentTo(2097152))
+				g.Expect(repo.Status.Phase).Should(Equal(dpv1alpha1.BackupRepoReady))
+			})).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+		})
+
+		It("should abort the kopia maintenance when a backup starts to run or the repo becomes read-only", func() {
+			fakeClock := testing.NewFakeClock(time.Now())
+			original := wallClock
+			wallClock = fakeClock
+			defer func() {
+				wallClock = original
+			}()
+			By("creating a repo with the maintenance schedule")
+			createBackupRepoSpec(func(repo *dpv1alpha1.BackupRepo) {
+				repo.Spec.MaintenanceSchedule = "0 * * * *"
+			})
+			completePreCheckJob(repo)
+			Eventually(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+				g.Expect(repo.Status.Phase).Should(Equal(dpv1alpha1.BackupRepoReady))
+			})).Should(Succeed())
+
+			By("creating a backup stored in the kopia repository")
+			const kopiaRepoPath = "/default/kopia"
+			backup := createBackupSpec(nil)
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(backup), func(backup *dpv1alpha1.Backup) {
+				backup.Status.KopiaRepoPath = kopiaRepoPath
+				backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+			})).Should(Succeed())
+
+			By("checking the maintenance job is created when the maintenance is due")
+			fakeClock.Step(2 * time.Hour)
+			touchRepo := func() {
+				Eventually(testapps.GetAndChangeObj(&testCtx, repoKey, func(repo *dpv1alpha1.BackupRepo) {
+					if repo.Annotations == nil {
+						repo.Annotations = make(map[string]string)
+					}
+					repo.Annotations["touch"] = time.Now().String()
+				})).Should(Succeed())
+			}
+			touchRepo()
+			jobKey := types.NamespacedName{
+				Name:      kopiaMaintenanceJobName(repo, kopiaRepoPath),
+				Namespace: testCtx.DefaultNamespace,
+			}
+			Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+
+			By("starting a backup, the maintenance job should be aborted")
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(backup), func(backup *dpv1alpha1.Backup) {
+				backup.Status.Phase = dpv1alpha1.BackupPhaseRunning
+			})).Should(Succeed())
+			touchRepo()
+			Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+
+			By("finishing the backup, the maintenance job should be recreated")
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, client.ObjectKeyFromObject(backup), func(backup *dpv1alpha1.Backup) {
+				backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+			})).Should(Succeed())
+			touchRepo()
+			Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+
+			By("making the repo read-only, the maintenance job should be aborted")
+			Eventually(testapps.GetAndChangeObj(&testCtx, repoKey, func(repo *dpv1alpha1.BackupRepo) {
+				repo.Spec.ReadOnly = true
+			})).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+			Consistently(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+			Expect(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+				g.Expect(repo.Status.LastMaintenanceTime).Should(BeNil())
+			})).Should(Succeed())
+		})
+
+		It("should parse the reclaimed bytes from the termination message of the maintenance job", func() {
+			message := "--- before maintenance\nEntries: 12\nDirs: 3\nFiles: 9\nTotalSize: 3145728\n" +
+				"--- after maintenance\nEntries: 8\nDirs: 3\nFiles: 5\nTotalSize: 1048576\n"
+			Expect(parseKopiaMaintenanceReclaimedBytes(message)).Should(BeEquivalentTo(2097152))
+
+			By("the repository grows during the maintenance")
+			message = "--- before maintenance\nTotalSize: 1024\n--- after maintenance\nTotalSize: 2048\n"
+			Expect(parseKopiaMaintenanceReclaimedBytes(message)).Should(BeZero())
+
+			By("the message is invalid")
+			for _, message = range []string{
+				"",
+				"2097152",
+				"--- before maintenance\nTotalSize: 1024\n",
+				"--- before maintenance\nSize: 2048\n--- after maintenance\nSize: 1024\n",
+			} {
+				_, err := parseKopiaMaintenanceReclaimedBytes(message)
+				Expect(intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal)).Should(BeTrue(), message)
+			}
+		})
+
+		It("should collect the kopia repositories and skip the ones with running backups", func() {
+			newBackup := func(namespace, path string, phase dpv1alpha1.BackupPhase) *dpv1alpha1.Backup {
+				backup := &dpv1alpha1.Backup{}
+				backup.Namespace = namespace
+				backup.Status.KopiaRepoPath = path
+				backup.Status.Phase = phase
+				return backup
+			}
+			kopiaRepos := collectKopiaRepos([]*dpv1alpha1.Backup{
+				newBackup(namespace2, "/namespace2/kopia", dpv1alpha1.BackupPhaseCompleted),
+				newBackup("default", "/default/kopia", dpv1alpha1.BackupPhaseCompleted),
+				newBackup("default", "/default/kopia", dpv1alpha1.BackupPhaseRunning),
+				newBackup("default", "", dpv1alpha1.BackupPhaseCompleted),
+			})
+			Expect(kopiaRepos).Should(HaveLen(2))
+			Expect(kopiaRepos[0].namespace).Should(Equal("default"))
+			Expect(kopiaRepos[0].hasRunningBackup).Should(BeTrue())
+			Expect(kopiaRepos[1].namespace).Should(Equal(namespace2))
+			Expect(kopiaRepos[1].hasRunningBackup).Should(BeFalse())
+		})
+
 		createBackupAndCheckPVC := func(namespace string) (backup *dpv1alpha1.Backup, pvcName string) {
 			By("making sure the repo is ready")
 			Eventually(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
@@ -995,6 +1185,51 @@ new-item=new-value
 				})).Should(Succeed())
 			})
 
+			It("should record the credential generation when the credential is rotated", func() {
+				Eventually(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+					g.Expect(repo.Status.CredentialGeneration).Should(BeEquivalentTo(1))
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, toolConfigSecretKey, func(g Gomega, secret *corev1.Secret) {
+					g.Expect(secret.Annotations).Should(HaveKeyWithValue(dataProtectionCredentialGenerationAnnotationKey, "1"))
+				})).Should(Succeed())
+
+				By("rotating the credential")
+				Eventually(testapps.GetAndChangeObj(&testCtx, credentialSecretKey, func(secret *corev1.Secret) {
+					secret.Data["cred-key1"] = []byte("rotated-cred-val1")
+				})).Should(Succeed())
+				completePreCheckJob(repo)
+				Eventually(testapps.CheckObj(&testCtx, repoKey, func(g Gomega, repo *dpv1alpha1.BackupRepo) {
+					g.Expect(repo.Status.CredentialGeneration).Should(BeEquivalentTo(2))
+					cond := meta.FindStatusCondition(repo.Status.Conditions, ConditionTypeToolConfigUpdated)
+					g.Expect(cond).NotTo(BeNil())
+					g.Expect(cond.Status).Should(BeEquivalentTo(corev1.ConditionTrue))
+				})).Should(Succeed())
+
+				By("the secret should be updated since there are no in-progress backups")
+				Eventually(testapps.CheckObj(&testCtx, toolConfigSecretKey, func(g Gomega, secret *corev1.Secret) {
+					g.Expect(secret.Annotations).Should(HaveKeyWithValue(dataProtectionCredentialGenerationAnnotationKey, "2"))
+					g.Expect(string(secret.Data["datasafed.conf"])).Should(ContainSubstring("cred-key1=rotated-cred-val1"))
+				})).Should(Succeed())
+			})
+
+			It("should bound the deferral of the tool config update", func() {
+				repo := &dpv1alpha1.BackupRepo{}
+				Expect(isToolConfigUpdateDeferralExpired(repo)).Should(BeFalse())
+
+				By("the deferral is not expired within the timeout")
+				setCondition(repo, ConditionTypeToolConfigUpdated, metav1.ConditionFalse, ReasonWaitingForBackups, "")
+				Expect(isToolConfigUpdateDeferralExpired(repo)).Should(BeFalse())
+
+				By("the deferral is expired once the timeout is exceeded")
+				cond := meta.FindStatusCondition(repo.Status.Conditions, ConditionTypeToolConfigUpdated)
+				cond.LastTransitionTime = metav1.NewTime(time.Now().Add(-toolConfigUpdateDeferralTimeout))
+				Expect(isToolConfigUpdateDeferralExpired(repo)).Should(BeTrue())
+
+				By("the deferral is ended once the tool config is updated")
+				setCondition(repo, ConditionTypeToolConfigUpdated, metav1.ConditionTrue, ReasonToolConfigUpdated, "")
+				Expect(isToolConfigUpdateDeferralExpired(repo)).Should(BeFalse())
+			})
+
 			It("should run a pre-check job", func() {
 				By("creating a backup repo")
 				createBackupRepoSpec(func(repo *dpv1alpha1.BackupRepo) {
@@ -32,9 +32,11 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
@@ -96,7 +98,8 @@ func (r *BackupScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 // SetupWithManager sets up the controller with the Manager.
 func (r *BackupScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	b := intctrlutil.NewNamespacedControllerManagedBy(mgr).
-		For(&dpv1alpha1.BackupSchedule{})
+		For(&dpv1alpha1.BackupSchedule{}).
+		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.mapBackupToSchedule))
 
 	// Compatible with kubernetes versions prior to K8s 1.21, only supports batch v1beta1.
 	if dputils.SupportsCronJobV1() {
@@ -221,7 +224,76 @@ func (r *BackupScheduleReconciler) handleSchedule(
 		Scheme:               r.Scheme,
 		WorkerServiceAccount: saName,
 	}
-	return scheduler.Schedule()
+	if err = scheduler.Schedule(); err != nil {
+		return err
+	}
+	if err = r.patchSchedulesStatus(reqCtx, backupSchedule); err != nil {
+		return err
+	}
+	return r.patchRestorableTimeRange(reqCtx, backupSchedule, backupPolicy)
+}
+
+// patchSchedulesStatus patches the status of each schedule built from the backups created by the
+// backup schedule, so that the last successful backup can be found without listing the backups.
+// The status is persisted, so it is kept after the backups are deleted by the retention period.
+func (r *BackupScheduleReconciler) patchSchedulesStatus(
+	reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule) error {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(reqCtx.Ctx, backupList, client.InNamespace(backupSchedule.Namespace),
+		client.MatchingLabels{dptypes.BackupScheduleLabelKey: backupSchedule.Name}); err != nil {
+		return err
+	}
+	now := time.Now()
+	schedules := map[string]dpv1alpha1.ScheduleStatus{}
+	for i := range backupSchedule.Spec.Schedules {
+		schedulePolicy := &backupSchedule.Spec.Schedules[i]
+		schedules[schedulePolicy.BackupMethod] = dpbackup.BuildScheduleStatus(schedulePolicy,
+			backupSchedule.Status.Schedules[schedulePolicy.BackupMethod], backupList.Items, now)
+	}
+	if reflect.DeepEqual(backupSchedule.Status.Schedules, schedules) {
+		return nil
+	}
+	patch := client.MergeFrom(backupSchedule.DeepCopy())
+	backupSchedule.Status.Schedules = schedules
+	return r.Client.Status().Patch(reqCtx.Ctx, backupSchedule, patch)
+}
+
+// patchRestorableTimeRange patches the latest restorable time range of the cluster to the backup schedule status.
+func (r *BackupScheduleReconciler) patchRestorableTimeRange(
+	reqCtx intctrlutil.RequestCtx,
+	backupSchedule *dpv1alpha1.BackupSchedule,
+	backupPolicy *dpv1alpha1.BackupPolicy) error {
+	clusterName := backupPolicy.Labels[constant.AppInstanceLabelKey]
+	if clusterName == "" {
+		return nil
+	}
+	timeRanges, err := dputils.GetRestorableTimeRanges(reqCtx.Ctx, r.Client, clusterName, backupSchedule.Namespace)
+	if err != nil {
+		return err
+	}
+	var latest *dpv1alpha1.BackupTimeRange
+	if len(timeRanges) > 0 {
+		latest = &timeRanges[len(timeRanges)-1]
+	}
+	if reflect.DeepEqual(backupSchedule.Status.RestorableTimeRange, latest) {
+		return nil
+	}
+	patch := client.MergeFrom(backupSchedule.DeepCopy())
+	backupSchedule.Status.RestorableTimeRange = latest
+	return r.Client.Status().Patch(reqCtx.Ctx, backupSchedule, patch)
+}
+
+// mapBackupToSchedule enqueues the backup schedule which created the backup, to refresh
+// the status of its schedules and its restorable time range.
+func (r *BackupScheduleReconciler) mapBackupToSchedule(ctx context.Context, obj client.Object) []ctrl.Request {
+	scheduleName := obj.GetLabels()[dptypes.BackupScheduleLabelKey]
+	if scheduleName == "" {
+		return nil
+	}
+	return []ctrl.Request{{
+		NamespacedName: client.ObjectKey{Namespace: obj.GetNamespace(), Name: scheduleName},
+	}}
 }
 
 func (r *BackupScheduleReconciler) patchScheduleMetadata(
@@ -21,10 +21,13 @@ package dataprotection
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -35,6 +38,7 @@ import (
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -69,7 +73,8 @@ func (r *GCReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;delete
-// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // delete expired backups.
@@ -106,6 +111,15 @@ func (r *GCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 		return intctrlutil.Reconciled()
 	}
 
+	retained, err := r.isRetainedByKeepLatest(reqCtx, backup)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if retained {
+		reqCtx.Log.V(1).Info("backup has expired, but it is retained as one of the latest completed backups")
+		return intctrlutil.Reconciled()
+	}
+
 	reqCtx.Log.Info("backup has expired, delete it", "backup", req.String())
 	if err := intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, backup); err != nil {
 		reqCtx.Log.Error(err, "failed to delete backup")
@@ -116,6 +130,50 @@ func (r *GCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	return intctrlutil.Reconciled()
 }
 
+// isRetainedByKeepLatest checks whether the expired backup should be retained by the keepLatest of
+// the schedule policy with the same backup policy and backup method, and records it in the conditions.
+func (r *GCReconciler) isRetainedByKeepLatest(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (bool, error) {
+	backupScheduleList := &dpv1alpha1.BackupScheduleList{}
+	if err := r.Client.List(reqCtx.Ctx, backupScheduleList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return false, err
+	}
+	var keepLatest int32
+	for i := range backupScheduleList.Items {
+		schedulePolicy := dpbackup.GetSchedulePolicyByMethod(&backupScheduleList.Items[i], backup.Spec.BackupMethod)
+		if schedulePolicy != nil && schedulePolicy.KeepLatest != nil && *schedulePolicy.KeepLatest > keepLatest {
+			keepLatest = *schedulePolicy.KeepLatest
+		}
+	}
+	if keepLatest == 0 {
+		return false, nil
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(reqCtx.Ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return false, err
+	}
+	if !dpbackup.IsRetainedByKeepLatest(backup, backupList.Items, keepLatest) {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("the backup has expired, but it is retained as one of the latest %d completed backups", keepLatest)
+	if cond := meta.FindStatusCondition(backup.Status.Conditions, ConditionTypeRetainedByKeepLatest); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.Message == message {
+		return true, nil
+	}
+	patch := client.MergeFrom(backup.DeepCopy())
+	meta.SetStatusCondition(&backup.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeRetainedByKeepLatest,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonKeepLatestBackups,
+		Message:            message,
+		ObservedGeneration: backup.Generation,
+	})
+	return true, r.Client.Status().Patch(reqCtx.Ctx, backup, patch)
+}
+
 func getGCFrequency() time.Duration {
 	gcFrequencySeconds := viper.GetInt(dptypes.CfgKeyGCFrequencySeconds)
 	if gcFrequencySeconds > 0 {
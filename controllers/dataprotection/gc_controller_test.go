@@ -26,7 +26,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
@@ -52,6 +54,7 @@ var _ = Describe("Data Protection Garbage Collection Controller", func() {
 		testapps.ClearResources(&testCtx, generics.ClusterSignature, inNS, ml)
 		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
 		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupScheduleSignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupPolicySignature, true, inNS)
 		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS)
 
@@ -172,5 +175,65 @@ var _ = Describe("Data Protection Garbage Collection Controller", func() {
 			Eventually(testapps.CheckObjExists(&testCtx, backup1Key, &dpv1alpha1.Backup{}, true)).Should(Succeed())
 			Eventually(testapps.CheckObjExists(&testCtx, expiredKey, &dpv1alpha1.Backup{}, false)).Should(Succeed())
 		})
+
+		It("retain the latest completed backups even if they are expired", func() {
+			By("create a backup schedule keeping the latest completed backup")
+			_ = testdp.NewFakeBackupSchedule(&testCtx, func(schedule *dpv1alpha1.BackupSchedule) {
+				if schedule.Labels == nil {
+					schedule.Labels = map[string]string{}
+				}
+				schedule.Labels[dptypes.BackupPolicyLabelKey] = testdp.BackupPolicyName
+				for i := range schedule.Spec.Schedules {
+					schedule.Spec.Schedules[i].KeepLatest = pointer.Int32(1)
+				}
+			})
+
+			createBackup := func(name string) *dpv1alpha1.Backup {
+				return testdp.NewBackupFactory(testCtx.DefaultNamespace, name).
+					WithRandomName().
+					SetBackupPolicyName(testdp.BackupPolicyName).
+					SetBackupMethod(testdp.BackupMethodName).
+					Create(&testCtx).GetObject()
+			}
+
+			finishAndExpireBackup := func(backup *dpv1alpha1.Backup, jobCondition batchv1.JobConditionType,
+				phase dpv1alpha1.BackupPhase, completionTime time.Time) client.ObjectKey {
+				key := client.ObjectKeyFromObject(backup)
+				testdp.PatchK8sJobStatus(&testCtx, getJobKey(backup), jobCondition)
+				Eventually(testapps.CheckObj(&testCtx, key, func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(phase))
+				})).Should(Succeed())
+				Eventually(testapps.GetAndChangeObjStatus(&testCtx, key, func(fetched *dpv1alpha1.Backup) {
+					fetched.Status.Expiration = &metav1.Time{Time: fakeClock.Now().Add(-time.Hour)}
+					fetched.Status.CompletionTimestamp = &metav1.Time{Time: completionTime}
+				})).Should(Succeed())
+				return key
+			}
+
+			By("create interleaved completed and failed backups, all of them are expired")
+			now := fakeClock.Now()
+			completedKey1 := finishAndExpireBackup(createBackup(backupNamePrefix+"completed-1"),
+				batchv1.JobComplete, dpv1alpha1.BackupPhaseCompleted, now.Add(-time.Hour*4))
+			failedKey1 := finishAndExpireBackup(createBackup(backupNamePrefix+"failed-1"),
+				batchv1.JobFailed, dpv1alpha1.BackupPhaseFailed, now.Add(-time.Hour*3))
+			completedKey2 := finishAndExpireBackup(createBackup(backupNamePrefix+"completed-2"),
+				batchv1.JobComplete, dpv1alpha1.BackupPhaseCompleted, now.Add(-time.Hour*2))
+			failedKey2 := finishAndExpireBackup(createBackup(backupNamePrefix+"failed-2"),
+				batchv1.JobFailed, dpv1alpha1.BackupPhaseFailed, now.Add(-time.Hour))
+
+			By("the expired failed backups and the older completed backup are deleted")
+			Eventually(testapps.CheckObjExists(&testCtx, failedKey1, &dpv1alpha1.Backup{}, false)).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, failedKey2, &dpv1alpha1.Backup{}, false)).Should(Succeed())
+			Eventually(testapps.CheckObjExists(&testCtx, completedKey1, &dpv1alpha1.Backup{}, false)).Should(Succeed())
+
+			By("the latest completed backup is retained")
+			Eventually(testapps.CheckObj(&testCtx, completedKey2, func(g Gomega, fetched *dpv1alpha1.Backup) {
+				cond := meta.FindStatusCondition(fetched.Status.Conditions, ConditionTypeRetainedByKeepLatest)
+				g.Expect(cond).ShouldNot(BeNil())
+				g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+				g.Expect(cond.Reason).Should(Equal(ReasonKeepLatestBackups))
+			})).Should(Succeed())
+			Consistently(testapps.CheckObjExists(&testCtx, completedKey2, &dpv1alpha1.Backup{}, true)).Should(Succeed())
+		})
 	})
 })
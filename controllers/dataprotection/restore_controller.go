@@ -139,6 +139,9 @@ func (r *RestoreReconciler) newAction(reqCtx intctrlutil.RequestCtx, restore *dp
 	if _, ok := restore.Labels[constant.AppManagedByLabelKey]; !ok {
 		restore.Labels[constant.AppManagedByLabelKey] = dptypes.AppName
 	}
+	// label the restore with the source backup, the backup files are kept until the restore is finished.
+	restore.Labels[dptypes.BackupNameLabelKey] = restore.Spec.Backup.Name
+	restore.Labels[dptypes.BackupNamespaceLabelKey] = restore.Spec.Backup.Namespace
 	if !reflect.DeepEqual(restore.ObjectMeta, oldRestore.ObjectMeta) {
 		if err := r.Client.Patch(reqCtx.Ctx, restore, patch); err != nil {
 			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
@@ -149,7 +152,8 @@ func (r *RestoreReconciler) newAction(reqCtx intctrlutil.RequestCtx, restore *dp
 		restore.Status.Phase = dpv1alpha1.RestorePhaseAsDataSource
 	} else {
 		// check if restore CR is legal
-		err := dprestore.ValidateAndInitRestoreMGR(reqCtx, r.Client, dprestore.NewRestoreManager(restore, r.Recorder, r.Scheme))
+		restoreMgr := dprestore.NewRestoreManager(restore, r.Recorder, r.Scheme)
+		err := dprestore.ValidateAndInitRestoreMGR(reqCtx, r.Client, restoreMgr)
 		switch {
 		case intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal):
 			restore.Status.Phase = dpv1alpha1.RestorePhaseFailed
@@ -157,7 +161,14 @@ func (r *RestoreReconciler) newAction(reqCtx intctrlutil.RequestCtx, restore *dp
 			r.Recorder.Event(restore, corev1.EventTypeWarning, dprestore.ReasonRestoreFailed, err.Error())
 		case err != nil:
 			return RecorderEventAndRequeue(reqCtx, r.Recorder, restore, err)
+		case restore.Spec.Preflight:
+			if err = r.handlePreflight(reqCtx, restoreMgr); err != nil {
+				return RecorderEventAndRequeue(reqCtx, r.Recorder, restore, err)
+			}
 		default:
+			if err = r.recordBackupUsage(reqCtx, restore); err != nil {
+				return RecorderEventAndRequeue(reqCtx, r.Recorder, restore, err)
+			}
 			restore.Status.StartTimestamp = &metav1.Time{Time: time.Now()}
 			restore.Status.Phase = dpv1alpha1.RestorePhaseRunning
 			r.Recorder.Event(restore, corev1.EventTypeNormal, dprestore.ReasonRestoreStarting, "start to restore")
@@ -169,6 +180,56 @@ func (r *RestoreReconciler) newAction(reqCtx intctrlutil.RequestCtx, restore *dp
 	return intctrlutil.Reconciled()
 }
 
+// recordBackupUsage increases the number of restores from the source backup and records the restore time,
+// which helps to decide whether the backup is still needed. The optimistic lock avoids losing the count
+// of the concurrent restores from the same backup, and the UID of the last counted restore avoids counting
+// the restore again if its status fails to be updated and the reconciliation is retried.
+func (r *RestoreReconciler) recordBackupUsage(reqCtx intctrlutil.RequestCtx, restore *dpv1alpha1.Restore) error {
+	backup := &dpv1alpha1.Backup{}
+	if err := r.Client.Get(reqCtx.Ctx, client.ObjectKey{Namespace: restore.Spec.Backup.Namespace,
+		Name: restore.Spec.Backup.Name}, backup); err != nil {
+		return err
+	}
+	if backup.Status.LastRestoreUID == restore.UID {
+		return nil
+	}
+	patch := client.MergeFromWithOptions(backup.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	backup.Status.UsedByRestores++
+	backup.Status.LastRestoreUID = restore.UID
+	backup.Status.LastRestoreTime = &metav1.Time{Time: time.Now()}
+	return r.Client.Status().Patch(reqCtx.Ctx, backup, patch)
+}
+
+// handlePreflight checks whether the backups can be restored into the target environment and completes
+// the restore with the result, no restore workloads are created. The restore is kept in the initial phase
+// until the jobs of the checks are finished.
+func (r *RestoreReconciler) handlePreflight(reqCtx intctrlutil.RequestCtx, restoreMgr *dprestore.RestoreManager) error {
+	restore := restoreMgr.Restore
+	saName := restore.Spec.ServiceAccountName
+	if saName == "" {
+		var err error
+		if saName, err = EnsureWorkerServiceAccount(reqCtx, r.Client, restore.Namespace); err != nil {
+			return err
+		}
+	}
+	restoreMgr.WorkerServiceAccount = saName
+	finished, passed, err := dprestore.RunPreflightChecks(reqCtx, r.Client, restoreMgr)
+	if err != nil || !finished {
+		return err
+	}
+	restore.Status.StartTimestamp = &metav1.Time{Time: time.Now()}
+	restore.Status.CompletionTimestamp = restore.Status.StartTimestamp
+	restore.Status.Duration = dprestore.GetRestoreDuration(restore.Status)
+	if passed {
+		restore.Status.Phase = dpv1alpha1.RestorePhaseCompleted
+		r.Recorder.Event(restore, corev1.EventTypeNormal, dprestore.ReasonPreflightPassed, "all preflight checks passed")
+	} else {
+		restore.Status.Phase = dpv1alpha1.RestorePhaseFailed
+		r.Recorder.Event(restore, corev1.EventTypeWarning, dprestore.ReasonPreflightFailed, "some preflight checks failed, see the conditions for details")
+	}
+	return nil
+}
+
 func (r *RestoreReconciler) handleRunningPhase(reqCtx intctrlutil.RequestCtx, restore *dpv1alpha1.Restore) (ctrl.Result, error) {
 	restoreMgr := dprestore.NewRestoreManager(restore, r.Recorder, r.Scheme)
 	// validate if the restore.spec is valid and build restore manager.
@@ -364,6 +425,15 @@ func (r *RestoreReconciler) handleBackupActionSet(reqCtx intctrlutil.RequestCtx,
 	if len(jobs) == 0 {
 		return true, nil
 	}
+	// the exec jobs run with the exec worker service account in the namespace of KubeBlocks.
+	for _, job := range jobs {
+		if job.Spec.Template.Spec.ServiceAccountName == viper.GetString(dptypes.CfgKeyExecWorkerServiceAccountName) {
+			if err = ensureExecWorkerRoleBinding(reqCtx, r.Client, restoreMgr.Restore.Namespace); err != nil {
+				return false, err
+			}
+			break
+		}
+	}
 	// 3. create jobs
 	jobs, err = restoreMgr.CreateJobsIfNotExist(reqCtx, r.Client, restoreMgr.Restore, jobs)
 	if err != nil {
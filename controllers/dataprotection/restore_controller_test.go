@@ -34,6 +34,8 @@ import (
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dprestore "github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
@@ -302,6 +304,77 @@ var _ = Describe("Restore Controller test", func() {
 
 		})
 
+		Context("test the usage of the backup", func() {
+			var (
+				restore   *dpv1alpha1.Restore
+				backupKey client.ObjectKey
+			)
+
+			BeforeEach(func() {
+				restore = initResourcesAndWaitRestore(true, false, false, dpv1alpha1.RestorePhaseRunning,
+					func(f *testdp.MockRestoreFactory) {
+						f.SetVolumeClaimsTemplate(testdp.MysqlTemplateName, testdp.DataVolumeName,
+							testdp.DataVolumeMountPath, "", int32(1), int32(0), nil)
+					})
+				backupKey = client.ObjectKey{Namespace: restore.Spec.Backup.Namespace, Name: restore.Spec.Backup.Name}
+
+				By("check the restore is labeled with the backup, and the usage is recorded")
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(restore), func(g Gomega, r *dpv1alpha1.Restore) {
+					g.Expect(r.Labels[dptypes.BackupNameLabelKey]).Should(Equal(backupKey.Name))
+					g.Expect(r.Labels[dptypes.BackupNamespaceLabelKey]).Should(Equal(backupKey.Namespace))
+				})).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, b *dpv1alpha1.Backup) {
+					g.Expect(b.Status.UsedByRestores).Should(BeEquivalentTo(1))
+					g.Expect(b.Status.LastRestoreTime).ShouldNot(BeNil())
+				})).Should(Succeed())
+			})
+
+			It("should not count the restore again when it is retried", func() {
+				reconciler := &RestoreReconciler{Client: k8sClient}
+				reqCtx := intctrlutil.RequestCtx{Ctx: ctx}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(restore), restore)).Should(Succeed())
+				Expect(reconciler.recordBackupUsage(reqCtx, restore)).Should(Succeed())
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, b *dpv1alpha1.Backup) {
+					g.Expect(b.Status.UsedByRestores).Should(BeEquivalentTo(1))
+					g.Expect(b.Status.LastRestoreUID).Should(Equal(restore.UID))
+				})).Should(Succeed())
+			})
+
+			It("should keep the backup files until the restore is completed", func() {
+				By("delete the backup while restoring")
+				backup := &dpv1alpha1.Backup{}
+				Expect(k8sClient.Get(ctx, backupKey, backup)).Should(Succeed())
+				testapps.DeleteObject(&testCtx, backupKey, &dpv1alpha1.Backup{})
+				jobKey := dpbackup.BuildDeleteBackupFilesJobKey(backup, false)
+				Eventually(testapps.CheckObj(&testCtx, backupKey, func(g Gomega, b *dpv1alpha1.Backup) {
+					g.Expect(b.Status.Phase).Should(Equal(dpv1alpha1.BackupPhaseDeleting))
+				})).Should(Succeed())
+				Consistently(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, false)).Should(Succeed())
+
+				By("complete the restore, the backup files should be deleted")
+				mockRestoreJobsCompleted(restore)
+				Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(restore), func(g Gomega, r *dpv1alpha1.Restore) {
+					g.Expect(r.Status.Phase).Should(Equal(dpv1alpha1.RestorePhaseCompleted))
+				})).Should(Succeed())
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+			})
+
+			It("should delete the backup files while restoring if the backup is force deleted", func() {
+				By("delete the backup with the force-delete annotation")
+				backup := &dpv1alpha1.Backup{}
+				Expect(k8sClient.Get(ctx, backupKey, backup)).Should(Succeed())
+				Expect(testapps.ChangeObj(&testCtx, backup, func(b *dpv1alpha1.Backup) {
+					if b.Annotations == nil {
+						b.Annotations = map[string]string{}
+					}
+					b.Annotations[dptypes.ForceDeleteAnnotationKey] = "true"
+				})).Should(Succeed())
+				testapps.DeleteObject(&testCtx, backupKey, &dpv1alpha1.Backup{})
+				jobKey := dpbackup.BuildDeleteBackupFilesJobKey(backup, false)
+				Eventually(testapps.CheckObjExists(&testCtx, jobKey, &batchv1.Job{}, true)).Should(Succeed())
+			})
+		})
+
 		Context("test postReady stage", func() {
 			var _ *testdp.BackupClusterInfo
 			BeforeEach(func() {
@@ -368,11 +441,7 @@ func mockBackupForRestore(actionSetName, backupPVCName string, mockBackupComplet
 		if useVolumeSnapshotBackup {
 			backupMethodName = testdp.VSBackupMethodName
 		}
-		Expect(testapps.ChangeObjStatus(&testCtx, backup, func() {
-			backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
-			backup.Status.PersistentVolumeClaimName = backupPVCName
-			testdp.MockBackupStatusMethod(backup, backupMethodName, testdp.DataVolumeName, actionSetName)
-		})).Should(Succeed())
+		testdp.MockBackupCompleted(&testCtx, backup, backupMethodName, actionSetName, backupPVCName, nil)
 	}
 	return backup
 }
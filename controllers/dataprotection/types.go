@@ -37,24 +37,83 @@ const (
 	maxConcurDataProtectionReconKey = "MAXCONCURRENTRECONCILES_DATAPROTECTION"
 
 	// label keys
-	dataProtectionBackupRepoKey          = "dataprotection.kubeblocks.io/backup-repo-name"
-	dataProtectionWaitRepoPreparationKey = "dataprotection.kubeblocks.io/wait-repo-preparation"
-	dataProtectionIsToolConfigKey        = "dataprotection.kubeblocks.io/is-tool-config"
+	dataProtectionBackupRepoKey              = "dataprotection.kubeblocks.io/backup-repo-name"
+	dataProtectionWaitRepoPreparationKey     = "dataprotection.kubeblocks.io/wait-repo-preparation"
+	dataProtectionWaitCopyRepoPreparationKey = "dataprotection.kubeblocks.io/wait-copy-repo-preparation"
+	dataProtectionIsToolConfigKey            = "dataprotection.kubeblocks.io/is-tool-config"
+	dataProtectionKopiaMaintenanceKey        = "dataprotection.kubeblocks.io/kopia-maintenance"
 
 	// annotation keys
 	dataProtectionBackupRepoDigestAnnotationKey     = "dataprotection.kubeblocks.io/backup-repo-digest"
 	dataProtectionNeedUpdateToolConfigAnnotationKey = "dataprotection.kubeblocks.io/need-update-tool-config"
+	dataProtectionBackupRepoSelectedAtAnnotationKey = "dataprotection.kubeblocks.io/backup-repo-selected-at"
+	dataProtectionCredentialDigestAnnotationKey     = "dataprotection.kubeblocks.io/credential-digest"
+	// the generation of the credential of the backup repo, which is used by the tool config secret or the backup.
+	dataProtectionCredentialGenerationAnnotationKey = "dataprotection.kubeblocks.io/credential-generation"
+
+	// defaultBackupRepoFailoverTimeout is the default duration to wait for a backup repo
+	// to become ready before failing over to the next fallback backup repo.
+	defaultBackupRepoFailoverTimeout = 5 * time.Minute
+
+	// verificationThrottleInterval is the interval to wait for the in-progress backups stored
+	// in the same backup repo before verifying a backup.
+	verificationThrottleInterval = 30 * time.Second
+
+	// credentialRotationCheckInterval is the interval to check whether the in-progress backups
+	// are finished to update their tool config secrets with the rotated credential.
+	credentialRotationCheckInterval = 30 * time.Second
+
+	// toolConfigUpdateDeferralTimeout is the maximum duration to defer the update of the tool config
+	// secrets for the in-progress backups, the secrets are updated anyway once it is exceeded,
+	// so that the new backups don't keep using the stale credential.
+	toolConfigUpdateDeferralTimeout = time.Hour
+
+	// kopiaMaintenanceCheckInterval is the interval to check whether the maintenance jobs of the Kopia
+	// repositories are finished, or the running backups blocking the maintenance are finished.
+	kopiaMaintenanceCheckInterval = 30 * time.Second
+
+	// copyBackupCheckInterval is the interval to check whether the backup repo to copy the
+	// backup to is ready.
+	copyBackupCheckInterval = 30 * time.Second
+
+	// continuousBackupTrimInterval is the minimum interval between two trims of a continuous backup,
+	// the data out of the retention period is trimmed in batches to avoid scaling down the backup
+	// workload frequently.
+	continuousBackupTrimInterval = time.Hour
+
+	// initialRetryBackoff is the backoff before the first retry of a failed action, it is
+	// doubled after each retry until reaching the backoff limit of the retry policy.
+	initialRetryBackoff = 10 * time.Second
+
+	// defaultRetryBackoffLimit is the default upper limit of the backoff between retries.
+	defaultRetryBackoffLimit = 5 * time.Minute
+
+	// retryBackoffJitter is the maximum factor of the random jitter added to the backoff.
+	retryBackoffJitter = 0.1
+
+	// serviceAccountSubjectIndexKey is the field index of the role bindings and cluster role bindings
+	// by their service account subjects, in the format of "<namespace>/<name>".
+	serviceAccountSubjectIndexKey = "subjects.serviceAccount"
 )
 
 // condition constants
 const (
 	// condition types
-	ConditionTypeStorageProviderReady  = "StorageProviderReady"
-	ConditionTypeParametersChecked     = "ParametersChecked"
-	ConditionTypeStorageClassCreated   = "StorageClassCreated"
-	ConditionTypePVCTemplateChecked    = "PVCTemplateChecked"
-	ConditionTypeDerivedObjectsDeleted = "DerivedObjectsDeleted"
-	ConditionTypePreCheckPassed        = "PreCheckPassed"
+	ConditionTypeStorageProviderReady    = "StorageProviderReady"
+	ConditionTypeParametersChecked       = "ParametersChecked"
+	ConditionTypeStorageClassCreated     = "StorageClassCreated"
+	ConditionTypePVCTemplateChecked      = "PVCTemplateChecked"
+	ConditionTypeDerivedObjectsDeleted   = "DerivedObjectsDeleted"
+	ConditionTypePreCheckPassed          = "PreCheckPassed"
+	ConditionTypeDryRunPassed            = "DryRunPassed"
+	ConditionTypePostBackupHookSucceeded = "PostBackupHookSucceeded"
+	ConditionTypeVerified                = "Verified"
+	ConditionTypePaused                  = "Paused"
+	ConditionTypeTrimmed                 = "Trimmed"
+	ConditionTypeTargetReady             = "TargetReady"
+	ConditionTypeRetainedByKeepLatest    = "RetainedByKeepLatest"
+	ConditionTypeCompleted               = "Completed"
+	ConditionTypeToolConfigUpdated       = "ToolConfigUpdated"
 
 	// condition reasons
 	ReasonStorageProviderReady      = "StorageProviderReady"
@@ -76,6 +135,22 @@ const (
 	ReasonDigestChanged             = "DigestChanged"
 	ReasonUnknownError              = "UnknownError"
 	ReasonSkipped                   = "Skipped"
+	ReasonDryRunPassed              = "DryRunPassed"
+	ReasonDryRunFailed              = "DryRunFailed"
+	ReasonPostBackupHookFailed      = "PostBackupHookFailed"
+	ReasonVerificationPassed        = "VerificationPassed"
+	ReasonVerificationFailed        = "VerificationFailed"
+	ReasonBackupPaused              = "BackupPaused"
+	ReasonBackupResumed             = "BackupResumed"
+	ReasonBackupRepoReadOnly        = "BackupRepoReadOnly"
+	ReasonTrimSucceeded             = "TrimSucceeded"
+	ReasonTrimFailed                = "TrimFailed"
+	ReasonTargetPodNotReady         = "TargetPodNotReady"
+	ReasonKeepLatestBackups         = "KeepLatestBackups"
+	ReasonRetryingAction            = "RetryingAction"
+	ReasonToolConfigUpdated         = "ToolConfigUpdated"
+	ReasonWaitingForBackups         = "WaitingForBackups"
+	ReasonToolConfigUpdateTimeout   = "ToolConfigUpdateTimeout"
 )
 
 // constant  for volume populator
@@ -27,16 +27,20 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -47,6 +51,7 @@ import (
 	dperrors "github.com/apecloud/kubeblocks/pkg/dataprotection/errors"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
@@ -128,49 +133,134 @@ func HandleBackupRepo(request *dpbackup.Request) error {
 	return nil
 }
 
+// isBackupRepoPrepared checks whether the resources to access the backup repo, i.e. the PVC
+// or the tool config secret, have been prepared in the namespace by the backup repo controller.
+func isBackupRepoPrepared(ctx context.Context, cli client.Client, repo *dpv1alpha1.BackupRepo, namespace string) (bool, error) {
+	var key client.ObjectKey
+	var obj client.Object
+	switch {
+	case repo.AccessByMount():
+		key, obj = client.ObjectKey{Namespace: namespace, Name: repo.Status.BackupPVCName}, &corev1.PersistentVolumeClaim{}
+	case repo.AccessByTool():
+		key, obj = client.ObjectKey{Namespace: namespace, Name: repo.Status.ToolConfigSecretName}, &corev1.Secret{}
+	default:
+		return false, fmt.Errorf("unknown access method of backup repo %s: %s", repo.Name, repo.Spec.AccessMethod)
+	}
+	if key.Name == "" {
+		return false, nil
+	}
+	return intctrlutil.CheckResourceExists(ctx, cli, key, obj)
+}
+
+func existPodSelector(selector *dpv1alpha1.PodSelector) bool {
+	return selector != nil && selector.LabelSelector != nil
+}
+
+// getBackupTarget returns the target of the backup method if it specifies the pod selector,
+// otherwise returns the global target of the backup policy.
+func getBackupTarget(backupPolicy *dpv1alpha1.BackupPolicy, backupMethod *dpv1alpha1.BackupMethod) *dpv1alpha1.BackupTarget {
+	if backupMethod != nil && backupMethod.Target != nil && existPodSelector(backupMethod.Target.PodSelector) {
+		return backupMethod.Target
+	}
+	return backupPolicy.Spec.Target
+}
+
+// getTargetNamespace returns the namespace of the target pods, it defaults to the namespace of the backup policy.
+func getTargetNamespace(backupPolicy *dpv1alpha1.BackupPolicy, target *dpv1alpha1.BackupTarget) string {
+	if target != nil && target.Namespace != "" {
+		return target.Namespace
+	}
+	return backupPolicy.Namespace
+}
+
+// validateTargetNamespace checks whether the backup policy is allowed to back up the target pods in the namespace,
+// the target pods in other namespaces are rejected if the cross-namespace backup is not enabled.
+func validateTargetNamespace(backupPolicy *dpv1alpha1.BackupPolicy, namespace string) error {
+	if namespace == backupPolicy.Namespace {
+		return nil
+	}
+	if !viper.GetBool(dptypes.CfgKeyEnableCrossNamespaceBackup) {
+		return fmt.Errorf("the target namespace %s of BackupPolicy %s/%s is not allowed since the cross-namespace backup is not enabled",
+			namespace, backupPolicy.Namespace, backupPolicy.Name)
+	}
+	if !intctrlutil.IsManagedNamespace(namespace) {
+		return fmt.Errorf("the target namespace %s of BackupPolicy %s/%s is not managed", namespace, backupPolicy.Namespace, backupPolicy.Name)
+	}
+	return nil
+}
+
+// validateBackupPolicyTargets validates the namespaces of the targets of the backup policy and its backup methods.
+func validateBackupPolicyTargets(backupPolicy *dpv1alpha1.BackupPolicy) error {
+	if err := validateTargetNamespace(backupPolicy, getTargetNamespace(backupPolicy, backupPolicy.Spec.Target)); err != nil {
+		return err
+	}
+	for i := range backupPolicy.Spec.BackupMethods {
+		method := &backupPolicy.Spec.BackupMethods[i]
+		namespace := getTargetNamespace(backupPolicy, getBackupTarget(backupPolicy, method))
+		if err := validateTargetNamespace(backupPolicy, namespace); err != nil {
+			return err
+		}
+		if namespace != backupPolicy.Namespace && boolptr.IsSetToTrue(method.SnapshotVolumes) {
+			return fmt.Errorf("backup method %s takes volume snapshots which is not supported for the target namespace %s", method.Name, namespace)
+		}
+	}
+	return nil
+}
+
 // GetTargetPods gets the target pods by BackupPolicy. If podName is not empty,
 // it will return the pod which name is podName. Otherwise, it will return the
-// pods which are selected by BackupPolicy selector and strategy.
+// pods which are selected by BackupPolicy selector and strategy. If allowNotReady
+// is false, it returns an error if the selected pods are not ready.
 func GetTargetPods(reqCtx intctrlutil.RequestCtx,
 	cli client.Client, podName string,
 	backupMethod *dpv1alpha1.BackupMethod,
 	backupPolicy *dpv1alpha1.BackupPolicy,
+	allowNotReady bool,
 ) ([]*corev1.Pod, error) {
 	if backupMethod == nil {
 		return nil, nil
 	}
-	existPodSelector := func(selector *dpv1alpha1.PodSelector) bool {
-		return selector != nil && selector.LabelSelector != nil
+	target := getBackupTarget(backupPolicy, backupMethod)
+	if target == nil || !existPodSelector(target.PodSelector) {
+		return nil, nil
 	}
-	var selector *dpv1alpha1.PodSelector
-	if backupMethod.Target != nil && existPodSelector(backupMethod.Target.PodSelector) {
-		selector = backupMethod.Target.PodSelector
-	} else {
-		// using global target policy.
-		selector = backupPolicy.Spec.Target.PodSelector
-		if !existPodSelector(selector) {
-			return nil, nil
+	selector := target.PodSelector
+	namespace := getTargetNamespace(backupPolicy, target)
+	if err := validateTargetNamespace(backupPolicy, namespace); err != nil {
+		return nil, err
+	}
+	listPods := func(ls *metav1.LabelSelector) (*corev1.PodList, error) {
+		labelSelector, err := metav1.LabelSelectorAsSelector(ls)
+		if err != nil {
+			return nil, err
+		}
+		pods := &corev1.PodList{}
+		if err = cli.List(reqCtx.Ctx, pods,
+			client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+			return nil, err
 		}
+		return pods, nil
 	}
-	labelSelector, err := metav1.LabelSelectorAsSelector(selector.LabelSelector)
+	pods, err := listPods(selector.LabelSelector)
 	if err != nil {
 		return nil, err
 	}
-	pods := &corev1.PodList{}
-	if err = cli.List(reqCtx.Ctx, pods,
-		client.InNamespace(reqCtx.Req.Namespace),
-		client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
-		return nil, err
+	fallbackPods := &corev1.PodList{}
+	if selector.Strategy == dpv1alpha1.PodSelectionStrategyAny && selector.FallbackRole != "" {
+		if fallbackPods, err = listPods(buildFallbackRoleSelector(selector)); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(pods.Items) == 0 {
-		return nil, fmt.Errorf("failed to find target pods by backup policy %s/%s",
-			backupPolicy.Namespace, backupPolicy.Name)
+	if len(pods.Items) == 0 && len(fallbackPods.Items) == 0 {
+		return nil, dperrors.NewNoTargetPods(backupPolicy.Namespace, backupPolicy.Name)
 	}
 
 	var targetPods []*corev1.Pod
 	if podName != "" && selector.Strategy == dpv1alpha1.PodSelectionStrategyAny {
-		for _, pod := range pods.Items {
+		// the target pod may be selected by the fallback role.
+		for _, pod := range append(pods.Items, fallbackPods.Items...) {
 			if pod.Name == podName {
 				targetPods = append(targetPods, &pod)
 				break
@@ -185,18 +275,72 @@ func GetTargetPods(reqCtx intctrlutil.RequestCtx,
 	switch selector.Strategy {
 	case dpv1alpha1.PodSelectionStrategyAny:
 		pod := dputils.GetFirstIndexRunningPod(pods)
-		if pod != nil {
-			targetPods = append(targetPods, pod)
+		if pod == nil {
+			// fall back to the pods with the fallback role if none of the pods is ready
+			pod = dputils.GetFirstIndexRunningPod(fallbackPods)
 		}
+		if pod == nil {
+			if !allowNotReady {
+				return nil, dperrors.NewTargetPodsNotReady(getPodNames(append(pods.Items, fallbackPods.Items...)),
+					backupPolicy.Namespace, backupPolicy.Name)
+			}
+			// back up from the first pod even if it is not ready, the pods are sorted by name.
+			if len(pods.Items) > 0 {
+				pod = &pods.Items[0]
+			} else {
+				pod = &fallbackPods.Items[0]
+			}
+		}
+		targetPods = append(targetPods, pod)
 	case dpv1alpha1.PodSelectionStrategyAll:
 		for i := range pods.Items {
 			targetPods = append(targetPods, &pods.Items[i])
 		}
+		if notReadyPods := getNotReadyPods(targetPods); len(notReadyPods) > 0 && !allowNotReady {
+			return nil, dperrors.NewTargetPodsNotReady(notReadyPods, backupPolicy.Namespace, backupPolicy.Name)
+		}
 	}
 
 	return targetPods, nil
 }
 
+// getNotReadyPods returns the names of the pods which are not ready.
+func getNotReadyPods(pods []*corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		if !intctrlutil.IsAvailable(pod, 0) {
+			names = append(names, pod.Name)
+		}
+	}
+	return names
+}
+
+func getPodNames(pods []corev1.Pod) []string {
+	names := make([]string, len(pods))
+	for i := range pods {
+		names[i] = pods[i].Name
+	}
+	return names
+}
+
+// buildFallbackRoleSelector builds the label selector to select the pods with the fallback role,
+// the role requirements of the original selector are replaced by the fallback role.
+func buildFallbackRoleSelector(selector *dpv1alpha1.PodSelector) *metav1.LabelSelector {
+	fallbackSelector := selector.LabelSelector.DeepCopy()
+	if fallbackSelector.MatchLabels == nil {
+		fallbackSelector.MatchLabels = map[string]string{}
+	}
+	fallbackSelector.MatchLabels[constant.RoleLabelKey] = selector.FallbackRole
+	var matchExpressions []metav1.LabelSelectorRequirement
+	for _, req := range fallbackSelector.MatchExpressions {
+		if req.Key != constant.RoleLabelKey {
+			matchExpressions = append(matchExpressions, req)
+		}
+	}
+	fallbackSelector.MatchExpressions = matchExpressions
+	return fallbackSelector
+}
+
 // getCluster gets the cluster and will ignore the error.
 func getCluster(ctx context.Context,
 	cli client.Client,
@@ -220,8 +364,151 @@ func getClusterLabelKeys() []string {
 	return []string{constant.AppInstanceLabelKey, constant.KBAppComponentLabelKey}
 }
 
+// getPropagatedClusterLabelKeys returns the keys of the cluster labels which should be propagated to the backups.
+func getPropagatedClusterLabelKeys() []string {
+	var keys []string
+	for _, key := range strings.Split(viper.GetString(dptypes.CfgKeyPropagateClusterLabels), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// calculateBackupRepoUsage calculates the space consumed by the backups stored in the backup repo,
+// the backups which are being deleted are excluded.
+func calculateBackupRepoUsage(ctx context.Context, cli client.Client, repoName string) (*dpv1alpha1.BackupRepoUsage, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.MatchingLabels{
+		dataProtectionBackupRepoKey: repoName,
+	}); err != nil {
+		return nil, err
+	}
+	usage := &dpv1alpha1.BackupRepoUsage{
+		PerNamespace: map[string]resource.Quantity{},
+	}
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		if !backup.DeletionTimestamp.IsZero() || backup.Status.Phase == dpv1alpha1.BackupPhaseDeleting ||
+			backup.Status.TotalSize == "" {
+			continue
+		}
+		size, err := resource.ParseQuantity(backup.Status.TotalSize)
+		if err != nil {
+			// ignore the backup with an invalid total size
+			continue
+		}
+		usage.Total.Add(size)
+		nsUsage := usage.PerNamespace[backup.Namespace]
+		nsUsage.Add(size)
+		usage.PerNamespace[backup.Namespace] = nsUsage
+	}
+	return usage, nil
+}
+
+// checkBackupRepoQuota checks whether the quota of the backup repo is exceeded by the backups
+// stored in it, the new backups in the namespace should be rejected if so.
+func checkBackupRepoQuota(ctx context.Context, cli client.Client, repo *dpv1alpha1.BackupRepo, namespace string) error {
+	quota := repo.Spec.Quota
+	if quota == nil || (quota.Total == nil && len(quota.PerNamespace) == 0) {
+		return nil
+	}
+	usage, err := calculateBackupRepoUsage(ctx, cli, repo.Name)
+	if err != nil {
+		return err
+	}
+	if quota.Total != nil && usage.Total.Cmp(*quota.Total) > 0 {
+		return dperrors.NewBackupRepoQuotaExceeded(repo.Name, "all namespaces",
+			usage.Total.String(), quota.Total.String())
+	}
+	if limit, ok := quota.PerNamespace[namespace]; ok {
+		used := usage.PerNamespace[namespace]
+		if used.Cmp(limit) > 0 {
+			return dperrors.NewBackupRepoQuotaExceeded(repo.Name, fmt.Sprintf("namespace %s", namespace),
+				used.String(), limit.String())
+		}
+	}
+	return nil
+}
+
+// checkBackupRepoFreeSpace checks whether the free space of the backup repo PVC is enough for
+// the new backup, whose size is estimated by the size of the previous backup of the same backup
+// policy and backup method. It only applies to the backup repo accessed by mount.
+func checkBackupRepoFreeSpace(ctx context.Context, cli client.Client, request *dpbackup.Request) error {
+	repo, pvc := request.BackupRepo, request.BackupRepoPVC
+	if repo == nil || pvc == nil {
+		return nil
+	}
+	capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+	if !ok || capacity.IsZero() {
+		capacity = repo.Spec.VolumeCapacity
+	}
+	if capacity.IsZero() {
+		return nil
+	}
+	usage := repo.Status.Usage
+	if usage == nil {
+		var err error
+		if usage, err = calculateBackupRepoUsage(ctx, cli, repo.Name); err != nil {
+			return err
+		}
+	}
+	free := capacity.DeepCopy()
+	free.Sub(usage.Total)
+	estimated, err := estimateBackupSize(ctx, cli, request.Backup)
+	if err != nil {
+		return err
+	}
+	if free.Sign() <= 0 || free.Cmp(estimated) < 0 {
+		if free.Sign() < 0 {
+			free = resource.Quantity{}
+		}
+		return dperrors.NewBackupRepoOutOfSpace(repo.Name, free.String(), estimated.String())
+	}
+	return nil
+}
+
+// estimateBackupSize estimates the size of the backup by the total size of the latest completed
+// backup of the same backup policy and backup method, scaled by the configured percentage.
+func estimateBackupSize(ctx context.Context, cli client.Client, backup *dpv1alpha1.Backup) (resource.Quantity, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return resource.Quantity{}, err
+	}
+	var previous *dpv1alpha1.Backup
+	for i := range backupList.Items {
+		item := &backupList.Items[i]
+		if item.Name == backup.Name || item.Spec.BackupMethod != backup.Spec.BackupMethod ||
+			item.Status.Phase != dpv1alpha1.BackupPhaseCompleted || item.IsDryRun() || item.Status.TotalSize == "" ||
+			item.Status.CompletionTimestamp == nil {
+			continue
+		}
+		if previous == nil || previous.Status.CompletionTimestamp.Before(item.Status.CompletionTimestamp) {
+			previous = item
+		}
+	}
+	if previous == nil {
+		return resource.Quantity{}, nil
+	}
+	size, err := resource.ParseQuantity(previous.Status.TotalSize)
+	if err != nil {
+		// ignore the backup with an invalid total size
+		return resource.Quantity{}, nil
+	}
+	percent := int64(viper.GetInt(dptypes.CfgKeyBackupSizeEstimatePercent))
+	return *resource.NewQuantity(size.Value()*percent/100, resource.BinarySI), nil
+}
+
 // sendWarningEventForError sends warning event for backup controller error
 func sendWarningEventForError(recorder record.EventRecorder, obj client.Object, err error) {
+	// the failure code is used as the reason of the backup events if the failure is categorized.
+	if _, ok := obj.(*dpv1alpha1.Backup); ok {
+		if code := getBackupFailureCode(err); code != dpv1alpha1.BackupFailureCodeUnknown {
+			recorder.Eventf(obj, corev1.EventTypeWarning, string(code), err.Error())
+			return
+		}
+	}
 	controllerErr := intctrlutil.UnwrapControllerError(err)
 	if controllerErr != nil {
 		recorder.Eventf(obj, corev1.EventTypeWarning, string(controllerErr.Type), err.Error())
@@ -277,6 +564,55 @@ func deleteRelatedJobs(reqCtx intctrlutil.RequestCtx, cli client.Client, namespa
 	return nil
 }
 
+// getRetryBackoff returns the backoff before the given retry of a failed action, the backoff
+// grows exponentially from initialRetryBackoff up to the limit, with a random jitter added.
+func getRetryBackoff(retryCount int32, limitSeconds int32) time.Duration {
+	limit := defaultRetryBackoffLimit
+	if limitSeconds > 0 {
+		limit = time.Duration(limitSeconds) * time.Second
+	}
+	backoff := limit
+	// avoid overflowing the duration for a large retry count.
+	if retryCount <= 32 {
+		backoff = min(initialRetryBackoff<<max(retryCount-1, 0), limit)
+	}
+	return wait.Jitter(backoff, retryBackoffJitter)
+}
+
+// getBackupFailureCode returns the failure code of the backup according to the type of the error.
+func getBackupFailureCode(err error) dpv1alpha1.BackupFailureCode {
+	controllerErr := intctrlutil.UnwrapControllerError(err)
+	if controllerErr == nil {
+		return dpv1alpha1.BackupFailureCodeUnknown
+	}
+	switch controllerErr.Type {
+	case dperrors.ErrorTypeBackupRepoIsNotReady, dperrors.ErrorTypeToolConfigSecretNameIsEmpty,
+		dperrors.ErrorTypeBackupPVCNameIsEmpty:
+		return dpv1alpha1.BackupFailureCodeRepoNotReady
+	case dperrors.ErrorTypeNoTargetPods:
+		return dpv1alpha1.BackupFailureCodeTargetPodNotFound
+	case dperrors.ErrorTypeTargetPodsNotReady:
+		return dpv1alpha1.BackupFailureCodeTargetPodNotReady
+	case dperrors.ErrorTypeTargetContainerNotFound:
+		return dpv1alpha1.BackupFailureCodeTargetContainerNotFound
+	case dperrors.ErrorTypeBackupActionFailed, dperrors.ErrorTypeBackupJobFailed:
+		return dpv1alpha1.BackupFailureCodeActionFailed
+	case intctrlutil.ErrorTypeDeadlineExceeded:
+		return dpv1alpha1.BackupFailureCodeDeadlineExceeded
+	case dperrors.ErrorTypeBackupRepoQuotaExceeded:
+		return dpv1alpha1.BackupFailureCodeQuotaExceeded
+	case dperrors.ErrorTypeBackupRepoOutOfSpace:
+		return dpv1alpha1.BackupFailureCodeRepoOutOfSpace
+	case dperrors.ErrorTypeEncryptionKeyMissing:
+		return dpv1alpha1.BackupFailureCodeEncryptionKeyMissing
+	case dperrors.ErrorTypeBackupPathCollision:
+		return dpv1alpha1.BackupFailureCodePathCollision
+	case dperrors.ErrorTypeSnapshotClassMismatch:
+		return dpv1alpha1.BackupFailureCodeSnapshotClassMismatch
+	}
+	return dpv1alpha1.BackupFailureCodeUnknown
+}
+
 func RecorderEventAndRequeue(reqCtx intctrlutil.RequestCtx, recorder record.EventRecorder,
 	obj client.Object, err error) (reconcile.Result, error) {
 	sendWarningEventForError(recorder, obj, err)
@@ -303,15 +639,9 @@ func EnsureWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client
 		return "", fmt.Errorf("worker cluster role name is empty")
 	}
 
-	var extraAnnotations map[string]string
-	annotationsJSON := viper.GetString(dptypes.CfgKeyWorkerServiceAccountAnnotations)
-	if annotationsJSON != "" {
-		extraAnnotations = make(map[string]string)
-		err := json.Unmarshal([]byte(annotationsJSON), &extraAnnotations)
-		if err != nil {
-			return "", fmt.Errorf("failed to unmarshal worker service account annotations: %s, json: %q",
-				err.Error(), annotationsJSON)
-		}
+	extraAnnotations, err := getWorkerServiceAccountAnnotations()
+	if err != nil {
+		return "", err
 	}
 
 	if exists {
@@ -334,26 +664,6 @@ func EnsureWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client
 		return saName, nil
 	}
 
-	createRoleBinding := func() error {
-		rb := &rbacv1.RoleBinding{}
-		rb.Name = fmt.Sprintf("%s-rolebinding", saName)
-		rb.Namespace = namespace
-		rb.Subjects = []rbacv1.Subject{{
-			Kind:      rbacv1.ServiceAccountKind,
-			Name:      saName,
-			Namespace: namespace,
-		}}
-		rb.RoleRef = rbacv1.RoleRef{
-			Kind:     "ClusterRole",
-			Name:     clusterRoleName,
-			APIGroup: "rbac.authorization.k8s.io",
-		}
-		if err := cli.Create(reqCtx.Ctx, rb); err != nil {
-			return client.IgnoreAlreadyExists(err)
-		}
-		return nil
-	}
-
 	createServiceAccount := func() error {
 		sa := &corev1.ServiceAccount{}
 		sa.Name = saName
@@ -367,7 +677,7 @@ func EnsureWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client
 
 	// this function returns earlier if the service account already exists,
 	// so we create the role binding first for idempotent.
-	if err := createRoleBinding(); err != nil {
+	if err := ensureWorkerRoleBinding(reqCtx, cli, namespace, namespace, saName); err != nil {
 		return "", fmt.Errorf("failed to create rolebinding: %w", err)
 	}
 	if err := createServiceAccount(); err != nil {
@@ -376,6 +686,247 @@ func EnsureWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client
 	return saName, nil
 }
 
+// getWorkerServiceAccountAnnotations returns the extra annotations of the worker service accounts,
+// e.g. to associate the IAM roles in the cloud environments.
+func getWorkerServiceAccountAnnotations() (map[string]string, error) {
+	annotationsJSON := viper.GetString(dptypes.CfgKeyWorkerServiceAccountAnnotations)
+	if annotationsJSON == "" {
+		return nil, nil
+	}
+	annotations := make(map[string]string)
+	if err := json.Unmarshal([]byte(annotationsJSON), &annotations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal worker service account annotations: %s, json: %q",
+			err.Error(), annotationsJSON)
+	}
+	return annotations, nil
+}
+
+// ensureBackupWorkerServiceAccount ensures the worker service account of the backup policy, which is bound to
+// a role per backup method only granted the rules computed from what the backups of the method need. The service
+// account, roles and role bindings are owned by the backup policy, they are garbage-collected when the backup
+// policy is deleted.
+// It falls back to the shared worker service account if the legacy shared role is enabled.
+func ensureBackupWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client, request *dpbackup.Request) (string, error) {
+	if viper.GetBool(dptypes.CfgKeyWorkerLegacySharedRole) {
+		return EnsureWorkerServiceAccount(reqCtx, cli, request.Namespace)
+	}
+	rules, err := dpbackup.ComputeWorkerRBAC(request)
+	if err != nil {
+		return "", err
+	}
+	extraAnnotations, err := getWorkerServiceAccountAnnotations()
+	if err != nil {
+		return "", err
+	}
+
+	backupPolicy := request.BackupPolicy
+	name := dpbackup.GenerateWorkerRBACName(backupPolicy.Name)
+	labels := map[string]string{
+		constant.AppManagedByLabelKey: constant.AppName,
+		dptypes.BackupPolicyLabelKey:  backupPolicy.Name,
+	}
+	setOwnership := func(obj client.Object) error {
+		if obj.GetUID() != "" && !metav1.IsControlledBy(obj, backupPolicy) {
+			return fmt.Errorf("%s/%s already exists and is not owned by the backup policy", obj.GetNamespace(), obj.GetName())
+		}
+		obj.SetLabels(labels)
+		return controllerutil.SetControllerReference(backupPolicy, obj, cli.Scheme())
+	}
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: backupPolicy.Namespace, Name: name}}
+	if _, err = createOrUpdateObject(reqCtx.Ctx, cli, sa, func() error {
+		if len(extraAnnotations) > 0 && sa.Annotations == nil {
+			sa.Annotations = map[string]string{}
+		}
+		for k, v := range extraAnnotations {
+			sa.Annotations[k] = v
+		}
+		return setOwnership(sa)
+	}, func() bool {
+		for k, v := range extraAnnotations {
+			if sa.Annotations[k] != v {
+				return true
+			}
+		}
+		return false
+	}); err != nil {
+		return "", fmt.Errorf("failed to ensure worker service account: %w", err)
+	}
+
+	roleName := dpbackup.GenerateWorkerRoleName(backupPolicy.Name, request.BackupMethod.Name)
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Namespace: backupPolicy.Namespace, Name: roleName}}
+	if _, err = createOrUpdateObject(reqCtx.Ctx, cli, role, func() error {
+		role.Rules = rules
+		return setOwnership(role)
+	}, func() bool {
+		return !reflect.DeepEqual(role.Rules, rules)
+	}); err != nil {
+		return "", fmt.Errorf("failed to ensure worker role: %w", err)
+	}
+
+	rb := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: backupPolicy.Namespace, Name: roleName}}
+	if _, err = createObjectIfNotExist(reqCtx.Ctx, cli, rb, func() error {
+		rb.Subjects = []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      name,
+			Namespace: backupPolicy.Namespace,
+		}}
+		rb.RoleRef = rbacv1.RoleRef{
+			Kind:     "Role",
+			Name:     roleName,
+			APIGroup: rbacv1.GroupName,
+		}
+		return setOwnership(rb)
+	}); err != nil {
+		return "", fmt.Errorf("failed to ensure worker role binding: %w", err)
+	}
+	return name, nil
+}
+
+// ensureWorkerRoleBinding binds the worker service account to the worker cluster role in the namespace,
+// the service account may be in another namespace for the cross-namespace backup.
+func ensureWorkerRoleBinding(reqCtx intctrlutil.RequestCtx, cli client.Client, namespace, saNamespace, saName string) error {
+	clusterRoleName := viper.GetString(dptypes.CfgKeyWorkerClusterRoleName)
+	if clusterRoleName == "" {
+		return fmt.Errorf("worker cluster role name is empty")
+	}
+	rb := &rbacv1.RoleBinding{}
+	rb.Name = fmt.Sprintf("%s-rolebinding", saName)
+	if namespace != saNamespace {
+		rb.Name = fmt.Sprintf("%s-%s-rolebinding", saName, saNamespace)
+	}
+	rb.Namespace = namespace
+	rb.Subjects = []rbacv1.Subject{{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      saName,
+		Namespace: saNamespace,
+	}}
+	rb.RoleRef = rbacv1.RoleRef{
+		Kind:     "ClusterRole",
+		Name:     clusterRoleName,
+		APIGroup: "rbac.authorization.k8s.io",
+	}
+	if err := cli.Create(reqCtx.Ctx, rb); err != nil {
+		return client.IgnoreAlreadyExists(err)
+	}
+	return nil
+}
+
+// ensureExecWorkerRoleBinding binds the exec worker service account, which runs the "kubectl exec" jobs in the
+// namespace of KubeBlocks, to the exec worker cluster role in the namespace of the target pods.
+func ensureExecWorkerRoleBinding(reqCtx intctrlutil.RequestCtx, cli client.Client, namespace string) error {
+	if viper.GetBool(dptypes.CfgKeyWorkerLegacySharedRole) {
+		// the exec worker service account is bound to the exec worker cluster role cluster-wide.
+		return nil
+	}
+	clusterRoleName := viper.GetString(dptypes.CfgKeyExecWorkerClusterRoleName)
+	if clusterRoleName == "" {
+		return fmt.Errorf("exec worker cluster role name is empty")
+	}
+	saName := viper.GetString(dptypes.CfgKeyExecWorkerServiceAccountName)
+	saNamespace := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	rb := &rbacv1.RoleBinding{}
+	rb.Name = fmt.Sprintf("%s-%s-rolebinding", saName, saNamespace)
+	rb.Namespace = namespace
+	rb.Labels = map[string]string{constant.AppManagedByLabelKey: constant.AppName}
+	rb.Subjects = []rbacv1.Subject{{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      saName,
+		Namespace: saNamespace,
+	}}
+	rb.RoleRef = rbacv1.RoleRef{
+		Kind:     "ClusterRole",
+		Name:     clusterRoleName,
+		APIGroup: rbacv1.GroupName,
+	}
+	if err := cli.Create(reqCtx.Ctx, rb); err != nil {
+		return client.IgnoreAlreadyExists(err)
+	}
+	return nil
+}
+
+// checkWorkerServiceAccount checks that the worker service account specified by the backup policy
+// exists and is bound to a role or a cluster role, the controller does not manage it.
+// It requires the serviceAccountSubjectIndexKey field index to be added to the Manager.
+func checkWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client, namespace, saName string) error {
+	sa := &corev1.ServiceAccount{}
+	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Namespace: namespace, Name: saName}, sa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("worker service account %s/%s is not found", namespace, saName)
+		}
+		return fmt.Errorf("failed to get worker service account %s/%s: %w", namespace, saName, err)
+	}
+
+	subject := client.MatchingFields{serviceAccountSubjectIndexKey: namespace + "/" + saName}
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := cli.List(reqCtx.Ctx, roleBindings, client.InNamespace(namespace), subject); err != nil {
+		return err
+	}
+	if len(roleBindings.Items) > 0 {
+		return nil
+	}
+	clusterRoleBindings := &rbacv1.ClusterRoleBindingList{}
+	if err := cli.List(reqCtx.Ctx, clusterRoleBindings, subject); err != nil {
+		return err
+	}
+	if len(clusterRoleBindings.Items) > 0 {
+		return nil
+	}
+	return fmt.Errorf("worker service account %s/%s is not bound to any role, it requires the permissions of cluster role %s",
+		namespace, saName, viper.GetString(dptypes.CfgKeyWorkerClusterRoleName))
+}
+
+// indexServiceAccountSubjects returns the service account subjects of the role binding or cluster role binding,
+// which is used to index them by the serviceAccountSubjectIndexKey.
+func indexServiceAccountSubjects(obj client.Object) []string {
+	var subjects []rbacv1.Subject
+	switch binding := obj.(type) {
+	case *rbacv1.RoleBinding:
+		subjects = binding.Subjects
+	case *rbacv1.ClusterRoleBinding:
+		subjects = binding.Subjects
+	}
+	var keys []string
+	for _, s := range subjects {
+		if s.Kind == rbacv1.ServiceAccountKind {
+			keys = append(keys, s.Namespace+"/"+s.Name)
+		}
+	}
+	return keys
+}
+
+// getWorkerServiceAccount returns the service account to run the worker pods of the backup policy. If the
+// worker service account is specified by the backup policy, it is validated instead of being ensured.
+func getWorkerServiceAccount(reqCtx intctrlutil.RequestCtx, cli client.Client,
+	backupPolicy *dpv1alpha1.BackupPolicy, namespace string) (string, error) {
+	if backupPolicy != nil && backupPolicy.Spec.WorkerServiceAccountName != "" {
+		saName := backupPolicy.Spec.WorkerServiceAccountName
+		if err := checkWorkerServiceAccount(reqCtx, cli, namespace, saName); err != nil {
+			return "", err
+		}
+		return saName, nil
+	}
+	return EnsureWorkerServiceAccount(reqCtx, cli, namespace)
+}
+
+// getBackupEncryptionConfig returns the effective encryption config of the backup. The config recorded
+// in the backup status takes precedence, so that a started backup keeps using the same key after the key
+// of the backup policy is rotated. Otherwise, the config of the backup overrides the one of the backup
+// policy if the backup policy allows it.
+func getBackupEncryptionConfig(backup *dpv1alpha1.Backup,
+	backupPolicy *dpv1alpha1.BackupPolicy) (*dpv1alpha1.EncryptionConfig, error) {
+	if backup.Status.EncryptionConfig != nil {
+		return backup.Status.EncryptionConfig, nil
+	}
+	if backup.Spec.EncryptionConfig == nil {
+		return backupPolicy.Spec.EncryptionConfig, nil
+	}
+	if !backupPolicy.Spec.AllowEncryptionConfigOverride {
+		return nil, fmt.Errorf(`backup policy "%s" does not allow to override the encryption config`, backupPolicy.Name)
+	}
+	return backup.Spec.EncryptionConfig, nil
+}
+
 func checkSecretKeyRef(reqCtx intctrlutil.RequestCtx, cli client.Client,
 	namespace string, ref *corev1.SecretKeySelector) error {
 	if ref == nil {
@@ -20,15 +20,33 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package dataprotection
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
+	dperrors "github.com/apecloud/kubeblocks/pkg/dataprotection/errors"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -64,6 +82,14 @@ var _ = Describe("test EnsureWorkerServiceAccount", func() {
 		rbKey types.NamespacedName
 	)
 
+	updateDefaultEnv := func(key string, value string) func() {
+		old := viper.GetString(key)
+		viper.SetDefault(key, value)
+		return func() {
+			viper.SetDefault(key, old)
+		}
+	}
+
 	BeforeEach(func() {
 		cleanEnv()
 		viper.SetDefault(dptypes.CfgKeyWorkerServiceAccountName, defaultWorkerServiceAccountName)
@@ -142,14 +168,6 @@ var _ = Describe("test EnsureWorkerServiceAccount", func() {
 	})
 
 	Context("testing invalid argument", func() {
-		updateDefaultEnv := func(key string, value string) func() {
-			old := viper.GetString(key)
-			viper.SetDefault(key, value)
-			return func() {
-				viper.SetDefault(key, old)
-			}
-		}
-
 		It("should return error if namespace is empty", func() {
 			reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
 			_, err := EnsureWorkerServiceAccount(reqCtx, testCtx.Cli, "")
@@ -181,4 +199,679 @@ var _ = Describe("test EnsureWorkerServiceAccount", func() {
 			Expect(err.Error()).To(ContainSubstring("worker cluster role name is empty"))
 		})
 	})
+
+	Context("testing the exec worker role binding", func() {
+		It("should bind the exec worker service account in the namespace of the target pods", func() {
+			defer updateDefaultEnv(dptypes.CfgKeyExecWorkerClusterRoleName, "exec-worker-role")()
+			reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+			Expect(ensureExecWorkerRoleBinding(reqCtx, testCtx.Cli, testCtx.DefaultNamespace)).Should(Succeed())
+			// it is idempotent.
+			Expect(ensureExecWorkerRoleBinding(reqCtx, testCtx.Cli, testCtx.DefaultNamespace)).Should(Succeed())
+
+			saNamespace := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+			key := types.NamespacedName{
+				Name:      fmt.Sprintf("%s-%s-rolebinding", defaultExecWorkerServiceAccountName, saNamespace),
+				Namespace: testCtx.DefaultNamespace,
+			}
+			Eventually(testapps.CheckObj(&testCtx, key, func(g Gomega, rb *rbacv1.RoleBinding) {
+				g.Expect(rb.RoleRef.Kind).Should(Equal("ClusterRole"))
+				g.Expect(rb.RoleRef.Name).Should(Equal("exec-worker-role"))
+				g.Expect(rb.Subjects).Should(HaveLen(1))
+				g.Expect(rb.Subjects[0].Name).Should(Equal(defaultExecWorkerServiceAccountName))
+				g.Expect(rb.Subjects[0].Namespace).Should(Equal(saNamespace))
+			})).Should(Succeed())
+			Expect(testCtx.Cli.Delete(testCtx.Ctx, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			})).Should(Succeed())
+		})
+
+		It("should not bind the exec worker service account if the legacy shared role is enabled", func() {
+			defer updateDefaultEnv(dptypes.CfgKeyWorkerLegacySharedRole, "true")()
+			defer updateDefaultEnv(dptypes.CfgKeyExecWorkerClusterRoleName, "")()
+			reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+			Expect(ensureExecWorkerRoleBinding(reqCtx, testCtx.Cli, testCtx.DefaultNamespace)).Should(Succeed())
+		})
+	})
+
+	Context("testing the worker service account specified by the backup policy", func() {
+		const customWorkerServiceAccountName = "custom-sa-name"
+
+		It("should validate the service account instead of creating it", func() {
+			reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+			backupPolicy := &dpv1alpha1.BackupPolicy{}
+			backupPolicy.Spec.WorkerServiceAccountName = customWorkerServiceAccountName
+			// the role bindings are looked up by the field index, which is not supported by the API server.
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).Should(Succeed())
+			Expect(rbacv1.AddToScheme(scheme)).Should(Succeed())
+			cli := fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&rbacv1.RoleBinding{}, serviceAccountSubjectIndexKey, indexServiceAccountSubjects).
+				WithIndex(&rbacv1.ClusterRoleBinding{}, serviceAccountSubjectIndexKey, indexServiceAccountSubjects).
+				Build()
+			subjects := []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      customWorkerServiceAccountName,
+				Namespace: testCtx.DefaultNamespace,
+			}}
+			roleRef := rbacv1.RoleRef{
+				Kind:     "ClusterRole",
+				APIGroup: "rbac.authorization.k8s.io",
+				Name:     defaultWorkerClusterRoleName,
+			}
+
+			By("the service account does not exist")
+			_, err := getWorkerServiceAccount(reqCtx, cli, backupPolicy, testCtx.DefaultNamespace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not found"))
+
+			By("the service account is not bound to any role")
+			Expect(cli.Create(testCtx.Ctx, &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      customWorkerServiceAccountName,
+					Namespace: testCtx.DefaultNamespace,
+				},
+			})).Should(Succeed())
+			Expect(cli.Create(testCtx.Ctx, &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-sa-clusterrolebinding"},
+				Subjects: []rbacv1.Subject{{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      "other-sa-name",
+					Namespace: testCtx.DefaultNamespace,
+				}},
+				RoleRef: roleRef,
+			})).Should(Succeed())
+			_, err = getWorkerServiceAccount(reqCtx, cli, backupPolicy, testCtx.DefaultNamespace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not bound to any role"))
+
+			By("the service account is bound to the worker cluster role by a cluster role binding")
+			crb := &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: customWorkerServiceAccountName + "-clusterrolebinding"},
+				Subjects:   subjects,
+				RoleRef:    roleRef,
+			}
+			Expect(cli.Create(testCtx.Ctx, crb)).Should(Succeed())
+			saName, err := getWorkerServiceAccount(reqCtx, cli, backupPolicy, testCtx.DefaultNamespace)
+			Expect(err).To(BeNil())
+			Expect(saName).To(Equal(customWorkerServiceAccountName))
+
+			By("the service account is bound to the worker cluster role by a role binding")
+			Expect(cli.Delete(testCtx.Ctx, crb)).Should(Succeed())
+			Expect(cli.Create(testCtx.Ctx, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      customWorkerServiceAccountName + "-rolebinding",
+					Namespace: testCtx.DefaultNamespace,
+				},
+				Subjects: subjects,
+				RoleRef:  roleRef,
+			})).Should(Succeed())
+			saName, err = getWorkerServiceAccount(reqCtx, cli, backupPolicy, testCtx.DefaultNamespace)
+			Expect(err).To(BeNil())
+			Expect(saName).To(Equal(customWorkerServiceAccountName))
+
+			By("the managed worker service account is not created")
+			Eventually(testapps.CheckObjExists(&testCtx, saKey, &corev1.ServiceAccount{}, false)).Should(Succeed())
+		})
+	})
+})
+
+var _ = Describe("test BackupRepo quota", func() {
+	const repoName = "test-repo"
+
+	newBackup := func(namespace, name, totalSize string, deleting bool) *dpv1alpha1.Backup {
+		backup := &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					dataProtectionBackupRepoKey: repoName,
+				},
+			},
+			Status: dpv1alpha1.BackupStatus{
+				Phase:     dpv1alpha1.BackupPhaseCompleted,
+				TotalSize: totalSize,
+			},
+		}
+		if deleting {
+			backup.Status.Phase = dpv1alpha1.BackupPhaseDeleting
+		}
+		return backup
+	}
+
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(dpv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	It("should calculate the usage of the backup repo", func() {
+		cli := newClient(
+			newBackup("ns1", "backup1", "1Gi", false),
+			newBackup("ns1", "backup2", "1Gi", false),
+			newBackup("ns2", "backup3", "512Mi", false),
+			newBackup("ns2", "backup4", "10Gi", true),
+			newBackup("ns2", "backup5", "", false),
+		)
+		usage, err := calculateBackupRepoUsage(testCtx.Ctx, cli, repoName)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(usage.Total.Cmp(resource.MustParse("2560Mi"))).Should(Equal(0))
+		Expect(usage.PerNamespace).Should(HaveLen(2))
+		ns1Usage := usage.PerNamespace["ns1"]
+		Expect(ns1Usage.Cmp(resource.MustParse("2Gi"))).Should(Equal(0))
+		ns2Usage := usage.PerNamespace["ns2"]
+		Expect(ns2Usage.Cmp(resource.MustParse("512Mi"))).Should(Equal(0))
+	})
+
+	It("should reject new backups if the quota is exceeded", func() {
+		cli := newClient(
+			newBackup("ns1", "backup1", "1Gi", false),
+			newBackup("ns2", "backup2", "512Mi", false),
+		)
+		repo := &dpv1alpha1.BackupRepo{ObjectMeta: metav1.ObjectMeta{Name: repoName}}
+
+		By("no quota is specified")
+		Expect(checkBackupRepoQuota(testCtx.Ctx, cli, repo, "ns1")).Should(Succeed())
+
+		By("the total quota is not exceeded")
+		total := resource.MustParse("2Gi")
+		repo.Spec.Quota = &dpv1alpha1.BackupRepoQuota{Total: &total}
+		Expect(checkBackupRepoQuota(testCtx.Ctx, cli, repo, "ns1")).Should(Succeed())
+
+		By("the quota of namespace ns1 is reached but not exceeded")
+		repo.Spec.Quota.PerNamespace = map[string]resource.Quantity{
+			"ns1": resource.MustParse("1Gi"),
+			"ns2": resource.MustParse("1Gi"),
+		}
+		Expect(checkBackupRepoQuota(testCtx.Ctx, cli, repo, "ns1")).Should(Succeed())
+
+		By("the quota of namespace ns1 is exceeded")
+		repo.Spec.Quota.PerNamespace["ns1"] = resource.MustParse("512Mi")
+		err := checkBackupRepoQuota(testCtx.Ctx, cli, repo, "ns1")
+		Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeBackupRepoQuotaExceeded)).Should(BeTrue())
+		Expect(checkBackupRepoQuota(testCtx.Ctx, cli, repo, "ns2")).Should(Succeed())
+
+		By("the total quota is exceeded")
+		total = resource.MustParse("1Gi")
+		repo.Spec.Quota = &dpv1alpha1.BackupRepoQuota{Total: &total}
+		err = checkBackupRepoQuota(testCtx.Ctx, cli, repo, "ns2")
+		Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeBackupRepoQuotaExceeded)).Should(BeTrue())
+	})
+
+	It("should only reconcile the backup repo for the updates of the backup affecting it", func() {
+		oldBackup := newBackup("ns1", "backup1", "", false)
+		oldBackup.Status.Phase = dpv1alpha1.BackupPhaseRunning
+		newUpdateEvent := func(update func(backup *dpv1alpha1.Backup)) event.UpdateEvent {
+			backup := oldBackup.DeepCopy()
+			update(backup)
+			return event.UpdateEvent{ObjectOld: oldBackup, ObjectNew: backup}
+		}
+		p := backupUpdatePredicate{}
+
+		By("the progress of the backup is updated")
+		Expect(p.Update(newUpdateEvent(func(backup *dpv1alpha1.Backup) {
+			backup.Status.Extras = []map[string]string{{"progress": "50%"}}
+		}))).Should(BeFalse())
+
+		By("the total size of the backup is recorded")
+		Expect(p.Update(newUpdateEvent(func(backup *dpv1alpha1.Backup) {
+			backup.Status.TotalSize = "1Gi"
+		}))).Should(BeTrue())
+
+		By("the backup is completed")
+		Expect(p.Update(newUpdateEvent(func(backup *dpv1alpha1.Backup) {
+			backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+		}))).Should(BeTrue())
+
+		By("the backup waits for the backup repo")
+		Expect(p.Update(newUpdateEvent(func(backup *dpv1alpha1.Backup) {
+			backup.Labels[dataProtectionWaitRepoPreparationKey] = trueVal
+		}))).Should(BeTrue())
+
+		By("the backup is being deleted")
+		Expect(p.Update(newUpdateEvent(func(backup *dpv1alpha1.Backup) {
+			backup.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}))).Should(BeTrue())
+	})
+
+	It("should fail fast if the free space of the backup repo is not enough", func() {
+		viper.Set(dptypes.CfgKeyBackupSizeEstimatePercent, 150)
+		defer viper.Set(dptypes.CfgKeyBackupSizeEstimatePercent, dptypes.DefaultBackupSizeEstimatePercent)
+
+		const policyName = "test-policy"
+		newPolicyBackup := func(name, method, totalSize string, completedAt time.Time) *dpv1alpha1.Backup {
+			backup := newBackup("ns1", name, totalSize, false)
+			backup.Labels[dptypes.BackupPolicyLabelKey] = policyName
+			backup.Spec.BackupPolicyName = policyName
+			backup.Spec.BackupMethod = method
+			backup.Status.CompletionTimestamp = &metav1.Time{Time: completedAt}
+			return backup
+		}
+		now := time.Now()
+		cli := newClient(
+			newPolicyBackup("backup1", "full", "2Gi", now.Add(-2*time.Hour)),
+			newPolicyBackup("backup2", "full", "1Gi", now.Add(-time.Hour)),
+			newPolicyBackup("backup3", "incremental", "512Mi", now),
+		)
+		repo := &dpv1alpha1.BackupRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: repoName},
+			Spec:       dpv1alpha1.BackupRepoSpec{VolumeCapacity: resource.MustParse("5Gi")},
+		}
+		request := &dpbackup.Request{
+			Backup:        newPolicyBackup("backup4", "full", "", now),
+			BackupRepo:    repo,
+			BackupRepoPVC: &corev1.PersistentVolumeClaim{},
+		}
+		request.Backup.Status = dpv1alpha1.BackupStatus{}
+
+		By("the free space is more than 150% of the latest backup of the same method")
+		Expect(checkBackupRepoFreeSpace(testCtx.Ctx, cli, request)).Should(Succeed())
+
+		By("the free space is less than 150% of the latest backup of the same method")
+		request.BackupRepoPVC.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceStorage: resource.MustParse("4Gi"),
+		}
+		err := checkBackupRepoFreeSpace(testCtx.Ctx, cli, request)
+		Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeBackupRepoOutOfSpace)).Should(BeTrue())
+		Expect(getBackupFailureCode(err)).Should(Equal(dpv1alpha1.BackupFailureCodeRepoOutOfSpace))
+
+		By("the backup repo accessed by tool is not checked")
+		request.BackupRepoPVC = nil
+		Expect(checkBackupRepoFreeSpace(testCtx.Ctx, cli, request)).Should(Succeed())
+	})
+
+	It("should emit the event only once when pausing the new backup to the read-only backup repo", func() {
+		backup := newBackup("ns1", "backup1", "", false)
+		backup.Status = dpv1alpha1.BackupStatus{}
+		scheme := runtime.NewScheme()
+		Expect(dpv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(backup).WithStatusSubresource(backup).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &BackupReconciler{Client: cli, Recorder: recorder}
+		reqCtx := intctrlutil.RequestCtx{Ctx: testCtx.Ctx}
+
+		By("pausing the backup in the repeated reconciliations")
+		for i := 0; i < 3; i++ {
+			Expect(cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(backup), backup)).Should(Succeed())
+			err := r.pauseForReadOnlyBackupRepo(reqCtx, backup, repoName)
+			Expect(intctrlutil.IsRequeueError(err)).Should(BeTrue())
+		}
+		Expect(cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(backup), backup)).Should(Succeed())
+		cond := meta.FindStatusCondition(backup.Status.Conditions, ConditionTypePaused)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Reason).Should(Equal(ReasonBackupRepoReadOnly))
+		Expect(recorder.Events).Should(HaveLen(1))
+		<-recorder.Events
+
+		By("resuming the backup once the backup repo becomes writable")
+		request := &dpbackup.Request{
+			Backup:     backup.DeepCopy(),
+			BackupRepo: &dpv1alpha1.BackupRepo{ObjectMeta: metav1.ObjectMeta{Name: repoName}},
+		}
+		Expect(r.resumeFromReadOnlyBackupRepo(reqCtx, backup, request)).Should(Succeed())
+		Expect(meta.FindStatusCondition(request.Status.Conditions, ConditionTypePaused)).Should(BeNil())
+		Expect(cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(backup), backup)).Should(Succeed())
+		Expect(meta.FindStatusCondition(backup.Status.Conditions, ConditionTypePaused)).Should(BeNil())
+		Expect(recorder.Events).Should(HaveLen(1))
+	})
+})
+
+var _ = Describe("test backup object meta", func() {
+	It("should not patch the backup if the object meta is not changed", func() {
+		viper.Set(constant.CfgKeyDPEncryptionKey, "test-encryption-key")
+		defer viper.Set(constant.CfgKeyDPEncryptionKey, "")
+
+		const namespace = "default"
+		scheme := runtime.NewScheme()
+		Expect(dpv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(appsv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		Expect(corev1.AddToScheme(scheme)).Should(Succeed())
+		cluster := &appsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "mycluster", UID: "cluster-uid"},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "mycluster-conn-credential"},
+			Data:       map[string][]byte{"password": []byte("password")},
+		}
+		backup := &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        "backup",
+				Annotations: map[string]string{dptypes.CreatedByAnnotationKey: "schedule/test-schedule"},
+			},
+			Spec: dpv1alpha1.BackupSpec{BackupPolicyName: "policy", BackupMethod: "xtrabackup"},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "mycluster-mysql-0",
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    cluster.Name,
+					constant.KBAppComponentLabelKey: "mysql",
+				},
+			},
+		}
+		patches := 0
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, secret, backup).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patches++
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build()
+
+		reconcile := func() *dpv1alpha1.Backup {
+			original := &dpv1alpha1.Backup{}
+			Expect(cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(backup), original)).Should(Succeed())
+			request := &dpbackup.Request{
+				Backup:     original.DeepCopy(),
+				RequestCtx: intctrlutil.RequestCtx{Ctx: testCtx.Ctx},
+				Client:     cli,
+				BackupPolicy: &dpv1alpha1.BackupPolicy{
+					Spec: dpv1alpha1.BackupPolicySpec{
+						Target: &dpv1alpha1.BackupTarget{
+							ConnectionCredential: &dpv1alpha1.ConnectionCredential{
+								SecretName:  secret.Name,
+								PasswordKey: "password",
+							},
+						},
+					},
+				},
+				TargetPods: []*corev1.Pod{pod},
+			}
+			// the request initializes the nil labels and annotations as empty
+			if request.Labels == nil {
+				request.Labels = map[string]string{}
+			}
+			if request.Annotations == nil {
+				request.Annotations = map[string]string{}
+			}
+			wait, err := PatchBackupObjectMeta(original, request)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(wait).Should(BeFalse())
+			return request.Backup
+		}
+
+		By("the new backup is patched")
+		patched := reconcile()
+		Expect(patches).Should(Equal(1))
+		Expect(patched.Labels).Should(HaveKeyWithValue(dptypes.ClusterUIDLabelKey, "cluster-uid"))
+		Expect(patched.Annotations).Should(HaveKey(dptypes.ConnectionPasswordAnnotationKey))
+		Expect(patched.Annotations).Should(HaveKeyWithValue(dptypes.CreatedByAnnotationKey, "schedule/test-schedule"))
+		Expect(patched.Finalizers).Should(ConsistOf(dptypes.DataProtectionFinalizerName))
+
+		By("the backup already up to date is not patched again")
+		for i := 0; i < 3; i++ {
+			reconciled := reconcile()
+			Expect(reconciled.ObjectMeta).Should(Equal(patched.ObjectMeta))
+		}
+		Expect(patches).Should(Equal(1))
+
+		By("the built object meta is deterministic and doesn't modify the backup")
+		original := backup.DeepCopy()
+		request := &dpbackup.Request{
+			Backup:       backup,
+			RequestCtx:   intctrlutil.RequestCtx{Ctx: testCtx.Ctx},
+			Client:       cli,
+			BackupPolicy: &dpv1alpha1.BackupPolicy{},
+			TargetPods:   []*corev1.Pod{pod},
+		}
+		labels, annotations, err := BuildBackupObjectMeta(backup, request)
+		Expect(err).ShouldNot(HaveOccurred())
+		labels2, annotations2, err := BuildBackupObjectMeta(backup, request)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(labels2).Should(Equal(labels))
+		Expect(annotations2).Should(Equal(annotations))
+		Expect(backup).Should(Equal(original))
+	})
+
+	It("should compare the object meta semantically", func() {
+		Expect(backupObjectMetaEqual(&metav1.ObjectMeta{}, &metav1.ObjectMeta{
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		})).Should(BeTrue())
+		Expect(backupObjectMetaEqual(&metav1.ObjectMeta{Finalizers: []string{"a", "b"}},
+			&metav1.ObjectMeta{Finalizers: []string{"b", "a"}})).Should(BeTrue())
+		Expect(backupObjectMetaEqual(&metav1.ObjectMeta{Finalizers: []string{"a"}},
+			&metav1.ObjectMeta{Finalizers: []string{"a", "b"}})).Should(BeFalse())
+		Expect(backupObjectMetaEqual(&metav1.ObjectMeta{Labels: map[string]string{"a": ""}},
+			&metav1.ObjectMeta{})).Should(BeFalse())
+	})
+})
+
+var _ = Describe("test volume snapshot class", func() {
+	It("should check the pinned volume snapshot class", func() {
+		const (
+			namespace = "default"
+			driver    = "hostpath.csi.k8s.io"
+		)
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).Should(Succeed())
+		Expect(vsv1.AddToScheme(scheme)).Should(Succeed())
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-0"},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: driver},
+				},
+			},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "data-mysql-0"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pv.Name},
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pv, pvc,
+			&vsv1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "csi-hostpath"}, Driver: driver},
+			&vsv1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "csi-ebs"}, Driver: "ebs.csi.aws.com"},
+		).Build()
+		request := &dpbackup.Request{
+			Backup: &dpv1alpha1.Backup{},
+			BackupMethod: &dpv1alpha1.BackupMethod{
+				TargetVolumes: &dpv1alpha1.TargetVolumeInfo{Volumes: []string{"data"}},
+			},
+			TargetPods: []*corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "mysql-0"},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+						},
+					}},
+				},
+			}},
+		}
+
+		By("the class is selected by the driver if not pinned")
+		Expect(checkVolumeSnapshotClass(testCtx.Ctx, cli, request)).Should(Succeed())
+
+		By("the pinned class matches the driver of the volume")
+		request.BackupMethod.VolumeSnapshotClassName = "csi-hostpath"
+		Expect(checkVolumeSnapshotClass(testCtx.Ctx, cli, request)).Should(Succeed())
+
+		By("the pinned class doesn't match the driver of the volume")
+		request.BackupMethod.VolumeSnapshotClassName = "csi-ebs"
+		err := checkVolumeSnapshotClass(testCtx.Ctx, cli, request)
+		Expect(getBackupFailureCode(err)).Should(Equal(dpv1alpha1.BackupFailureCodeSnapshotClassMismatch))
+
+		By("the class pinned by the backup overrides the one of the backup method")
+		request.Backup.Annotations = map[string]string{dptypes.VolumeSnapshotClassAnnotationKey: "csi-hostpath"}
+		Expect(checkVolumeSnapshotClass(testCtx.Ctx, cli, request)).Should(Succeed())
+
+		By("the pinned class is not found")
+		request.Backup.Annotations[dptypes.VolumeSnapshotClassAnnotationKey] = "not-exist"
+		err = checkVolumeSnapshotClass(testCtx.Ctx, cli, request)
+		Expect(getBackupFailureCode(err)).Should(Equal(dpv1alpha1.BackupFailureCodeSnapshotClassMismatch))
+		Expect(err.Error()).Should(ContainSubstring(`the VolumeSnapshotClass "not-exist" is not found`))
+	})
+})
+
+var _ = Describe("test retry backoff", func() {
+	It("should grow exponentially up to the limit with jitter", func() {
+		withinJitter := func(backoff, expected time.Duration) {
+			Expect(backoff).Should(BeNumerically(">=", expected))
+			Expect(backoff).Should(BeNumerically("<=", time.Duration(float64(expected)*(1+retryBackoffJitter))))
+		}
+		withinJitter(getRetryBackoff(1, 300), 10*time.Second)
+		withinJitter(getRetryBackoff(2, 300), 20*time.Second)
+		withinJitter(getRetryBackoff(3, 300), 40*time.Second)
+		withinJitter(getRetryBackoff(6, 300), 300*time.Second)
+		withinJitter(getRetryBackoff(3, 15), 15*time.Second)
+
+		By("the default limit is used if not specified")
+		withinJitter(getRetryBackoff(10, 0), defaultRetryBackoffLimit)
+		withinJitter(getRetryBackoff(100, 0), defaultRetryBackoffLimit)
+	})
+})
+
+var _ = Describe("test backup progress", func() {
+	It("should be the mean of the progress of the backup data actions", func() {
+		status := &dpv1alpha1.BackupStatus{
+			Actions: []dpv1alpha1.ActionStatus{
+				{Name: "dp-prebackuphook-0", Phase: dpv1alpha1.ActionPhaseCompleted},
+				{Name: "dp-backup-0", Phase: dpv1alpha1.ActionPhaseCompleted, Progress: 90},
+				{Name: "dp-backup-1", Phase: dpv1alpha1.ActionPhaseRunning, Progress: 30},
+				{Name: "dp-backup-2", Phase: dpv1alpha1.ActionPhaseNew},
+			},
+		}
+		updateBackupProgress(status)
+		Expect(status.Progress).Should(BeEquivalentTo(43))
+
+		By("the progress is not changed if there are no backup data actions")
+		status.Actions = status.Actions[:1]
+		updateBackupProgress(status)
+		Expect(status.Progress).Should(BeEquivalentTo(43))
+	})
+})
+
+var _ = Describe("test backup total size", func() {
+	It("should be the sum of the sizes of the actions", func() {
+		status := &dpv1alpha1.BackupStatus{
+			Actions: []dpv1alpha1.ActionStatus{
+				{Name: "dp-prebackuphook-0", Phase: dpv1alpha1.ActionPhaseCompleted},
+				{Name: "dp-backup-0", Phase: dpv1alpha1.ActionPhaseCompleted, TotalSize: "1Gi"},
+				{Name: "dp-backup-1", Phase: dpv1alpha1.ActionPhaseCompleted, TotalSize: "512Mi"},
+			},
+		}
+		updateBackupStatusByActionStatus(status)
+		Expect(status.TotalSize).Should(Equal("1536Mi"))
+	})
+
+	It("should sum the sizes of the volume snapshots for the snapshot-only backup", func() {
+		status := &dpv1alpha1.BackupStatus{
+			Actions: []dpv1alpha1.ActionStatus{
+				{
+					Name:  "dp-createvolumesnapshot-0",
+					Phase: dpv1alpha1.ActionPhaseCompleted,
+					VolumeSnapshots: []dpv1alpha1.VolumeSnapshotStatus{
+						{Name: "snapshot-data", Size: "10Gi"},
+						{Name: "snapshot-log", Size: "2Gi"},
+					},
+				},
+			},
+		}
+		updateBackupStatusByActionStatus(status)
+		Expect(status.TotalSize).Should(Equal("12Gi"))
+	})
+
+	It("should keep the size reported by the action if it can not be parsed", func() {
+		status := &dpv1alpha1.BackupStatus{
+			Actions: []dpv1alpha1.ActionStatus{
+				{Name: "dp-backup-0", Phase: dpv1alpha1.ActionPhaseCompleted, TotalSize: "unknown"},
+			},
+		}
+		updateBackupStatusByActionStatus(status)
+		Expect(status.TotalSize).Should(Equal("unknown"))
+	})
+})
+
+var _ = Describe("test backup encryption config", func() {
+	newEncryptionConfig := func(secretName string) *dpv1alpha1.EncryptionConfig {
+		return &dpv1alpha1.EncryptionConfig{
+			Algorithm: "AES-256-CFB",
+			PassPhraseSecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "password",
+			},
+		}
+	}
+
+	It("should resolve the effective encryption config of the backup", func() {
+		backupPolicy := &dpv1alpha1.BackupPolicy{}
+		backupPolicy.Name = "test-policy"
+		backupPolicy.Spec.EncryptionConfig = newEncryptionConfig("policy-key")
+		backup := &dpv1alpha1.Backup{}
+
+		By("inherit the encryption config of the backup policy")
+		config, err := getBackupEncryptionConfig(backup, backupPolicy)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).Should(Equal(newEncryptionConfig("policy-key")))
+
+		By("reject the override if it is not allowed by the backup policy")
+		backup.Spec.EncryptionConfig = newEncryptionConfig("backup-key")
+		_, err = getBackupEncryptionConfig(backup, backupPolicy)
+		Expect(err).Should(HaveOccurred())
+
+		By("override the encryption config of the backup policy")
+		backupPolicy.Spec.AllowEncryptionConfigOverride = true
+		config, err = getBackupEncryptionConfig(backup, backupPolicy)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).Should(Equal(newEncryptionConfig("backup-key")))
+
+		By("keep the recorded encryption config after the key is rotated")
+		backup.Spec.EncryptionConfig = nil
+		backup.Status.EncryptionConfig = newEncryptionConfig("policy-key")
+		backupPolicy.Spec.EncryptionConfig = newEncryptionConfig("rotated-key")
+		config, err = getBackupEncryptionConfig(backup, backupPolicy)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(config).Should(Equal(newEncryptionConfig("policy-key")))
+	})
+})
+
+var _ = Describe("test checkTargetContainer", func() {
+	newPod := func(name string, containers ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testCtx.DefaultNamespace}}
+		for _, c := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+		}
+		return pod
+	}
+
+	It("should pass if the target container is not specified", func() {
+		backupMethod := &dpv1alpha1.BackupMethod{Name: "test-method"}
+		Expect(checkTargetContainer(backupMethod, []*corev1.Pod{newPod("pod-0", "mysql")})).Should(Succeed())
+	})
+
+	It("should pass if the target container exists in all target pods", func() {
+		backupMethod := &dpv1alpha1.BackupMethod{Name: "test-method", TargetContainerName: "sidecar"}
+		pods := []*corev1.Pod{newPod("pod-0", "mysql", "sidecar"), newPod("pod-1", "sidecar", "mysql")}
+		Expect(checkTargetContainer(backupMethod, pods)).Should(Succeed())
+	})
+
+	It("should fail with TargetContainerNotFound if the target container is missing in a target pod", func() {
+		backupMethod := &dpv1alpha1.BackupMethod{Name: "test-method", TargetContainerName: "sidecar"}
+		pods := []*corev1.Pod{newPod("pod-0", "mysql", "sidecar"), newPod("pod-1", "mysql")}
+		err := checkTargetContainer(backupMethod, pods)
+		Expect(intctrlutil.IsTargetError(err, dperrors.ErrorTypeTargetContainerNotFound)).Should(BeTrue())
+		Expect(err.Error()).Should(ContainSubstring("pod-1"))
+		Expect(getBackupFailureCode(err)).Should(Equal(dpv1alpha1.BackupFailureCodeTargetContainerNotFound))
+	})
+})
+
+var _ = Describe("test sendWarningEventForError", func() {
+	It("should use the failure code or the error type as the event reason", func() {
+		recorder := record.NewFakeRecorder(10)
+		backup := &dpv1alpha1.Backup{}
+
+		By("the failure is categorized")
+		sendWarningEventForError(recorder, backup, dperrors.NewBackupRepoQuotaExceeded("repo", "namespace ns1", "2Gi", "1Gi"))
+		Expect(<-recorder.Events).Should(HavePrefix(fmt.Sprintf("Warning %s ", dpv1alpha1.BackupFailureCodeQuotaExceeded)))
+
+		By("the failure is not categorized but typed")
+		sendWarningEventForError(recorder, backup, intctrlutil.NewFatalError("invalid backup method"))
+		Expect(<-recorder.Events).Should(HavePrefix(fmt.Sprintf("Warning %s ", intctrlutil.ErrorTypeFatal)))
+
+		By("the failure is neither categorized nor typed")
+		sendWarningEventForError(recorder, backup, fmt.Errorf("unexpected error"))
+		Expect(<-recorder.Events).Should(HavePrefix("Warning FailedCreatedBackup "))
+	})
 })
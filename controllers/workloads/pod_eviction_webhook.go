@@ -0,0 +1,55 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package workloads
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/apecloud/kubeblocks/pkg/controller/rsm"
+)
+
+// PodEvictionWebhookPath is the path of the pod eviction webhook.
+const PodEvictionWebhookPath = "/validate-v1-pod-eviction"
+
+//+kubebuilder:webhook:path=/validate-v1-pod-eviction,mutating=false,failurePolicy=ignore,sideEffects=NoneOnDryRun,groups="",resources=pods/eviction,verbs=create,versions=v1,name=vpodeviction.kb.io,admissionReviewVersions=v1
+
+// PodEvictionWebhook records the eviction requests of the leaders whose leader eviction policy is SwitchoverFirst,
+// to switch the leadership over before the eviction. It never denies the eviction, which is blocked by the
+// PodDisruptionBudget of the leader until the switchover is done.
+type PodEvictionWebhook struct {
+	Client client.Client
+}
+
+var _ admission.Handler = &PodEvictionWebhook{}
+
+func (w *PodEvictionWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.DryRun != nil && *req.DryRun {
+		return admission.Allowed("")
+	}
+	if err := rsm.RecordLeaderEvictionRequest(ctx, w.Client, req.Namespace, req.Name, time.Now()); err != nil {
+		log.FromContext(ctx).Error(err, "failed to record the leader eviction request", "pod", req.Namespace+"/"+req.Name)
+	}
+	return admission.Allowed("")
+}
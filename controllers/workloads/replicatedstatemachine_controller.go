@@ -28,6 +28,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -60,6 +61,10 @@ type ReplicatedStateMachineReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/finalizers,verbs=update
 
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // TODO(user): Modify the Reconcile function to compare the state specified by
@@ -109,7 +114,7 @@ func (r *ReplicatedStateMachineReconciler) Reconcile(ctx context.Context, req ct
 	//    If you do need to create/update/delete object, make your intent operation a model.ObjectVertex and put it into the DAG.
 	//
 	// TODO: transformers are vertices, theirs' dependencies are edges, make plan Build stage a DAG.
-	plan, err := planBuilder.
+	plan, errBuild := planBuilder.
 		AddTransformer(
 			// fix meta
 			&rsm.FixMetaTransformer{},
@@ -124,11 +129,15 @@ func (r *ReplicatedStateMachineReconciler) Reconcile(ctx context.Context, req ct
 			&rsm.UpdateStrategyTransformer{},
 			// handle member reconfiguration
 			&rsm.MemberReconfigurationTransformer{},
+			// handle leader eviction
+			&rsm.LeaderEvictionTransformer{Clock: clock.RealClock{}},
 			// always safe to put your transformer below
 		).
 		Build()
-	if err != nil {
-		return requeueError(err)
+	// delayed requeue error means the plan is built successfully but should be requeued after executing,
+	// e.g. waiting for the updated members to be available.
+	if errBuild != nil && !intctrlutil.IsDelayedRequeueError(errBuild) {
+		return requeueError(errBuild)
 	}
 	// TODO: define error categories in Build stage and handle them here like this:
 	// switch errBuild.(type) {
@@ -137,10 +146,12 @@ func (r *ReplicatedStateMachineReconciler) Reconcile(ctx context.Context, req ct
 	// }
 
 	// Execute stage
-	if err = plan.Execute(); err != nil {
+	if err := plan.Execute(); err != nil {
 		return requeueError(err)
 	}
-
+	if errBuild != nil {
+		return requeueError(errBuild)
+	}
 	return intctrlutil.Reconciled()
 }
 
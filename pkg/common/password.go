@@ -47,6 +47,15 @@ func (r *PasswordReader) Seed(seed int64) {
 
 // GeneratePassword generates a password with the given requirements and seed.
 func GeneratePassword(length, numDigits, numSymbols int, noUpper bool, seed string) (string, error) {
+	return GeneratePasswordWithSymbols(length, numDigits, numSymbols, noUpper, seed, "")
+}
+
+// GeneratePasswordWithSymbols generates a password with the given requirements and seed,
+// the symbols in the password are chosen from the given symbols, or Symbols if it's empty.
+func GeneratePasswordWithSymbols(length, numDigits, numSymbols int, noUpper bool, seed, symbols string) (string, error) {
+	if len(symbols) == 0 {
+		symbols = Symbols
+	}
 	var rand *mathrand.Rand
 	if len(seed) == 0 {
 		rand = mathrand.New(mathrand.NewSource(time.Now().UnixNano()))
@@ -63,7 +72,7 @@ func GeneratePassword(length, numDigits, numSymbols int, noUpper bool, seed stri
 	gen, err := password.NewGenerator(&password.GeneratorInput{
 		LowerLetters: password.LowerLetters,
 		UpperLetters: password.UpperLetters,
-		Symbols:      Symbols,
+		Symbols:      symbols,
 		Digits:       password.Digits,
 		Reader:       passwordReader,
 	})
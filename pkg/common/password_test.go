@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package common
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/sethvargo/go-password/password"
@@ -93,3 +94,53 @@ func TestGeneratorGeneratePassword(t *testing.T) {
 func TestGeneratorGeneratePasswordWithSeed(t *testing.T) {
 	testGeneratorGeneratePasswordWithSeed(t)
 }
+
+func TestGeneratePasswordWithSymbols(t *testing.T) {
+	isLetterOrDigit := func(r rune) bool {
+		return strings.ContainsRune(password.LowerLetters+password.UpperLetters+password.Digits, r)
+	}
+	checkSymbols := func(t *testing.T, passwd, symbols string, numSymbols int) {
+		count := 0
+		for _, r := range passwd {
+			if isLetterOrDigit(r) {
+				continue
+			}
+			if !strings.ContainsRune(symbols, r) {
+				t.Fatalf("password %q contains symbol %q out of %q", passwd, r, symbols)
+			}
+			count++
+		}
+		if count != numSymbols {
+			t.Fatalf("password %q should contain %d symbols, got %d", passwd, numSymbols, count)
+		}
+	}
+
+	t.Run("whitelisted symbols", func(t *testing.T) {
+		symbols := "_-."
+		for i := 0; i < N; i++ {
+			res, err := GeneratePasswordWithSymbols(16, 4, 4, false, "", symbols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSymbols(t, res, symbols, 4)
+		}
+	})
+
+	t.Run("default symbols", func(t *testing.T) {
+		for i := 0; i < N; i++ {
+			res, err := GeneratePasswordWithSymbols(16, 4, 4, false, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSymbols(t, res, Symbols, 4)
+		}
+	})
+
+	t.Run("whitelisted symbols with seed", func(t *testing.T) {
+		res, err := GeneratePasswordWithSymbols(16, 4, 4, false, "mock-seed", "_")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSymbols(t, res, "_", 4)
+	})
+}
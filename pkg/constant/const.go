@@ -22,6 +22,7 @@ package constant
 const (
 	// config keys used in viper, DON'T refactor the value without careful inspections
 	CfgKeyServerInfo                    = "_KUBE_SERVER_INFO"
+	CfgKeyServiceMonitorAPIEnabled      = "_SERVICE_MONITOR_API_ENABLED"
 	CfgKeyCtrlrMgrNS                    = "CM_NAMESPACE"
 	CfgKeyCtrlrMgrAffinity              = "CM_AFFINITY"
 	CfgKeyCtrlrMgrNodeSelector          = "CM_NODE_SELECTOR"
@@ -41,11 +42,20 @@ const (
 	CfgKeyDataPlaneTolerations = "DATA_PLANE_TOLERATIONS"
 	CfgKeyDataPlaneAffinity    = "DATA_PLANE_AFFINITY"
 
+	// command executor config keys
+	CfgKeyCmdExecutorDefaultResources = "CMD_EXECUTOR_DEFAULT_RESOURCES"
+
 	// storage config keys
 	CfgKeyDefaultStorageClass = "DEFAULT_STORAGE_CLASS"
 
 	// customized encryption key for encrypting the password of connection credential.
 	CfgKeyDPEncryptionKey = "DP_ENCRYPTION_KEY"
+
+	// the URL of the KMS webhook to generate the passwords of the system accounts.
+	CfgKeyKMSPasswordGeneratorURL = "KMS_PASSWORD_GENERATOR_URL"
+
+	// webhook config keys
+	CfgKeyRejectUnknownConnCredentialPlaceholders = "REJECT_UNKNOWN_CONN_CREDENTIAL_PLACEHOLDERS"
 )
 
 const (
@@ -126,6 +136,7 @@ const (
 	ServiceDescriptorNameLabelKey            = "servicedescriptor.kubeblocks.io/name"
 	RestoreForHScaleLabelKey                 = "apps.kubeblocks.io/restore-for-hscale"
 	ResourceConstraintProviderLabelKey       = "resourceconstraint.kubeblocks.io/provider"
+	BackupPolicyTemplateLabelKey             = "apps.kubeblocks.io/backup-policy-template" // BackupPolicyTemplateLabelKey marks the BackupPolicyTemplate which the backup policy is generated from
 
 	// StatefulSetPodNameLabelKey is used to mark the pod name of the StatefulSet
 	StatefulSetPodNameLabelKey = "statefulset.kubernetes.io/pod-name"
@@ -140,8 +151,10 @@ const (
 	SnapShotForStartAnnotationKey               = "kubeblocks.io/snapshot-for-start"
 	ComponentReplicasAnnotationKey              = "apps.kubeblocks.io/component-replicas" // ComponentReplicasAnnotationKey specifies the number of pods in replicas
 	BackupPolicyTemplateAnnotationKey           = "apps.kubeblocks.io/backup-policy-template"
+	DefaultBackupPolicyTemplateNameAnnotKey     = "apps.kubeblocks.io/default-backup-policy-template" // DefaultBackupPolicyTemplateNameAnnotKey specifies the default BackupPolicyTemplate of the ClusterDefinition or ComponentDefinition.
 	LastAppliedClusterAnnotationKey             = "apps.kubeblocks.io/last-applied-cluster"
 	PVLastClaimPolicyAnnotationKey              = "apps.kubeblocks.io/pv-last-claim-policy"
+	ConnCredentialSeedAnnotationKey             = "apps.kubeblocks.io/connection-credential-seed"
 	HaltRecoveryAllowInconsistentCVAnnotKey     = "clusters.apps.kubeblocks.io/allow-inconsistent-cv"
 	HaltRecoveryAllowInconsistentResAnnotKey    = "clusters.apps.kubeblocks.io/allow-inconsistent-resource"
 	PrimaryAnnotationKey                        = "rs.apps.kubeblocks.io/primary"
@@ -155,6 +168,9 @@ const (
 	KubeBlocksGenerationKey                     = "kubeblocks.io/generation"
 	ExtraEnvAnnotationKey                       = "kubeblocks.io/extra-env"
 	LastRoleSnapshotVersionAnnotationKey        = "apps.kubeblocks.io/last-role-snapshot-version"
+	RotateConnCredentialAnnotationKey           = "apps.kubeblocks.io/rotate-connection-credential" // RotateConnCredentialAnnotationKey triggers the rotation of the random passwords in connection credential
+	AllowUnsafeUpdateStrategyAnnotationKey      = "apps.kubeblocks.io/allow-unsafe-update-strategy" // AllowUnsafeUpdateStrategyAnnotationKey allows the Parallel update strategy for Consensus components
+	OpsRequestRequestorAnnotationKey            = "ops.kubeblocks.io/requestor"                     // OpsRequestRequestorAnnotationKey specifies the user who requested the OpsRequest, it's set by the client, e.g. kbcli
 
 	// kubeblocks.io well-known finalizers
 	DBClusterFinalizerName             = "cluster.kubeblocks.io/finalizer"
@@ -220,8 +236,12 @@ const (
 	ServiceKind               = "Service"
 	ConfigMapKind             = "ConfigMap"
 	DaemonSetKind             = "DaemonSet"
+	ServiceMonitorKind        = "ServiceMonitor"
 )
 
+// MonitoringAPIGroupVersion is the API group version of the ServiceMonitor of prometheus-operator.
+const MonitoringAPIGroupVersion = "monitoring.coreos.com/v1"
+
 const (
 	// BackupRetain always retained, unless manually deleted by the user
 	BackupRetain = "Retain"
@@ -306,6 +326,7 @@ const (
 	VolumeRestorePolicyKeyForRestore = "volumeRestorePolicy"
 	RestoreTimeKeyForRestore         = "restoreTime"
 	ConnectionPassword               = "connectionPassword"
+	EncryptionAlgorithmKeyForRestore = "encryptionAlgorithm"
 )
 
 const (
@@ -45,6 +45,7 @@ const (
 	KBEnvCompName           = "KB_COMP_NAME"
 	KBEnvCompReplicas       = "KB_COMP_REPLICAS"
 	KBEnvCompServiceVersion = "KB_COMP_SERVICE_VERSION"
+	KBEnvPodList            = "KB_POD_LIST"
 )
 
 // Pod
@@ -111,6 +112,8 @@ const (
 	// KBEnvRsmRoleUpdateMechanism defines the method to send events: DirectAPIServerEventUpdate(through lorry service), ReadinessProbeEventUpdate(through kubelet service)
 	KBEnvRsmRoleUpdateMechanism = "KB_RSM_ROLE_UPDATE_MECHANISM"
 	KBEnvRoleProbeTimeout       = "KB_RSM_ROLE_PROBE_TIMEOUT"
+	// KBEnvRoleProbeHTTPAction defines the HTTP request performed by the role probe, the response body is taken as the role.
+	KBEnvRoleProbeHTTPAction = "KB_ROLE_PROBE_HTTP_ACTION"
 
 	KBEnvVolumeProtectionSpec = "KB_VOLUME_PROTECTION_SPEC"
 )
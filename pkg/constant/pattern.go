@@ -75,6 +75,15 @@ func GenerateDefaultComponentHeadlessServiceName(clusterName, compName string) s
 	return GenerateComponentHeadlessServiceName(clusterName, compName, "")
 }
 
+// GenerateComponentHeadlessServiceNameWithSuffix generates the headless service name for component, the custom suffix
+// replaces the default suffix `headless` if it's not empty.
+func GenerateComponentHeadlessServiceNameWithSuffix(clusterName, compName, suffix string) string {
+	if len(suffix) > 0 {
+		return fmt.Sprintf("%s-%s-%s", clusterName, compName, suffix)
+	}
+	return GenerateDefaultComponentHeadlessServiceName(clusterName, compName)
+}
+
 // GenerateDefaultConnCredential generates the default connection credential name for cluster.
 // TODO: deprecated, will be removed later.
 func GenerateDefaultConnCredential(clusterName string) string {
@@ -86,6 +95,11 @@ func GenerateClusterComponentEnvPattern(clusterName, compName string) string {
 	return fmt.Sprintf("%s-%s-env", clusterName, compName)
 }
 
+// GenerateClusterComponentEnvSecretPattern generates the name of the secret holding the sensitive envs of the component.
+func GenerateClusterComponentEnvSecretPattern(clusterName, compName string) string {
+	return fmt.Sprintf("%s-%s-env-secret", clusterName, compName)
+}
+
 // GenerateDefaultServiceAccountName generates default service account name for a cluster.
 func GenerateDefaultServiceAccountName(name string) string {
 	return fmt.Sprintf("%s-%s", KBLowerPrefix, name)
@@ -21,6 +21,7 @@ package builder
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -102,6 +103,11 @@ func (builder *ComponentBuilder) SetInstances(instances []string) *ComponentBuil
 	return builder
 }
 
+func (builder *ComponentBuilder) SetPodSpecPatch(patch *runtime.RawExtension) *ComponentBuilder {
+	builder.get().Spec.PodSpecPatch = patch
+	return builder
+}
+
 func (builder *ComponentBuilder) SetTLSConfig(enable bool, issuer *appsv1alpha1.Issuer) *ComponentBuilder {
 	if enable {
 		builder.get().Spec.TLSConfig = &appsv1alpha1.TLSConfig{
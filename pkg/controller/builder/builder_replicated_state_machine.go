@@ -95,6 +95,11 @@ func (builder *ReplicatedStateMachineBuilder) SetServiceName(serviceName string)
 	return builder
 }
 
+func (builder *ReplicatedStateMachineBuilder) SetHeadlessService(service *workloads.HeadlessService) *ReplicatedStateMachineBuilder {
+	builder.get().Spec.HeadlessService = service
+	return builder
+}
+
 func (builder *ReplicatedStateMachineBuilder) SetRoles(roles []workloads.ReplicaRole) *ReplicatedStateMachineBuilder {
 	builder.get().Spec.Roles = roles
 	return builder
@@ -182,6 +187,11 @@ func (builder *ReplicatedStateMachineBuilder) SetMemberUpdateStrategy(strategy *
 	return builder
 }
 
+func (builder *ReplicatedStateMachineBuilder) SetLeaderEvictionPolicy(policy workloads.LeaderEvictionPolicy) *ReplicatedStateMachineBuilder {
+	builder.get().Spec.LeaderEvictionPolicy = policy
+	return builder
+}
+
 func (builder *ReplicatedStateMachineBuilder) SetPaused(paused bool) *ReplicatedStateMachineBuilder {
 	builder.get().Spec.Paused = paused
 	return builder
@@ -114,6 +114,11 @@ func (builder *ServiceBuilder) SetPublishNotReadyAddresses(enabled bool) *Servic
 	return builder
 }
 
+func (builder *ServiceBuilder) SetSessionAffinity(affinity corev1.ServiceAffinity) *ServiceBuilder {
+	builder.get().Spec.SessionAffinity = affinity
+	return builder
+}
+
 func (builder *ServiceBuilder) Optimize4ExternalTraffic() *ServiceBuilder {
 	if builder.get().Spec.Type == corev1.ServiceTypeLoadBalancer && len(builder.get().Spec.ExternalTrafficPolicy) == 0 {
 		// Set externalTrafficPolicy to Local has two benefits:
@@ -50,6 +50,16 @@ func ShortName(clusterName, compName string) (string, error) {
 	return name, nil
 }
 
+// HeadlessServiceName returns the name of the headless service which governs the pods of the component,
+// the default suffix `headless` of the name may be customized by the ClusterDefinition.
+func HeadlessServiceName(synthesizedComp *SynthesizedComponent) string {
+	var suffix string
+	if synthesizedComp.HeadlessService != nil {
+		suffix = synthesizedComp.HeadlessService.NameSuffix
+	}
+	return constant.GenerateComponentHeadlessServiceNameWithSuffix(synthesizedComp.ClusterName, synthesizedComp.Name, suffix)
+}
+
 func GetClusterName(comp *appsv1alpha1.Component) (string, error) {
 	return getCompLabelValue(comp, constant.AppInstanceLabelKey)
 }
@@ -90,6 +100,7 @@ func BuildComponent(cluster *appsv1alpha1.Cluster, clusterCompSpec *appsv1alpha1
 		SetTLSConfig(clusterCompSpec.TLS, clusterCompSpec.Issuer).
 		SetNodes(clusterCompSpec.Nodes).
 		SetInstances(clusterCompSpec.Instances).
+		SetPodSpecPatch(clusterCompSpec.PodSpecPatch).
 		SetTransformPolicy(clusterCompSpec.RsmTransformPolicy)
 	if customLabels != nil {
 		compBuilder.AddLabelsInMap(customLabels)
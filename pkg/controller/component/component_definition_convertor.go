@@ -21,8 +21,10 @@ package component
 
 import (
 	"fmt"
+	"net/http"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
@@ -34,6 +36,56 @@ import (
 
 // TODO(component): type check
 
+// ConvertClusterComponentDefToComponentDefinition converts the component definition @compDefName of the ClusterDefinition
+// to a standalone ComponentDefinition object, which helps to migrate the deprecated componentDefs to the ComponentDefinition API.
+// The name of the ComponentDefinition is generated as "<clusterDefName>-<compDefName>".
+// An error is returned if the component definition has any construct that cannot be translated into the ComponentDefinition.
+func ConvertClusterComponentDefToComponentDefinition(cd *appsv1alpha1.ClusterDefinition, compDefName string) (*appsv1alpha1.ComponentDefinition, error) {
+	if cd == nil {
+		return nil, fmt.Errorf("the cluster definition is nil")
+	}
+	clusterCompDef := cd.GetComponentDefByName(compDefName)
+	if clusterCompDef == nil {
+		return nil, fmt.Errorf("component definition %s not found in cluster definition %s", compDefName, cd.Name)
+	}
+	if err := checkClusterComponentDefConvertible(clusterCompDef); err != nil {
+		return nil, fmt.Errorf("component definition %s of cluster definition %s cannot be converted: %w", compDefName, cd.Name, err)
+	}
+	compDef, err := buildComponentDefinitionByConversion(clusterCompDef, nil)
+	if err != nil {
+		return nil, err
+	}
+	compDef.TypeMeta = metav1.TypeMeta{
+		APIVersion: appsv1alpha1.APIVersion,
+		Kind:       appsv1alpha1.ComponentDefinitionKind,
+	}
+	compDef.Name = fmt.Sprintf("%s-%s", cd.Name, compDefName)
+	return compDef, nil
+}
+
+// checkClusterComponentDefConvertible checks the constructs of the ClusterComponentDefinition which have no counterpart in the ComponentDefinition.
+func checkClusterComponentDefConvertible(clusterCompDef *appsv1alpha1.ClusterComponentDefinition) error {
+	if len(clusterCompDef.ComponentDefRef) > 0 {
+		return fmt.Errorf("the env injection of componentDefRef is not supported, use vars of the ComponentDefinition instead")
+	}
+	if clusterCompDef.HorizontalScalePolicy != nil &&
+		clusterCompDef.HorizontalScalePolicy.Type != appsv1alpha1.HScaleDataClonePolicyNone {
+		return fmt.Errorf("the horizontalScalePolicy %s is not supported", clusterCompDef.HorizontalScalePolicy.Type)
+	}
+	if spec := clusterCompDef.VolumeProtectionSpec; spec != nil {
+		// only the high watermark can be converted to the volumes of the ComponentDefinition.
+		if spec.LowWatermark > 0 {
+			return fmt.Errorf("the lowWatermark of volumeProtectionSpec is not supported")
+		}
+		for _, v := range spec.Volumes {
+			if v.LowWatermark != nil {
+				return fmt.Errorf("the lowWatermark of volume %s is not supported", v.Name)
+			}
+		}
+	}
+	return nil
+}
+
 // buildComponentDefinitionByConversion builds a ComponentDefinition from a ClusterComponentDefinition and a ClusterComponentVersion.
 func buildComponentDefinitionByConversion(clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
 	clusterCompVer *appsv1alpha1.ClusterComponentVersion) (*appsv1alpha1.ComponentDefinition, error) {
@@ -56,9 +108,12 @@ func buildComponentDefinitionByConversion(clusterCompDef *appsv1alpha1.ClusterCo
 		"scripts":                &compDefScriptsConvertor{},
 		"policyrules":            &compDefPolicyRulesConvertor{},
 		"labels":                 &compDefLabelsConvertor{},
+		"annotations":            &compDefAnnotationsConvertor{},
 		"replicasLimit":          &compDefReplicasLimitConvertor{},
 		"systemaccounts":         &compDefSystemAccountsConvertor{},
 		"updatestrategy":         &compDefUpdateStrategyConvertor{},
+		"minreadyseconds":        &compDefMinReadySecondsConvertor{},
+		"leaderevictionpolicy":   &compDefLeaderEvictionPolicyConvertor{},
 		"roles":                  &compDefRolesConvertor{},
 		"rolearbitrator":         &compDefRoleArbitratorConvertor{},
 		"lifecycleactions":       &compDefLifecycleActionsConvertor{},
@@ -253,9 +308,11 @@ func (c *compDefServicesConvertor) convert(args ...any) (any, error) {
 		AddPorts(clusterCompDef.Service.ToSVCSpec().Ports...).
 		GetObject()
 
+	headlessSvcSpec := clusterCompDef.Service.ToHeadlessSVCSpec()
 	headlessSvcBuilder := builder.NewHeadlessServiceBuilder("", "").
-		AddPorts(clusterCompDef.Service.ToSVCSpec().Ports...).
-		SetPublishNotReadyAddresses(true)
+		AddPorts(headlessSvcSpec.Ports...).
+		SetPublishNotReadyAddresses(headlessSvcSpec.PublishNotReadyAddresses).
+		SetSessionAffinity(headlessSvcSpec.SessionAffinity)
 	if clusterCompDef.PodSpec != nil {
 		for _, container := range clusterCompDef.PodSpec.Containers {
 			headlessSvcBuilder = headlessSvcBuilder.AddContainerPorts(container.Ports...)
@@ -275,7 +332,7 @@ func (c *compDefServicesConvertor) convert(args ...any) (any, error) {
 		{
 			Service: appsv1alpha1.Service{
 				Name:         "headless",
-				ServiceName:  "headless",
+				ServiceName:  c.headlessServiceName(clusterCompDef),
 				Spec:         headlessSvc.Spec,
 				RoleSelector: c.roleSelector(clusterCompDef),
 			},
@@ -284,6 +341,13 @@ func (c *compDefServicesConvertor) convert(args ...any) (any, error) {
 	return services, nil
 }
 
+func (c *compDefServicesConvertor) headlessServiceName(clusterCompDef *appsv1alpha1.ClusterComponentDefinition) string {
+	if suffix := clusterCompDef.Service.GetHeadlessNameSuffix(); suffix != "" {
+		return suffix
+	}
+	return "headless"
+}
+
 func (c *compDefServicesConvertor) removeDuplicatePorts(svc *corev1.Service) *corev1.Service {
 	ports := make(map[int32]bool)
 	servicePorts := make([]corev1.ServicePort, 0)
@@ -373,6 +437,22 @@ func (c *compDefLabelsConvertor) convert(args ...any) (any, error) {
 	return labels, nil
 }
 
+// compDefAnnotationsConvertor is an implementation of the convertor interface, used to convert the given object into ComponentDefinition.Spec.Annotations.
+type compDefAnnotationsConvertor struct{}
+
+func (c *compDefAnnotationsConvertor) convert(args ...any) (any, error) {
+	clusterCompDef := args[0].(*appsv1alpha1.ClusterComponentDefinition)
+	if clusterCompDef.CustomAnnotationSpecs == nil {
+		return nil, nil
+	}
+
+	annotations := make(map[string]string, 0)
+	for _, customAnnotation := range clusterCompDef.CustomAnnotationSpecs {
+		annotations[customAnnotation.Key] = customAnnotation.Value
+	}
+	return annotations, nil
+}
+
 type compDefReplicasLimitConvertor struct{}
 
 func (c *compDefReplicasLimitConvertor) convert(args ...any) (any, error) {
@@ -392,7 +472,7 @@ func (c *compDefSystemAccountsConvertor) convert(args ...any) (any, error) {
 	for _, account := range clusterCompDef.SystemAccounts.Accounts {
 		accounts = append(accounts, appsv1alpha1.SystemAccount{
 			Name:                     string(account.Name),
-			PasswordGenerationPolicy: clusterCompDef.SystemAccounts.PasswordConfig,
+			PasswordGenerationPolicy: clusterCompDef.SystemAccounts.GetPasswordConfig(account),
 			SecretRef:                account.ProvisionPolicy.SecretRef,
 		})
 		if account.ProvisionPolicy.Statements != nil {
@@ -434,6 +514,34 @@ func (c *compDefUpdateStrategyConvertor) convert(args ...any) (any, error) {
 	return strategy, nil
 }
 
+// compDefMinReadySecondsConvertor is an implementation of the convertor interface, used to convert the given object into ComponentDefinition.Spec.MinReadySeconds.
+type compDefMinReadySecondsConvertor struct{}
+
+func (c *compDefMinReadySecondsConvertor) convert(args ...any) (any, error) {
+	clusterCompDef := args[0].(*appsv1alpha1.ClusterComponentDefinition)
+	if clusterCompDef.RSMSpec != nil && clusterCompDef.RSMSpec.MinReadySeconds > 0 {
+		return clusterCompDef.RSMSpec.MinReadySeconds, nil
+	}
+	if w := clusterCompDef.GetStatefulSetWorkload(); w != nil {
+		return w.GetMinReadySeconds(), nil
+	}
+	return int32(0), nil
+}
+
+// compDefLeaderEvictionPolicyConvertor is an implementation of the convertor interface, used to convert the given object into ComponentDefinition.Spec.LeaderEvictionPolicy.
+type compDefLeaderEvictionPolicyConvertor struct{}
+
+func (c *compDefLeaderEvictionPolicyConvertor) convert(args ...any) (any, error) {
+	clusterCompDef := args[0].(*appsv1alpha1.ClusterComponentDefinition)
+	if clusterCompDef.RSMSpec != nil && len(clusterCompDef.RSMSpec.LeaderEvictionPolicy) > 0 {
+		return clusterCompDef.RSMSpec.LeaderEvictionPolicy, nil
+	}
+	if clusterCompDef.ConsensusSpec != nil {
+		return clusterCompDef.ConsensusSpec.LeaderEvictionPolicy, nil
+	}
+	return workloads.LeaderEvictionPolicy(""), nil
+}
+
 // compDefRolesConvertor is an implementation of the convertor interface, used to convert the given object into ComponentDefinition.Spec.Roles.
 type compDefRolesConvertor struct{}
 
@@ -638,6 +746,19 @@ func (c *compDefLifecycleActionsConvertor) convertRoleProbe(clusterCompDef *apps
 	}
 
 	roleProbe.BuiltinHandler = &builtinHandler
+	if httpGet := clusterCompDefRoleProbe.HTTPGet; httpGet != nil {
+		roleProbe.CustomHandler = &appsv1alpha1.Action{
+			HTTP: &appsv1alpha1.HTTPAction{
+				Path:        httpGet.Path,
+				Port:        httpGet.Port,
+				Host:        httpGet.Host,
+				Scheme:      httpGet.Scheme,
+				Method:      http.MethodGet,
+				HTTPHeaders: httpGet.HTTPHeaders,
+			},
+		}
+		return roleProbe
+	}
 	if clusterCompDefRoleProbe.Commands == nil || len(clusterCompDefRoleProbe.Commands.Queries) == 0 {
 		roleProbe.CustomHandler = nil
 		return roleProbe
@@ -669,6 +790,8 @@ func (c *compDefLifecycleActionsConvertor) convertPostProvision(postStart *appsv
 				Args:    postStart.CmdExecutorConfig.Args,
 			},
 			Env:          postStart.CmdExecutorConfig.Env,
+			EnvFrom:      postStart.CmdExecutorConfig.EnvFrom,
+			Resources:    postStart.CmdExecutorConfig.Resources,
 			PreCondition: &defaultPreCondition,
 		},
 	}
@@ -695,7 +818,9 @@ func (c *compDefLifecycleActionsConvertor) convertSwitchover(switchover *appsv1a
 				Command: spec.WithCandidate.CmdExecutorConfig.Command,
 				Args:    spec.WithCandidate.CmdExecutorConfig.Args,
 			},
-			Env: spec.WithCandidate.CmdExecutorConfig.Env,
+			Env:       spec.WithCandidate.CmdExecutorConfig.Env,
+			EnvFrom:   spec.WithCandidate.CmdExecutorConfig.EnvFrom,
+			Resources: spec.WithCandidate.CmdExecutorConfig.Resources,
 		}
 	}
 	if spec.WithoutCandidate != nil && spec.WithoutCandidate.CmdExecutorConfig != nil {
@@ -705,30 +830,59 @@ func (c *compDefLifecycleActionsConvertor) convertSwitchover(switchover *appsv1a
 				Command: spec.WithoutCandidate.CmdExecutorConfig.Command,
 				Args:    spec.WithoutCandidate.CmdExecutorConfig.Args,
 			},
-			Env: spec.WithoutCandidate.CmdExecutorConfig.Env,
+			Env:       spec.WithoutCandidate.CmdExecutorConfig.Env,
+			EnvFrom:   spec.WithoutCandidate.CmdExecutorConfig.EnvFrom,
+			Resources: spec.WithoutCandidate.CmdExecutorConfig.Resources,
 		}
 	}
 
 	mergeScriptSpec := func() []appsv1alpha1.ScriptSpecSelector {
-		if len(spec.WithCandidate.ScriptSpecSelectors) == 0 && len(spec.WithoutCandidate.ScriptSpecSelectors) == 0 {
+		var scriptSpecSelectors []appsv1alpha1.ScriptSpecSelector
+		if spec.WithCandidate != nil {
+			scriptSpecSelectors = append(scriptSpecSelectors, spec.WithCandidate.ScriptSpecSelectors...)
+		}
+		if spec.WithoutCandidate != nil {
+			scriptSpecSelectors = append(scriptSpecSelectors, spec.WithoutCandidate.ScriptSpecSelectors...)
+		}
+		if len(scriptSpecSelectors) == 0 {
 			return nil
 		}
 
+		// remove the duplicated script specs and keep the order stable.
 		mergeScriptSpecMap := map[appsv1alpha1.ScriptSpecSelector]bool{}
-		for _, val := range append(spec.WithCandidate.ScriptSpecSelectors, spec.WithoutCandidate.ScriptSpecSelectors...) {
+		scriptSpecList := make([]appsv1alpha1.ScriptSpecSelector, 0, len(scriptSpecSelectors))
+		for _, val := range scriptSpecSelectors {
+			if mergeScriptSpecMap[val] {
+				continue
+			}
 			mergeScriptSpecMap[val] = true
+			scriptSpecList = append(scriptSpecList, val)
 		}
+		return scriptSpecList
+	}
 
-		scriptSpecList := make([]appsv1alpha1.ScriptSpecSelector, 0, len(mergeScriptSpecMap))
-		for key := range mergeScriptSpecMap {
-			scriptSpecList = append(scriptSpecList, key)
+	convertCheck := func(check *appsv1alpha1.CmdExecutorConfig) *appsv1alpha1.Action {
+		if check == nil {
+			return nil
+		}
+		return &appsv1alpha1.Action{
+			Image: check.Image,
+			Exec: &appsv1alpha1.ExecAction{
+				Command: check.Command,
+				Args:    check.Args,
+			},
+			Env:       check.Env,
+			EnvFrom:   check.EnvFrom,
+			Resources: check.Resources,
 		}
-		return scriptSpecList
 	}
 
 	return &appsv1alpha1.ComponentSwitchover{
 		WithCandidate:       withCandidateAction,
 		WithoutCandidate:    withoutCandidateAction,
 		ScriptSpecSelectors: mergeScriptSpec(),
+		PreCheck:            convertCheck(spec.PreCheck),
+		PostCheck:           convertCheck(spec.PostCheck),
+		ParametersSchema:    spec.ParametersSchema,
 	}
 }
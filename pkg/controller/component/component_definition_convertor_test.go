@@ -20,11 +20,18 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package component
 
 import (
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
@@ -269,14 +276,12 @@ var _ = Describe("Component Definition Convertor", func() {
 					{
 						Key:   "scope",
 						Value: "scope",
-						Resources: []appsv1alpha1.GVKResource{
-							{
-								GVK: "v1/pod",
-								Selector: map[string]string{
-									"managed-by": "kubeblocks",
-								},
-							},
-						},
+					},
+				},
+				CustomAnnotationSpecs: []appsv1alpha1.CustomAnnotationSpec{
+					{
+						Key:   "mesh",
+						Value: "$(KB_CLUSTER_NAME)-$(KB_COMP_NAME)",
 					},
 				},
 				SwitchoverSpec: &appsv1alpha1.SwitchoverSpec{},
@@ -702,6 +707,19 @@ var _ = Describe("Component Definition Convertor", func() {
 			Expect(labels).Should(BeEquivalentTo(expectedLabels))
 		})
 
+		It("annotations", func() {
+			convertor := &compDefAnnotationsConvertor{}
+			res, err := convertor.convert(clusterCompDef)
+			Expect(err).Should(Succeed())
+
+			annotations := res.(map[string]string)
+			expectedAnnotations := map[string]string{}
+			for _, item := range clusterCompDef.CustomAnnotationSpecs {
+				expectedAnnotations[item.Key] = item.Value
+			}
+			Expect(annotations).Should(BeEquivalentTo(expectedAnnotations))
+		})
+
 		Context("system accounts", func() {
 			It("w/o accounts", func() {
 				clusterCompDef.SystemAccounts = nil
@@ -755,6 +773,36 @@ var _ = Describe("Component Definition Convertor", func() {
 			})
 		})
 
+		Context("min ready seconds", func() {
+			It("w/o workload spec", func() {
+				clusterCompDef.ConsensusSpec = nil
+
+				convertor := &compDefMinReadySecondsConvertor{}
+				res, err := convertor.convert(clusterCompDef)
+				Expect(err).Should(Succeed())
+				Expect(res).Should(Equal(int32(0)))
+			})
+
+			It("ok", func() {
+				clusterCompDef.ConsensusSpec.MinReadySeconds = 10
+
+				convertor := &compDefMinReadySecondsConvertor{}
+				res, err := convertor.convert(clusterCompDef)
+				Expect(err).Should(Succeed())
+				Expect(res).Should(Equal(int32(10)))
+			})
+
+			It("rsm spec takes precedence", func() {
+				clusterCompDef.ConsensusSpec.MinReadySeconds = 10
+				clusterCompDef.RSMSpec = &appsv1alpha1.RSMSpec{MinReadySeconds: 30}
+
+				convertor := &compDefMinReadySecondsConvertor{}
+				res, err := convertor.convert(clusterCompDef)
+				Expect(err).Should(Succeed())
+				Expect(res).Should(Equal(int32(30)))
+			})
+		})
+
 		Context("roles", func() {
 			It("non-consensus workload", func() {
 				clusterCompDef.WorkloadType = appsv1alpha1.Stateful
@@ -966,6 +1014,29 @@ var _ = Describe("Component Definition Convertor", func() {
 				Expect(*actions.RoleProbe).Should(BeEquivalentTo(*expectedRoleProbe))
 			})
 
+			It("role probe with http get", func() {
+				clusterCompDef.Probes.RoleProbe.Commands = nil
+				clusterCompDef.Probes.RoleProbe.HTTPGet = &corev1.HTTPGetAction{
+					Path: "/role",
+					Port: intstr.FromString("http"),
+				}
+
+				convertor := &compDefLifecycleActionsConvertor{}
+				res, err := convertor.convert(clusterCompDef)
+				Expect(err).Should(Succeed())
+
+				actions := res.(*appsv1alpha1.ComponentLifecycleActions)
+				Expect(actions.RoleProbe).ShouldNot(BeNil())
+				Expect(actions.RoleProbe.BuiltinHandler).ShouldNot(BeNil())
+				Expect(actions.RoleProbe.CustomHandler).ShouldNot(BeNil())
+				Expect(actions.RoleProbe.CustomHandler.Exec).Should(BeNil())
+				Expect(*actions.RoleProbe.CustomHandler.HTTP).Should(BeEquivalentTo(appsv1alpha1.HTTPAction{
+					Path:   "/role",
+					Port:   intstr.FromString("http"),
+					Method: http.MethodGet,
+				}))
+			})
+
 			It("rsm spec role probe convertor", func() {
 				convertor := &compDefLifecycleActionsConvertor{}
 				mockCommand := []string{
@@ -1006,3 +1077,100 @@ var _ = Describe("Component Definition Convertor", func() {
 		})
 	})
 })
+
+var updateGolden = flag.Bool("update-golden", false, "update the golden files of the component definition conversion")
+
+func TestConvertClusterComponentDefToComponentDefinition(t *testing.T) {
+	buildSwitchoverSpec := func(image string, command ...string) *appsv1alpha1.SwitchoverSpec {
+		return &appsv1alpha1.SwitchoverSpec{
+			WithCandidate: &appsv1alpha1.SwitchoverAction{
+				CmdExecutorConfig: &appsv1alpha1.CmdExecutorConfig{
+					CommandExecutorEnvItem: appsv1alpha1.CommandExecutorEnvItem{
+						Image: image,
+					},
+					CommandExecutorItem: appsv1alpha1.CommandExecutorItem{
+						Command: command,
+					},
+				},
+			},
+		}
+	}
+	cases := []struct {
+		name       string
+		tplType    testapps.ComponentDefTplType
+		switchover *appsv1alpha1.SwitchoverSpec
+		golden     string
+	}{
+		{
+			name:       "mysql",
+			tplType:    testapps.ConsensusMySQLComponent,
+			switchover: buildSwitchoverSpec(testapps.ApeCloudMySQLImage, "mysql", "-e", "call dbms_consensus.change_leader('$KB_SWITCHOVER_CANDIDATE_FQDN')"),
+			golden:     "consensus-mysql.yaml",
+		},
+		{
+			name:       "redis",
+			tplType:    testapps.ReplicationRedisComponent,
+			switchover: buildSwitchoverSpec("redis:7.0.5", "redis-cli", "-h", "$KB_SWITCHOVER_CANDIDATE_FQDN", "replicaof", "no", "one"),
+			golden:     "replication-redis.yaml",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			clusterDef := testapps.NewClusterDefFactory("test-cd").
+				AddComponentDef(c.tplType, c.name).
+				AddSwitchoverSpec(c.switchover).
+				GetObject()
+			compDef, err := ConvertClusterComponentDefToComponentDefinition(clusterDef, c.name)
+			if err != nil {
+				t.Fatalf("failed to convert component definition: %v", err)
+			}
+			if compDef.Name != "test-cd-"+c.name {
+				t.Errorf("unexpected component definition name: %s", compDef.Name)
+			}
+			actual, err := yaml.Marshal(compDef)
+			if err != nil {
+				t.Fatal(err)
+			}
+			goldenFile := filepath.Join("testdata", "compdef_conversion", c.golden)
+			if *updateGolden {
+				if err = os.WriteFile(goldenFile, actual, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := os.ReadFile(goldenFile)
+			if err != nil {
+				t.Fatalf("failed to read golden file %s: %v", goldenFile, err)
+			}
+			if string(expected) != string(actual) {
+				t.Errorf("the converted component definition does not match the golden file %s, "+
+					"run the test with -update-golden to update it if the change is expected.\nactual:\n%s", goldenFile, actual)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		clusterDef := testapps.NewClusterDefFactory("test-cd").
+			AddComponentDef(testapps.ConsensusMySQLComponent, "mysql").
+			GetObject()
+		if _, err := ConvertClusterComponentDefToComponentDefinition(clusterDef, "redis"); err == nil {
+			t.Errorf("expect an error for the non-existent component definition")
+		}
+	})
+
+	t.Run("componentDefRef", func(t *testing.T) {
+		clusterDef := testapps.NewClusterDefFactory("test-cd").
+			AddComponentDef(testapps.ConsensusMySQLComponent, "mysql").
+			GetObject()
+		clusterDef.Spec.ComponentDefs[0].ComponentDefRef = []appsv1alpha1.ComponentDefRef{
+			{
+				ComponentDefName: "proxy",
+				ComponentRefEnvs: []appsv1alpha1.ComponentRefEnv{
+					{Name: "PROXY_HOST", ValueFrom: &appsv1alpha1.ComponentValueFrom{Type: appsv1alpha1.FromServiceRef}},
+				},
+			},
+		}
+		if _, err := ConvertClusterComponentDefToComponentDefinition(clusterDef, "mysql"); err == nil {
+			t.Errorf("expect an error for the componentDefRef")
+		}
+	})
+}
@@ -21,18 +21,43 @@ package component
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/util/jsonpath"
 	"k8s.io/klog/v2"
+	utilsnet "k8s.io/utils/net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 )
 
-func buildComponentRef(clusterDef *appsv1alpha1.ClusterDefinition,
+// ComponentRefCredentialEnv is the env referring to a key of the connection credential secret, its value
+// is resolved by the component controller and kept in the env secret of the component.
+type ComponentRefCredentialEnv struct {
+	Name          string                         `json:"name"`
+	SecretName    string                         `json:"secretName"`
+	Key           string                         `json:"key"`
+	FailurePolicy appsv1alpha1.FailurePolicyType `json:"failurePolicy,omitempty"`
+	Default       string                         `json:"default,omitempty"`
+}
+
+// ComponentRefEnvResult is the result of resolving an env of the componentDefRef, the Value is the default
+// value of the env if it fails to be resolved.
+type ComponentRefEnvResult struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func buildComponentRef(ctx context.Context, cli client.Reader,
+	clusterDef *appsv1alpha1.ClusterDefinition,
 	cluster *appsv1alpha1.Cluster,
 	clusterCompDef *appsv1alpha1.ClusterComponentDefinition,
 	component *SynthesizedComponent) error {
@@ -43,7 +68,10 @@ func buildComponentRef(clusterDef *appsv1alpha1.ClusterDefinition,
 	}
 
 	component.ComponentRefEnvs = make([]corev1.EnvVar, 0)
+	component.ComponentRefCredentialEnvs = make([]ComponentRefCredentialEnv, 0)
+	component.IgnoredComponentRefEnvs = nil
 
+	var errs []error
 	for _, compRef := range compRefs {
 		referredComponentDef := clusterDef.GetComponentDefByName(compRef.ComponentDefName)
 		referredComponents := cluster.Spec.GetDefNameMappingComponents()[compRef.ComponentDefName]
@@ -60,46 +88,73 @@ func buildComponentRef(clusterDef *appsv1alpha1.ClusterDefinition,
 
 		envMap := make(map[string]string)
 		for _, refEnv := range compRef.ComponentRefEnvs {
-			env := corev1.EnvVar{Name: refEnv.Name}
-			var err error
-			if len(refEnv.Value) != 0 {
-				env.Value = refEnv.Value
-			} else if refEnv.ValueFrom != nil {
-				switch refEnv.ValueFrom.Type {
-				case appsv1alpha1.FromFieldRef:
-					if env.Value, err = resolveFieldRef(refEnv.ValueFrom, referredComponents, referredComponentDef); err != nil {
-						return err
-					}
-				case appsv1alpha1.FromServiceRef:
-					if env.Value, err = resolveServiceRef(cluster.Name, referredComponents, referredComponentDef); err != nil {
-						return err
-					}
-				case appsv1alpha1.FromHeadlessServiceRef:
-					if referredComponentDef.WorkloadType == appsv1alpha1.Stateless {
-						errMsg := fmt.Sprintf("headless service ref is not supported for stateless component, cluster: %s, referred component: %s", cluster.Name, referredComponentDef.Name)
-						klog.V(1).Infof(errMsg)
-						if compRef.FailurePolicy == appsv1alpha1.FailurePolicyFail {
-							return fmt.Errorf(errMsg)
-						}
-					}
-					env.Value = resolveHeadlessServiceFieldRef(refEnv.ValueFrom, cluster, referredComponents)
-				}
+			if refEnv.ValueFrom != nil && len(refEnv.Value) == 0 && refEnv.ValueFrom.Type == appsv1alpha1.FromCredentialRef {
+				// the sensitive value is resolved from the secret later, it is not kept in the env configmap.
+				component.ComponentRefCredentialEnvs = append(component.ComponentRefCredentialEnvs, ComponentRefCredentialEnv{
+					Name:          refEnv.Name,
+					SecretName:    constant.GenerateDefaultConnCredential(cluster.Name),
+					Key:           refEnv.ValueFrom.CredentialKey,
+					FailurePolicy: compRef.FailurePolicy,
+					Default:       refEnv.Default,
+				})
+				continue
 			}
 
-			component.ComponentRefEnvs = append(component.ComponentRefEnvs, env)
-			envMap[env.Name] = env.Value
+			// each env is resolved independently, and the failure policy is applied to it individually.
+			result := resolveComponentRefEnv(ctx, cli, cluster, refEnv, referredComponents, referredComponentDef)
+			if result.Err != nil {
+				if compRef.FailurePolicy == appsv1alpha1.FailurePolicyFail {
+					errs = append(errs, result.Err)
+					continue
+				}
+				klog.V(1).Info(result.Err.Error())
+				component.IgnoredComponentRefEnvs = append(component.IgnoredComponentRefEnvs, result)
+			}
+			component.ComponentRefEnvs = append(component.ComponentRefEnvs, corev1.EnvVar{Name: result.Name, Value: result.Value})
+			envMap[result.Name] = result.Value
 		}
 
 		// for each env in componentRefEnvs, resolve reference
-		for _, env := range component.ComponentRefEnvs {
-			val := env.Value
+		for i := range component.ComponentRefEnvs {
+			val := component.ComponentRefEnvs[i].Value
 			for k, v := range envMap {
 				val = strings.ReplaceAll(val, fmt.Sprintf("$(%s)", k), v)
 			}
-			env.Value = val
+			component.ComponentRefEnvs[i].Value = val
 		}
 	}
-	return nil
+	return utilerrors.NewAggregate(errs)
+}
+
+// resolveComponentRefEnv resolves the value of the env referring to the components, the default value of the env
+// is taken if it fails to be resolved.
+func resolveComponentRefEnv(ctx context.Context, cli client.Reader, cluster *appsv1alpha1.Cluster,
+	refEnv appsv1alpha1.ComponentRefEnv, referredComponents []appsv1alpha1.ClusterComponentSpec,
+	referredComponentDef *appsv1alpha1.ClusterComponentDefinition) ComponentRefEnvResult {
+	result := ComponentRefEnvResult{Name: refEnv.Name, Value: refEnv.Value}
+	if len(refEnv.Value) != 0 || refEnv.ValueFrom == nil {
+		return result
+	}
+
+	var err error
+	switch refEnv.ValueFrom.Type {
+	case appsv1alpha1.FromFieldRef:
+		result.Value, err = resolveFieldRef(refEnv.ValueFrom, referredComponents, referredComponentDef)
+	case appsv1alpha1.FromServiceRef:
+		result.Value, err = resolveServiceRef(cluster.Name, referredComponents, referredComponentDef)
+	case appsv1alpha1.FromHeadlessServiceRef:
+		if referredComponentDef.WorkloadType == appsv1alpha1.Stateless {
+			err = fmt.Errorf("headless service ref is not supported for stateless component, cluster: %s, referred component: %s",
+				cluster.Name, referredComponentDef.Name)
+		} else {
+			result.Value, err = resolveHeadlessServiceFieldRef(ctx, cli, refEnv.ValueFrom, cluster, referredComponents, referredComponentDef)
+		}
+	}
+	if err != nil {
+		result.Value = refEnv.Default
+		result.Err = fmt.Errorf("failed to resolve env %s: %w", refEnv.Name, err)
+	}
+	return result
 }
 
 type referredObject struct {
@@ -130,8 +185,11 @@ func resolveServiceRef(clusterName string, components []appsv1alpha1.ClusterComp
 	return fmt.Sprintf("%s-%s", clusterName, components[0].Name), nil
 }
 
-func resolveHeadlessServiceFieldRef(valueFrom *appsv1alpha1.ComponentValueFrom,
-	cluster *appsv1alpha1.Cluster, components []appsv1alpha1.ClusterComponentSpec) string {
+// resolveHeadlessServiceFieldRef renders the addresses of the pods of the components by the format, the pods whose IP
+// is not allocated yet are skipped if the format refers to `$(POD_IP)`.
+func resolveHeadlessServiceFieldRef(ctx context.Context, cli client.Reader, valueFrom *appsv1alpha1.ComponentValueFrom,
+	cluster *appsv1alpha1.Cluster, components []appsv1alpha1.ClusterComponentSpec,
+	componentDef *appsv1alpha1.ClusterComponentDefinition) (string, error) {
 
 	preDefineVars := []string{"POD_NAME", "POD_FQDN", "POD_ORDINAL"}
 
@@ -146,11 +204,26 @@ func resolveHeadlessServiceFieldRef(valueFrom *appsv1alpha1.ComponentValueFrom,
 
 	hosts := make([]string, 0)
 	for _, comp := range components {
+		headlessSvcName := constant.GenerateComponentHeadlessServiceNameWithSuffix(cluster.Name, comp.Name,
+			componentDef.Service.GetHeadlessNameSuffix())
+		var podIPs map[string]map[corev1.IPFamily]string
+		ipFamily := valueFrom.PreferredIPFamily
+		if strings.Contains(format, "$("+podIPVar+")") {
+			var err error
+			if podIPs, err = listHeadlessServicePodIPs(ctx, cli, cluster.Namespace, headlessSvcName); err != nil {
+				return "", err
+			}
+			if len(ipFamily) == 0 {
+				if ipFamily, err = getServiceIPFamily(ctx, cli, cluster.Namespace, headlessSvcName); err != nil {
+					return "", err
+				}
+			}
+		}
 		for i := int32(0); i < comp.Replicas; i++ {
 			qualifiedName := fmt.Sprintf("%s-%s", cluster.Name, comp.Name)
 			podOrdinal := strconv.Itoa(int(i))
 			podName := fmt.Sprintf("%s-%s", qualifiedName, podOrdinal)
-			podFQDN := fmt.Sprintf("%s.%s-headless.%s.svc", podName, qualifiedName, cluster.Namespace)
+			podFQDN := fmt.Sprintf("%s.%s.%s.svc", podName, headlessSvcName, cluster.Namespace)
 
 			valuesToReplace := []string{podName, podFQDN, podOrdinal}
 
@@ -158,10 +231,87 @@ func resolveHeadlessServiceFieldRef(valueFrom *appsv1alpha1.ComponentValueFrom,
 			for idx, preDefineVar := range preDefineVars {
 				host = strings.ReplaceAll(host, "$("+preDefineVar+")", valuesToReplace[idx])
 			}
+			if podIPs != nil {
+				// the pods without IP are skipped, the component is reconciled again by the changes of the
+				// endpoint slices once they get their IPs.
+				podIP := selectPodIP(podIPs[podName], ipFamily)
+				if len(podIP) == 0 {
+					continue
+				}
+				host = replacePodIP(host, podIP)
+			}
 			hosts = append(hosts, host)
 		}
 	}
-	return strings.Join(hosts, joinWith)
+	return strings.Join(hosts, joinWith), nil
+}
+
+const podIPVar = "POD_IP"
+
+// listHeadlessServicePodIPs returns the IPs of the pods by their IP families from the endpoint slices of the headless service.
+func listHeadlessServicePodIPs(ctx context.Context, cli client.Reader, namespace, svcName string) (map[string]map[corev1.IPFamily]string, error) {
+	slices := &discoveryv1.EndpointSliceList{}
+	if err := cli.List(ctx, slices, client.InNamespace(namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: svcName}); err != nil {
+		return nil, err
+	}
+	return buildPodIPs(slices.Items), nil
+}
+
+func buildPodIPs(slices []discoveryv1.EndpointSlice) map[string]map[corev1.IPFamily]string {
+	podIPs := map[string]map[corev1.IPFamily]string{}
+	for _, slice := range slices {
+		var ipFamily corev1.IPFamily
+		switch slice.AddressType {
+		case discoveryv1.AddressTypeIPv4:
+			ipFamily = corev1.IPv4Protocol
+		case discoveryv1.AddressTypeIPv6:
+			ipFamily = corev1.IPv6Protocol
+		default:
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" || len(endpoint.Addresses) == 0 {
+				continue
+			}
+			if podIPs[endpoint.TargetRef.Name] == nil {
+				podIPs[endpoint.TargetRef.Name] = map[corev1.IPFamily]string{}
+			}
+			podIPs[endpoint.TargetRef.Name][ipFamily] = endpoint.Addresses[0]
+		}
+	}
+	return podIPs
+}
+
+// getServiceIPFamily returns the primary IP family of the service, IPv4 is returned if the service is not found.
+func getServiceIPFamily(ctx context.Context, cli client.Reader, namespace, svcName string) (corev1.IPFamily, error) {
+	svc := &corev1.Service{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: svcName}, svc); err != nil {
+		return corev1.IPv4Protocol, client.IgnoreNotFound(err)
+	}
+	if len(svc.Spec.IPFamilies) == 0 {
+		return corev1.IPv4Protocol, nil
+	}
+	return svc.Spec.IPFamilies[0], nil
+}
+
+// selectPodIP selects the pod IP of the preferred IP family, or the IP of the other family if the pod is single-stack.
+func selectPodIP(podIPs map[corev1.IPFamily]string, preferred corev1.IPFamily) string {
+	if ip, ok := podIPs[preferred]; ok {
+		return ip
+	}
+	for _, ip := range podIPs {
+		return ip
+	}
+	return ""
+}
+
+// replacePodIP replaces the `$(POD_IP)` in the host, the IPv6 address is enclosed in brackets if it is followed by a port.
+func replacePodIP(host, podIP string) string {
+	if utilsnet.IsIPv6String(podIP) {
+		host = strings.ReplaceAll(host, "$("+podIPVar+"):", "["+podIP+"]:")
+	}
+	return strings.ReplaceAll(host, "$("+podIPVar+")", podIP)
 }
 
 func retrieveValueByJSONPath(jsonObj interface{}, jpath string) ([]byte, error) {
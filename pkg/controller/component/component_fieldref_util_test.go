@@ -20,13 +20,24 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package component
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 )
 
@@ -184,12 +195,251 @@ var _ = Describe("ComponentRef Fields Tests", func() {
 				JoinWith: "",
 			}
 
-			value := resolveHeadlessServiceFieldRef(valueFrom, cluster, components)
+			value, err := resolveHeadlessServiceFieldRef(testCtx.Ctx, k8sClient, valueFrom, cluster, components, componentDef)
+			Expect(err).Should(Succeed())
 			addrs := strings.Split(value, ",")
 			Expect(len(addrs)).To(Equal(int(replicas)))
 			for i, addr := range addrs {
 				Expect(addr).To(Equal(fmt.Sprintf("%s-%s-%d.%s-%s-headless.%s.svc", cluster.Name, referredCompName, i, cluster.Name, referredCompName, cluster.Namespace)))
 			}
+
+			By("construct the customized headless service name")
+			componentDef.Service.Headless = &appsv1alpha1.HeadlessServiceSpec{NameSuffix: "hs"}
+			value, err = resolveHeadlessServiceFieldRef(testCtx.Ctx, k8sClient, valueFrom, cluster, components, componentDef)
+			Expect(err).Should(Succeed())
+			for i, addr := range strings.Split(value, ",") {
+				Expect(addr).To(Equal(fmt.Sprintf("%s-%s-%d.%s-%s-hs.%s.svc", cluster.Name, referredCompName, i, cluster.Name, referredCompName, cluster.Namespace)))
+			}
+		})
+
+		It("test credentialRef", func() {
+			clusterDef := clusterDefBuilder.GetObject()
+
+			By("add one component to cluster")
+			clusterBuilder = clusterBuilder.AddComponent(mysqlCompName, mysqlCompDefName).AddComponent(referredCompName, referredCompDefName)
+			cluster := clusterBuilder.GetObject()
+
+			clusterCompDef := &appsv1alpha1.ClusterComponentDefinition{
+				Name: mysqlCompDefName,
+				ComponentDefRef: []appsv1alpha1.ComponentDefRef{
+					{
+						ComponentDefName: referredCompDefName,
+						FailurePolicy:    appsv1alpha1.FailurePolicyIgnore,
+						ComponentRefEnvs: []appsv1alpha1.ComponentRefEnv{
+							{
+								Name: "MAXSCALE_PASSWORD",
+								ValueFrom: &appsv1alpha1.ComponentValueFrom{
+									Type:          appsv1alpha1.FromCredentialRef,
+									CredentialKey: "password",
+								},
+							},
+						},
+					},
+				},
+			}
+
+			By("build component ref, the credential env should not be kept in the component ref envs")
+			synthesizedComp := &SynthesizedComponent{}
+			Expect(buildComponentRef(testCtx.Ctx, k8sClient, clusterDef, cluster, clusterCompDef, synthesizedComp)).Should(Succeed())
+			Expect(synthesizedComp.ComponentRefEnvs).Should(BeEmpty())
+			Expect(synthesizedComp.ComponentRefCredentialEnvs).Should(HaveLen(1))
+			credEnv := synthesizedComp.ComponentRefCredentialEnvs[0]
+			Expect(credEnv.Name).Should(Equal("MAXSCALE_PASSWORD"))
+			Expect(credEnv.SecretName).Should(Equal(constant.GenerateDefaultConnCredential(cluster.Name)))
+			Expect(credEnv.Key).Should(Equal("password"))
+			Expect(credEnv.FailurePolicy).Should(Equal(appsv1alpha1.FailurePolicyIgnore))
 		})
 	})
 })
+
+func TestResolveHeadlessServicePodIPs(t *testing.T) {
+	const (
+		namespace = "default"
+		svcName   = "mycluster-proxy-headless"
+	)
+	cluster := &appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "mycluster"}}
+	components := []appsv1alpha1.ClusterComponentSpec{{Name: "proxy", Replicas: 3}}
+	componentDef := &appsv1alpha1.ClusterComponentDefinition{Name: "proxy", WorkloadType: appsv1alpha1.Stateful}
+
+	newEndpointSlice := func(addressType discoveryv1.AddressType, podIPs map[string]string) *discoveryv1.EndpointSlice {
+		slice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      svcName + "-" + strings.ToLower(string(addressType)),
+				Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+			},
+			AddressType: addressType,
+		}
+		for pod, ip := range podIPs {
+			slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+				Addresses: []string{ip},
+				TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod},
+			})
+		}
+		return slice
+	}
+	newService := func(ipFamilies ...corev1.IPFamily) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: svcName},
+			Spec:       corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone, IPFamilies: ipFamilies},
+		}
+	}
+	ipv4Slice := newEndpointSlice(discoveryv1.AddressTypeIPv4, map[string]string{
+		"mycluster-proxy-0": "10.0.0.1", "mycluster-proxy-1": "10.0.0.2", "mycluster-proxy-2": "10.0.0.3",
+	})
+	ipv6Slice := newEndpointSlice(discoveryv1.AddressTypeIPv6, map[string]string{
+		"mycluster-proxy-0": "fd00::1", "mycluster-proxy-1": "fd00::2", "mycluster-proxy-2": "fd00::3",
+	})
+
+	testCases := []struct {
+		name      string
+		objects   []client.Object
+		format    string
+		preferred corev1.IPFamily
+		expected  string
+	}{
+		{
+			name:     "IPv4 only",
+			objects:  []client.Object{newService(corev1.IPv4Protocol), ipv4Slice},
+			format:   "$(POD_IP):3306",
+			expected: "10.0.0.1:3306,10.0.0.2:3306,10.0.0.3:3306",
+		},
+		{
+			name:     "IPv6 only",
+			objects:  []client.Object{newService(corev1.IPv6Protocol), ipv6Slice},
+			format:   "$(POD_IP):3306",
+			expected: "[fd00::1]:3306,[fd00::2]:3306,[fd00::3]:3306",
+		},
+		{
+			name:      "IPv6 only and IPv4 is preferred",
+			objects:   []client.Object{newService(corev1.IPv6Protocol), ipv6Slice},
+			format:    "$(POD_IP)",
+			preferred: corev1.IPv4Protocol,
+			expected:  "fd00::1,fd00::2,fd00::3",
+		},
+		{
+			name:     "dual-stack and the primary family of the service is preferred",
+			objects:  []client.Object{newService(corev1.IPv6Protocol, corev1.IPv4Protocol), ipv4Slice, ipv6Slice},
+			format:   "$(POD_NAME)=$(POD_IP):3306",
+			expected: "mycluster-proxy-0=[fd00::1]:3306,mycluster-proxy-1=[fd00::2]:3306,mycluster-proxy-2=[fd00::3]:3306",
+		},
+		{
+			name:      "dual-stack and IPv4 is preferred",
+			objects:   []client.Object{newService(corev1.IPv6Protocol, corev1.IPv4Protocol), ipv4Slice, ipv6Slice},
+			format:    "$(POD_IP):3306",
+			preferred: corev1.IPv4Protocol,
+			expected:  "10.0.0.1:3306,10.0.0.2:3306,10.0.0.3:3306",
+		},
+		{
+			name: "the pods without IP are skipped",
+			objects: []client.Object{newEndpointSlice(discoveryv1.AddressTypeIPv4, map[string]string{
+				"mycluster-proxy-1": "10.0.0.2",
+			})},
+			format:   "$(POD_IP)",
+			expected: "10.0.0.2",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.objects...).Build()
+			valueFrom := &appsv1alpha1.ComponentValueFrom{
+				Type:              appsv1alpha1.FromHeadlessServiceRef,
+				Format:            tc.format,
+				PreferredIPFamily: tc.preferred,
+			}
+			value, err := resolveHeadlessServiceFieldRef(context.Background(), cli, valueFrom, cluster, components, componentDef)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, value)
+			}
+		})
+	}
+}
+
+func TestBuildComponentRefWithMixedFailures(t *testing.T) {
+	const (
+		mysqlCompDefName    = "mysql-def"
+		referredCompDefName = "maxscale-def"
+		referredCompName    = "maxscale"
+	)
+	clusterDef := testapps.NewClusterDefFactory("test-clusterdef").
+		AddComponentDef(testapps.StatefulMySQLComponent, mysqlCompDefName).
+		AddComponentDef(testapps.StatefulMySQLComponent, referredCompDefName).
+		GetObject()
+	cluster := testapps.NewClusterFactory("default", "test-cluster", clusterDef.Name, "").
+		AddComponent("mysql", mysqlCompDefName).
+		AddComponent(referredCompName, referredCompDefName).
+		GetObject()
+	newClusterCompDef := func(failurePolicy appsv1alpha1.FailurePolicyType) *appsv1alpha1.ClusterComponentDefinition {
+		fieldRef := func(fieldPath string) *appsv1alpha1.ComponentValueFrom {
+			return &appsv1alpha1.ComponentValueFrom{Type: appsv1alpha1.FromFieldRef, FieldPath: fieldPath}
+		}
+		return &appsv1alpha1.ClusterComponentDefinition{
+			Name: mysqlCompDefName,
+			ComponentDefRef: []appsv1alpha1.ComponentDefRef{
+				{
+					ComponentDefName: referredCompDefName,
+					FailurePolicy:    failurePolicy,
+					ComponentRefEnvs: []appsv1alpha1.ComponentRefEnv{
+						{Name: "PLAIN", Value: "plain"},
+						{Name: "COMP_NAME", ValueFrom: fieldRef("$.components[0].name")},
+						{Name: "WITH_DEFAULT", ValueFrom: fieldRef("$.invalidField.name"), Default: "fallback"},
+						{Name: "WITHOUT_DEFAULT", ValueFrom: fieldRef("$.invalidField.port")},
+						{Name: "REFERRING", Value: "$(COMP_NAME)-suffix"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("ignore", func(t *testing.T) {
+		synthesizedComp := &SynthesizedComponent{}
+		if err := buildComponentRef(context.Background(), nil, clusterDef, cluster,
+			newClusterCompDef(appsv1alpha1.FailurePolicyIgnore), synthesizedComp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectedEnvs := []corev1.EnvVar{
+			{Name: "PLAIN", Value: "plain"},
+			{Name: "COMP_NAME", Value: referredCompName},
+			{Name: "WITH_DEFAULT", Value: "fallback"},
+			{Name: "WITHOUT_DEFAULT", Value: ""},
+			{Name: "REFERRING", Value: referredCompName + "-suffix"},
+		}
+		if !reflect.DeepEqual(expectedEnvs, synthesizedComp.ComponentRefEnvs) {
+			t.Errorf("expected envs %v, got %v", expectedEnvs, synthesizedComp.ComponentRefEnvs)
+		}
+		var ignored []string
+		for _, result := range synthesizedComp.IgnoredComponentRefEnvs {
+			if result.Err == nil {
+				t.Errorf("expected the error of the ignored env %s", result.Name)
+			}
+			ignored = append(ignored, result.Name)
+		}
+		if !reflect.DeepEqual([]string{"WITH_DEFAULT", "WITHOUT_DEFAULT"}, ignored) {
+			t.Errorf("unexpected ignored envs: %v", ignored)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		synthesizedComp := &SynthesizedComponent{}
+		err := buildComponentRef(context.Background(), nil, clusterDef, cluster,
+			newClusterCompDef(appsv1alpha1.FailurePolicyFail), synthesizedComp)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		// all failed envs are reported, not only the first one.
+		for _, name := range []string{"WITH_DEFAULT", "WITHOUT_DEFAULT"} {
+			if !strings.Contains(err.Error(), "env "+name) {
+				t.Errorf("expected the error of env %s, got %v", name, err)
+			}
+		}
+		if strings.Contains(err.Error(), "env COMP_NAME") {
+			t.Errorf("unexpected error of the resolved env: %v", err)
+		}
+		if len(synthesizedComp.IgnoredComponentRefEnvs) != 0 {
+			t.Errorf("unexpected ignored envs: %v", synthesizedComp.IgnoredComponentRefEnvs)
+		}
+	})
+}
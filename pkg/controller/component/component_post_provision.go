@@ -22,17 +22,20 @@ package component
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	cfgutil "github.com/apecloud/kubeblocks/pkg/configuration/util"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
@@ -50,6 +53,9 @@ const (
 	kbCompPostStartDoneKeyPattern = "kubeblocks.io/%s-poststart-done"
 	// kbCompPostProvisionDoneKey is used to mark the component postProvision job is done
 	kbCompPostProvisionDoneKey = "kubeblocks.io/post-provision-done"
+	// kbCompPostStartHashKey records the hash of the post-start action executed last time, it is used to
+	// re-execute the action when its definition changes.
+	kbCompPostStartHashKey = "kubeblocks.io/post-start-hash"
 
 	kbPostProvisionClusterPodNameList     = "KB_CLUSTER_POD_NAME_LIST"
 	kbPostProvisionClusterPodIPList       = "KB_CLUSTER_POD_IP_LIST"
@@ -70,7 +76,12 @@ func ReconcileCompPostProvision(ctx context.Context,
 	comp *appsv1alpha1.Component,
 	synthesizeComp *SynthesizedComponent,
 	dag *graph.DAG) error {
-	needPostProvision, err := needDoPostProvision(ctx, cli, cluster, comp, synthesizeComp)
+	postStartHash, err := getPostStartHash(ctx, cli, synthesizeComp)
+	if err != nil {
+		return err
+	}
+
+	needPostProvision, err := needDoPostProvision(ctx, cli, cluster, comp, synthesizeComp, postStartHash)
 	if err != nil {
 		return err
 	}
@@ -78,7 +89,7 @@ func ReconcileCompPostProvision(ctx context.Context,
 		return nil
 	}
 
-	job, err := createPostProvisionJobIfNotExist(ctx, cli, cluster, comp, synthesizeComp)
+	job, err := createPostProvisionJobIfNotExist(ctx, cli, cluster, comp, synthesizeComp, postStartHash)
 	if err != nil {
 		return err
 	}
@@ -91,14 +102,26 @@ func ReconcileCompPostProvision(ctx context.Context,
 		if intctrlutil.IsTargetError(err, intctrlutil.ErrorWaitCacheRefresh) {
 			return nil
 		}
+		// the failure of the re-executed post-start action is reported by the condition, and does not block the component.
+		if errors.Is(err, ErrJobFailed) && checkPostProvisionDoneAnnotationExist(*cluster, *comp, *synthesizeComp) {
+			msg := fmt.Sprintf("post-start job %s failed", job.Name)
+			if termMsg, _ := GetJobTerminationMessage(ctx, cli, cluster, job.Name); termMsg != "" {
+				msg = fmt.Sprintf("%s: %s", msg, termMsg)
+			}
+			setPostStartCondition(comp, errors.New(msg))
+			return nil
+		}
 		return err
 	}
 
 	// job executed successfully, add the annotation to indicate that the postProvision has been executed and delete the job
 	compOrig := comp.DeepCopy()
-	if err := setPostProvisionDoneAnnotation(cli, comp, dag); err != nil {
+	if err := setPostProvisionDoneAnnotation(cli, comp, postStartHash, dag); err != nil {
 		return err
 	}
+	if postStartHash != "" {
+		setPostStartCondition(comp, nil)
+	}
 
 	// clean up the postProvision job
 	if err := cleanPostProvisionJob(ctx, cli, cluster, *compOrig, *synthesizeComp, job.Name); err != nil {
@@ -108,8 +131,8 @@ func ReconcileCompPostProvision(ctx context.Context,
 	return nil
 }
 
-func needDoPostProvision(ctx context.Context, cli client.Client,
-	cluster *appsv1alpha1.Cluster, comp *appsv1alpha1.Component, synthesizeComp *SynthesizedComponent) (bool, error) {
+func needDoPostProvision(ctx context.Context, cli client.Client, cluster *appsv1alpha1.Cluster,
+	comp *appsv1alpha1.Component, synthesizeComp *SynthesizedComponent, postStartHash string) (bool, error) {
 	// if the component does not have a custom postProvision, skip it
 	if !checkPostProvisionAction(synthesizeComp) {
 		return false, nil
@@ -143,18 +166,55 @@ func needDoPostProvision(ctx context.Context, cli client.Client,
 	jobExist := checkPostProvisionJobExist(ctx, cli, cluster, genPostProvisionJobName(cluster.Name, synthesizeComp.Name))
 	finishAnnotationExist := checkPostProvisionDoneAnnotationExist(*cluster, *comp, *synthesizeComp)
 	if finishAnnotationExist && !jobExist {
-		// if the annotation has been set and the job does not exist, it means that the postProvision has finished, so skip it
-		return false, nil
+		// if the annotation has been set and the job does not exist, it means that the postProvision has finished,
+		// skip it unless the post-start action has changed since the last execution.
+		return postStartHash != "" && comp.Annotations[kbCompPostStartHashKey] != postStartHash, nil
 	}
 	return true, nil
 }
 
+// getPostStartHash computes the hash of the post-start action, including the image, command, args, env and
+// the contents of the referenced scripts. It returns empty if the action is not re-executed on change.
+func getPostStartHash(ctx context.Context, cli client.Client, synthesizeComp *SynthesizedComponent) (string, error) {
+	if !checkPostProvisionAction(synthesizeComp) || synthesizeComp.PostStartSpec == nil ||
+		synthesizeComp.PostStartSpec.PostStartPolicy != appsv1alpha1.PostStartPolicyOnChange {
+		return "", nil
+	}
+
+	selected := func(name string) bool {
+		selectors := synthesizeComp.PostStartSpec.ScriptSpecSelectors
+		return len(selectors) == 0 || slices.ContainsFunc(selectors, func(s appsv1alpha1.ScriptSpecSelector) bool {
+			return s.Name == name
+		})
+	}
+	scripts := make(map[string]map[string]string)
+	for _, tpl := range synthesizeComp.ScriptTemplates {
+		if !selected(tpl.Name) {
+			continue
+		}
+		cm := &corev1.ConfigMap{}
+		if err := cli.Get(ctx, types.NamespacedName{Namespace: tpl.Namespace, Name: tpl.TemplateRef}, cm); err != nil {
+			return "", err
+		}
+		scripts[tpl.Name] = cm.Data
+	}
+
+	action := synthesizeComp.LifecycleActions.PostProvision.CustomHandler
+	return cfgutil.ComputeHash(struct {
+		Image   string
+		Exec    *appsv1alpha1.ExecAction
+		Env     []corev1.EnvVar
+		Scripts map[string]map[string]string
+	}{action.Image, action.Exec, action.Env, scripts})
+}
+
 // createPostProvisionJobIfNotExist creates a job to execute component-level postProvision command, each component only has a corresponding job.
 func createPostProvisionJobIfNotExist(ctx context.Context,
 	cli client.Client,
 	cluster *appsv1alpha1.Cluster,
 	comp *appsv1alpha1.Component,
-	synthesizeComp *SynthesizedComponent) (*batchv1.Job, error) {
+	synthesizeComp *SynthesizedComponent,
+	postStartHash string) (*batchv1.Job, error) {
 	if !checkPostProvisionAction(synthesizeComp) {
 		return nil, nil
 	}
@@ -163,11 +223,21 @@ func createPostProvisionJobIfNotExist(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if postStartHash != "" {
+		postProvisionJob.Annotations = map[string]string{kbCompPostStartHashKey: postStartHash}
+	}
 
 	key := types.NamespacedName{Namespace: cluster.Namespace, Name: postProvisionJob.Name}
 	existJob := &batchv1.Job{}
 	exist, _ := intctrlutil.CheckResourceExists(ctx, cli, key, existJob)
 	if exist {
+		// the job is stale if the post-start action has changed, delete it and wait for the re-execution.
+		if postStartHash != "" && existJob.Annotations[kbCompPostStartHashKey] != postStartHash {
+			if err := cli.Delete(ctx, existJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return nil, err
+			}
+			return nil, intctrlutil.NewDelayedRequeueError(time.Second, "wait for the stale post-start job to be deleted")
+		}
 		return existJob, nil
 	}
 
@@ -251,6 +321,10 @@ func renderPostProvisionCmdJob(ctx context.Context,
 			postProvisionCustomHandler = postProvisionSpec.CustomHandler
 		)
 		volumes, volumeMounts := renderJobPodVolumes()
+		resources, err := intctrlutil.BuildCmdExecutorResources(postProvisionCustomHandler.Resources)
+		if err != nil {
+			return nil, err
+		}
 		jobName := genPostProvisionJobName(cluster.Name, synthesizeComp.Name)
 		job := &batchv1.Job{
 			ObjectMeta: metav1.ObjectMeta{
@@ -277,6 +351,7 @@ func renderPostProvisionCmdJob(ctx context.Context,
 								Env:             envs,
 								EnvFrom:         envFroms,
 								VolumeMounts:    volumeMounts,
+								Resources:       resources,
 							},
 						},
 					},
@@ -317,7 +392,15 @@ func buildPostProvisionEnvs(ctx context.Context,
 
 	if synthesizeComp != nil && synthesizeComp.LifecycleActions != nil &&
 		synthesizeComp.LifecycleActions.PostProvision != nil && synthesizeComp.LifecycleActions.PostProvision.CustomHandler != nil {
-		workloadEnvs = append(workloadEnvs, synthesizeComp.LifecycleActions.PostProvision.CustomHandler.Env...)
+		action := synthesizeComp.LifecycleActions.PostProvision.CustomHandler
+		podNames := make([]string, 0, len(pods))
+		for _, pod := range pods {
+			podNames = append(podNames, pod.Name)
+		}
+		actionEnvs, actionEnvFroms := ReplaceCmdExecutorEnvVars(GetReplacementMapForCmdExecutor(cluster.Name, synthesizeComp.Name, podNames),
+			action.Env, action.EnvFrom)
+		workloadEnvs = append(workloadEnvs, actionEnvs...)
+		workloadEnvFroms = append(workloadEnvFroms, actionEnvFroms...)
 	}
 
 	if tplPod != nil && len(tplPod.Spec.Containers) > 0 {
@@ -472,25 +555,47 @@ func getPostProvisionCmdJobLabel(clusterName, componentName string) map[string]s
 	}
 }
 
-// setPostProvisionDoneAnnotation sets the postProvision done annotation to the component object.
+// setPostProvisionDoneAnnotation sets the postProvision done annotation and the post-start hash annotation to the component object.
 func setPostProvisionDoneAnnotation(cli client.Client,
 	comp *appsv1alpha1.Component,
+	postStartHash string,
 	dag *graph.DAG) error {
 	graphCli := model.NewGraphClient(cli)
 	if comp.Annotations == nil {
 		comp.Annotations = make(map[string]string)
 	}
-	_, ok := comp.Annotations[kbCompPostProvisionDoneKey]
-	if ok {
+	_, done := comp.Annotations[kbCompPostProvisionDoneKey]
+	hashChanged := postStartHash != "" && comp.Annotations[kbCompPostStartHashKey] != postStartHash
+	if done && !hashChanged {
 		return nil
 	}
 	compObj := comp.DeepCopy()
-	timeStr := time.Now().Format(time.RFC3339Nano)
-	comp.Annotations[kbCompPostProvisionDoneKey] = timeStr
+	if !done {
+		comp.Annotations[kbCompPostProvisionDoneKey] = time.Now().Format(time.RFC3339Nano)
+	}
+	if hashChanged {
+		comp.Annotations[kbCompPostStartHashKey] = postStartHash
+	}
 	graphCli.Update(dag, compObj, comp, &model.ReplaceIfExistingOption{})
 	return nil
 }
 
+// setPostStartCondition records the result of the post-start action in the component conditions.
+func setPostStartCondition(comp *appsv1alpha1.Component, err error) {
+	cond := metav1.Condition{
+		Type:               appsv1alpha1.ConditionTypePostStartSucceeded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: comp.Generation,
+		Reason:             appsv1alpha1.ReasonPostStartSucceed,
+	}
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = appsv1alpha1.ReasonPostStartFailed
+		cond.Message = err.Error()
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, cond)
+}
+
 func cleanPostProvisionJob(ctx context.Context,
 	cli client.Client,
 	cluster *appsv1alpha1.Cluster,
@@ -26,9 +26,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
@@ -146,7 +150,7 @@ var _ = Describe("Component PostProvision Test", func() {
 				},
 			}
 			synthesizeComp.LifecycleActions.PostProvision = &postProvision
-			need, err := needDoPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp)
+			need, err := needDoPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, "")
 			Expect(err).Should(Succeed())
 			Expect(need).Should(BeFalse())
 			err = ReconcileCompPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, dag)
@@ -154,7 +158,7 @@ var _ = Describe("Component PostProvision Test", func() {
 
 			By("mock component status ready, should do postProvision action")
 			comp.Status.Phase = appsv1alpha1.RunningClusterCompPhase
-			need, err = needDoPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp)
+			need, err = needDoPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, "")
 			Expect(err).Should(Succeed())
 			Expect(need).Should(BeTrue())
 			err = ReconcileCompPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, dag)
@@ -224,6 +228,91 @@ var _ = Describe("Component PostProvision Test", func() {
 			Expect(clusterPodIPListExist).Should(BeTrue())
 			Expect(clusterPodHostNameListExist).Should(BeTrue())
 			Expect(clusterPodHostIPListExist).Should(BeTrue())
+
+			By("check the built-in variables in the envs of postProvision action are resolved")
+			postProvision.CustomHandler.Env = []corev1.EnvVar{{Name: "ENDPOINT_$(KB_COMP_NAME)", Value: "$(KB_CLUSTER_NAME):$(KB_POD_LIST)"}}
+			postProvision.CustomHandler.EnvFrom = []corev1.EnvFromSource{{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "$(KB_CLUSTER_NAME)-endpoints"}},
+			}}
+			renderJob, err = renderPostProvisionCmdJob(testCtx.Ctx, testCtx.Cli, cluster, synthesizeComp)
+			Expect(err).Should(Succeed())
+			Expect(renderJob.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(corev1.EnvVar{
+				Name:  "ENDPOINT_" + synthesizeComp.Name,
+				Value: cluster.Name + ":" + pods[0].Name,
+			}))
+			Expect(renderJob.Spec.Template.Spec.Containers[0].EnvFrom).Should(ContainElement(corev1.EnvFromSource{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: cluster.Name + "-endpoints"}},
+			}))
+		})
+
+		It("should re-run the post-start action when it changes", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: ctx,
+				Log: tlog,
+			}
+			synthesizeComp, err := BuildSynthesizedComponentWrapper4Test(reqCtx, testCtx.Cli,
+				clusterDef, clusterVersion, cluster, &cluster.Spec.ComponentSpecs[0])
+			Expect(err).Should(Succeed())
+			defaultPreCondition := appsv1alpha1.ComponentReadyPreConditionType
+			synthesizeComp.LifecycleActions = &appsv1alpha1.ComponentLifecycleActions{
+				PostProvision: &appsv1alpha1.LifecycleActionHandler{
+					CustomHandler: &appsv1alpha1.Action{
+						Image:        constant.KBToolsImage,
+						Exec:         &appsv1alpha1.ExecAction{Command: []string{"echo", "v1"}},
+						PreCondition: &defaultPreCondition,
+					},
+				},
+			}
+
+			By("the post-start action is not re-executed with the Once policy")
+			comp, err := BuildComponent(cluster, &cluster.Spec.ComponentSpecs[0], nil, nil)
+			Expect(err).Should(Succeed())
+			comp.UID = cluster.UID
+			comp.Status.Phase = appsv1alpha1.RunningClusterCompPhase
+			comp.Annotations = map[string]string{kbCompPostProvisionDoneKey: "done", kbCompPostStartHashKey: "legacy"}
+			synthesizeComp.PostStartSpec = &appsv1alpha1.PostStartAction{PostStartPolicy: appsv1alpha1.PostStartPolicyOnce}
+			hash, err := getPostStartHash(testCtx.Ctx, testCtx.Cli, synthesizeComp)
+			Expect(err).Should(Succeed())
+			Expect(hash).Should(BeEmpty())
+			need, err := needDoPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, hash)
+			Expect(err).Should(Succeed())
+			Expect(need).Should(BeFalse())
+
+			By("the post-start action is re-executed with the OnChange policy")
+			synthesizeComp.PostStartSpec.PostStartPolicy = appsv1alpha1.PostStartPolicyOnChange
+			hash, err = getPostStartHash(testCtx.Ctx, testCtx.Cli, synthesizeComp)
+			Expect(err).Should(Succeed())
+			Expect(hash).ShouldNot(BeEmpty())
+			need, err = needDoPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, hash)
+			Expect(err).Should(Succeed())
+			Expect(need).Should(BeTrue())
+
+			for _, pod := range mockPodsForTest(cluster, 1) {
+				Expect(client.IgnoreAlreadyExists(testCtx.Cli.Create(testCtx.Ctx, &pod))).Should(Succeed())
+			}
+			dag := graph.NewDAG()
+			dag.AddVertex(&model.ObjectVertex{Obj: cluster, Action: model.ActionUpdatePtr()})
+			Expect(ReconcileCompPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, dag)).Should(Succeed())
+			jobKey := types.NamespacedName{Namespace: cluster.Namespace, Name: genPostProvisionJobName(cluster.Name, synthesizeComp.Name)}
+			job := &batchv1.Job{}
+			Expect(testCtx.Cli.Get(testCtx.Ctx, jobKey, job)).Should(Succeed())
+			Expect(job.Annotations[kbCompPostStartHashKey]).Should(Equal(hash))
+
+			By("the failure of the re-execution is reported by the condition")
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+			Expect(testCtx.Cli.Status().Update(testCtx.Ctx, job)).Should(Succeed())
+			Eventually(func(g Gomega) {
+				g.Expect(ReconcileCompPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, dag)).Should(Succeed())
+				cond := meta.FindStatusCondition(comp.Status.Conditions, appsv1alpha1.ConditionTypePostStartSucceeded)
+				g.Expect(cond).ShouldNot(BeNil())
+				g.Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+			}).Should(Succeed())
+			Expect(comp.Annotations[kbCompPostStartHashKey]).Should(Equal("legacy"))
+
+			By("the stale job is deleted after the post-start action changes again")
+			synthesizeComp.LifecycleActions.PostProvision.CustomHandler.Exec.Command = []string{"echo", "v2"}
+			err = ReconcileCompPostProvision(testCtx.Ctx, testCtx.Cli, cluster, comp, synthesizeComp, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
 		})
 	})
 })
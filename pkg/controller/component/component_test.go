@@ -21,11 +21,13 @@ package component
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -443,3 +445,117 @@ func TestGetConfigSpecByName(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildLabelsAndAnnotations(t *testing.T) {
+	compDef := &appsv1alpha1.ComponentDefinition{
+		Spec: appsv1alpha1.ComponentDefinitionSpec{
+			Labels:      map[string]string{"app": "$(KB_CLUSTER_NAME)-$(KB_COMP_NAME)"},
+			Annotations: map[string]string{"mesh": "$(KB_CLUSTER_COMP_NAME)"},
+		},
+	}
+	comp := &appsv1alpha1.Component{}
+	synthesizeComp := &SynthesizedComponent{ClusterName: "mycluster", Name: "mysql"}
+	if err := buildLabelsAndAnnotations(compDef, comp, synthesizeComp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if synthesizeComp.Labels["app"] != "mycluster-mysql" {
+		t.Errorf("expected the label value to be substituted, got: %s", synthesizeComp.Labels["app"])
+	}
+	if synthesizeComp.Annotations["mesh"] != "mycluster-mysql" {
+		t.Errorf("expected the annotation value to be substituted, got: %s", synthesizeComp.Annotations["mesh"])
+	}
+
+	// the label value exceeds 63 characters after substitution
+	synthesizeComp = &SynthesizedComponent{ClusterName: strings.Repeat("a", 60), Name: "mysql"}
+	if err := buildLabelsAndAnnotations(compDef, comp, synthesizeComp); err == nil {
+		t.Error("expected error for the invalid label value")
+	}
+}
+
+func TestBuildBackwardCompatibleUpdateStrategy(t *testing.T) {
+	clusterDef := &appsv1alpha1.ClusterDefinition{
+		Spec: appsv1alpha1.ClusterDefinitionSpec{
+			ComponentDefs: []appsv1alpha1.ClusterComponentDefinition{
+				{Name: "stateful", WorkloadType: appsv1alpha1.Stateful},
+				{Name: "consensus", WorkloadType: appsv1alpha1.Consensus, ConsensusSpec: appsv1alpha1.NewConsensusSetSpec()},
+			},
+		},
+	}
+	cluster := &appsv1alpha1.Cluster{}
+	parallel := appsv1alpha1.ParallelStrategy
+	build := func(compDefRef string, strategy *appsv1alpha1.UpdateStrategy) *SynthesizedComponent {
+		serial := appsv1alpha1.SerialStrategy
+		synthesizeComp := &SynthesizedComponent{PodSpec: &corev1.PodSpec{}}
+		if compDefRef == "consensus" {
+			synthesizeComp.UpdateStrategy = &serial
+		}
+		clusterCompSpec := &appsv1alpha1.ClusterComponentSpec{Name: compDefRef, ComponentDefRef: compDefRef, UpdateStrategy: strategy}
+		if err := buildBackwardCompatibleFields(intctrlutil.RequestCtx{}, nil, clusterDef, nil, cluster, clusterCompSpec, synthesizeComp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return synthesizeComp
+	}
+
+	// the pod management policy of Stateful workloads follows the update strategy
+	if policy := *build("stateful", nil).PodManagementPolicy; policy != appsv1.OrderedReadyPodManagement {
+		t.Errorf("expected the pod management policy of the definition, got: %s", policy)
+	}
+	if policy := *build("stateful", &parallel).PodManagementPolicy; policy != appsv1.ParallelPodManagement {
+		t.Errorf("expected the pod management policy to be overridden, got: %s", policy)
+	}
+	if build("stateful", &parallel).UpdateStrategy != nil {
+		t.Error("expected no member update strategy for Stateful workloads")
+	}
+
+	// the member update strategy of Consensus workloads follows the update strategy
+	if strategy := *build("consensus", nil).UpdateStrategy; strategy != appsv1alpha1.SerialStrategy {
+		t.Errorf("expected the update strategy of the definition, got: %s", strategy)
+	}
+	if strategy := *build("consensus", &parallel).UpdateStrategy; strategy != appsv1alpha1.ParallelStrategy {
+		t.Errorf("expected the update strategy to be overridden, got: %s", strategy)
+	}
+	if clusterDef.Spec.ComponentDefs[1].ConsensusSpec.UpdateStrategy != appsv1alpha1.SerialStrategy {
+		t.Error("expected the cluster definition not to be modified")
+	}
+}
+
+func TestOverrideSwitchoverSpecAttr(t *testing.T) {
+	switchoverSpec := &appsv1alpha1.SwitchoverSpec{
+		WithCandidate: &appsv1alpha1.SwitchoverAction{
+			CmdExecutorConfig: &appsv1alpha1.CmdExecutorConfig{
+				CommandExecutorEnvItem: appsv1alpha1.CommandExecutorEnvItem{Image: "switchover:v1"},
+			},
+		},
+		PreCheck: &appsv1alpha1.CmdExecutorConfig{
+			CommandExecutorEnvItem: appsv1alpha1.CommandExecutorEnvItem{
+				Image: "precheck:v1",
+				Env:   []corev1.EnvVar{{Name: "CHECK_TIMEOUT", Value: "10"}, {Name: "VERSION", Value: "v1"}},
+			},
+		},
+		PostCheck: &appsv1alpha1.CmdExecutorConfig{},
+	}
+	cvSwitchoverSpec := &appsv1alpha1.SwitchoverShortSpec{
+		CmdExecutorConfig: &appsv1alpha1.CommandExecutorEnvItem{
+			Image: "switchover:v2",
+			Env:   []corev1.EnvVar{{Name: "VERSION", Value: "v2"}, {Name: "ENGINE", Value: "mysql"}},
+		},
+	}
+	overrideSwitchoverSpecAttr(switchoverSpec, cvSwitchoverSpec)
+
+	if switchoverSpec.WithCandidate.CmdExecutorConfig.Image != "switchover:v2" {
+		t.Errorf("expected the switchover image to be overridden, got: %s", switchoverSpec.WithCandidate.CmdExecutorConfig.Image)
+	}
+	if switchoverSpec.PreCheck.Image != "precheck:v1" {
+		t.Errorf("expected the preCheck image to be kept, got: %s", switchoverSpec.PreCheck.Image)
+	}
+	expectedEnvs := []corev1.EnvVar{{Name: "CHECK_TIMEOUT", Value: "10"}, {Name: "VERSION", Value: "v2"}, {Name: "ENGINE", Value: "mysql"}}
+	if !reflect.DeepEqual(switchoverSpec.PreCheck.Env, expectedEnvs) {
+		t.Errorf("expected the preCheck envs to be merged, got: %v", switchoverSpec.PreCheck.Env)
+	}
+	if switchoverSpec.PostCheck.Image != "switchover:v2" {
+		t.Errorf("expected the postCheck without image to use the cluster version image, got: %s", switchoverSpec.PostCheck.Image)
+	}
+	if !reflect.DeepEqual(switchoverSpec.PostCheck.Env, cvSwitchoverSpec.CmdExecutorConfig.Env) {
+		t.Errorf("expected the postCheck envs to be the cluster version envs, got: %v", switchoverSpec.PostCheck.Env)
+	}
+}
@@ -22,8 +22,10 @@ package component
 import (
 	"context"
 	"errors"
+	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,6 +37,9 @@ const (
 	KBJobTTLSecondsAfterFinished = 5
 )
 
+// ErrJobFailed is returned by CheckJobSucceed if the job failed.
+var ErrJobFailed = errors.New("job failed, pls check")
+
 // GetJobWithLabels gets the job list with the specified labels.
 func GetJobWithLabels(ctx context.Context,
 	cli client.Client,
@@ -109,10 +114,30 @@ func CheckJobSucceed(ctx context.Context,
 		case batchv1.JobComplete:
 			return nil
 		case batchv1.JobFailed:
-			return errors.New("job failed, pls check")
+			return ErrJobFailed
 		default:
 			return intctrlutil.NewErrorf(intctrlutil.ErrorWaitCacheRefresh, "requeue to waiting for job %s finished.", key.Name)
 		}
 	}
 	return intctrlutil.NewErrorf(intctrlutil.ErrorWaitCacheRefresh, "requeue to waiting for job %s finished.", key.Name)
 }
+
+// GetJobTerminationMessage gets the termination message of the containers of the job pods.
+func GetJobTerminationMessage(ctx context.Context,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	jobName string) (string, error) {
+	podList := &corev1.PodList{}
+	if err := cli.List(ctx, podList, client.InNamespace(cluster.Namespace), client.MatchingLabels{"job-name": jobName}); err != nil {
+		return "", err
+	}
+	var messages []string
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.Message != "" {
+				messages = append(messages, strings.TrimSpace(status.State.Terminated.Message))
+			}
+		}
+	}
+	return strings.Join(messages, "; "), nil
+}
@@ -262,6 +262,11 @@ func buildLorryEnvs(container *corev1.Container, synthesizeComp *SynthesizedComp
 		envs = append(envs, buildEnv4VolumeProtection(*synthesizeComp.VolumeProtection))
 	}
 
+	// pass the HTTP request of the role probe to lorry container through env.
+	if httpAction := getRoleProbeHTTPAction(synthesizeComp); httpAction != nil {
+		envs = append(envs, buildEnv4RoleProbeHTTPAction(synthesizeComp, *httpAction))
+	}
+
 	container.Env = append(container.Env, envs...)
 }
 
@@ -358,6 +363,39 @@ func buildEnv4VolumeProtection(spec appsv1alpha1.VolumeProtectionSpec) corev1.En
 	}
 }
 
+func getRoleProbeHTTPAction(synthesizeComp *SynthesizedComponent) *appsv1alpha1.HTTPAction {
+	if synthesizeComp.LifecycleActions == nil || synthesizeComp.LifecycleActions.RoleProbe == nil {
+		return nil
+	}
+	customHandler := synthesizeComp.LifecycleActions.RoleProbe.CustomHandler
+	if customHandler == nil || customHandler.Exec != nil {
+		return nil
+	}
+	return customHandler.HTTP
+}
+
+// buildEnv4RoleProbeHTTPAction builds the env of the role probe HTTP request, the named port is resolved
+// to the port number of the containers, as lorry can not look up the ports of the other containers.
+func buildEnv4RoleProbeHTTPAction(synthesizeComp *SynthesizedComponent, action appsv1alpha1.HTTPAction) corev1.EnvVar {
+	if action.Port.Type == intstr.String {
+		for _, c := range synthesizeComp.PodSpec.Containers {
+			for _, port := range c.Ports {
+				if port.Name == action.Port.StrVal {
+					action.Port = intstr.FromInt(int(port.ContainerPort))
+				}
+			}
+		}
+	}
+	value, err := json.Marshal(action)
+	if err != nil {
+		panic(fmt.Sprintf("marshal role probe http action error: %s", err.Error()))
+	}
+	return corev1.EnvVar{
+		Name:  constant.KBEnvRoleProbeHTTPAction,
+		Value: string(value),
+	}
+}
+
 // getBuiltinActionHandler gets the built-in handler.
 // The BuiltinActionHandler within the same synthesizeComp LifecycleActions should be consistent, we can take any one of them.
 func getBuiltinActionHandler(synthesizeComp *SynthesizedComponent) appsv1alpha1.BuiltinActionHandlerType {
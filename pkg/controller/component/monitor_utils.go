@@ -24,8 +24,22 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
+// IsServiceMonitorAPIEnabled checks whether the ServiceMonitor CRD of prometheus-operator is installed,
+// which is detected when the manager starts.
+func IsServiceMonitorAPIEnabled() bool {
+	return viper.GetBool(constant.CfgKeyServiceMonitorAPIEnabled)
+}
+
+// UseServiceMonitor checks whether the metrics of the component are scraped by the ServiceMonitor,
+// instead of by the monitor annotations of the headless service.
+func (r *MonitorConfig) UseServiceMonitor() bool {
+	return r != nil && r.Enable && !r.BuiltIn && r.ServiceMonitor && IsServiceMonitorAPIEnabled()
+}
+
 func buildMonitorConfigLegacy(
 	compDef *appsv1alpha1.ClusterComponentDefinition,
 	compSpec *appsv1alpha1.ClusterComponentSpec,
@@ -49,10 +63,13 @@ func buildMonitorConfig(
 			return
 		}
 		synthesizeComp.Monitor = &MonitorConfig{
-			Enable:     true,
-			BuiltIn:    false,
-			ScrapePath: monitorConfig.Exporter.ScrapePath,
-			ScrapePort: monitorConfig.Exporter.ScrapePort.IntVal,
+			Enable:         true,
+			BuiltIn:        false,
+			ScrapePath:     monitorConfig.Exporter.ScrapePath,
+			ScrapePort:     monitorConfig.Exporter.ScrapePort.IntVal,
+			ScrapeScheme:   monitorConfig.Exporter.GetScrapeScheme(),
+			TLSConfig:      monitorConfig.Exporter.TLSConfig,
+			ServiceMonitor: monitorConfig.Exporter.ServiceMonitor,
 		}
 
 		if monitorConfig.Exporter.ScrapePort.Type == intstr.String {
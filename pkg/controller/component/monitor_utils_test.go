@@ -22,6 +22,7 @@ package component
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -68,6 +69,27 @@ var _ = Describe("monitor_utils", func() {
 			Expect(monitorConfig.ScrapePath).To(Equal("/metrics"))
 		})
 
+		It("should propagate the scrape scheme and TLS config of ExporterConfig", func() {
+			clusterCompSpec.Monitor = true
+			clusterCompDef.Monitor.BuiltIn = false
+			buildMonitorConfigLegacy(clusterCompDef, clusterCompSpec, component)
+			Expect(component.Monitor.ScrapeScheme).To(Equal(appsv1alpha1.HTTPScrapeScheme))
+			Expect(component.Monitor.TLSConfig).To(BeNil())
+
+			tlsConfig := &appsv1alpha1.ExporterTLSConfig{
+				InsecureSkipVerify: true,
+				CASecretRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "exporter-ca"},
+					Key:                  "ca.crt",
+				},
+			}
+			clusterCompDef.Monitor.Exporter.ScrapeScheme = appsv1alpha1.HTTPSScrapeScheme
+			clusterCompDef.Monitor.Exporter.TLSConfig = tlsConfig
+			buildMonitorConfigLegacy(clusterCompDef, clusterCompSpec, component)
+			Expect(component.Monitor.ScrapeScheme).To(Equal(appsv1alpha1.HTTPSScrapeScheme))
+			Expect(component.Monitor.TLSConfig).To(Equal(tlsConfig))
+		})
+
 		It("should disable monitor if ClusterComponentDefinition.Monitor.BuiltIn is false and lack of ExporterConfig", func() {
 			clusterCompSpec.Monitor = true
 			clusterCompDef.Monitor.BuiltIn = false
@@ -20,11 +20,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package component
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -104,3 +109,25 @@ func IsComponentPodsWithLatestRevision(ctx context.Context, cli client.Reader,
 	}
 	return true, nil
 }
+
+// ApplyPodSpecPatch applies the strategic merge patch to the podSpec, and returns the patched podSpec.
+func ApplyPodSpecPatch(podSpec *corev1.PodSpec, patch *runtime.RawExtension) (*corev1.PodSpec, error) {
+	if patch == nil || len(patch.Raw) == 0 {
+		return podSpec, nil
+	}
+	original, err := json.Marshal(podSpec)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch.Raw, corev1.PodSpec{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply the podSpecPatch: %s", err.Error())
+	}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	result := &corev1.PodSpec{}
+	if err = decoder.Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to apply the podSpecPatch: %s", err.Error())
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestApplyPodSpecPatch(t *testing.T) {
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:         "mysql",
+				Image:        "mysql:8.0.33",
+				Args:         []string{"--max-connections=100"},
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			},
+			{Name: "lorry", Image: "lorry"},
+		},
+	}
+
+	patched, err := ApplyPodSpecPatch(podSpec, nil)
+	if err != nil || patched != podSpec {
+		t.Errorf("expect the podSpec is not changed without patch, got: %v", err)
+	}
+
+	patch := &runtime.RawExtension{Raw: []byte(`{"containers":[{"name":"mysql","args":["--max-connections=1000"]},{"name":"sidecar","image":"busybox"}]}`)}
+	patched, err = ApplyPodSpecPatch(podSpec, patch)
+	if err != nil {
+		t.Fatalf("expect the patch applied, got: %v", err)
+	}
+	if len(patched.Containers) != 3 {
+		t.Fatalf("expect the sidecar is added, got: %v", patched.Containers)
+	}
+	mysql := patched.Containers[0]
+	if mysql.Args[0] != "--max-connections=1000" || mysql.Image != "mysql:8.0.33" || len(mysql.VolumeMounts) != 1 {
+		t.Errorf("expect the args patched and the injected volume mounts kept, got: %v", mysql)
+	}
+	if podSpec.Containers[0].Args[0] != "--max-connections=100" {
+		t.Error("expect the original podSpec is not changed")
+	}
+
+	patch = &runtime.RawExtension{Raw: []byte(`{"containers":[{"name":"mysql","unknownField":true}]}`)}
+	if _, err = ApplyPodSpecPatch(podSpec, patch); err == nil || !strings.Contains(err.Error(), "unknownField") {
+		t.Errorf("expect the unknown field rejected, got: %v", err)
+	}
+
+	patch = &runtime.RawExtension{Raw: []byte(`{"containers":{"name":"mysql"}}`)}
+	if _, err = ApplyPodSpecPatch(podSpec, patch); err == nil || !strings.Contains(err.Error(), "failed to apply the podSpecPatch") {
+		t.Errorf("expect the invalid patch rejected, got: %v", err)
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
@@ -48,6 +49,7 @@ func BuildRSMFrom(synthesizeComp *SynthesizedComponent, protoRSM *workloads.Repl
 		"memberupdatestrategy":      &rsmMemberUpdateStrategyConvertor{},
 		"podmanagementpolicy":       &rsmPodManagementPolicyConvertor{},
 		"updatestrategy":            &rsmUpdateStrategyConvertor{},
+		"maxunavailable":            &rsmMaxUnavailableConvertor{},
 	}
 	if err := covertObject(convertors, &protoRSM.Spec, synthesizeComp); err != nil {
 		return nil, err
@@ -116,9 +118,36 @@ func (c *rsmUpdateStrategyConvertor) convert(args ...any) (any, error) {
 			Type: appsv1.OnDeleteStatefulSetStrategyType,
 		}, nil
 	}
+	if synthesizedComp.StsUpdateStrategy != nil {
+		return *synthesizedComp.StsUpdateStrategy, nil
+	}
 	return nil, nil
 }
 
+// rsmMaxUnavailableConvertor is an implementation of the convertor interface, used to convert the given object into ReplicatedStateMachine.Spec.MaxUnavailable.
+type rsmMaxUnavailableConvertor struct{}
+
+func (c *rsmMaxUnavailableConvertor) convert(args ...any) (any, error) {
+	synthesizedComp, err := parseRSMConvertorArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	// only the secondaries of the Replication workload are updated with the maxUnavailable,
+	// the others are updated in two halves to keep the quorum of the Consensus workload.
+	memberUpdateStrategy := getMemberUpdateStrategy(synthesizedComp)
+	if synthesizedComp.WorkloadType != appsv1alpha1.Replication ||
+		memberUpdateStrategy == nil || *memberUpdateStrategy != workloads.BestEffortParallelUpdateStrategy {
+		return nil, nil
+	}
+	replicationSpec := &appsv1alpha1.ReplicationSetSpec{MaxUnavailable: synthesizedComp.MaxUnavailable}
+	maxUnavailable, err := replicationSpec.FinalMaxUnavailable(synthesizedComp.Replicas)
+	if err != nil {
+		return nil, err
+	}
+	value := intstr.FromInt(int(maxUnavailable))
+	return &value, nil
+}
+
 // parseRSMConvertorArgs parses the args of rsm convertor.
 func parseRSMConvertorArgs(args ...any) (*SynthesizedComponent, error) {
 	synthesizeComp, ok := args[0].(*SynthesizedComponent)
@@ -23,6 +23,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
+
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	workloadsalpha1 "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 )
@@ -59,5 +61,30 @@ var _ = Describe("Test RSM Convertor", func() {
 			Expect(probe.CustomHandler[0].Command).Should(BeEquivalentTo(command))
 			Expect(probe.CustomHandler[0].Args).Should(BeEquivalentTo(args))
 		})
+
+		It("convert update strategy", func() {
+			convertor := &rsmUpdateStrategyConvertor{}
+			res, err := convertor.convert(synComp)
+			Expect(err).Should(Succeed())
+			Expect(res).Should(BeNil())
+
+			By("the resolved update strategy is applied")
+			partition := int32(1)
+			strategy := appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+			}
+			synComp.StsUpdateStrategy = &strategy
+			res, err = convertor.convert(synComp)
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal(strategy))
+
+			By("the pods are updated on delete if the member update strategy is set")
+			serial := appsv1alpha1.SerialStrategy
+			synComp.UpdateStrategy = &serial
+			res, err = convertor.convert(synComp)
+			Expect(err).Should(Succeed())
+			Expect(res).Should(Equal(appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}))
+		})
 	})
 })
@@ -21,7 +21,7 @@ package component
 
 import (
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"golang.org/x/exp/slices"
@@ -93,10 +93,16 @@ func GenServiceReferences(reqCtx intctrlutil.RequestCtx,
 				}
 			}
 		}
-		// _, exist := serviceReferences[serviceRefDecl.Name]
-		// if !exist {
-		//	 return nil, fmt.Errorf("componentDef %s's serviceRefDeclaration %s has not been defined, please check if there is corresponding service definition and binding in Cluster.spec.componentSpecs[*].serviceRefs", clusterCompDef.Name, serviceRefDecl.Name)
-		// }
+		if _, exist := serviceReferences[serviceRefDecl.Name]; exist {
+			continue
+		}
+		// fall back to the default endpoint if the service reference is not bound.
+		switch {
+		case serviceRefDecl.DefaultEndpoint != nil:
+			serviceReferences[serviceRefDecl.Name] = buildDefaultEndpointServiceRef(namespace, clusterName, serviceRefDecl)
+		case serviceRefDecl.Optional != nil && !*serviceRefDecl.Optional:
+			return nil, fmt.Errorf("componentDef %s's serviceRefDeclaration %s is not bound, please check if there is corresponding binding in Cluster.spec.componentSpecs[*].serviceRefs", compDef.Name, serviceRefDecl.Name)
+		}
 	}
 	if len(serviceReferences) == 0 {
 		return nil, nil
@@ -151,6 +157,33 @@ func handleClusterTypeServiceRef(reqCtx intctrlutil.RequestCtx,
 	return nil
 }
 
+// buildDefaultEndpointServiceRef builds the service descriptor from the default endpoint of the service reference declaration.
+func buildDefaultEndpointServiceRef(namespace, clusterName string, serviceRefDecl appsv1alpha1.ServiceRefDeclaration) *appsv1alpha1.ServiceDescriptor {
+	defaultEndpoint := serviceRefDecl.DefaultEndpoint
+	sdBuilder := builder.NewServiceDescriptorBuilder(namespace, fmt.Sprintf("kbsd-%s-%s-default", clusterName, serviceRefDecl.Name))
+	sdBuilder.SetServiceKind("")
+	sdBuilder.SetServiceVersion("")
+	sdBuilder.SetEndpoint(appsv1alpha1.CredentialVar{Value: defaultEndpoint.Host})
+	if defaultEndpoint.Port != 0 {
+		sdBuilder.SetPort(appsv1alpha1.CredentialVar{Value: strconv.Itoa(int(defaultEndpoint.Port))})
+	}
+	if defaultEndpoint.CredentialSecretRef != nil {
+		secretKeyRef := func(key string) appsv1alpha1.CredentialVar {
+			return appsv1alpha1.CredentialVar{
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: *defaultEndpoint.CredentialSecretRef,
+						Key:                  key,
+					},
+				},
+			}
+		}
+		sdBuilder.SetAuthUsername(secretKeyRef(constant.ServiceDescriptorUsernameKey))
+		sdBuilder.SetAuthPassword(secretKeyRef(constant.ServiceDescriptorPasswordKey))
+	}
+	return sdBuilder.GetObject()
+}
+
 // handleServiceDescriptorTypeServiceRef handles the service reference is provided by external ServiceDescriptor object.
 func handleServiceDescriptorTypeServiceRef(reqCtx intctrlutil.RequestCtx,
 	cli client.Reader,
@@ -159,17 +192,20 @@ func handleServiceDescriptorTypeServiceRef(reqCtx intctrlutil.RequestCtx,
 	serviceRefDecl appsv1alpha1.ServiceRefDeclaration,
 	serviceReferences map[string]*appsv1alpha1.ServiceDescriptor) error {
 	// verify service kind and version
-	verifyServiceKindAndVersion := func(serviceDescriptor appsv1alpha1.ServiceDescriptor, serviceRefDeclSpecs ...appsv1alpha1.ServiceRefDeclarationSpec) bool {
-		for _, serviceRefDeclSpec := range serviceRefDecl.ServiceRefDeclarationSpecs {
+	verifyServiceKindAndVersion := func(serviceDescriptor appsv1alpha1.ServiceDescriptor, serviceRefDeclSpecs ...appsv1alpha1.ServiceRefDeclarationSpec) (bool, error) {
+		for _, serviceRefDeclSpec := range serviceRefDeclSpecs {
 			if getWellKnownServiceKindAliasMapping(serviceRefDeclSpec.ServiceKind) != getWellKnownServiceKindAliasMapping(serviceDescriptor.Spec.ServiceKind) {
 				continue
 			}
-			versionMatch := verifyServiceVersion(serviceDescriptor.Spec.ServiceVersion, serviceRefDeclSpec.ServiceVersion)
+			versionMatch, err := appsv1alpha1.MatchServiceVersion(serviceRefDeclSpec.ServiceVersion, serviceDescriptor.Spec.ServiceVersion)
+			if err != nil {
+				return false, fmt.Errorf("invalid serviceVersion of service reference declaration %s: %s", serviceRefDecl.Name, err.Error())
+			}
 			if versionMatch {
-				return true
+				return true, nil
 			}
 		}
-		return false
+		return false, nil
 	}
 	serviceDescriptor := &appsv1alpha1.ServiceDescriptor{}
 	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Namespace: namespace, Name: serviceRef.ServiceDescriptor}, serviceDescriptor); err != nil {
@@ -178,7 +214,10 @@ func handleServiceDescriptorTypeServiceRef(reqCtx intctrlutil.RequestCtx,
 	if serviceDescriptor.Status.Phase != appsv1alpha1.AvailablePhase {
 		return fmt.Errorf("service descriptor %s status is not available", serviceDescriptor.Name)
 	}
-	match := verifyServiceKindAndVersion(*serviceDescriptor, serviceRefDecl.ServiceRefDeclarationSpecs...)
+	match, err := verifyServiceKindAndVersion(*serviceDescriptor, serviceRefDecl.ServiceRefDeclarationSpecs...)
+	if err != nil {
+		return err
+	}
 	if !match {
 		return fmt.Errorf("service descriptor %s kind or version is not match with service reference declaration %s", serviceDescriptor.Name, serviceRefDecl.Name)
 	}
@@ -186,18 +225,6 @@ func handleServiceDescriptorTypeServiceRef(reqCtx intctrlutil.RequestCtx,
 	return nil
 }
 
-func verifyServiceVersion(serviceDescriptorVersion, serviceRefDeclarationServiceVersion string) bool {
-	isRegex := false
-	regex, err := regexp.Compile(serviceRefDeclarationServiceVersion)
-	if err == nil {
-		isRegex = true
-	}
-	if !isRegex {
-		return serviceDescriptorVersion == serviceRefDeclarationServiceVersion
-	}
-	return regex.MatchString(serviceDescriptorVersion)
-}
-
 func getWellKnownServiceKindAliasMapping(serviceKind string) string {
 	lowerServiceKind := strings.ToLower(serviceKind)
 	switch {
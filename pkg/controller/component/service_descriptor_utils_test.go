@@ -193,7 +193,8 @@ var _ = Describe("generate service descriptor", func() {
 				want: false,
 			}}
 			for _, tt := range tests {
-				match := verifyServiceVersion(tt.fields.serviceDescriptorVersion, tt.fields.serviceRefDeclRegex)
+				match, err := appsv1alpha1.MatchServiceVersion(tt.fields.serviceRefDeclRegex, tt.fields.serviceDescriptorVersion)
+				Expect(err).Should(Succeed())
 				Expect(match).Should(Equal(tt.want))
 			}
 		})
@@ -330,5 +331,59 @@ var _ = Describe("generate service descriptor", func() {
 			Expect(serviceReferences[mysqlServiceRefDeclarationName].Spec.Auth.Password.ValueFrom).ShouldNot(BeNil())
 			Expect(serviceReferences[mysqlServiceRefDeclarationName].Spec.Auth.Password.ValueFrom.SecretKeyRef).ShouldNot(BeNil())
 		})
+
+		It("generate service descriptor for the unbound service reference test", func() {
+			reqCtx := intctrlutil.RequestCtx{
+				Ctx: testCtx.Ctx,
+				Log: log.FromContext(ctx),
+			}
+			optional := func(b bool) *bool { return &b }
+			compDef := &appsv1alpha1.ComponentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: nginxCompDefName},
+				Spec: appsv1alpha1.ComponentDefinitionSpec{
+					ServiceRefDeclarations: []appsv1alpha1.ServiceRefDeclaration{
+						{
+							Name:     redisServiceRefDeclarationName,
+							Optional: optional(true),
+						},
+						{
+							Name:     mysqlServiceRefDeclarationName,
+							Optional: optional(true),
+							DefaultEndpoint: &appsv1alpha1.ServiceRefDefaultEndpoint{
+								Host:                "mysql.default.svc.cluster.local",
+								Port:                3306,
+								CredentialSecretRef: &corev1.LocalObjectReference{Name: secretName},
+							},
+						},
+					},
+				},
+			}
+			comp := &appsv1alpha1.Component{}
+
+			By("the optional service reference is skipped and the default endpoint is used")
+			serviceReferences, err := GenServiceReferences(reqCtx, testCtx.Cli, testCtx.DefaultNamespace, clusterName, compDef, comp)
+			Expect(err).Should(Succeed())
+			Expect(serviceReferences).Should(HaveLen(1))
+			Expect(serviceReferences).ShouldNot(HaveKey(redisServiceRefDeclarationName))
+			sd := serviceReferences[mysqlServiceRefDeclarationName]
+			Expect(sd).ShouldNot(BeNil())
+			Expect(sd.Spec.Endpoint.Value).Should(Equal("mysql.default.svc.cluster.local"))
+			Expect(sd.Spec.Port.Value).Should(Equal("3306"))
+			Expect(sd.Spec.Auth.Username.ValueFrom.SecretKeyRef.Name).Should(Equal(secretName))
+			Expect(sd.Spec.Auth.Username.ValueFrom.SecretKeyRef.Key).Should(Equal(constant.ServiceDescriptorUsernameKey))
+			Expect(sd.Spec.Auth.Password.ValueFrom.SecretKeyRef.Key).Should(Equal(constant.ServiceDescriptorPasswordKey))
+
+			By("the unbound service reference without optional is skipped")
+			compDef.Spec.ServiceRefDeclarations[0].Optional = nil
+			serviceReferences, err = GenServiceReferences(reqCtx, testCtx.Cli, testCtx.DefaultNamespace, clusterName, compDef, comp)
+			Expect(err).Should(Succeed())
+			Expect(serviceReferences).ShouldNot(HaveKey(redisServiceRefDeclarationName))
+
+			By("the required service reference must be bound")
+			compDef.Spec.ServiceRefDeclarations[0].Optional = optional(false)
+			_, err = GenServiceReferences(reqCtx, testCtx.Cli, testCtx.DefaultNamespace, clusterName, compDef, comp)
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("is not bound"))
+		})
 	})
 })
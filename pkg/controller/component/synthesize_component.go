@@ -22,13 +22,14 @@ package component
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
@@ -144,39 +145,41 @@ func buildSynthesizedComponent(reqCtx intctrlutil.RequestCtx,
 	}
 	compDefObj := compDef.DeepCopy()
 	synthesizeComp := &SynthesizedComponent{
-		Namespace:          comp.Namespace,
-		ClusterName:        clusterName,
-		ClusterUID:         clusterUID,
-		Comp2CompDefs:      buildComp2CompDefs(cluster, clusterCompSpec),
-		Name:               compName,
-		FullCompName:       comp.Name,
-		CompDefName:        compDef.Name,
-		ClusterGeneration:  clusterGeneration(cluster, comp),
-		PodSpec:            &compDef.Spec.Runtime,
-		HostNetwork:        compDefObj.Spec.HostNetwork,
-		LogConfigs:         compDefObj.Spec.LogConfigs,
-		ConfigTemplates:    compDefObj.Spec.Configs,
-		ScriptTemplates:    compDefObj.Spec.Scripts,
-		Roles:              compDefObj.Spec.Roles,
-		UpdateStrategy:     compDefObj.Spec.UpdateStrategy,
-		MinReadySeconds:    compDefObj.Spec.MinReadySeconds,
-		PolicyRules:        compDefObj.Spec.PolicyRules,
-		LifecycleActions:   compDefObj.Spec.LifecycleActions,
-		SystemAccounts:     compDefObj.Spec.SystemAccounts,
-		RoleArbitrator:     compDefObj.Spec.RoleArbitrator,
-		Replicas:           comp.Spec.Replicas,
-		TLSConfig:          comp.Spec.TLSConfig,
-		ServiceAccountName: comp.Spec.ServiceAccountName,
-		Nodes:              comp.Spec.Nodes,
-		Instances:          comp.Spec.Instances,
-		RsmTransformPolicy: comp.Spec.RsmTransformPolicy,
+		Namespace:            comp.Namespace,
+		ClusterName:          clusterName,
+		ClusterUID:           clusterUID,
+		Comp2CompDefs:        buildComp2CompDefs(cluster, clusterCompSpec),
+		Name:                 compName,
+		FullCompName:         comp.Name,
+		CompDefName:          compDef.Name,
+		ClusterGeneration:    clusterGeneration(cluster, comp),
+		PodSpec:              &compDef.Spec.Runtime,
+		HostNetwork:          compDefObj.Spec.HostNetwork,
+		LogConfigs:           compDefObj.Spec.LogConfigs,
+		ConfigTemplates:      compDefObj.Spec.Configs,
+		ScriptTemplates:      compDefObj.Spec.Scripts,
+		Roles:                compDefObj.Spec.Roles,
+		UpdateStrategy:       compDefObj.Spec.UpdateStrategy,
+		MinReadySeconds:      compDefObj.Spec.MinReadySeconds,
+		LeaderEvictionPolicy: compDefObj.Spec.LeaderEvictionPolicy,
+		PolicyRules:          compDefObj.Spec.PolicyRules,
+		LifecycleActions:     compDefObj.Spec.LifecycleActions,
+		SystemAccounts:       compDefObj.Spec.SystemAccounts,
+		RoleArbitrator:       compDefObj.Spec.RoleArbitrator,
+		Replicas:             comp.Spec.Replicas,
+		TLSConfig:            comp.Spec.TLSConfig,
+		ServiceAccountName:   comp.Spec.ServiceAccountName,
+		Nodes:                comp.Spec.Nodes,
+		Instances:            comp.Spec.Instances,
+		RsmTransformPolicy:   comp.Spec.RsmTransformPolicy,
+		PodSpecPatch:         comp.Spec.PodSpecPatch,
 	}
 
 	// build backward compatible fields, including workload, services, componentRefEnvs, clusterDefName, clusterCompDefName, and clusterCompVer, etc.
 	// if cluster referenced a clusterDefinition and clusterVersion, for backward compatibility, we need to merge the clusterDefinition and clusterVersion into the component
 	// TODO(xingran): it will be removed in the future
 	if clusterDef != nil && cluster != nil && clusterCompSpec != nil {
-		if err = buildBackwardCompatibleFields(reqCtx, clusterDef, clusterVer, cluster, clusterCompSpec, synthesizeComp); err != nil {
+		if err = buildBackwardCompatibleFields(reqCtx, cli, clusterDef, clusterVer, cluster, clusterCompSpec, synthesizeComp); err != nil {
 			return nil, err
 		}
 	}
@@ -195,7 +198,10 @@ func buildSynthesizedComponent(reqCtx intctrlutil.RequestCtx,
 	}
 
 	// build labels and annotations
-	buildLabelsAndAnnotations(compDef, comp, synthesizeComp)
+	if err := buildLabelsAndAnnotations(compDef, comp, synthesizeComp); err != nil {
+		reqCtx.Log.Error(err, "build labels and annotations failed.")
+		return nil, err
+	}
 
 	// build volumeClaimTemplates
 	buildVolumeClaimTemplates(synthesizeComp, comp)
@@ -262,7 +268,7 @@ func buildComp2CompDefs(cluster *appsv1alpha1.Cluster, clusterCompSpec *appsv1al
 }
 
 // buildLabelsAndAnnotations builds labels and annotations for synthesizedComponent.
-func buildLabelsAndAnnotations(compDef *appsv1alpha1.ComponentDefinition, comp *appsv1alpha1.Component, synthesizeComp *SynthesizedComponent) {
+func buildLabelsAndAnnotations(compDef *appsv1alpha1.ComponentDefinition, comp *appsv1alpha1.Component, synthesizeComp *SynthesizedComponent) error {
 	replaceEnvPlaceholderTokens := func(clusterName, uid, componentName string, kvMap map[string]string) map[string]string {
 		replacedMap := make(map[string]string, len(kvMap))
 		builtInEnvMap := GetReplacementMapForBuiltInEnv(clusterName, uid, componentName)
@@ -283,6 +289,12 @@ func buildLabelsAndAnnotations(compDef *appsv1alpha1.ComponentDefinition, comp *
 		baseLabels := make(map[string]string)
 		if compDef.Spec.Labels != nil {
 			baseLabels = replaceEnvPlaceholderTokens(synthesizeComp.ClusterName, synthesizeComp.ClusterUID, synthesizeComp.Name, compDef.Spec.Labels)
+			// the values may exceed the length limit after the built-in variables are substituted.
+			for k, v := range baseLabels {
+				if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+					return fmt.Errorf("invalid value %q of label %s: %s", v, k, strings.Join(errs, "; "))
+				}
+			}
 		}
 		// override labels from component
 		synthesizeComp.Labels = mergeMaps(baseLabels, comp.Labels)
@@ -296,6 +308,7 @@ func buildLabelsAndAnnotations(compDef *appsv1alpha1.ComponentDefinition, comp *
 		// override annotations from component
 		synthesizeComp.Annotations = mergeMaps(baseAnnotations, comp.Annotations)
 	}
+	return nil
 }
 
 // buildAffinitiesAndTolerations builds affinities and tolerations for component.
@@ -412,6 +425,7 @@ func buildServiceAccountName(synthesizeComp *SynthesizedComponent) {
 // buildBackwardCompatibleFields builds backward compatible fields for component which referenced a clusterComponentDefinition and clusterComponentVersion before KubeBlocks Version 0.7.0
 // TODO(xingran): it will be removed in the future
 func buildBackwardCompatibleFields(reqCtx intctrlutil.RequestCtx,
+	cli client.Reader,
 	clusterDef *appsv1alpha1.ClusterDefinition,
 	clusterVer *appsv1alpha1.ClusterVersion,
 	cluster *appsv1alpha1.Cluster,
@@ -433,9 +447,21 @@ func buildBackwardCompatibleFields(reqCtx intctrlutil.RequestCtx,
 		synthesizeComp.WorkloadType = clusterCompDef.WorkloadType
 		synthesizeComp.CharacterType = clusterCompDef.CharacterType
 		synthesizeComp.HorizontalScalePolicy = clusterCompDef.HorizontalScalePolicy
+		synthesizeComp.RestartPolicy = clusterCompDef.RestartPolicy
+		synthesizeComp.PostStartSpec = clusterCompDef.PostStartSpec
+		if clusterCompDef.WorkloadType == appsv1alpha1.Replication && clusterCompDef.ReplicationSpec != nil {
+			synthesizeComp.MaxUnavailable = clusterCompDef.ReplicationSpec.MaxUnavailable
+		}
 		synthesizeComp.Probes = clusterCompDef.Probes
 		synthesizeComp.VolumeTypes = clusterCompDef.VolumeTypes
 		synthesizeComp.VolumeProtection = clusterCompDef.VolumeProtectionSpec
+		// the update strategy of the cluster component takes precedence over the member update strategy of the definition,
+		// the member update strategy is not used by Stateful and Stateless workloads, see compDefUpdateStrategyConvertor.
+		if clusterCompSpec.UpdateStrategy != nil &&
+			(clusterCompDef.WorkloadType == appsv1alpha1.Consensus || clusterCompDef.WorkloadType == appsv1alpha1.Replication) {
+			strategy := *clusterCompSpec.UpdateStrategy
+			synthesizeComp.UpdateStrategy = &strategy
+		}
 		// TLS is a backward compatible field, which is used in configuration rendering before version 0.8.0.
 		if synthesizeComp.TLSConfig != nil {
 			synthesizeComp.TLS = true
@@ -445,6 +471,7 @@ func buildBackwardCompatibleFields(reqCtx intctrlutil.RequestCtx,
 	// Services is a backward compatible field, which will be replaced with ComponentServices in the future.
 	buildServices := func() {
 		if clusterCompDef.Service != nil {
+			synthesizeComp.HeadlessService = clusterCompDef.Service.Headless
 			service := corev1.Service{Spec: clusterCompDef.Service.ToSVCSpec()}
 			service.Spec.Type = corev1.ServiceTypeClusterIP
 			synthesizeComp.Services = append(synthesizeComp.Services, service)
@@ -481,12 +508,12 @@ func buildBackwardCompatibleFields(reqCtx intctrlutil.RequestCtx,
 	}
 
 	buildPodManagementPolicy := func() {
-		var podManagementPolicy appsv1.PodManagementPolicyType
-		w := clusterCompDef.GetStatefulSetWorkload()
-		if w == nil {
+		// the stateless workload has no pod management policy and update strategy
+		podManagementPolicy, updateStrategy, err := appsv1alpha1.ResolveWorkloadUpdateStrategy(clusterCompDef, clusterCompSpec.UpdateStrategy)
+		if err != nil {
 			podManagementPolicy = ""
 		} else {
-			podManagementPolicy, _ = w.FinalStsUpdateStrategy()
+			synthesizeComp.StsUpdateStrategy = &updateStrategy
 		}
 		synthesizeComp.PodManagementPolicy = &podManagementPolicy
 	}
@@ -504,7 +531,7 @@ func buildBackwardCompatibleFields(reqCtx intctrlutil.RequestCtx,
 	buildPodManagementPolicy()
 
 	// build componentRefEnvs
-	if err := buildComponentRef(clusterDef, cluster, clusterCompDef, synthesizeComp); err != nil {
+	if err := buildComponentRef(reqCtx.Ctx, cli, clusterDef, cluster, clusterCompDef, synthesizeComp); err != nil {
 		reqCtx.Log.Error(err, "failed to merge componentRef")
 		return err
 	}
@@ -648,6 +675,47 @@ func ReplaceSecretEnvVars(namedValuesMap map[string]string, envs []corev1.EnvVar
 	return newEnvs
 }
 
+// GetReplacementMapForCmdExecutor gets the replacement map for the built-in variables which can be referred by
+// the envs of the command executors, they are resolved when rendering the jobs of system accounts, postStart and switchover.
+func GetReplacementMapForCmdExecutor(clusterName, componentName string, podNames []string) map[string]string {
+	podList := slices.Clone(podNames)
+	slices.Sort(podList)
+	return map[string]string{
+		constant.EnvPlaceHolder(constant.KBEnvClusterName): clusterName,
+		constant.EnvPlaceHolder(constant.KBEnvCompName):    componentName,
+		constant.EnvPlaceHolder(constant.KBEnvPodList):     strings.Join(podList, ","),
+	}
+}
+
+// ReplaceCmdExecutorEnvVars replaces the built-in variables in the names and values of envs, and in the prefixes and
+// the referred object names of envFroms, and returns the new envs and envFroms.
+func ReplaceCmdExecutorEnvVars(namedValuesMap map[string]string,
+	envs []corev1.EnvVar, envFroms []corev1.EnvFromSource) ([]corev1.EnvVar, []corev1.EnvFromSource) {
+	replace := func(s string) string {
+		return ReplaceNamedVars(namedValuesMap, s, -1, true)
+	}
+	var newEnvs []corev1.EnvVar
+	for _, e := range envs {
+		e = *e.DeepCopy()
+		e.Name = replace(e.Name)
+		e.Value = replace(e.Value)
+		newEnvs = append(newEnvs, e)
+	}
+	var newEnvFroms []corev1.EnvFromSource
+	for _, e := range envFroms {
+		e = *e.DeepCopy()
+		e.Prefix = replace(e.Prefix)
+		if e.ConfigMapRef != nil {
+			e.ConfigMapRef.Name = replace(e.ConfigMapRef.Name)
+		}
+		if e.SecretRef != nil {
+			e.SecretRef.Name = replace(e.SecretRef.Name)
+		}
+		newEnvFroms = append(newEnvFroms, e)
+	}
+	return newEnvs, newEnvFroms
+}
+
 // overrideSwitchoverSpecAttr overrides the attributes in switchoverSpec with the attributes of SwitchoverShortSpec in clusterVersion.
 func overrideSwitchoverSpecAttr(switchoverSpec *appsv1alpha1.SwitchoverSpec, cvSwitchoverSpec *appsv1alpha1.SwitchoverShortSpec) {
 	if switchoverSpec == nil || cvSwitchoverSpec == nil || cvSwitchoverSpec.CmdExecutorConfig == nil {
@@ -663,6 +731,12 @@ func overrideSwitchoverSpecAttr(switchoverSpec *appsv1alpha1.SwitchoverSpec, cvS
 		if len(cvSwitchoverSpec.CmdExecutorConfig.Env) > 0 {
 			cmdExecutorConfig.Env = cvSwitchoverSpec.CmdExecutorConfig.Env
 		}
+		if len(cvSwitchoverSpec.CmdExecutorConfig.EnvFrom) > 0 {
+			cmdExecutorConfig.EnvFrom = cvSwitchoverSpec.CmdExecutorConfig.EnvFrom
+		}
+		if len(cvSwitchoverSpec.CmdExecutorConfig.Resources.Limits) > 0 || len(cvSwitchoverSpec.CmdExecutorConfig.Resources.Requests) > 0 {
+			cmdExecutorConfig.Resources = cvSwitchoverSpec.CmdExecutorConfig.Resources
+		}
 	}
 	if switchoverSpec.WithCandidate != nil {
 		applyCmdExecutorConfig(switchoverSpec.WithCandidate.CmdExecutorConfig)
@@ -670,6 +744,48 @@ func overrideSwitchoverSpecAttr(switchoverSpec *appsv1alpha1.SwitchoverSpec, cvS
 	if switchoverSpec.WithoutCandidate != nil {
 		applyCmdExecutorConfig(switchoverSpec.WithoutCandidate.CmdExecutorConfig)
 	}
+	// the checks keep their own image and envs, the envs of clusterVersion are merged into them.
+	mergeCmdExecutorConfig := func(cmdExecutorConfig *appsv1alpha1.CmdExecutorConfig) {
+		if cmdExecutorConfig == nil {
+			return
+		}
+		if len(cmdExecutorConfig.Image) == 0 {
+			cmdExecutorConfig.Image = cvSwitchoverSpec.CmdExecutorConfig.Image
+		}
+		cmdExecutorConfig.Env = mergeSwitchoverEnvs(cmdExecutorConfig.Env, cvSwitchoverSpec.CmdExecutorConfig.Env)
+		cmdExecutorConfig.EnvFrom = append(cmdExecutorConfig.EnvFrom, cvSwitchoverSpec.CmdExecutorConfig.EnvFrom...)
+		if len(cmdExecutorConfig.Resources.Limits) == 0 && len(cmdExecutorConfig.Resources.Requests) == 0 {
+			cmdExecutorConfig.Resources = cvSwitchoverSpec.CmdExecutorConfig.Resources
+		}
+	}
+	mergeCmdExecutorConfig(switchoverSpec.PreCheck)
+	mergeCmdExecutorConfig(switchoverSpec.PostCheck)
+}
+
+// mergeSwitchoverEnvs merges the overrideEnvs into envs, the env with the same name is overridden.
+func mergeSwitchoverEnvs(envs, overrideEnvs []corev1.EnvVar) []corev1.EnvVar {
+	if len(overrideEnvs) == 0 {
+		return envs
+	}
+	merged := make([]corev1.EnvVar, 0, len(envs)+len(overrideEnvs))
+	overridden := make(map[string]corev1.EnvVar, len(overrideEnvs))
+	for _, e := range overrideEnvs {
+		overridden[e.Name] = e
+	}
+	for _, e := range envs {
+		if o, ok := overridden[e.Name]; ok {
+			merged = append(merged, o)
+			delete(overridden, e.Name)
+			continue
+		}
+		merged = append(merged, e)
+	}
+	for _, e := range overrideEnvs {
+		if _, ok := overridden[e.Name]; ok {
+			merged = append(merged, e)
+		}
+	}
+	return merged
 }
 
 func GetConfigSpecByName(synthesizedComp *SynthesizedComponent, configSpec string) *appsv1alpha1.ComponentConfigSpec {
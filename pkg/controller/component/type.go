@@ -23,17 +23,22 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 )
 
 type MonitorConfig struct {
-	Enable     bool   `json:"enable"`
-	BuiltIn    bool   `json:"builtIn"`
-	ScrapePort int32  `json:"scrapePort,omitempty"`
-	ScrapePath string `json:"scrapePath,omitempty"`
+	Enable         bool                        `json:"enable"`
+	BuiltIn        bool                        `json:"builtIn"`
+	ScrapePort     int32                       `json:"scrapePort,omitempty"`
+	ScrapePath     string                      `json:"scrapePath,omitempty"`
+	ScrapeScheme   v1alpha1.ScrapeScheme       `json:"scrapeScheme,omitempty"`
+	TLSConfig      *v1alpha1.ExporterTLSConfig `json:"tlsConfig,omitempty"`
+	ServiceMonitor bool                        `json:"serviceMonitor,omitempty"`
 }
 
 type SynthesizedComponent struct {
@@ -55,44 +60,53 @@ type SynthesizedComponent struct {
 	TLSConfig            *v1alpha1.TLSConfig                    `json:"tlsConfig"`
 	ServiceAccountName   string                                 `json:"serviceAccountName,omitempty"`
 	// TODO: remove this later
-	ComponentRefEnvs  []corev1.EnvVar                        `json:"componentRefEnvs,omitempty"`
-	ServiceReferences map[string]*v1alpha1.ServiceDescriptor `json:"serviceReferences,omitempty"`
-	TemplateVars      map[string]any                         `json:"templateVars,omitempty"`
-	EnvVars           []corev1.EnvVar                        `json:"envVars,omitempty"`
-	EnvFromSources    []corev1.EnvFromSource                 `json:"envFromSources,omitempty"`
+	ComponentRefEnvs           []corev1.EnvVar                        `json:"componentRefEnvs,omitempty"`
+	ComponentRefCredentialEnvs []ComponentRefCredentialEnv            `json:"componentRefCredentialEnvs,omitempty"`
+	IgnoredComponentRefEnvs    []ComponentRefEnvResult                `json:"-"` // The envs of the componentDefRefs which failed to be resolved and are ignored by the failure policy.
+	ServiceReferences          map[string]*v1alpha1.ServiceDescriptor `json:"serviceReferences,omitempty"`
+	TemplateVars               map[string]any                         `json:"templateVars,omitempty"`
+	EnvVars                    []corev1.EnvVar                        `json:"envVars,omitempty"`
+	EnvFromSources             []corev1.EnvFromSource                 `json:"envFromSources,omitempty"`
 
 	RsmTransformPolicy workloads.RsmTransformPolicy `json:"rsmTransformPolicy,omitempty"`
 	Nodes              []types.NodeName             `json:"nodes,omitempty"`
 	Instances          []string                     `json:"instances,omitempty"`
+	PodSpecPatch       *runtime.RawExtension        `json:"podSpecPatch,omitempty"`
 
 	NodesAssignment []workloads.NodeAssignment `json:"nodesAssignment,omitempty"`
 
 	// The following fields were introduced with the ComponentDefinition and Component API in KubeBlocks version 0.8.0
-	Roles               []v1alpha1.ReplicaRole              `json:"roles,omitempty"`
-	Labels              map[string]string                   `json:"labels,omitempty"`
-	Annotations         map[string]string                   `json:"annotations,omitempty"`
-	UpdateStrategy      *v1alpha1.UpdateStrategy            `json:"updateStrategy,omitempty"`
-	PodManagementPolicy *appsv1.PodManagementPolicyType     `json:"podManagementPolicy,omitempty"`
-	PolicyRules         []rbacv1.PolicyRule                 `json:"policyRules,omitempty"`
-	LifecycleActions    *v1alpha1.ComponentLifecycleActions `json:"lifecycleActions,omitempty"`
-	SystemAccounts      []v1alpha1.SystemAccount            `json:"systemAccounts,omitempty"`
-	RoleArbitrator      *v1alpha1.RoleArbitrator            `json:"roleArbitrator,omitempty"`
-	Volumes             []v1alpha1.ComponentVolume          `json:"volumes,omitempty"`
-	HostNetwork         *v1alpha1.HostNetwork               `json:"hostNetwork,omitempty"`
-	ComponentServices   []v1alpha1.ComponentService         `json:"componentServices,omitempty"`
-	MinReadySeconds     int32                               `json:"minReadySeconds,omitempty"`
+	Roles                []v1alpha1.ReplicaRole              `json:"roles,omitempty"`
+	Labels               map[string]string                   `json:"labels,omitempty"`
+	Annotations          map[string]string                   `json:"annotations,omitempty"`
+	UpdateStrategy       *v1alpha1.UpdateStrategy            `json:"updateStrategy,omitempty"`
+	PodManagementPolicy  *appsv1.PodManagementPolicyType     `json:"podManagementPolicy,omitempty"`
+	PolicyRules          []rbacv1.PolicyRule                 `json:"policyRules,omitempty"`
+	LifecycleActions     *v1alpha1.ComponentLifecycleActions `json:"lifecycleActions,omitempty"`
+	SystemAccounts       []v1alpha1.SystemAccount            `json:"systemAccounts,omitempty"`
+	RoleArbitrator       *v1alpha1.RoleArbitrator            `json:"roleArbitrator,omitempty"`
+	Volumes              []v1alpha1.ComponentVolume          `json:"volumes,omitempty"`
+	HostNetwork          *v1alpha1.HostNetwork               `json:"hostNetwork,omitempty"`
+	ComponentServices    []v1alpha1.ComponentService         `json:"componentServices,omitempty"`
+	MinReadySeconds      int32                               `json:"minReadySeconds,omitempty"`
+	LeaderEvictionPolicy workloads.LeaderEvictionPolicy      `json:"leaderEvictionPolicy,omitempty"`
 
 	// TODO(xingran): The following fields will be deprecated after version 0.8.0 and will be replaced with a new data structure.
-	Probes           *v1alpha1.ClusterDefinitionProbes `json:"probes,omitempty"`           // The Probes will be replaced with LifecycleActions.RoleProbe in the future.
-	VolumeTypes      []v1alpha1.VolumeTypeSpec         `json:"volumeTypes,omitempty"`      // The VolumeTypes will be replaced with Volumes in the future.
-	VolumeProtection *v1alpha1.VolumeProtectionSpec    `json:"volumeProtection,omitempty"` // The VolumeProtection will be replaced with Volumes in the future.
-	Services         []corev1.Service                  `json:"services,omitempty"`         // The Services will be replaced with ComponentServices in the future.
-	TLS              bool                              `json:"tls"`                        // The TLS will be replaced with TLSConfig in the future.
+	Probes            *v1alpha1.ClusterDefinitionProbes `json:"probes,omitempty"`            // The Probes will be replaced with LifecycleActions.RoleProbe in the future.
+	VolumeTypes       []v1alpha1.VolumeTypeSpec         `json:"volumeTypes,omitempty"`       // The VolumeTypes will be replaced with Volumes in the future.
+	VolumeProtection  *v1alpha1.VolumeProtectionSpec    `json:"volumeProtection,omitempty"`  // The VolumeProtection will be replaced with Volumes in the future.
+	Services          []corev1.Service                  `json:"services,omitempty"`          // The Services will be replaced with ComponentServices in the future.
+	HeadlessService   *v1alpha1.HeadlessServiceSpec     `json:"headlessService,omitempty"`   // The HeadlessService customizes the headless service created by the workload.
+	StsUpdateStrategy *appsv1.StatefulSetUpdateStrategy `json:"stsUpdateStrategy,omitempty"` // The StsUpdateStrategy is resolved from the StatefulSetSpec of the ClusterComponentDefinition.
+	TLS               bool                              `json:"tls"`                         // The TLS will be replaced with TLSConfig in the future.
 
 	// TODO(xingran): The following fields will be deprecated after KubeBlocks version 0.8.0
-	ClusterDefName        string                          `json:"clusterDefName,omitempty"`     // the name of the clusterDefinition
-	ClusterCompDefName    string                          `json:"clusterCompDefName,omitempty"` // the name of the clusterDefinition.Spec.ComponentDefs[*].Name or cluster.Spec.ComponentSpecs[*].ComponentDefRef
-	CharacterType         string                          `json:"characterType,omitempty"`
-	WorkloadType          v1alpha1.WorkloadType           `json:"workloadType,omitempty"`
-	HorizontalScalePolicy *v1alpha1.HorizontalScalePolicy `json:"horizontalScalePolicy,omitempty"`
+	ClusterDefName        string                           `json:"clusterDefName,omitempty"`     // the name of the clusterDefinition
+	ClusterCompDefName    string                           `json:"clusterCompDefName,omitempty"` // the name of the clusterDefinition.Spec.ComponentDefs[*].Name or cluster.Spec.ComponentSpecs[*].ComponentDefRef
+	CharacterType         string                           `json:"characterType,omitempty"`
+	WorkloadType          v1alpha1.WorkloadType            `json:"workloadType,omitempty"`
+	HorizontalScalePolicy *v1alpha1.HorizontalScalePolicy  `json:"horizontalScalePolicy,omitempty"`
+	RestartPolicy         *v1alpha1.ScheduledRestartPolicy `json:"restartPolicy,omitempty"`
+	PostStartSpec         *v1alpha1.PostStartAction        `json:"postStartSpec,omitempty"`
+	MaxUnavailable        *intstr.IntOrString              `json:"maxUnavailable,omitempty"` // the maxUnavailable of the Replication workload
 }
@@ -313,17 +313,23 @@ func buildDefaultEnvVars(synthesizedComp *SynthesizedComponent, legacy bool) []c
 	clusterCompName := func() string {
 		return constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name)
 	}()
+	podFQDN := func(headlessSvcName string) string {
+		return fmt.Sprintf("%s.%s.%s.svc", constant.EnvPlaceHolder(constant.KBEnvPodName), headlessSvcName, constant.EnvPlaceHolder(constant.KBEnvNamespace))
+	}
+	headlessSvcName := HeadlessServiceName(synthesizedComp)
 	if legacy {
 		vars = append(vars, []corev1.EnvVar{
 			{Name: constant.KBEnvClusterName, Value: synthesizedComp.ClusterName},
 			{Name: constant.KBEnvCompName, Value: synthesizedComp.Name},
 			{Name: constant.KBEnvClusterCompName, Value: clusterCompName},
 			{Name: constant.KBEnvClusterUIDPostfix8Deprecated, Value: clusterUIDPostfix(synthesizedComp)},
-			{Name: constant.KBEnvPodFQDN, Value: fmt.Sprintf("%s.%s-headless.%s.svc", constant.EnvPlaceHolder(constant.KBEnvPodName), constant.EnvPlaceHolder(constant.KBEnvClusterCompName), constant.EnvPlaceHolder(constant.KBEnvNamespace))}}...)
+			// keep referring to the env of cluster component name in the legacy mode.
+			{Name: constant.KBEnvPodFQDN, Value: podFQDN(constant.EnvPlaceHolder(constant.KBEnvClusterCompName) +
+				strings.TrimPrefix(headlessSvcName, clusterCompName))}}...)
 	} else {
 		vars = append(vars, corev1.EnvVar{
 			Name:  constant.KBEnvPodFQDN,
-			Value: fmt.Sprintf("%s.%s-headless.%s.svc", constant.EnvPlaceHolder(constant.KBEnvPodName), clusterCompName, constant.EnvPlaceHolder(constant.KBEnvNamespace)),
+			Value: podFQDN(headlessSvcName),
 		})
 	}
 	return vars
@@ -846,12 +852,11 @@ func resolvePodVarRefLow(ctx context.Context, cli client.Reader, synthesizedComp
 func resolveServiceVarRefLow(ctx context.Context, cli client.Reader, synthesizedComp *SynthesizedComponent,
 	selector appsv1alpha1.ServiceVarSelector, option *appsv1alpha1.VarOption, resolveVar func(any) (*corev1.EnvVar, *corev1.EnvVar)) (*corev1.EnvVar, *corev1.EnvVar, error) {
 	resolveObj := func() (any, error) {
+		if selector.Name == "headless" {
+			return resolveReferentHeadlessService(ctx, cli, synthesizedComp, selector.ClusterObjectReference)
+		}
 		objName := func(compName string) string {
-			svcName := constant.GenerateComponentServiceName(synthesizedComp.ClusterName, compName, selector.Name)
-			if selector.Name == "headless" {
-				svcName = constant.GenerateDefaultComponentHeadlessServiceName(synthesizedComp.ClusterName, compName)
-			}
-			return svcName
+			return constant.GenerateComponentServiceName(synthesizedComp.ClusterName, compName, selector.Name)
 		}
 		return resolveReferentObject(ctx, cli, synthesizedComp, selector.ClusterObjectReference, objName, &corev1.Service{})
 	}
@@ -902,6 +907,42 @@ func resolveReferentObject(ctx context.Context, cli client.Reader, synthesizedCo
 	return obj, nil
 }
 
+// resolveReferentHeadlessService resolves the headless service of the referent component, whose name may be
+// customized, so the name is taken from the workload of the component other than the component itself.
+func resolveReferentHeadlessService(ctx context.Context, cli client.Reader, synthesizedComp *SynthesizedComponent,
+	objRef appsv1alpha1.ClusterObjectReference) (any, error) {
+	compName, err := resolveReferentComponent(synthesizedComp, objRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	svcName := HeadlessServiceName(synthesizedComp)
+	if compName != synthesizedComp.Name {
+		rsm := &workloads.ReplicatedStateMachine{}
+		rsmKey := types.NamespacedName{
+			Namespace: synthesizedComp.Namespace,
+			Name:      constant.GenerateRSMNamePattern(synthesizedComp.ClusterName, compName),
+		}
+		if err = cli.Get(ctx, rsmKey, rsm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		svcName = rsm.Spec.ServiceName
+	}
+	svc := &corev1.Service{}
+	if err = cli.Get(ctx, types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: svcName}, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return svc, nil
+}
+
 func resolveReferentComponent(synthesizedComp *SynthesizedComponent, objRef appsv1alpha1.ClusterObjectReference) (string, error) {
 	if len(objRef.CompDef) == 0 || objRef.CompDef == synthesizedComp.CompDefName {
 		return synthesizedComp.Name, nil
@@ -554,6 +554,42 @@ var _ = Describe("vars", func() {
 			checkEnvVarWithValue(envVars, "service-port", strconv.Itoa(svcPort))
 			checkEnvVarWithValue(envVars, "service-port-wo-name", strconv.Itoa(svcPort+1))
 
+			By("customized headless service")
+			synthesizedComp.HeadlessService = &appsv1alpha1.HeadlessServiceSpec{NameSuffix: "hs"}
+			headlessSvcName := HeadlessServiceName(synthesizedComp)
+			vars = []appsv1alpha1.EnvVar{
+				{
+					Name: "headless-service-host",
+					ValueFrom: &appsv1alpha1.VarSource{
+						ServiceVarRef: &appsv1alpha1.ServiceVarSelector{
+							ClusterObjectReference: appsv1alpha1.ClusterObjectReference{
+								Name:     "headless",
+								Optional: required(),
+							},
+							ServiceVars: appsv1alpha1.ServiceVars{
+								Host: &appsv1alpha1.VarRequired,
+							},
+						},
+					},
+				},
+			}
+			reader = &mockReader{
+				cli: testCtx.Cli,
+				objs: []client.Object{
+					&corev1.Service{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: testCtx.DefaultNamespace,
+							Name:      headlessSvcName,
+						},
+					},
+				},
+			}
+			templateVars, envVars, err = ResolveTemplateNEnvVars(testCtx.Ctx, reader, synthesizedComp, vars)
+			Expect(err).Should(Succeed())
+			Expect(templateVars).Should(HaveKeyWithValue("headless-service-host", headlessSvcName))
+			checkEnvVarWithValue(envVars, "headless-service-host", headlessSvcName)
+			synthesizedComp.HeadlessService = nil
+
 			By("service var ref with pod ordinal")
 			svcNameRefPrefix := "service-node-port"
 			vars = []appsv1alpha1.EnvVar{
@@ -20,11 +20,15 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package factory
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -32,6 +36,7 @@ import (
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 
@@ -87,9 +92,11 @@ func BuildRSM(cluster *appsv1alpha1.Cluster, synthesizedComp *component.Synthesi
 		AddLabelsInMap(mergeLabels).
 		AddAnnotationsInMap(mergeAnnotations).
 		AddMatchLabelsInMap(labels).
-		SetServiceName(constant.GenerateRSMServiceNamePattern(rsmName)).
+		SetServiceName(component.HeadlessServiceName(synthesizedComp)).
+		SetHeadlessService(buildRSMHeadlessService(synthesizedComp)).
 		SetReplicas(synthesizedComp.Replicas).
 		SetMinReadySeconds(synthesizedComp.MinReadySeconds).
+		SetLeaderEvictionPolicy(synthesizedComp.LeaderEvictionPolicy).
 		SetRsmTransformPolicy(synthesizedComp.RsmTransformPolicy).
 		SetNodeAssignment(synthesizedComp.NodesAssignment).
 		SetTemplate(template)
@@ -124,6 +131,16 @@ func BuildRSM(cluster *appsv1alpha1.Cluster, synthesizedComp *component.Synthesi
 	return rsmObj, nil
 }
 
+func buildRSMHeadlessService(synthesizedComp *component.SynthesizedComponent) *workloads.HeadlessService {
+	if synthesizedComp.HeadlessService == nil {
+		return nil
+	}
+	return &workloads.HeadlessService{
+		PublishNotReadyAddresses: synthesizedComp.HeadlessService.PublishNotReadyAddresses,
+		SessionAffinity:          synthesizedComp.HeadlessService.SessionAffinity,
+	}
+}
+
 func vctToPVC(vct corev1.PersistentVolumeClaimTemplate) corev1.PersistentVolumeClaim {
 	return corev1.PersistentVolumeClaim{
 		ObjectMeta: vct.ObjectMeta,
@@ -143,12 +160,26 @@ func getMonitorAnnotations(synthesizedComp *component.SynthesizedComponent) map[
 	case synthesizedComp.Monitor.BuiltIn:
 		annotations["monitor.kubeblocks.io/scrape"] = falseStr
 		annotations["monitor.kubeblocks.io/agamotto"] = trueStr
+	case synthesizedComp.Monitor.UseServiceMonitor():
+		// the metrics are scraped by the ServiceMonitor
+		annotations["monitor.kubeblocks.io/scrape"] = falseStr
+		annotations["monitor.kubeblocks.io/agamotto"] = falseStr
 	default:
 		annotations["monitor.kubeblocks.io/scrape"] = trueStr
 		annotations["monitor.kubeblocks.io/path"] = synthesizedComp.Monitor.ScrapePath
 		annotations["monitor.kubeblocks.io/port"] = strconv.Itoa(int(synthesizedComp.Monitor.ScrapePort))
-		annotations["monitor.kubeblocks.io/scheme"] = "http"
+		annotations["monitor.kubeblocks.io/scheme"] = string(appsv1alpha1.HTTPScrapeScheme)
+		if synthesizedComp.Monitor.ScrapeScheme != "" {
+			annotations["monitor.kubeblocks.io/scheme"] = string(synthesizedComp.Monitor.ScrapeScheme)
+		}
 		annotations["monitor.kubeblocks.io/agamotto"] = falseStr
+		if tlsConfig := synthesizedComp.Monitor.TLSConfig; tlsConfig != nil {
+			annotations["monitor.kubeblocks.io/tls-insecure-skip-verify"] = strconv.FormatBool(tlsConfig.InsecureSkipVerify)
+			if tlsConfig.CASecretRef != nil {
+				annotations["monitor.kubeblocks.io/tls-ca-secret-name"] = tlsConfig.CASecretRef.Name
+				annotations["monitor.kubeblocks.io/tls-ca-secret-key"] = tlsConfig.CASecretRef.Key
+			}
+		}
 	}
 	return rsm.AddAnnotationScope(rsm.HeadlessServiceScope, annotations)
 }
@@ -187,13 +218,136 @@ func randomString(length int) string {
 	return rand.String(length)
 }
 
-func strongRandomString(length int) string {
-	str, _ := common.GeneratePassword(length, 3, 3, false, "")
+func strongRandomString(length int, symbols string) string {
+	str, _ := common.GeneratePasswordWithSymbols(length, 3, 3, false, "", symbols)
+	return str
+}
+
+// randomStringAlphanums is the alphabet of rand.String.
+const randomStringAlphanums = "bcdfghjklmnpqrstvwxz2456789"
+
+// seededReader reads the bytes derived from the seed and the key by HMAC-SHA256 in counter mode,
+// the same bytes are read with the same seed and key.
+type seededReader struct {
+	seed    []byte
+	key     []byte
+	counter uint32
+	buf     []byte
+}
+
+func newSeededReader(seed, key string) *seededReader {
+	return &seededReader{seed: []byte(seed), key: []byte(key)}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			mac := hmac.New(sha256.New, r.seed)
+			mac.Write(r.key)
+			mac.Write(binary.BigEndian.AppendUint32(nil, r.counter))
+			r.buf = mac.Sum(nil)
+			r.counter++
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// seededRandomString derives a string of the given length from the seed and the key,
+// with the same alphabet as rand.String.
+func seededRandomString(seed, key string, length int) string {
+	r := newSeededReader(seed, key)
+	// discard the bytes beyond the largest multiple of the alphabet size to avoid the modulo bias.
+	limit := byte(256 - 256%len(randomStringAlphanums))
+	b := make([]byte, 1)
+	str := make([]byte, 0, length)
+	for len(str) < length {
+		_, _ = r.Read(b)
+		if b[0] >= limit {
+			continue
+		}
+		str = append(str, randomStringAlphanums[int(b[0])%len(randomStringAlphanums)])
+	}
+	return string(str)
+}
+
+// seededStrongRandomString derives a strong password of the given length from the seed and the key.
+func seededStrongRandomString(seed, key string, length int, symbols string) string {
+	b := make([]byte, sha256.Size)
+	_, _ = newSeededReader(seed, key).Read(b)
+	str, _ := common.GeneratePasswordWithSymbols(length, 3, 3, false, hex.EncodeToString(b), symbols)
 	return str
 }
 
+// seededUUID derives a UUID v4 from the seed and the key.
+func seededUUID(seed, key string) uuid.UUID {
+	// the seeded reader never fails
+	u, _ := uuid.NewRandomFromReader(newSeededReader(seed, key))
+	return u
+}
+
+// getPasswordSymbolCharacters returns the symbol whitelist of the password config of the componentDef.
+func getPasswordSymbolCharacters(clusterDefinition *appsv1alpha1.ClusterDefinition, synthesizedComp *component.SynthesizedComponent) string {
+	compDef := clusterDefinition.GetComponentDefByName(synthesizedComp.ClusterCompDefName)
+	if compDef == nil || compDef.SystemAccounts == nil {
+		return ""
+	}
+	return compDef.SystemAccounts.PasswordConfig.SymbolCharacters
+}
+
+// svcFQDNPlaceholderRegex matches the `$(SVC_FQDN_{COMPONENT-NAME})` and `$(HEADLESS_SVC_FQDN_{COMPONENT-NAME})`
+// placeholders in connection credential.
+var svcFQDNPlaceholderRegex = regexp.MustCompile(`\$\((HEADLESS_)?SVC_FQDN_([^)]+)\)`)
+
+// buildComponentSvcFQDNs resolves the `$(SVC_FQDN_{COMPONENT-NAME})` and `$(HEADLESS_SVC_FQDN_{COMPONENT-NAME})`
+// placeholders in connection credential against the named componentDef, which must define a service.
+func buildComponentSvcFQDNs(clusterDefinition *appsv1alpha1.ClusterDefinition, cluster *appsv1alpha1.Cluster,
+	stringData map[string]string) (map[string]string, error) {
+	resolve := func(compDefName string, headless bool) (string, error) {
+		compDef := clusterDefinition.GetComponentDefByName(compDefName)
+		if compDef == nil || compDef.Service == nil {
+			return "", fmt.Errorf("componentDef %s referenced by connection credential does not define a service", compDefName)
+		}
+		for _, compSpec := range cluster.Spec.ComponentSpecs {
+			if compSpec.ComponentDefRef != compDefName {
+				continue
+			}
+			if headless {
+				return constant.GenerateComponentHeadlessServiceNameWithSuffix(cluster.Name, compSpec.Name,
+					compDef.Service.GetHeadlessNameSuffix()), nil
+			}
+			return constant.GenerateDefaultComponentServiceName(cluster.Name, compSpec.Name), nil
+		}
+		return "", fmt.Errorf("componentDef %s referenced by connection credential is not used by any component of cluster %s",
+			compDefName, cluster.Name)
+	}
+
+	m := map[string]string{}
+	for k, v := range stringData {
+		for _, vv := range []string{k, v} {
+			for _, match := range svcFQDNPlaceholderRegex.FindAllStringSubmatch(vv, -1) {
+				if _, ok := m[match[0]]; ok {
+					continue
+				}
+				fqdn, err := resolve(match[2], len(match[1]) > 0)
+				if err != nil {
+					return nil, err
+				}
+				m[match[0]] = fqdn
+			}
+		}
+	}
+	return m, nil
+}
+
+// escapedDollarPlaceholder temporarily stands for the escaped `$$` in connection credential values.
+const escapedDollarPlaceholder = "\x00"
+
 func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, cluster *appsv1alpha1.Cluster,
-	synthesizedComp *component.SynthesizedComponent) *corev1.Secret {
+	synthesizedComp *component.SynthesizedComponent) (*corev1.Secret, error) {
 	wellKnownLabels := constant.GetKBWellKnownLabels(clusterDefinition.Name, cluster.Name, "")
 	delete(wellKnownLabels, constant.KBAppComponentLabelKey)
 	credentialBuilder := builder.NewSecretBuilder(cluster.Namespace, constant.GenerateDefaultConnCredential(cluster.Name)).
@@ -205,8 +359,18 @@ func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, clus
 	connCredential := credentialBuilder.GetObject()
 
 	if len(connCredential.StringData) == 0 {
-		return connCredential
+		return connCredential, nil
+	}
+
+	// the `$$` is an escaped `$`, hide it from the replacement and restore it as a literal `$` at last.
+	for k, v := range connCredential.StringData {
+		connCredential.StringData[k] = strings.ReplaceAll(v, "$$", escapedDollarPlaceholder)
 	}
+	defer func() {
+		for k, v := range connCredential.StringData {
+			connCredential.StringData[k] = strings.ReplaceAll(v, escapedDollarPlaceholder, "$")
+		}
+	}()
 
 	replaceVarObjects := func(k, v *string, i int, origValue string, varObjectsMap map[string]string) {
 		toReplace := origValue
@@ -259,7 +423,11 @@ func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, clus
 		if !ok {
 			return ""
 		}
-		e := intctrlutil.NewEncryptor(viper.GetString(constant.CfgKeyDPEncryptionKey))
+		e, err := intctrlutil.NewEncryptorWithAlgorithm(viper.GetString(constant.CfgKeyDPEncryptionKey),
+			backupSource[constant.EncryptionAlgorithmKeyForRestore])
+		if err != nil {
+			return ""
+		}
 		password, _ = e.Decrypt([]byte(password))
 		return password
 	}
@@ -267,13 +435,20 @@ func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, clus
 	// TODO: do JIT value generation for lower CPU resources
 	// 1st pass replace variables
 	uuidVal := uuid.New()
+	randomPassword := randomString(8)
+	strongRandomPasswd := strongRandomString(16, getPasswordSymbolCharacters(clusterDefinition, synthesizedComp))
+	// derive the random values from the seed if specified, the seed is keyed by the placeholder names.
+	if seed := cluster.Annotations[constant.ConnCredentialSeedAnnotationKey]; seed != "" {
+		uuidVal = seededUUID(seed, "UUID")
+		randomPassword = seededRandomString(seed, "RANDOM_PASSWD", 8)
+		strongRandomPasswd = seededStrongRandomString(seed, "STRONG_RANDOM_PASSWD", 16,
+			getPasswordSymbolCharacters(clusterDefinition, synthesizedComp))
+	}
 	uuidBytes := uuidVal[:]
 	uuidStr := uuidVal.String()
 	uuidB64 := base64.RawStdEncoding.EncodeToString(uuidBytes)
 	uuidStrB64 := base64.RawStdEncoding.EncodeToString([]byte(strings.ReplaceAll(uuidStr, "-", "")))
 	uuidHex := hex.EncodeToString(uuidBytes)
-	randomPassword := randomString(8)
-	strongRandomPasswd := strongRandomString(16)
 	restorePassword := getRestorePassword()
 	// check if a connection password is specified during recovery.
 	// if exists, replace the random password
@@ -289,13 +464,20 @@ func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, clus
 		"$(UUID_HEX)":             uuidHex,
 		"$(SVC_FQDN)":             constant.GenerateDefaultComponentServiceName(cluster.Name, synthesizedComp.Name),
 		constant.EnvPlaceHolder(constant.KBEnvClusterCompName): constant.GenerateClusterComponentName(cluster.Name, synthesizedComp.Name),
-		"$(HEADLESS_SVC_FQDN)":                                 constant.GenerateDefaultComponentHeadlessServiceName(cluster.Name, synthesizedComp.Name),
+		"$(HEADLESS_SVC_FQDN)":                                 component.HeadlessServiceName(synthesizedComp),
 	}
 	if len(synthesizedComp.Services) > 0 {
 		for _, p := range synthesizedComp.Services[0].Spec.Ports {
 			m[fmt.Sprintf("$(SVC_PORT_%s)", p.Name)] = strconv.Itoa(int(p.Port))
 		}
 	}
+	compSvcFQDNs, err := buildComponentSvcFQDNs(clusterDefinition, cluster, connCredential.StringData)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range compSvcFQDNs {
+		m[k] = v
+	}
 	replaceData(m)
 
 	// 2nd pass replace $(CONN_CREDENTIAL) variables
@@ -304,7 +486,66 @@ func BuildConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, clus
 		m[fmt.Sprintf("$(CONN_CREDENTIAL).%s", k)] = v
 	}
 	replaceData(m)
-	return connCredential
+	return connCredential, nil
+}
+
+// randomPasswdPlaceholders are the placeholders in connection credential whose values are randomly generated.
+var randomPasswdPlaceholders = []string{"$(RANDOM_PASSWD)", "$(STRONG_RANDOM_PASSWD)"}
+
+// RotateConnCredential regenerates the random entries of the existing connection credential, that is the entries
+// built from $(RANDOM_PASSWD) or $(STRONG_RANDOM_PASSWD), and the ones referring them by $(CONN_CREDENTIAL).{key}.
+// All other entries are kept unchanged. It returns the rotated entries only, or nil if there is nothing to rotate.
+func RotateConnCredential(clusterDefinition *appsv1alpha1.ClusterDefinition, cluster *appsv1alpha1.Cluster,
+	synthesizedComp *component.SynthesizedComponent, existing *corev1.Secret) (map[string]string, error) {
+	template := clusterDefinition.Spec.ConnectionCredential
+	rotatedKeys := map[string]bool{}
+	for k, v := range template {
+		// the escaped `$$(...)` is a literal string rather than a placeholder.
+		v = strings.ReplaceAll(v, "$$", escapedDollarPlaceholder)
+		for _, placeholder := range randomPasswdPlaceholders {
+			if strings.Contains(v, placeholder) {
+				rotatedKeys[k] = true
+			}
+		}
+	}
+	refKeys := map[string]bool{}
+	for k, v := range template {
+		v = strings.ReplaceAll(v, "$$", escapedDollarPlaceholder)
+		for rk := range rotatedKeys {
+			if strings.Contains(v, fmt.Sprintf("$(CONN_CREDENTIAL).%s", rk)) {
+				refKeys[k] = true
+			}
+		}
+	}
+	for k := range refKeys {
+		rotatedKeys[k] = true
+	}
+	if len(rotatedKeys) == 0 {
+		return nil, nil
+	}
+
+	// the password specified during recovery and the seed should not be reused by rotation.
+	cluster = cluster.DeepCopy()
+	delete(cluster.Annotations, constant.RestoreFromBackupAnnotationKey)
+	delete(cluster.Annotations, constant.ConnCredentialSeedAnnotationKey)
+	connCredential, err := BuildConnCredential(clusterDefinition, cluster, synthesizedComp)
+	if err != nil {
+		return nil, err
+	}
+	rotated := map[string]string{}
+	for k := range rotatedKeys {
+		// the entries whose keys are built from placeholders are not rotated.
+		if _, ok := existing.Data[k]; !ok {
+			continue
+		}
+		if v, ok := connCredential.StringData[k]; ok {
+			rotated[k] = v
+		}
+	}
+	if len(rotated) == 0 {
+		return nil, nil
+	}
+	return rotated, nil
 }
 
 func BuildPVC(cluster *appsv1alpha1.Cluster,
@@ -339,7 +580,7 @@ func BuildBackup(cluster *appsv1alpha1.Cluster,
 	backupPolicyName string,
 	backupKey types.NamespacedName,
 	backupMethod string) *dpv1alpha1.Backup {
-	return builder.NewBackupBuilder(backupKey.Namespace, backupKey.Name).
+	backupBuilder := builder.NewBackupBuilder(backupKey.Namespace, backupKey.Name).
 		AddLabels(dptypes.BackupMethodLabelKey, backupMethod).
 		AddLabels(dptypes.BackupPolicyLabelKey, backupPolicyName).
 		AddLabels(constant.KBManagedByKey, "cluster").
@@ -348,8 +589,12 @@ func BuildBackup(cluster *appsv1alpha1.Cluster,
 		AddLabels(constant.AppManagedByLabelKey, constant.AppName).
 		AddLabels(constant.KBAppComponentLabelKey, component.Name).
 		SetBackupPolicyName(backupPolicyName).
-		SetBackupMethod(backupMethod).
-		GetObject()
+		SetBackupMethod(backupMethod)
+	// the VolumeSnapshotClass of the horizontal scale policy overrides the one of the backup method.
+	if policy := component.HorizontalScalePolicy; policy != nil && policy.VolumeSnapshotClassName != "" {
+		backupBuilder.AddAnnotations(dptypes.VolumeSnapshotClassAnnotationKey, policy.VolumeSnapshotClassName)
+	}
+	return backupBuilder.GetObject()
 }
 
 func BuildConfigMapWithTemplate(cluster *appsv1alpha1.Cluster,
@@ -508,3 +753,99 @@ func BuildClusterRoleBinding(cluster *appsv1alpha1.Cluster, saName string) *rbac
 		}).
 		GetObject()
 }
+
+// BuildServiceMonitor builds a ServiceMonitor of prometheus-operator to scrape the metrics from the exporter
+// of the component, through the default headless service of the component.
+func BuildServiceMonitor(synthesizedComp *component.SynthesizedComponent) *unstructured.Unstructured {
+	var (
+		monitor         = synthesizedComp.Monitor
+		labels          = constant.GetComponentWellKnownLabels(synthesizedComp.ClusterName, synthesizedComp.Name)
+		headlessSvcName = component.HeadlessServiceName(synthesizedComp)
+	)
+
+	endpoint := map[string]interface{}{
+		"path":   "/metrics",
+		"scheme": string(appsv1alpha1.HTTPScrapeScheme),
+		// the other services of the component may expose the same port, only the headless service is scraped.
+		"relabelings": []interface{}{
+			map[string]interface{}{
+				"sourceLabels": []interface{}{"__meta_kubernetes_service_name"},
+				"regex":        headlessSvcName,
+				"action":       "keep",
+			},
+		},
+	}
+	if portName := getServiceMonitorPortName(synthesizedComp); portName != "" {
+		endpoint["port"] = portName
+	} else {
+		endpoint["targetPort"] = int64(monitor.ScrapePort)
+	}
+	if monitor.ScrapePath != "" {
+		endpoint["path"] = monitor.ScrapePath
+	}
+	if monitor.ScrapeScheme != "" {
+		endpoint["scheme"] = string(monitor.ScrapeScheme)
+	}
+	if tlsConfig := monitor.TLSConfig; tlsConfig != nil {
+		tls := map[string]interface{}{
+			"insecureSkipVerify": tlsConfig.InsecureSkipVerify,
+		}
+		if tlsConfig.CASecretRef != nil {
+			tls["ca"] = map[string]interface{}{
+				"secret": map[string]interface{}{
+					"name": tlsConfig.CASecretRef.Name,
+					"key":  tlsConfig.CASecretRef.Key,
+				},
+			}
+		}
+		endpoint["tlsConfig"] = tls
+	}
+
+	matchLabels := map[string]interface{}{}
+	for k, v := range labels {
+		matchLabels[k] = v
+	}
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": matchLabels,
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{synthesizedComp.Namespace},
+				},
+				"endpoints": []interface{}{endpoint},
+			},
+		},
+	}
+	obj.SetAPIVersion(constant.MonitoringAPIGroupVersion)
+	obj.SetKind(constant.ServiceMonitorKind)
+	obj.SetNamespace(synthesizedComp.Namespace)
+	obj.SetName(constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name))
+	obj.SetLabels(labels)
+	return obj
+}
+
+// getServiceMonitorPortName returns the name of the headless service port for the scrape port,
+// which is named after the container port by the headless service.
+func getServiceMonitorPortName(synthesizedComp *component.SynthesizedComponent) string {
+	if synthesizedComp.PodSpec == nil {
+		return ""
+	}
+	for _, c := range synthesizedComp.PodSpec.Containers {
+		for _, p := range c.Ports {
+			if p.ContainerPort != synthesizedComp.Monitor.ScrapePort {
+				continue
+			}
+			if len(p.Name) > 0 {
+				return p.Name
+			}
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			return fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), p.ContainerPort)
+		}
+	}
+	return ""
+}
@@ -20,16 +20,22 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package factory
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/slices"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -38,6 +44,7 @@ import (
 	cfgcm "github.com/apecloud/kubeblocks/pkg/configuration/config_manager"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/rsm"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -185,13 +192,15 @@ var _ = Describe("builder", func() {
 				clusterDefObj                             = testapps.NewClusterDefFactoryWithConnCredential("conn-cred", mysqlCompDefName).GetObject()
 				clusterDef, cluster, synthesizedComponent = newClusterObjs(clusterDefObj)
 			)
-			credential := BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			credential, err := BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			Expect(err).ShouldNot(HaveOccurred())
 			Expect(credential).ShouldNot(BeNil())
 			Expect(credential.Labels[constant.KBAppClusterDefTypeLabelKey]).Should(BeEmpty())
 			By("setting type")
 			characterType := "test-character-type"
 			clusterDef.Spec.Type = characterType
-			credential = BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			credential, err = BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			Expect(err).ShouldNot(HaveOccurred())
 			Expect(credential).ShouldNot(BeNil())
 			Expect(credential.Labels[constant.KBAppClusterDefTypeLabelKey]).Should(Equal(characterType))
 			// "username":      "root",
@@ -237,6 +246,43 @@ var _ = Describe("builder", func() {
 
 		})
 
+		It("builds Conn. Credential with component qualified FQDN placeholders", func() {
+			var (
+				clusterDefObj                             = testapps.NewClusterDefFactoryWithConnCredential("conn-cred", mysqlCompDefName).GetObject()
+				clusterDef, cluster, synthesizedComponent = newClusterObjs(clusterDefObj)
+			)
+			clusterDef.Spec.ConnectionCredential["COMP_SVC_FQDN"] = fmt.Sprintf("$(SVC_FQDN_%s)", mysqlCompDefName)
+			clusterDef.Spec.ConnectionCredential["COMP_HEADLESS_SVC_FQDN"] = fmt.Sprintf("$(HEADLESS_SVC_FQDN_%s)", mysqlCompDefName)
+			clusterDef.Spec.ConnectionCredential["compEndpoint"] = fmt.Sprintf("tcp:$(SVC_FQDN_%s):$(SVC_PORT_mysql)", mysqlCompDefName)
+			credential, err := BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(credential).ShouldNot(BeNil())
+
+			svcFQDN := fmt.Sprintf("%s-%s", cluster.Name, synthesizedComponent.Name)
+			headlessSvcFQDN := fmt.Sprintf("%s-%s-headless", cluster.Name, synthesizedComponent.Name)
+			Expect(credential.StringData["SVC_FQDN"]).Should(Equal(svcFQDN))
+			Expect(credential.StringData["HEADLESS_SVC_FQDN"]).Should(Equal(headlessSvcFQDN))
+			Expect(credential.StringData["COMP_SVC_FQDN"]).Should(Equal(svcFQDN))
+			Expect(credential.StringData["COMP_HEADLESS_SVC_FQDN"]).Should(Equal(headlessSvcFQDN))
+			Expect(credential.StringData["compEndpoint"]).Should(HavePrefix(fmt.Sprintf("tcp:%s:", svcFQDN)))
+
+			By("referring to a componentDef without service")
+			for i := range clusterDef.Spec.ComponentDefs {
+				if clusterDef.Spec.ComponentDefs[i].Name == mysqlCompDefName {
+					clusterDef.Spec.ComponentDefs[i].Service = nil
+				}
+			}
+			_, err = BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring(mysqlCompDefName))
+
+			By("referring to a non-existent componentDef")
+			clusterDef.Spec.ConnectionCredential["COMP_SVC_FQDN"] = "$(SVC_FQDN_non-existent)"
+			_, err = BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("non-existent"))
+		})
+
 		It("builds Conn. Credential during restoring from backup", func() {
 			originalPassword := "test-passw0rd"
 			encryptionKey := "encryptionKey"
@@ -249,11 +295,52 @@ var _ = Describe("builder", func() {
 			ciphertext, _ := e.Encrypt([]byte(originalPassword))
 			cluster.Annotations[constant.RestoreFromBackupAnnotationKey] = fmt.Sprintf(`{"%s":{"%s":"%s"}}`,
 				synthesizedComponent.Name, constant.ConnectionPassword, ciphertext)
-			credential := BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			credential, err := BuildConnCredential(clusterDef, cluster, synthesizedComponent)
+			Expect(err).ShouldNot(HaveOccurred())
 			Expect(credential).ShouldNot(BeNil())
 			Expect(credential.StringData["RANDOM_PASSWD"]).Should(Equal(originalPassword))
 		})
 
+		It("rotates Conn. Credential", func() {
+			var (
+				clusterDefObj                             = testapps.NewClusterDefFactoryWithConnCredential("conn-cred", mysqlCompDefName).GetObject()
+				clusterDef, cluster, synthesizedComponent = newClusterObjs(clusterDefObj)
+			)
+			clusterDef.Spec.ConnectionCredential["password"] = "$(CONN_CREDENTIAL).RANDOM_PASSWD"
+			credential, err := BuildConnCredential(clusterDef.DeepCopy(), cluster, synthesizedComponent)
+			Expect(err).ShouldNot(HaveOccurred())
+			credential.Data = map[string][]byte{}
+			for k, v := range credential.StringData {
+				credential.Data[k] = []byte(v)
+			}
+
+			By("regenerating the random entries only")
+			rotated, err := RotateConnCredential(clusterDef.DeepCopy(), cluster, synthesizedComponent, credential)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rotated).Should(HaveLen(3))
+			Expect(rotated["RANDOM_PASSWD"]).Should(HaveLen(8))
+			Expect(rotated["RANDOM_PASSWD"]).ShouldNot(Equal(credential.StringData["RANDOM_PASSWD"]))
+			Expect(rotated["STRONG_RANDOM_PASSWD"]).ShouldNot(Equal(credential.StringData["STRONG_RANDOM_PASSWD"]))
+			Expect(rotated["password"]).Should(Equal(rotated["RANDOM_PASSWD"]))
+
+			By("ignoring the password specified during recovery")
+			encryptionKey := "encryptionKey"
+			viper.Set(constant.CfgKeyDPEncryptionKey, encryptionKey)
+			ciphertext, _ := intctrlutil.NewEncryptor(encryptionKey).Encrypt([]byte("test-passw0rd"))
+			cluster.Annotations[constant.RestoreFromBackupAnnotationKey] = fmt.Sprintf(`{"%s":{"%s":"%s"}}`,
+				synthesizedComponent.Name, constant.ConnectionPassword, ciphertext)
+			rotated, err = RotateConnCredential(clusterDef.DeepCopy(), cluster, synthesizedComponent, credential)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rotated["RANDOM_PASSWD"]).ShouldNot(Equal("test-passw0rd"))
+
+			By("nothing to rotate without random entries")
+			delete(clusterDef.Spec.ConnectionCredential, "RANDOM_PASSWD")
+			delete(clusterDef.Spec.ConnectionCredential, "STRONG_RANDOM_PASSWD")
+			rotated, err = RotateConnCredential(clusterDef, cluster, synthesizedComponent, credential)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(rotated).Should(BeNil())
+		})
+
 		It("builds RSM correctly", func() {
 			clusterDef, cluster, synthesizedComponent := newClusterObjs(nil)
 
@@ -453,3 +540,182 @@ var _ = Describe("builder", func() {
 		})
 	})
 })
+
+func TestBuildConnCredentialWithSeed(t *testing.T) {
+	const (
+		compDefName = "replicasets"
+		seed        = "test-connection-credential-seed"
+	)
+	clusterDef := testapps.NewClusterDefFactoryWithConnCredential("conn-cred", compDefName).GetObject()
+	clusterDef.Spec.ConnectionCredential["password"] = "$(CONN_CREDENTIAL).STRONG_RANDOM_PASSWD"
+	newCluster := func(seed string) *appsv1alpha1.Cluster {
+		cluster := testapps.NewClusterFactory("default", "test-cluster", clusterDef.Name, "").
+			AddComponent("mysql", compDefName).
+			GetObject()
+		if seed != "" {
+			cluster.Annotations = map[string]string{constant.ConnCredentialSeedAnnotationKey: seed}
+		}
+		return cluster
+	}
+	synthesizedComp := &component.SynthesizedComponent{Name: "mysql", ClusterCompDefName: compDefName}
+	render := func(cluster *appsv1alpha1.Cluster) ([]byte, map[string]string) {
+		credential, err := BuildConnCredential(clusterDef.DeepCopy(), cluster, synthesizedComp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		data, err := json.Marshal(credential)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return data, credential.StringData
+	}
+
+	first, stringData := render(newCluster(seed))
+	second, _ := render(newCluster(seed))
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected identical connection credentials with the same seed, got:\n%s\n%s", first, second)
+	}
+	if bytes.Contains(first, []byte(seed)) {
+		t.Errorf("the seed is written into the connection credential: %s", first)
+	}
+	if len(stringData["RANDOM_PASSWD"]) != 8 || len(stringData["STRONG_RANDOM_PASSWD"]) != 16 {
+		t.Errorf("unexpected password lengths: %v", stringData)
+	}
+	if stringData["password"] != stringData["STRONG_RANDOM_PASSWD"] {
+		t.Errorf("expected the referred password to be rendered, got %s", stringData["password"])
+	}
+	if u, err := uuid.Parse(stringData["UUID"]); err != nil || u.Version() != 4 {
+		t.Errorf("expected a valid UUID v4, got %s", stringData["UUID"])
+	}
+	if stringData["UUID_HEX"] != strings.ReplaceAll(stringData["UUID"], "-", "") {
+		t.Errorf("expected the UUID placeholders to be derived from the same UUID, got %v", stringData)
+	}
+
+	another, _ := render(newCluster("another-seed"))
+	if bytes.Equal(first, another) {
+		t.Errorf("expected different connection credentials with different seeds")
+	}
+	random, _ := render(newCluster(""))
+	if bytes.Equal(random, second) {
+		t.Errorf("expected random connection credentials without the seed")
+	}
+
+	// the seed is not used by rotation
+	existing := &corev1.Secret{Data: map[string][]byte{}}
+	for k, v := range stringData {
+		existing.Data[k] = []byte(v)
+	}
+	rotated, err := RotateConnCredential(clusterDef.DeepCopy(), newCluster(seed), synthesizedComp, existing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated["RANDOM_PASSWD"] == stringData["RANDOM_PASSWD"] || rotated["STRONG_RANDOM_PASSWD"] == stringData["STRONG_RANDOM_PASSWD"] {
+		t.Errorf("expected the passwords to be rotated, got %v", rotated)
+	}
+}
+
+func TestBuildServiceMonitor(t *testing.T) {
+	synthesizedComp := &component.SynthesizedComponent{
+		Namespace:   "default",
+		ClusterName: "test-cluster",
+		Name:        "mysql",
+		PodSpec: &corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "mysql", Ports: []corev1.ContainerPort{{Name: "mysql", ContainerPort: 3306}}},
+				{Name: "exporter", Ports: []corev1.ContainerPort{{ContainerPort: 9104}}},
+			},
+		},
+		Monitor: &component.MonitorConfig{
+			Enable:         true,
+			ScrapePort:     9104,
+			ScrapeScheme:   appsv1alpha1.HTTPSScrapeScheme,
+			TLSConfig:      &appsv1alpha1.ExporterTLSConfig{InsecureSkipVerify: true},
+			ServiceMonitor: true,
+		},
+	}
+
+	serviceMonitor := BuildServiceMonitor(synthesizedComp)
+	if serviceMonitor.GetName() != "test-cluster-mysql" || serviceMonitor.GetKind() != constant.ServiceMonitorKind {
+		t.Errorf("unexpected ServiceMonitor: %s %s", serviceMonitor.GetKind(), serviceMonitor.GetName())
+	}
+	if serviceMonitor.GetLabels()[constant.KBAppComponentLabelKey] != "mysql" {
+		t.Errorf("expected the component labels, got %v", serviceMonitor.GetLabels())
+	}
+	endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	if len(endpoints) != 1 {
+		t.Fatalf("expected one endpoint, got %v", endpoints)
+	}
+	endpoint := endpoints[0].(map[string]interface{})
+	if endpoint["port"] != "tcp-9104" || endpoint["path"] != "/metrics" || endpoint["scheme"] != "https" {
+		t.Errorf("unexpected endpoint: %v", endpoint)
+	}
+	if skip, _, _ := unstructured.NestedBool(endpoint, "tlsConfig", "insecureSkipVerify"); !skip {
+		t.Errorf("expected the TLS config in the endpoint, got %v", endpoint)
+	}
+	if _, err := json.Marshal(serviceMonitor); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the scrape port is not declared by the containers
+	synthesizedComp.Monitor.ScrapePort = 9100
+	synthesizedComp.Monitor.ScrapePath = "/stats"
+	endpoints, _, _ = unstructured.NestedSlice(BuildServiceMonitor(synthesizedComp).Object, "spec", "endpoints")
+	endpoint = endpoints[0].(map[string]interface{})
+	if endpoint["targetPort"] != int64(9100) || endpoint["path"] != "/stats" {
+		t.Errorf("unexpected endpoint: %v", endpoint)
+	}
+}
+
+func TestGetMonitorAnnotationsWithServiceMonitor(t *testing.T) {
+	synthesizedComp := &component.SynthesizedComponent{
+		Monitor: &component.MonitorConfig{Enable: true, ScrapePort: 9104, ScrapePath: "/metrics", ServiceMonitor: true},
+	}
+	scrapeKey := rsm.AddAnnotationScope(rsm.HeadlessServiceScope, map[string]string{"monitor.kubeblocks.io/scrape": ""})
+
+	defer viper.Set(constant.CfgKeyServiceMonitorAPIEnabled, false)
+	for _, enabled := range []bool{false, true} {
+		viper.Set(constant.CfgKeyServiceMonitorAPIEnabled, enabled)
+		annotations := getMonitorAnnotations(synthesizedComp)
+		for key := range scrapeKey {
+			// fall back to the annotations if the ServiceMonitor CRD is not installed
+			if expected := strconv.FormatBool(!enabled); annotations[key] != expected {
+				t.Errorf("expected %s to be %s with ServiceMonitor API enabled %v, got %v", key, expected, enabled, annotations)
+			}
+		}
+	}
+}
+
+func TestBuildConnCredentialWithEscapedPlaceholder(t *testing.T) {
+	const compDefName = "replicasets"
+	clusterDef := testapps.NewClusterDefFactoryWithConnCredential("conn-cred", compDefName).GetObject()
+	clusterDef.Spec.ConnectionCredential = map[string]string{
+		"escaped":   "$$(RANDOM_PASSWD) costs $5",
+		"dollarPwd": "$$$(UUID)",
+		"literal":   "$$$$(SVC_FQDN)",
+	}
+	cluster := testapps.NewClusterFactory("default", "test-cluster", clusterDef.Name, "").
+		AddComponent("mysql", compDefName).
+		GetObject()
+	synthesizedComp := &component.SynthesizedComponent{Name: "mysql", ClusterCompDefName: compDefName}
+	credential, err := BuildConnCredential(clusterDef.DeepCopy(), cluster, synthesizedComp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := credential.StringData["escaped"]; v != "$(RANDOM_PASSWD) costs $5" {
+		t.Errorf("expected the escaped placeholder rendered as a literal string, got %q", v)
+	}
+	if v := credential.StringData["dollarPwd"]; !strings.HasPrefix(v, "$") || len(v) != len("$")+36 {
+		t.Errorf("expected a literal $ followed by the UUID, got %q", v)
+	}
+	if v := credential.StringData["literal"]; v != "$$(SVC_FQDN)" {
+		t.Errorf("expected the escaped dollars rendered as literals, got %q", v)
+	}
+
+	rotated, err := RotateConnCredential(clusterDef.DeepCopy(), cluster, synthesizedComp, credential)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rotated != nil {
+		t.Errorf("expected nothing to rotate for the escaped placeholders, got %v", rotated)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	apps "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -180,6 +181,9 @@ var _ = Describe("object deletion transformer test.", func() {
 					list.Items = []batchv1.Job{*action}
 					return nil
 				}).Times(1)
+			k8sMock.EXPECT().
+				List(gomock.Any(), &policyv1.PodDisruptionBudgetList{}, gomock.Any()).
+				Return(nil).Times(1)
 
 			Expect(transformer.Transform(transCtx, dag)).Should(Equal(graph.ErrPrematureStop))
 			dagExpected := mockDAG()
@@ -264,6 +268,9 @@ var _ = Describe("object deletion transformer test.", func() {
 				list.Items = []batchv1.Job{*action}
 				return nil
 			}).Times(1)
+		k8sMock.EXPECT().
+			List(gomock.Any(), &policyv1.PodDisruptionBudgetList{}, gomock.Any()).
+			Return(nil).Times(1)
 
 		Expect(transformer.Transform(transCtxForPods, dagForPods)).Should(Equal(graph.ErrPrematureStop))
 		dagExpected := mockDAGForPods()
@@ -0,0 +1,308 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package rsm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// LeaderEvictionTransformer switches the leadership over before the leader is evicted if the
+// leader eviction policy is SwitchoverFirst.
+//
+// A PodDisruptionBudget selecting the pod with the leader role blocks the eviction of the leader.
+// When the eviction of the leader is requested, which is recorded on the pod by the pod eviction webhook,
+// a switchover action is triggered, and the old leader can be evicted as soon as the role label moves
+// to another member.
+// If the leadership is not switched over within the leader eviction timeout, the PodDisruptionBudget
+// stops protecting the leader, that is, the leader is evicted directly.
+type LeaderEvictionTransformer struct {
+	// Clock is used to time the leader eviction, the real clock is used if it is nil.
+	Clock clock.PassiveClock
+}
+
+var _ graph.Transformer = &LeaderEvictionTransformer{}
+
+func (t *LeaderEvictionTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*rsmTransformContext)
+	rsm := transCtx.rsm
+	if model.IsObjectDeleting(transCtx.rsmOrig) {
+		return nil
+	}
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+
+	oldPDB := &policyv1.PodDisruptionBudget{}
+	pdbKey := client.ObjectKey{Namespace: rsm.Namespace, Name: getLeaderPDBName(rsm.Name)}
+	if err := transCtx.Client.Get(transCtx.Context, pdbKey, oldPDB); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		oldPDB = nil
+	}
+	if !isLeaderEvictionProtected(rsm) {
+		if oldPDB != nil {
+			graphCli.Delete(dag, oldPDB)
+		}
+		return nil
+	}
+
+	pdb := buildLeaderPDB(rsm)
+	waiting, err := handleLeaderEviction(transCtx, dag, oldPDB, pdb, t.now())
+	if err != nil {
+		return err
+	}
+	if oldPDB == nil {
+		if err = setOwnership(rsm, pdb, model.GetScheme(), getFinalizer(pdb)); err != nil {
+			return err
+		}
+		graphCli.Create(dag, pdb)
+	} else {
+		newPDB := oldPDB.DeepCopy()
+		newPDB.Labels = pdb.Labels
+		newPDB.Annotations = pdb.Annotations
+		newPDB.Spec = pdb.Spec
+		if !reflect.DeepEqual(oldPDB, newPDB) {
+			graphCli.Update(dag, oldPDB, newPDB)
+		}
+	}
+	if waiting {
+		return intctrlutil.NewDelayedRequeueError(leaderEvictionCheckInterval, "wait for the leader to be switched over before eviction")
+	}
+	return nil
+}
+
+func (t *LeaderEvictionTransformer) now() time.Time {
+	if t.Clock == nil {
+		return time.Now()
+	}
+	return t.Clock.Now()
+}
+
+// handleLeaderEviction triggers the switchover if the leader is going to be evicted, it returns true
+// if the leader is still protected and waiting for the switchover.
+func handleLeaderEviction(transCtx *rsmTransformContext, dag *graph.DAG, oldPDB, pdb *policyv1.PodDisruptionBudget, now time.Time) (bool, error) {
+	rsm := transCtx.rsm
+	leader := getLeaderPodName(rsm.Status.MembersStatus)
+	if len(leader) == 0 {
+		return false, nil
+	}
+	requested, err := isLeaderEvictionRequested(transCtx, rsm.Namespace, leader, now)
+	if err != nil || !requested {
+		return false, err
+	}
+
+	// the start time is kept as long as the same leader is waiting for eviction.
+	startTime := now
+	if oldPDB != nil && oldPDB.Annotations[leaderEvictionPodAnnotationKey] == leader {
+		if t, err := time.Parse(time.RFC3339, oldPDB.Annotations[leaderEvictionStartTimeAnnotationKey]); err == nil {
+			startTime = t
+		}
+	}
+	pdb.Annotations = map[string]string{
+		leaderEvictionPodAnnotationKey:       leader,
+		leaderEvictionStartTimeAnnotationKey: startTime.Format(time.RFC3339),
+	}
+
+	timeout := viper.GetDuration(LeaderEvictionTimeout)
+	if now.Sub(startTime) >= timeout {
+		minAvailable := intstr.FromInt(0)
+		pdb.Spec.MinAvailable = &minAvailable
+		if oldPDB == nil || oldPDB.Spec.MinAvailable == nil || oldPDB.Spec.MinAvailable.IntValue() > 0 {
+			transCtx.EventRecorder.Eventf(rsm, corev1.EventTypeWarning, "LeaderEvictionTimeout",
+				"the leadership of %s is not switched over within %s, evict it directly", leader, timeout)
+		}
+		return false, nil
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	ordinal, _ := getPodOrdinal(leader)
+	actionName := getActionName(rsm.Name, int(startTime.Unix()), ordinal, jobScenarioLeaderEviction)
+	action := &batchv1.Job{}
+	if err = transCtx.Client.Get(transCtx.Context, client.ObjectKey{Namespace: rsm.Namespace, Name: actionName}, action); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return true, err
+		}
+		target, err := selectLeaderEvictionTarget(transCtx, leader)
+		if err != nil || len(target) == 0 {
+			// no member is able to take over the leadership, wait for it until timeout.
+			return true, err
+		}
+		action = buildAction(rsm, actionName, jobTypeSwitchover, jobScenarioLeaderEviction, leader, target)
+		if err = createAction(dag, graphCli, rsm, action); err != nil {
+			return true, err
+		}
+		transCtx.EventRecorder.Eventf(rsm, corev1.EventTypeNormal, "LeaderEviction",
+			"the eviction of leader %s is requested, switch the leadership over to %s before eviction", leader, target)
+		return true, nil
+	}
+	if action.Labels[jobHandledLabel] == jobHandledFalse && (action.Status.Succeeded > 0 || action.Status.Failed > 0) {
+		if action.Status.Failed > 0 {
+			emitActionFailedEvent(transCtx, jobTypeSwitchover, action.Name)
+		}
+		doActionCleanup(dag, graphCli, action)
+	}
+	// wait for the role label to move to another member, even if the action has failed.
+	return true, nil
+}
+
+// selectLeaderEvictionTarget selects a ready member with role which is not going to be evicted as the new leader.
+func selectLeaderEvictionTarget(transCtx *rsmTransformContext, leader string) (string, error) {
+	rsm := transCtx.rsm
+	podList := &corev1.PodList{}
+	if err := transCtx.Client.List(transCtx.Context, podList, client.InNamespace(rsm.Namespace),
+		client.MatchingLabels(getSvcSelector(rsm, true))); err != nil {
+		return "", err
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Name == leader || len(pod.Labels[roleLabelKey]) == 0 || !intctrlutil.PodIsReady(pod) {
+			continue
+		}
+		draining, err := isPodOnUnschedulableNode(transCtx, rsm.Namespace, pod.Name)
+		if err != nil {
+			return "", err
+		}
+		if !draining {
+			return pod.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// isLeaderEvictionRequested checks whether the eviction of the leader is requested recently. The eviction
+// is retried by the requester, e.g. kubectl drain, as long as it is blocked by the PodDisruptionBudget,
+// so the request expires if it is not retried within the TTL.
+func isLeaderEvictionRequested(transCtx *rsmTransformContext, namespace, podName string, now time.Time) (bool, error) {
+	pod := &corev1.Pod{}
+	if err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Namespace: namespace, Name: podName}, pod); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	requestTime, err := time.Parse(time.RFC3339, pod.Annotations[leaderEvictionRequestedAnnotationKey])
+	if err != nil {
+		return false, nil
+	}
+	return now.Sub(requestTime) < leaderEvictionRequestTTL, nil
+}
+
+// RecordLeaderEvictionRequest records the eviction request of the pod if it is the leader of an RSM whose
+// leader eviction policy is SwitchoverFirst, the RSM is reconciled to switch the leadership over then.
+func RecordLeaderEvictionRequest(ctx context.Context, cli client.Client, namespace, podName string, now time.Time) error {
+	pod := &corev1.Pod{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: podName}, pod); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if len(pod.Labels[roleLabelKey]) == 0 {
+		return nil
+	}
+	rsmName := getRSMNameOfPod(pod)
+	if len(rsmName) == 0 {
+		return nil
+	}
+	rsm := &workloads.ReplicatedStateMachine{}
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: rsmName}, rsm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !isLeaderEvictionProtected(rsm) || getLeaderPodName(rsm.Status.MembersStatus) != pod.Name {
+		return nil
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[leaderEvictionRequestedAnnotationKey] = now.Format(time.RFC3339)
+	return cli.Patch(ctx, pod, patch)
+}
+
+// getRSMNameOfPod gets the name of the RSM which owns the pod directly, or owns the statefulset with
+// the same name which owns the pod.
+func getRSMNameOfPod(pod *corev1.Pod) string {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "StatefulSet" || owner.Kind == workloads.ReplicatedStateMachineKind {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+func isPodOnUnschedulableNode(transCtx *rsmTransformContext, namespace, podName string) (bool, error) {
+	pod := &corev1.Pod{}
+	if err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Namespace: namespace, Name: podName}, pod); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if len(pod.Spec.NodeName) == 0 {
+		return false, nil
+	}
+	node := &corev1.Node{}
+	if err := transCtx.Client.Get(transCtx.Context, client.ObjectKey{Name: pod.Spec.NodeName}, node); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return node.Spec.Unschedulable, nil
+}
+
+// isLeaderEvictionProtected checks whether the leader should be switched over before eviction.
+func isLeaderEvictionProtected(rsm *workloads.ReplicatedStateMachine) bool {
+	if rsm.Spec.LeaderEvictionPolicy != workloads.SwitchoverFirstLeaderEviction {
+		return false
+	}
+	reconfiguration := rsm.Spec.MembershipReconfiguration
+	if reconfiguration == nil || reconfiguration.SwitchoverAction == nil {
+		return false
+	}
+	_, ok := getSvcSelector(rsm, false)[roleLabelKey]
+	return ok
+}
+
+func buildLeaderPDB(rsm *workloads.ReplicatedStateMachine) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(1)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rsm.Namespace,
+			Name:      getLeaderPDBName(rsm.Name),
+			Labels:    getLabels(rsm),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: getSvcSelector(rsm, false),
+			},
+			MinAvailable: &minAvailable,
+		},
+	}
+}
+
+func getLeaderPDBName(rsmName string) string {
+	return fmt.Sprintf("%s-leader", rsmName)
+}
@@ -0,0 +1,300 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package rsm
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+var _ = Describe("leader eviction transformer test.", func() {
+	const (
+		leaderNode   = "node-leader"
+		followerNode = "node-follower"
+	)
+
+	var (
+		leaderPod   *corev1.Pod
+		followerPod *corev1.Pod
+		now         = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	notFound := func(resource string) error {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: resource}, "")
+	}
+
+	expectGetPDB := func(pdb *policyv1.PodDisruptionBudget) {
+		k8sMock.EXPECT().
+			Get(gomock.Any(), gomock.Any(), &policyv1.PodDisruptionBudget{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ client.ObjectKey, obj *policyv1.PodDisruptionBudget, _ ...client.GetOption) error {
+				if pdb == nil {
+					return notFound("poddisruptionbudgets")
+				}
+				*obj = *pdb
+				return nil
+			}).Times(1)
+	}
+
+	expectGetPods := func(times int) {
+		pods := map[string]*corev1.Pod{leaderPod.Name: leaderPod, followerPod.Name: followerPod}
+		k8sMock.EXPECT().
+			Get(gomock.Any(), gomock.Any(), &corev1.Pod{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, objKey client.ObjectKey, obj *corev1.Pod, _ ...client.GetOption) error {
+				*obj = *pods[objKey.Name]
+				return nil
+			}).Times(times)
+	}
+
+	expectGetNodes := func(times int) {
+		k8sMock.EXPECT().
+			Get(gomock.Any(), gomock.Any(), &corev1.Node{}, gomock.Any()).
+			DoAndReturn(func(_ context.Context, objKey client.ObjectKey, obj *corev1.Node, _ ...client.GetOption) error {
+				obj.Name = objKey.Name
+				obj.Spec.Unschedulable = objKey.Name == leaderNode
+				return nil
+			}).Times(times)
+	}
+
+	requestLeaderEviction := func(requested time.Time) {
+		leaderPod.Annotations = map[string]string{leaderEvictionRequestedAnnotationKey: requested.Format(time.RFC3339)}
+	}
+
+	BeforeEach(func() {
+		rsm = builder.NewReplicatedStateMachineBuilder(namespace, name).
+			SetUID(uid).
+			AddMatchLabelsInMap(selectors).
+			SetReplicas(2).
+			SetRoles(roles).
+			SetMembershipReconfiguration(&reconfiguration).
+			SetLeaderEvictionPolicy(workloads.SwitchoverFirstLeaderEviction).
+			SetService(service).
+			GetObject()
+		rsm.Status.MembersStatus = []workloads.MemberStatus{
+			{
+				PodName:     getPodName(rsm.Name, 0),
+				ReplicaRole: workloads.ReplicaRole{Name: "leader", IsLeader: true},
+			},
+			{
+				PodName:     getPodName(rsm.Name, 1),
+				ReplicaRole: workloads.ReplicaRole{Name: "follower"},
+			},
+		}
+		readyCondition := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}
+		leaderPod = builder.NewPodBuilder(namespace, getPodName(rsm.Name, 0)).
+			AddLabelsInMap(selectors).
+			AddLabels(roleLabelKey, "leader").
+			SetNodeName(leaderNode).
+			GetObject()
+		leaderPod.Status.Conditions = []corev1.PodCondition{readyCondition}
+		followerPod = builder.NewPodBuilder(namespace, getPodName(rsm.Name, 1)).
+			AddLabelsInMap(selectors).
+			AddLabels(roleLabelKey, "follower").
+			SetNodeName(followerNode).
+			GetObject()
+		followerPod.Status.Conditions = []corev1.PodCondition{readyCondition}
+
+		transCtx = &rsmTransformContext{
+			Context:       ctx,
+			Client:        graphCli,
+			EventRecorder: record.NewFakeRecorder(10),
+			Logger:        logger,
+			rsmOrig:       rsm.DeepCopy(),
+			rsm:           rsm,
+		}
+		dag = mockDAG()
+		transformer = &LeaderEvictionTransformer{Clock: testingclock.NewFakePassiveClock(now)}
+		viper.Set(LeaderEvictionTimeout, time.Minute)
+	})
+
+	AfterEach(func() {
+		viper.Set(LeaderEvictionTimeout, nil)
+	})
+
+	findPDB := func() *policyv1.PodDisruptionBudget {
+		objs := graphCli.FindAll(dag, &policyv1.PodDisruptionBudget{})
+		Expect(objs).Should(HaveLen(1))
+		return objs[0].(*policyv1.PodDisruptionBudget)
+	}
+
+	Context("the leader eviction policy is Direct", func() {
+		It("should delete the PDB of the leader", func() {
+			rsm.Spec.LeaderEvictionPolicy = workloads.DirectLeaderEviction
+			pdb := buildLeaderPDB(rsm)
+			expectGetPDB(pdb)
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			dagExpected := mockDAG()
+			graphCli.Delete(dagExpected, pdb)
+			Expect(dag.Equals(dagExpected, less)).Should(BeTrue())
+		})
+	})
+
+	Context("the eviction of the leader is not requested", func() {
+		It("should protect the leader by a PDB", func() {
+			expectGetPDB(nil)
+			expectGetPods(1)
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			pdb := findPDB()
+			Expect(pdb.Name).Should(Equal(getLeaderPDBName(rsm.Name)))
+			Expect(pdb.Spec.Selector.MatchLabels).Should(HaveKeyWithValue(roleLabelKey, "leader"))
+			Expect(pdb.Spec.MinAvailable.IntValue()).Should(Equal(1))
+			Expect(pdb.Annotations).Should(BeEmpty())
+		})
+
+		It("should ignore the expired eviction request", func() {
+			requestLeaderEviction(now.Add(-leaderEvictionRequestTTL))
+			expectGetPDB(nil)
+			expectGetPods(1)
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			pdb := findPDB()
+			Expect(pdb.Spec.MinAvailable.IntValue()).Should(Equal(1))
+			Expect(pdb.Annotations).Should(BeEmpty())
+		})
+	})
+
+	Context("the eviction of the leader is requested", func() {
+		BeforeEach(func() {
+			requestLeaderEviction(now.Add(-time.Second))
+		})
+
+		It("should switch over before eviction", func() {
+			expectGetPDB(nil)
+			// the leader for the eviction request, and the follower for the switchover target.
+			expectGetPods(2)
+			expectGetNodes(1)
+			k8sMock.EXPECT().
+				Get(gomock.Any(), gomock.Any(), &batchv1.Job{}, gomock.Any()).
+				Return(notFound("jobs")).Times(1)
+			k8sMock.EXPECT().
+				List(gomock.Any(), &corev1.PodList{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, list *corev1.PodList, _ ...client.ListOption) error {
+					list.Items = []corev1.Pod{*leaderPod, *followerPod}
+					return nil
+				}).Times(1)
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			pdb := findPDB()
+			Expect(pdb.Spec.MinAvailable.IntValue()).Should(Equal(1))
+			Expect(pdb.Annotations).Should(HaveKeyWithValue(leaderEvictionPodAnnotationKey, leaderPod.Name))
+			Expect(pdb.Annotations).Should(HaveKeyWithValue(leaderEvictionStartTimeAnnotationKey, now.Format(time.RFC3339)))
+			actions := graphCli.FindAll(dag, &batchv1.Job{})
+			Expect(actions).Should(HaveLen(1))
+			Expect(actions[0].GetLabels()).Should(HaveKeyWithValue(jobTypeLabel, jobTypeSwitchover))
+			Expect(actions[0].GetLabels()).Should(HaveKeyWithValue(jobScenarioLabel, jobScenarioLeaderEviction))
+		})
+
+		buildWaitingPDB := func(waited time.Duration) *policyv1.PodDisruptionBudget {
+			pdb := buildLeaderPDB(rsm)
+			pdb.Annotations = map[string]string{
+				leaderEvictionPodAnnotationKey:       leaderPod.Name,
+				leaderEvictionStartTimeAnnotationKey: now.Add(-waited).Format(time.RFC3339),
+			}
+			return pdb
+		}
+
+		It("should keep protecting the leader within the timeout", func() {
+			oldPDB := buildWaitingPDB(time.Minute - time.Second)
+			expectGetPDB(oldPDB)
+			expectGetPods(1)
+			k8sMock.EXPECT().
+				Get(gomock.Any(), gomock.Any(), &batchv1.Job{}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, objKey client.ObjectKey, obj *batchv1.Job, _ ...client.GetOption) error {
+					obj.Name = objKey.Name
+					obj.Labels = map[string]string{jobHandledLabel: jobHandledFalse}
+					return nil
+				}).Times(1)
+
+			err := transformer.Transform(transCtx, dag)
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			// the PDB is kept as is, that is, the leader is still protected since the same start time.
+			Expect(graphCli.FindAll(dag, &policyv1.PodDisruptionBudget{})).Should(BeEmpty())
+			Expect(graphCli.FindAll(dag, &batchv1.Job{})).Should(BeEmpty())
+		})
+
+		It("should evict the leader directly once the timeout expires", func() {
+			oldPDB := buildWaitingPDB(time.Minute)
+			expectGetPDB(oldPDB)
+			expectGetPods(1)
+
+			Expect(transformer.Transform(transCtx, dag)).Should(Succeed())
+			pdb := findPDB()
+			Expect(pdb.Spec.MinAvailable.IntValue()).Should(Equal(0))
+			Expect(pdb.Annotations).Should(Equal(oldPDB.Annotations))
+			Expect(transCtx.EventRecorder.(*record.FakeRecorder).Events).Should(Receive(ContainSubstring("LeaderEvictionTimeout")))
+		})
+	})
+
+	Context("recording the eviction request", func() {
+		ownedPod := func(pod *corev1.Pod) *corev1.Pod {
+			pod = pod.DeepCopy()
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: "StatefulSet", Name: rsm.Name}}
+			return pod
+		}
+
+		getRequestTime := func(cli client.Client, podName string) string {
+			pod := &corev1.Pod{}
+			Expect(cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: podName}, pod)).Should(Succeed())
+			return pod.Annotations[leaderEvictionRequestedAnnotationKey]
+		}
+
+		It("should record the eviction request of the leader only", func() {
+			cli := fake.NewClientBuilder().WithScheme(model.GetScheme()).
+				WithObjects(rsm, ownedPod(leaderPod), ownedPod(followerPod)).Build()
+
+			Expect(RecordLeaderEvictionRequest(ctx, cli, namespace, leaderPod.Name, now)).Should(Succeed())
+			Expect(getRequestTime(cli, leaderPod.Name)).Should(Equal(now.Format(time.RFC3339)))
+			Expect(RecordLeaderEvictionRequest(ctx, cli, namespace, followerPod.Name, now)).Should(Succeed())
+			Expect(getRequestTime(cli, followerPod.Name)).Should(BeEmpty())
+		})
+
+		It("should not record the eviction request if the leader eviction policy is Direct", func() {
+			rsm.Spec.LeaderEvictionPolicy = workloads.DirectLeaderEviction
+			cli := fake.NewClientBuilder().WithScheme(model.GetScheme()).
+				WithObjects(rsm, ownedPod(leaderPod)).Build()
+
+			Expect(RecordLeaderEvictionRequest(ctx, cli, namespace, leaderPod.Name, now)).Should(Succeed())
+			Expect(getRequestTime(cli, leaderPod.Name)).Should(BeEmpty())
+		})
+	})
+})
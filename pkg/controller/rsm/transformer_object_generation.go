@@ -328,6 +328,12 @@ func buildHeadlessSvc(rsm workloads.ReplicatedStateMachine) *corev1.Service {
 		AddSelectorsInMap(selectors).
 		AddAnnotationsInMap(annotations).
 		SetPublishNotReadyAddresses(true)
+	if rsm.Spec.HeadlessService != nil {
+		if rsm.Spec.HeadlessService.PublishNotReadyAddresses != nil {
+			hdlBuilder.SetPublishNotReadyAddresses(*rsm.Spec.HeadlessService.PublishNotReadyAddresses)
+		}
+		hdlBuilder.SetSessionAffinity(rsm.Spec.HeadlessService.SessionAffinity)
+	}
 
 	for _, container := range rsm.Spec.Template.Spec.Containers {
 		for _, port := range container.Ports {
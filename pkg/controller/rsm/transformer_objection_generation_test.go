@@ -229,6 +229,26 @@ var _ = Describe("object generation transformer test.", func() {
 		})
 	})
 
+	Context("buildHeadlessSvc function", func() {
+		It("should work well", func() {
+			svc := buildHeadlessSvc(*rsm)
+			Expect(svc.Name).Should(Equal(headlessSvcName))
+			Expect(svc.Spec.PublishNotReadyAddresses).Should(BeTrue())
+
+			By("customize the headless service")
+			publishNotReadyAddresses := false
+			rsm.Spec.ServiceName = name + "-hs"
+			rsm.Spec.HeadlessService = &workloads.HeadlessService{
+				PublishNotReadyAddresses: &publishNotReadyAddresses,
+				SessionAffinity:          corev1.ServiceAffinityClientIP,
+			}
+			svc = buildHeadlessSvc(*rsm)
+			Expect(svc.Name).Should(Equal(name + "-hs"))
+			Expect(svc.Spec.PublishNotReadyAddresses).Should(BeFalse())
+			Expect(svc.Spec.SessionAffinity).Should(Equal(corev1.ServiceAffinityClientIP))
+		})
+	})
+
 	Context("well-known service labels", func() {
 		It("should work well", func() {
 			svc := buildSvc(*rsm)
@@ -21,6 +21,7 @@ package rsm
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/client-go/tools/record"
@@ -45,6 +46,10 @@ const (
 
 	FeatureGateRSMToPod = "RSM_TO_POD"
 
+	// LeaderEvictionTimeout is the key of the timeout to wait for the leadership to be switched over before the
+	// leader is evicted directly, it only takes effect if the leader eviction policy is SwitchoverFirst.
+	LeaderEvictionTimeout = "RSM_LEADER_EVICTION_TIMEOUT"
+
 	workloadsManagedByLabelKey = "workloads.kubeblocks.io/managed-by"
 	workloadsInstanceLabelKey  = "workloads.kubeblocks.io/instance"
 
@@ -70,6 +75,13 @@ const (
 	jobTypePromote              = "promote"
 	jobScenarioMembership       = "membership-reconfiguration"
 	jobScenarioUpdate           = "pod-update"
+	jobScenarioLeaderEviction   = "leader-eviction"
+
+	leaderEvictionPodAnnotationKey       = "rsm.workloads.kubeblocks.io/leader-eviction-pod"
+	leaderEvictionStartTimeAnnotationKey = "rsm.workloads.kubeblocks.io/leader-eviction-start-time"
+	leaderEvictionRequestedAnnotationKey = "rsm.workloads.kubeblocks.io/leader-eviction-requested"
+	leaderEvictionCheckInterval          = 5 * time.Second
+	leaderEvictionRequestTTL             = time.Minute
 
 	roleProbeContainerName       = "kb-role-probe"
 	roleProbeBinaryName          = "lorry"
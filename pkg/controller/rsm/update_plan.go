@@ -21,8 +21,11 @@ package rsm
 
 import (
 	"errors"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/util/podutils"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
@@ -43,6 +46,8 @@ type realUpdatePlan struct {
 	pods            []corev1.Pod
 	dag             *graph.DAG
 	podsToBeUpdated []*corev1.Pod
+	// requeueAfter is the time to wait for the updated pods to be available
+	requeueAfter time.Duration
 }
 
 var _ updatePlan = &realUpdatePlan{}
@@ -72,11 +77,17 @@ func (p *realUpdatePlan) planWalkFunc(vertex graph.Vertex) error {
 
 	// if pod is the latest version, we do nothing
 	if intctrlutil.GetPodRevision(pod) == p.rsm.Status.UpdateRevision {
-		if intctrlutil.PodIsReadyWithLabel(*pod) {
-			return ErrContinue
-		} else {
+		if !intctrlutil.PodIsReadyWithLabel(*pod) {
 			return ErrWait
 		}
+		// wait the pod to be available for minReadySeconds before updating the next one
+		if waitTime := p.waitTimeToBeAvailable(pod); waitTime > 0 {
+			if p.requeueAfter == 0 || waitTime < p.requeueAfter {
+				p.requeueAfter = waitTime
+			}
+			return ErrWait
+		}
+		return ErrContinue
 	}
 
 	// delete the pod to trigger associate StatefulSet to re-create it
@@ -109,6 +120,9 @@ func (p *realUpdatePlan) build() {
 }
 
 // unknown & empty & learner & 1/2 followers -> 1/2 followers -> leader
+//
+// if the maxUnavailable is specified, the followers are updated in batches of maxUnavailable instead of two halves:
+// unknown & empty & learner & maxUnavailable followers -> ... -> the rest followers -> leader
 func (p *realUpdatePlan) buildBestEffortParallelUpdatePlan(rolePriorityMap map[string]int) {
 	currentVertex, _ := model.FindRootVertex(p.dag)
 	preVertex := currentVertex
@@ -127,7 +141,7 @@ func (p *realUpdatePlan) buildBestEffortParallelUpdatePlan(rolePriorityMap map[s
 	}
 	preVertex = currentVertex
 
-	// append 1/2 followers
+	// append followers batch by batch
 	podList = podList[index:]
 	followerCount := 0
 	for _, pod := range podList {
@@ -136,31 +150,49 @@ func (p *realUpdatePlan) buildBestEffortParallelUpdatePlan(rolePriorityMap map[s
 			followerCount++
 		}
 	}
-	end := followerCount / 2
-	for i := 0; i < end; i++ {
-		vertex := &model.ObjectVertex{Obj: &podList[i]}
-		p.dag.AddConnect(preVertex, vertex)
-		currentVertex = vertex
+	for _, end := range p.followerBatches(followerCount) {
+		for i := 0; i < end; i++ {
+			vertex := &model.ObjectVertex{Obj: &podList[i]}
+			p.dag.AddConnect(preVertex, vertex)
+			currentVertex = vertex
+		}
+		podList = podList[end:]
+		preVertex = currentVertex
 	}
-	preVertex = currentVertex
 
-	// append the other 1/2 followers
-	podList = podList[end:]
-	end = followerCount - end
+	// append leader
+	end := len(podList)
 	for i := 0; i < end; i++ {
 		vertex := &model.ObjectVertex{Obj: &podList[i]}
 		p.dag.AddConnect(preVertex, vertex)
-		currentVertex = vertex
 	}
-	preVertex = currentVertex
+}
 
-	// append leader
-	podList = podList[end:]
-	end = len(podList)
-	for i := 0; i < end; i++ {
-		vertex := &model.ObjectVertex{Obj: &podList[i]}
-		p.dag.AddConnect(preVertex, vertex)
+// followerBatches splits the followers into batches which are updated one after another.
+func (p *realUpdatePlan) followerBatches(followerCount int) []int {
+	halves := []int{followerCount / 2, followerCount - followerCount/2}
+	if p.rsm.Spec.MaxUnavailable == nil {
+		return halves
+	}
+	replicas := int32(1)
+	if p.rsm.Spec.Replicas != nil {
+		replicas = *p.rsm.Spec.Replicas
+	}
+	var leaders int32
+	for _, role := range p.rsm.Spec.Roles {
+		if role.IsLeader {
+			leaders++
+		}
+	}
+	maxUnavailable, err := workloads.GetMaxUnavailableMembers(p.rsm.Spec.MaxUnavailable, replicas, leaders)
+	if err != nil {
+		return halves
 	}
+	var batches []int
+	for count := followerCount; count > 0; count -= int(maxUnavailable) {
+		batches = append(batches, min(count, int(maxUnavailable)))
+	}
+	return batches
 }
 
 // unknown & empty & leader & followers & learner
@@ -188,9 +220,29 @@ func (p *realUpdatePlan) execute() ([]*corev1.Pod, error) {
 		return nil, err
 	}
 
+	if len(p.podsToBeUpdated) == 0 && p.requeueAfter > 0 {
+		return nil, intctrlutil.NewDelayedRequeueError(p.requeueAfter, "wait for the updated pods to be available")
+	}
 	return p.podsToBeUpdated, nil
 }
 
+// waitTimeToBeAvailable returns how long to wait for the ready pod to be available,
+// zero means the pod is available already.
+func (p *realUpdatePlan) waitTimeToBeAvailable(pod *corev1.Pod) time.Duration {
+	minReadySeconds := p.rsm.Spec.MinReadySeconds
+	now := metav1.Now()
+	if minReadySeconds <= 0 || podutils.IsPodAvailable(pod, minReadySeconds, now) {
+		return 0
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			availableTime := cond.LastTransitionTime.Add(time.Duration(minReadySeconds) * time.Second)
+			return availableTime.Sub(now.Time)
+		}
+	}
+	return time.Duration(minReadySeconds) * time.Second
+}
+
 func newUpdatePlan(rsm workloads.ReplicatedStateMachine, pods []corev1.Pod) updatePlan {
 	return &realUpdatePlan{
 		rsm:  rsm,
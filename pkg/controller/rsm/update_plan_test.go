@@ -20,15 +20,20 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package rsm
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
 var _ = Describe("update plan test.", func() {
@@ -157,5 +162,113 @@ var _ = Describe("update plan test.", func() {
 			}
 			checkPlan(expectedPlan)
 		})
+
+		It("should wait the updated pod to be available in a serial plan", func() {
+			By("build a serial plan with minReadySeconds")
+			strategy := workloads.SerialUpdateStrategy
+			rsm.Spec.MemberUpdateStrategy = &strategy
+			rsm.Spec.MinReadySeconds = 10
+			podUpdateList, err := newUpdatePlan(*rsm, buildPodList()).execute()
+			Expect(err).Should(BeNil())
+			Expect(equalPodList(toPodList(podUpdateList), toPodList([]*corev1.Pod{pod4}))).Should(BeTrue())
+
+			By("the updated pod is ready but not available yet")
+			makePodUpdateReady(newRevision, pod4)
+			pod4.Status.Conditions[0].LastTransitionTime = metav1.Now()
+			podUpdateList, err = newUpdatePlan(*rsm, buildPodList()).execute()
+			Expect(intctrlutil.IsDelayedRequeueError(err)).Should(BeTrue())
+			Expect(err.(intctrlutil.RequeueError).RequeueAfter()).Should(BeNumerically("<=", 10*time.Second))
+			Expect(podUpdateList).Should(BeEmpty())
+
+			By("the updated pod is available")
+			pod4.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-11 * time.Second))
+			podUpdateList, err = newUpdatePlan(*rsm, buildPodList()).execute()
+			Expect(err).Should(BeNil())
+			Expect(equalPodList(toPodList(podUpdateList), toPodList([]*corev1.Pod{pod2}))).Should(BeTrue())
+		})
+	})
+
+	Context("best effort parallel plan with maxUnavailable", func() {
+		replicationRoles := []workloads.ReplicaRole{
+			{Name: "primary", IsLeader: true, CanVote: true, AccessMode: workloads.ReadWriteMode},
+			{Name: "secondary", CanVote: true, AccessMode: workloads.ReadonlyMode},
+		}
+
+		buildPods := func(replicas int) []*corev1.Pod {
+			var pods []*corev1.Pod
+			for i := 0; i < replicas; i++ {
+				role := "secondary"
+				if i == 0 {
+					role = "primary"
+				}
+				pods = append(pods, builder.NewPodBuilder(namespace, getPodName(name, i)).
+					AddLabels(roleLabelKey, role).
+					AddLabels(apps.StatefulSetRevisionLabel, oldRevision).
+					GetObject())
+			}
+			return pods
+		}
+
+		podNames := func(pods []*corev1.Pod) []string {
+			var names []string
+			for _, pod := range pods {
+				names = append(names, pod.Name)
+			}
+			return names
+		}
+
+		checkPlan := func(replicas int, maxUnavailable *intstr.IntOrString, expectedPlan [][]int) {
+			strategy := workloads.BestEffortParallelUpdateStrategy
+			rsm = builder.NewReplicatedStateMachineBuilder(namespace, name).
+				SetReplicas(int32(replicas)).
+				SetRoles(replicationRoles).
+				SetMemberUpdateStrategy(&strategy).
+				GetObject()
+			rsm.Spec.MaxUnavailable = maxUnavailable
+			rsm.Status.UpdateRevision = newRevision
+			pods := buildPods(replicas)
+			for i, ordinals := range expectedPlan {
+				if i > 0 {
+					for _, ordinal := range expectedPlan[i-1] {
+						makePodUpdateReady(newRevision, pods[ordinal])
+					}
+				}
+				var podList []corev1.Pod
+				for _, pod := range pods {
+					podList = append(podList, *pod)
+				}
+				podUpdateList, err := newUpdatePlan(*rsm, podList).execute()
+				Expect(err).Should(BeNil())
+				var expectedPods []*corev1.Pod
+				for _, ordinal := range ordinals {
+					expectedPods = append(expectedPods, pods[ordinal])
+				}
+				Expect(podNames(podUpdateList)).Should(ConsistOf(podNames(expectedPods)))
+			}
+		}
+
+		It("should update the secondaries in batches with 2 replicas", func() {
+			maxUnavailable := intstr.FromInt(1)
+			checkPlan(2, &maxUnavailable, [][]int{{1}, {0}})
+		})
+
+		It("should update the secondaries in batches with 3 replicas", func() {
+			maxUnavailable := intstr.FromInt(1)
+			checkPlan(3, &maxUnavailable, [][]int{{1}, {2}, {0}})
+		})
+
+		It("should update the secondaries in batches with 5 replicas", func() {
+			maxUnavailable := intstr.FromInt(3)
+			checkPlan(5, &maxUnavailable, [][]int{{1, 2, 3}, {4}, {0}})
+		})
+
+		It("should never update the primary together with the secondaries", func() {
+			maxUnavailable := intstr.FromString("100%")
+			checkPlan(5, &maxUnavailable, [][]int{{1, 2, 3, 4}, {0}})
+		})
+
+		It("should update the secondaries in two halves if maxUnavailable is not set", func() {
+			checkPlan(5, nil, [][]int{{1, 2}, {3, 4}, {0}})
+		})
 	})
 })
@@ -31,6 +31,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -220,7 +221,7 @@ func ownedKinds(policy workloads.RsmTransformPolicy) []client.ObjectList {
 
 func deletionKinds(policy workloads.RsmTransformPolicy) []client.ObjectList {
 	kinds := ownedKinds(policy)
-	kinds = append(kinds, &batchv1.JobList{})
+	kinds = append(kinds, &batchv1.JobList{}, &policyv1.PodDisruptionBudgetList{})
 	return kinds
 }
 
@@ -248,7 +249,12 @@ func getPodsOfStatefulSet(ctx context.Context, cli client.Reader, stsObj *appsv1
 	return pods, nil
 }
 
+// getHeadlessSvcName returns the name of the headless service which governs the pods, it's specified by
+// the service name of rsm, and defaults to {rsm.Name}-headless.
 func getHeadlessSvcName(rsm workloads.ReplicatedStateMachine) string {
+	if len(rsm.Spec.ServiceName) > 0 {
+		return rsm.Spec.ServiceName
+	}
 	return strings.Join([]string{rsm.Name, "headless"}, "-")
 }
 
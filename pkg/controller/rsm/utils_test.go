@@ -197,6 +197,10 @@ var _ = Describe("utils test", func() {
 	Context("getHeadlessSvcName function", func() {
 		It("should work well", func() {
 			Expect(getHeadlessSvcName(*rsm)).Should(Equal("bar-headless"))
+
+			By("the service name of rsm is used if specified")
+			rsm.Spec.ServiceName = "bar-hs"
+			Expect(getHeadlessSvcName(*rsm)).Should(Equal("bar-hs"))
 		})
 	})
 
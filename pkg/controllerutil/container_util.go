@@ -20,8 +20,13 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package controllerutil
 
 import (
+	"encoding/json"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
 func GetContainerByName(containers []corev1.Container, name string) (int, *corev1.Container) {
@@ -49,3 +54,18 @@ func InjectZeroResourcesLimitsIfEmpty(c *corev1.Container) {
 	safeSetLimitValue(corev1.ResourceCPU)
 	safeSetLimitValue(corev1.ResourceMemory)
 }
+
+// BuildCmdExecutorResources builds the resource requirements of the container spawned from a CmdExecutorConfig,
+// the cluster-wide default resources from config are used if no resources are specified.
+func BuildCmdExecutorResources(resources corev1.ResourceRequirements) (corev1.ResourceRequirements, error) {
+	if len(resources.Limits) > 0 || len(resources.Requests) > 0 {
+		return *resources.DeepCopy(), nil
+	}
+	defaultResources := corev1.ResourceRequirements{}
+	if val := viper.GetString(constant.CfgKeyCmdExecutorDefaultResources); val != "" {
+		if err := json.Unmarshal([]byte(val), &defaultResources); err != nil {
+			return defaultResources, err
+		}
+	}
+	return defaultResources, nil
+}
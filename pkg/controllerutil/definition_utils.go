@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+const referencedObjectPollInterval = time.Second
+
+// TerminalPhaseGetter is implemented by the definition objects, e.g. ClusterDefinition, ClusterVersion
+// and ComponentDefinition, which can be referenced once their status reaches one of the terminal phases.
+type TerminalPhaseGetter interface {
+	client.Object
+	GetTerminalPhaseStatus() appsv1alpha1.TerminalPhaseStatus
+}
+
+var (
+	_ TerminalPhaseGetter = &appsv1alpha1.ClusterDefinition{}
+	_ TerminalPhaseGetter = &appsv1alpha1.ClusterVersion{}
+	_ TerminalPhaseGetter = &appsv1alpha1.ComponentDefinition{}
+)
+
+// CheckReferencedObjectAvailable checks whether the referenced object is available for its latest generation.
+// An object whose status has not observed the latest generation is treated as unavailable, even if
+// its phase is a terminal phase, as the phase may belong to the previous generation.
+// It returns an error of ErrorTypeReferencedDefinitionNotAvailable if the object is not available.
+func CheckReferencedObjectAvailable(obj TerminalPhaseGetter) error {
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	status := obj.GetTerminalPhaseStatus()
+	if status.GetObservedGeneration() != obj.GetGeneration() {
+		return NewErrorf(ErrorTypeReferencedDefinitionNotAvailable,
+			"the referenced %s %s is being updated, observed generation: %d, generation: %d",
+			kind, obj.GetName(), status.GetObservedGeneration(), obj.GetGeneration())
+	}
+	if !slices.Contains(status.GetTerminalPhases(), status.GetPhase()) {
+		return NewErrorf(ErrorTypeReferencedDefinitionNotAvailable,
+			"the referenced %s %s is unavailable, phase: %s, message: %s",
+			kind, obj.GetName(), status.GetPhase(), status.GetMessage())
+	}
+	return nil
+}
+
+// WaitForReferencedObjectAvailable gets the referenced object by its name and namespace, and waits
+// until it's available for its latest generation or the timeout expires. If the timeout is not positive,
+// the object is checked only once, which is the case for reconcilers that requeue instead of blocking.
+// The error of the last check is returned if the object is still unavailable.
+func WaitForReferencedObjectAvailable(ctx context.Context, cli client.Reader, obj TerminalPhaseGetter, timeout time.Duration) error {
+	key := client.ObjectKeyFromObject(obj)
+	check := func(ctx context.Context) error {
+		if err := cli.Get(ctx, key, obj); err != nil {
+			return err
+		}
+		return CheckReferencedObjectAvailable(obj)
+	}
+	if timeout <= 0 {
+		return check(ctx)
+	}
+
+	var checkErr error
+	err := wait.PollUntilContextTimeout(ctx, referencedObjectPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		checkErr = check(ctx)
+		if checkErr != nil && !IsTargetError(checkErr, ErrorTypeReferencedDefinitionNotAvailable) {
+			return false, checkErr
+		}
+		return checkErr == nil, nil
+	})
+	if err != nil && checkErr != nil {
+		return checkErr
+	}
+	return err
+}
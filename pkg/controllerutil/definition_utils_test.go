@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+func TestCheckReferencedObjectAvailable(t *testing.T) {
+	newClusterDef := func(generation, observedGeneration int64, phase appsv1alpha1.Phase) *appsv1alpha1.ClusterDefinition {
+		return &appsv1alpha1.ClusterDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cd", Generation: generation},
+			Status: appsv1alpha1.ClusterDefinitionStatus{
+				ObservedGeneration: observedGeneration,
+				Phase:              phase,
+				Message:            "test message",
+			},
+		}
+	}
+
+	assert.NoError(t, CheckReferencedObjectAvailable(newClusterDef(2, 2, appsv1alpha1.AvailablePhase)))
+
+	err := CheckReferencedObjectAvailable(newClusterDef(2, 2, appsv1alpha1.UnavailablePhase))
+	assert.True(t, IsTargetError(err, ErrorTypeReferencedDefinitionNotAvailable))
+	assert.Contains(t, err.Error(), "ClusterDefinition test-cd")
+	assert.Contains(t, err.Error(), "test message")
+
+	// the phase of the previous generation is not trusted
+	err = CheckReferencedObjectAvailable(newClusterDef(3, 2, appsv1alpha1.AvailablePhase))
+	assert.True(t, IsTargetError(err, ErrorTypeReferencedDefinitionNotAvailable))
+	assert.Contains(t, err.Error(), "being updated")
+
+	compDef := &appsv1alpha1.ComponentDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cmpd", Generation: 1},
+		Status:     appsv1alpha1.ComponentDefinitionStatus{ObservedGeneration: 1},
+	}
+	err = CheckReferencedObjectAvailable(compDef)
+	assert.True(t, IsTargetError(err, ErrorTypeReferencedDefinitionNotAvailable))
+	assert.Contains(t, err.Error(), "ComponentDefinition test-cmpd")
+}
+
+func TestWaitForReferencedObjectAvailable(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, appsv1alpha1.AddToScheme(scheme))
+	cv := &appsv1alpha1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cv", Generation: 1},
+		Status: appsv1alpha1.ClusterVersionStatus{
+			ObservedGeneration: 1,
+			Phase:              appsv1alpha1.UnavailablePhase,
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cv).Build()
+	ctx := context.Background()
+
+	obj := &appsv1alpha1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "test-cv"}}
+	err := WaitForReferencedObjectAvailable(ctx, cli, obj, 0)
+	assert.True(t, IsTargetError(err, ErrorTypeReferencedDefinitionNotAvailable))
+	assert.Equal(t, appsv1alpha1.UnavailablePhase, obj.Status.Phase)
+
+	err = WaitForReferencedObjectAvailable(ctx, cli, obj, 2*time.Second)
+	assert.True(t, IsTargetError(err, ErrorTypeReferencedDefinitionNotAvailable))
+
+	cv.Status.Phase = appsv1alpha1.AvailablePhase
+	assert.NoError(t, cli.Update(ctx, cv))
+	assert.NoError(t, WaitForReferencedObjectAvailable(ctx, cli, obj, 2*time.Second))
+
+	notFound := &appsv1alpha1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "not-found"}}
+	err = WaitForReferencedObjectAvailable(ctx, cli, notFound, 2*time.Second)
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -28,23 +28,59 @@ import (
 	"fmt"
 	"io"
 
+	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
 )
 
 const purposeEncryptionKey = "encryption"
 
+const (
+	// EncryptionAlgorithmAES256GCM is the default algorithm of the encryptor.
+	EncryptionAlgorithmAES256GCM = "AES-256-GCM"
+	// EncryptionAlgorithmChaCha20Poly1305 is the ChaCha20-Poly1305 algorithm.
+	EncryptionAlgorithmChaCha20Poly1305 = "ChaCha20-Poly1305"
+)
+
 type encryptor struct {
+	algorithm         string
 	encryptionKey     []byte
 	encryptionHashKey []byte
 	gcm               cipher.AEAD
 }
 
+// NewEncryptor creates an encryptor with the default algorithm AES-256-GCM.
 func NewEncryptor(encryptionKey string) *encryptor {
 	return &encryptor{
+		algorithm:     EncryptionAlgorithmAES256GCM,
 		encryptionKey: []byte(encryptionKey),
 	}
 }
 
+// NewEncryptorWithAlgorithm creates an encryptor with the specified algorithm,
+// the default algorithm AES-256-GCM is used if the algorithm is empty.
+func NewEncryptorWithAlgorithm(encryptionKey, algorithm string) (*encryptor, error) {
+	if algorithm == "" {
+		algorithm = EncryptionAlgorithmAES256GCM
+	}
+	if !IsSupportedEncryptionAlgorithm(algorithm) {
+		return nil, fmt.Errorf("unsupported encryption algorithm: %s", algorithm)
+	}
+	return &encryptor{
+		algorithm:     algorithm,
+		encryptionKey: []byte(encryptionKey),
+	}, nil
+}
+
+// IsSupportedEncryptionAlgorithm checks if the algorithm is supported by the encryptor.
+func IsSupportedEncryptionAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case EncryptionAlgorithmAES256GCM, EncryptionAlgorithmChaCha20Poly1305:
+		return true
+	default:
+		return false
+	}
+}
+
 func (e *encryptor) deriveKey() {
 	key := make([]byte, 32)
 	k := hkdf.New(sha256.New, e.encryptionKey, []byte(purposeEncryptionKey), nil)
@@ -54,15 +90,24 @@ func (e *encryptor) deriveKey() {
 
 func (e *encryptor) init() error {
 	e.deriveKey()
-	block, err := aes.NewCipher(e.encryptionHashKey)
-	if err != nil {
-		return err
+	var (
+		aead cipher.AEAD
+		err  error
+	)
+	switch e.algorithm {
+	case EncryptionAlgorithmChaCha20Poly1305:
+		aead, err = chacha20poly1305.New(e.encryptionHashKey)
+	default:
+		var block cipher.Block
+		if block, err = aes.NewCipher(e.encryptionHashKey); err != nil {
+			return err
+		}
+		aead, err = cipher.NewGCM(block)
 	}
-	gcm, err := cipher.NewGCM(block)
 	if err != nil {
 		return err
 	}
-	e.gcm = gcm
+	e.gcm = aead
 	return nil
 }
 
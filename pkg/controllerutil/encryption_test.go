@@ -41,3 +41,39 @@ func TestEncryptor(t *testing.T) {
 		}
 	}
 }
+
+func TestEncryptorWithAlgorithm(t *testing.T) {
+	secretKey := "dp-aes-test"
+	for _, algorithm := range []string{"", EncryptionAlgorithmAES256GCM, EncryptionAlgorithmChaCha20Poly1305} {
+		e, err := NewEncryptorWithAlgorithm(secretKey, algorithm)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		ciphertext, err := e.Encrypt([]byte("test-password"))
+		if err != nil {
+			t.Error(err.Error())
+		}
+		plaintext, err := e.Decrypt([]byte(ciphertext))
+		if err != nil {
+			t.Error(err.Error())
+		}
+		if plaintext != "test-password" {
+			t.Errorf("encrypt/decrypt value is incorrect for algorithm %s", algorithm)
+		}
+	}
+
+	// the ciphertext encrypted by the default encryptor should be decrypted by AES-256-GCM.
+	ciphertext, _ := NewEncryptor(secretKey).Encrypt([]byte("test-password"))
+	e, _ := NewEncryptorWithAlgorithm(secretKey, EncryptionAlgorithmAES256GCM)
+	if plaintext, err := e.Decrypt([]byte(ciphertext)); err != nil || plaintext != "test-password" {
+		t.Errorf("failed to decrypt the ciphertext of the default encryptor")
+	}
+	e, _ = NewEncryptorWithAlgorithm(secretKey, EncryptionAlgorithmChaCha20Poly1305)
+	if _, err := e.Decrypt([]byte(ciphertext)); err == nil {
+		t.Errorf("the ciphertext of AES-256-GCM should not be decrypted by ChaCha20-Poly1305")
+	}
+
+	if _, err := NewEncryptorWithAlgorithm(secretKey, "AES-128-CFB"); err == nil {
+		t.Errorf("unsupported algorithm should be rejected")
+	}
+}
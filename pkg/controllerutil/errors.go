@@ -54,6 +54,12 @@ const (
 	ErrorTypeRestoreFailed ErrorType = "RestoreFailed"
 	ErrorTypeNeedWaiting   ErrorType = "NeedWaiting" // waiting for next reconcile
 
+	// ErrorTypeReferencedDefinitionNotAvailable the referenced definition is not available for its latest generation.
+	ErrorTypeReferencedDefinitionNotAvailable ErrorType = "ReferencedDefinitionNotAvailable"
+
+	// ErrorType for backup controller
+	ErrorTypeDeadlineExceeded ErrorType = "DeadlineExceeded"
+
 	// ErrorType for preflight
 	ErrorTypePreflightCommon = "PreflightCommon"
 	ErrorTypeSkipPreflight   = "SkipPreflight"
@@ -0,0 +1,159 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// PasswordGenerator generates the passwords of the system accounts.
+type PasswordGenerator interface {
+	Generate(config appsv1alpha1.PasswordConfig) (string, error)
+}
+
+var (
+	passwordGeneratorsMutex sync.RWMutex
+	passwordGenerators      = map[appsv1alpha1.PasswordGeneratorType]PasswordGenerator{
+		appsv1alpha1.DefaultPasswordGenerator: &defaultPasswordGenerator{},
+		appsv1alpha1.KmsPasswordGenerator:     &kmsPasswordGenerator{},
+	}
+)
+
+// RegisterPasswordGenerator registers the password generator with the name, the existing one is replaced.
+func RegisterPasswordGenerator(name appsv1alpha1.PasswordGeneratorType, generator PasswordGenerator) {
+	passwordGeneratorsMutex.Lock()
+	defer passwordGeneratorsMutex.Unlock()
+	passwordGenerators[name] = generator
+}
+
+// GetPasswordGenerator returns the password generator registered with the name,
+// the default generator is returned if the name is empty.
+func GetPasswordGenerator(name appsv1alpha1.PasswordGeneratorType) (PasswordGenerator, error) {
+	if name == "" {
+		name = appsv1alpha1.DefaultPasswordGenerator
+	}
+	passwordGeneratorsMutex.RLock()
+	defer passwordGeneratorsMutex.RUnlock()
+	generator, ok := passwordGenerators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown password generator: %s", name)
+	}
+	return generator, nil
+}
+
+// GeneratePassword generates a password by the generator specified in the config.
+func GeneratePassword(config appsv1alpha1.PasswordConfig) (string, error) {
+	generator, err := GetPasswordGenerator(config.Generator)
+	if err != nil {
+		return "", err
+	}
+	passwd, err := generator.Generate(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password by the %s generator: %s", config.Generator, err.Error())
+	}
+	return passwd, nil
+}
+
+// defaultPasswordGenerator generates the passwords by KubeBlocks, the passwords are deterministic if the seed is set.
+type defaultPasswordGenerator struct{}
+
+func (g *defaultPasswordGenerator) Generate(config appsv1alpha1.PasswordConfig) (string, error) {
+	passwd, err := common.GeneratePasswordWithSymbols((int)(config.Length), (int)(config.NumDigits), (int)(config.NumSymbols),
+		false, config.Seed, config.SymbolCharacters)
+	if err != nil {
+		return "", err
+	}
+	return applyLetterCase(passwd, config.LetterCase), nil
+}
+
+const kmsPasswordGeneratorTimeout = 10 * time.Second
+
+// kmsPasswordGenerator requests the passwords from the KMS webhook configured by KMS_PASSWORD_GENERATOR_URL.
+// The requirements of the password are posted to the webhook, and the password is expected in the response,
+// e.g. {"password": "..."}.
+type kmsPasswordGenerator struct{}
+
+type kmsPasswordRequest struct {
+	Length           int32                   `json:"length"`
+	NumDigits        int32                   `json:"numDigits"`
+	NumSymbols       int32                   `json:"numSymbols"`
+	LetterCase       appsv1alpha1.LetterCase `json:"letterCase,omitempty"`
+	SymbolCharacters string                  `json:"symbolCharacters,omitempty"`
+}
+
+type kmsPasswordResponse struct {
+	Password string `json:"password"`
+}
+
+func (g *kmsPasswordGenerator) Generate(config appsv1alpha1.PasswordConfig) (string, error) {
+	url := viper.GetString(constant.CfgKeyKMSPasswordGeneratorURL)
+	if url == "" {
+		return "", fmt.Errorf("the KMS webhook is not configured by %s", constant.CfgKeyKMSPasswordGeneratorURL)
+	}
+	body, err := json.Marshal(kmsPasswordRequest{
+		Length:           config.Length,
+		NumDigits:        config.NumDigits,
+		NumSymbols:       config.NumSymbols,
+		LetterCase:       config.LetterCase,
+		SymbolCharacters: config.SymbolCharacters,
+	})
+	if err != nil {
+		return "", err
+	}
+	cli := &http.Client{Timeout: kmsPasswordGeneratorTimeout}
+	resp, err := cli.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("the KMS webhook responded with status %s", resp.Status)
+	}
+	result := &kmsPasswordResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", fmt.Errorf("failed to decode the response of the KMS webhook: %s", err.Error())
+	}
+	if result.Password == "" {
+		return "", fmt.Errorf("the KMS webhook responded with an empty password")
+	}
+	return applyLetterCase(result.Password, config.LetterCase), nil
+}
+
+func applyLetterCase(passwd string, letterCase appsv1alpha1.LetterCase) string {
+	switch letterCase {
+	case appsv1alpha1.UpperCases:
+		return strings.ToUpper(passwd)
+	case appsv1alpha1.LowerCases:
+		return strings.ToLower(passwd)
+	default:
+		return passwd
+	}
+}
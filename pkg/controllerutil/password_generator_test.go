@@ -0,0 +1,139 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package controllerutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+type fakePasswordGenerator struct {
+	password string
+}
+
+func (g *fakePasswordGenerator) Generate(_ appsv1alpha1.PasswordConfig) (string, error) {
+	return g.password, nil
+}
+
+func TestGetPasswordGenerator(t *testing.T) {
+	testCases := []struct {
+		name     appsv1alpha1.PasswordGeneratorType
+		expected PasswordGenerator
+	}{
+		{name: "", expected: &defaultPasswordGenerator{}},
+		{name: appsv1alpha1.DefaultPasswordGenerator, expected: &defaultPasswordGenerator{}},
+		{name: appsv1alpha1.KmsPasswordGenerator, expected: &kmsPasswordGenerator{}},
+	}
+	for _, tc := range testCases {
+		generator, err := GetPasswordGenerator(tc.name)
+		if err != nil {
+			t.Fatalf("unexpected error for generator %q: %s", tc.name, err.Error())
+		}
+		if reflect.TypeOf(generator) != reflect.TypeOf(tc.expected) {
+			t.Errorf("expected %T for generator %q, got %T", tc.expected, tc.name, generator)
+		}
+	}
+	if _, err := GetPasswordGenerator("unknown"); err == nil {
+		t.Error("expected error for unknown generator")
+	}
+
+	RegisterPasswordGenerator("Fake", &fakePasswordGenerator{password: "fake-password"})
+	defer func() {
+		passwordGeneratorsMutex.Lock()
+		delete(passwordGenerators, "Fake")
+		passwordGeneratorsMutex.Unlock()
+	}()
+	passwd, err := GeneratePassword(appsv1alpha1.PasswordConfig{Generator: "Fake"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if passwd != "fake-password" {
+		t.Errorf("expected the password of the registered generator, got %s", passwd)
+	}
+}
+
+func TestDefaultPasswordGenerator(t *testing.T) {
+	config := appsv1alpha1.PasswordConfig{
+		Length:     16,
+		NumDigits:  4,
+		NumSymbols: 2,
+		LetterCase: appsv1alpha1.UpperCases,
+		Seed:       "mycluster-mysql",
+	}
+	passwd1, err := GeneratePassword(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	passwd2, err := GeneratePassword(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if passwd1 != passwd2 {
+		t.Errorf("expected the same password with the seed, got %s and %s", passwd1, passwd2)
+	}
+	if len(passwd1) != 16 || passwd1 != strings.ToUpper(passwd1) {
+		t.Errorf("the password %s does not satisfy the config", passwd1)
+	}
+}
+
+func TestKmsPasswordGenerator(t *testing.T) {
+	config := appsv1alpha1.PasswordConfig{
+		Length:     10,
+		NumDigits:  2,
+		LetterCase: appsv1alpha1.LowerCases,
+		Generator:  appsv1alpha1.KmsPasswordGenerator,
+	}
+	viper.Set(constant.CfgKeyKMSPasswordGeneratorURL, "")
+	if _, err := GeneratePassword(config); err == nil || !strings.Contains(err.Error(), constant.CfgKeyKMSPasswordGeneratorURL) {
+		t.Errorf("expected error if the KMS webhook is not configured, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &kmsPasswordRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil || req.Length != 10 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(kmsPasswordResponse{Password: "KmsPassw0rd"})
+	}))
+	defer server.Close()
+	viper.Set(constant.CfgKeyKMSPasswordGeneratorURL, server.URL)
+	defer viper.Set(constant.CfgKeyKMSPasswordGeneratorURL, "")
+	passwd, err := GeneratePassword(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if passwd != "kmspassw0rd" {
+		t.Errorf("expected the password from the KMS webhook, got %s", passwd)
+	}
+
+	config.Length = 8
+	if _, err = GeneratePassword(config); err == nil || !strings.Contains(err.Error(), "400 Bad Request") {
+		t.Errorf("expected error if the KMS webhook fails, got %v", err)
+	}
+}
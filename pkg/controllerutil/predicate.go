@@ -43,6 +43,12 @@ func NewNamespacedControllerManagedBy(mgr manager.Manager) *builder.Builder {
 }
 
 func namespacePredicateFilter(object client.Object) bool {
+	return IsManagedNamespace(object.GetNamespace())
+}
+
+// IsManagedNamespace checks whether the namespace is managed by the controllers, all namespaces
+// are managed if the managed namespaces are not specified.
+func IsManagedNamespace(namespace string) bool {
 	if managedNamespaces == nil {
 		set := &sets.Set[string]{}
 		namespaces := viper.GetString(strings.ReplaceAll(constant.ManagedNamespacesFlag, "-", "_"))
@@ -51,8 +57,8 @@ func namespacePredicateFilter(object client.Object) bool {
 		}
 		managedNamespaces = set
 	}
-	if len(*managedNamespaces) == 0 || len(object.GetNamespace()) == 0 {
+	if len(*managedNamespaces) == 0 || len(namespace) == 0 {
 		return true
 	}
-	return managedNamespaces.Has(object.GetNamespace())
+	return managedNamespaces.Has(namespace)
 }
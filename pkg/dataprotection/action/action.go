@@ -41,6 +41,15 @@ type Action interface {
 	Type() dpv1alpha1.ActionType
 }
 
+// RetryableAction is an action that can be executed again after it failed.
+type RetryableAction interface {
+	Action
+
+	// Retry cleans up the failed execution of the action, the action is executed
+	// again by the next call of Execute.
+	Retry(actCtx ActionContext) error
+}
+
 type ActionContext struct {
 	Ctx      context.Context
 	Client   client.Client
@@ -0,0 +1,222 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	copyBackupContainerName        = "copy"
+	copyBackupSourceVolumeName     = "dp-copy-source"
+	copyBackupSourceMountPath      = "/dp-copy-source"
+	copyBackupSourceConfigFileName = "datasafed.conf"
+)
+
+// CopyBackupAction is an action that creates a batch job to copy the backup data
+// from the backup repo of the backup to another backup repo. Each file is streamed
+// from the source repo to the target repo without being staged in the pod, and the
+// files are copied as they are stored, including the ones of the Kopia repository.
+type CopyBackupAction struct {
+	// Name is the Name of the action.
+	Name string
+
+	// Owner is the owner of the job.
+	Owner client.Object
+
+	// ObjectMeta is the metadata of the job.
+	ObjectMeta metav1.ObjectMeta
+
+	// Backup is the backup to copy.
+	Backup *dpv1alpha1.Backup
+
+	// SourceRepo is the backup repo where the backup data is stored.
+	SourceRepo *dpv1alpha1.BackupRepo
+
+	// TargetRepo is the backup repo to copy the backup data to.
+	TargetRepo *dpv1alpha1.BackupRepo
+
+	// RepoVolumeMountPath is the path to mount the backup repo volume.
+	RepoVolumeMountPath string
+
+	// ServiceAccountName is the service account of the job.
+	ServiceAccountName string
+
+	// BackOffLimit is the number of retries before considering the action as failed.
+	BackOffLimit *int32
+}
+
+func (c *CopyBackupAction) GetName() string {
+	return c.Name
+}
+
+func (c *CopyBackupAction) Type() dpv1alpha1.ActionType {
+	return dpv1alpha1.ActionTypeJob
+}
+
+func (c *CopyBackupAction) Execute(actCtx ActionContext) (*dpv1alpha1.ActionStatus, error) {
+	if err := c.validate(); err != nil {
+		return newStatusBuilder(c).withErr(err).build(), err
+	}
+	podSpec, err := c.buildPodSpec()
+	if err != nil {
+		return newStatusBuilder(c).withErr(err).build(), err
+	}
+	jobAction := &JobAction{
+		Name:         c.Name,
+		Owner:        c.Owner,
+		ObjectMeta:   c.ObjectMeta,
+		PodSpec:      podSpec,
+		BackOffLimit: c.BackOffLimit,
+	}
+	return jobAction.Execute(actCtx)
+}
+
+func (c *CopyBackupAction) validate() error {
+	if c.ObjectMeta.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if c.Backup == nil {
+		return fmt.Errorf("backup is required")
+	}
+	if c.Backup.Status.Path == "" {
+		return fmt.Errorf("the path of backup %s is empty", c.Backup.Name)
+	}
+	if c.SourceRepo == nil || c.TargetRepo == nil {
+		return fmt.Errorf("source and target backup repos are required")
+	}
+	return nil
+}
+
+// buildPodSpec builds the pod spec of the copy job. The datasafed of the target repo is injected
+// as usual, and the source repo is mounted to another path, which is passed to the datasafed
+// pulling from the source repo. The files are copied as they are stored, so the encryption and
+// the Kopia repository are not configured for the datasafed.
+func (c *CopyBackupAction) buildPodSpec() (*corev1.PodSpec, error) {
+	runAsUser := int64(0)
+	container := corev1.Container{
+		Name:            copyBackupContainerName,
+		Command:         []string{"sh", "-c"},
+		Image:           viper.GetString(constant.KBToolsImage),
+		ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolptr.False(),
+			RunAsUser:                &runAsUser,
+		},
+	}
+	ctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+
+	sourceVolume := corev1.Volume{Name: copyBackupSourceVolumeName}
+	sourceVolumeMount := corev1.VolumeMount{Name: copyBackupSourceVolumeName, MountPath: copyBackupSourceMountPath}
+	var sourceDatasafed string
+	if c.SourceRepo.AccessByMount() {
+		sourceVolume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: c.SourceRepo.Status.BackupPVCName,
+		}
+		sourceDatasafed = fmt.Sprintf("env %s=%s datasafed", types.DPDatasafedLocalBackendPath, copyBackupSourceMountPath)
+	} else {
+		sourceVolume.Secret = &corev1.SecretVolumeSource{SecretName: c.SourceRepo.Status.ToolConfigSecretName}
+		sourceVolumeMount.ReadOnly = true
+		sourceDatasafed = fmt.Sprintf("env -u %s datasafed --conf %s/%s", types.DPDatasafedLocalBackendPath,
+			copyBackupSourceMountPath, copyBackupSourceConfigFileName)
+	}
+	container.VolumeMounts = append(container.VolumeMounts, sourceVolumeMount)
+
+	paths := []string{c.Backup.Status.Path}
+	if c.Backup.Status.KopiaRepoPath != "" {
+		paths = append(paths, c.Backup.Status.KopiaRepoPath)
+	}
+	container.Args = []string{buildCopyBackupScript(sourceDatasafed, paths)}
+
+	podSpec := &corev1.PodSpec{
+		Containers:         []corev1.Container{container},
+		Volumes:            []corev1.Volume{sourceVolume},
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: c.ServiceAccountName,
+	}
+	if err := utils.AddTolerations(podSpec); err != nil {
+		return nil, err
+	}
+	utils.InjectDatasafed(podSpec, c.TargetRepo, c.RepoVolumeMountPath, nil, "")
+	return podSpec, nil
+}
+
+// buildCopyBackupScript builds the script to stream the files in the paths from the source repo
+// to the target repo, a failure of pulling a file fails the script as well as a failure of pushing it.
+func buildCopyBackupScript(sourceDatasafed string, paths []string) string {
+	for i := range paths {
+		// make sure the path has a leading slash
+		if !strings.HasPrefix(paths[i], "/") {
+			paths[i] = "/" + paths[i]
+		}
+	}
+	return fmt.Sprintf(`
+set -e
+export PATH="$PATH:$%s"
+pullFailed="/tmp/dp-copy-pull-failed"
+
+copy_file() {
+	rm -f "${pullFailed}"
+	{ %s pull "$1" - || touch "${pullFailed}"; } | datasafed push - "$1"
+	if [ -f "${pullFailed}" ]; then
+		echo "failed to pull $1"
+		exit 1
+	fi
+}
+
+for path in %s; do
+	echo "copying backup files in ${path}"
+	%s list -r "${path}" | while read -r file; do
+		# skip the directories
+		case "${file}" in
+			*/) continue ;;
+		esac
+		case "${file}" in
+			/*) ;;
+			*) file="${path}/${file}" ;;
+		esac
+		copy_file "${file}"
+	done
+done
+`, types.DPDatasafedBinPath, sourceDatasafed, strings.Join(quoteAll(paths), " "), sourceDatasafed)
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i := range values {
+		quoted[i] = fmt.Sprintf("%q", values[i])
+	}
+	return quoted
+}
+
+var _ Action = &CopyBackupAction{}
@@ -0,0 +1,156 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package action_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+var _ = Describe("CopyBackupAction Test", func() {
+	const (
+		actionName = "test-copy-backup-action"
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.JobSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		viper.Set(constant.KBToolsImage, testdp.KBToolImage)
+	})
+
+	AfterEach(func() {
+		cleanEnv()
+		viper.Set(constant.KBToolsImage, "")
+	})
+
+	newRepo := func(name string, accessMethod dpv1alpha1.AccessMethod) *dpv1alpha1.BackupRepo {
+		return &dpv1alpha1.BackupRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       dpv1alpha1.BackupRepoSpec{AccessMethod: accessMethod},
+			Status: dpv1alpha1.BackupRepoStatus{
+				BackupPVCName:        name + "-pvc",
+				ToolConfigSecretName: name + "-tool-config",
+			},
+		}
+	}
+
+	Context("create copy backup action", func() {
+		It("should return error when backup repos are empty", func() {
+			act := &action.CopyBackupAction{
+				ObjectMeta: metav1.ObjectMeta{Name: actionName, Namespace: testCtx.DefaultNamespace},
+				Backup:     testdp.NewFakeBackup(&testCtx, nil),
+			}
+			status, err := act.Execute(buildActionCtx())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseFailed))
+		})
+
+		It("should success to execute copy backup action", func() {
+			backup := testdp.NewFakeBackup(&testCtx, nil)
+			backup.Status.Path = "/test/" + backup.Name
+			backup.Status.KopiaRepoPath = "/test/kopia"
+			act := &action.CopyBackupAction{
+				Name: actionName,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      actionName,
+					Namespace: testCtx.DefaultNamespace,
+				},
+				Owner:               backup,
+				Backup:              backup,
+				SourceRepo:          newRepo("source-repo", dpv1alpha1.AccessMethodMount),
+				TargetRepo:          newRepo("target-repo", dpv1alpha1.AccessMethodTool),
+				RepoVolumeMountPath: "/backupdata",
+			}
+
+			By("should success to execute")
+			status, err := act.Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseRunning))
+
+			By("check the job streams the files from the source repo to the target repo")
+			job := &batchv1.Job{}
+			key := client.ObjectKey{Name: actionName, Namespace: testCtx.DefaultNamespace}
+			Eventually(testapps.CheckObjExists(&testCtx, key, job, true)).Should(Succeed())
+			podSpec := job.Spec.Template.Spec
+			Expect(podSpec.Containers).Should(HaveLen(1))
+			// only the init container installing the datasafed, the files are not staged in the pod.
+			Expect(podSpec.InitContainers).Should(HaveLen(1))
+			script := podSpec.Containers[0].Args[0]
+			Expect(script).Should(ContainSubstring("DATASAFED_LOCAL_BACKEND_PATH=/dp-copy-source datasafed"))
+			Expect(script).Should(ContainSubstring(fmt.Sprintf("%q %q", backup.Status.Path, backup.Status.KopiaRepoPath)))
+			for _, env := range podSpec.Containers[0].Env {
+				Expect(env.Name).ShouldNot(Equal("DATASAFED_KOPIA_REPO_ROOT"))
+				Expect(env.Name).ShouldNot(Equal("DATASAFED_ENCRYPTION_ALGORITHM"))
+			}
+			volumeNames := map[string]bool{}
+			for _, v := range podSpec.Volumes {
+				Expect(volumeNames[v.Name]).Should(BeFalse())
+				volumeNames[v.Name] = true
+				if v.PersistentVolumeClaim != nil {
+					Expect(v.PersistentVolumeClaim.ClaimName).Should(Equal("source-repo-pvc"))
+				}
+				if v.Secret != nil {
+					Expect(v.Secret.SecretName).Should(Equal("target-repo-tool-config"))
+				}
+			}
+
+			By("the source repo accessed by the tool is configured by its own config file")
+			toolAct := *act
+			toolAct.Name = actionName + "-tool"
+			toolAct.ObjectMeta.Name = toolAct.Name
+			toolAct.SourceRepo = newRepo("source-repo", dpv1alpha1.AccessMethodTool)
+			_, err = toolAct.Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			toolJob := &batchv1.Job{}
+			Eventually(testapps.CheckObjExists(&testCtx, client.ObjectKey{Name: toolAct.Name, Namespace: testCtx.DefaultNamespace},
+				toolJob, true)).Should(Succeed())
+			Expect(toolJob.Spec.Template.Spec.Containers[0].Args[0]).Should(
+				ContainSubstring("env -u DATASAFED_LOCAL_BACKEND_PATH datasafed --conf /dp-copy-source/datasafed.conf"))
+
+			By("set job status to complete")
+			testdp.PatchK8sJobStatus(&testCtx, client.ObjectKeyFromObject(job), batchv1.JobComplete)
+
+			By("action status should be completed")
+			status, err = act.Execute(buildActionCtx())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseCompleted))
+		})
+	})
+})
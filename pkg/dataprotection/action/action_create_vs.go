@@ -27,6 +27,7 @@ import (
 	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,6 +53,10 @@ type CreateVolumeSnapshotAction struct {
 
 	// PersistentVolumeClaimWrappers is the list of persistent volume claims wrapper to snapshot.
 	PersistentVolumeClaimWrappers []PersistentVolumeClaimWrapper
+
+	// VolumeSnapshotClassName is the name of the volume snapshot class to use, if it is
+	// empty, the class is selected by the CSI driver of the persistent volume.
+	VolumeSnapshotClassName string
 }
 
 type PersistentVolumeClaimWrapper struct {
@@ -87,11 +92,16 @@ func (c *CreateVolumeSnapshotAction) Execute(actCtx ActionContext) (*dpv1alpha1.
 		return handleErr(err)
 	}
 
+	// the volume snapshot API may be served in v1beta1.
+	actCtx.Client = utils.NewCompatClient(actCtx.Client)
+
 	var (
-		ok   bool
-		err  error
-		snap *vsv1.VolumeSnapshot
+		ok        bool
+		err       error
+		snap      *vsv1.VolumeSnapshot
+		snapshots []dpv1alpha1.VolumeSnapshotStatus
 	)
+	totalSize := resource.NewQuantity(0, resource.BinarySI)
 	for _, w := range c.PersistentVolumeClaimWrappers {
 		key := client.ObjectKey{
 			Namespace: w.PersistentVolumeClaim.Namespace,
@@ -110,14 +120,36 @@ func (c *CreateVolumeSnapshotAction) Execute(actCtx ActionContext) (*dpv1alpha1.
 		if !ok {
 			return sb.startTimestamp(&snap.CreationTimestamp).build(), nil
 		}
+		snapshots = append(snapshots, buildVolumeSnapshotStatus(snap, w.VolumeName))
+		if snap.Status.RestoreSize != nil {
+			totalSize.Add(*snap.Status.RestoreSize)
+		}
 	}
 
-	// volume snapshot is ready and status is not error
-	// TODO(ldm): now only support one volume to take snapshot, set its time, size to status
-	return sb.phase(dpv1alpha1.ActionPhaseCompleted).
-		totalSize(snap.Status.RestoreSize.String()).
-		timeRange(snap.Status.CreationTime, snap.Status.CreationTime).
-		build(), nil
+	// volume snapshot is ready and status is not error, the total size is the sum
+	// of the restore sizes of the volume snapshots.
+	// TODO(ldm): now only support one volume to take snapshot, set its time to status
+	sb.phase(dpv1alpha1.ActionPhaseCompleted).
+		volumeSnapshots(snapshots).
+		timeRange(snap.Status.CreationTime, snap.Status.CreationTime)
+	if !totalSize.IsZero() {
+		sb.totalSize(totalSize.String())
+	}
+	return sb.build(), nil
+}
+
+func buildVolumeSnapshotStatus(snap *vsv1.VolumeSnapshot, volumeName string) dpv1alpha1.VolumeSnapshotStatus {
+	status := dpv1alpha1.VolumeSnapshotStatus{
+		Name:       snap.Name,
+		VolumeName: volumeName,
+	}
+	if snap.Status.BoundVolumeSnapshotContentName != nil {
+		status.ContentName = *snap.Status.BoundVolumeSnapshotContentName
+	}
+	if snap.Status.RestoreSize != nil {
+		status.Size = snap.Status.RestoreSize.String()
+	}
+	return status
 }
 
 func (c *CreateVolumeSnapshotAction) validate() error {
@@ -165,8 +197,11 @@ func (c *CreateVolumeSnapshotAction) createVolumeSnapshotIfNotExist(
 		},
 	}
 
-	if vscName, err = c.getVolumeSnapshotClassName(ctx.Ctx, ctx.Client, pvc.Spec.VolumeName); err != nil {
-		return err
+	vscName = c.VolumeSnapshotClassName
+	if vscName == "" {
+		if vscName, err = c.getVolumeSnapshotClassName(ctx.Ctx, ctx.Client, pvc.Spec.VolumeName); err != nil {
+			return err
+		}
 	}
 
 	if vscName != "" {
@@ -174,8 +209,11 @@ func (c *CreateVolumeSnapshotAction) createVolumeSnapshotIfNotExist(
 	}
 
 	controllerutil.AddFinalizer(snap, dptypes.DataProtectionFinalizerName)
-	if err = utils.SetControllerReference(c.Owner, snap, ctx.Scheme); err != nil {
-		return err
+	// the cross-namespace owner reference is not allowed.
+	if c.Owner != nil && c.Owner.GetNamespace() == snap.Namespace {
+		if err = utils.SetControllerReference(c.Owner, snap, ctx.Scheme); err != nil {
+			return err
+		}
 	}
 
 	msg := fmt.Sprintf("creating volume snapshot %s/%s", snap.Namespace, snap.Name)
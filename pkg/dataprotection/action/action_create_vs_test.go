@@ -25,7 +25,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
@@ -107,6 +109,23 @@ var _ = Describe("CreateVolumeSnapshotAction Test", func() {
 				Name:      dputils.GetBackupVolumeSnapshotName(actionName, volumeName),
 			}
 			Eventually(testapps.CheckObjExists(&testCtx, key, &vsv1.VolumeSnapshot{}, true)).Should(Succeed())
+
+			By("mark the volume snapshot ready, the action should be completed with its size")
+			Eventually(testapps.GetAndChangeObjStatus(&testCtx, key, func(fetched *vsv1.VolumeSnapshot) {
+				restoreSize := resource.MustParse("1Gi")
+				fetched.Status = &vsv1.VolumeSnapshotStatus{
+					ReadyToUse:  pointer.Bool(true),
+					RestoreSize: &restoreSize,
+				}
+			})).Should(Succeed())
+			status, err = act.Execute(buildActionCtx())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseCompleted))
+			Expect(status.TotalSize).Should(Equal("1Gi"))
+			Expect(status.VolumeSnapshots).Should(HaveLen(1))
+			Expect(status.VolumeSnapshots[0].Name).Should(Equal(key.Name))
+			Expect(status.VolumeSnapshots[0].VolumeName).Should(Equal(volumeName))
+			Expect(status.VolumeSnapshots[0].Size).Should(Equal("1Gi"))
 		})
 	})
 })
@@ -20,17 +20,21 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	ref "k8s.io/client-go/tools/reference"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/tracing"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 )
@@ -70,34 +74,17 @@ func (j *JobAction) Execute(actCtx ActionContext) (*dpv1alpha1.ActionStatus, err
 		return handleErr(err)
 	}
 
-	key := client.ObjectKey{
-		Namespace: j.ObjectMeta.Namespace,
-		Name:      j.ObjectMeta.Name,
-	}
-	original := batchv1.Job{}
-	exists, err := ctrlutil.CheckResourceExists(actCtx.Ctx, actCtx.Client, key, &original)
+	// adopt the existing job created by this action before, and set action status accordingly
+	original, err := j.GetExistingJob(actCtx.Ctx, actCtx.Client)
 	if err != nil {
 		return handleErr(err)
 	}
-
-	// job exists, check job status and set action status accordingly
-	if exists {
-		objRef, _ := ref.GetReference(actCtx.Scheme, &original)
-		sb = sb.startTimestamp(&original.CreationTimestamp).objectRef(objRef)
-		_, finishedType, msg := utils.IsJobFinished(&original)
-		switch finishedType {
-		case batchv1.JobComplete:
-			return sb.phase(dpv1alpha1.ActionPhaseCompleted).
-				completionTimestamp(nil).
-				build(), nil
-		case batchv1.JobFailed:
-			return sb.phase(dpv1alpha1.ActionPhaseFailed).
-				completionTimestamp(nil).
-				reason(msg).
-				build(), nil
+	if original != nil {
+		if !original.DeletionTimestamp.IsZero() {
+			// the failed job is being deleted for retrying, wait for it to be deleted.
+			return sb.build(), nil
 		}
-		// job is running
-		return handleErr(nil)
+		return j.BuildStatusFromJob(actCtx.Scheme, original), nil
 	}
 
 	// job doesn't exist, create it
@@ -111,6 +98,8 @@ func (j *JobAction) Execute(actCtx ActionContext) (*dpv1alpha1.ActionStatus, err
 			BackoffLimit: j.BackOffLimit,
 		},
 	}
+	// propagate the trace context to the job pod, the tools instrumented in the pod can join the trace.
+	job.Spec.Template.Annotations = tracing.InjectTraceContext(actCtx.Ctx, j.ObjectMeta.Annotations)
 
 	controllerutil.AddFinalizer(job, types.DataProtectionFinalizerName)
 	if job.Namespace == j.Owner.GetNamespace() {
@@ -123,6 +112,82 @@ func (j *JobAction) Execute(actCtx ActionContext) (*dpv1alpha1.ActionStatus, err
 	return handleErr(client.IgnoreAlreadyExists(actCtx.Client.Create(actCtx.Ctx, job)))
 }
 
+// GetExistingJob returns the job with the deterministic name of the action, nil is returned if
+// the job does not exist. The job is adopted only if it carries the same backup labels as the
+// action, otherwise an error is returned to avoid taking over a job of another backup.
+func (j *JobAction) GetExistingJob(ctx context.Context, cli client.Client) (*batchv1.Job, error) {
+	key := client.ObjectKey{
+		Namespace: j.ObjectMeta.Namespace,
+		Name:      j.ObjectMeta.Name,
+	}
+	job := &batchv1.Job{}
+	exists, err := ctrlutil.CheckResourceExists(ctx, cli, key, job)
+	if err != nil || !exists {
+		return nil, err
+	}
+	for _, labelKey := range []string{types.BackupNameLabelKey, types.BackupNamespaceLabelKey} {
+		v, ok := j.ObjectMeta.Labels[labelKey]
+		if ok && job.Labels[labelKey] != v {
+			return nil, fmt.Errorf("job %s/%s already exists but its label %s is %s, expected %s",
+				job.Namespace, job.Name, labelKey, job.Labels[labelKey], v)
+		}
+	}
+	return job, nil
+}
+
+// BuildStatusFromJob builds the action status according to the status of the job created by the action.
+func (j *JobAction) BuildStatusFromJob(scheme *runtime.Scheme, job *batchv1.Job) *dpv1alpha1.ActionStatus {
+	objRef, _ := ref.GetReference(scheme, job)
+	sb := newStatusBuilder(j).startTimestamp(&job.CreationTimestamp).objectRef(objRef).
+		progress(getJobProgress(job))
+	_, finishedType, msg := utils.IsJobFinished(job)
+	switch finishedType {
+	case batchv1.JobComplete:
+		return sb.phase(dpv1alpha1.ActionPhaseCompleted).
+			completionTimestamp(nil).
+			progress(&Progress{Progress: 100, ProcessedSize: sb.status.ProcessedSize}).
+			build()
+	case batchv1.JobFailed:
+		return sb.phase(dpv1alpha1.ActionPhaseFailed).
+			completionTimestamp(nil).
+			reason(msg).
+			build()
+	}
+	// job is running
+	return sb.build()
+}
+
+// getJobProgress returns the progress published by the job, nil is returned if the job
+// does not publish its progress or the progress is malformed.
+func getJobProgress(job *batchv1.Job) *Progress {
+	value, ok := job.Annotations[types.BackupProgressAnnotationKey]
+	if !ok {
+		return nil
+	}
+	progress := &Progress{}
+	if err := json.Unmarshal([]byte(value), progress); err != nil {
+		return nil
+	}
+	if progress.Progress < 0 || progress.Progress > 100 {
+		return nil
+	}
+	return progress
+}
+
+// Retry deletes the failed job of the action, the job will be recreated by the next execution.
+func (j *JobAction) Retry(actCtx ActionContext) error {
+	job, err := j.GetExistingJob(actCtx.Ctx, actCtx.Client)
+	if err != nil || job == nil {
+		return err
+	}
+	if err = utils.RemoveDataProtectionFinalizer(actCtx.Ctx, actCtx.Client, job); err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("deleting failed job %s/%s for retrying", job.Namespace, job.Name)
+	actCtx.Recorder.Event(j.Owner, corev1.EventTypeNormal, "DeletingJob", msg)
+	return ctrlutil.BackgroundDeleteObject(actCtx.Client, actCtx.Ctx, job)
+}
+
 func (j *JobAction) validate() error {
 	if j.ObjectMeta.Name == "" {
 		return fmt.Errorf("name is required")
@@ -136,4 +201,4 @@ func (j *JobAction) validate() error {
 	return nil
 }
 
-var _ Action = &JobAction{}
+var _ RetryableAction = &JobAction{}
@@ -31,6 +31,7 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
@@ -108,6 +109,25 @@ var _ = Describe("JobAction Test", func() {
 			key := client.ObjectKey{Name: actionName, Namespace: testCtx.DefaultNamespace}
 			Eventually(testapps.CheckObjExists(&testCtx, key, job, true)).Should(Succeed())
 
+			By("the progress annotated to the job should be reported")
+			Expect(testapps.ChangeObj(&testCtx, job, func(job *batchv1.Job) {
+				job.Annotations = map[string]string{
+					dptypes.BackupProgressAnnotationKey: `{"progress":42,"processedSize":"1Gi"}`,
+				}
+			})).Should(Succeed())
+			status, err = act.Execute(buildActionCtx())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Progress).Should(BeEquivalentTo(42))
+			Expect(status.ProcessedSize).Should(Equal("1Gi"))
+
+			By("the invalid progress should be ignored")
+			Expect(testapps.ChangeObj(&testCtx, job, func(job *batchv1.Job) {
+				job.Annotations[dptypes.BackupProgressAnnotationKey] = `{"progress":142}`
+			})).Should(Succeed())
+			status, err = act.Execute(buildActionCtx())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Progress).Should(BeEquivalentTo(0))
+
 			By("set job status to complete")
 			testdp.PatchK8sJobStatus(&testCtx, client.ObjectKeyFromObject(job), batchv1.JobComplete)
 
@@ -115,6 +135,7 @@ var _ = Describe("JobAction Test", func() {
 			status, err = act.Execute(buildActionCtx())
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseCompleted))
+			Expect(status.Progress).Should(BeEquivalentTo(100))
 		})
 	})
 })
@@ -20,8 +20,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package action
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"math"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -36,6 +39,7 @@ import (
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
 // StatefulSetAction is an action that creates or updates the StatefulSet of Continuous backup.
@@ -52,6 +56,14 @@ type StatefulSetAction struct {
 	PodSpec *corev1.PodSpec
 
 	ActionSet *dpv1alpha1.ActionSet
+
+	// ToolConfigSecret is the secret of the tool config of the backup repo, the pods are restarted
+	// when it is changed.
+	ToolConfigSecret *corev1.Secret
+
+	// RetryPolicy specifies the maximum restarts of the backup pod before considering
+	// the action as failed.
+	RetryPolicy *dpv1alpha1.BackupRetryPolicy
 }
 
 func (s *StatefulSetAction) GetName() string {
@@ -79,9 +91,13 @@ func (s *StatefulSetAction) Execute(ctx ActionContext) (actionStatus *dpv1alpha1
 	// inject continuous env
 	_ = s.injectContinuousEnvForPodSpec(ctx, s.PodSpec)
 	s.PodSpec.RestartPolicy = corev1.RestartPolicyAlways
+	podTemplateHash, err := s.computePodTemplateHash()
+	if err != nil {
+		return nil, err
+	}
 	// if not exists, create the statefulSet
 	if !exists {
-		if err = s.createStatefulSet(ctx, s.PodSpec); err != nil {
+		if err = s.createStatefulSet(ctx, s.PodSpec, podTemplateHash); err != nil {
 			return nil, err
 		}
 		return &dpv1alpha1.ActionStatus{
@@ -91,10 +107,7 @@ func (s *StatefulSetAction) Execute(ctx ActionContext) (actionStatus *dpv1alpha1
 			StartTimestamp: &metav1.Time{Time: time.Now()},
 		}, nil
 	}
-	sts.Spec.Template.Spec = *s.PodSpec
-	// update the statefulSet
-
-	if err = ctx.Client.Update(ctx.Ctx, sts); err != nil {
+	if err = s.updateStatefulSet(ctx, sts, podTemplateHash); err != nil {
 		return nil, err
 	}
 	actionStatus = &dpv1alpha1.ActionStatus{
@@ -105,16 +118,26 @@ func (s *StatefulSetAction) Execute(ctx ActionContext) (actionStatus *dpv1alpha1
 	}
 	s.Backup.Status.Phase = dpv1alpha1.BackupPhaseRunning
 	actionStatus.ObjectRef, _ = ref.GetReference(ctx.Scheme, sts)
-	if s.stsIsFailed(ctx) {
+	isFailed, restartCount := s.stsIsFailed(ctx)
+	actionStatus.RetryCount = restartCount
+	if isFailed {
 		actionStatus.Phase = dpv1alpha1.ActionPhaseFailed
 		actionStatus.FailureReason = fmt.Sprintf("pod %s-0 is not running", sts.Name)
+		if s.RetryPolicy != nil {
+			actionStatus.FailureReason = fmt.Sprintf("pod %s-0 is not running after %d restarts", sts.Name, restartCount)
+		}
 	}
 	return actionStatus, nil
 }
 
-func (s *StatefulSetAction) createStatefulSet(ctx ActionContext, podSpec *corev1.PodSpec) error {
+func (s *StatefulSetAction) createStatefulSet(ctx ActionContext, podSpec *corev1.PodSpec, podTemplateHash string) error {
+	objectMeta := *s.ObjectMeta.DeepCopy()
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = map[string]string{}
+	}
+	objectMeta.Annotations[dptypes.PodTemplateHashAnnotationKey] = podTemplateHash
 	sts := &appsv1.StatefulSet{
-		ObjectMeta: s.ObjectMeta,
+		ObjectMeta: objectMeta,
 		Spec: appsv1.StatefulSetSpec{
 			Replicas: s.Replicas,
 			Selector: &metav1.LabelSelector{
@@ -122,7 +145,8 @@ func (s *StatefulSetAction) createStatefulSet(ctx ActionContext, podSpec *corev1
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: s.ObjectMeta.Labels,
+					Labels:      s.ObjectMeta.Labels,
+					Annotations: s.buildPodTemplateAnnotations(nil),
 				},
 				Spec: *podSpec,
 			},
@@ -135,6 +159,116 @@ func (s *StatefulSetAction) createStatefulSet(ctx ActionContext, podSpec *corev1
 	return ctx.Client.Create(ctx.Ctx, sts)
 }
 
+// updateStatefulSet updates the replicas of the statefulSet, and rolls its pods if the pod template is drifted
+// from the ActionSet or the tool config, unless the continuous backup workloads are frozen.
+func (s *StatefulSetAction) updateStatefulSet(ctx ActionContext, sts *appsv1.StatefulSet, podTemplateHash string) error {
+	stsCopy := sts.DeepCopy()
+	// the replicas is changed when the backup is paused or resumed.
+	sts.Spec.Replicas = s.Replicas
+	oldHash, ok := sts.Annotations[dptypes.PodTemplateHashAnnotationKey]
+	switch {
+	case !ok:
+		// the statefulSet is created by an older version, adopt it by recording the hash
+		// without touching the pod annotations to avoid restarting the pods.
+		sts.Spec.Template.Spec = *s.PodSpec
+		if sts.Annotations == nil {
+			sts.Annotations = map[string]string{}
+		}
+		sts.Annotations[dptypes.PodTemplateHashAnnotationKey] = podTemplateHash
+	case oldHash != podTemplateHash && !viper.GetBool(dptypes.CfgKeyFreezeContinuousBackupWorkload):
+		changes := s.summarizePodTemplateChanges(&sts.Spec.Template)
+		sts.Spec.Template.Spec = *s.PodSpec
+		sts.Spec.Template.Annotations = s.buildPodTemplateAnnotations(sts.Spec.Template.Annotations)
+		sts.Annotations[dptypes.PodTemplateHashAnnotationKey] = podTemplateHash
+		msg := fmt.Sprintf("rolling statefulSet %s/%s as its pod template is changed", sts.Namespace, sts.Name)
+		if len(changes) > 0 {
+			msg += ": " + strings.Join(changes, "; ")
+		}
+		ctx.Recorder.Event(s.Backup, corev1.EventTypeNormal, "UpdatingStatefulSet", msg)
+	}
+	if reflect.DeepEqual(stsCopy, sts) {
+		return nil
+	}
+	return ctx.Client.Update(ctx.Ctx, sts)
+}
+
+// computePodTemplateHash computes the hash of the rendered pod spec and the version of the tool config.
+func (s *StatefulSetAction) computePodTemplateHash() (string, error) {
+	data, err := json.Marshal(struct {
+		PodSpec           *corev1.PodSpec `json:"podSpec"`
+		ToolConfigVersion string          `json:"toolConfigVersion,omitempty"`
+	}{s.PodSpec, s.toolConfigVersion()})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16], nil
+}
+
+func (s *StatefulSetAction) toolConfigVersion() string {
+	if s.ToolConfigSecret == nil {
+		return ""
+	}
+	return s.ToolConfigSecret.ResourceVersion
+}
+
+// buildPodTemplateAnnotations records the version of the tool config in the pod annotations, the secret is mounted
+// or referenced by the envs, so the pods have to be restarted to pick up the change.
+func (s *StatefulSetAction) buildPodTemplateAnnotations(annotations map[string]string) map[string]string {
+	version := s.toolConfigVersion()
+	if version == "" {
+		delete(annotations, dptypes.ToolConfigVersionAnnotationKey)
+		return annotations
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[dptypes.ToolConfigVersionAnnotationKey] = version
+	return annotations
+}
+
+// summarizePodTemplateChanges summarizes the changes of the images, commands and envs of the containers,
+// and the tool config between the current pod template and the desired one.
+func (s *StatefulSetAction) summarizePodTemplateChanges(template *corev1.PodTemplateSpec) []string {
+	var changes []string
+	oldContainers := map[string]corev1.Container{}
+	for _, c := range template.Spec.Containers {
+		oldContainers[c.Name] = c
+	}
+	envValues := func(envs []corev1.EnvVar) map[string]string {
+		values := map[string]string{}
+		for _, env := range envs {
+			values[env.Name] = env.Value
+		}
+		return values
+	}
+	for _, c := range s.PodSpec.Containers {
+		oldContainer, ok := oldContainers[c.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s is added", c.Name))
+			continue
+		}
+		delete(oldContainers, c.Name)
+		if oldContainer.Image != c.Image {
+			changes = append(changes, fmt.Sprintf("image of container %s is changed from %s to %s", c.Name, oldContainer.Image, c.Image))
+		}
+		if !reflect.DeepEqual(oldContainer.Command, c.Command) || !reflect.DeepEqual(oldContainer.Args, c.Args) {
+			changes = append(changes, fmt.Sprintf("command of container %s is changed", c.Name))
+		}
+		if !reflect.DeepEqual(envValues(oldContainer.Env), envValues(c.Env)) {
+			changes = append(changes, fmt.Sprintf("env of container %s is changed", c.Name))
+		}
+	}
+	for _, c := range template.Spec.Containers {
+		if _, ok := oldContainers[c.Name]; ok {
+			changes = append(changes, fmt.Sprintf("container %s is removed", c.Name))
+		}
+	}
+	if template.Annotations[dptypes.ToolConfigVersionAnnotationKey] != s.toolConfigVersion() {
+		changes = append(changes, "tool config is changed")
+	}
+	return changes
+}
+
 func (s *StatefulSetAction) injectContinuousEnvForPodSpec(ctx ActionContext, podSpec *corev1.PodSpec) error {
 	backupSchedule := &dpv1alpha1.BackupSchedule{}
 	if err := ctx.Client.Get(ctx.Ctx, client.ObjectKey{Name: s.Backup.Labels[dptypes.BackupScheduleLabelKey],
@@ -205,12 +339,49 @@ loop:
 	return interval + "s"
 }
 
-func (s *StatefulSetAction) stsIsFailed(ctx ActionContext) bool {
+// stsIsFailed checks whether the backup pod is failed, and returns the restart count of the pod since it
+// was last running stably. If the retry policy is specified, the pod is considered failed only if it is
+// still failing after the maximum restarts, otherwise it is failed once it has failed for a timeout.
+func (s *StatefulSetAction) stsIsFailed(ctx ActionContext) (bool, int32) {
 	pod := &corev1.Pod{}
 	if err := ctx.Client.Get(ctx.Ctx, client.ObjectKey{Name: s.ObjectMeta.Name + "-0",
 		Namespace: s.ObjectMeta.Namespace}, pod); err != nil {
-		return false
+		return false, 0
 	}
+	restartCount := getRestartCountSinceStable(ctx, pod)
 	isFailed, isTimeout, _ := intctrlutil.IsPodFailedAndTimedOut(pod)
-	return isFailed && isTimeout
+	if s.RetryPolicy != nil {
+		return isFailed && restartCount > s.RetryPolicy.MaxRetries, restartCount
+	}
+	return isFailed && isTimeout, restartCount
+}
+
+// getRestartCountSinceStable returns the restart count of the pod since all its containers were last running
+// for the restartCountResetWindow. The total restart count at that time is recorded as the baseline in the
+// annotation of the pod, so the baseline is dropped along with the restart count when the pod is recreated.
+func getRestartCountSinceStable(ctx ActionContext, pod *corev1.Pod) int32 {
+	var total int32
+	stable := len(pod.Status.ContainerStatuses) > 0
+	for _, status := range pod.Status.ContainerStatuses {
+		total += status.RestartCount
+		if status.State.Running == nil || time.Since(status.State.Running.StartedAt.Time) < restartCountResetWindow {
+			stable = false
+		}
+	}
+	baseline, err := strconv.ParseInt(pod.Annotations[dptypes.RestartCountBaselineAnnotationKey], 10, 32)
+	if err != nil || baseline < 0 || int32(baseline) > total {
+		baseline = 0
+	}
+	if stable && int32(baseline) != total {
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[dptypes.RestartCountBaselineAnnotationKey] = strconv.Itoa(int(total))
+		// the baseline is updated in the next reconciliation if failed to patch.
+		if err = ctx.Client.Patch(ctx.Ctx, pod, patch); err == nil {
+			baseline = int64(total)
+		}
+	}
+	return total - int32(baseline)
 }
@@ -0,0 +1,184 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package action_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+var _ = Describe("StatefulSetAction Test", func() {
+	const (
+		actionName = "test-stateful-action"
+		container  = "container"
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.StatefulSetSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PodSignature, true, inNS)
+	}
+
+	BeforeEach(cleanEnv)
+
+	AfterEach(func() {
+		cleanEnv()
+		viper.Set(dptypes.CfgKeyFreezeContinuousBackupWorkload, false)
+	})
+
+	Context("execute statefulSet action", func() {
+		var backup *dpv1alpha1.Backup
+
+		newAction := func(image string, toolConfigSecret *corev1.Secret) *action.StatefulSetAction {
+			labels := map[string]string{
+				"dp-test-action": actionName,
+			}
+			return &action.StatefulSetAction{
+				Name: actionName,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      actionName,
+					Namespace: testCtx.DefaultNamespace,
+					Labels:    labels,
+				},
+				Replicas: pointer.Int32(1),
+				Backup:   backup,
+				PodSpec: &corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:    container,
+							Image:   image,
+							Command: []string{"sleep", "infinity"},
+						},
+					},
+				},
+				ToolConfigSecret: toolConfigSecret,
+			}
+		}
+
+		getStatefulSet := func() *appsv1.StatefulSet {
+			sts := &appsv1.StatefulSet{}
+			key := client.ObjectKey{Name: actionName, Namespace: testCtx.DefaultNamespace}
+			Expect(testCtx.Cli.Get(testCtx.Ctx, key, sts)).Should(Succeed())
+			return sts
+		}
+
+		BeforeEach(func() {
+			backup = testdp.NewFakeBackup(&testCtx, nil)
+		})
+
+		It("should roll the statefulSet when the pod template is changed", func() {
+			By("create the statefulSet with the hash of the pod template")
+			_, err := newAction(testdp.KBToolImage, nil).Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			sts := getStatefulSet()
+			hash := sts.Annotations[dptypes.PodTemplateHashAnnotationKey]
+			Expect(hash).ShouldNot(BeEmpty())
+
+			By("the statefulSet should not be updated if nothing is changed")
+			_, err = newAction(testdp.KBToolImage, nil).Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			Expect(getStatefulSet().ResourceVersion).Should(Equal(sts.ResourceVersion))
+
+			By("the statefulSet should be rolled if the image is changed")
+			_, err = newAction(testdp.KBToolImage+"-new", nil).Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			sts = getStatefulSet()
+			Expect(sts.Spec.Template.Spec.Containers[0].Image).Should(Equal(testdp.KBToolImage + "-new"))
+			Expect(sts.Annotations[dptypes.PodTemplateHashAnnotationKey]).ShouldNot(Equal(hash))
+
+			By("the statefulSet should be rolled if the tool config is changed")
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "tool-config", ResourceVersion: "2"}}
+			_, err = newAction(testdp.KBToolImage+"-new", secret).Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			sts = getStatefulSet()
+			Expect(sts.Spec.Template.Annotations[dptypes.ToolConfigVersionAnnotationKey]).Should(Equal("2"))
+		})
+
+		It("should not roll the statefulSet when the workloads are frozen", func() {
+			_, err := newAction(testdp.KBToolImage, nil).Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+
+			viper.Set(dptypes.CfgKeyFreezeContinuousBackupWorkload, true)
+			_, err = newAction(testdp.KBToolImage+"-new", nil).Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			Expect(getStatefulSet().Spec.Template.Spec.Containers[0].Image).Should(Equal(testdp.KBToolImage))
+		})
+
+		It("should count the restarts of the backup pod since it was last running stably", func() {
+			act := newAction(testdp.KBToolImage, nil)
+			act.RetryPolicy = &dpv1alpha1.BackupRetryPolicy{MaxRetries: 3}
+			_, err := act.Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+
+			By("create the backup pod which is running stably after several restarts")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: actionName + "-0", Namespace: testCtx.DefaultNamespace},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: container, Image: testdp.KBToolImage}},
+				},
+			}
+			Expect(testCtx.Cli.Create(testCtx.Ctx, pod)).Should(Succeed())
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:         container,
+				RestartCount: 5,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now().Add(-time.Hour))},
+				},
+			}}
+			Expect(testCtx.Cli.Status().Update(testCtx.Ctx, pod)).Should(Succeed())
+			status, err := act.Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			Expect(status.RetryCount).Should(BeZero())
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseRunning))
+
+			By("the restarts before the pod was running stably should not be counted")
+			Expect(testCtx.Cli.Get(testCtx.Ctx, client.ObjectKeyFromObject(pod), pod)).Should(Succeed())
+			Expect(pod.Annotations[dptypes.RestartCountBaselineAnnotationKey]).Should(Equal("5"))
+			pod.Status.ContainerStatuses[0].RestartCount = 7
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			}
+			Expect(testCtx.Cli.Status().Update(testCtx.Ctx, pod)).Should(Succeed())
+			status, err = act.Execute(buildActionCtx())
+			Expect(err).Should(Succeed())
+			Expect(status.RetryCount).Should(BeEquivalentTo(2))
+			Expect(status.Phase).Should(Equal(dpv1alpha1.ActionPhaseRunning))
+		})
+	})
+})
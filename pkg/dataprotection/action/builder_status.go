@@ -92,6 +92,15 @@ func (b *statusBuilder) totalSize(size string) *statusBuilder {
 	return b
 }
 
+func (b *statusBuilder) progress(progress *Progress) *statusBuilder {
+	if progress == nil {
+		return b
+	}
+	b.status.Progress = progress.Progress
+	b.status.ProcessedSize = progress.ProcessedSize
+	return b
+}
+
 func (b *statusBuilder) timeRange(start, end *metav1.Time) *statusBuilder {
 	b.status.TimeRange = &dpv1alpha1.BackupTimeRange{
 		Start: start,
@@ -100,6 +109,11 @@ func (b *statusBuilder) timeRange(start, end *metav1.Time) *statusBuilder {
 	return b
 }
 
+func (b *statusBuilder) volumeSnapshots(snapshots []dpv1alpha1.VolumeSnapshotStatus) *statusBuilder {
+	b.status.VolumeSnapshots = snapshots
+	return b
+}
+
 func (b *statusBuilder) build() *dpv1alpha1.ActionStatus {
 	return b.status
 }
@@ -19,9 +19,24 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
 package action
 
+import "time"
+
 type ErrorMode string
 
 const (
 	ErrorModeContinue ErrorMode = "Continue"
 	ErrorModeFail     ErrorMode = "Fail"
 )
+
+// restartCountResetWindow is the duration the continuous backup pod keeps running to reset its restart count
+// counted by the retry policy, as the kubelet resets the crash loop backoff of the container.
+const restartCountResetWindow = 10 * time.Minute
+
+// Progress is the progress published by the backup job through the annotation
+// dataprotection.kubeblocks.io/progress of the job.
+type Progress struct {
+	// Progress is the progress in percentage, from 0 to 100.
+	Progress int32 `json:"progress"`
+	// ProcessedSize is the size of the data processed so far, e.g. "10Gi".
+	ProcessedSize string `json:"processedSize,omitempty"`
+}
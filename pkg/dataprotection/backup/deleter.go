@@ -20,8 +20,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package backup
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -45,8 +48,30 @@ import (
 
 const (
 	deleteBackupFilesJobNamePrefix = "delete-"
+	trimBackupFilesJobNamePrefix   = "trim-"
+	copyBackupJobNamePrefix        = "copy-"
+
+	// deletionJobRequeueInterval is the interval to wait for a free slot when the
+	// deletion job concurrency limit is reached.
+	deletionJobRequeueInterval = 5 * time.Second
+	// deletionJobObservedTimeout is the duration to treat a created deletion job as in-flight
+	// even if it is not observed by the informer cache.
+	deletionJobObservedTimeout = time.Minute
 )
 
+// deletionJobLimiter limits the number of in-flight deletion jobs, its state is shared
+// by all Deleters in the process.
+var deletionJobLimiter = &jobLimiter{
+	created: map[types.NamespacedName]time.Time{},
+}
+
+type jobLimiter struct {
+	sync.Mutex
+	// created records the deletion jobs created by this process, which may not be
+	// observed by the informer cache yet.
+	created map[types.NamespacedName]time.Time
+}
+
 type DeletionStatus string
 
 const (
@@ -67,7 +92,8 @@ type Deleter struct {
 
 // DeleteBackupFiles builds a job to delete backup files, and returns the deletion status.
 // If the deletion job exists, it will check the job status and return the corresponding
-// deletion status.
+// deletion status. If the number of in-flight deletion jobs reaches the limit, it returns
+// DeletionStatusDeleting with a RequeueError, and the caller should retry later.
 func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus, error) {
 	backupMethod := backup.Status.BackupMethod
 	if backupMethod != nil && boolptr.IsSetToTrue(backupMethod.SnapshotVolumes) {
@@ -148,6 +174,10 @@ func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus,
 	if preDeleteAction != nil {
 		preJob, err := d.doPreDeleteAction(backup, backupRepo, preDeleteAction, legacyPVCName, backupFilePath)
 		if err != nil {
+			if _, ok := err.(ctrlutil.RequeueError); ok {
+				// wait for the in-flight deletion jobs to be completed
+				return DeletionStatusDeleting, err
+			}
 			return DeletionStatusUnknown, err
 		}
 		_, finishedType, msg := utils.IsJobFinished(preJob)
@@ -159,7 +189,7 @@ func (d *Deleter) DeleteBackupFiles(backup *dpv1alpha1.Backup) (DeletionStatus,
 		}
 	}
 	// do delete action
-	return DeletionStatusDeleting, d.createDeleteBackupFilesJob(jobKey, backup, backupRepo, legacyPVCName)
+	return DeletionStatusDeleting, d.createDeleteBackupFilesJob(jobKey, backup, backupRepo, legacyPVCName, true)
 }
 
 func (d *Deleter) buildDeleteBackupFilesScript(backupPath string) string {
@@ -223,7 +253,8 @@ func (d *Deleter) createDeleteBackupFilesJob(
 	jobKey types.NamespacedName,
 	backup *dpv1alpha1.Backup,
 	backupRepo *dpv1alpha1.BackupRepo,
-	legacyPVCName string) error {
+	legacyPVCName string,
+	limited bool) error {
 
 	runAsUser := int64(0)
 	container := corev1.Container{
@@ -237,14 +268,18 @@ func (d *Deleter) createDeleteBackupFilesJob(
 			RunAsUser:                &runAsUser,
 		},
 	}
-	return d.createDeleteJob(container, jobKey, backup, backupRepo, legacyPVCName)
+	return d.createDeleteJob(container, jobKey, backup, backupRepo, legacyPVCName, limited)
 }
 
+// createDeleteJob creates the job to delete or trim the backup files. If limited is true, the job is
+// labeled as a backup deletion job and is subject to the deletion job concurrency limit, the jobs
+// for trimming the backup and deleting the backup copies are not limited.
 func (d *Deleter) createDeleteJob(container corev1.Container,
 	jobKey types.NamespacedName,
 	backup *dpv1alpha1.Backup,
 	backupRepo *dpv1alpha1.BackupRepo,
-	legacyPVCName string) error {
+	legacyPVCName string,
+	limited bool) error {
 	ctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
 
 	// build pod
@@ -287,8 +322,61 @@ func (d *Deleter) createDeleteJob(container corev1.Container,
 	if err := utils.SetControllerReference(backup, job, d.Scheme); err != nil {
 		return err
 	}
+	if !limited {
+		d.Log.V(1).Info("create a job to delete backup files", "job", job)
+		return client.IgnoreAlreadyExists(d.Client.Create(d.Ctx, job))
+	}
+
+	job.Labels[dptypes.BackupDeletionJobLabelKey] = "true"
+	deletionJobLimiter.Lock()
+	defer deletionJobLimiter.Unlock()
+	reached, err := d.reachDeletionJobConcurrencyLimit()
+	if err != nil {
+		return err
+	}
+	if reached {
+		return ctrlutil.NewRequeueError(deletionJobRequeueInterval,
+			fmt.Sprintf("the number of in-flight deletion jobs reaches the limit %d", viper.GetInt(dptypes.CfgKeyDeletionJobConcurrency)))
+	}
 	d.Log.V(1).Info("create a job to delete backup files", "job", job)
-	return client.IgnoreAlreadyExists(d.Client.Create(d.Ctx, job))
+	if err = d.Client.Create(d.Ctx, job); err != nil {
+		return client.IgnoreAlreadyExists(err)
+	}
+	deletionJobLimiter.created[jobKey] = time.Now()
+	return nil
+}
+
+// reachDeletionJobConcurrencyLimit checks whether the number of in-flight deletion jobs
+// reaches the limit, the backup deletion jobs are counted by the BackupDeletionJobLabelKey label.
+// The caller must hold the lock of deletionJobLimiter.
+func (d *Deleter) reachDeletionJobConcurrencyLimit() (bool, error) {
+	limit := viper.GetInt(dptypes.CfgKeyDeletionJobConcurrency)
+	if limit <= 0 {
+		return false, nil
+	}
+	jobList := &batchv1.JobList{}
+	if err := d.Client.List(d.Ctx, jobList, client.MatchingLabels{
+		dptypes.BackupDeletionJobLabelKey: "true",
+	}); err != nil {
+		return false, err
+	}
+	inFlight := 0
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		// the job has been observed, no need to track it anymore
+		delete(deletionJobLimiter.created, client.ObjectKeyFromObject(job))
+		if finished, _, _ := utils.IsJobFinished(job); !finished {
+			inFlight++
+		}
+	}
+	for key, createdAt := range deletionJobLimiter.created {
+		if time.Since(createdAt) > deletionJobObservedTimeout {
+			delete(deletionJobLimiter.created, key)
+			continue
+		}
+		inFlight++
+	}
+	return inFlight >= limit, nil
 }
 
 func (d *Deleter) getPreDeleteAction(backupMethod *dpv1alpha1.BackupMethod) (*dpv1alpha1.BaseJobActionSpec, error) {
@@ -336,7 +424,96 @@ func (d *Deleter) doPreDeleteAction(
 			RunAsUser:                &runAsUser,
 		},
 	}
-	return preJob, d.createDeleteJob(container, preJobKey, backup, backupRepo, legacyPVCName)
+	return preJob, d.createDeleteJob(container, preJobKey, backup, backupRepo, legacyPVCName, true)
+}
+
+// TrimBackupFiles builds a job to trim the data of the continuous backup before the specified time
+// by the trim action of the ActionSet, and returns the trimming status. The caller must make sure
+// that the backup workload is not running while trimming, and delete the finished job by
+// DeleteTrimJob before trimming again.
+func (d *Deleter) TrimBackupFiles(backup *dpv1alpha1.Backup, before time.Time) (DeletionStatus, error) {
+	jobKey := BuildTrimBackupFilesJobKey(backup)
+	job := &batchv1.Job{}
+	exists, err := ctrlutil.CheckResourceExists(d.Ctx, d.Client, jobKey, job)
+	if err != nil {
+		return DeletionStatusUnknown, err
+	}
+
+	// if trim job exists, check its status
+	if exists {
+		_, finishedType, msg := utils.IsJobFinished(job)
+		switch finishedType {
+		case batchv1.JobComplete:
+			return DeletionStatusSucceeded, nil
+		case batchv1.JobFailed:
+			return DeletionStatusFailed,
+				fmt.Errorf("trim backup files job \"%s\" failed, %s", job.Name, msg)
+		}
+		return DeletionStatusDeleting, nil
+	}
+
+	trimAction, err := d.getTrimAction(backup.Status.BackupMethod)
+	if err != nil {
+		return DeletionStatusUnknown, err
+	}
+	if trimAction == nil {
+		return DeletionStatusUnknown, fmt.Errorf("the trim action is not defined in the actionSet of backup %s", backup.Name)
+	}
+	if backup.Status.BackupRepoName == "" {
+		return DeletionStatusUnknown, fmt.Errorf("the backup repo of backup %s is empty", backup.Name)
+	}
+	backupRepo := &dpv1alpha1.BackupRepo{}
+	if err = d.Client.Get(d.Ctx, client.ObjectKey{Name: backup.Status.BackupRepoName}, backupRepo); err != nil {
+		return DeletionStatusUnknown, err
+	}
+
+	backupFilePath := backup.Status.Path
+	// make sure the path has a leading slash
+	if !strings.HasPrefix(backupFilePath, "/") {
+		backupFilePath = "/" + backupFilePath
+	}
+	runAsUser := int64(0)
+	envVars := []corev1.EnvVar{
+		{Name: dptypes.DPBackupBasePath, Value: backupFilePath},
+		{Name: dptypes.DPBackupName, Value: backup.Name},
+		{Name: dptypes.DPBackupTrimBeforeTime, Value: before.UTC().Format(time.RFC3339)},
+	}
+	envVars = append(envVars, d.actionSet.Spec.Env...)
+	container := corev1.Container{
+		Name:            backup.Name,
+		Command:         trimAction.Command,
+		Image:           common.Expand(trimAction.Image, common.MappingFuncFor(utils.CovertEnvToMap(envVars))),
+		Env:             envVars,
+		ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolptr.False(),
+			RunAsUser:                &runAsUser,
+		},
+	}
+	return DeletionStatusDeleting, d.createDeleteJob(container, jobKey, backup, backupRepo, "", false)
+}
+
+// DeleteTrimJob deletes the finished trim job, so that the backup can be trimmed again.
+func (d *Deleter) DeleteTrimJob(backup *dpv1alpha1.Backup) error {
+	job := &batchv1.Job{}
+	jobKey := BuildTrimBackupFilesJobKey(backup)
+	job.Namespace, job.Name = jobKey.Namespace, jobKey.Name
+	return client.IgnoreNotFound(d.Client.Delete(d.Ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
+
+func (d *Deleter) getTrimAction(backupMethod *dpv1alpha1.BackupMethod) (*dpv1alpha1.BaseJobActionSpec, error) {
+	if backupMethod == nil || backupMethod.ActionSetName == "" {
+		return nil, nil
+	}
+	actionSet, err := utils.GetActionSetByName(d.RequestCtx, d.Client, backupMethod.ActionSetName)
+	if err != nil {
+		return nil, err
+	}
+	d.actionSet = actionSet
+	if actionSet.Spec.Backup == nil {
+		return nil, nil
+	}
+	return actionSet.Spec.Backup.TrimBackup, nil
 }
 
 func (d *Deleter) DeleteVolumeSnapshots(backup *dpv1alpha1.Backup) error {
@@ -368,14 +545,106 @@ func (d *Deleter) DeleteVolumeSnapshots(backup *dpv1alpha1.Backup) error {
 		return nil
 	}
 
+	deleted := map[string]bool{}
 	for i := range snaps.Items {
 		if err := deleteVolumeSnapshot(&snaps.Items[i]); err != nil {
 			return err
 		}
+		deleted[snaps.Items[i].Name] = true
+	}
+
+	// the volume snapshots created by older versions or manually may lack the labels,
+	// look them up by the deterministic names.
+	for _, key := range GetVolumeSnapshotKeys(backup) {
+		if deleted[key.Name] {
+			continue
+		}
+		vs := &vsv1.VolumeSnapshot{}
+		if err := vsCli.Get(d.Ctx, key, vs); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if IsVolumeSnapshotOwnedByOthers(backup, vs) {
+			continue
+		}
+		if err := deleteVolumeSnapshot(vs); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// DeleteBackupCopies deletes the copies of the backup in other backup repos, the copy jobs are
+// deleted first to stop the in-progress copies. It returns DeletionStatusSucceeded only if all
+// the copies are deleted, otherwise the deletion status of the first undeleted copy is returned.
+func (d *Deleter) DeleteBackupCopies(backup *dpv1alpha1.Backup) (DeletionStatus, error) {
+	for _, backupCopy := range backup.Status.Copies {
+		if err := d.deleteCopyJob(backup, backupCopy.RepoName); err != nil {
+			return DeletionStatusUnknown, err
+		}
+		// nothing has been written to the backup repo.
+		if backupCopy.Phase == dpv1alpha1.BackupCopyPhasePending {
+			continue
+		}
+		status, err := d.deleteBackupCopyFiles(backup, backupCopy.RepoName)
+		if status != DeletionStatusSucceeded {
+			return status, err
+		}
+	}
+	return DeletionStatusSucceeded, nil
+}
+
+func (d *Deleter) deleteCopyJob(backup *dpv1alpha1.Backup, repoName string) error {
+	job := &batchv1.Job{}
+	exists, err := ctrlutil.CheckResourceExists(d.Ctx, d.Client, BuildCopyBackupJobKey(backup, repoName), job)
+	if err != nil || !exists {
+		return err
+	}
+	if err = utils.RemoveDataProtectionFinalizer(d.Ctx, d.Client, job); err != nil {
+		return err
+	}
+	return ctrlutil.BackgroundDeleteObject(d.Client, d.Ctx, job)
+}
+
+// deleteBackupCopyFiles builds a job to delete the backup files copied to the backup repo,
+// and returns the deletion status.
+func (d *Deleter) deleteBackupCopyFiles(backup *dpv1alpha1.Backup, repoName string) (DeletionStatus, error) {
+	jobKey := BuildDeleteBackupCopyFilesJobKey(backup, repoName)
+	job := &batchv1.Job{}
+	exists, err := ctrlutil.CheckResourceExists(d.Ctx, d.Client, jobKey, job)
+	if err != nil {
+		return DeletionStatusUnknown, err
+	}
+	if exists {
+		_, finishedType, msg := utils.IsJobFinished(job)
+		switch finishedType {
+		case batchv1.JobComplete:
+			return DeletionStatusSucceeded, nil
+		case batchv1.JobFailed:
+			return DeletionStatusFailed,
+				fmt.Errorf("deletion backup copy files job \"%s\" failed, you can delete it to re-delete the backup copy files, %s", job.Name, msg)
+		}
+		return DeletionStatusDeleting, nil
+	}
+
+	backupRepo := &dpv1alpha1.BackupRepo{}
+	if err = d.Client.Get(d.Ctx, client.ObjectKey{Name: repoName}, backupRepo); err != nil {
+		if apierrors.IsNotFound(err) {
+			return DeletionStatusSucceeded, nil
+		}
+		return DeletionStatusUnknown, err
+	}
+	// the copy shares the path with the backup, see the deletion of the backup files for the check.
+	if backup.Status.Path == "" || !strings.Contains(backup.Status.Path, backup.Name) {
+		d.Log.Info("skip deleting backup copy files because backup file path is invalid",
+			"backupFilePath", backup.Status.Path, "backup", backup.Name, "backupRepo", repoName)
+		return DeletionStatusSucceeded, nil
+	}
+	return DeletionStatusDeleting, d.createDeleteBackupFilesJob(jobKey, backup, backupRepo, "", false)
+}
+
 func BuildDeleteBackupFilesJobKey(backup *dpv1alpha1.Backup, isPreDelete bool) client.ObjectKey {
 	var preDeletePrefix string
 	if isPreDelete {
@@ -387,3 +656,32 @@ func BuildDeleteBackupFilesJobKey(backup *dpv1alpha1.Backup, isPreDelete bool) c
 	}
 	return client.ObjectKey{Namespace: backup.Namespace, Name: jobName}
 }
+
+func BuildTrimBackupFilesJobKey(backup *dpv1alpha1.Backup) client.ObjectKey {
+	jobName := fmt.Sprintf("%s-%s%s", backup.UID[:8], trimBackupFilesJobNamePrefix, backup.Name)
+	if len(jobName) > 63 {
+		jobName = strings.TrimSuffix(jobName[:63], "-")
+	}
+	return client.ObjectKey{Namespace: backup.Namespace, Name: jobName}
+}
+
+func BuildCopyBackupJobKey(backup *dpv1alpha1.Backup, repoName string) client.ObjectKey {
+	return client.ObjectKey{Namespace: backup.Namespace, Name: buildBackupCopyJobName(backup, copyBackupJobNamePrefix, repoName)}
+}
+
+func BuildDeleteBackupCopyFilesJobKey(backup *dpv1alpha1.Backup, repoName string) client.ObjectKey {
+	return client.ObjectKey{Namespace: backup.Namespace,
+		Name: buildBackupCopyJobName(backup, deleteBackupFilesJobNamePrefix+copyBackupJobNamePrefix, repoName)}
+}
+
+// buildBackupCopyJobName builds the name of the job for the copy of the backup in the backup repo.
+// The name is suffixed with the hash of the backup repo name if it is too long, so that the jobs
+// for the backup repos with the same long name prefix are not conflicting.
+func buildBackupCopyJobName(backup *dpv1alpha1.Backup, prefix, repoName string) string {
+	jobName := fmt.Sprintf("%s-%s%s", backup.UID[:8], prefix, repoName)
+	if len(jobName) <= 63 {
+		return jobName
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(repoName)))[:16]
+	return fmt.Sprintf("%s-%s", strings.TrimSuffix(jobName[:63-len(hash)-1], "-"), hash)
+}
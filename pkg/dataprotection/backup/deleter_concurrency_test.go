@@ -0,0 +1,133 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+func TestTrimJobsDoNotBlockBackupDeletion(t *testing.T) {
+	const (
+		namespace     = "default"
+		repoName      = "test-repo"
+		actionSetName = "test-actionset"
+	)
+	viper.Set(dptypes.CfgKeyDeletionJobConcurrency, 1)
+	defer viper.Set(dptypes.CfgKeyDeletionJobConcurrency, 0)
+	deletionJobLimiter.created = map[types.NamespacedName]time.Time{}
+	defer func() { deletionJobLimiter.created = map[types.NamespacedName]time.Time{} }()
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, dpv1alpha1.AddToScheme(scheme))
+
+	newBackup := func(name string) *dpv1alpha1.Backup {
+		return &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(name + "-0000-0000-0000-000000000000")},
+			Status: dpv1alpha1.BackupStatus{
+				BackupRepoName: repoName,
+				Path:           "/" + namespace + "/" + name,
+				BackupMethod:   &dpv1alpha1.BackupMethod{Name: "test-method", ActionSetName: actionSetName},
+			},
+		}
+	}
+	repo := &dpv1alpha1.BackupRepo{ObjectMeta: metav1.ObjectMeta{Name: repoName}}
+	actionSet := &dpv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: actionSetName},
+		Spec: dpv1alpha1.ActionSetSpec{
+			BackupType: dpv1alpha1.BackupTypeContinuous,
+			Backup: &dpv1alpha1.BackupActionSpec{
+				TrimBackup: &dpv1alpha1.BaseJobActionSpec{Image: "busybox", Command: []string{"sh", "-c", "exit 0"}},
+			},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(repo, actionSet).Build()
+	d := &Deleter{
+		RequestCtx: ctrlutil.RequestCtx{Ctx: context.Background(), Log: logr.Discard()},
+		Client:     cli,
+		Scheme:     scheme,
+	}
+
+	// trimming a continuous backup, the trim job is not counted by the limit
+	trimmed := newBackup("trimmed")
+	status, err := d.TrimBackupFiles(trimmed, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, DeletionStatusDeleting, status)
+	trimJob := &batchv1.Job{}
+	assert.NoError(t, cli.Get(d.Ctx, BuildTrimBackupFilesJobKey(trimmed), trimJob))
+	assert.NotContains(t, trimJob.Labels, dptypes.BackupDeletionJobLabelKey)
+
+	// deleting a backup while the trim job is in flight
+	deleted := newBackup("deleted")
+	status, err = d.DeleteBackupFiles(deleted)
+	assert.NoError(t, err)
+	assert.Equal(t, DeletionStatusDeleting, status)
+	deletionJob := &batchv1.Job{}
+	assert.NoError(t, cli.Get(d.Ctx, BuildDeleteBackupFilesJobKey(deleted, false), deletionJob))
+	assert.Equal(t, "true", deletionJob.Labels[dptypes.BackupDeletionJobLabelKey])
+
+	// deleting another backup, it waits for the in-flight deletion job
+	waiting := newBackup("waiting")
+	status, err = d.DeleteBackupFiles(waiting)
+	assert.Equal(t, DeletionStatusDeleting, status)
+	_, ok := err.(ctrlutil.RequeueError)
+	assert.True(t, ok, "expected a requeue error, got: %v", err)
+	exists, err := ctrlutil.CheckResourceExists(d.Ctx, cli, BuildDeleteBackupFilesJobKey(waiting, false), &batchv1.Job{})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	jobList := &batchv1.JobList{}
+	assert.NoError(t, cli.List(d.Ctx, jobList, client.MatchingLabels{dptypes.BackupDeletionJobLabelKey: "true"}))
+	assert.Len(t, jobList.Items, 1)
+}
+
+func TestBuildCopyBackupJobKey(t *testing.T) {
+	backup := &dpv1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup", UID: "12345678-uid"}}
+	assert.Equal(t, "12345678-copy-repo", BuildCopyBackupJobKey(backup, "repo").Name)
+
+	// the long repo names sharing the same prefix are not truncated to the same job name.
+	prefix := strings.Repeat("repo", 14)
+	key1 := BuildCopyBackupJobKey(backup, prefix+"-region-a")
+	key2 := BuildCopyBackupJobKey(backup, prefix+"-region-b")
+	assert.NotEqual(t, key1.Name, key2.Name)
+	assert.LessOrEqual(t, len(key1.Name), 63)
+	assert.Equal(t, key1, BuildCopyBackupJobKey(backup, prefix+"-region-a"))
+
+	deleteKey := BuildDeleteBackupCopyFilesJobKey(backup, prefix+"-region-a")
+	assert.NotEqual(t, key1.Name, deleteKey.Name)
+	assert.LessOrEqual(t, len(deleteKey.Name), 63)
+}
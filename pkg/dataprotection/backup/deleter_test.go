@@ -24,11 +24,14 @@ import (
 	. "github.com/onsi/gomega"
 
 	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
@@ -187,5 +190,36 @@ var _ = Describe("Backup Deleter Test", func() {
 			Eventually(testapps.CheckObjExists(&testCtx,
 				client.ObjectKeyFromObject(vs), vs, false)).Should(Succeed())
 		})
+
+		It("should delete the legacy volume snapshot without labels", func() {
+			By("mock the legacy volume snapshot named after the backup")
+			backup.Status.BackupMethod = &dpv1alpha1.BackupMethod{
+				SnapshotVolumes: pointer.Bool(true),
+				TargetVolumes:   &dpv1alpha1.TargetVolumeInfo{Volumes: []string{testdp.DataVolumeName}},
+			}
+			legacyVS := testdp.NewVolumeSnapshotFactory(testCtx.DefaultNamespace,
+				dputils.GetBackupVolumeSnapshotName(backup.Name, testdp.DataVolumeName)).
+				SetSourcePVCName(backupPVCName).
+				Create(&testCtx).GetObject()
+			Eventually(testapps.CheckObjExists(&testCtx,
+				client.ObjectKeyFromObject(legacyVS), legacyVS, true)).Should(Succeed())
+
+			By("mock the volume snapshot of another backup with the matching name")
+			otherVS := testdp.NewVolumeSnapshotFactory(testCtx.DefaultNamespace,
+				dputils.GetBackupVolumeSnapshotName(backup.Name, testdp.LogVolumeName)).
+				SetSourcePVCName(backupPVCName).
+				AddLabels(dptypes.BackupNameLabelKey, "other-backup").
+				Create(&testCtx).GetObject()
+			backup.Status.BackupMethod.TargetVolumes.Volumes = append(backup.Status.BackupMethod.TargetVolumes.Volumes, testdp.LogVolumeName)
+
+			By("delete volume snapshots")
+			Expect(deleter.DeleteVolumeSnapshots(backup)).Should(Succeed())
+
+			By("check only the legacy volume snapshot deleted")
+			Eventually(testapps.CheckObjExists(&testCtx,
+				client.ObjectKeyFromObject(legacyVS), legacyVS, false)).Should(Succeed())
+			Consistently(testapps.CheckObjExists(&testCtx,
+				client.ObjectKeyFromObject(otherVS), otherVS, true)).Should(Succeed())
+		})
 	})
 })
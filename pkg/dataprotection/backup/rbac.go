@@ -0,0 +1,115 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// GenerateWorkerRBACName generates the name of the service account of the worker of the backup policy,
+// it is created in the namespace of the backup policy.
+func GenerateWorkerRBACName(backupPolicyName string) string {
+	return fmt.Sprintf("%s-%s", viper.GetString(dptypes.CfgKeyWorkerServiceAccountName), backupPolicyName)
+}
+
+// GenerateWorkerRoleName generates the name of the role and role binding granted to the worker service account
+// of the backup policy for the backup method, the backups of different methods may run concurrently.
+func GenerateWorkerRoleName(backupPolicyName, backupMethodName string) string {
+	return fmt.Sprintf("%s-%s", GenerateWorkerRBACName(backupPolicyName), backupMethodName)
+}
+
+// ComputeWorkerRBAC computes the rules of the role granted to the worker of the backup from what the
+// backup actually needs:
+//   - the job actions read and write the backup repo through the mounted PVC, which needs no rules,
+//     the rules to report the status and the progress are required only if the sync progress is enabled.
+//   - the exec actions run with the exec worker service account, see HasExecActions.
+//   - the volume snapshots are created by the controller, not by the worker.
+func ComputeWorkerRBAC(request *Request) ([]rbacv1.PolicyRule, error) {
+	if request.BackupPolicy == nil {
+		return nil, fmt.Errorf("backup policy is required to compute the worker rules")
+	}
+	if request.BackupMethod == nil {
+		return nil, fmt.Errorf("backup method is required to compute the worker rules")
+	}
+
+	var rules []rbacv1.PolicyRule
+	if request.syncProgressEnabled() {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{dpv1alpha1.GroupVersion.Group},
+				Resources: []string{"backups"},
+				Verbs:     []string{"get"},
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{dpv1alpha1.GroupVersion.Group},
+				Resources: []string{"backups/status"},
+				Verbs:     []string{"get", "patch"},
+			})
+		// the progress is annotated to the job of the backup data action, which is a statefulSet for the continuous backups.
+		// The names of the jobs are unknown before the backups are created, the rule is scoped by the namespaced role.
+		if request.ActionSet.Spec.BackupType != dpv1alpha1.BackupTypeContinuous {
+			rules = append(rules, rbacv1.PolicyRule{
+				APIGroups: []string{"batch"},
+				Resources: []string{"jobs"},
+				Verbs:     []string{"get", "patch"},
+			})
+		}
+	}
+	return rules, nil
+}
+
+// HasExecActions checks if any action of the backup is executed in the target pods, these actions run
+// with the exec worker service account in the namespace of KubeBlocks, which must be bound to the exec
+// worker cluster role in the namespace of the target pods.
+func (r *Request) HasExecActions() bool {
+	hookHasExec := func(hook *dpv1alpha1.BackupHook) bool {
+		return hook != nil && (hook.Target == dpv1alpha1.BackupHookTargetPod || hook.Target == "")
+	}
+	if hookHasExec(r.BackupMethod.PreBackupHook) || hookHasExec(r.BackupMethod.PostBackupHook) {
+		return true
+	}
+	if !r.backupActionSetExists() {
+		return false
+	}
+	for _, actions := range [][]dpv1alpha1.ActionSpec{r.ActionSet.Spec.Backup.PreBackup, r.ActionSet.Spec.Backup.PostBackup} {
+		for _, act := range actions {
+			if act.Exec != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// syncProgressEnabled checks if the backup data action syncs the status and the progress of the backup.
+func (r *Request) syncProgressEnabled() bool {
+	if !r.backupActionSetExists() || r.ActionSet.Spec.Backup.BackupData == nil {
+		return false
+	}
+	syncProgress := r.ActionSet.Spec.Backup.BackupData.SyncProgress
+	return syncProgress != nil && boolptr.IsSetToTrue(syncProgress.Enabled)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+)
+
+func TestComputeWorkerRBAC(t *testing.T) {
+	resources := func(rules []rbacv1.PolicyRule) []string {
+		var res []string
+		for _, rule := range rules {
+			res = append(res, rule.Resources...)
+		}
+		return res
+	}
+	newActionSet := func(backupType dpv1alpha1.BackupType, syncProgress bool, preBackup ...dpv1alpha1.ActionSpec) *dpv1alpha1.ActionSet {
+		return &dpv1alpha1.ActionSet{
+			Spec: dpv1alpha1.ActionSetSpec{
+				BackupType: backupType,
+				Backup: &dpv1alpha1.BackupActionSpec{
+					BackupData: &dpv1alpha1.BackupDataActionSpec{
+						SyncProgress: &dpv1alpha1.SyncProgress{Enabled: &syncProgress},
+					},
+					PreBackup: preBackup,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		method    *dpv1alpha1.BackupMethod
+		actionSet *dpv1alpha1.ActionSet
+		expected  []string
+		hasExec   bool
+	}{
+		{
+			name:      "job-based file backup",
+			method:    &dpv1alpha1.BackupMethod{},
+			actionSet: newActionSet(dpv1alpha1.BackupTypeFull, false),
+		},
+		{
+			name:      "job-based file backup with sync progress",
+			method:    &dpv1alpha1.BackupMethod{},
+			actionSet: newActionSet(dpv1alpha1.BackupTypeFull, true),
+			expected:  []string{"backups", "backups/status", "jobs"},
+		},
+		{
+			name:      "continuous backup with sync progress",
+			method:    &dpv1alpha1.BackupMethod{},
+			actionSet: newActionSet(dpv1alpha1.BackupTypeContinuous, true),
+			expected:  []string{"backups", "backups/status"},
+		},
+		{
+			name:   "exec pre-backup action",
+			method: &dpv1alpha1.BackupMethod{},
+			actionSet: newActionSet(dpv1alpha1.BackupTypeFull, false, dpv1alpha1.ActionSpec{
+				Exec: &dpv1alpha1.ExecActionSpec{Command: []string{"sync"}},
+			}),
+			hasExec: true,
+		},
+		{
+			name: "exec backup hook",
+			method: &dpv1alpha1.BackupMethod{
+				PreBackupHook: &dpv1alpha1.BackupHook{Command: []string{"sync"}},
+			},
+			actionSet: newActionSet(dpv1alpha1.BackupTypeFull, false),
+			hasExec:   true,
+		},
+		{
+			name: "job backup hook",
+			method: &dpv1alpha1.BackupMethod{
+				PreBackupHook: &dpv1alpha1.BackupHook{Target: dpv1alpha1.BackupHookTargetWorkerJob, Image: "busybox"},
+			},
+			actionSet: newActionSet(dpv1alpha1.BackupTypeFull, false),
+		},
+		{
+			name:   "volume snapshot",
+			method: &dpv1alpha1.BackupMethod{SnapshotVolumes: boolptr.True()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &Request{
+				BackupPolicy: &dpv1alpha1.BackupPolicy{},
+				BackupMethod: tt.method,
+				ActionSet:    tt.actionSet,
+			}
+			rules, err := ComputeWorkerRBAC(request)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, resources(rules))
+			assert.Equal(t, tt.hasExec, request.HasExecActions())
+		})
+	}
+
+	_, err := ComputeWorkerRBAC(&Request{BackupMethod: &dpv1alpha1.BackupMethod{}})
+	assert.Error(t, err)
+}
@@ -22,9 +22,11 @@ package backup
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/tracing"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
@@ -43,6 +46,8 @@ const (
 	BackupDataJobNamePrefix      = "dp-backup"
 	prebackupJobNamePrefix       = "dp-prebackup"
 	postbackupJobNamePrefix      = "dp-postbackup"
+	preBackupHookJobNamePrefix   = "dp-prebackuphook"
+	postBackupHookJobNamePrefix  = "dp-postbackuphook"
 	BackupDataContainerName      = "backupdata"
 	SyncProgressContainerName    = "sync-progress"
 	SyncProgressSharedVolumeName = "sync-progress-shared-volume"
@@ -58,11 +63,37 @@ type Request struct {
 	BackupPolicy         *dpv1alpha1.BackupPolicy
 	BackupMethod         *dpv1alpha1.BackupMethod
 	ActionSet            *dpv1alpha1.ActionSet
+	ParentBackup         *dpv1alpha1.Backup
 	TargetPods           []*corev1.Pod
 	BackupRepoPVC        *corev1.PersistentVolumeClaim
 	BackupRepo           *dpv1alpha1.BackupRepo
 	ToolConfigSecret     *corev1.Secret
 	WorkerServiceAccount string
+	// ConnectionCredential overrides the connection credential of the backup policy, it refers to
+	// the secret copied into the namespace of the backup if the target pods are in another namespace.
+	ConnectionCredential *dpv1alpha1.ConnectionCredential
+	// EncryptionConfig is the effective encryption config of the backup, it is either overridden
+	// by the backup or inherited from the backup policy.
+	EncryptionConfig *dpv1alpha1.EncryptionConfig
+}
+
+// getConnectionCredential returns the connection credential to connect to the target pods.
+func (r *Request) getConnectionCredential() *dpv1alpha1.ConnectionCredential {
+	if r.ConnectionCredential != nil {
+		return r.ConnectionCredential
+	}
+	if r.BackupPolicy.Spec.Target == nil {
+		return nil
+	}
+	return r.BackupPolicy.Spec.Target.ConnectionCredential
+}
+
+// getParentBackupName returns the name of the parent backup, the resolved parent backup takes precedence.
+func (r *Request) getParentBackupName() string {
+	if r.ParentBackup != nil {
+		return r.ParentBackup.Name
+	}
+	return utils.GetParentBackupName(r.Backup)
 }
 
 func (r *Request) GetBackupType() string {
@@ -77,6 +108,13 @@ func (r *Request) GetBackupType() string {
 
 // BuildActions builds the actions for the backup.
 func (r *Request) BuildActions() ([]action.Action, error) {
+	_, span := tracing.StartBackupSpan(r.Ctx, "Backup.BuildActions", r.Backup)
+	actions, err := r.buildActions()
+	tracing.EndSpan(span, err)
+	return actions, err
+}
+
+func (r *Request) buildActions() ([]action.Action, error) {
 	var actions []action.Action
 
 	appendIgnoreNil := func(elems ...action.Action) {
@@ -88,6 +126,19 @@ func (r *Request) BuildActions() ([]action.Action, error) {
 		}
 	}
 
+	var preBackupHook, postBackupHook *dpv1alpha1.BackupHook
+	if r.BackupMethod != nil {
+		preBackupHook = r.BackupMethod.PreBackupHook
+		postBackupHook = r.BackupMethod.PostBackupHook
+	}
+
+	// build pre-backup hook actions, they run before any other actions.
+	preBackupHookActions, err := r.buildBackupHookActions(preBackupHook, preBackupHookJobNamePrefix)
+	if err != nil {
+		return nil, err
+	}
+	appendIgnoreNil(preBackupHookActions...)
+
 	// build pre-backup actions
 	preBackupActions, err := r.buildPreBackupActions()
 	if err != nil {
@@ -125,11 +176,64 @@ func (r *Request) BuildActions() ([]action.Action, error) {
 		return nil, err
 	}
 
+	// build post-backup hook actions, they run after all other actions.
+	postBackupHookActions, err := r.buildBackupHookActions(postBackupHook, postBackupHookJobNamePrefix)
+	if err != nil {
+		return nil, err
+	}
+
 	appendIgnoreNil(backupKubeResourcesAction)
 	appendIgnoreNil(postBackupActions...)
+	appendIgnoreNil(postBackupHookActions...)
 	return actions, nil
 }
 
+// IsBackupDataAction checks if the action backs up the data of a target pod.
+func IsBackupDataAction(name string) bool {
+	return strings.HasPrefix(name, BackupDataJobNamePrefix+"-")
+}
+
+// IsPreBackupHookAction checks if the action is built from the pre-backup hook of the backup method.
+func IsPreBackupHookAction(name string) bool {
+	return strings.HasPrefix(name, preBackupHookJobNamePrefix+"-")
+}
+
+// IsPostBackupHookAction checks if the action is built from the post-backup hook of the backup method.
+func IsPostBackupHookAction(name string) bool {
+	return strings.HasPrefix(name, postBackupHookJobNamePrefix+"-")
+}
+
+// BuildActionStatuses builds the initial statuses of the actions. If the job of a job action
+// already exists, e.g. the action statuses were lost after the job had been created, the job
+// is adopted and the action status is reconstructed from it, so no duplicate job will be created.
+func (r *Request) BuildActionStatuses(actions []action.Action, scheme *runtime.Scheme) ([]dpv1alpha1.ActionStatus, error) {
+	statuses := make([]dpv1alpha1.ActionStatus, len(actions))
+	for i, act := range actions {
+		statuses[i] = dpv1alpha1.ActionStatus{
+			Name:       act.GetName(),
+			Phase:      dpv1alpha1.ActionPhaseNew,
+			ActionType: act.Type(),
+		}
+		var jobAction *action.JobAction
+		switch a := act.(type) {
+		case *action.JobAction:
+			jobAction = a
+		case *action.ExecAction:
+			jobAction = &a.JobAction
+		default:
+			continue
+		}
+		job, err := jobAction.GetExistingJob(r.Ctx, r.Client)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			statuses[i] = *jobAction.BuildStatusFromJob(scheme, job)
+		}
+	}
+	return statuses, nil
+}
+
 func (r *Request) buildPreBackupActions() ([]action.Action, error) {
 	if !r.backupActionSetExists() ||
 		len(r.ActionSet.Spec.Backup.PreBackup) == 0 {
@@ -168,6 +272,42 @@ func (r *Request) buildPostBackupActions() ([]action.Action, error) {
 	return actions, nil
 }
 
+func (r *Request) buildBackupHookActions(hook *dpv1alpha1.BackupHook, namePrefix string) ([]action.Action, error) {
+	if hook == nil {
+		return nil, nil
+	}
+
+	var actions []action.Action
+	for i := range r.TargetPods {
+		name := fmt.Sprintf("%s-%d", namePrefix, i)
+		switch hook.Target {
+		case dpv1alpha1.BackupHookTargetWorkerJob:
+			if hook.Image == "" {
+				return nil, fmt.Errorf("image is required for the backup hook %s with target %s", name, hook.Target)
+			}
+			a, err := r.buildJobAction(r.TargetPods[i], name, &dpv1alpha1.JobActionSpec{
+				BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+					Image:   hook.Image,
+					Command: hook.Command,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			actions = append(actions, a)
+		case dpv1alpha1.BackupHookTargetPod, "":
+			actions = append(actions, r.buildExecAction(r.TargetPods[i], name, &dpv1alpha1.ExecActionSpec{
+				Container: hook.Container,
+				Command:   hook.Command,
+				Timeout:   hook.Timeout,
+			}))
+		default:
+			return nil, fmt.Errorf("unsupported target %s of the backup hook %s", hook.Target, name)
+		}
+	}
+	return actions, nil
+}
+
 func (r *Request) buildBackupDataAction(targetPod *corev1.Pod, name string) (action.Action, error) {
 	if !r.backupActionSetExists() ||
 		r.ActionSet.Spec.Backup.BackupData == nil {
@@ -176,7 +316,7 @@ func (r *Request) buildBackupDataAction(targetPod *corev1.Pod, name string) (act
 
 	backupDataAct := r.ActionSet.Spec.Backup.BackupData
 	switch r.ActionSet.Spec.BackupType {
-	case dpv1alpha1.BackupTypeFull:
+	case dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupTypeIncremental:
 		podSpec, err := r.BuildJobActionPodSpec(targetPod, BackupDataContainerName, &backupDataAct.JobActionSpec)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build job action pod spec: %w", err)
@@ -199,6 +339,12 @@ func (r *Request) buildBackupDataAction(targetPod *corev1.Pod, name string) (act
 		if backupDataAct.SyncProgress != nil {
 			r.InjectSyncProgressContainer(podSpec, backupDataAct.SyncProgress, r.buildContinuousSyncProgressCommand())
 		}
+		// scale the backup workload down to zero if the backup is paused or its data is being trimmed,
+		// the trim job must not run concurrently with the backup workload writing to the same path.
+		replicas := int32(1)
+		if r.Spec.Paused || r.Annotations[dptypes.TrimBeforeAnnotationKey] != "" {
+			replicas = 0
+		}
 		return &action.StatefulSetAction{
 			Name: r.Name,
 			ObjectMeta: metav1.ObjectMeta{
@@ -206,10 +352,12 @@ func (r *Request) buildBackupDataAction(targetPod *corev1.Pod, name string) (act
 				Name:      r.Name,
 				Labels:    BuildBackupWorkloadLabels(r.Backup),
 			},
-			Replicas:  pointer.Int32(int32(1)),
-			Backup:    r.Backup,
-			PodSpec:   podSpec,
-			ActionSet: r.ActionSet,
+			Replicas:         pointer.Int32(replicas),
+			Backup:           r.Backup,
+			PodSpec:          podSpec,
+			ActionSet:        r.ActionSet,
+			ToolConfigSecret: r.ToolConfigSecret,
+			RetryPolicy:      r.BackupMethod.RetryPolicy,
 		}, nil
 	}
 	return nil, fmt.Errorf("unsupported backup type %s", r.ActionSet.Spec.BackupType)
@@ -249,9 +397,22 @@ func (r *Request) buildCreateVolumeSnapshotAction(targetPod *corev1.Pod, name st
 		},
 		Owner:                         r.Backup,
 		PersistentVolumeClaimWrappers: pvcs,
+		VolumeSnapshotClassName:       r.GetVolumeSnapshotClassName(),
 	}, nil
 }
 
+// GetVolumeSnapshotClassName returns the name of the VolumeSnapshotClass pinned by the backup or the backup method,
+// it returns an empty string if the class should be selected by the CSI driver of the volumes.
+func (r *Request) GetVolumeSnapshotClassName() string {
+	if name := r.Backup.Annotations[dptypes.VolumeSnapshotClassAnnotationKey]; name != "" {
+		return name
+	}
+	if r.BackupMethod != nil {
+		return r.BackupMethod.VolumeSnapshotClassName
+	}
+	return ""
+}
+
 // TODO(ldm): implement this
 func (r *Request) buildBackupKubeResourcesAction() (action.Action, error) {
 	return nil, nil
@@ -268,7 +429,13 @@ func (r *Request) buildAction(targetPod *corev1.Pod,
 	}
 	switch {
 	case act.Exec != nil:
-		return r.buildExecAction(targetPod, name, act.Exec), nil
+		exec := act.Exec
+		// the target container of the backup method overrides the container of the ActionSet.
+		if r.BackupMethod.TargetContainerName != "" {
+			exec = exec.DeepCopy()
+			exec.Container = r.BackupMethod.TargetContainerName
+		}
+		return r.buildExecAction(targetPod, name, exec), nil
 	case act.Job != nil:
 		return r.buildJobAction(targetPod, name, act.Job)
 	}
@@ -283,7 +450,10 @@ func (r *Request) buildExecAction(targetPod *corev1.Pod,
 	// create exec job in kubeblocks namespace for security
 	objectMeta.Namespace = viper.GetString(constant.CfgKeyCtrlrMgrNS)
 	containerName := exec.Container
-	if exec.Container == "" {
+	if containerName == "" {
+		containerName = r.BackupMethod.TargetContainerName
+	}
+	if containerName == "" {
 		containerName = targetPod.Spec.Containers[0].Name
 	}
 	return &action.ExecAction{
@@ -332,7 +502,7 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 			},
 			{
 				Name:  dptypes.DPParentBackupName,
-				Value: r.Backup.Spec.ParentBackupName,
+				Value: r.getParentBackupName(),
 			},
 			{
 				Name:  dptypes.DPTargetPodName,
@@ -350,12 +520,39 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 				Name:  dptypes.DPBackupInfoFile,
 				Value: SyncProgressSharedMountPath + "/" + BackupInfoFileName,
 			},
+			{
+				Name:  dptypes.DPBackupProgressFile,
+				Value: SyncProgressSharedMountPath + "/" + BackupProgressFileName,
+			},
 			{
 				Name:  dptypes.DPTTL,
 				Value: r.Spec.RetentionPeriod.String(),
 			},
 		}
-		envVars = append(envVars, utils.BuildEnvByCredential(targetPod, r.BackupPolicy.Spec.Target.ConnectionCredential)...)
+		// the incremental backup builds on the data of the parent backup.
+		if r.ParentBackup != nil {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  dptypes.DPParentBackupBasePath,
+				Value: r.ParentBackup.Status.Path,
+			}, corev1.EnvVar{
+				Name:  dptypes.DPParentKopiaRepoPath,
+				Value: r.ParentBackup.Status.KopiaRepoPath,
+			})
+		}
+		// the actions only back up the specified sub paths of the target volumes.
+		if r.BackupMethod.TargetVolumes != nil && len(r.BackupMethod.TargetVolumes.VolumeSubPaths) > 0 {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  dptypes.DPBackupVolumeSubPaths,
+				Value: strings.Join(r.BackupMethod.TargetVolumes.VolumeSubPaths, ","),
+			})
+		}
+		if r.BackupMethod.TargetContainerName != "" {
+			envVars = append(envVars, corev1.EnvVar{
+				Name:  dptypes.DPTargetContainerName,
+				Value: r.BackupMethod.TargetContainerName,
+			})
+		}
+		envVars = append(envVars, utils.BuildEnvByCredential(targetPod, r.BackupMethod.TargetContainerName, r.getConnectionCredential(), r.Namespace)...)
 		if r.ActionSet != nil {
 			envVars = append(envVars, r.ActionSet.Spec.Env...)
 		}
@@ -368,7 +565,7 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 		setKBClusterEnv(dptypes.ClusterUIDLabelKey, constant.KBEnvClusterUID)
 		setKBClusterEnv(constant.AppInstanceLabelKey, constant.KBEnvClusterName)
 		setKBClusterEnv(constant.KBAppComponentLabelKey, constant.KBEnvCompName)
-		envVars = append(envVars, corev1.EnvVar{Name: constant.KBEnvNamespace, Value: r.Namespace})
+		envVars = append(envVars, corev1.EnvVar{Name: constant.KBEnvNamespace, Value: targetPod.Namespace})
 		return utils.MergeEnv(envVars, r.BackupMethod.Env)
 	}
 
@@ -406,6 +603,13 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 		return volumesMount
 	}
 
+	// the volumes of the target pod can not be mounted by the backup pod in another namespace.
+	if runOnTargetPodNode() && targetPod.Namespace != r.Namespace &&
+		len(getVolumesByVolumeInfo(targetPod, r.BackupMethod.TargetVolumes)) > 0 {
+		return nil, fmt.Errorf("the volumes of the target pod %s/%s can not be mounted in namespace %s",
+			targetPod.Namespace, targetPod.Name, r.Namespace)
+	}
+
 	runAsUser := int64(0)
 	env := buildEnv()
 	container := corev1.Container{
@@ -452,26 +656,80 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 			return nil, err
 		}
 	}
+	applyWorkerPodSpec(podSpec, r.BackupPolicy.Spec.WorkerPodSpec, runOnTargetPodNode())
 
 	utils.InjectDatasafed(podSpec, r.BackupRepo, RepoVolumeMountPath,
 		r.Status.EncryptionConfig, r.Status.KopiaRepoPath)
+	// datasafed maps the progress of the kopia snapshots to the progress file of the backup.
+	if r.Status.KopiaRepoPath != "" {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name:  dptypes.DPDatasafedProgressFile,
+			Value: SyncProgressSharedMountPath + "/" + BackupProgressFileName,
+		})
+	}
 	return podSpec, nil
 }
 
+// applyWorkerPodSpec applies the scheduling constraints of the backup policy to the worker pod.
+// The node selector and affinity are ignored if the worker pod runs on the target pod node,
+// otherwise they may conflict with the node pinning.
+func applyWorkerPodSpec(podSpec *corev1.PodSpec, workerPodSpec *dpv1alpha1.WorkerPodSpec, runOnTargetPodNode bool) {
+	if workerPodSpec == nil {
+		return
+	}
+	// copy the tolerations, which may be shared with the target pod.
+	tolerations := make([]corev1.Toleration, 0, len(podSpec.Tolerations)+len(workerPodSpec.Tolerations))
+	tolerations = append(tolerations, podSpec.Tolerations...)
+	podSpec.Tolerations = append(tolerations, workerPodSpec.Tolerations...)
+	if workerPodSpec.PriorityClassName != "" {
+		podSpec.PriorityClassName = workerPodSpec.PriorityClassName
+	}
+	if runOnTargetPodNode {
+		return
+	}
+	if len(workerPodSpec.NodeSelector) > 0 {
+		podSpec.NodeSelector = workerPodSpec.NodeSelector
+	}
+	if workerPodSpec.Affinity != nil {
+		podSpec.Affinity = workerPodSpec.Affinity
+	}
+}
+
 func (r *Request) buildSyncProgressCommand() string {
 	// sync progress script will wait for the backup info file to be created,
 	// if the file is created, it will update the backup status and exit.
 	// If an exit file named with the backup info file with .exit suffix exists,
 	// it indicates that the container for backing up data exited abnormally,
 	// this script will exit.
+	// While waiting, the progress written to the progress file is published
+	// to the annotation of the backup job, which is read by the job action.
 	return fmt.Sprintf(`
 set -o errexit
 set -o nounset
 
+last_progress=
+function update_backup_progress() {
+  local progress_file="$1"
+  local namespace="$2"
+  if [ -z "${%s:-}" ] || [ -z "$progress_file" ] || [ ! -f "$progress_file" ]; then
+    return 0
+  fi
+  local progress=$(cat $progress_file)
+  if [ "$progress" == "$last_progress" ]; then
+    return 0
+  fi
+  if kubectl -n "$namespace" annotate jobs.batch "${%s}" --overwrite "%s=${progress}"; then
+    last_progress="$progress"
+  fi
+}
+
 function update_backup_stauts() {
   local backup_info_file="$1"
   local exit_file="$1.exit"
   local sleep_seconds="$2"
+  local namespace="$3"
+  local backup_name="$4"
+  local progress_file="$5"
   while true; do 
     if [ -f "$exit_file" ]; then
       echo "exit file $exit_file exists, exit"
@@ -480,18 +738,18 @@ function update_backup_stauts() {
     if [ -f "$backup_info_file" ]; then
       break
     fi
+    update_backup_progress "$progress_file" "$namespace"
     echo "backup info file not exists, wait for ${sleep_seconds}s"
     sleep $sleep_seconds
   done
   local backup_info=$(cat $backup_info_file)
   echo backupInfo:${backup_info}
-  local namespace="$3"
-  local backup_name="$4"
   status="{\"status\":${backup_info}}"
   kubectl -n "$namespace" patch backups.dataprotection.kubeblocks.io "$backup_name" --subresource=status --type=merge --patch "${status}"
 }
-update_backup_stauts ${%s} ${%s} %s %s
-`, dptypes.DPBackupInfoFile, dptypes.DPCheckInterval, r.Backup.Namespace, r.Backup.Name)
+update_backup_stauts ${%s} ${%s} %s %s "${%s:-}"
+`, dptypes.DPBackupJobName, dptypes.DPBackupJobName, dptypes.BackupProgressAnnotationKey,
+		dptypes.DPBackupInfoFile, dptypes.DPCheckInterval, r.Backup.Namespace, r.Backup.Name, dptypes.DPBackupProgressFile)
 }
 
 func (r *Request) buildContinuousSyncProgressCommand() string {
@@ -560,6 +818,11 @@ func (r *Request) InjectSyncProgressContainer(podSpec *corev1.PodSpec,
 		corev1.EnvVar{
 			Name:  dptypes.DPCheckInterval,
 			Value: fmt.Sprintf("%d", checkIntervalSeconds)},
+		corev1.EnvVar{
+			Name: dptypes.DPBackupJobName,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['job-name']"},
+			}},
 	)
 	container.Args = []string{command}
 	podSpec.Containers = append(podSpec.Containers, *container)
@@ -24,11 +24,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
@@ -133,6 +135,55 @@ var _ = Describe("Request Test", func() {
 				Expect(err).NotTo(HaveOccurred())
 			})
 
+			It("should apply the worker pod spec to the backup job", func() {
+				workerPodSpec := &dpv1alpha1.WorkerPodSpec{
+					Tolerations: []corev1.Toleration{{
+						Key:      "dedicated",
+						Operator: corev1.TolerationOpEqual,
+						Value:    "backup",
+						Effect:   corev1.TaintEffectNoSchedule,
+					}},
+					NodeSelector: map[string]string{"node-role": "backup"},
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								LabelSelector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{constant.RoleLabelKey: constant.Leader},
+								},
+								TopologyKey: corev1.LabelHostname,
+							}},
+						},
+					},
+					PriorityClassName: "backup-priority",
+				}
+				backupPolicy.Spec.WorkerPodSpec = workerPodSpec
+				// the node selector and affinity are ignored if the job runs on the target pod node.
+				actionSet.Spec.Backup.BackupData.RunOnTargetPodNode = boolptr.False()
+				request.Backup = backup
+				request.ActionSet = actionSet
+				request.TargetPods = []*corev1.Pod{targetPod}
+				request.BackupPolicy = backupPolicy
+				request.BackupMethod = &backupPolicy.Spec.BackupMethods[0]
+				request.BackupRepo = backupRepo
+				actions, err := request.BuildActions()
+				Expect(err).NotTo(HaveOccurred())
+
+				var jobActions int
+				for _, act := range actions {
+					jobAction, ok := act.(*action.JobAction)
+					if !ok {
+						continue
+					}
+					jobActions++
+					podSpec := jobAction.PodSpec
+					Expect(podSpec.Tolerations).Should(ContainElement(workerPodSpec.Tolerations[0]))
+					Expect(podSpec.NodeSelector).Should(Equal(workerPodSpec.NodeSelector))
+					Expect(podSpec.Affinity).Should(Equal(workerPodSpec.Affinity))
+					Expect(podSpec.PriorityClassName).Should(Equal(workerPodSpec.PriorityClassName))
+				}
+				Expect(jobActions).ShouldNot(BeZero())
+			})
+
 			It("build create volume snapshot action", func() {
 				request.TargetPods = []*corev1.Pod{targetPod}
 				request.BackupMethod = &dpv1alpha1.BackupMethod{
@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -44,6 +45,10 @@ import (
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
+// backupNameSuffixLength is the length of the random suffix appended to the backup name
+// when the name is used by an existing backup.
+const backupNameSuffixLength = 5
+
 type Scheduler struct {
 	intctrlutil.RequestCtx
 	Client               client.Client
@@ -85,6 +90,21 @@ func (s *Scheduler) validate() error {
 		return fmt.Errorf("backup method %s is not in backup policy %s/%s",
 			sp.BackupMethod, s.BackupPolicy.Namespace, s.BackupPolicy.Name)
 	}
+
+	for i := range s.BackupSchedule.Spec.Schedules {
+		sp := &s.BackupSchedule.Spec.Schedules[i]
+		if sp.NameTemplate == "" {
+			continue
+		}
+		// check the name rendered with the actual values, the schedule time is always of the same length.
+		name, err := s.renderBackupName(sp, dpv1alpha1.BackupNameScheduleTimeLayout)
+		if err != nil {
+			return err
+		}
+		if err = dpv1alpha1.ValidateBackupName(name); err != nil {
+			return fmt.Errorf("invalid name template of backup method %s: %s", sp.BackupMethod, err.Error())
+		}
+	}
 	return nil
 }
 
@@ -167,8 +187,18 @@ func (s *Scheduler) buildCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy, cron
 }
 
 func (s *Scheduler) buildPodSpec(schedulePolicy *dpv1alpha1.SchedulePolicy) (*corev1.PodSpec, error) {
+	backupName, err := s.generateBackupName(schedulePolicy)
+	if err != nil {
+		return nil, err
+	}
 	// TODO(ldm): add backup deletionPolicy
 	createBackupCmd := fmt.Sprintf(`
+backupName="%s"
+# append a short random suffix if the backup name is used by an existing backup
+if kubectl get backups.dataprotection.kubeblocks.io "${backupName}" -n %s >/dev/null 2>&1; then
+  suffix=$(head -c 16 /dev/urandom | md5sum | cut -c1-%d)
+  backupName="$(echo "${backupName}" | cut -c1-%d | sed 's/-*$//')-${suffix}"
+fi
 kubectl create -f - <<EOF
 apiVersion: dataprotection.kubeblocks.io/v1alpha1
 kind: Backup
@@ -176,14 +206,18 @@ metadata:
   labels:
     dataprotection.kubeblocks.io/autobackup: "true"
     dataprotection.kubeblocks.io/backup-schedule: "%s"
-  name: %s
+  annotations:
+    %s: "%s"
+  name: ${backupName}
   namespace: %s
 spec:
   backupPolicyName: %s
   backupMethod: %s
   retentionPeriod: %s
 EOF
-`, s.BackupSchedule.Name, s.generateBackupName(), s.BackupSchedule.Namespace,
+`, backupName, s.BackupSchedule.Namespace,
+		backupNameSuffixLength, validation.DNS1123LabelMaxLength-backupNameSuffixLength-1,
+		s.BackupSchedule.Name, dptypes.CreatedByAnnotationKey, s.createdBy(), s.BackupSchedule.Namespace,
 		s.BackupPolicy.Name, schedulePolicy.BackupMethod,
 		schedulePolicy.RetentionPeriod)
 
@@ -266,17 +300,45 @@ func (s *Scheduler) reconcileCronJob(schedulePolicy *dpv1alpha1.SchedulePolicy)
 	return s.Client.Patch(s.Ctx, cronJob, patch)
 }
 
-func (s *Scheduler) generateBackupName() string {
-	target := s.BackupPolicy.Spec.Target
+// generateBackupName generates the name of the backups created by the cronjob, the schedule
+// time in the name is evaluated by the shell when the backup is created.
+func (s *Scheduler) generateBackupName(schedulePolicy *dpv1alpha1.SchedulePolicy) (string, error) {
+	scheduleTime := "$(date -u +'%Y%m%d%H%M%S')"
+	if schedulePolicy.NameTemplate != "" {
+		return s.renderBackupName(schedulePolicy, scheduleTime)
+	}
 
 	// if cluster name can be found in target labels, use it as backup name prefix
-	backupNamePrefix := target.PodSelector.MatchLabels[constant.AppInstanceLabelKey]
+	backupNamePrefix := s.getTargetLabel(constant.AppInstanceLabelKey)
 
 	// if cluster name can not be found, use backup schedule name as backup name prefix
 	if backupNamePrefix == "" {
 		backupNamePrefix = s.BackupSchedule.Name
 	}
-	return backupNamePrefix + "-$(date -u +'%Y%m%d%H%M%S')"
+	return backupNamePrefix + "-" + scheduleTime, nil
+}
+
+// renderBackupName renders the name template of the schedule policy with the backup policy target.
+func (s *Scheduler) renderBackupName(schedulePolicy *dpv1alpha1.SchedulePolicy, scheduleTime string) (string, error) {
+	return dpv1alpha1.RenderBackupNameTemplate(schedulePolicy.NameTemplate, map[string]string{
+		dpv1alpha1.BackupNameVarClusterName:   s.getTargetLabel(constant.AppInstanceLabelKey),
+		dpv1alpha1.BackupNameVarComponentName: s.getTargetLabel(constant.KBAppComponentLabelKey),
+		dpv1alpha1.BackupNameVarMethod:        schedulePolicy.BackupMethod,
+		dpv1alpha1.BackupNameVarScheduleTime:  scheduleTime,
+	})
+}
+
+// createdBy returns the value of the created-by annotation of the backups created by the schedule.
+func (s *Scheduler) createdBy() string {
+	return dptypes.CreatedBySchedulePrefix + s.BackupSchedule.Name
+}
+
+func (s *Scheduler) getTargetLabel(key string) string {
+	target := s.BackupPolicy.Spec.Target
+	if target == nil || target.PodSelector == nil || target.PodSelector.LabelSelector == nil {
+		return ""
+	}
+	return target.PodSelector.MatchLabels[key]
 }
 
 func (s *Scheduler) reconcileForContinuous(schedulePolicy *dpv1alpha1.SchedulePolicy) error {
@@ -300,6 +362,7 @@ func (s *Scheduler) reconcileForContinuous(schedulePolicy *dpv1alpha1.SchedulePo
 			return nil
 		}
 		backup.Name = backupName
+		backup.Annotations = map[string]string{dptypes.CreatedByAnnotationKey: s.createdBy()}
 		backup.Namespace = s.BackupSchedule.Namespace
 		backup.Spec.BackupMethod = schedulePolicy.BackupMethod
 		backup.Spec.BackupPolicyName = s.BackupSchedule.Spec.BackupPolicyName
@@ -314,7 +377,21 @@ func (s *Scheduler) reconcileForContinuous(schedulePolicy *dpv1alpha1.SchedulePo
 		// if schedule is enabled and backup already is Completed/Failed, update phase to running
 		backup.Status.Phase = dpv1alpha1.BackupPhaseRunning
 		backup.Status.FailureReason = ""
-		return s.Client.Status().Patch(s.Ctx, backup, patch)
+		backup.Status.CompletionReason = ""
+		// resume into the same archive path when the backup was completed by disabling the schedule.
+		resumePath, resumable := backup.Annotations[dptypes.ResumeArchivePathAnnotationKey]
+		if resumable && resumePath != "" {
+			backup.Status.Path = resumePath
+		}
+		if err = s.Client.Status().Patch(s.Ctx, backup, patch); err != nil {
+			return err
+		}
+		if !resumable {
+			return nil
+		}
+		patch = client.MergeFrom(backup.DeepCopy())
+		delete(backup.Annotations, dptypes.ResumeArchivePathAnnotationKey)
+		return s.Client.Patch(s.Ctx, backup, patch)
 	}
 	if backup.Annotations == nil {
 		backup.Annotations = map[string]string{}
@@ -20,14 +20,25 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package backup
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
@@ -110,6 +121,99 @@ var _ = Describe("Scheduler Test", func() {
 				}
 				Expect(scheduler.Schedule()).ShouldNot(Succeed())
 			})
+
+			It("schedule should fail if the rendered backup name is invalid", func() {
+				scheduler.BackupSchedule = backupSchedule
+				scheduler.BackupPolicy = backupPolicy
+				for i := range scheduler.BackupSchedule.Spec.Schedules {
+					scheduler.BackupSchedule.Spec.Schedules[i].NameTemplate =
+						strings.Repeat("a", 50) + "-$(CLUSTER_NAME)-$(SCHEDULE_TIME)"
+				}
+				Expect(scheduler.Schedule()).Should(MatchError(ContainSubstring("invalid name template")))
+			})
 		})
 	})
 })
+
+func TestScheduledBackupName(t *testing.T) {
+	if _, err := exec.LookPath("md5sum"); err != nil {
+		t.Skip("md5sum is required to run the backup schedule script")
+	}
+	const (
+		clusterName = "mycluster"
+		methodName  = "xtrabackup"
+	)
+	scheduler := &Scheduler{
+		BackupSchedule: &dpv1alpha1.BackupSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-schedule", Namespace: "default"},
+		},
+		BackupPolicy: &dpv1alpha1.BackupPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-policy", Namespace: "default"},
+			Spec: dpv1alpha1.BackupPolicySpec{
+				Target: &dpv1alpha1.BackupTarget{
+					PodSelector: &dpv1alpha1.PodSelector{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								constant.AppInstanceLabelKey:    clusterName,
+								constant.KBAppComponentLabelKey: "mysql",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// runScript runs the script of the backup schedule with a fake kubectl, the existing
+	// backup is found by kubectl get, and the created backup is returned.
+	runScript := func(t *testing.T, nameTemplate, existingBackup string) *dpv1alpha1.Backup {
+		podSpec, err := scheduler.buildPodSpec(&dpv1alpha1.SchedulePolicy{
+			BackupMethod: methodName,
+			NameTemplate: nameTemplate,
+		})
+		assert.NoError(t, err)
+		dir := t.TempDir()
+		manifest := filepath.Join(dir, "backup.yaml")
+		fakeKubectl := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "get" ]; then
+  [ "$3" = "%s" ] && exit 0
+  exit 1
+fi
+cat > %s
+`, existingBackup, manifest)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte(fakeKubectl), 0755))
+		cmd := exec.Command(podSpec.Containers[0].Command[0], append(podSpec.Containers[0].Command[1:], podSpec.Containers[0].Args...)...)
+		cmd.Env = append(os.Environ(), "PATH="+dir+":"+os.Getenv("PATH"))
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+		data, err := os.ReadFile(manifest)
+		assert.NoError(t, err)
+		backup := &dpv1alpha1.Backup{}
+		assert.NoError(t, yaml.Unmarshal(data, backup))
+		return backup
+	}
+
+	t.Run("default name", func(t *testing.T) {
+		backup := runScript(t, "", "")
+		assert.Regexp(t, `^mycluster-\d{14}$`, backup.Name)
+		assert.Equal(t, "schedule/test-schedule", backup.Annotations[dptypes.CreatedByAnnotationKey])
+	})
+
+	t.Run("rendered name", func(t *testing.T) {
+		assert.Regexp(t, `^mycluster-mysql-xtrabackup-\d{14}$`,
+			runScript(t, "$(CLUSTER_NAME)-$(COMPONENT_NAME)-$(METHOD)-$(SCHEDULE_TIME)", "").Name)
+		assert.Equal(t, "daily-mycluster", runScript(t, "daily-$(CLUSTER_NAME)", "another-backup").Name)
+	})
+
+	t.Run("rendered name collides with an existing backup", func(t *testing.T) {
+		name := runScript(t, "daily-$(CLUSTER_NAME)", "daily-mycluster").Name
+		assert.Regexp(t, `^daily-mycluster-[0-9a-f]{5}$`, name)
+	})
+
+	t.Run("long rendered name collides with an existing backup", func(t *testing.T) {
+		nameTemplate := strings.Repeat("a", 51) + "-$(CLUSTER_NAME)"
+		name := runScript(t, nameTemplate, strings.Repeat("a", 51)+"-mycluster").Name
+		assert.Regexp(t, `^a{51}-myclu-[0-9a-f]{5}$`, name)
+		assert.NoError(t, dpv1alpha1.ValidateBackupName(name))
+	})
+}
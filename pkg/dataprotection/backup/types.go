@@ -21,11 +21,17 @@ package backup
 
 // FormatVersion is the backup file format version, including major, minor, and patch version.
 const (
-	FormatVersion = "0.1.0"
+	FormatVersion = "0.2.0"
+
+	// legacyFormatVersion is the format version whose backup path does not contain the cluster UID.
+	legacyFormatVersion = "0.1.0"
 
 	// RepoVolumeMountPath is the backup repo volume mount path.
 	RepoVolumeMountPath = "/backupdata"
 
 	// BackupInfoFileName is the backup info file name in the backup path.
 	BackupInfoFileName = "backup.info"
+
+	// BackupProgressFileName is the file name to which the backup job writes its progress.
+	BackupProgressFileName = "backup.progress"
 )
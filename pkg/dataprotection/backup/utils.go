@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/rogpeppe/go-internal/semver"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +37,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/action"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 )
 
 func getVolumesByNames(pod *corev1.Pod, volumeNames []string) []corev1.Volume {
@@ -84,9 +87,11 @@ func getVolumeMountsByVolumeInfo(pod *corev1.Pod, info *dpv1alpha1.TargetVolumeI
 	var mounts []corev1.VolumeMount
 	for _, v := range pod.Spec.Volumes {
 		for _, m := range info.VolumeMounts {
-			if v.Name == m.Name {
-				mounts = append(mounts, m)
+			if v.Name != m.Name {
+				continue
 			}
+			// only mount the sub paths to back up if specified.
+			mounts = append(mounts, dputils.BuildVolumeSubPathMounts(m, info.VolumeSubPaths)...)
 		}
 	}
 	return mounts
@@ -138,6 +143,33 @@ func BuildBackupWorkloadLabels(backup *dpv1alpha1.Backup) map[string]string {
 	return labels
 }
 
+// GetVolumeSnapshotKeys returns the keys of the volume snapshots created by the backup,
+// they are named after the backup and the target volumes, see dputils.GetBackupVolumeSnapshotName.
+func GetVolumeSnapshotKeys(backup *dpv1alpha1.Backup) []client.ObjectKey {
+	method := backup.Status.BackupMethod
+	if method == nil || !boolptr.IsSetToTrue(method.SnapshotVolumes) || method.TargetVolumes == nil {
+		return nil
+	}
+	keys := make([]client.ObjectKey, 0, len(method.TargetVolumes.Volumes))
+	for _, v := range method.TargetVolumes.Volumes {
+		keys = append(keys, client.ObjectKey{
+			Namespace: backup.Namespace,
+			Name:      dputils.GetBackupVolumeSnapshotName(backup.Name, v),
+		})
+	}
+	return keys
+}
+
+// IsVolumeSnapshotOwnedByOthers checks if the volume snapshot is labeled or controlled by another object,
+// such volume snapshots should not be adopted or deleted by the backup even if the names match.
+func IsVolumeSnapshotOwnedByOthers(backup *dpv1alpha1.Backup, vs metav1.Object) bool {
+	if name, ok := vs.GetLabels()[types.BackupNameLabelKey]; ok && name != backup.Name {
+		return true
+	}
+	owner := metav1.GetControllerOf(vs)
+	return owner != nil && owner.UID != backup.UID
+}
+
 func buildBackupJobObjMeta(backup *dpv1alpha1.Backup, prefix string) *metav1.ObjectMeta {
 	return &metav1.ObjectMeta{
 		Name:      GenerateBackupJobName(backup, prefix),
@@ -155,6 +187,12 @@ func GenerateBackupJobName(backup *dpv1alpha1.Backup, prefix string) string {
 	return name
 }
 
+// GenerateCrossNamespaceCredentialSecretName generates the name of the connection credential secret
+// which is copied into the namespace of the backup from the namespace of the target pods.
+func GenerateCrossNamespaceCredentialSecretName(backup *dpv1alpha1.Backup) string {
+	return fmt.Sprintf("%s-credential-%s", backup.Name, backup.UID[:8])
+}
+
 // GenerateCRNameByBackupSchedule generate a CR name which is created by BackupSchedule, such as CronJob Backup.
 func GenerateCRNameByBackupSchedule(backupSchedule *dpv1alpha1.BackupSchedule, method string) string {
 	name := fmt.Sprintf("%s-%s", generateUniqueNameWithBackupSchedule(backupSchedule), backupSchedule.Namespace)
@@ -173,12 +211,29 @@ func generateUniqueNameWithBackupSchedule(backupSchedule *dpv1alpha1.BackupSched
 }
 
 // BuildBackupPath builds the path to storage backup data in backup repository.
+// The path is /<namespace>/<pathPrefix>/<clusterUID>/<backupName>, the cluster UID is omitted
+// if the backup does not belong to a cluster. The backups of the legacy format version keep
+// the layout /<namespace>/<pathPrefix>/<backupName>.
 func BuildBackupPath(backup *dpv1alpha1.Backup, pathPrefix string) string {
 	pathPrefix = strings.TrimRight(pathPrefix, "/")
-	if strings.TrimSpace(pathPrefix) == "" || strings.HasPrefix(pathPrefix, "/") {
+	if strings.TrimSpace(pathPrefix) != "" && !strings.HasPrefix(pathPrefix, "/") {
+		pathPrefix = "/" + pathPrefix
+	}
+	clusterUID := backup.Labels[types.ClusterUIDLabelKey]
+	if isLegacyBackupPathLayout(backup) || clusterUID == "" {
 		return fmt.Sprintf("/%s%s/%s", backup.Namespace, pathPrefix, backup.Name)
 	}
-	return fmt.Sprintf("/%s/%s/%s", backup.Namespace, pathPrefix, backup.Name)
+	return fmt.Sprintf("/%s%s/%s/%s", backup.Namespace, pathPrefix, clusterUID, backup.Name)
+}
+
+// isLegacyBackupPathLayout checks whether the backup is stored in the legacy path layout
+// without the cluster UID, it is the backup of the legacy format version, or the backup
+// has been started without the format version.
+func isLegacyBackupPathLayout(backup *dpv1alpha1.Backup) bool {
+	if backup.Status.FormatVersion == "" {
+		return backup.Status.Path != ""
+	}
+	return backup.Status.FormatVersion == legacyFormatVersion
 }
 
 // BuildKopiaRepoPath builds the path of kopia repository.
@@ -230,6 +285,29 @@ func SetExpirationByCreationTime(backup *dpv1alpha1.Backup) error {
 	return nil
 }
 
+// SetExpirationForFailedBackup sets the expiration time of the failed backup by the failed retention period,
+// it is counted from the failure time and overrides the expiration set by the retention period of the backup.
+// If the failed retention period is not set, or the deletion policy of the backup is Retain, the expiration
+// is set by the retention period of the backup.
+func SetExpirationForFailedBackup(backup *dpv1alpha1.Backup, failedRetentionPeriod dpv1alpha1.RetentionPeriod, failedTime time.Time) error {
+	if failedRetentionPeriod == "" || backup.Spec.DeletionPolicy == dpv1alpha1.BackupDeletionPolicyRetain {
+		return SetExpirationByCreationTime(backup)
+	}
+
+	duration, err := failedRetentionPeriod.ToDuration()
+	if err != nil {
+		return fmt.Errorf("failed to parse failed retention period %s, %v", failedRetentionPeriod, err)
+	}
+
+	// if duration is zero, the failed backup will be kept forever.
+	if duration.Seconds() == 0 {
+		backup.Status.Expiration = nil
+		return nil
+	}
+	backup.Status.Expiration = &metav1.Time{Time: failedTime.Add(duration)}
+	return nil
+}
+
 // BuildCronJobSchedule build cron job schedule info based on kubernetes version.
 // For kubernetes version >= 1.25, the timeZone field is supported, return timezone.
 // Ref https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#time-zones
@@ -254,3 +332,108 @@ func BuildCronJobSchedule(cronExpression string) (*string, string) {
 	}
 	return nil, fmt.Sprintf("CRON_TZ=%s %s", timeZone, cronExpression)
 }
+
+// BuildScheduleStatus builds the status of the schedule policy from the previous status and the backups
+// created by it, which records the last scheduled backup, the last successfully completed backup, the number
+// of consecutive failed backups since then and the next time to schedule the backup. The previous status is
+// kept if the backups it refers to have been deleted, e.g. by the retention period.
+func BuildScheduleStatus(schedulePolicy *dpv1alpha1.SchedulePolicy, prevStatus dpv1alpha1.ScheduleStatus,
+	backups []dpv1alpha1.Backup, now time.Time) dpv1alpha1.ScheduleStatus {
+	status := dpv1alpha1.ScheduleStatus{
+		LastScheduleTime:         prevStatus.LastScheduleTime,
+		LastSuccessfulTime:       prevStatus.LastSuccessfulTime,
+		LastSuccessfulBackupName: prevStatus.LastSuccessfulBackupName,
+	}
+	if boolptr.IsSetToTrue(schedulePolicy.Enabled) {
+		status.Phase = dpv1alpha1.ScheduleRunning
+		// the cron expression is in UTC unless the time zone is specified.
+		cronExpression := schedulePolicy.CronExpression
+		if !strings.HasPrefix(cronExpression, "CRON_TZ=") && !strings.HasPrefix(cronExpression, "TZ=") {
+			cronExpression = "CRON_TZ=UTC " + cronExpression
+		}
+		if schedule, err := cron.ParseStandard(cronExpression); err == nil {
+			status.NextScheduleTime = &metav1.Time{Time: schedule.Next(now).UTC()}
+		}
+	}
+
+	// sort the backups of the backup method from the newest to the oldest.
+	var methodBackups []*dpv1alpha1.Backup
+	for i := range backups {
+		if backups[i].Spec.BackupMethod == schedulePolicy.BackupMethod {
+			methodBackups = append(methodBackups, &backups[i])
+		}
+	}
+	slices.SortFunc(methodBackups, func(a, b *dpv1alpha1.Backup) int {
+		return b.CreationTimestamp.Compare(a.CreationTimestamp.Time)
+	})
+	if len(methodBackups) > 0 && (status.LastScheduleTime == nil ||
+		methodBackups[0].CreationTimestamp.After(status.LastScheduleTime.Time)) {
+		status.LastScheduleTime = methodBackups[0].CreationTimestamp.DeepCopy()
+	}
+	countFailures := true
+	for _, backup := range methodBackups {
+		switch backup.Status.Phase {
+		case dpv1alpha1.BackupPhaseFailed:
+			if !countFailures {
+				continue
+			}
+			if status.ConsecutiveFailures == 0 {
+				status.FailureReason = backup.Status.FailureReason
+			}
+			status.ConsecutiveFailures++
+		case dpv1alpha1.BackupPhaseCompleted:
+			countFailures = false
+			completionTime := backup.Status.CompletionTimestamp
+			if completionTime == nil || (status.LastSuccessfulTime != nil && !completionTime.After(status.LastSuccessfulTime.Time)) {
+				continue
+			}
+			status.LastSuccessfulTime = completionTime.DeepCopy()
+			status.LastSuccessfulBackupName = backup.Name
+		}
+	}
+	// the failed backups counted previously may have been deleted, keep the previous count
+	// until a new backup is completed successfully.
+	if status.LastSuccessfulBackupName == prevStatus.LastSuccessfulBackupName &&
+		prevStatus.ConsecutiveFailures > status.ConsecutiveFailures {
+		status.ConsecutiveFailures = prevStatus.ConsecutiveFailures
+		status.FailureReason = prevStatus.FailureReason
+	}
+	if status.ConsecutiveFailures > 0 && status.Phase == dpv1alpha1.ScheduleRunning {
+		status.Phase = dpv1alpha1.ScheduleFailed
+	}
+	return status
+}
+
+// IsRetainedByKeepLatest checks whether the backup is one of the latest keepLatest completed backups
+// with the same backup policy and backup method in the backups, which should be kept even if it has
+// expired. The backups being deleted and the dry-run backups are not counted.
+func IsRetainedByKeepLatest(backup *dpv1alpha1.Backup, backups []dpv1alpha1.Backup, keepLatest int32) bool {
+	if keepLatest <= 0 || backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted || backup.IsDryRun() {
+		return false
+	}
+	completedTime := func(b *dpv1alpha1.Backup) time.Time {
+		if b.Status.CompletionTimestamp != nil {
+			return b.Status.CompletionTimestamp.Time
+		}
+		return b.CreationTimestamp.Time
+	}
+	backupTime := completedTime(backup)
+	var newer int32
+	for i := range backups {
+		b := &backups[i]
+		if b.Name == backup.Name ||
+			b.Spec.BackupPolicyName != backup.Spec.BackupPolicyName ||
+			b.Spec.BackupMethod != backup.Spec.BackupMethod ||
+			b.Status.Phase != dpv1alpha1.BackupPhaseCompleted ||
+			b.IsDryRun() ||
+			!b.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// the backups completed at the same time are ordered by name to be deterministic.
+		t := completedTime(b)
+		if t.After(backupTime) || (t.Equal(backupTime) && b.Name > backup.Name) {
+			newer++
+		}
+	}
+	return newer < keepLatest
+}
@@ -21,12 +21,17 @@ package backup
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/utils/pointer"
 
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
@@ -87,3 +92,297 @@ func TestBuildCronJobSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestSetExpirationForFailedBackup(t *testing.T) {
+	creationTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	failedTime := creationTime.Add(10 * time.Minute)
+	expirationByRetentionPeriod := creationTime.Add(7 * 24 * time.Hour)
+	expirationByFailedRetentionPeriod := failedTime.Add(time.Hour)
+	newBackup := func(deletionPolicy dpv1alpha1.BackupDeletionPolicy) *dpv1alpha1.Backup {
+		backup := &dpv1alpha1.Backup{}
+		backup.CreationTimestamp = metav1.Time{Time: creationTime}
+		backup.Spec.DeletionPolicy = deletionPolicy
+		backup.Spec.RetentionPeriod = "7d"
+		return backup
+	}
+
+	tests := []struct {
+		name                  string
+		deletionPolicy        dpv1alpha1.BackupDeletionPolicy
+		failedRetentionPeriod dpv1alpha1.RetentionPeriod
+		expectExpiration      *time.Time
+		expectErr             bool
+	}{
+		{
+			name:             "failed retention period is not set",
+			deletionPolicy:   dpv1alpha1.BackupDeletionPolicyDelete,
+			expectExpiration: &expirationByRetentionPeriod,
+		},
+		{
+			name:                  "failed retention period is set",
+			deletionPolicy:        dpv1alpha1.BackupDeletionPolicyDelete,
+			failedRetentionPeriod: "1h",
+			expectExpiration:      &expirationByFailedRetentionPeriod,
+		},
+		{
+			name:                  "failed retention period is zero",
+			deletionPolicy:        dpv1alpha1.BackupDeletionPolicyDelete,
+			failedRetentionPeriod: "0h",
+		},
+		{
+			name:                  "failed retention period is invalid",
+			deletionPolicy:        dpv1alpha1.BackupDeletionPolicyDelete,
+			failedRetentionPeriod: "1x",
+			expectErr:             true,
+		},
+		{
+			name:                  "deletion policy is Retain",
+			deletionPolicy:        dpv1alpha1.BackupDeletionPolicyRetain,
+			failedRetentionPeriod: "1h",
+			expectExpiration:      &expirationByRetentionPeriod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := newBackup(tt.deletionPolicy)
+			err := SetExpirationForFailedBackup(backup, tt.failedRetentionPeriod, failedTime)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectExpiration == nil {
+				assert.Nil(t, backup.Status.Expiration)
+				return
+			}
+			assert.NotNil(t, backup.Status.Expiration)
+			assert.True(t, tt.expectExpiration.Equal(backup.Status.Expiration.Time))
+		})
+	}
+}
+
+func TestBuildBackupPath(t *testing.T) {
+	const clusterUID = "cluster-uid"
+	newBackup := func(formatVersion, path string, labels map[string]string) *dpv1alpha1.Backup {
+		backup := &dpv1alpha1.Backup{}
+		backup.Namespace = "default"
+		backup.Name = "backup"
+		backup.Labels = labels
+		backup.Status.FormatVersion = formatVersion
+		backup.Status.Path = path
+		return backup
+	}
+
+	tests := []struct {
+		name       string
+		backup     *dpv1alpha1.Backup
+		pathPrefix string
+		expectPath string
+	}{
+		{
+			name:       "new backup of cluster",
+			backup:     newBackup(FormatVersion, "", map[string]string{types.ClusterUIDLabelKey: clusterUID}),
+			pathPrefix: "mysql",
+			expectPath: "/default/mysql/cluster-uid/backup",
+		},
+		{
+			name:       "new backup of cluster with absolute path prefix",
+			backup:     newBackup(FormatVersion, "", map[string]string{types.ClusterUIDLabelKey: clusterUID}),
+			pathPrefix: "/mysql/",
+			expectPath: "/default/mysql/cluster-uid/backup",
+		},
+		{
+			name:       "new backup without cluster",
+			backup:     newBackup(FormatVersion, "", nil),
+			expectPath: "/default/backup",
+		},
+		{
+			name:       "backup of legacy format version",
+			backup:     newBackup(legacyFormatVersion, "/default/mysql/backup", map[string]string{types.ClusterUIDLabelKey: clusterUID}),
+			pathPrefix: "mysql",
+			expectPath: "/default/mysql/backup",
+		},
+		{
+			name:       "backup without format version",
+			backup:     newBackup("", "/default/mysql/backup", map[string]string{types.ClusterUIDLabelKey: clusterUID}),
+			pathPrefix: "mysql",
+			expectPath: "/default/mysql/backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectPath, BuildBackupPath(tt.backup, tt.pathPrefix))
+		})
+	}
+}
+
+func TestIsVolumeSnapshotOwnedByOthers(t *testing.T) {
+	backup := &dpv1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "test-backup", UID: "backup-uid"}}
+	backup.Status.BackupMethod = &dpv1alpha1.BackupMethod{
+		SnapshotVolumes: pointer.Bool(true),
+		TargetVolumes:   &dpv1alpha1.TargetVolumeInfo{Volumes: []string{"data"}},
+	}
+	keys := GetVolumeSnapshotKeys(backup)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "test-backup-data", keys[0].Name)
+
+	// the legacy volume snapshot without labels and owner references
+	vs := &metav1.ObjectMeta{Name: keys[0].Name}
+	assert.False(t, IsVolumeSnapshotOwnedByOthers(backup, vs))
+
+	vs.Labels = map[string]string{types.BackupNameLabelKey: "other-backup"}
+	assert.True(t, IsVolumeSnapshotOwnedByOthers(backup, vs))
+
+	vs.Labels = map[string]string{types.BackupNameLabelKey: backup.Name}
+	vs.OwnerReferences = []metav1.OwnerReference{{UID: "other-uid", Controller: pointer.Bool(true)}}
+	assert.True(t, IsVolumeSnapshotOwnedByOthers(backup, vs))
+
+	vs.OwnerReferences[0].UID = backup.UID
+	assert.False(t, IsVolumeSnapshotOwnedByOthers(backup, vs))
+}
+
+func TestBuildScheduleStatus(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 30, 0, 0, time.UTC)
+	newBackup := func(name, method string, created time.Time, phase dpv1alpha1.BackupPhase) dpv1alpha1.Backup {
+		backup := dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Spec:       dpv1alpha1.BackupSpec{BackupMethod: method},
+			Status:     dpv1alpha1.BackupStatus{Phase: phase, FailureReason: name + " failed"},
+		}
+		if phase == dpv1alpha1.BackupPhaseCompleted {
+			backup.Status.CompletionTimestamp = &metav1.Time{Time: created.Add(10 * time.Minute)}
+		}
+		return backup
+	}
+	backups := []dpv1alpha1.Backup{
+		newBackup("b1", "xtrabackup", now.Add(-4*time.Hour), dpv1alpha1.BackupPhaseCompleted),
+		newBackup("b2", "xtrabackup", now.Add(-3*time.Hour), dpv1alpha1.BackupPhaseFailed),
+		newBackup("b4", "xtrabackup", now.Add(-1*time.Hour), dpv1alpha1.BackupPhaseRunning),
+		newBackup("b3", "xtrabackup", now.Add(-2*time.Hour), dpv1alpha1.BackupPhaseFailed),
+		newBackup("b5", "volume-snapshot", now.Add(-30*time.Minute), dpv1alpha1.BackupPhaseCompleted),
+	}
+	schedulePolicy := &dpv1alpha1.SchedulePolicy{
+		Enabled:        pointer.Bool(true),
+		BackupMethod:   "xtrabackup",
+		CronExpression: "0 * * * *",
+	}
+
+	status := BuildScheduleStatus(schedulePolicy, dpv1alpha1.ScheduleStatus{}, backups, now)
+	assert.Equal(t, dpv1alpha1.ScheduleFailed, status.Phase)
+	assert.Equal(t, now.Add(-1*time.Hour), status.LastScheduleTime.Time)
+	assert.Equal(t, "b1", status.LastSuccessfulBackupName)
+	assert.Equal(t, now.Add(-4*time.Hour+10*time.Minute), status.LastSuccessfulTime.Time)
+	assert.Equal(t, int32(2), status.ConsecutiveFailures)
+	assert.Equal(t, "b3 failed", status.FailureReason)
+	assert.Equal(t, time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC), status.NextScheduleTime.Time)
+
+	// the summary is kept after the backups are deleted by the retention period
+	prevStatus := status
+	status = BuildScheduleStatus(schedulePolicy, prevStatus, backups[3:], now)
+	assert.Equal(t, dpv1alpha1.ScheduleFailed, status.Phase)
+	assert.Equal(t, now.Add(-1*time.Hour), status.LastScheduleTime.Time)
+	assert.Equal(t, "b1", status.LastSuccessfulBackupName)
+	assert.Equal(t, now.Add(-4*time.Hour+10*time.Minute), status.LastSuccessfulTime.Time)
+	assert.Equal(t, int32(2), status.ConsecutiveFailures)
+	assert.Equal(t, "b3 failed", status.FailureReason)
+
+	// the consecutive failures are reset once a new backup is completed
+	backups = append(backups, newBackup("b6", "xtrabackup", now.Add(-10*time.Minute), dpv1alpha1.BackupPhaseCompleted))
+	status = BuildScheduleStatus(schedulePolicy, prevStatus, backups[3:], now)
+	assert.Equal(t, dpv1alpha1.ScheduleRunning, status.Phase)
+	assert.Equal(t, "b6", status.LastSuccessfulBackupName)
+	assert.Equal(t, int32(0), status.ConsecutiveFailures)
+	assert.Empty(t, status.FailureReason)
+
+	schedulePolicy.BackupMethod = "volume-snapshot"
+	schedulePolicy.Enabled = pointer.Bool(false)
+	status = BuildScheduleStatus(schedulePolicy, dpv1alpha1.ScheduleStatus{}, backups, now)
+	assert.Equal(t, dpv1alpha1.SchedulePhase(""), status.Phase)
+	assert.Equal(t, "b5", status.LastSuccessfulBackupName)
+	assert.Equal(t, int32(0), status.ConsecutiveFailures)
+	assert.Nil(t, status.NextScheduleTime)
+}
+
+func TestIsRetainedByKeepLatest(t *testing.T) {
+	const (
+		policyName = "test-policy"
+		methodName = "test-method"
+	)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newBackup := func(name, method string, phase dpv1alpha1.BackupPhase, hour int) dpv1alpha1.Backup {
+		backup := dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.Time{Time: base.Add(time.Duration(hour) * time.Hour)},
+			},
+			Spec: dpv1alpha1.BackupSpec{
+				BackupPolicyName: policyName,
+				BackupMethod:     method,
+			},
+			Status: dpv1alpha1.BackupStatus{Phase: phase},
+		}
+		if phase == dpv1alpha1.BackupPhaseCompleted {
+			backup.Status.CompletionTimestamp = &metav1.Time{Time: backup.CreationTimestamp.Add(time.Minute)}
+		}
+		return backup
+	}
+
+	// the completed and failed backups are interleaved, and the latest backups have been failing.
+	backups := []dpv1alpha1.Backup{
+		newBackup("completed-1", methodName, dpv1alpha1.BackupPhaseCompleted, 1),
+		newBackup("failed-2", methodName, dpv1alpha1.BackupPhaseFailed, 2),
+		newBackup("completed-3", methodName, dpv1alpha1.BackupPhaseCompleted, 3),
+		newBackup("failed-4", methodName, dpv1alpha1.BackupPhaseFailed, 4),
+		newBackup("completed-5", methodName, dpv1alpha1.BackupPhaseCompleted, 5),
+		newBackup("failed-6", methodName, dpv1alpha1.BackupPhaseFailed, 6),
+		newBackup("failed-7", methodName, dpv1alpha1.BackupPhaseFailed, 7),
+		newBackup("other-method-8", "other-method", dpv1alpha1.BackupPhaseCompleted, 8),
+	}
+	retained := func(keepLatest int32) []string {
+		var names []string
+		for i := range backups {
+			if IsRetainedByKeepLatest(&backups[i], backups, keepLatest) {
+				names = append(names, backups[i].Name)
+			}
+		}
+		return names
+	}
+
+	assert.Empty(t, retained(0))
+	assert.Equal(t, []string{"completed-5", "other-method-8"}, retained(1))
+	assert.Equal(t, []string{"completed-3", "completed-5", "other-method-8"}, retained(2))
+	assert.Equal(t, []string{"completed-1", "completed-3", "completed-5", "other-method-8"}, retained(5))
+
+	// the dry-run backups are neither retained nor counted.
+	dryRun := newBackup("dry-run-9", methodName, dpv1alpha1.BackupPhaseCompleted, 9)
+	dryRun.Status.CompletionReason = dpv1alpha1.BackupCompletionReasonDryRun
+	withDryRun := append(backups, dryRun)
+	assert.False(t, IsRetainedByKeepLatest(&withDryRun[len(withDryRun)-1], withDryRun, 5))
+	assert.True(t, IsRetainedByKeepLatest(&withDryRun[4], withDryRun, 1))
+
+	// the backups being deleted are not counted.
+	now := metav1.Now()
+	backups[4].DeletionTimestamp = &now
+	assert.True(t, IsRetainedByKeepLatest(&backups[2], backups, 1))
+}
+
+func TestGetVolumeMountsByVolumeInfo(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{{Name: "data"}, {Name: "log"}},
+		},
+	}
+	info := &dpv1alpha1.TargetVolumeInfo{
+		VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+	}
+	assert.Equal(t, info.VolumeMounts, getVolumeMountsByVolumeInfo(pod, info))
+
+	// only the sub paths are mounted.
+	info.VolumeSubPaths = []string{"mysql", "binlog"}
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data/mysql", SubPath: "mysql"},
+		{Name: "data", MountPath: "/data/binlog", SubPath: "binlog"},
+	}, getVolumeMountsByVolumeInfo(pod, info))
+}
@@ -51,6 +51,26 @@ const (
 	ErrorTypeLogfileScheduleDisabled intctrlutil.ErrorType = "LogfileScheduleDisabled"
 	// ErrorTypeWaitForExternalHandler wait for external handler to handle the Backup or Restore
 	ErrorTypeWaitForExternalHandler intctrlutil.ErrorType = "WaitForExternalHandler"
+	// ErrorTypeBackupRepoQuotaExceeded the quota of the backup repository is exceeded
+	ErrorTypeBackupRepoQuotaExceeded intctrlutil.ErrorType = "QuotaExceeded"
+	// ErrorTypeBackupRepoOutOfSpace the free space of the backup repository is not enough for the backup
+	ErrorTypeBackupRepoOutOfSpace intctrlutil.ErrorType = "BackupRepoOutOfSpace"
+	// ErrorTypeBackupRepoReadOnly the backup repository is read-only
+	ErrorTypeBackupRepoReadOnly intctrlutil.ErrorType = "BackupRepoReadOnly"
+	// ErrorTypeNoTargetPods no pods matched the target pod selector
+	ErrorTypeNoTargetPods intctrlutil.ErrorType = "NoTargetPods"
+	// ErrorTypeTargetPodsNotReady the pods matched the target pod selector are not ready
+	ErrorTypeTargetPodsNotReady intctrlutil.ErrorType = "TargetPodsNotReady"
+	// ErrorTypeTargetContainerNotFound the target container is not found in the target pod
+	ErrorTypeTargetContainerNotFound intctrlutil.ErrorType = "TargetContainerNotFound"
+	// ErrorTypeBackupPathCollision the backup path is used by another backup in the same backup repository
+	ErrorTypeBackupPathCollision intctrlutil.ErrorType = "BackupPathCollision"
+	// ErrorTypeBackupActionFailed an action of the backup failed
+	ErrorTypeBackupActionFailed intctrlutil.ErrorType = "BackupActionFailed"
+	// ErrorTypeEncryptionKeyMissing the encryption key of the backup is missing
+	ErrorTypeEncryptionKeyMissing intctrlutil.ErrorType = "EncryptionKeyMissing"
+	// ErrorTypeSnapshotClassMismatch the volume snapshot class is not found or its driver doesn't match the volume
+	ErrorTypeSnapshotClassMismatch intctrlutil.ErrorType = "SnapshotClassMismatch"
 )
 
 // NewBackupNotSupported returns a new Error with ErrorTypeBackupNotSupported.
@@ -97,3 +117,53 @@ func NewBackupScheduleDisabled(backupType, backupPolicyName string) *intctrlutil
 func NewBackupLogfileScheduleDisabled(backupToolName string) *intctrlutil.Error {
 	return intctrlutil.NewErrorf(ErrorTypeLogfileScheduleDisabled, `BackupTool "%s" of the backup relies on logfile. Please enable the logfile scheduling firstly`, backupToolName)
 }
+
+// NewBackupRepoQuotaExceeded returns a new Error with ErrorTypeBackupRepoQuotaExceeded.
+func NewBackupRepoQuotaExceeded(backupRepo, scope, used, limit string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeBackupRepoQuotaExceeded, `the size of backups %s of %s has reached the quota %s of backup repository %s`, used, scope, limit, backupRepo)
+}
+
+// NewBackupRepoOutOfSpace returns a new Error with ErrorTypeBackupRepoOutOfSpace.
+func NewBackupRepoOutOfSpace(backupRepo, free, estimated string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeBackupRepoOutOfSpace, `the free space %s of backup repository %s is less than the estimated backup size %s`, free, backupRepo, estimated)
+}
+
+// NewBackupRepoReadOnly returns a new Error with ErrorTypeBackupRepoReadOnly.
+func NewBackupRepoReadOnly(backupRepo string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeBackupRepoReadOnly, `the backup repository %s is read-only`, backupRepo)
+}
+
+// NewNoTargetPods returns a new Error with ErrorTypeNoTargetPods.
+func NewNoTargetPods(backupPolicyNamespace, backupPolicyName string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeNoTargetPods, `no pods matched the target pod selector of BackupPolicy "%s/%s"`, backupPolicyNamespace, backupPolicyName)
+}
+
+// NewTargetPodsNotReady returns a new Error with ErrorTypeTargetPodsNotReady.
+func NewTargetPodsNotReady(pods []string, backupPolicyNamespace, backupPolicyName string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeTargetPodsNotReady, `the pods %v matched the target pod selector of BackupPolicy "%s/%s" are not ready, you can set spec.allowNotReadyTarget of the Backup to back up from a not ready pod`, pods, backupPolicyNamespace, backupPolicyName)
+}
+
+// NewTargetContainerNotFound returns a new Error with ErrorTypeTargetContainerNotFound.
+func NewTargetContainerNotFound(containerName, podNamespace, podName string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeTargetContainerNotFound, `the container "%s" is not found in the target pod "%s/%s"`, containerName, podNamespace, podName)
+}
+
+// NewBackupPathCollision returns a new Error with ErrorTypeBackupPathCollision.
+func NewBackupPathCollision(path, backupRepo, backupNamespace, backupName string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeBackupPathCollision, `the backup path "%s" in backup repository %s is used by the backup "%s/%s"`, path, backupRepo, backupNamespace, backupName)
+}
+
+// NewBackupActionFailed returns a new Error with ErrorTypeBackupActionFailed.
+func NewBackupActionFailed(actionName, reason string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeBackupActionFailed, `action %s failed, %s`, actionName, reason)
+}
+
+// NewEncryptionKeyMissing returns a new Error with ErrorTypeEncryptionKeyMissing.
+func NewEncryptionKeyMissing(reason string) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeEncryptionKeyMissing, `failed to check encryption key reference: %s`, reason)
+}
+
+// NewSnapshotClassMismatch returns a new Error with ErrorTypeSnapshotClassMismatch.
+func NewSnapshotClassMismatch(format string, a ...any) *intctrlutil.Error {
+	return intctrlutil.NewErrorf(ErrorTypeSnapshotClassMismatch, format, a...)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+var labelNames = []string{"namespace", "policy", "method", "repo"}
+
+var (
+	backupDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "kubeblocks_backup_duration_seconds",
+		Help: "The duration of the completed backups in seconds.",
+		// from 30 seconds to about 17 hours
+		Buckets: prometheus.ExponentialBuckets(30, 2, 12),
+	}, labelNames)
+
+	backupTotalSize = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubeblocks_backup_total_size_bytes",
+		Help: "The total size of the completed backups in bytes.",
+	}, labelNames)
+
+	backupFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubeblocks_backup_failed_total",
+		Help: "The number of the failed backups.",
+	}, labelNames)
+
+	backupDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubeblocks_backup_deleted_total",
+		Help: "The number of the backups whose files are deleted from the backup repository.",
+	}, labelNames)
+)
+
+// recordedPhases keeps the terminal phase recorded for each backup, to avoid double-counting
+// on repeated reconciles of the same terminal phase before the phase is observed from the cache.
+// The entries are deleted by ForgetBackup once the phase is observed, or the backup is deleted.
+var recordedPhases sync.Map
+
+func init() {
+	// register to the controller-runtime metrics registry, the metrics are exposed on the /metrics endpoint.
+	ctrlmetrics.Registry.MustRegister(backupDuration, backupTotalSize, backupFailed, backupDeleted)
+}
+
+// RecordBackupCompleted records the duration and the total size of the completed backup.
+func RecordBackupCompleted(backup *dpv1alpha1.Backup) {
+	if backup.IsDryRun() || !markRecorded(backup, dpv1alpha1.BackupPhaseCompleted) {
+		return
+	}
+	values := labelValues(backup)
+	if backup.Status.Duration != nil {
+		backupDuration.WithLabelValues(values...).Observe(backup.Status.Duration.Seconds())
+	}
+	if backup.Status.TotalSize != "" {
+		if size, err := resource.ParseQuantity(backup.Status.TotalSize); err == nil {
+			backupTotalSize.WithLabelValues(values...).Add(float64(size.Value()))
+		}
+	}
+}
+
+// RecordBackupFailed records the failed backup.
+func RecordBackupFailed(backup *dpv1alpha1.Backup) {
+	if backup.IsDryRun() || !markRecorded(backup, dpv1alpha1.BackupPhaseFailed) {
+		return
+	}
+	backupFailed.WithLabelValues(labelValues(backup)...).Inc()
+}
+
+// RecordBackupDeleted records the backup whose files are deleted, it should be called once the
+// finalizer of the backup is removed, and the recorded phase of the backup is forgotten.
+func RecordBackupDeleted(backup *dpv1alpha1.Backup) {
+	ForgetBackup(backup)
+	if backup.IsDryRun() {
+		return
+	}
+	backupDeleted.WithLabelValues(labelValues(backup)...).Inc()
+}
+
+// ForgetBackup forgets the recorded phase of the backup, it should be called once the terminal phase
+// of the backup is observed, since the metrics of the phase won't be recorded again by the reconciles.
+func ForgetBackup(backup *dpv1alpha1.Backup) {
+	recordedPhases.Delete(backup.UID)
+}
+
+// markRecorded marks the phase of the backup as recorded, it returns false if the phase has been recorded.
+func markRecorded(backup *dpv1alpha1.Backup, phase dpv1alpha1.BackupPhase) bool {
+	prev, loaded := recordedPhases.Swap(backup.UID, phase)
+	return !loaded || prev.(dpv1alpha1.BackupPhase) != phase
+}
+
+func labelValues(backup *dpv1alpha1.Backup) []string {
+	return []string{
+		backup.Namespace,
+		backup.Spec.BackupPolicyName,
+		backup.Spec.BackupMethod,
+		backup.Status.BackupRepoName,
+	}
+}
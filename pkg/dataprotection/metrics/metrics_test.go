@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2022-2023 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+func TestRecordBackupMetrics(t *testing.T) {
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup",
+			Namespace: "default",
+			UID:       types.UID("backup-uid"),
+		},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: "policy",
+			BackupMethod:     "xtrabackup",
+		},
+		Status: dpv1alpha1.BackupStatus{
+			Phase:          dpv1alpha1.BackupPhaseCompleted,
+			BackupRepoName: "repo",
+			TotalSize:      "1Ki",
+			Duration:       &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	values := labelValues(backup)
+
+	// repeated reconciles of the completed phase are recorded once
+	RecordBackupCompleted(backup)
+	RecordBackupCompleted(backup)
+	assert.Equal(t, float64(1024), testutil.ToFloat64(backupTotalSize.WithLabelValues(values...)))
+	assert.Equal(t, 1, testutil.CollectAndCount(backupDuration))
+
+	// repeated reconciles of the failed phase are recorded once
+	failed := backup.DeepCopy()
+	failed.UID = types.UID("failed-backup-uid")
+	failed.Status.Phase = dpv1alpha1.BackupPhaseFailed
+	RecordBackupFailed(failed)
+	RecordBackupFailed(failed)
+	assert.Equal(t, float64(1), testutil.ToFloat64(backupFailed.WithLabelValues(values...)))
+
+	// the recorded phase is forgotten once it is observed
+	ForgetBackup(failed)
+	_, loaded := recordedPhases.Load(failed.UID)
+	assert.False(t, loaded)
+
+	// the dry-run backups are not recorded
+	dryRun := backup.DeepCopy()
+	dryRun.UID = types.UID("dry-run-backup-uid")
+	dryRun.Spec.DryRun = true
+	RecordBackupCompleted(dryRun)
+	RecordBackupFailed(dryRun)
+	assert.Equal(t, float64(1024), testutil.ToFloat64(backupTotalSize.WithLabelValues(values...)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(backupFailed.WithLabelValues(values...)))
+	_, loaded = recordedPhases.Load(dryRun.UID)
+	assert.False(t, loaded)
+
+	// the recorded phase is forgotten after the backup is deleted
+	RecordBackupDeleted(backup)
+	assert.Equal(t, float64(1), testutil.ToFloat64(backupDeleted.WithLabelValues(values...)))
+	_, loaded = recordedPhases.Load(backup.UID)
+	assert.False(t, loaded)
+}
@@ -77,21 +77,21 @@ func newRestoreJobBuilder(restore *dpv1alpha1.Restore, backupSet BackupActionSet
 func (r *restoreJobBuilder) buildPVCVolumeAndMount(
 	claim dpv1alpha1.VolumeConfig,
 	claimName,
-	identifier string) (*corev1.Volume, *corev1.VolumeMount, error) {
+	identifier string) (*corev1.Volume, []corev1.VolumeMount, error) {
 	volumeName := fmt.Sprintf("%s-%s", identifier, claimName)
 	volume := &corev1.Volume{
 		Name:         volumeName,
 		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName}},
 	}
-	volumeMount := &corev1.VolumeMount{Name: volumeName}
+	volumeMount := corev1.VolumeMount{Name: volumeName}
 	if claim.MountPath != "" {
 		volumeMount.MountPath = claim.MountPath
-		return volume, volumeMount, nil
+		return volume, r.buildVolumeSubPathMounts(volumeMount), nil
 	}
 	mountPath := getMountPathWithSourceVolume(r.backupSet.Backup, claim.VolumeSource)
 	if mountPath != "" {
 		volumeMount.MountPath = mountPath
-		return volume, volumeMount, nil
+		return volume, r.buildVolumeSubPathMounts(volumeMount), nil
 	}
 
 	if r.backupSet.UseVolumeSnapshot && !r.backupSet.ActionSet.HasPrepareDataStage() {
@@ -101,14 +101,22 @@ func (r *restoreJobBuilder) buildPVCVolumeAndMount(
 		claim.VolumeSource, r.backupSet.Backup.Name))
 }
 
-// addToCommonVolumesAndMounts adds the volume and volumeMount to common volumes and volumeMounts slice.
-func (r *restoreJobBuilder) addToCommonVolumesAndMounts(volume *corev1.Volume, volumeMount *corev1.VolumeMount) *restoreJobBuilder {
+// buildVolumeSubPathMounts restores the same sub paths of the target volumes as the backup,
+// only the sub paths are mounted if the backup method specifies them.
+func (r *restoreJobBuilder) buildVolumeSubPathMounts(volumeMount corev1.VolumeMount) []corev1.VolumeMount {
+	backupMethod := r.backupSet.Backup.Status.BackupMethod
+	if backupMethod == nil || backupMethod.TargetVolumes == nil {
+		return []corev1.VolumeMount{volumeMount}
+	}
+	return utils.BuildVolumeSubPathMounts(volumeMount, backupMethod.TargetVolumes.VolumeSubPaths)
+}
+
+// addToCommonVolumesAndMounts adds the volume and volumeMounts to common volumes and volumeMounts slice.
+func (r *restoreJobBuilder) addToCommonVolumesAndMounts(volume *corev1.Volume, volumeMounts ...corev1.VolumeMount) *restoreJobBuilder {
 	if volume != nil {
 		r.commonVolumes = append(r.commonVolumes, *volume)
 	}
-	if volumeMount != nil {
-		r.commonVolumeMounts = append(r.commonVolumeMounts, *volumeMount)
-	}
+	r.commonVolumeMounts = append(r.commonVolumeMounts, volumeMounts...)
 	return r
 }
 
@@ -118,14 +126,12 @@ func (r *restoreJobBuilder) resetSpecificVolumesAndMounts() {
 	r.specificVolumeMounts = []corev1.VolumeMount{}
 }
 
-// addToSpecificVolumesAndMounts adds the volume and volumeMount to specific volumes and volumeMounts slice.
-func (r *restoreJobBuilder) addToSpecificVolumesAndMounts(volume *corev1.Volume, volumeMount *corev1.VolumeMount) *restoreJobBuilder {
+// addToSpecificVolumesAndMounts adds the volume and volumeMounts to specific volumes and volumeMounts slice.
+func (r *restoreJobBuilder) addToSpecificVolumesAndMounts(volume *corev1.Volume, volumeMounts ...corev1.VolumeMount) *restoreJobBuilder {
 	if volume != nil {
 		r.specificVolumes = append(r.specificVolumes, *volume)
 	}
-	if volumeMount != nil {
-		r.specificVolumeMounts = append(r.specificVolumeMounts, *volumeMount)
-	}
+	r.specificVolumeMounts = append(r.specificVolumeMounts, volumeMounts...)
 	return r
 }
 
@@ -221,8 +227,20 @@ func (r *restoreJobBuilder) addCommonEnv() *restoreJobBuilder {
 	// append actionSet env
 	r.env = append(r.env, actionSetEnv...)
 	backupMethod := r.backupSet.Backup.Status.BackupMethod
-	if backupMethod != nil && len(backupMethod.Env) > 0 {
-		r.env = utils.MergeEnv(r.env, backupMethod.Env)
+	if backupMethod != nil {
+		// restore the same sub paths of the target volumes as the backup.
+		if backupMethod.TargetVolumes != nil && len(backupMethod.TargetVolumes.VolumeSubPaths) > 0 {
+			r.env = append(r.env, corev1.EnvVar{
+				Name:  dptypes.DPBackupVolumeSubPaths,
+				Value: strings.Join(backupMethod.TargetVolumes.VolumeSubPaths, ","),
+			})
+		}
+		if backupMethod.TargetContainerName != "" {
+			r.env = append(r.env, corev1.EnvVar{Name: dptypes.DPTargetContainerName, Value: backupMethod.TargetContainerName})
+		}
+		if len(backupMethod.Env) > 0 {
+			r.env = utils.MergeEnv(r.env, backupMethod.Env)
+		}
 	}
 	// merge the restore env
 	r.env = utils.MergeEnv(r.env, r.restore.Spec.Env)
@@ -234,14 +252,18 @@ func (r *restoreJobBuilder) addTargetPodAndCredentialEnv(pod *corev1.Pod,
 	if pod == nil {
 		return r
 	}
-	var env []corev1.EnvVar
-	// Note: now only add the first container envs.
-	if len(pod.Spec.Containers) != 0 {
-		env = pod.Spec.Containers[0].Env
-		r.envFrom = pod.Spec.Containers[0].EnvFrom
+	var (
+		env           []corev1.EnvVar
+		containerName string
+	)
+	// Note: now only add the envs of the target container of the backup method, or the first container.
+	if container := r.getTargetContainer(pod); container != nil {
+		env = container.Env
+		r.envFrom = container.EnvFrom
+		containerName = container.Name
 	}
 	env = append(env, corev1.EnvVar{Name: dptypes.DPDBHost, Value: intctrlutil.BuildPodHostDNS(pod)})
-	env = append(env, corev1.EnvVar{Name: dptypes.DPDBPort, Value: strconv.Itoa(int(utils.GetPodFirstContainerPort(pod)))})
+	env = append(env, corev1.EnvVar{Name: dptypes.DPDBPort, Value: strconv.Itoa(int(utils.GetPodContainerPort(pod, containerName)))})
 	if connectionCredential != nil {
 		appendEnvFromSecret := func(envName, keyName string) {
 			if keyName == "" {
@@ -269,6 +291,20 @@ func (r *restoreJobBuilder) addTargetPodAndCredentialEnv(pod *corev1.Pod,
 	return r
 }
 
+// getTargetContainer returns the target container of the backup method in the pod,
+// or the first container if the target container is not specified or not found.
+func (r *restoreJobBuilder) getTargetContainer(pod *corev1.Pod) *corev1.Container {
+	if len(pod.Spec.Containers) == 0 {
+		return nil
+	}
+	if backupMethod := r.backupSet.Backup.Status.BackupMethod; backupMethod != nil && backupMethod.TargetContainerName != "" {
+		if _, c := intctrlutil.GetContainerByName(pod.Spec.Containers, backupMethod.TargetContainerName); c != nil {
+			return c
+		}
+	}
+	return &pod.Spec.Containers[0]
+}
+
 // builderRestoreJobName builds restore job name.
 func (r *restoreJobBuilder) builderRestoreJobName(jobIndex int) string {
 	jobName := fmt.Sprintf("restore-%s-%s-%s-%d", strings.ToLower(string(r.stage)), r.restore.UID[:8], r.backupSet.Backup.Name, jobIndex)
@@ -99,7 +99,7 @@ func (r *RestoreManager) GetBackupActionSetByNamespaced(reqCtx intctrlutil.Reque
 
 // BuildDifferentialBackupActionSets builds the backupActionSets for specified incremental backup.
 func (r *RestoreManager) BuildDifferentialBackupActionSets(reqCtx intctrlutil.RequestCtx, cli client.Client, sourceBackupSet BackupActionSet) error {
-	parentBackupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, sourceBackupSet.Backup.Spec.ParentBackupName, sourceBackupSet.Backup.Namespace)
+	parentBackupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, utils.GetParentBackupName(sourceBackupSet.Backup), sourceBackupSet.Backup.Namespace)
 	if err != nil || parentBackupSet == nil {
 		return err
 	}
@@ -112,7 +112,7 @@ func (r *RestoreManager) BuildIncrementalBackupActionSets(reqCtx intctrlutil.Req
 	r.SetBackupSets(sourceBackupSet)
 	if sourceBackupSet.ActionSet != nil && sourceBackupSet.ActionSet.Spec.BackupType == dpv1alpha1.BackupTypeIncremental {
 		// get the parent BackupActionSet for incremental.
-		backupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, sourceBackupSet.Backup.Spec.ParentBackupName, sourceBackupSet.Backup.Namespace)
+		backupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, utils.GetParentBackupName(sourceBackupSet.Backup), sourceBackupSet.Backup.Namespace)
 		if err != nil || backupSet == nil {
 			return err
 		}
@@ -246,7 +246,7 @@ func (r *RestoreManager) listCompletedFullBackups(reqCtx intctrlutil.RequestCtx,
 	}
 	backupItems := []dpv1alpha1.Backup{}
 	for _, b := range backups.Items {
-		if b.Status.Phase == dpv1alpha1.BackupPhaseCompleted {
+		if b.Status.Phase == dpv1alpha1.BackupPhaseCompleted && !b.IsDryRun() {
 			backupItems = append(backupItems, b)
 		}
 	}
@@ -394,7 +394,7 @@ func (r *RestoreManager) BuildPrepareDataJobs(reqCtx intctrlutil.RequestCtx, cli
 		setServiceAccount(r.WorkerServiceAccount).
 		attachBackupRepo()
 
-	createPVCIfNotExistsAndBuildVolume := func(claim dpv1alpha1.RestoreVolumeClaim, identifier string) (*corev1.Volume, *corev1.VolumeMount, error) {
+	createPVCIfNotExistsAndBuildVolume := func(claim dpv1alpha1.RestoreVolumeClaim, identifier string) (*corev1.Volume, []corev1.VolumeMount, error) {
 		if err := r.createPVCIfNotExist(reqCtx, cli, claim.ObjectMeta, claim.VolumeClaimSpec); err != nil {
 			return nil, nil, err
 		}
@@ -403,11 +403,11 @@ func (r *RestoreManager) BuildPrepareDataJobs(reqCtx intctrlutil.RequestCtx, cli
 
 	// create pvc from volumeClaims, set volume and volumeMount to jobBuilder
 	for _, claim := range prepareDataConfig.RestoreVolumeClaims {
-		volume, volumeMount, err := createPVCIfNotExistsAndBuildVolume(claim, "dp-claim")
+		volume, volumeMounts, err := createPVCIfNotExistsAndBuildVolume(claim, "dp-claim")
 		if err != nil {
 			return nil, err
 		}
-		jobBuilder.addToCommonVolumesAndMounts(volume, volumeMount)
+		jobBuilder.addToCommonVolumesAndMounts(volume, volumeMounts...)
 	}
 
 	var (
@@ -445,14 +445,14 @@ func (r *RestoreManager) BuildPrepareDataJobs(reqCtx intctrlutil.RequestCtx, cli
 			//  create pvc from claims template, build volumes and volumeMounts
 			for _, claim := range claimsTemplate.Templates {
 				claim.Name = fmt.Sprintf("%s-%d", claim.Name, i+int(claimsTemplate.StartingIndex))
-				volume, volumeMount, err := createPVCIfNotExistsAndBuildVolume(claim, "dp-claim-tpl")
+				volume, volumeMounts, err := createPVCIfNotExistsAndBuildVolume(claim, "dp-claim-tpl")
 				if err != nil {
 					return nil, err
 				}
 				for k, v := range claim.Labels {
 					jobBuilder.addLabel(k, v)
 				}
-				jobBuilder.addToSpecificVolumesAndMounts(volume, volumeMount)
+				jobBuilder.addToSpecificVolumesAndMounts(volume, volumeMounts...)
 			}
 		}
 		// build job and append
@@ -492,11 +492,11 @@ func (r *RestoreManager) BuildVolumePopulateJob(
 		setServiceAccount(r.WorkerServiceAccount).
 		attachBackupRepo().
 		addCommonEnv()
-	volume, volumeMount, err := jobBuilder.buildPVCVolumeAndMount(*prepareDataConfig.DataSourceRef, populatePVC.Name, "dp-claim")
+	volume, volumeMounts, err := jobBuilder.buildPVCVolumeAndMount(*prepareDataConfig.DataSourceRef, populatePVC.Name, "dp-claim")
 	if err != nil {
 		return nil, err
 	}
-	job := jobBuilder.addToSpecificVolumesAndMounts(volume, volumeMount).build()
+	job := jobBuilder.addToSpecificVolumesAndMounts(volume, volumeMounts...).build()
 	return job, nil
 }
 
@@ -553,7 +553,7 @@ func (r *RestoreManager) BuildPostReadyActionJobs(reqCtx intctrlutil.RequestCtx,
 					if volume.Name != volumeMount.Name {
 						continue
 					}
-					jobBuilder.addToSpecificVolumesAndMounts(&volume, &volumeMount)
+					jobBuilder.addToSpecificVolumesAndMounts(&volume, jobBuilder.buildVolumeSubPathMounts(volumeMount)...)
 				}
 			}
 		}
@@ -581,7 +581,7 @@ func (r *RestoreManager) BuildPostReadyActionJobs(reqCtx intctrlutil.RequestCtx,
 		for i := range targetPodList.Items {
 			containerName := actionSpec.Exec.Container
 			if containerName == "" {
-				containerName = targetPodList.Items[i].Spec.Containers[0].Name
+				containerName = jobBuilder.getTargetContainer(&targetPodList.Items[i]).Name
 			}
 			args := append([]string{"-n", targetPodList.Items[i].Namespace, "exec", targetPodList.Items[i].Name, "-c", containerName, "--"}, actionSpec.Exec.Command...)
 			jobBuilder.setImage(viper.GetString(constant.KBToolsImage)).setCommand([]string{"kubectl"}).setArgs(args).
@@ -104,16 +104,16 @@ var _ = Describe("Backup Deleter Test", func() {
 				if useVolumeSnapshotBackup {
 					backupMethodName = testdp.VSBackupMethodName
 				}
-				Expect(testapps.ChangeObjStatus(testCtx, backup, func() {
-					endTime, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
-					backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
-					backup.Status.PersistentVolumeClaimName = backupPVCName
-					backup.Status.TimeRange = &dpv1alpha1.BackupTimeRange{
-						TimeZone: "+08:00",
-						End:      &metav1.Time{Time: endTime},
-					}
-					testdp.MockBackupStatusMethod(backup, backupMethodName, testdp.DataVolumeName, actionSetName)
-				})).Should(Succeed())
+				testdp.MockBackupCompleted(testCtx, backup, backupMethodName, actionSetName, backupPVCName,
+					func(backup *dpv1alpha1.Backup) {
+						// the backup repo is not created, the backup data is accessed by the backup PVC.
+						backup.Status.BackupRepoName = ""
+						endTime, _ := time.Parse(time.RFC3339, "2023-01-01T10:00:00Z")
+						backup.Status.TimeRange = &dpv1alpha1.BackupTimeRange{
+							TimeZone: "+08:00",
+							End:      &metav1.Time{Time: endTime},
+						}
+					})
 			}
 			return backup
 		}
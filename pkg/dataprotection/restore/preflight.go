@@ -0,0 +1,267 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// preflightCheckFunc checks whether a backup can be restored, it returns a fatal error if the check fails,
+// a requeue error if the check is still in progress, and returns false if the check is not applicable to the backup.
+type preflightCheckFunc func(reqCtx intctrlutil.RequestCtx, cli client.Client,
+	restoreMgr *RestoreManager, backupSet BackupActionSet) (bool, error)
+
+type preflightCheck struct {
+	conditionType string
+	check         preflightCheckFunc
+}
+
+var preflightChecks = []preflightCheck{
+	{conditionType: ConditionTypePreflightBackupArtifacts, check: checkBackupArtifacts},
+	{conditionType: ConditionTypePreflightEncryptionKey, check: checkEncryptionKey},
+	{conditionType: ConditionTypePreflightConnectionPassword, check: checkConnectionPassword},
+	{conditionType: ConditionTypePreflightClusterSnapshot, check: checkClusterSnapshot},
+}
+
+// RunPreflightChecks checks whether the backups of the restore manager can be restored into the target
+// environment without creating any restore workloads, only the jobs to access the backup repository are created.
+// The result of each check is recorded in a condition of the restore. It returns whether all checks are
+// finished, and whether all of them pass.
+func RunPreflightChecks(reqCtx intctrlutil.RequestCtx, cli client.Client, restoreMgr *RestoreManager) (finished bool, passed bool, err error) {
+	var (
+		backupSets []BackupActionSet
+		visited    = map[string]bool{}
+	)
+	for _, backupSet := range append(append([]BackupActionSet{}, restoreMgr.PrepareDataBackupSets...), restoreMgr.PostReadyBackupSets...) {
+		if visited[backupSet.Backup.Name] {
+			continue
+		}
+		visited[backupSet.Backup.Name] = true
+		backupSets = append(backupSets, backupSet)
+	}
+
+	restore := restoreMgr.Restore
+	finished, passed = true, true
+	for _, c := range preflightChecks {
+		var failures, pending, checked []string
+		for _, backupSet := range backupSets {
+			applicable, err := c.check(reqCtx, cli, restoreMgr, backupSet)
+			switch {
+			case intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal):
+				failures = append(failures, err.Error())
+			case intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeRequeue):
+				pending = append(pending, err.Error())
+			case err != nil:
+				return false, false, err
+			case applicable:
+				checked = append(checked, backupSet.Backup.Name)
+			}
+		}
+		switch {
+		case len(failures) > 0:
+			passed = false
+			SetRestoreCondition(restore, metav1.ConditionFalse, c.conditionType, ReasonPreflightFailed, strings.Join(failures, "; "))
+		case len(pending) > 0:
+			finished = false
+			SetRestoreCondition(restore, metav1.ConditionUnknown, c.conditionType, ReasonPreflightChecking, strings.Join(pending, "; "))
+		case len(checked) == 0:
+			SetRestoreCondition(restore, metav1.ConditionTrue, c.conditionType, ReasonPreflightSkipped, "the check is not applicable to the backups")
+		default:
+			SetRestoreCondition(restore, metav1.ConditionTrue, c.conditionType, ReasonPreflightPassed,
+				fmt.Sprintf("the check passed for backups: %s", strings.Join(checked, ", ")))
+		}
+	}
+	return finished, passed, nil
+}
+
+// checkBackupArtifacts checks whether the volume snapshots of the backup exist, or the backup path
+// can be accessed in the backup repository of the backup.
+func checkBackupArtifacts(reqCtx intctrlutil.RequestCtx, cli client.Client,
+	restoreMgr *RestoreManager, backupSet BackupActionSet) (bool, error) {
+	backup := backupSet.Backup
+	if backupSet.UseVolumeSnapshot {
+		var snapshots []string
+		for _, action := range backup.Status.Actions {
+			for _, vs := range action.VolumeSnapshots {
+				snapshots = append(snapshots, vs.Name)
+			}
+		}
+		if len(snapshots) == 0 {
+			return false, intctrlutil.NewFatalError(fmt.Sprintf(`no volume snapshots are recorded in backup "%s"`, backup.Name))
+		}
+		vsCli := utils.NewCompatClient(cli)
+		for _, name := range snapshots {
+			exist, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, vsCli,
+				types.NamespacedName{Namespace: backup.Namespace, Name: name}, &vsv1.VolumeSnapshot{})
+			if err != nil {
+				return false, err
+			}
+			if !exist {
+				return false, intctrlutil.NewFatalError(fmt.Sprintf(`volume snapshot "%s" of backup "%s" does not exist`, name, backup.Name))
+			}
+		}
+		return true, nil
+	}
+
+	if backup.Status.BackupRepoName == "" || backup.Status.Path == "" {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`the backup repository or path of backup "%s" is empty`, backup.Name))
+	}
+	repo := &dpv1alpha1.BackupRepo{}
+	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Name: backup.Status.BackupRepoName}, repo); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, intctrlutil.NewFatalError(fmt.Sprintf(`backup repository "%s" of backup "%s" does not exist`,
+				backup.Status.BackupRepoName, backup.Name))
+		}
+		return false, err
+	}
+	// the backup repository is ready only if its pre-check job has accessed the storage successfully.
+	if repo.Status.Phase != dpv1alpha1.BackupRepoReady {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`backup repository "%s" of backup "%s" is not ready, current phase: %s`,
+			repo.Name, backup.Name, repo.Status.Phase))
+	}
+	return checkBackupPath(reqCtx, cli, restoreMgr, backupSet, repo)
+}
+
+// checkBackupPath runs a job to list the backup path with datasafed, the backup data is not accessible
+// if the job fails. The restore is reconciled again when the job is finished.
+func checkBackupPath(reqCtx intctrlutil.RequestCtx, cli client.Client, restoreMgr *RestoreManager,
+	backupSet BackupActionSet, repo *dpv1alpha1.BackupRepo) (bool, error) {
+	restore := restoreMgr.Restore
+	backup := backupSet.Backup
+	backupPath := backup.Status.Path
+	if !strings.HasPrefix(backupPath, "/") {
+		backupPath = "/" + backupPath
+	}
+	job := newRestoreJobBuilder(restore, backupSet, repo, dpv1alpha1.PostReady).
+		setJobName(cutJobName(fmt.Sprintf("restore-preflight-%s-%s", restore.UID[:8], backup.Name))).
+		setImage(viper.GetString(constant.KBToolsImage)).
+		setCommand([]string{"sh", "-c"}).
+		setArgs([]string{buildCheckBackupPathScript(backupPath)}).
+		setServiceAccount(restoreMgr.WorkerServiceAccount).
+		attachBackupRepo().
+		build()
+	jobs, err := restoreMgr.CreateJobsIfNotExist(reqCtx, cli, restore, []*batchv1.Job{job})
+	if err != nil {
+		return false, err
+	}
+	done, _, errMsg := utils.IsJobFinished(jobs[0])
+	switch {
+	case errMsg != "":
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`failed to access the path "%s" of backup "%s", see the logs of job "%s" for details, %s`,
+			backupPath, backup.Name, job.Name, errMsg))
+	case !done:
+		return false, intctrlutil.NewErrorf(intctrlutil.ErrorTypeRequeue, `waiting for job "%s" to access the path of backup "%s"`, job.Name, backup.Name)
+	}
+	return true, nil
+}
+
+func buildCheckBackupPathScript(backupPath string) string {
+	return fmt.Sprintf(`
+set -e
+export PATH="$PATH:$%s"
+backupPath="%s"
+result=$(datasafed list "${backupPath}")
+if [ -z "${result}" ]; then
+	echo "backup path ${backupPath} is empty or does not exist"
+	exit 1
+fi
+echo "${result}"
+`, dptypes.DPDatasafedBinPath, backupPath)
+}
+
+// checkEncryptionKey checks whether the secret of the encryption key exists in the namespace of the restore,
+// which is required by the restore jobs to decrypt the backup data.
+func checkEncryptionKey(reqCtx intctrlutil.RequestCtx, cli client.Client,
+	restoreMgr *RestoreManager, backupSet BackupActionSet) (bool, error) {
+	restore := restoreMgr.Restore
+	backup := backupSet.Backup
+	encryptionConfig := backup.Status.EncryptionConfig
+	if encryptionConfig == nil || encryptionConfig.PassPhraseSecretKeyRef == nil {
+		return false, nil
+	}
+	secretRef := encryptionConfig.PassPhraseSecretKeyRef
+	secret := &corev1.Secret{}
+	if err := cli.Get(reqCtx.Ctx, client.ObjectKey{Namespace: restore.Namespace, Name: secretRef.Name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, intctrlutil.NewFatalError(fmt.Sprintf(`secret "%s" of the encryption key of backup "%s" does not exist in namespace "%s"`,
+				secretRef.Name, backup.Name, restore.Namespace))
+		}
+		return false, err
+	}
+	if _, ok := secret.Data[secretRef.Key]; !ok {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`key "%s" of the encryption key of backup "%s" does not exist in secret "%s"`,
+			secretRef.Key, backup.Name, secretRef.Name))
+	}
+	return true, nil
+}
+
+// checkConnectionPassword checks whether the connection password saved in the backup can be decrypted.
+func checkConnectionPassword(_ intctrlutil.RequestCtx, _ client.Client,
+	_ *RestoreManager, backupSet BackupActionSet) (bool, error) {
+	backup := backupSet.Backup
+	ciphertext := backup.Annotations[dptypes.ConnectionPasswordAnnotationKey]
+	if ciphertext == "" {
+		return false, nil
+	}
+	algorithm := utils.GetConnectionPasswordEncryptionAlgorithm(backup.Status.EncryptionConfig)
+	e, err := intctrlutil.NewEncryptorWithAlgorithm(viper.GetString(constant.CfgKeyDPEncryptionKey), algorithm)
+	if err != nil {
+		return false, intctrlutil.NewFatalError(err.Error())
+	}
+	if _, err = e.Decrypt([]byte(ciphertext)); err != nil {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`failed to decrypt the connection password of backup "%s": %s`, backup.Name, err.Error()))
+	}
+	return true, nil
+}
+
+// checkClusterSnapshot checks whether the cluster snapshot saved in the backup can be parsed by the current
+// cluster API, the unknown fields are rejected as they will be dropped when restoring the cluster.
+func checkClusterSnapshot(_ intctrlutil.RequestCtx, _ client.Client,
+	_ *RestoreManager, backupSet BackupActionSet) (bool, error) {
+	backup := backupSet.Backup
+	snapshot := backup.Annotations[constant.ClusterSnapshotAnnotationKey]
+	if snapshot == "" {
+		return false, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(snapshot))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&appsv1alpha1.Cluster{}); err != nil {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`failed to parse the cluster snapshot of backup "%s": %s`, backup.Name, err.Error()))
+	}
+	return true, nil
+}
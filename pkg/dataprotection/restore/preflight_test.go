@@ -0,0 +1,271 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"context"
+	"testing"
+
+	vsv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+func TestRunPreflightChecks(t *testing.T) {
+	const (
+		namespace     = "default"
+		repoName      = "test-repo"
+		secretName    = "test-encryption-key"
+		secretKey     = "key"
+		encryptionKey = "test-encryption-key"
+	)
+	viper.Set(constant.CfgKeyDPEncryptionKey, encryptionKey)
+	defer viper.Set(constant.CfgKeyDPEncryptionKey, nil)
+
+	e, err := intctrlutil.NewEncryptorWithAlgorithm(encryptionKey, intctrlutil.EncryptionAlgorithmAES256GCM)
+	assert.NoError(t, err)
+	password, err := e.Encrypt([]byte("password"))
+	assert.NoError(t, err)
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, dpv1alpha1.AddToScheme(scheme))
+	assert.NoError(t, vsv1.AddToScheme(scheme))
+
+	newRepo := func(phase dpv1alpha1.BackupRepoPhase) *dpv1alpha1.BackupRepo {
+		return &dpv1alpha1.BackupRepo{
+			ObjectMeta: metav1.ObjectMeta{Name: repoName},
+			Status:     dpv1alpha1.BackupRepoStatus{Phase: phase},
+		}
+	}
+	newSecret := func(key string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secretName},
+			Data:       map[string][]byte{key: []byte("passphrase")},
+		}
+	}
+	newBackup := func() *dpv1alpha1.Backup {
+		return &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "test-backup",
+				Annotations: map[string]string{
+					dptypes.ConnectionPasswordAnnotationKey: password,
+					constant.ClusterSnapshotAnnotationKey:   `{"metadata":{"name":"test-cluster"},"spec":{"clusterDefinitionRef":"mysql"}}`,
+				},
+			},
+			Status: dpv1alpha1.BackupStatus{
+				BackupRepoName: repoName,
+				Path:           "/default/test-backup",
+				EncryptionConfig: &dpv1alpha1.EncryptionConfig{
+					Algorithm: intctrlutil.EncryptionAlgorithmAES256GCM,
+					PassPhraseSecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+						Key:                  secretKey,
+					},
+				},
+			},
+		}
+	}
+	actionSet := &dpv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-actionset"},
+		Spec: dpv1alpha1.ActionSetSpec{
+			BackupType: dpv1alpha1.BackupTypeFull,
+			Restore: &dpv1alpha1.RestoreActionSpec{
+				PrepareData: &dpv1alpha1.JobActionSpec{
+					BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+						Image:   "restore-image:1.0",
+						Command: []string{"sh", "-c", "restore"},
+					},
+				},
+			},
+		},
+	}
+	assertCondition := func(restore *dpv1alpha1.Restore, conditionType, reason string) {
+		cond := meta.FindStatusCondition(restore.Status.Conditions, conditionType)
+		assert.NotNil(t, cond)
+		assert.Equal(t, reason, cond.Reason, cond.Message)
+	}
+	// runChecksWithJob runs the checks until the jobs to access the backup path are finished with jobCondition.
+	runChecksWithJob := func(backupSet BackupActionSet, jobCondition batchv1.JobConditionType, objs ...client.Object) (bool, *dpv1alpha1.Restore) {
+		backupSet.ActionSet = actionSet
+		ctx := context.Background()
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		restore := &dpv1alpha1.Restore{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-restore", UID: "7d3c52e5-1f0e-4c4b-9a4e-f3a1c1a1b2c3"},
+			Spec:       dpv1alpha1.RestoreSpec{Preflight: true},
+		}
+		restoreMgr := NewRestoreManager(restore, record.NewFakeRecorder(10), scheme)
+		restoreMgr.SetBackupSets(backupSet)
+		reqCtx := intctrlutil.RequestCtx{Ctx: ctx}
+		finished, passed, err := RunPreflightChecks(reqCtx, cli, restoreMgr)
+		assert.NoError(t, err)
+		if finished {
+			return passed, restore
+		}
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightChecking)
+		jobs := &batchv1.JobList{}
+		assert.NoError(t, cli.List(ctx, jobs, client.InNamespace(namespace)))
+		assert.Len(t, jobs.Items, 1)
+		job := &jobs.Items[0]
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], `datasafed list "${backupPath}"`)
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args[0], backupSet.Backup.Status.Path)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: jobCondition, Status: corev1.ConditionTrue}}
+		assert.NoError(t, cli.Status().Update(ctx, job))
+		finished, passed, err = RunPreflightChecks(reqCtx, cli, restoreMgr)
+		assert.NoError(t, err)
+		assert.True(t, finished)
+		return passed, restore
+	}
+	runChecks := func(backupSet BackupActionSet, objs ...client.Object) (bool, *dpv1alpha1.Restore) {
+		return runChecksWithJob(backupSet, batchv1.JobComplete, objs...)
+	}
+
+	t.Run("all checks pass", func(t *testing.T) {
+		passed, restore := runChecks(BackupActionSet{Backup: newBackup()}, newRepo(dpv1alpha1.BackupRepoReady), newSecret(secretKey))
+		assert.True(t, passed)
+		assert.Len(t, restore.Status.Conditions, 4)
+		for _, cond := range restore.Status.Conditions {
+			assert.Equal(t, ReasonPreflightPassed, cond.Reason, cond.Type)
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		}
+	})
+
+	t.Run("the checks are skipped if not applicable", func(t *testing.T) {
+		backup := newBackup()
+		backup.Annotations = nil
+		backup.Status.EncryptionConfig = nil
+		passed, restore := runChecks(BackupActionSet{Backup: backup}, newRepo(dpv1alpha1.BackupRepoReady))
+		assert.True(t, passed)
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightPassed)
+		assertCondition(restore, ConditionTypePreflightEncryptionKey, ReasonPreflightSkipped)
+		assertCondition(restore, ConditionTypePreflightConnectionPassword, ReasonPreflightSkipped)
+		assertCondition(restore, ConditionTypePreflightClusterSnapshot, ReasonPreflightSkipped)
+	})
+
+	t.Run("the backup path is empty", func(t *testing.T) {
+		backup := newBackup()
+		backup.Status.Path = ""
+		passed, restore := runChecks(BackupActionSet{Backup: backup}, newRepo(dpv1alpha1.BackupRepoReady), newSecret(secretKey))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightFailed)
+	})
+
+	t.Run("the backup repository does not exist", func(t *testing.T) {
+		passed, restore := runChecks(BackupActionSet{Backup: newBackup()}, newSecret(secretKey))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightFailed)
+		assertCondition(restore, ConditionTypePreflightEncryptionKey, ReasonPreflightPassed)
+	})
+
+	t.Run("the backup repository is not ready", func(t *testing.T) {
+		passed, restore := runChecks(BackupActionSet{Backup: newBackup()}, newRepo(dpv1alpha1.BackupRepoFailed), newSecret(secretKey))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightFailed)
+	})
+
+	t.Run("the backup path can not be accessed", func(t *testing.T) {
+		passed, restore := runChecksWithJob(BackupActionSet{Backup: newBackup()}, batchv1.JobFailed,
+			newRepo(dpv1alpha1.BackupRepoReady), newSecret(secretKey))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightFailed)
+		assertCondition(restore, ConditionTypePreflightEncryptionKey, ReasonPreflightPassed)
+	})
+
+	t.Run("the volume snapshot does not exist", func(t *testing.T) {
+		backup := newBackup()
+		backup.Status.Actions = []dpv1alpha1.ActionStatus{
+			{VolumeSnapshots: []dpv1alpha1.VolumeSnapshotStatus{{Name: "test-backup-data"}, {Name: "test-backup-log"}}},
+		}
+		snapshot := &vsv1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-backup-data"}}
+		passed, restore := runChecks(BackupActionSet{Backup: backup, UseVolumeSnapshot: true}, snapshot, newSecret(secretKey))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightBackupArtifacts, ReasonPreflightFailed)
+		assert.Contains(t, meta.FindStatusCondition(restore.Status.Conditions, ConditionTypePreflightBackupArtifacts).Message, "test-backup-log")
+	})
+
+	t.Run("the secret of the encryption key does not exist", func(t *testing.T) {
+		passed, restore := runChecks(BackupActionSet{Backup: newBackup()}, newRepo(dpv1alpha1.BackupRepoReady))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightEncryptionKey, ReasonPreflightFailed)
+	})
+
+	t.Run("the key of the encryption key does not exist", func(t *testing.T) {
+		passed, restore := runChecks(BackupActionSet{Backup: newBackup()}, newRepo(dpv1alpha1.BackupRepoReady), newSecret("other"))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightEncryptionKey, ReasonPreflightFailed)
+	})
+
+	t.Run("the backup is checked with its recorded key after the key is rotated", func(t *testing.T) {
+		// the backup policy has been rotated to a new key, which does not exist in the namespace of the restore.
+		backupPolicy := &dpv1alpha1.BackupPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-backup-policy"},
+			Spec: dpv1alpha1.BackupPolicySpec{
+				EncryptionConfig: &dpv1alpha1.EncryptionConfig{
+					Algorithm: intctrlutil.EncryptionAlgorithmChaCha20Poly1305,
+					PassPhraseSecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "rotated-encryption-key"},
+						Key:                  secretKey,
+					},
+				},
+			},
+		}
+		backup := newBackup()
+		backup.Spec.BackupPolicyName = backupPolicy.Name
+		passed, restore := runChecks(BackupActionSet{Backup: backup}, backupPolicy, newRepo(dpv1alpha1.BackupRepoReady), newSecret(secretKey))
+		assert.True(t, passed)
+		assertCondition(restore, ConditionTypePreflightEncryptionKey, ReasonPreflightPassed)
+		assertCondition(restore, ConditionTypePreflightConnectionPassword, ReasonPreflightPassed)
+	})
+
+	t.Run("the connection password can not be decrypted", func(t *testing.T) {
+		backup := newBackup()
+		otherPassword, err := intctrlutil.NewEncryptor("other-encryption-key").Encrypt([]byte("password"))
+		assert.NoError(t, err)
+		backup.Annotations[dptypes.ConnectionPasswordAnnotationKey] = otherPassword
+		passed, restore := runChecks(BackupActionSet{Backup: backup}, newRepo(dpv1alpha1.BackupRepoReady), newSecret(secretKey))
+		assert.False(t, passed)
+		assertCondition(restore, ConditionTypePreflightConnectionPassword, ReasonPreflightFailed)
+	})
+
+	t.Run("the cluster snapshot can not be parsed", func(t *testing.T) {
+		for _, snapshot := range []string{`{"spec":`, `{"spec":{"unknownField":"value"}}`} {
+			backup := newBackup()
+			backup.Annotations[constant.ClusterSnapshotAnnotationKey] = snapshot
+			passed, restore := runChecks(BackupActionSet{Backup: backup}, newRepo(dpv1alpha1.BackupRepoReady), newSecret(secretKey))
+			assert.False(t, passed)
+			assertCondition(restore, ConditionTypePreflightClusterSnapshot, ReasonPreflightFailed)
+		}
+	})
+}
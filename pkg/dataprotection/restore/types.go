@@ -28,6 +28,13 @@ const (
 	ConditionTypeRestorePreparedData     = "PrepareData"
 	ConditionTypeReadinessProbe          = "ReadinessProbe"
 	ConditionTypeRestorePostReady        = "PostReady"
+	ConditionTypeActionSetChanged        = "ActionSetChanged"
+
+	// condition types of the preflight checks
+	ConditionTypePreflightBackupArtifacts    = "PreflightBackupArtifacts"
+	ConditionTypePreflightEncryptionKey      = "PreflightEncryptionKey"
+	ConditionTypePreflightConnectionPassword = "PreflightConnectionPassword"
+	ConditionTypePreflightClusterSnapshot    = "PreflightClusterSnapshot"
 
 	// condition reasons
 	ReasonRestoreStarting      = "RestoreStarting"
@@ -38,6 +45,11 @@ const (
 	ReasonProcessing           = "Processing"
 	ReasonFailed               = "Failed"
 	ReasonSucceed              = "Succeed"
+	ReasonActionSetChanged     = "ActionSetChanged"
+	ReasonPreflightPassed      = "PreflightPassed"
+	ReasonPreflightFailed      = "PreflightFailed"
+	ReasonPreflightSkipped     = "PreflightSkipped"
+	ReasonPreflightChecking    = "PreflightChecking"
 	reasonCreateRestoreJob     = "CreateRestoreJob"
 	reasonCreateRestorePVC     = "CreateRestorePVC"
 )
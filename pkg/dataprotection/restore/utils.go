@@ -255,6 +255,9 @@ func ValidateAndInitRestoreMGR(reqCtx intctrlutil.RequestCtx,
 		err = intctrlutil.NewFatalError(fmt.Sprintf(`phase of backup "%s" is not completed`, backupName))
 		return err
 	}
+	if backupSet.Backup.IsDryRun() {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`backup "%s" is a dry run and has no backup data`, backupName))
+	}
 
 	// build backupActionSets of prepareData and postReady stage based on the specified backup's type.
 	switch backupType {
@@ -269,7 +272,51 @@ func ValidateAndInitRestoreMGR(reqCtx intctrlutil.RequestCtx,
 	default:
 		err = intctrlutil.NewFatalError(fmt.Sprintf("backup type of %s is empty", backupName))
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	return checkActionSetsChanged(restoreMgr)
+}
+
+// checkActionSetsChanged checks whether the ActionSets to restore the backups have changed since the backups
+// were taken, by comparing the hashes of the ActionSets with the ones recorded in the backups.
+// If any of them has changed, the restore fails if the actionSetChangedPolicy is Fail, otherwise the change
+// is recorded in the ActionSetChanged condition.
+func checkActionSetsChanged(restoreMgr *RestoreManager) error {
+	var (
+		changed []string
+		checked = map[string]bool{}
+	)
+	backupSets := append(append([]BackupActionSet{}, restoreMgr.PrepareDataBackupSets...), restoreMgr.PostReadyBackupSets...)
+	for _, backupSet := range backupSets {
+		backup := backupSet.Backup
+		// the backups taken before the ActionSet hash is recorded are not checked.
+		if backupSet.ActionSet == nil || backup.Status.ActionSetHash == "" || checked[backup.Name] {
+			continue
+		}
+		checked[backup.Name] = true
+		actionSetHash, err := utils.ComputeActionSetHash(backupSet.ActionSet)
+		if err != nil {
+			return err
+		}
+		if actionSetHash != backup.Status.ActionSetHash {
+			changed = append(changed, fmt.Sprintf(`ActionSet "%s" of backup "%s" (generation %d at backup time, %d now)`,
+				backupSet.ActionSet.Name, backup.Name, backup.Status.ActionSetGeneration, backupSet.ActionSet.Generation))
+		}
+	}
+
+	restore := restoreMgr.Restore
+	if len(changed) == 0 {
+		meta.RemoveStatusCondition(&restore.Status.Conditions, ConditionTypeActionSetChanged)
+		return nil
+	}
+	message := fmt.Sprintf("the backups may not be restorable as the ActionSets have changed since they were taken: %s",
+		strings.Join(changed, ", "))
+	if restore.Spec.ActionSetChangedPolicy == dpv1alpha1.ActionSetChangedPolicyFail {
+		return intctrlutil.NewFatalError(message)
+	}
+	SetRestoreCondition(restore, metav1.ConditionTrue, ConditionTypeActionSetChanged, ReasonActionSetChanged, message)
+	return nil
 }
 
 func cutJobName(jobName string) string {
@@ -326,6 +373,8 @@ func GetRestoreFromBackupAnnotation(backup *dpv1alpha1.Backup, compSpecs []appsv
 	connectionPassword := backup.Annotations[dptypes.ConnectionPasswordAnnotationKey]
 	if connectionPassword != "" {
 		restoreInfoMap[constant.ConnectionPassword] = connectionPassword
+		// the algorithm is recorded in the backup status, decrypt the password with it.
+		restoreInfoMap[constant.EncryptionAlgorithmKeyForRestore] = utils.GetConnectionPasswordEncryptionAlgorithm(backup.Status.EncryptionConfig)
 	}
 	restoreForClusterMap := map[string]map[string]string{}
 	restoreForClusterMap[componentName] = restoreInfoMap
@@ -0,0 +1,135 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+)
+
+func TestCheckActionSetsChanged(t *testing.T) {
+	newActionSet := func(command string) *dpv1alpha1.ActionSet {
+		return &dpv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-actionset", Generation: 1},
+			Spec: dpv1alpha1.ActionSetSpec{
+				BackupType: dpv1alpha1.BackupTypeFull,
+				Restore: &dpv1alpha1.RestoreActionSpec{
+					PrepareData: &dpv1alpha1.JobActionSpec{
+						BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+							Image:   "restore-image:1.0",
+							Command: []string{"sh", "-c", command},
+						},
+					},
+				},
+			},
+		}
+	}
+	backupActionSet := newActionSet("restore")
+	backupHash, err := utils.ComputeActionSetHash(backupActionSet)
+	assert.NoError(t, err)
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-backup"},
+		Status: dpv1alpha1.BackupStatus{
+			ActionSetName:       backupActionSet.Name,
+			ActionSetGeneration: backupActionSet.Generation,
+			ActionSetHash:       backupHash,
+		},
+	}
+	newRestoreMgr := func(actionSet *dpv1alpha1.ActionSet, policy dpv1alpha1.ActionSetChangedPolicy) *RestoreManager {
+		restore := &dpv1alpha1.Restore{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-restore"},
+			Spec:       dpv1alpha1.RestoreSpec{ActionSetChangedPolicy: policy},
+		}
+		restoreMgr := NewRestoreManager(restore, nil, nil)
+		restoreMgr.SetBackupSets(BackupActionSet{Backup: backup, ActionSet: actionSet})
+		return restoreMgr
+	}
+	changedActionSet := newActionSet("restore --new-flag")
+	changedActionSet.Generation = 2
+
+	t.Run("the ActionSet is not changed", func(t *testing.T) {
+		restoreMgr := newRestoreMgr(newActionSet("restore"), dpv1alpha1.ActionSetChangedPolicyFail)
+		assert.NoError(t, checkActionSetsChanged(restoreMgr))
+		assert.Nil(t, meta.FindStatusCondition(restoreMgr.Restore.Status.Conditions, ConditionTypeActionSetChanged))
+	})
+
+	t.Run("the ActionSet is changed with the Warn policy", func(t *testing.T) {
+		restoreMgr := newRestoreMgr(changedActionSet, dpv1alpha1.ActionSetChangedPolicyWarn)
+		assert.NoError(t, checkActionSetsChanged(restoreMgr))
+		cond := meta.FindStatusCondition(restoreMgr.Restore.Status.Conditions, ConditionTypeActionSetChanged)
+		assert.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Contains(t, cond.Message, "generation 1 at backup time, 2 now")
+	})
+
+	t.Run("the ActionSet is changed with the Fail policy", func(t *testing.T) {
+		restoreMgr := newRestoreMgr(changedActionSet, dpv1alpha1.ActionSetChangedPolicyFail)
+		err := checkActionSetsChanged(restoreMgr)
+		assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+	})
+
+	t.Run("the backup without the ActionSet hash is not checked", func(t *testing.T) {
+		legacyBackup := backup.DeepCopy()
+		legacyBackup.Status.ActionSetHash = ""
+		restoreMgr := NewRestoreManager(&dpv1alpha1.Restore{
+			Spec: dpv1alpha1.RestoreSpec{ActionSetChangedPolicy: dpv1alpha1.ActionSetChangedPolicyFail},
+		}, nil, nil)
+		restoreMgr.SetBackupSets(BackupActionSet{Backup: legacyBackup, ActionSet: changedActionSet})
+		assert.NoError(t, checkActionSetsChanged(restoreMgr))
+	})
+}
+
+func TestBuildPVCVolumeAndMountWithSubPaths(t *testing.T) {
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-backup"},
+		Status: dpv1alpha1.BackupStatus{
+			BackupMethod: &dpv1alpha1.BackupMethod{
+				TargetVolumes: &dpv1alpha1.TargetVolumeInfo{
+					VolumeMounts:   []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+					VolumeSubPaths: []string{"mysql", "binlog"},
+				},
+			},
+		},
+	}
+	restore := &dpv1alpha1.Restore{ObjectMeta: metav1.ObjectMeta{Name: "test-restore", UID: "12345678-uid"}}
+	builder := newRestoreJobBuilder(restore, BackupActionSet{Backup: backup}, nil, dpv1alpha1.PrepareData)
+
+	volume, volumeMounts, err := builder.buildPVCVolumeAndMount(dpv1alpha1.VolumeConfig{VolumeSource: "data"}, "pvc-data", "dp-claim")
+	assert.NoError(t, err)
+	assert.Equal(t, "dp-claim-pvc-data", volume.Name)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "dp-claim-pvc-data", MountPath: "/data/mysql", SubPath: "mysql"},
+		{Name: "dp-claim-pvc-data", MountPath: "/data/binlog", SubPath: "binlog"},
+	}, volumeMounts)
+
+	// the whole volume is mounted if the backup has no sub paths.
+	backup.Status.BackupMethod.TargetVolumes.VolumeSubPaths = nil
+	_, volumeMounts, err = builder.buildPVCVolumeAndMount(dpv1alpha1.VolumeConfig{MountPath: "/restore"}, "pvc-data", "dp-claim")
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "dp-claim-pvc-data", MountPath: "/restore"}}, volumeMounts)
+}
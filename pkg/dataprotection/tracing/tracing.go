@@ -0,0 +1,147 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	tracerName  = "github.com/apecloud/kubeblocks/pkg/dataprotection"
+	serviceName = "kubeblocks-dataprotection"
+)
+
+var (
+	// enabled is set once the tracing is set up, the spans are not started if it is not set,
+	// so that the instrumentation allocates nothing in the hot path.
+	enabled bool
+
+	tracer trace.Tracer = noop.NewTracerProvider().Tracer(tracerName)
+
+	// noopSpan is returned if the tracing is disabled.
+	noopSpan = trace.SpanFromContext(context.Background())
+
+	propagator = propagation.TraceContext{}
+)
+
+// Setup sets up the tracer provider which exports the traces to the OTLP endpoint configured by
+// CfgKeyTracingOTLPEndpoint. The tracing is disabled if the endpoint is not configured. The returned
+// function shuts down the tracer provider and flushes the pending spans.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	endpoint := viper.GetString(dptypes.CfgKeyTracingOTLPEndpoint)
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if viper.GetBool(dptypes.CfgKeyTracingOTLPInsecure) {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(viper.GetFloat64(dptypes.CfgKeyTracingSamplingRatio)))),
+	)
+	setTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func setTracerProvider(provider trace.TracerProvider) {
+	tracer = provider.Tracer(tracerName)
+	enabled = true
+}
+
+// StartBackupSpan starts a span carrying the attributes of the backup, it returns the noop span
+// if the tracing is disabled.
+func StartBackupSpan(ctx context.Context, spanName string, backup *dpv1alpha1.Backup) (context.Context, trace.Span) {
+	if !enabled {
+		return ctx, noopSpan
+	}
+	return tracer.Start(ctx, spanName, trace.WithAttributes(backupAttributes(backup)...))
+}
+
+// StartActionSpan starts a span of the backup action carrying the attributes of the backup and the action name,
+// it returns the noop span if the tracing is disabled.
+func StartActionSpan(ctx context.Context, backup *dpv1alpha1.Backup, actionName string) (context.Context, trace.Span) {
+	if !enabled {
+		return ctx, noopSpan
+	}
+	return tracer.Start(ctx, "Backup.Action/"+actionName, trace.WithAttributes(
+		append(backupAttributes(backup), attribute.String("backup.action", actionName))...))
+}
+
+// EndSpan ends the span, and records the error in the span if it is not nil.
+func EndSpan(span trace.Span, err error) {
+	if !enabled {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectTraceContext returns the annotations with the W3C trace context of the span in the context,
+// so that the tools instrumented in the job pods can join the trace. The annotations are returned
+// as they are if the tracing is disabled.
+func InjectTraceContext(ctx context.Context, annotations map[string]string) map[string]string {
+	if !enabled || !trace.SpanContextFromContext(ctx).IsValid() {
+		return annotations
+	}
+	carrier := make(propagation.MapCarrier, len(annotations)+2)
+	for k, v := range annotations {
+		carrier[k] = v
+	}
+	propagator.Inject(ctx, carrier)
+	return carrier
+}
+
+func backupAttributes(backup *dpv1alpha1.Backup) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("backup.name", backup.Name),
+		attribute.String("backup.namespace", backup.Namespace),
+		attribute.String("backup.policy", backup.Spec.BackupPolicyName),
+		attribute.String("backup.method", backup.Spec.BackupMethod),
+		attribute.String("backup.repo", backup.Status.BackupRepoName),
+	}
+}
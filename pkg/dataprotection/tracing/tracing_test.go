@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+)
+
+func newBackup() *dpv1alpha1.Backup {
+	return &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: "policy",
+			BackupMethod:     "xtrabackup",
+		},
+		Status: dpv1alpha1.BackupStatus{BackupRepoName: "repo"},
+	}
+}
+
+// enableTracing enables the tracing with a span recorder, and disables it after the test.
+func enableTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	setTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		enabled = false
+	})
+	return recorder
+}
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.False(t, enabled)
+}
+
+func TestDisabledTracing(t *testing.T) {
+	backup := newBackup()
+	ctx := context.Background()
+	spanCtx, span := StartBackupSpan(ctx, "Backup.Reconcile", backup)
+	assert.Equal(t, ctx, spanCtx)
+	assert.False(t, span.SpanContext().IsValid())
+
+	annotations := map[string]string{"key": "value"}
+	assert.Equal(t, annotations, InjectTraceContext(spanCtx, annotations))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		spanCtx, span := StartBackupSpan(ctx, "Backup.Reconcile", backup)
+		_, actionSpan := StartActionSpan(spanCtx, backup, "backup-data")
+		EndSpan(actionSpan, nil)
+		_ = InjectTraceContext(spanCtx, annotations)
+		EndSpan(span, nil)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestEnabledTracing(t *testing.T) {
+	recorder := enableTracing(t)
+	backup := newBackup()
+
+	ctx, span := StartBackupSpan(context.Background(), "Backup.Reconcile", backup)
+	actionCtx, actionSpan := StartActionSpan(ctx, backup, "backup-data")
+	annotations := InjectTraceContext(actionCtx, map[string]string{"key": "value"})
+	assert.Equal(t, "value", annotations["key"])
+	assert.Contains(t, annotations["traceparent"], actionSpan.SpanContext().TraceID().String())
+	EndSpan(actionSpan, errors.New("job failed"))
+	EndSpan(span, nil)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "Backup.Action/backup-data", spans[0].Name())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("backup.action", "backup-data"))
+	assert.Equal(t, "Backup.Reconcile", spans[1].Name())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("backup.name", "backup"),
+		attribute.String("backup.namespace", "default"),
+		attribute.String("backup.policy", "policy"),
+		attribute.String("backup.method", "xtrabackup"),
+		attribute.String("backup.repo", "repo"),
+	}, spans[1].Attributes())
+}
+
+func BenchmarkDisabledTracing(b *testing.B) {
+	backup := newBackup()
+	ctx := context.Background()
+	annotations := map[string]string{"key": "value"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		spanCtx, span := StartBackupSpan(ctx, "Backup.Reconcile", backup)
+		_, actionSpan := StartActionSpan(spanCtx, backup, "backup-data")
+		EndSpan(actionSpan, nil)
+		_ = InjectTraceContext(spanCtx, annotations)
+		EndSpan(span, nil)
+	}
+}
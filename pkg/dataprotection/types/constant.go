@@ -29,16 +29,46 @@ const (
 	CfgKeyWorkerServiceAccountName = "WORKER_SERVICE_ACCOUNT_NAME"
 	// CfgKeyExecWorkerServiceAccountName is the key of service account name for worker that runs "kubectl exec"
 	CfgKeyExecWorkerServiceAccountName = "EXEC_WORKER_SERVICE_ACCOUNT_NAME"
+	// CfgKeyExecWorkerClusterRoleName is the key of cluster role name for binding the service account of the exec worker
+	CfgKeyExecWorkerClusterRoleName = "EXEC_WORKER_CLUSTER_ROLE_NAME"
 	// CfgKeyWorkerServiceAccountAnnotations is the key of annotations for the service account of the worker
 	CfgKeyWorkerServiceAccountAnnotations = "WORKER_SERVICE_ACCOUNT_ANNOTATIONS"
 	// CfgKeyWorkerClusterRoleName is the key of cluster role name for binding the service account of the worker
 	CfgKeyWorkerClusterRoleName = "WORKER_CLUSTER_ROLE_NAME"
+	// CfgKeyDeletionJobConcurrency is the key of the maximum number of in-flight jobs for deleting backup files
+	CfgKeyDeletionJobConcurrency = "DELETION_JOB_CONCURRENCY"
+	// CfgKeyPropagateClusterLabels is the key of the comma-separated cluster label keys which are propagated to the backups
+	CfgKeyPropagateClusterLabels = "PROPAGATE_CLUSTER_LABELS"
+	// CfgKeyEnableCrossNamespaceBackup is the key of the feature gate to back up the target pods in other namespaces
+	CfgKeyEnableCrossNamespaceBackup = "ENABLE_CROSS_NAMESPACE_BACKUP"
+	// CfgKeyBackupSizeEstimatePercent is the key of the percentage of the size of the previous backup used to
+	// estimate the size of a new backup, which is checked against the free space of the backup repo
+	CfgKeyBackupSizeEstimatePercent = "BACKUP_SIZE_ESTIMATE_PERCENT"
+	// CfgKeyFreezeContinuousBackupWorkload is the key of the feature gate to freeze the pod template of the statefulSets
+	// of the running continuous backups, they are not rolled when the ActionSet or the tool config is changed.
+	CfgKeyFreezeContinuousBackupWorkload = "FREEZE_CONTINUOUS_BACKUP_WORKLOAD"
+	// CfgKeyWorkerLegacySharedRole is the key of the compatibility flag to run the backup workers with the shared
+	// worker service account bound to the worker cluster role, instead of the roles computed for the backup policies.
+	CfgKeyWorkerLegacySharedRole = "WORKER_LEGACY_SHARED_ROLE"
+	// CfgKeyTracingOTLPEndpoint is the key of the OTLP gRPC endpoint the traces are exported to, the tracing is disabled if it is empty
+	CfgKeyTracingOTLPEndpoint = "TRACING_OTLP_ENDPOINT"
+	// CfgKeyTracingOTLPInsecure is the key of whether to export the traces to the OTLP endpoint without TLS
+	CfgKeyTracingOTLPInsecure = "TRACING_OTLP_INSECURE"
+	// CfgKeyTracingSamplingRatio is the key of the ratio of the traces sampled, from 0 to 1
+	CfgKeyTracingSamplingRatio = "TRACING_SAMPLING_RATIO"
+	// CfgKeyKopiaMaintenanceSchedule is the key of the default Cron schedule to run the maintenance of the Kopia repositories,
+	// it is overridden by the maintenanceSchedule of the BackupRepo, and the maintenance is disabled if both are empty
+	CfgKeyKopiaMaintenanceSchedule = "KOPIA_MAINTENANCE_SCHEDULE"
 )
 
 // config default values
 const (
 	// DefaultGCFrequencySeconds is the default gc frequency, its unit is second
 	DefaultGCFrequencySeconds = 60 * 60
+	// DefaultDeletionJobConcurrency is the default maximum number of in-flight jobs for deleting backup files
+	DefaultDeletionJobConcurrency = 10
+	// DefaultBackupSizeEstimatePercent is the default percentage of the size of the previous backup used to estimate the size of a new backup
+	DefaultBackupSizeEstimatePercent = 120
 )
 
 const (
@@ -60,6 +90,47 @@ const (
 	ConnectionPasswordAnnotationKey = "dataprotection.kubeblocks.io/connection-password"
 	// GeminiAcknowledgedAnnotationKey indicates whether Gemini has acknowledged the backup.
 	GeminiAcknowledgedAnnotationKey = "dataprotection.kubeblocks.io/gemini-acknowledged"
+	// SkipDeletionGracePeriodAnnotationKey specifies whether to skip the deletion grace period of the backup.
+	SkipDeletionGracePeriodAnnotationKey = "dataprotection.kubeblocks.io/skip-deletion-grace-period"
+	// ForceDeleteAnnotationKey specifies whether to delete the backup files even if there are incremental backups depending on it,
+	// or restores from it are in progress.
+	ForceDeleteAnnotationKey = "dataprotection.kubeblocks.io/force-delete"
+	// ActionSetHashAnnotationKey specifies the hash of the ActionSet spec used by the backup, it is exposed for external tooling.
+	ActionSetHashAnnotationKey = "kubeblocks.io/actionset-hash"
+	// TrimBeforeAnnotationKey specifies the time before which the data of the continuous backup is being trimmed,
+	// the backup workload is scaled down to zero while the annotation exists.
+	TrimBeforeAnnotationKey = "dataprotection.kubeblocks.io/trim-before"
+	// ResumeArchivePathAnnotationKey specifies the archive path of the continuous backup completed by disabling its
+	// schedule, the backup is resumed into the same path when the schedule is enabled again.
+	ResumeArchivePathAnnotationKey = "dataprotection.kubeblocks.io/resume-archive-path"
+	// BackupProgressAnnotationKey specifies the progress published by the backup job, e.g. {"progress":42,"processedSize":"10Gi"},
+	// it's synced from the file specified by DP_BACKUP_PROGRESS_FILE by the sync progress container.
+	BackupProgressAnnotationKey = "dataprotection.kubeblocks.io/progress"
+	// VolumeSnapshotClassAnnotationKey specifies the VolumeSnapshotClass used by the backup to take the volume snapshots,
+	// it overrides the VolumeSnapshotClass of the backup method.
+	VolumeSnapshotClassAnnotationKey = "dataprotection.kubeblocks.io/volume-snapshot-class"
+	// PodTemplateHashAnnotationKey specifies the hash of the rendered pod template of the continuous backup statefulSet,
+	// the statefulSet is updated when the hash is drifted.
+	PodTemplateHashAnnotationKey = "dataprotection.kubeblocks.io/pod-template-hash"
+	// ToolConfigVersionAnnotationKey specifies the resource version of the tool config secret used by the pods of the
+	// continuous backup statefulSet, the pods are restarted when the secret is changed.
+	ToolConfigVersionAnnotationKey = "dataprotection.kubeblocks.io/tool-config-version"
+	// CreatedByAnnotationKey specifies who or what created the backup, e.g. schedule/<name>, opsrequest/<name>
+	// or user/<username>, it's set when the backup is created and kept unchanged afterwards.
+	CreatedByAnnotationKey = "dataprotection.kubeblocks.io/created-by"
+	// RequestedByAnnotationKey specifies the user who requested the OpsRequest which created the backup.
+	RequestedByAnnotationKey = "dataprotection.kubeblocks.io/requested-by"
+	// RestartCountBaselineAnnotationKey specifies the restart count of the continuous backup pod when it was last running
+	// stably, the restarts before it are not counted by the retry policy.
+	RestartCountBaselineAnnotationKey = "dataprotection.kubeblocks.io/restart-count-baseline"
+)
+
+// the prefixes of the value of the created-by annotation
+const (
+	CreatedBySchedulePrefix   = "schedule/"
+	CreatedByOpsRequestPrefix = "opsrequest/"
+	CreatedByUserPrefix       = "user/"
+	CreatedByClusterPrefix    = "cluster/"
 )
 
 // label keys
@@ -82,6 +153,18 @@ const (
 	AutoBackupLabelKey = "dataprotection.kubeblocks.io/autobackup"
 	// BackupTargetPodLabelKey specifies the backup target pod label key.
 	BackupTargetPodLabelKey = "dataprotection.kubeblocks.io/target-pod-name"
+	// BackupTargetPodRoleAnnotationKey specifies the role of the backup target pod.
+	BackupTargetPodRoleAnnotationKey = "dataprotection.kubeblocks.io/target-pod-role"
+	// BackupDeletionJobLabelKey specifies the label key of the jobs for deleting backup files, which are
+	// counted by the deletion job concurrency limit.
+	BackupDeletionJobLabelKey = "dataprotection.kubeblocks.io/backup-deletion-job"
+	// BackupVerificationJobLabelKey specifies the label key of the jobs for verifying backups.
+	BackupVerificationJobLabelKey = "dataprotection.kubeblocks.io/backup-verification-job"
+	// BackupCopyJobLabelKey specifies the label key of the jobs for copying backups to other backup repos.
+	BackupCopyJobLabelKey = "dataprotection.kubeblocks.io/backup-copy-job"
+	// FinalBackupLabelKey specifies the label key of the final backup taken before the cluster is deleted,
+	// which is retained after the cluster is deleted.
+	FinalBackupLabelKey = "dataprotection.kubeblocks.io/final-backup"
 )
 
 // env names
@@ -100,24 +183,38 @@ const (
 	DPTargetPodName = "DP_TARGET_POD_NAME"
 	// DPTargetPodRole the target pod role
 	DPTargetPodRole = "DP_TARGET_POD_ROLE"
+	// DPTargetContainerName the target container name
+	DPTargetContainerName = "DP_TARGET_CONTAINER_NAME"
+	// DPBackupVolumeSubPaths the sub paths of the target volumes to back up, separated by commas
+	DPBackupVolumeSubPaths = "DP_BACKUP_VOLUME_SUBPATHS"
 	// DPBackupBasePath the base path for backup data in the storage
 	DPBackupBasePath = "DP_BACKUP_BASE_PATH"
 	// DPBackupName backup CR name
 	DPBackupName = "DP_BACKUP_NAME"
 	// DPParentBackupName backup CR name
 	DPParentBackupName = "DP_PARENT_BACKUP_NAME"
+	// DPParentBackupBasePath the base path for the parent backup data in the storage
+	DPParentBackupBasePath = "DP_PARENT_BACKUP_BASE_PATH"
+	// DPParentKopiaRepoPath the path of the Kopia repository of the parent backup
+	DPParentKopiaRepoPath = "DP_PARENT_KOPIA_REPO_PATH"
 	// DPTTL backup time to live, reference the backup.spec.retentionPeriod
 	DPTTL = "DP_TTL"
 	// DPCheckInterval check interval for sync backup progress
 	DPCheckInterval = "DP_CHECK_INTERVAL"
 	// DPBackupInfoFile the file name which retains the backup.status info
 	DPBackupInfoFile = "DP_BACKUP_INFO_FILE"
+	// DPBackupProgressFile the file name which retains the progress of the backup, e.g. {"progress":42,"processedSize":"10Gi"}
+	DPBackupProgressFile = "DP_BACKUP_PROGRESS_FILE"
+	// DPBackupJobName the name of the backup job, which is annotated with the backup progress
+	DPBackupJobName = "DP_BACKUP_JOB_NAME"
 	// DPTimeFormat golang time format string
 	DPTimeFormat = "DP_TIME_FORMAT"
 	// DPTimeZone golang time zone string
 	DPTimeZone = "DP_TIME_ZONE"
 	// DPBackupStopTime backup stop time
 	DPBackupStopTime = "DP_BACKUP_STOP_TIME" // backup stop time
+	// DPBackupTrimBeforeTime the data of the continuous backup before this time can be trimmed
+	DPBackupTrimBeforeTime = "DP_BACKUP_TRIM_BEFORE_TIME"
 	// DPDatasafedBinPath the path containing the datasafed binary
 	DPDatasafedBinPath = "DP_DATASAFED_BIN_PATH"
 
@@ -131,6 +228,9 @@ const (
 	DPDatasafedEncryptionAlgorithm = "DATASAFED_ENCRYPTION_ALGORITHM"
 	// DPDatasafedEncryptionPassPhrase specifies the encryption key
 	DPDatasafedEncryptionPassPhrase = "DATASAFED_ENCRYPTION_PASS_PHRASE"
+	// DPDatasafedProgressFile specifies the file to which datasafed writes the progress of the kopia snapshots,
+	// in the same format as DP_BACKUP_PROGRESS_FILE
+	DPDatasafedProgressFile = "DATASAFED_PROGRESS_FILE"
 
 	DPArchiveInterval      = "DP_ARCHIVE_INTERVAL"
 	DPContinuousTTLSeconds = "DP_TTL_SECONDS"
@@ -20,7 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package utils
 
 import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
 )
@@ -53,3 +60,161 @@ func GetBackupMethodsFromBackupPolicy(backupPolicyList *dpv1alpha1.BackupPolicyL
 	}
 	return defaultBackupMethod, backupMethodsMap
 }
+
+// GetRestorableTimeRanges returns the time ranges which the cluster can be restored to. A time range
+// starts from the stop time of a completed full backup and ends at the end time of the continuous backup
+// covering it. The returned ranges are merged, disjoint and sorted by the start time, a gap between them
+// means the continuous backup was interrupted, e.g. it was restarted.
+func GetRestorableTimeRanges(ctx context.Context, cli client.Client, clusterName, namespace string) ([]dpv1alpha1.BackupTimeRange, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(namespace),
+		client.MatchingLabels{constant.AppInstanceLabelKey: clusterName}); err != nil {
+		return nil, err
+	}
+	return buildRestorableTimeRanges(backupList.Items), nil
+}
+
+func buildRestorableTimeRanges(backups []dpv1alpha1.Backup) []dpv1alpha1.BackupTimeRange {
+	var fullBackups, continuousBackups []*dpv1alpha1.Backup
+	for i := range backups {
+		backup := &backups[i]
+		switch dpv1alpha1.BackupType(backup.Labels[dptypes.BackupTypeLabelKey]) {
+		case dpv1alpha1.BackupTypeFull:
+			if backup.Status.Phase == dpv1alpha1.BackupPhaseCompleted && !backup.IsDryRun() && backup.GetEndTime() != nil {
+				fullBackups = append(fullBackups, backup)
+			}
+		case dpv1alpha1.BackupTypeContinuous:
+			if backup.Status.Phase != dpv1alpha1.BackupPhaseFailed &&
+				backup.GetStartTime() != nil && backup.GetEndTime() != nil {
+				continuousBackups = append(continuousBackups, backup)
+			}
+		}
+	}
+
+	var timeRanges []dpv1alpha1.BackupTimeRange
+	for _, continuousBackup := range continuousBackups {
+		startTime, endTime := continuousBackup.GetStartTime(), continuousBackup.GetEndTime()
+		// find the earliest full backup which can be used as the base backup of the continuous backup,
+		// it follows the same rules as choosing the base backup when restoring.
+		var baseTime *metav1.Time
+		for _, fullBackup := range fullBackups {
+			if fullBackup.Labels[constant.KBAppComponentLabelKey] != continuousBackup.Labels[constant.KBAppComponentLabelKey] {
+				continue
+			}
+			stopTime := fullBackup.GetEndTime()
+			if stopTime.Before(startTime) || endTime.Before(stopTime) {
+				continue
+			}
+			if baseTime == nil || stopTime.Before(baseTime) {
+				baseTime = stopTime
+			}
+		}
+		if baseTime == nil {
+			continue
+		}
+		timeRanges = append(timeRanges, dpv1alpha1.BackupTimeRange{
+			TimeZone: continuousBackup.GetTimeZone(),
+			Start:    baseTime.DeepCopy(),
+			End:      endTime.DeepCopy(),
+		})
+	}
+	if len(timeRanges) == 0 {
+		return nil
+	}
+
+	// merge the overlapping time ranges.
+	sort.Slice(timeRanges, func(i, j int) bool {
+		return timeRanges[i].Start.Before(timeRanges[j].Start)
+	})
+	merged := []dpv1alpha1.BackupTimeRange{timeRanges[0]}
+	for _, timeRange := range timeRanges[1:] {
+		last := &merged[len(merged)-1]
+		if timeRange.Start.After(last.End.Time) {
+			merged = append(merged, timeRange)
+			continue
+		}
+		if last.End.Before(timeRange.End) {
+			last.End = timeRange.End
+		}
+	}
+	return merged
+}
+
+// GetParentBackupName returns the name of the parent backup, the one resolved in the status
+// takes precedence over the one specified in the spec.
+func GetParentBackupName(backup *dpv1alpha1.Backup) string {
+	if backup.Status.ParentBackupName != "" {
+		return backup.Status.ParentBackupName
+	}
+	return backup.Spec.ParentBackupName
+}
+
+// GetLatestFullBackup returns the latest completed full backup of the backup policy, the dry-run
+// backups are excluded. It returns nil if no such backup is found.
+func GetLatestFullBackup(ctx context.Context, cli client.Client, backupPolicyName, namespace string) (*dpv1alpha1.Backup, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(namespace),
+		client.MatchingLabels{
+			dptypes.BackupPolicyLabelKey: backupPolicyName,
+			dptypes.BackupTypeLabelKey:   string(dpv1alpha1.BackupTypeFull),
+		}); err != nil {
+		return nil, err
+	}
+	var latest *dpv1alpha1.Backup
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		if backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted || backup.IsDryRun() || backup.Status.CompletionTimestamp == nil {
+			continue
+		}
+		if latest == nil || latest.Status.CompletionTimestamp.Before(backup.Status.CompletionTimestamp) {
+			latest = backup
+		}
+	}
+	return latest, nil
+}
+
+// GetDependentBackupNames returns the names of the incremental backups which refer to the backup
+// as their parent backup, the backup can not be deleted until they are deleted.
+func GetDependentBackupNames(ctx context.Context, cli client.Client, backup *dpv1alpha1.Backup) ([]string, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{
+			dptypes.BackupPolicyLabelKey: backup.Spec.BackupPolicyName,
+			dptypes.BackupTypeLabelKey:   string(dpv1alpha1.BackupTypeIncremental),
+		}); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, v := range backupList.Items {
+		if GetParentBackupName(&v) == backup.Name {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetInProgressRestoreNames returns the names of the restores from the backup which are not finished,
+// the backup files can not be deleted until they are finished. The restores are found by the labels
+// of the source backup, the restores as the data source of volumes are not included. The deleting
+// restores are regarded as finished, even if they are not started yet.
+func GetInProgressRestoreNames(ctx context.Context, cli client.Client, backup *dpv1alpha1.Backup) ([]string, error) {
+	restoreList := &dpv1alpha1.RestoreList{}
+	if err := cli.List(ctx, restoreList, client.MatchingLabels{
+		dptypes.BackupNameLabelKey:      backup.Name,
+		dptypes.BackupNamespaceLabelKey: backup.Namespace,
+	}); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, v := range restoreList.Items {
+		if !v.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if v.Status.Phase == "" || v.Status.Phase == dpv1alpha1.RestorePhaseRunning {
+			names = append(names, v.Namespace+"/"+v.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
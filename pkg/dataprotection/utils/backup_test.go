@@ -0,0 +1,213 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestBuildRestorableTimeRanges(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) *metav1.Time {
+		return &metav1.Time{Time: base.Add(time.Duration(hour) * time.Hour)}
+	}
+	newBackup := func(backupType dpv1alpha1.BackupType, phase dpv1alpha1.BackupPhase, start, end int) dpv1alpha1.Backup {
+		return dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{dptypes.BackupTypeLabelKey: string(backupType)},
+			},
+			Status: dpv1alpha1.BackupStatus{
+				Phase:     phase,
+				TimeRange: &dpv1alpha1.BackupTimeRange{Start: at(start), End: at(end)},
+			},
+		}
+	}
+	full := func(start, end int) dpv1alpha1.Backup {
+		return newBackup(dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, start, end)
+	}
+	continuous := func(start, end int) dpv1alpha1.Backup {
+		return newBackup(dpv1alpha1.BackupTypeContinuous, dpv1alpha1.BackupPhaseRunning, start, end)
+	}
+	dryRun := func(start, end int) dpv1alpha1.Backup {
+		backup := full(start, end)
+		backup.Status.CompletionReason = dpv1alpha1.BackupCompletionReasonDryRun
+		return backup
+	}
+	timeRange := func(start, end int) dpv1alpha1.BackupTimeRange {
+		return dpv1alpha1.BackupTimeRange{Start: at(start), End: at(end)}
+	}
+
+	tests := []struct {
+		name     string
+		backups  []dpv1alpha1.Backup
+		expected []dpv1alpha1.BackupTimeRange
+	}{
+		{
+			name:     "no continuous backup",
+			backups:  []dpv1alpha1.Backup{full(0, 1)},
+			expected: nil,
+		},
+		{
+			name:     "full backup is not covered by the continuous backup",
+			backups:  []dpv1alpha1.Backup{full(0, 1), continuous(2, 10)},
+			expected: nil,
+		},
+		{
+			name:     "the earliest covered full backup is the start",
+			backups:  []dpv1alpha1.Backup{full(4, 5), full(1, 3), continuous(2, 10)},
+			expected: []dpv1alpha1.BackupTimeRange{timeRange(3, 10)},
+		},
+		{
+			name: "failed full backup is ignored",
+			backups: []dpv1alpha1.Backup{newBackup(dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseFailed, 2, 3),
+				full(4, 5), continuous(2, 10)},
+			expected: []dpv1alpha1.BackupTimeRange{timeRange(5, 10)},
+		},
+		{
+			name:     "dry-run full backup is ignored",
+			backups:  []dpv1alpha1.Backup{dryRun(2, 3), full(4, 5), continuous(2, 10)},
+			expected: []dpv1alpha1.BackupTimeRange{timeRange(5, 10)},
+		},
+		{
+			name:     "overlapping ranges are merged",
+			backups:  []dpv1alpha1.Backup{full(1, 2), continuous(0, 10), full(8, 9), continuous(8, 20)},
+			expected: []dpv1alpha1.BackupTimeRange{timeRange(2, 20)},
+		},
+		{
+			name:     "continuous backup restarted",
+			backups:  []dpv1alpha1.Backup{full(1, 2), continuous(0, 10), full(12, 13), continuous(12, 20)},
+			expected: []dpv1alpha1.BackupTimeRange{timeRange(2, 10), timeRange(13, 20)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildRestorableTimeRanges(tt.backups))
+		})
+	}
+}
+
+func TestGetLatestFullBackupAndDependentBackups(t *testing.T) {
+	const (
+		namespace  = "default"
+		policyName = "test-policy"
+	)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newBackup := func(name string, backupType dpv1alpha1.BackupType, phase dpv1alpha1.BackupPhase, hour int) *dpv1alpha1.Backup {
+		backup := &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					dptypes.BackupPolicyLabelKey: policyName,
+					dptypes.BackupTypeLabelKey:   string(backupType),
+				},
+			},
+			Spec:   dpv1alpha1.BackupSpec{BackupPolicyName: policyName},
+			Status: dpv1alpha1.BackupStatus{Phase: phase},
+		}
+		if phase == dpv1alpha1.BackupPhaseCompleted {
+			backup.Status.CompletionTimestamp = &metav1.Time{Time: base.Add(time.Duration(hour) * time.Hour)}
+		}
+		return backup
+	}
+
+	full1 := newBackup("full-1", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, 1)
+	full2 := newBackup("full-2", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, 2)
+	full3 := newBackup("full-3", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseFailed, 3)
+	incr1 := newBackup("incr-1", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseCompleted, 4)
+	incr1.Status.ParentBackupName = full2.Name
+	incr2 := newBackup("incr-2", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseNew, 0)
+	incr2.Spec.ParentBackupName = incr1.Name
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, dpv1alpha1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(full1, full2, full3, incr1, incr2).Build()
+	ctx := context.Background()
+
+	latest, err := GetLatestFullBackup(ctx, cli, policyName, namespace)
+	assert.NoError(t, err)
+	assert.NotNil(t, latest)
+	assert.Equal(t, full2.Name, latest.Name)
+
+	latest, err = GetLatestFullBackup(ctx, cli, "other-policy", namespace)
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+
+	for _, tt := range []struct {
+		backup     *dpv1alpha1.Backup
+		dependents []string
+	}{
+		{backup: full1},
+		{backup: full2, dependents: []string{incr1.Name}},
+		{backup: incr1, dependents: []string{incr2.Name}},
+	} {
+		names, err := GetDependentBackupNames(ctx, cli, tt.backup)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.dependents, names, tt.backup.Name)
+	}
+}
+
+func TestGetInProgressRestoreNames(t *testing.T) {
+	backup := &dpv1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "test-backup", Namespace: "default"}}
+	newRestore := func(namespace, name, backupName string, phase dpv1alpha1.RestorePhase) *dpv1alpha1.Restore {
+		return &dpv1alpha1.Restore{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					dptypes.BackupNameLabelKey:      backupName,
+					dptypes.BackupNamespaceLabelKey: backup.Namespace,
+				},
+			},
+			Status: dpv1alpha1.RestoreStatus{Phase: phase},
+		}
+	}
+
+	// the deleting restore is regarded as finished even if it is not started
+	deletingRestore := newRestore("default", "restore-deleting", backup.Name, "")
+	deletingRestore.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deletingRestore.Finalizers = []string{dptypes.DataProtectionFinalizerName}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, dpv1alpha1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newRestore("default", "restore-new", backup.Name, ""),
+		newRestore("other", "restore-running", backup.Name, dpv1alpha1.RestorePhaseRunning),
+		newRestore("default", "restore-completed", backup.Name, dpv1alpha1.RestorePhaseCompleted),
+		newRestore("default", "restore-failed", backup.Name, dpv1alpha1.RestorePhaseFailed),
+		newRestore("default", "restore-other-backup", "other-backup", dpv1alpha1.RestorePhaseRunning),
+		deletingRestore,
+	).Build()
+
+	names, err := GetInProgressRestoreNames(context.Background(), cli, backup)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/restore-new", "other/restore-running"}, names)
+}
@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package utils
 
 import (
+	"fmt"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -29,15 +30,17 @@ import (
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 )
 
-func BuildEnvByCredential(pod *corev1.Pod, credential *dpv1alpha1.ConnectionCredential) []corev1.EnvVar {
+// BuildEnvByCredential builds the connection envs of the pod, the port is read from the
+// container with the containerName if the credential has no port key.
+func BuildEnvByCredential(pod *corev1.Pod, containerName string, credential *dpv1alpha1.ConnectionCredential, namespace string) []corev1.EnvVar {
 	var envVars []corev1.EnvVar
 	if credential == nil {
-		envVars = append(envVars, corev1.EnvVar{Name: dptypes.DPDBHost, Value: intctrlutil.BuildPodHostDNS(pod)})
+		envVars = append(envVars, corev1.EnvVar{Name: dptypes.DPDBHost, Value: buildPodHostDNS(pod, namespace)})
 		return envVars
 	}
 	var hostEnv corev1.EnvVar
 	if credential.HostKey == "" {
-		hostEnv = corev1.EnvVar{Name: dptypes.DPDBHost, Value: intctrlutil.BuildPodHostDNS(pod)}
+		hostEnv = corev1.EnvVar{Name: dptypes.DPDBHost, Value: buildPodHostDNS(pod, namespace)}
 	} else {
 		hostEnv = buildEnvBySecretKey(dptypes.DPDBHost, credential.SecretName, credential.HostKey)
 	}
@@ -51,11 +54,21 @@ func BuildEnvByCredential(pod *corev1.Pod, credential *dpv1alpha1.ConnectionCred
 	if credential.PortKey != "" {
 		envVars = append(envVars, buildEnvBySecretKey(dptypes.DPDBPort, credential.SecretName, credential.PortKey))
 	} else {
-		envVars = append(envVars, corev1.EnvVar{Name: dptypes.DPDBPort, Value: strconv.Itoa(int(GetPodFirstContainerPort(pod)))})
+		envVars = append(envVars, corev1.EnvVar{Name: dptypes.DPDBPort, Value: strconv.Itoa(int(GetPodContainerPort(pod, containerName)))})
 	}
 	return envVars
 }
 
+// buildPodHostDNS builds the host DNS of the pod which is resolvable in the namespace,
+// the namespace of the pod is appended if it is in another namespace.
+func buildPodHostDNS(pod *corev1.Pod, namespace string) string {
+	hostDNS := intctrlutil.BuildPodHostDNS(pod)
+	if pod.Spec.Subdomain == "" || pod.Namespace == namespace {
+		return hostDNS
+	}
+	return fmt.Sprintf("%s.%s", hostDNS, pod.Namespace)
+}
+
 func buildEnvBySecretKey(name, secretName, key string) corev1.EnvVar {
 	return corev1.EnvVar{
 		Name: name,
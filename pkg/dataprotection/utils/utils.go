@@ -22,9 +22,12 @@ package utils
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"path"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/rogpeppe/go-internal/semver"
 	batchv1 "k8s.io/api/batch/v1"
@@ -115,6 +118,16 @@ func GetActionSetByName(reqCtx intctrlutil.RequestCtx, cli client.Client, name s
 	return as, nil
 }
 
+// ComputeActionSetHash computes the hash of the ActionSet spec, which changes when the ActionSet
+// is upgraded and is used to check whether a backup is taken with the current ActionSet.
+func ComputeActionSetHash(actionSet *dpv1alpha1.ActionSet) (string, error) {
+	data, err := json.Marshal(actionSet.Spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16], nil
+}
+
 func GetBackupPolicyByName(reqCtx intctrlutil.RequestCtx, cli client.Client, name string) (*dpv1alpha1.BackupPolicy, error) {
 	backupPolicy := &dpv1alpha1.BackupPolicy{}
 	key := client.ObjectKey{
@@ -263,6 +276,17 @@ func PrependSpaces(content string, spaces int) string {
 	return w.String()
 }
 
+// GetConnectionPasswordEncryptionAlgorithm gets the algorithm to encrypt the connection password
+// of the backup, it falls back to the default algorithm of the encryptor if the algorithm of the
+// encryption config is not supported by the encryptor or the encryption config is not set, which
+// keeps the backups created before compatible.
+func GetConnectionPasswordEncryptionAlgorithm(encryptionConfig *dpv1alpha1.EncryptionConfig) string {
+	if encryptionConfig != nil && intctrlutil.IsSupportedEncryptionAlgorithm(encryptionConfig.Algorithm) {
+		return encryptionConfig.Algorithm
+	}
+	return intctrlutil.EncryptionAlgorithmAES256GCM
+}
+
 // GetFirstIndexRunningPod gets the first running pod with index.
 func GetFirstIndexRunningPod(podList *corev1.PodList) *corev1.Pod {
 	if podList == nil {
@@ -300,9 +324,55 @@ func SupportsCronJobV1() bool {
 	return semver.Compare(kubeVersion, "v1.21") >= 0
 }
 func GetPodFirstContainerPort(pod *corev1.Pod) int32 {
-	ports := pod.Spec.Containers[0].Ports
-	if len(ports) == 0 {
+	return GetPodContainerPort(pod, "")
+}
+
+// GetPodContainerPort returns the first port of the container with the name, or of the
+// first container if the name is empty or the container is not found.
+func GetPodContainerPort(pod *corev1.Pod, containerName string) int32 {
+	if len(pod.Spec.Containers) == 0 {
+		return 0
+	}
+	container := &pod.Spec.Containers[0]
+	if containerName != "" {
+		if _, c := intctrlutil.GetContainerByName(pod.Spec.Containers, containerName); c != nil {
+			container = c
+		}
+	}
+	if len(container.Ports) == 0 {
 		return 0
 	}
-	return ports[0].ContainerPort
+	return container.Ports[0].ContainerPort
+}
+
+// ValidateVolumeSubPaths checks the sub paths of the target volumes are relative paths
+// inside the volumes.
+func ValidateVolumeSubPaths(subPaths []string) error {
+	for _, subPath := range subPaths {
+		if subPath == "" || path.IsAbs(subPath) {
+			return fmt.Errorf(`the volume sub path "%s" must be a non-empty relative path`, subPath)
+		}
+		for _, elem := range strings.Split(subPath, "/") {
+			if elem == ".." {
+				return fmt.Errorf(`the volume sub path "%s" must not contain '..'`, subPath)
+			}
+		}
+	}
+	return nil
+}
+
+// BuildVolumeSubPathMounts builds the volume mounts of the sub paths of the volume mount,
+// the sub paths are mounted at the same paths relative to the mount path of the volume.
+func BuildVolumeSubPathMounts(volumeMount corev1.VolumeMount, subPaths []string) []corev1.VolumeMount {
+	if len(subPaths) == 0 {
+		return []corev1.VolumeMount{volumeMount}
+	}
+	var mounts []corev1.VolumeMount
+	for _, subPath := range subPaths {
+		subPathMount := volumeMount
+		subPathMount.MountPath = path.Join(volumeMount.MountPath, subPath)
+		subPathMount.SubPath = path.Join(volumeMount.SubPath, subPath)
+		mounts = append(mounts, subPathMount)
+	}
+	return mounts
 }
@@ -24,9 +24,12 @@ import (
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/version"
 
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 )
 
 func TestGetKubeVersion(t *testing.T) {
@@ -81,3 +84,115 @@ func TestGetKubeVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConnectionPasswordEncryptionAlgorithm(t *testing.T) {
+	tests := []struct {
+		name             string
+		encryptionConfig *dpv1alpha1.EncryptionConfig
+		expected         string
+	}{
+		{
+			name:             "no encryption config",
+			encryptionConfig: nil,
+			expected:         "AES-256-GCM",
+		},
+		{
+			name:             "algorithm not supported by the encryptor",
+			encryptionConfig: &dpv1alpha1.EncryptionConfig{Algorithm: "AES-128-CFB"},
+			expected:         "AES-256-GCM",
+		},
+		{
+			name:             "ChaCha20-Poly1305",
+			encryptionConfig: &dpv1alpha1.EncryptionConfig{Algorithm: "ChaCha20-Poly1305"},
+			expected:         "ChaCha20-Poly1305",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetConnectionPasswordEncryptionAlgorithm(tt.encryptionConfig))
+		})
+	}
+}
+
+func TestBuildEnvByCredentialHostDNS(t *testing.T) {
+	pod := &corev1.Pod{}
+	pod.Namespace = "tenant"
+	pod.Name = "mysql-0"
+	pod.Spec.Subdomain = "mysql-headless"
+
+	getHost := func(namespace string) string {
+		for _, env := range BuildEnvByCredential(pod, "", nil, namespace) {
+			if env.Name == dptypes.DPDBHost {
+				return env.Value
+			}
+		}
+		return ""
+	}
+	assert.Equal(t, "mysql-0.mysql-headless", getHost("tenant"))
+	assert.Equal(t, "mysql-0.mysql-headless.tenant", getHost("dp-system"))
+}
+
+func TestComputeActionSetHash(t *testing.T) {
+	actionSet := &dpv1alpha1.ActionSet{
+		Spec: dpv1alpha1.ActionSetSpec{
+			BackupType: dpv1alpha1.BackupTypeFull,
+			Restore: &dpv1alpha1.RestoreActionSpec{
+				PrepareData: &dpv1alpha1.JobActionSpec{
+					BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+						Image:   "restore-image:1.0",
+						Command: []string{"sh", "-c", "restore"},
+					},
+				},
+			},
+		},
+	}
+	hash, err := ComputeActionSetHash(actionSet)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hash)
+
+	// the metadata does not affect the hash
+	actionSet.Generation = 2
+	actionSet.Labels = map[string]string{"test": "test"}
+	sameHash, err := ComputeActionSetHash(actionSet)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	actionSet.Spec.Restore.PrepareData.Command = []string{"sh", "-c", "restore --new-flag"}
+	changedHash, err := ComputeActionSetHash(actionSet)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestGetPodContainerPort(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "mysql", Ports: []corev1.ContainerPort{{ContainerPort: 3306}}},
+				{Name: "proxy", Ports: []corev1.ContainerPort{{ContainerPort: 6033}}},
+			},
+		},
+	}
+	assert.Equal(t, int32(3306), GetPodContainerPort(pod, ""))
+	assert.Equal(t, int32(6033), GetPodContainerPort(pod, "proxy"))
+	// fall back to the first container if the container is not found.
+	assert.Equal(t, int32(3306), GetPodContainerPort(pod, "not-exist"))
+	assert.Equal(t, int32(0), GetPodContainerPort(&corev1.Pod{}, "proxy"))
+}
+
+func TestValidateVolumeSubPaths(t *testing.T) {
+	assert.NoError(t, ValidateVolumeSubPaths(nil))
+	assert.NoError(t, ValidateVolumeSubPaths([]string{"mysql", "binlog/2024", "a..b"}))
+	for _, subPath := range []string{"", "/data", "..", "../etc", "mysql/../../etc", "mysql/.."} {
+		assert.Error(t, ValidateVolumeSubPaths([]string{"mysql", subPath}), subPath)
+	}
+}
+
+func TestBuildVolumeSubPathMounts(t *testing.T) {
+	mount := corev1.VolumeMount{Name: "data", MountPath: "/data", SubPath: "mysql"}
+	assert.Equal(t, []corev1.VolumeMount{mount}, BuildVolumeSubPathMounts(mount, nil))
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data/db", SubPath: "mysql/db"},
+		{Name: "data", MountPath: "/data/binlog", SubPath: "mysql/binlog"},
+	}, BuildVolumeSubPathMounts(mount, []string{"db", "binlog"}))
+}
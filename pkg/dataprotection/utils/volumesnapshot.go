@@ -58,3 +58,30 @@ func IsVolumeSnapshotEnabled(ctx context.Context, cli client.Client, pvName stri
 	}
 	return false, nil
 }
+
+// GetVolumeSnapshotClass gets the VolumeSnapshotClass by name, it returns nil if the class is not found.
+func GetVolumeSnapshotClass(ctx context.Context, cli client.Client, name string) (*vsv1.VolumeSnapshotClass, error) {
+	vsc := &vsv1.VolumeSnapshotClass{}
+	if err := NewCompatClient(cli).Get(ctx, client.ObjectKey{Name: name}, vsc); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return vsc, nil
+}
+
+// GetPVCProvisioner gets the provisioner of the persistent volume claim, which is the CSI driver of the
+// bound volume, or the storage provisioner annotated to the claim if the volume is not provisioned by CSI.
+func GetPVCProvisioner(ctx context.Context, cli client.Client, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if len(pvc.Spec.VolumeName) > 0 {
+		pv := &corev1.PersistentVolume{}
+		if err := cli.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+			return "", err
+		}
+		if pv.Spec.CSI != nil {
+			return pv.Spec.CSI.Driver, nil
+		}
+	}
+	if provisioner := pvc.Annotations["volume.kubernetes.io/storage-provisioner"]; provisioner != "" {
+		return provisioner, nil
+	}
+	return pvc.Annotations["volume.beta.kubernetes.io/storage-provisioner"], nil
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -51,6 +52,7 @@ type KubernetesStore struct {
 	clusterName        string
 	componentName      string
 	clusterCompName    string
+	headlessSvcName    string
 	currentMemberName  string
 	namespace          string
 	cluster            *Cluster
@@ -89,6 +91,12 @@ func NewKubernetesStore() (*KubernetesStore, error) {
 		clusterCompName = clusterName + "-" + componentName
 	}
 
+	// the headless service name may be customized, take it from the pod FQDN: {pod}.{headless service}.{namespace}.svc
+	var headlessSvcName string
+	if fqdn := strings.Split(os.Getenv(constant.KBEnvPodFQDN), "."); len(fqdn) > 1 {
+		headlessSvcName = fqdn[1]
+	}
+
 	currentMemberName := os.Getenv(constant.KBEnvPodName)
 	if clusterName == "" {
 		return nil, errors.New(fmt.Sprintf("%s must be set", constant.KBEnvPodName))
@@ -104,6 +112,7 @@ func NewKubernetesStore() (*KubernetesStore, error) {
 		clusterName:       clusterName,
 		componentName:     componentName,
 		clusterCompName:   clusterCompName,
+		headlessSvcName:   headlessSvcName,
 		currentMemberName: currentMemberName,
 		namespace:         namespace,
 		client:            client,
@@ -137,6 +146,10 @@ func (store *KubernetesStore) GetClusterName() string {
 }
 
 func (store *KubernetesStore) SetCompName(componentName string) {
+	// the headless service taken from the pod FQDN belongs to the original component.
+	if componentName != store.componentName {
+		store.headlessSvcName = ""
+	}
 	store.componentName = componentName
 	store.clusterCompName = store.clusterName + "-" + componentName
 }
@@ -198,6 +211,7 @@ func (store *KubernetesStore) GetCluster() (*Cluster, error) {
 
 	cluster := &Cluster{
 		ClusterCompName: store.clusterCompName,
+		HeadlessSvcName: store.headlessSvcName,
 		Namespace:       store.namespace,
 		Replicas:        replicas,
 		Members:         members,
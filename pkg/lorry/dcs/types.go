@@ -29,6 +29,9 @@ import (
 
 type Cluster struct {
 	ClusterCompName string
+	// HeadlessSvcName is the name of the headless service which governs the pods, it defaults to
+	// {ClusterCompName}-headless if it's empty.
+	HeadlessSvcName string
 	Namespace       string
 	Replicas        int32
 	HaConfig        *HaConfig
@@ -99,11 +102,18 @@ func (c *Cluster) GetMemberAddr(member Member) string {
 		return member.PodIP
 	}
 	clusterDomain := viper.GetString(constant.KubernetesClusterDomainEnv)
-	return fmt.Sprintf("%s.%s-headless.%s.svc.%s", member.Name, c.ClusterCompName, c.Namespace, clusterDomain)
+	return fmt.Sprintf("%s.%s.%s.svc.%s", member.Name, c.GetHeadlessSvcName(), c.Namespace, clusterDomain)
 }
 
 func (c *Cluster) GetMemberShortAddr(member Member) string {
-	return fmt.Sprintf("%s.%s-headless", member.Name, c.ClusterCompName)
+	return fmt.Sprintf("%s.%s", member.Name, c.GetHeadlessSvcName())
+}
+
+func (c *Cluster) GetHeadlessSvcName() string {
+	if c.HeadlessSvcName != "" {
+		return c.HeadlessSvcName
+	}
+	return c.ClusterCompName + "-headless"
 }
 
 func (c *Cluster) GetMemberAddrs() []string {
@@ -21,8 +21,13 @@ package replica
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -31,6 +36,7 @@ import (
 	"github.com/spf13/viper"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/lorry/dcs"
 	"github.com/apecloud/kubeblocks/pkg/lorry/engines/register"
@@ -52,6 +58,8 @@ type CheckRole struct {
 	ProbeTimeout               time.Duration
 	DBRoles                    map[string]AccessMode
 	Command                    []string
+	HTTPAction                 *appsv1alpha1.HTTPAction
+	httpClient                 *http.Client
 }
 
 var checkrole operations.Operation = &CheckRole{}
@@ -103,6 +111,22 @@ func (s *CheckRole) Init(ctx context.Context) error {
 			s.Command = roleProbeCmd
 		}
 	}
+	httpActionJSON := viper.GetString(constant.KBEnvRoleProbeHTTPAction)
+	if httpActionJSON != "" {
+		httpAction := &appsv1alpha1.HTTPAction{}
+		if err := json.Unmarshal([]byte(httpActionJSON), httpAction); err != nil {
+			s.logger.Info("get role probe http action failed", "error", err)
+		} else {
+			s.HTTPAction = httpAction
+			// the client is shared by the role probes to reuse the connections to the endpoint.
+			// As the kubelet does, the certificate is not verified for the HTTPS scheme.
+			s.httpClient = &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+				},
+			}
+		}
+	}
 	return nil
 
 }
@@ -120,7 +144,12 @@ func (s *CheckRole) Do(ctx context.Context, req *operations.OpsRequest) (*operat
 	var role string
 	var err error
 
-	if len(s.Command) == 0 {
+	switch {
+	case len(s.Command) > 0:
+		role, err = util.ExecCommand(s.Command)
+	case s.HTTPAction != nil:
+		role, err = s.getRoleByHTTP(ctx)
+	default:
 		manager, err1 := register.GetDBManager()
 		if err1 != nil {
 			return nil, errors.Wrap(err1, "get manager failed")
@@ -136,8 +165,6 @@ func (s *CheckRole) Do(ctx context.Context, req *operations.OpsRequest) (*operat
 		ctx1, cancel := context.WithTimeout(ctx, s.ProbeTimeout)
 		defer cancel()
 		role, err = manager.GetReplicaRole(ctx1, cluster)
-	} else {
-		role, err = util.ExecCommand(s.Command)
 	}
 
 	if err != nil {
@@ -169,6 +196,53 @@ func (s *CheckRole) Do(ctx context.Context, req *operations.OpsRequest) (*operat
 	return resp, err
 }
 
+// getRoleByHTTP performs the HTTP request of the role probe, and takes the response body as the role.
+func (s *CheckRole) getRoleByHTTP(ctx context.Context) (string, error) {
+	scheme := strings.ToLower(string(s.HTTPAction.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	host := s.HTTPAction.Host
+	if host == "" {
+		host = viper.GetString(constant.KBEnvPodIP)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	method := s.HTTPAction.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(host, s.HTTPAction.Port.String()),
+		Path:   s.HTTPAction.Path,
+	}
+
+	ctx1, cancel := context.WithTimeout(ctx, s.ProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx1, method, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	for _, header := range s.HTTPAction.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return "", errors.Errorf("role probe request %s failed with status code %d", u.String(), resp.StatusCode)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
 // Component may have some internal roles that needn't be exposed to end user,
 // and not configured in cluster definition, e.g. ETCD's Candidate.
 // roleValidate is used to filter the internal roles and decrease the number
@@ -34,6 +34,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -53,9 +54,6 @@ const (
 
 	certFile  = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
 	tokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
-
-	reasonLock   = "HighVolumeWatermark"
-	reasonUnlock = "LowVolumeWatermark" // TODO
 )
 
 type volumeStatsRequester interface {
@@ -75,6 +73,7 @@ func init() {
 type volumeExt struct {
 	Name          string
 	HighWatermark int
+	LowWatermark  int
 	Stats         statsv1alpha1.VolumeStats
 }
 
@@ -84,6 +83,7 @@ type Protection struct {
 	Requester     volumeStatsRequester
 	Pod           string
 	HighWatermark int
+	LowWatermark  int
 	Volumes       map[string]volumeExt
 	Readonly      bool
 	SendEvent     bool // to disable event for testing
@@ -160,14 +160,17 @@ func (p *Protection) initVolumes() error {
 	}
 
 	p.HighWatermark = normalizeVolumeWatermark(&spec.HighWatermark, 0)
+	p.LowWatermark = normalizeVolumeWatermark(&spec.LowWatermark, 0)
 
 	if p.Volumes == nil {
 		p.Volumes = make(map[string]volumeExt)
 	}
 	for _, v := range spec.Volumes {
+		highWatermark := normalizeVolumeWatermark(v.HighWatermark, p.HighWatermark)
 		p.Volumes[v.Name] = volumeExt{
 			Name:          v.Name,
-			HighWatermark: normalizeVolumeWatermark(v.HighWatermark, p.HighWatermark),
+			HighWatermark: highWatermark,
+			LowWatermark:  normalizeLowWatermark(normalizeVolumeWatermark(v.LowWatermark, p.LowWatermark), highWatermark),
 			Stats: statsv1alpha1.VolumeStats{
 				Name: v.Name,
 			},
@@ -216,12 +219,12 @@ func (p *Protection) checkUsage(ctx context.Context) (map[string]any, error) {
 	lower := make([]string, 0)
 	higher := make([]string, 0)
 	for name, v := range p.Volumes {
-		ret := p.checkVolumeWatermark(v)
-		if ret == 0 {
-			lower = append(lower, name)
-		} else {
+		if p.checkVolumeWatermark(v, v.HighWatermark) != 0 {
 			higher = append(higher, name)
 		}
+		if p.checkVolumeWatermark(v, v.LowWatermark) == 0 {
+			lower = append(lower, name)
+		}
 	}
 
 	volumeUsages := p.buildVolumesMsg()
@@ -232,7 +235,7 @@ func (p *Protection) checkUsage(ctx context.Context) (map[string]any, error) {
 			return volumeUsages, err
 		}
 	}
-	// the instance is protected in RO mode, and all volumes' space usage are under the threshold.
+	// the instance is protected in RO mode, and all volumes' space usage are under the low threshold.
 	if readonly && len(lower) == len(p.Volumes) {
 		if err := p.lowWatermark(ctx, volumeUsages); err != nil {
 			return volumeUsages, err
@@ -241,18 +244,18 @@ func (p *Protection) checkUsage(ctx context.Context) (map[string]any, error) {
 	return volumeUsages, nil
 }
 
-// checkVolumeWatermark checks whether the volume's space usage is over the threshold.
+// checkVolumeWatermark checks whether the volume's space usage is over the given watermark.
 //
-//	returns 0 if the volume will not be taken in account or its space usage is under the threshold
-//	returns non-zero if the volume space usage is over the threshold
-func (p *Protection) checkVolumeWatermark(v volumeExt) int {
+//	returns 0 if the volume will not be taken in account or its space usage is under the watermark
+//	returns non-zero if the volume space usage is over the watermark
+func (p *Protection) checkVolumeWatermark(v volumeExt, watermark int) int {
 	if v.HighWatermark == 0 { // disabled
 		return 0
 	}
 	if v.Stats.CapacityBytes == nil || v.Stats.UsedBytes == nil {
 		return 0
 	}
-	thresholdBytes := *v.Stats.CapacityBytes / 100 * uint64(v.HighWatermark)
+	thresholdBytes := *v.Stats.CapacityBytes / 100 * uint64(watermark)
 	if *v.Stats.UsedBytes < thresholdBytes {
 		return 0
 	}
@@ -271,7 +274,7 @@ func (p *Protection) highWatermark(ctx context.Context, volumeUsages map[string]
 	p.Logger.Info("set instance to read-only OK", "msg", volumeUsages)
 	p.Readonly = true
 
-	if err := p.sendEvent(ctx, reasonLock, volumeUsages); err != nil {
+	if err := p.sendEvent(ctx, util.VolumeProtectionLockEventReason, corev1.EventTypeWarning, volumeUsages); err != nil {
 		p.Logger.Error(err, "send volume protection (lock) event error", "volumes", volumeUsages)
 		return err
 	}
@@ -290,7 +293,7 @@ func (p *Protection) lowWatermark(ctx context.Context, volumeUsages map[string]a
 	p.Logger.Info("reset instance to read-write OK", "msg", volumeUsages)
 	p.Readonly = false
 
-	if err := p.sendEvent(ctx, reasonUnlock, volumeUsages); err != nil {
+	if err := p.sendEvent(ctx, util.VolumeProtectionUnlockEventReason, corev1.EventTypeNormal, volumeUsages); err != nil {
 		p.Logger.Error(err, "send volume protection (unlock) event error", "volumes", volumeUsages)
 		return err
 	}
@@ -312,6 +315,9 @@ func (p *Protection) buildVolumesMsg() map[string]any {
 		if v.HighWatermark != p.HighWatermark {
 			usage["highWatermark"] = fmt.Sprintf("%d", v.HighWatermark)
 		}
+		if v.LowWatermark != v.HighWatermark {
+			usage["lowWatermark"] = fmt.Sprintf("%d", v.LowWatermark)
+		}
 		stats := v.Stats
 		if stats.UsedBytes == nil || stats.CapacityBytes == nil {
 			usage[v.Name] = "<nil>"
@@ -324,15 +330,19 @@ func (p *Protection) buildVolumesMsg() map[string]any {
 		"highWatermark": fmt.Sprintf("%d", p.HighWatermark),
 		"volumes":       volumes,
 	}
+	if p.LowWatermark != 0 {
+		usages["lowWatermark"] = fmt.Sprintf("%d", p.LowWatermark)
+	}
 	return usages
 }
 
-func (p *Protection) sendEvent(ctx context.Context, reason string, volumeUsages map[string]any) error {
+func (p *Protection) sendEvent(ctx context.Context, reason, eventType string, volumeUsages map[string]any) error {
 	if p.SendEvent {
 		event, err := util.CreateEvent(reason, volumeUsages)
 		if err != nil {
 			return errors.Wrap(err, "create volume protection event failed")
 		}
+		event.Type = eventType
 		return util.SendEvent(ctx, event)
 	}
 	return nil
@@ -455,3 +465,12 @@ func normalizeVolumeWatermark(watermark *int, defaultVal int) int {
 	}
 	return *watermark
 }
+
+// normalizeLowWatermark takes the high watermark as the low watermark of the volume if it is not specified
+// or exceeds the high one, which keeps the single-threshold behavior.
+func normalizeLowWatermark(lowWatermark, highWatermark int) int {
+	if lowWatermark == 0 || lowWatermark > highWatermark {
+		return highWatermark
+	}
+	return lowWatermark
+}
@@ -196,6 +196,46 @@ var _ = Describe("Volume Protection Operation", func() {
 			}
 		})
 
+		It("init - normalize low watermark", func() {
+			lowThreshold := defaultThreshold - 10
+			higherThreshold := defaultThreshold + 5
+			spec := appsv1alpha1.VolumeProtectionSpec{
+				HighWatermark: defaultThreshold,
+				Volumes: []appsv1alpha1.ProtectedVolume{
+					{
+						Name: "01",
+					},
+					{
+						Name:         "02",
+						LowWatermark: &lowThreshold,
+					},
+					{
+						Name:         "03",
+						LowWatermark: &higherThreshold,
+					},
+				},
+			}
+
+			By("fallback to the high watermark if low watermark is not set")
+			resetVolumeProtectionSpecEnv(spec)
+			obj := newProtection()
+			Expect(obj.initVolumes()).Should(Succeed())
+			Expect(obj.LowWatermark).Should(Equal(0))
+			Expect(obj.Volumes["01"].LowWatermark).Should(Equal(defaultThreshold))
+			Expect(obj.Volumes["02"].LowWatermark).Should(Equal(lowThreshold))
+			Expect(obj.Volumes["03"].LowWatermark).Should(Equal(defaultThreshold))
+
+			By("inherit the component level low watermark")
+			spec.LowWatermark = lowThreshold + 5
+			resetVolumeProtectionSpecEnv(spec)
+			obj = newProtection()
+			Expect(obj.initVolumes()).Should(Succeed())
+			Expect(obj.LowWatermark).Should(Equal(spec.LowWatermark))
+			Expect(obj.Volumes["01"].LowWatermark).Should(Equal(spec.LowWatermark))
+			Expect(obj.Volumes["02"].LowWatermark).Should(Equal(lowThreshold))
+			Expect(obj.Volumes["03"].LowWatermark).Should(Equal(defaultThreshold))
+		})
+
 		It("disabled - empty pod name", func() {
 			viper.SetDefault(constant.KBEnvPodName, "")
 			obj := newProtection()
@@ -380,6 +420,60 @@ var _ = Describe("Volume Protection Operation", func() {
 			Expect(obj.Readonly).Should(BeFalse())
 		})
 
+		It("volume between low and high watermark", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			mockDBManager := engines.NewMockDBManager(ctrl)
+			mockDBManager.EXPECT().Lock(gomock.Any(), gomock.Any()).Return(nil)
+			mockDBManager.EXPECT().Unlock(gomock.Any()).Return(nil)
+			register.SetDBManager(mockDBManager)
+
+			lowThreshold := defaultThreshold - 5
+			usedBytesUnderLowThreshold := capacityBytes * uint64(lowThreshold-1) / 100
+			spec := *volumeProtectionSpec.DeepCopy()
+			spec.LowWatermark = lowThreshold
+			resetVolumeProtectionSpecEnv(spec)
+			defer resetVolumeProtectionSpecEnv(*volumeProtectionSpec)
+
+			obj := newProtection()
+			mock := obj.Requester.(*mockVolumeStatsRequester)
+			stats := statsv1alpha1.Summary{
+				Pods: []statsv1alpha1.PodStats{
+					{
+						PodRef: statsv1alpha1.PodReference{
+							Name: podName,
+						},
+						VolumeStats: []statsv1alpha1.VolumeStats{
+							{
+								Name: volumeName,
+								FsStats: statsv1alpha1.FsStats{
+									CapacityBytes: &capacityBytes,
+									UsedBytes:     &usedBytesOverThreshold,
+								},
+							},
+						},
+					},
+				},
+			}
+			mock.summary, _ = json.Marshal(stats)
+			_, err := obj.Do(context.Background(), nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(obj.Readonly).Should(BeTrue())
+
+			// drops down the usage under the high watermark, but still over the low watermark, no unlock action triggered
+			stats.Pods[0].VolumeStats[0].UsedBytes = &usedBytesUnderThreshold
+			mock.summary, _ = json.Marshal(stats)
+			_, err = obj.Do(context.Background(), nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(obj.Readonly).Should(BeTrue())
+
+			// drops down the usage under the low watermark, and trigger unlock action
+			stats.Pods[0].VolumeStats[0].UsedBytes = &usedBytesUnderLowThreshold
+			mock.summary, _ = json.Marshal(stats)
+			_, err = obj.Do(context.Background(), nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(obj.Readonly).Should(BeFalse())
+		})
+
 		It("lock/unlock error", func() {
 			ctrl := gomock.NewController(GinkgoT())
 			mockDBManager := engines.NewMockDBManager(ctrl)
@@ -114,6 +114,15 @@ const (
 	InvalidRole    RoleType = "invalid"
 )
 
+const (
+	// VolumeProtectionLockEventReason is the reason of the event sent when the instance is locked to read-only
+	// as some volumes have exceeded the high watermark.
+	VolumeProtectionLockEventReason = "HighVolumeWatermark"
+	// VolumeProtectionUnlockEventReason is the reason of the event sent when the instance is unlocked
+	// as all volumes are under the low watermark.
+	VolumeProtectionUnlockEventReason = "LowVolumeWatermark"
+)
+
 // ProbeError is the error for Lorry probe api, it implements error interface
 type ProbeError struct {
 	message string
@@ -92,7 +92,7 @@ var (
 			RoleProbe: &appsv1alpha1.ClusterDefinitionProbe{
 				FailureThreshold: 3,
 				PeriodSeconds:    1,
-				TimeoutSeconds:   5,
+				TimeoutSeconds:   1,
 			},
 		},
 		VolumeProtectionSpec: &appsv1alpha1.VolumeProtectionSpec{},
@@ -176,7 +176,7 @@ var (
 			RoleProbe: &appsv1alpha1.ClusterDefinitionProbe{
 				FailureThreshold: 3,
 				PeriodSeconds:    1,
-				TimeoutSeconds:   5,
+				TimeoutSeconds:   1,
 			},
 		},
 		VolumeProtectionSpec: &appsv1alpha1.VolumeProtectionSpec{},
@@ -220,7 +220,7 @@ var (
 			RoleProbe: &appsv1alpha1.ClusterDefinitionProbe{
 				FailureThreshold: 3,
 				PeriodSeconds:    1,
-				TimeoutSeconds:   5,
+				TimeoutSeconds:   1,
 			},
 		},
 		VolumeProtectionSpec: &appsv1alpha1.VolumeProtectionSpec{},
@@ -440,7 +440,7 @@ var (
 			RoleProbe: &appsv1alpha1.ClusterDefinitionProbe{
 				FailureThreshold: 3,
 				PeriodSeconds:    1,
-				TimeoutSeconds:   5,
+				TimeoutSeconds:   1,
 			},
 		},
 		VolumeProtectionSpec: &appsv1alpha1.VolumeProtectionSpec{},
@@ -0,0 +1,124 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+type MockActionSetFactory struct {
+	testapps.BaseFactory[dpv1alpha1.ActionSet, *dpv1alpha1.ActionSet, MockActionSetFactory]
+}
+
+func NewActionSetFactory(name string) *MockActionSetFactory {
+	f := &MockActionSetFactory{}
+	f.Init("", name,
+		&dpv1alpha1.ActionSet{
+			Spec: dpv1alpha1.ActionSetSpec{
+				BackupType: dpv1alpha1.BackupTypeFull,
+			},
+		}, f)
+	return f
+}
+
+func (f *MockActionSetFactory) SetBackupType(backupType dpv1alpha1.BackupType) *MockActionSetFactory {
+	f.Get().Spec.BackupType = backupType
+	return f
+}
+
+func (f *MockActionSetFactory) AddEnv(name, value string) *MockActionSetFactory {
+	f.Get().Spec.Env = append(f.Get().Spec.Env, corev1.EnvVar{Name: name, Value: value})
+	return f
+}
+
+func (f *MockActionSetFactory) getBackupActionSpec() *dpv1alpha1.BackupActionSpec {
+	if f.Get().Spec.Backup == nil {
+		f.Get().Spec.Backup = &dpv1alpha1.BackupActionSpec{}
+	}
+	return f.Get().Spec.Backup
+}
+
+func (f *MockActionSetFactory) SetBackupData(image string, runOnTargetPodNode bool, command ...string) *MockActionSetFactory {
+	f.getBackupActionSpec().BackupData = &dpv1alpha1.BackupDataActionSpec{
+		JobActionSpec: dpv1alpha1.JobActionSpec{
+			BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+				Image:   image,
+				Command: command,
+			},
+			RunOnTargetPodNode: &runOnTargetPodNode,
+		},
+	}
+	return f
+}
+
+// SetSyncProgress sets the sync progress of the backup data action, it should be called after SetBackupData.
+func (f *MockActionSetFactory) SetSyncProgress(enabled bool, intervalSeconds int32) *MockActionSetFactory {
+	f.getBackupActionSpec().BackupData.SyncProgress = &dpv1alpha1.SyncProgress{
+		Enabled:         &enabled,
+		IntervalSeconds: &intervalSeconds,
+	}
+	return f
+}
+
+func (f *MockActionSetFactory) AddPreBackupExec(container string, command ...string) *MockActionSetFactory {
+	backup := f.getBackupActionSpec()
+	backup.PreBackup = append(backup.PreBackup, dpv1alpha1.ActionSpec{
+		Exec: &dpv1alpha1.ExecActionSpec{
+			Container: container,
+			Command:   command,
+		},
+	})
+	return f
+}
+
+func (f *MockActionSetFactory) AddPostBackupExec(container string, command ...string) *MockActionSetFactory {
+	backup := f.getBackupActionSpec()
+	backup.PostBackup = append(backup.PostBackup, dpv1alpha1.ActionSpec{
+		Exec: &dpv1alpha1.ExecActionSpec{
+			Container: container,
+			Command:   command,
+		},
+	})
+	return f
+}
+
+func (f *MockActionSetFactory) SetTrimBackup(image string, command ...string) *MockActionSetFactory {
+	f.getBackupActionSpec().TrimBackup = &dpv1alpha1.BaseJobActionSpec{
+		Image:   image,
+		Command: command,
+	}
+	return f
+}
+
+func (f *MockActionSetFactory) SetRestorePrepareData(image string, command ...string) *MockActionSetFactory {
+	if f.Get().Spec.Restore == nil {
+		f.Get().Spec.Restore = &dpv1alpha1.RestoreActionSpec{}
+	}
+	f.Get().Spec.Restore.PrepareData = &dpv1alpha1.JobActionSpec{
+		BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+			Image:   image,
+			Command: command,
+		},
+	}
+	return f
+}
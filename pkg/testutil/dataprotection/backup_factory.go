@@ -66,3 +66,26 @@ func (f *MockBackupFactory) SetBackupTimeRange(startTime, stopTime time.Time) *M
 	f.Get().Status.TimeRange = tr
 	return f
 }
+
+func (f *MockBackupFactory) SetEncryptionConfig(encryptionConfig *dpv1alpha1.EncryptionConfig) *MockBackupFactory {
+	f.Get().Spec.EncryptionConfig = encryptionConfig
+	return f
+}
+
+// SetPhase sets the phase of the backup status. The status is dropped when the backup is created
+// in the API server, it takes effect for the backups used by the fake clients or the unit tests.
+func (f *MockBackupFactory) SetPhase(phase dpv1alpha1.BackupPhase) *MockBackupFactory {
+	f.Get().Status.Phase = phase
+	return f
+}
+
+// SetCompleted sets the status of the backup to be completed, see MockBackupCompletedStatus.
+func (f *MockBackupFactory) SetCompleted(backupMethodName, actionSetName, backupPVCName string) *MockBackupFactory {
+	MockBackupCompletedStatus(f.Get(), backupMethodName, actionSetName, backupPVCName)
+	return f
+}
+
+func (f *MockBackupFactory) SetStatusEncryptionConfig(encryptionConfig *dpv1alpha1.EncryptionConfig) *MockBackupFactory {
+	f.Get().Status.EncryptionConfig = encryptionConfig
+	return f
+}
@@ -270,3 +270,41 @@ func MockBackupStatusMethod(backup *dpv1alpha1.Backup, backupMethodName, targetV
 		},
 	}
 }
+
+// NewFakeEncryptionConfig builds the encryption config whose pass phrase is referenced by the key of the secret.
+func NewFakeEncryptionConfig(secretName, algorithm string) *dpv1alpha1.EncryptionConfig {
+	return &dpv1alpha1.EncryptionConfig{
+		Algorithm: algorithm,
+		PassPhraseSecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: secretName,
+			},
+			Key: EncryptionKeyName,
+		},
+	}
+}
+
+// MockBackupCompletedStatus sets the status of the backup to be completed. The backup repo and the backup path
+// are wired consistently with the fake backup policy, and the encryption config is inherited from the spec
+// if it is not set in the status, so that the backup can be consumed by the restore tests.
+func MockBackupCompletedStatus(backup *dpv1alpha1.Backup, backupMethodName, actionSetName, backupPVCName string) {
+	backup.Status.Phase = dpv1alpha1.BackupPhaseCompleted
+	backup.Status.BackupRepoName = BackupRepoName
+	backup.Status.PersistentVolumeClaimName = backupPVCName
+	backup.Status.Path = fmt.Sprintf("/%s%s/%s", backup.Namespace, BackupPathPrefix, backup.Name)
+	if backup.Status.EncryptionConfig == nil {
+		backup.Status.EncryptionConfig = backup.Spec.EncryptionConfig
+	}
+	MockBackupStatusMethod(backup, backupMethodName, DataVolumeName, actionSetName)
+}
+
+// MockBackupCompleted mocks the backup in the API server to be completed, see MockBackupCompletedStatus.
+func MockBackupCompleted(testCtx *testutil.TestContext, backup *dpv1alpha1.Backup,
+	backupMethodName, actionSetName, backupPVCName string, change func(backup *dpv1alpha1.Backup)) {
+	Expect(testapps.ChangeObjStatus(testCtx, backup, func() {
+		MockBackupCompletedStatus(backup, backupMethodName, actionSetName, backupPVCName)
+		if change != nil {
+			change(backup)
+		}
+	})).Should(Succeed())
+}
@@ -20,6 +20,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package dataprotection
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -104,3 +106,13 @@ func (f *MockBackupPolicyFactory) SetTargetConnectionCredential(secretName strin
 	}
 	return f
 }
+
+func (f *MockBackupPolicyFactory) SetEncryptionConfig(encryptionConfig *dpv1alpha1.EncryptionConfig) *MockBackupPolicyFactory {
+	f.Get().Spec.EncryptionConfig = encryptionConfig
+	return f
+}
+
+func (f *MockBackupPolicyFactory) SetReplicationLagThreshold(threshold time.Duration) *MockBackupPolicyFactory {
+	f.Get().Spec.ReplicationLagThreshold = &metav1.Duration{Duration: threshold}
+	return f
+}
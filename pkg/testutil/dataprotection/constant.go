@@ -46,6 +46,8 @@ const (
 	BackupRetention         = "7d"
 	StartingDeadlineMinutes = 10
 
+	EncryptionKeyName = "password"
+
 	KBToolImage   = "apecloud/kubeblocks-tool:latest"
 	BackupPVCName = "test-backup-pvc"
 	ImageTag      = "latest"
@@ -51,6 +51,10 @@ func GetInt32(key string) int32 {
 	return rCall(key, viper.GetInt32)
 }
 
+func GetFloat64(key string) float64 {
+	return rCall(key, viper.GetFloat64)
+}
+
 func GetString(key string) string {
 	return rCall(key, viper.GetString)
 }